@@ -8,6 +8,7 @@ import (
 	"github.com/quietsato/toy-small-chat/api/internal/applications/account/usecase/repository"
 	"github.com/quietsato/toy-small-chat/api/internal/applications/account/usecase/service"
 	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
 )
 
 type CreateAccountInput struct {
@@ -39,7 +40,7 @@ func (c *CreateAccountController) CreateAccount(ctx context.Context, inp CreateA
 	}
 
 	uc := usecase.NewCreateAccountUsecase(c.repo, c.auth)
-	res, err := uc.Execute(ctx, usecase.CreateAccountInput{
+	res, err := tracing.Execute(ctx, "CreateAccountUsecase", uc.Execute, usecase.CreateAccountInput{
 		UserName: userName,
 		Password: password,
 	})
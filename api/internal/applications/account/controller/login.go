@@ -4,10 +4,11 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/quietsato/toy-small-chat/api/internal/applications/account/infrastructure/serviceimpl"
 	"github.com/quietsato/toy-small-chat/api/internal/applications/account/usecase"
 	"github.com/quietsato/toy-small-chat/api/internal/applications/account/usecase/queryprocessor"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/account/usecase/service"
 	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
 )
 
 type LoginInput struct {
@@ -21,15 +22,14 @@ type LoginOutput struct {
 
 type LoginController struct {
 	query queryprocessor.AccountQueryProcessor
+	auth  service.AuthService
 }
 
-func NewLoginController(query queryprocessor.AccountQueryProcessor) *LoginController {
-	return &LoginController{query}
+func NewLoginController(query queryprocessor.AccountQueryProcessor, auth service.AuthService) *LoginController {
+	return &LoginController{query, auth}
 }
 
 func (c *LoginController) Login(ctx context.Context, inp LoginInput) (LoginOutput, error) {
-	auth := serviceimpl.NewAuthService([]byte("secretKey"))
-
 	userName, err := domain.NewUserName(inp.UserName)
 	if err != nil {
 		return LoginOutput{}, fmt.Errorf("bad username: %w", err)
@@ -39,8 +39,8 @@ func (c *LoginController) Login(ctx context.Context, inp LoginInput) (LoginOutpu
 		return LoginOutput{}, fmt.Errorf("bad password: %w", err)
 	}
 
-	uc := usecase.NewLoginUsecase(c.query, auth)
-	res, err := uc.Execute(ctx, usecase.LoginInput{
+	uc := usecase.NewLoginUsecase(c.query, c.auth)
+	res, err := tracing.Execute(ctx, "LoginUsecase", uc.Execute, usecase.LoginInput{
 		UserName: userName,
 		Password: password,
 	})
@@ -33,16 +33,22 @@ func TestLoginController_Login(t *testing.T) {
 	t.Run("ログイン成功", func(t *testing.T) {
 		t.Parallel()
 
+		accountID := uuid.New()
 		mockQP := &mockAccountQueryProcessor{
 			getLoginCredentialFunc: func(ctx context.Context, inp queryprocessor.GetLoginCredentialInput) (queryprocessor.GetLoginCredentialOutput, error) {
 				return queryprocessor.GetLoginCredentialOutput{
-					AccountID:    uuid.New(),
+					AccountID:    accountID,
 					PasswordHash: hashedPassword.Bytes(),
 				}, nil
 			},
 		}
+		mockAuth := &mockAuthService{
+			generateTokenFunc: func(id string) string {
+				return "token-for-" + id
+			},
+		}
 
-		ctrl := controller.NewLoginController(mockQP)
+		ctrl := controller.NewLoginController(mockQP, mockAuth)
 
 		out, err := ctrl.Login(t.Context(), controller.LoginInput{
 			UserName: "testuser",
@@ -51,7 +57,7 @@ func TestLoginController_Login(t *testing.T) {
 
 		require.NoError(t, err)
 		require.Equal(t, "testuser", out.UserName)
-		require.NotEmpty(t, out.Token)
+		require.Equal(t, "token-for-"+accountID.String(), out.Token)
 	})
 
 	t.Run("無効なユーザー名でエラーを返す", func(t *testing.T) {
@@ -59,7 +65,7 @@ func TestLoginController_Login(t *testing.T) {
 
 		mockQP := &mockAccountQueryProcessor{}
 
-		ctrl := controller.NewLoginController(mockQP)
+		ctrl := controller.NewLoginController(mockQP, &mockAuthService{})
 
 		_, err := ctrl.Login(t.Context(), controller.LoginInput{
 			UserName: "", // invalid
@@ -74,7 +80,7 @@ func TestLoginController_Login(t *testing.T) {
 
 		mockQP := &mockAccountQueryProcessor{}
 
-		ctrl := controller.NewLoginController(mockQP)
+		ctrl := controller.NewLoginController(mockQP, &mockAuthService{})
 
 		_, err := ctrl.Login(t.Context(), controller.LoginInput{
 			UserName: "testuser",
@@ -93,7 +99,7 @@ func TestLoginController_Login(t *testing.T) {
 			},
 		}
 
-		ctrl := controller.NewLoginController(mockQP)
+		ctrl := controller.NewLoginController(mockQP, &mockAuthService{})
 
 		_, err := ctrl.Login(t.Context(), controller.LoginInput{
 			UserName: "nonexistent",
@@ -115,7 +121,7 @@ func TestLoginController_Login(t *testing.T) {
 			},
 		}
 
-		ctrl := controller.NewLoginController(mockQP)
+		ctrl := controller.NewLoginController(mockQP, &mockAuthService{})
 
 		_, err := ctrl.Login(t.Context(), controller.LoginInput{
 			UserName: "testuser",
@@ -133,7 +139,7 @@ func TestNewLoginController(t *testing.T) {
 		t.Parallel()
 		mockQP := &mockAccountQueryProcessor{}
 
-		ctrl := controller.NewLoginController(mockQP)
+		ctrl := controller.NewLoginController(mockQP, &mockAuthService{})
 
 		require.NotNil(t, ctrl)
 	})
@@ -2,14 +2,18 @@ package repositoryimpl
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/quietsato/toy-small-chat/api/internal/applications/account/usecase/repository"
 	"github.com/quietsato/toy-small-chat/api/internal/db"
 )
 
+const uniqueViolation = "23505"
+
 func NewAccountRepositoryOnDB(pool *pgxpool.Pool) *AccountRepositoryOnDB {
 	return &AccountRepositoryOnDB{pool}
 }
@@ -34,6 +38,10 @@ func (r *AccountRepositoryOnDB) CreateAccount(ctx context.Context, inp repositor
 		Username:     inp.UserName,
 		PasswordHash: inp.PasswordHash,
 	})
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return repository.CreateAccountOutput{}, repository.ErrUserNameAlreadyRegistered
+	}
 	if err != nil {
 		return repository.CreateAccountOutput{}, fmt.Errorf("failed to create account: %w", err)
 	}
@@ -12,11 +12,13 @@ const AccountIDKey = "accountId"
 
 type AuthServiceImpl struct {
 	tokenAuth *jwtauth.JWTAuth
+	// tokenLifetime は発行するトークンの有効期間
+	tokenLifetime time.Duration
 }
 
-func NewAuthService(secretKey []byte) *AuthServiceImpl {
+func NewAuthService(secretKey []byte, tokenLifetime time.Duration) *AuthServiceImpl {
 	tokenAuth := jwtauth.New("HS256", secretKey, nil) // TODO: 公開鍵暗号方式に変更する
-	return &AuthServiceImpl{tokenAuth}
+	return &AuthServiceImpl{tokenAuth, tokenLifetime}
 }
 
 func (a *AuthServiceImpl) GetTokenAuthForMiddleware() *jwtauth.JWTAuth {
@@ -25,7 +27,7 @@ func (a *AuthServiceImpl) GetTokenAuthForMiddleware() *jwtauth.JWTAuth {
 
 func (a *AuthServiceImpl) GenerateToken(id string) string {
 	claims := make(map[string]any, 2)
-	jwtauth.SetExpiryIn(claims, 1*time.Hour) // TODO: リフレッシュトークンに対応させたら有効期限を短くする
+	jwtauth.SetExpiryIn(claims, a.tokenLifetime) // TODO: リフレッシュトークンに対応させたら有効期限を短くする
 	claims[AccountIDKey] = id
 	_, tokenString, _ := a.tokenAuth.Encode(claims)
 	return tokenString
@@ -2,6 +2,7 @@ package serviceimpl_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/go-chi/jwtauth/v5"
 	"github.com/quietsato/toy-small-chat/api/internal/applications/account/infrastructure/serviceimpl"
@@ -15,7 +16,7 @@ func TestAuthServiceImpl_GenerateToken(t *testing.T) {
 		t.Parallel()
 
 		secretKey := []byte("test-secret-key")
-		auth := serviceimpl.NewAuthService(secretKey)
+		auth := serviceimpl.NewAuthService(secretKey, time.Hour)
 
 		accountID := "test-account-id"
 		token := auth.GenerateToken(accountID)
@@ -27,7 +28,7 @@ func TestAuthServiceImpl_GenerateToken(t *testing.T) {
 		t.Parallel()
 
 		secretKey := []byte("test-secret-key")
-		auth := serviceimpl.NewAuthService(secretKey)
+		auth := serviceimpl.NewAuthService(secretKey, time.Hour)
 
 		accountID := "test-account-id"
 		token := auth.GenerateToken(accountID)
@@ -45,7 +46,7 @@ func TestAuthServiceImpl_GenerateToken(t *testing.T) {
 		t.Parallel()
 
 		secretKey := []byte("test-secret-key")
-		auth := serviceimpl.NewAuthService(secretKey)
+		auth := serviceimpl.NewAuthService(secretKey, time.Hour)
 
 		token1 := auth.GenerateToken("account-1")
 		token2 := auth.GenerateToken("account-2")
@@ -60,7 +61,7 @@ func TestNewAuthService(t *testing.T) {
 	t.Run("正しく初期化される", func(t *testing.T) {
 		t.Parallel()
 		secretKey := []byte("test-secret-key")
-		auth := serviceimpl.NewAuthService(secretKey)
+		auth := serviceimpl.NewAuthService(secretKey, time.Hour)
 
 		require.NotNil(t, auth)
 	})
@@ -72,7 +73,7 @@ func TestAuthServiceImpl_GetTokenAuthForMiddleware(t *testing.T) {
 	t.Run("JWTAuthが取得できる", func(t *testing.T) {
 		t.Parallel()
 		secretKey := []byte("test-secret-key")
-		auth := serviceimpl.NewAuthService(secretKey)
+		auth := serviceimpl.NewAuthService(secretKey, time.Hour)
 
 		tokenAuth := auth.GetTokenAuthForMiddleware()
 
@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase/queryprocessor"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase/service"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+)
+
+type GetAttachmentInput struct {
+	RoomID       string
+	AttachmentID string
+	Thumbnail    bool
+}
+
+// GetAttachmentOutput の Body は呼び出し側で Close する
+type GetAttachmentOutput struct {
+	FileName    string
+	ContentType string
+	Body        io.ReadCloser
+}
+
+type GetAttachmentController struct {
+	query   queryprocessor.AttachmentQueryProcessor
+	storage service.BlobStorage
+}
+
+func NewGetAttachmentController(query queryprocessor.AttachmentQueryProcessor, storage service.BlobStorage) *GetAttachmentController {
+	return &GetAttachmentController{query, storage}
+}
+
+func (c *GetAttachmentController) GetAttachment(ctx context.Context, inp GetAttachmentInput) (GetAttachmentOutput, error) {
+	roomID, err := domain.ParseRoomID(inp.RoomID)
+	if err != nil {
+		return GetAttachmentOutput{}, usecase.ErrAttachmentNotFound
+	}
+	attachmentID, err := domain.ParseAttachmentID(inp.AttachmentID)
+	if err != nil {
+		return GetAttachmentOutput{}, usecase.ErrAttachmentNotFound
+	}
+
+	uc := usecase.NewGetAttachmentUsecase(c.query, c.storage)
+	res, err := tracing.Execute(ctx, "GetAttachmentUsecase", uc.Execute, usecase.GetAttachmentInput{
+		RoomID:       roomID,
+		AttachmentID: attachmentID,
+		Thumbnail:    inp.Thumbnail,
+	})
+	if err != nil {
+		return GetAttachmentOutput{}, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	return GetAttachmentOutput{
+		FileName:    res.FileName,
+		ContentType: res.ContentType,
+		Body:        res.Body,
+	}, nil
+}
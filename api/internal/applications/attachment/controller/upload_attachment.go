@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase/service"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+)
+
+type UploadAttachmentInput struct {
+	RoomID     string
+	UploadedBy string
+	FileName   string
+	Body       io.Reader
+}
+
+type UploadAttachmentOutput struct {
+	ID           string `json:"id"`
+	FileName     string `json:"fileName"`
+	ContentType  string `json:"contentType"`
+	Size         int64  `json:"size"`
+	HasThumbnail bool   `json:"hasThumbnail"`
+}
+
+type UploadAttachmentController struct {
+	repo        repository.AttachmentRepository
+	storage     service.BlobStorage
+	thumbnailer service.Thumbnailer
+	policy      domain.AttachmentPolicy
+}
+
+func NewUploadAttachmentController(
+	repo repository.AttachmentRepository,
+	storage service.BlobStorage,
+	thumbnailer service.Thumbnailer,
+	policy domain.AttachmentPolicy,
+) *UploadAttachmentController {
+	return &UploadAttachmentController{repo, storage, thumbnailer, policy}
+}
+
+func (c *UploadAttachmentController) UploadAttachment(ctx context.Context, inp UploadAttachmentInput) (UploadAttachmentOutput, error) {
+	roomID, err := domain.ParseRoomID(inp.RoomID)
+	if err != nil {
+		return UploadAttachmentOutput{}, fmt.Errorf("bad room id: %w", err)
+	}
+	uploadedBy, err := domain.ParseAccountID(inp.UploadedBy)
+	if err != nil {
+		return UploadAttachmentOutput{}, fmt.Errorf("bad account id: %w", err)
+	}
+	fileName, err := domain.NewAttachmentFileName(inp.FileName)
+	if err != nil {
+		return UploadAttachmentOutput{}, fmt.Errorf("bad file name: %w", err)
+	}
+
+	uc := usecase.NewUploadAttachmentUsecase(c.repo, c.storage, c.thumbnailer, c.policy)
+	res, err := tracing.Execute(ctx, "UploadAttachmentUsecase", uc.Execute, usecase.UploadAttachmentInput{
+		RoomID:     roomID,
+		UploadedBy: uploadedBy,
+		FileName:   fileName,
+		Body:       inp.Body,
+	})
+	if err != nil {
+		return UploadAttachmentOutput{}, fmt.Errorf("failed to upload attachment: %w", err)
+	}
+
+	return UploadAttachmentOutput{
+		ID:           res.ID,
+		FileName:     res.FileName,
+		ContentType:  res.ContentType,
+		Size:         res.Size,
+		HasThumbnail: res.HasThumbnail,
+	}, nil
+}
@@ -0,0 +1,103 @@
+package controller_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/attachment/controller"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock implementations
+type mockAttachmentRepository struct {
+	createAttachmentFunc func(ctx context.Context, inp repository.CreateAttachmentInput) (repository.CreateAttachmentOutput, error)
+}
+
+func (m *mockAttachmentRepository) CreateAttachment(ctx context.Context, inp repository.CreateAttachmentInput) (repository.CreateAttachmentOutput, error) {
+	if m.createAttachmentFunc != nil {
+		return m.createAttachmentFunc(ctx, inp)
+	}
+	return repository.CreateAttachmentOutput{}, nil
+}
+
+type discardBlobStorage struct{}
+
+func (discardBlobStorage) Put(ctx context.Context, key string, r io.Reader) error { return nil }
+func (discardBlobStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(nil)), nil
+}
+func (discardBlobStorage) Delete(ctx context.Context, key string) error { return nil }
+
+type nopThumbnailer struct{}
+
+func (nopThumbnailer) Generate(image []byte) ([]byte, error) { return image, nil }
+
+const (
+	roomID    = "8481027d-d6f6-402f-ae6d-98571e8f6496"
+	accountID = "5e305dee-d8d8-49b3-ad6c-73037e58601a"
+)
+
+func TestUploadAttachmentController_UploadAttachment(t *testing.T) {
+	t.Parallel()
+
+	policy := domain.NewAttachmentPolicy(1024, []string{"text/plain"})
+
+	t.Run("アップロード成功", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockAttachmentRepository{
+			createAttachmentFunc: func(ctx context.Context, inp repository.CreateAttachmentInput) (repository.CreateAttachmentOutput, error) {
+				require.Equal(t, roomID, inp.RoomID)
+				require.Equal(t, accountID, inp.UploadedBy)
+				require.Equal(t, "app.log", inp.FileName)
+				return repository.CreateAttachmentOutput{}, nil
+			},
+		}
+
+		ctrl := controller.NewUploadAttachmentController(mockRepo, discardBlobStorage{}, nopThumbnailer{}, policy)
+		out, err := ctrl.UploadAttachment(t.Context(), controller.UploadAttachmentInput{
+			RoomID:     roomID,
+			UploadedBy: accountID,
+			FileName:   "logs/app.log",
+			Body:       bytes.NewBufferString("INFO started\n"),
+		})
+
+		require.NoError(t, err)
+		require.NotEmpty(t, out.ID)
+		require.Equal(t, "app.log", out.FileName)
+		require.Equal(t, "text/plain", out.ContentType)
+		require.EqualValues(t, 13, out.Size)
+	})
+
+	t.Run("不正なファイル名でエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := controller.NewUploadAttachmentController(&mockAttachmentRepository{}, discardBlobStorage{}, nopThumbnailer{}, policy)
+		_, err := ctrl.UploadAttachment(t.Context(), controller.UploadAttachmentInput{
+			RoomID:     roomID,
+			UploadedBy: accountID,
+			FileName:   "",
+			Body:       bytes.NewBufferString("x"),
+		})
+
+		require.ErrorIs(t, err, domain.ErrInvalidAttachmentFileName)
+	})
+
+	t.Run("不正なルーム ID でエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := controller.NewUploadAttachmentController(&mockAttachmentRepository{}, discardBlobStorage{}, nopThumbnailer{}, policy)
+		_, err := ctrl.UploadAttachment(t.Context(), controller.UploadAttachmentInput{
+			RoomID:     "not-a-uuid",
+			UploadedBy: accountID,
+			FileName:   "app.log",
+			Body:       bytes.NewBufferString("x"),
+		})
+
+		require.Error(t, err)
+	})
+}
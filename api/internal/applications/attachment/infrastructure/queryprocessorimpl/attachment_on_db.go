@@ -0,0 +1,61 @@
+package queryprocessorimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase/queryprocessor"
+	"github.com/quietsato/toy-small-chat/api/internal/db"
+)
+
+type AttachmentQueryProcessorOnDB struct {
+	queries *db.Queries
+}
+
+func NewAttachmentQueryProcessorOnDB(pool *pgxpool.Pool) *AttachmentQueryProcessorOnDB {
+	return &AttachmentQueryProcessorOnDB{
+		queries: db.New(pool),
+	}
+}
+
+// GetAttachment implements queryprocessor.AttachmentQueryProcessor.
+func (q *AttachmentQueryProcessorOnDB) GetAttachment(ctx context.Context, inp queryprocessor.GetAttachmentInput) (queryprocessor.GetAttachmentOutput, error) {
+	id, err := uuid.Parse(inp.AttachmentID)
+	if err != nil {
+		return queryprocessor.GetAttachmentOutput{}, queryprocessor.ErrAttachmentNotFound
+	}
+
+	row, err := q.queries.GetAttachmentByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return queryprocessor.GetAttachmentOutput{}, queryprocessor.ErrAttachmentNotFound
+	}
+	if err != nil {
+		return queryprocessor.GetAttachmentOutput{}, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	roomDeleted := false
+	if _, err := q.queries.GetRoomOwner(ctx, row.RoomID); errors.Is(err, pgx.ErrNoRows) {
+		roomDeleted = true
+	} else if err != nil {
+		return queryprocessor.GetAttachmentOutput{}, fmt.Errorf("failed to get room: %w", err)
+	}
+
+	return queryprocessor.GetAttachmentOutput{
+		Attachment: queryprocessor.AttachmentDTO{
+			ID:           row.ID.String(),
+			RoomID:       row.RoomID.String(),
+			RoomDeleted:  roomDeleted,
+			FileName:     row.FileName,
+			ContentType:  row.ContentType,
+			Size:         row.Size,
+			StorageKey:   row.StorageKey,
+			ThumbnailKey: row.ThumbnailKey.String,
+		},
+	}, nil
+}
+
+var _ queryprocessor.AttachmentQueryProcessor = (*AttachmentQueryProcessorOnDB)(nil)
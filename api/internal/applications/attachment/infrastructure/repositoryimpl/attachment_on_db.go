@@ -0,0 +1,42 @@
+package repositoryimpl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/db"
+)
+
+func NewAttachmentRepositoryOnDB(pool *pgxpool.Pool) *AttachmentRepositoryOnDB {
+	return &AttachmentRepositoryOnDB{pool}
+}
+
+type AttachmentRepositoryOnDB struct {
+	pool *pgxpool.Pool
+}
+
+// CreateAttachment implements repository.AttachmentRepository.
+func (r *AttachmentRepositoryOnDB) CreateAttachment(ctx context.Context, inp repository.CreateAttachmentInput) (repository.CreateAttachmentOutput, error) {
+	queries := db.New(r.pool)
+	err := queries.CreateAttachment(ctx, db.CreateAttachmentParams{
+		ID:           uuid.MustParse(inp.ID),
+		RoomID:       uuid.MustParse(inp.RoomID),
+		UploadedBy:   uuid.MustParse(inp.UploadedBy),
+		FileName:     inp.FileName,
+		ContentType:  inp.ContentType,
+		Size:         inp.Size,
+		StorageKey:   inp.StorageKey,
+		ThumbnailKey: pgtype.Text{String: inp.ThumbnailKey, Valid: inp.ThumbnailKey != ""},
+	})
+	if err != nil {
+		return repository.CreateAttachmentOutput{}, fmt.Errorf("failed to query: %w", err)
+	}
+
+	return repository.CreateAttachmentOutput{}, nil
+}
+
+var _ repository.AttachmentRepository = new(AttachmentRepositoryOnDB)
@@ -0,0 +1,99 @@
+package serviceimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase/service"
+)
+
+var (
+	ErrInvalidBlobKey = errors.New("invalid blob key")
+)
+
+// LocalBlobStorage はローカルファイルシステムに添付ファイルを保存する
+type LocalBlobStorage struct {
+	root string
+}
+
+func NewLocalBlobStorage(root string) *LocalBlobStorage {
+	return &LocalBlobStorage{root}
+}
+
+// path は key を root 配下のパスに変換する。root の外を指す key は拒否する
+func (s *LocalBlobStorage) path(key string) (string, error) {
+	p := filepath.FromSlash(key)
+	if !filepath.IsLocal(p) {
+		return "", ErrInvalidBlobKey
+	}
+	return filepath.Join(s.root, p), nil
+}
+
+// Put implements service.BlobStorage.
+//
+// 一時ファイルに書き込んでから rename し、書き込み途中のファイルが読まれないようにする
+func (s *LocalBlobStorage) Put(ctx context.Context, key string, r io.Reader) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer func() {
+		// rename 済みの場合は存在しないので失敗しても問題ない
+		_ = os.Remove(f.Name())
+	}()
+
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close blob: %w", err)
+	}
+	if err := os.Rename(f.Name(), p); err != nil {
+		return fmt.Errorf("failed to rename blob: %w", err)
+	}
+	return nil
+}
+
+// Get implements service.BlobStorage.
+func (s *LocalBlobStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, service.ErrBlobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blob: %w", err)
+	}
+	return f, nil
+}
+
+// Delete implements service.BlobStorage.
+func (s *LocalBlobStorage) Delete(ctx context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	return nil
+}
+
+var _ service.BlobStorage = new(LocalBlobStorage)
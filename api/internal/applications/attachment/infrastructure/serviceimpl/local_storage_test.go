@@ -0,0 +1,53 @@
+package serviceimpl_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/attachment/infrastructure/serviceimpl"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase/service"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalBlobStorage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("保存したファイルを取得・削除できる", func(t *testing.T) {
+		t.Parallel()
+
+		storage := serviceimpl.NewLocalBlobStorage(t.TempDir())
+
+		err := storage.Put(t.Context(), "rooms/room-1/file", bytes.NewReader([]byte("hello")))
+		require.NoError(t, err)
+
+		r, err := storage.Get(t.Context(), "rooms/room-1/file")
+		require.NoError(t, err)
+		body, _ := io.ReadAll(r)
+		require.NoError(t, r.Close())
+		require.Equal(t, "hello", string(body))
+
+		require.NoError(t, storage.Delete(t.Context(), "rooms/room-1/file"))
+		_, err = storage.Get(t.Context(), "rooms/room-1/file")
+		require.ErrorIs(t, err, service.ErrBlobNotFound)
+	})
+
+	t.Run("存在しないファイルの削除はエラーにしない", func(t *testing.T) {
+		t.Parallel()
+
+		storage := serviceimpl.NewLocalBlobStorage(t.TempDir())
+
+		require.NoError(t, storage.Delete(t.Context(), "missing"))
+	})
+
+	t.Run("ルート外を指すキーは拒否する", func(t *testing.T) {
+		t.Parallel()
+
+		storage := serviceimpl.NewLocalBlobStorage(t.TempDir())
+
+		for _, key := range []string{"../escape", "/etc/passwd", "a/../../escape", ""} {
+			err := storage.Put(t.Context(), key, bytes.NewReader([]byte("x")))
+			require.ErrorIs(t, err, serviceimpl.ErrInvalidBlobKey, key)
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package serviceimpl
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+
+	// 対応する画像形式のデコーダを登録する
+	_ "image/gif"
+	_ "image/jpeg"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase/service"
+)
+
+const (
+	thumbnailMaxSide = 256
+	// 展開後のサイズが極端に大きい画像 (decompression bomb) はデコードしない
+	thumbnailMaxSourcePixels = 50_000_000
+)
+
+var (
+	ErrImageTooLarge = errors.New("image too large")
+)
+
+type PNGThumbnailer struct{}
+
+func NewPNGThumbnailer() *PNGThumbnailer {
+	return &PNGThumbnailer{}
+}
+
+// Generate implements service.Thumbnailer.
+//
+// 縦横比を保ったまま長辺を thumbnailMaxSide に収め、各画素は対応する領域の平均色とする
+func (t *PNGThumbnailer) Generate(data []byte) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image config: %w", err)
+	}
+	if cfg.Width*cfg.Height > thumbnailMaxSourcePixels {
+		return nil, ErrImageTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	dst := scaleDown(src, thumbnailMaxSide)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, dst); err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func scaleDown(src image.Image, maxSide int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxSide && h <= maxSide {
+		return src
+	}
+
+	dw, dh := maxSide, maxSide
+	if w > h {
+		dh = max(1, h*maxSide/w)
+	} else {
+		dw = max(1, w*maxSide/h)
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := range dh {
+		sy0, sy1 := b.Min.Y+y*h/dh, b.Min.Y+(y+1)*h/dh
+		for x := range dw {
+			sx0, sx1 := b.Min.X+x*w/dw, b.Min.X+(x+1)*w/dw
+
+			var r, g, bl, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			if a == 0 {
+				continue
+			}
+			// RGBA() は alpha 乗算済みなので、非乗算の NRGBA に戻す
+			dst.Pix[i+0] = uint8(r * 0xff / a)
+			dst.Pix[i+1] = uint8(g * 0xff / a)
+			dst.Pix[i+2] = uint8(bl * 0xff / a)
+			dst.Pix[i+3] = uint8(a / n >> 8)
+		}
+	}
+	return dst
+}
+
+var _ service.Thumbnailer = new(PNGThumbnailer)
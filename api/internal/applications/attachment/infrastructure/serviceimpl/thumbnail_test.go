@@ -0,0 +1,63 @@
+package serviceimpl_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/attachment/infrastructure/serviceimpl"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPNGThumbnailer_Generate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("長辺が 256px に縮小され縦横比が保たれる", func(t *testing.T) {
+		t.Parallel()
+
+		src := image.NewRGBA(image.Rect(0, 0, 1024, 512))
+		for y := range 512 {
+			for x := range 1024 {
+				src.Set(x, y, color.RGBA{R: 255, A: 255})
+			}
+		}
+		var buf bytes.Buffer
+		require.NoError(t, jpeg.Encode(&buf, src, nil))
+
+		out, err := serviceimpl.NewPNGThumbnailer().Generate(buf.Bytes())
+		require.NoError(t, err)
+
+		thumb, err := png.Decode(bytes.NewReader(out))
+		require.NoError(t, err)
+		require.Equal(t, 256, thumb.Bounds().Dx())
+		require.Equal(t, 128, thumb.Bounds().Dy())
+
+		r, _, _, a := thumb.At(10, 10).RGBA()
+		require.Greater(t, r, uint32(0xf000))
+		require.Equal(t, uint32(0xffff), a)
+	})
+
+	t.Run("小さい画像はそのままの大きさ", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 16, 8))))
+
+		out, err := serviceimpl.NewPNGThumbnailer().Generate(buf.Bytes())
+		require.NoError(t, err)
+
+		thumb, err := png.Decode(bytes.NewReader(out))
+		require.NoError(t, err)
+		require.Equal(t, image.Rect(0, 0, 16, 8), thumb.Bounds())
+	})
+
+	t.Run("画像でない場合はエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := serviceimpl.NewPNGThumbnailer().Generate([]byte("not an image"))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase/queryprocessor"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase/service"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+)
+
+type GetAttachmentUsecase struct {
+	query   queryprocessor.AttachmentQueryProcessor
+	storage service.BlobStorage
+}
+
+type GetAttachmentInput struct {
+	RoomID       domain.RoomID
+	AttachmentID domain.AttachmentID
+	Thumbnail    bool
+}
+
+// GetAttachmentOutput の Body は呼び出し側で Close する
+type GetAttachmentOutput struct {
+	FileName    string
+	ContentType string
+	Body        io.ReadCloser
+}
+
+var (
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrRoomNotFound       = errors.New("room not found")
+)
+
+func NewGetAttachmentUsecase(query queryprocessor.AttachmentQueryProcessor, storage service.BlobStorage) *GetAttachmentUsecase {
+	return &GetAttachmentUsecase{query, storage}
+}
+
+func (u *GetAttachmentUsecase) Execute(ctx context.Context, inp GetAttachmentInput) (GetAttachmentOutput, error) {
+	res, err := u.query.GetAttachment(ctx, queryprocessor.GetAttachmentInput{
+		AttachmentID: inp.AttachmentID.String(),
+	})
+	if errors.Is(err, queryprocessor.ErrAttachmentNotFound) {
+		return GetAttachmentOutput{}, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return GetAttachmentOutput{}, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	// 別のルームの添付ファイルは存在しないものとして扱う
+	attachment := res.Attachment
+	if attachment.RoomID != inp.RoomID.String() {
+		return GetAttachmentOutput{}, ErrAttachmentNotFound
+	}
+	// 削除したルームは復元できる期間中も存在しないものとして扱う
+	if attachment.RoomDeleted {
+		return GetAttachmentOutput{}, ErrRoomNotFound
+	}
+
+	key, contentType := attachment.StorageKey, attachment.ContentType
+	if inp.Thumbnail {
+		if attachment.ThumbnailKey == "" {
+			return GetAttachmentOutput{}, ErrAttachmentNotFound
+		}
+		key, contentType = attachment.ThumbnailKey, "image/png"
+	}
+
+	body, err := u.storage.Get(ctx, key)
+	if errors.Is(err, service.ErrBlobNotFound) {
+		return GetAttachmentOutput{}, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return GetAttachmentOutput{}, fmt.Errorf("failed to open attachment: %w", err)
+	}
+
+	return GetAttachmentOutput{
+		FileName:    attachment.FileName,
+		ContentType: contentType,
+		Body:        body,
+	}, nil
+}
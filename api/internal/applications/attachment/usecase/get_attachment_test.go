@@ -0,0 +1,132 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase/queryprocessor"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock implementations
+type mockAttachmentQueryProcessor struct {
+	getAttachmentFunc func(ctx context.Context, inp queryprocessor.GetAttachmentInput) (queryprocessor.GetAttachmentOutput, error)
+}
+
+func (m *mockAttachmentQueryProcessor) GetAttachment(ctx context.Context, inp queryprocessor.GetAttachmentInput) (queryprocessor.GetAttachmentOutput, error) {
+	if m.getAttachmentFunc != nil {
+		return m.getAttachmentFunc(ctx, inp)
+	}
+	return queryprocessor.GetAttachmentOutput{}, queryprocessor.ErrAttachmentNotFound
+}
+
+func TestGetAttachmentUsecase_Execute(t *testing.T) {
+	t.Parallel()
+
+	roomID := domain.RoomIDFromUuid(uuid.New())
+	attachmentID := domain.AttachmentIDFromUuid(uuid.New())
+
+	newStorage := func(t *testing.T) *memoryBlobStorage {
+		storage := newMemoryBlobStorage()
+		require.NoError(t, storage.Put(t.Context(), "original", bytes.NewReader([]byte("original"))))
+		require.NoError(t, storage.Put(t.Context(), "thumb", bytes.NewReader([]byte("thumb"))))
+		return storage
+	}
+	mockQP := &mockAttachmentQueryProcessor{
+		getAttachmentFunc: func(ctx context.Context, inp queryprocessor.GetAttachmentInput) (queryprocessor.GetAttachmentOutput, error) {
+			return queryprocessor.GetAttachmentOutput{
+				Attachment: queryprocessor.AttachmentDTO{
+					ID:           inp.AttachmentID,
+					RoomID:       roomID.String(),
+					FileName:     "screenshot.png",
+					ContentType:  "image/png",
+					StorageKey:   "original",
+					ThumbnailKey: "thumb",
+				},
+			}, nil
+		},
+	}
+
+	t.Run("添付ファイルを取得できる", func(t *testing.T) {
+		t.Parallel()
+
+		uc := usecase.NewGetAttachmentUsecase(mockQP, newStorage(t))
+		out, err := uc.Execute(t.Context(), usecase.GetAttachmentInput{RoomID: roomID, AttachmentID: attachmentID})
+		require.NoError(t, err)
+		defer out.Body.Close()
+
+		body, _ := io.ReadAll(out.Body)
+		require.Equal(t, "original", string(body))
+		require.Equal(t, "image/png", out.ContentType)
+		require.Equal(t, "screenshot.png", out.FileName)
+	})
+
+	t.Run("サムネイルを取得できる", func(t *testing.T) {
+		t.Parallel()
+
+		uc := usecase.NewGetAttachmentUsecase(mockQP, newStorage(t))
+		out, err := uc.Execute(t.Context(), usecase.GetAttachmentInput{RoomID: roomID, AttachmentID: attachmentID, Thumbnail: true})
+		require.NoError(t, err)
+		defer out.Body.Close()
+
+		body, _ := io.ReadAll(out.Body)
+		require.Equal(t, "thumb", string(body))
+	})
+
+	t.Run("別のルームからは取得できない", func(t *testing.T) {
+		t.Parallel()
+
+		uc := usecase.NewGetAttachmentUsecase(mockQP, newStorage(t))
+		_, err := uc.Execute(t.Context(), usecase.GetAttachmentInput{
+			RoomID:       domain.RoomIDFromUuid(uuid.New()),
+			AttachmentID: attachmentID,
+		})
+
+		require.ErrorIs(t, err, usecase.ErrAttachmentNotFound)
+	})
+
+	t.Run("削除したルームからは取得できない", func(t *testing.T) {
+		t.Parallel()
+
+		deletedQP := &mockAttachmentQueryProcessor{
+			getAttachmentFunc: func(ctx context.Context, inp queryprocessor.GetAttachmentInput) (queryprocessor.GetAttachmentOutput, error) {
+				return queryprocessor.GetAttachmentOutput{
+					Attachment: queryprocessor.AttachmentDTO{
+						ID:          inp.AttachmentID,
+						RoomID:      roomID.String(),
+						RoomDeleted: true,
+						StorageKey:  "original",
+					},
+				}, nil
+			},
+		}
+
+		uc := usecase.NewGetAttachmentUsecase(deletedQP, newStorage(t))
+		_, err := uc.Execute(t.Context(), usecase.GetAttachmentInput{RoomID: roomID, AttachmentID: attachmentID})
+
+		require.ErrorIs(t, err, usecase.ErrRoomNotFound)
+	})
+
+	t.Run("存在しない添付ファイルはエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		uc := usecase.NewGetAttachmentUsecase(&mockAttachmentQueryProcessor{}, newStorage(t))
+		_, err := uc.Execute(t.Context(), usecase.GetAttachmentInput{RoomID: roomID, AttachmentID: attachmentID})
+
+		require.ErrorIs(t, err, usecase.ErrAttachmentNotFound)
+	})
+
+	t.Run("実体が失われている場合はエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		uc := usecase.NewGetAttachmentUsecase(mockQP, newMemoryBlobStorage())
+		_, err := uc.Execute(t.Context(), usecase.GetAttachmentInput{RoomID: roomID, AttachmentID: attachmentID})
+
+		require.ErrorIs(t, err, usecase.ErrAttachmentNotFound)
+	})
+}
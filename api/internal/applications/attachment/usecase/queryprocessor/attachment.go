@@ -0,0 +1,33 @@
+package queryprocessor
+
+import (
+	"context"
+	"errors"
+)
+
+type GetAttachmentInput struct {
+	AttachmentID string
+}
+type GetAttachmentOutput struct {
+	Attachment AttachmentDTO
+}
+type AttachmentDTO struct {
+	ID           string
+	RoomID       string
+	FileName     string
+	ContentType  string
+	Size         int64
+	StorageKey   string
+	ThumbnailKey string
+
+	// RoomDeleted はルームが削除され、復元を待っている場合に true
+	RoomDeleted bool
+}
+
+var (
+	ErrAttachmentNotFound = errors.New("attachment not found")
+)
+
+type AttachmentQueryProcessor interface {
+	GetAttachment(ctx context.Context, inp GetAttachmentInput) (GetAttachmentOutput, error)
+}
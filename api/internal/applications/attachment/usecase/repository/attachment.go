@@ -0,0 +1,19 @@
+package repository
+
+import "context"
+
+type CreateAttachmentInput struct {
+	ID           string
+	RoomID       string
+	UploadedBy   string
+	FileName     string
+	ContentType  string
+	Size         int64
+	StorageKey   string
+	ThumbnailKey string
+}
+type CreateAttachmentOutput struct{}
+
+type AttachmentRepository interface {
+	CreateAttachment(ctx context.Context, inp CreateAttachmentInput) (CreateAttachmentOutput, error)
+}
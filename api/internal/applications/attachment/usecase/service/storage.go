@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var (
+	ErrBlobNotFound = errors.New("blob not found")
+)
+
+// BlobStorage は添付ファイルの実体を保存する
+//
+// key は "/" 区切りの相対パスで、実装はそれをローカルディスクやオブジェクトストレージのキーに対応付ける
+type BlobStorage interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,8 @@
+package service
+
+// Thumbnailer は画像からサムネイルを生成する
+//
+// サムネイルは元画像の形式によらず PNG で返す
+type Thumbnailer interface {
+	Generate(image []byte) ([]byte, error)
+}
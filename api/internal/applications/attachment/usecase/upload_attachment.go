@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase/service"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+)
+
+type UploadAttachmentUsecase struct {
+	repo        repository.AttachmentRepository
+	storage     service.BlobStorage
+	thumbnailer service.Thumbnailer
+	policy      domain.AttachmentPolicy
+}
+
+type UploadAttachmentInput struct {
+	RoomID     domain.RoomID
+	UploadedBy domain.AccountID
+	FileName   domain.AttachmentFileName
+	Body       io.Reader
+}
+
+type UploadAttachmentOutput struct {
+	ID           string
+	FileName     string
+	ContentType  string
+	Size         int64
+	HasThumbnail bool
+}
+
+func NewUploadAttachmentUsecase(
+	repo repository.AttachmentRepository,
+	storage service.BlobStorage,
+	thumbnailer service.Thumbnailer,
+	policy domain.AttachmentPolicy,
+) *UploadAttachmentUsecase {
+	return &UploadAttachmentUsecase{repo, storage, thumbnailer, policy}
+}
+
+func (u *UploadAttachmentUsecase) Execute(ctx context.Context, inp UploadAttachmentInput) (UploadAttachmentOutput, error) {
+	// 上限 + 1 バイトまで読み、上限を超えているかを判定する
+	data, err := io.ReadAll(io.LimitReader(inp.Body, u.policy.MaxSize()+1))
+	if err != nil {
+		return UploadAttachmentOutput{}, fmt.Errorf("failed to read attachment: %w", err)
+	}
+
+	// クライアント申告の Content-Type は信用せず、内容から判定する
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(data))
+	if err != nil {
+		return UploadAttachmentOutput{}, fmt.Errorf("failed to detect content type: %w", err)
+	}
+	if err := u.policy.Validate(int64(len(data)), contentType); err != nil {
+		return UploadAttachmentOutput{}, err
+	}
+
+	id := domain.NewAttachmentID()
+	storageKey := fmt.Sprintf("rooms/%s/%s", inp.RoomID, id)
+	if err := u.storage.Put(ctx, storageKey, bytes.NewReader(data)); err != nil {
+		return UploadAttachmentOutput{}, fmt.Errorf("failed to store attachment: %w", err)
+	}
+	storedKeys := []string{storageKey}
+
+	// サムネイル生成の失敗はアップロード自体の失敗とはしない
+	thumbnailKey := ""
+	if domain.IsThumbnailable(contentType) {
+		thumbnail, err := u.thumbnailer.Generate(data)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to generate thumbnail", slog.Any("err", err))
+		} else {
+			key := storageKey + ".thumb"
+			if err := u.storage.Put(ctx, key, bytes.NewReader(thumbnail)); err != nil {
+				slog.WarnContext(ctx, "failed to store thumbnail", slog.Any("err", err))
+			} else {
+				thumbnailKey = key
+				storedKeys = append(storedKeys, key)
+			}
+		}
+	}
+
+	if _, err := u.repo.CreateAttachment(ctx, repository.CreateAttachmentInput{
+		ID:           id.String(),
+		RoomID:       inp.RoomID.String(),
+		UploadedBy:   inp.UploadedBy.String(),
+		FileName:     inp.FileName.String(),
+		ContentType:  contentType,
+		Size:         int64(len(data)),
+		StorageKey:   storageKey,
+		ThumbnailKey: thumbnailKey,
+	}); err != nil {
+		for _, key := range storedKeys {
+			if err := u.storage.Delete(ctx, key); err != nil {
+				slog.WarnContext(ctx, "failed to delete orphan blob", slog.String("key", key), slog.Any("err", err))
+			}
+		}
+		return UploadAttachmentOutput{}, fmt.Errorf("failed to create attachment: %w", err)
+	}
+
+	return UploadAttachmentOutput{
+		ID:           id.String(),
+		FileName:     inp.FileName.String(),
+		ContentType:  contentType,
+		Size:         int64(len(data)),
+		HasThumbnail: thumbnailKey != "",
+	}, nil
+}
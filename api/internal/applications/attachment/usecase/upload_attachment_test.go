@@ -0,0 +1,200 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase/service"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock implementations
+type mockAttachmentRepository struct {
+	createAttachmentFunc func(ctx context.Context, inp repository.CreateAttachmentInput) (repository.CreateAttachmentOutput, error)
+}
+
+func (m *mockAttachmentRepository) CreateAttachment(ctx context.Context, inp repository.CreateAttachmentInput) (repository.CreateAttachmentOutput, error) {
+	if m.createAttachmentFunc != nil {
+		return m.createAttachmentFunc(ctx, inp)
+	}
+	return repository.CreateAttachmentOutput{}, nil
+}
+
+type memoryBlobStorage struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+}
+
+func newMemoryBlobStorage() *memoryBlobStorage {
+	return &memoryBlobStorage{blobs: map[string][]byte{}}
+}
+
+func (m *memoryBlobStorage) Put(ctx context.Context, key string, r io.Reader) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.blobs[key] = b
+	return nil
+}
+
+func (m *memoryBlobStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b, ok := m.blobs[key]
+	if !ok {
+		return nil, service.ErrBlobNotFound
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (m *memoryBlobStorage) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.blobs, key)
+	return nil
+}
+
+type mockThumbnailer struct {
+	generateFunc func(image []byte) ([]byte, error)
+}
+
+func (m *mockThumbnailer) Generate(image []byte) ([]byte, error) {
+	if m.generateFunc != nil {
+		return m.generateFunc(image)
+	}
+	return []byte("thumbnail"), nil
+}
+
+func pngBytes(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4))))
+	return buf.Bytes()
+}
+
+func uploadInput(t *testing.T, body []byte) usecase.UploadAttachmentInput {
+	t.Helper()
+	fileName, err := domain.NewAttachmentFileName("file.bin")
+	require.NoError(t, err)
+	return usecase.UploadAttachmentInput{
+		RoomID:     domain.RoomIDFromUuid(uuid.New()),
+		UploadedBy: domain.AccountIDFromUuid(uuid.New()),
+		FileName:   fileName,
+		Body:       bytes.NewReader(body),
+	}
+}
+
+var policy = domain.NewAttachmentPolicy(1024, []string{"image/png", "text/plain"})
+
+func TestUploadAttachmentUsecase_Execute(t *testing.T) {
+	t.Parallel()
+
+	t.Run("画像をアップロードするとサムネイルも保存される", func(t *testing.T) {
+		t.Parallel()
+
+		storage := newMemoryBlobStorage()
+		mockRepo := &mockAttachmentRepository{
+			createAttachmentFunc: func(ctx context.Context, inp repository.CreateAttachmentInput) (repository.CreateAttachmentOutput, error) {
+				require.Equal(t, "image/png", inp.ContentType)
+				require.Equal(t, "file.bin", inp.FileName)
+				require.NotEmpty(t, inp.ThumbnailKey)
+				return repository.CreateAttachmentOutput{}, nil
+			},
+		}
+
+		uc := usecase.NewUploadAttachmentUsecase(mockRepo, storage, &mockThumbnailer{}, policy)
+		out, err := uc.Execute(t.Context(), uploadInput(t, pngBytes(t)))
+
+		require.NoError(t, err)
+		require.Equal(t, "image/png", out.ContentType)
+		require.True(t, out.HasThumbnail)
+		require.Len(t, storage.blobs, 2)
+	})
+
+	t.Run("テキストはサムネイルを生成しない", func(t *testing.T) {
+		t.Parallel()
+
+		storage := newMemoryBlobStorage()
+		thumbnailer := &mockThumbnailer{
+			generateFunc: func(image []byte) ([]byte, error) {
+				t.Fatal("should not be called")
+				return nil, nil
+			},
+		}
+
+		uc := usecase.NewUploadAttachmentUsecase(&mockAttachmentRepository{}, storage, thumbnailer, policy)
+		out, err := uc.Execute(t.Context(), uploadInput(t, []byte("panic: something went wrong\n")))
+
+		require.NoError(t, err)
+		require.Equal(t, "text/plain", out.ContentType)
+		require.False(t, out.HasThumbnail)
+		require.Len(t, storage.blobs, 1)
+	})
+
+	t.Run("サムネイル生成に失敗してもアップロードは成功する", func(t *testing.T) {
+		t.Parallel()
+
+		thumbnailer := &mockThumbnailer{
+			generateFunc: func(image []byte) ([]byte, error) {
+				return nil, errors.New("broken image")
+			},
+		}
+
+		uc := usecase.NewUploadAttachmentUsecase(&mockAttachmentRepository{}, newMemoryBlobStorage(), thumbnailer, policy)
+		out, err := uc.Execute(t.Context(), uploadInput(t, pngBytes(t)))
+
+		require.NoError(t, err)
+		require.False(t, out.HasThumbnail)
+	})
+
+	t.Run("上限を超えるサイズはエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		storage := newMemoryBlobStorage()
+		uc := usecase.NewUploadAttachmentUsecase(&mockAttachmentRepository{}, storage, &mockThumbnailer{}, policy)
+		_, err := uc.Execute(t.Context(), uploadInput(t, []byte(strings.Repeat("a", 1025))))
+
+		require.ErrorIs(t, err, domain.ErrAttachmentTooLarge)
+		require.Empty(t, storage.blobs)
+	})
+
+	t.Run("許可されていない形式はエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		uc := usecase.NewUploadAttachmentUsecase(&mockAttachmentRepository{}, newMemoryBlobStorage(), &mockThumbnailer{}, policy)
+		_, err := uc.Execute(t.Context(), uploadInput(t, []byte("PK\x03\x04zipfile")))
+
+		require.ErrorIs(t, err, domain.ErrAttachmentTypeNotAllowed)
+	})
+
+	t.Run("リポジトリエラー時は保存済みの実体を削除する", func(t *testing.T) {
+		t.Parallel()
+
+		storage := newMemoryBlobStorage()
+		mockRepo := &mockAttachmentRepository{
+			createAttachmentFunc: func(ctx context.Context, inp repository.CreateAttachmentInput) (repository.CreateAttachmentOutput, error) {
+				return repository.CreateAttachmentOutput{}, errors.New("db error")
+			},
+		}
+
+		uc := usecase.NewUploadAttachmentUsecase(mockRepo, storage, &mockThumbnailer{}, policy)
+		_, err := uc.Execute(t.Context(), uploadInput(t, pngBytes(t)))
+
+		require.Error(t, err)
+		require.Empty(t, storage.blobs)
+	})
+}
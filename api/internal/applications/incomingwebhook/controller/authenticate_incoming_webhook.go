@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+)
+
+type AuthenticateIncomingWebhookInput struct {
+	WebhookID string
+	Token     string
+}
+
+type AuthenticateIncomingWebhookOutput struct {
+	RoomID       string
+	BotAccountID string
+}
+
+type AuthenticateIncomingWebhookController struct {
+	repo repository.IncomingWebhookRepository
+}
+
+func NewAuthenticateIncomingWebhookController(repo repository.IncomingWebhookRepository) *AuthenticateIncomingWebhookController {
+	return &AuthenticateIncomingWebhookController{repo}
+}
+
+func (c *AuthenticateIncomingWebhookController) AuthenticateIncomingWebhook(ctx context.Context, inp AuthenticateIncomingWebhookInput) (AuthenticateIncomingWebhookOutput, error) {
+	token, err := domain.ParseIncomingWebhookToken(inp.Token)
+	if err != nil {
+		return AuthenticateIncomingWebhookOutput{}, fmt.Errorf("bad token: %w", err)
+	}
+
+	uc := usecase.NewAuthenticateIncomingWebhookUsecase(c.repo)
+	res, err := tracing.Execute(ctx, "AuthenticateIncomingWebhookUsecase", uc.Execute, usecase.AuthenticateIncomingWebhookInput{
+		WebhookID: inp.WebhookID,
+		Token:     token,
+	})
+	if err != nil {
+		return AuthenticateIncomingWebhookOutput{}, err
+	}
+
+	return AuthenticateIncomingWebhookOutput{
+		RoomID:       res.RoomID,
+		BotAccountID: res.BotAccountID,
+	}, nil
+}
@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+)
+
+// CreateIncomingWebhookInput の Name は Bot の名前で、ユーザー名と同じ規則で検証する
+//
+// RateLimit が 0 の場合は既定の上限を使う
+type CreateIncomingWebhookInput struct {
+	Name      string `json:"name"`
+	RateLimit int    `json:"rateLimit"`
+	RoomID    string `json:"-"`
+	AccountID string `json:"-"`
+}
+
+// CreateIncomingWebhookOutput の Token と URL は登録時にだけ返す
+type CreateIncomingWebhookOutput struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	RateLimit int    `json:"rateLimit"`
+	Token     string `json:"token"`
+	URL       string `json:"url"`
+}
+
+type CreateIncomingWebhookController struct {
+	repo repository.IncomingWebhookRepository
+}
+
+func NewCreateIncomingWebhookController(repo repository.IncomingWebhookRepository) *CreateIncomingWebhookController {
+	return &CreateIncomingWebhookController{repo}
+}
+
+func (c *CreateIncomingWebhookController) CreateIncomingWebhook(ctx context.Context, inp CreateIncomingWebhookInput) (CreateIncomingWebhookOutput, error) {
+	name, err := domain.NewUserName(inp.Name)
+	if err != nil {
+		return CreateIncomingWebhookOutput{}, fmt.Errorf("bad name: %w", err)
+	}
+
+	rateLimit, err := domain.NewIncomingWebhookRateLimit(inp.RateLimit)
+	if err != nil {
+		return CreateIncomingWebhookOutput{}, fmt.Errorf("bad rate limit: %w", err)
+	}
+
+	uc := usecase.NewCreateIncomingWebhookUsecase(c.repo)
+	res, err := tracing.Execute(ctx, "CreateIncomingWebhookUsecase", uc.Execute, usecase.CreateIncomingWebhookInput{
+		RoomID:    inp.RoomID,
+		AccountID: inp.AccountID,
+		BotName:   name,
+		RateLimit: rateLimit,
+	})
+	if err != nil {
+		return CreateIncomingWebhookOutput{}, err
+	}
+
+	return CreateIncomingWebhookOutput{
+		ID:        res.ID,
+		Name:      name.String(),
+		RateLimit: rateLimit.PerMinute(),
+		Token:     res.Token.String(),
+		URL:       incomingWebhookURL(res.ID, res.Token),
+	}, nil
+}
+
+// incomingWebhookURL は投稿先のパスを返す。トークンを含むため秘密として扱う
+func incomingWebhookURL(id string, token domain.IncomingWebhookToken) string {
+	return fmt.Sprintf("/v1/hooks/%s/%s", id, token.String())
+}
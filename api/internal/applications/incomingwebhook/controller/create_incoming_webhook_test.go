@@ -0,0 +1,108 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/controller"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock implementations
+type mockIncomingWebhookRepository struct {
+	createIncomingWebhookFunc func(ctx context.Context, inp repository.CreateIncomingWebhookInput) (repository.CreateIncomingWebhookOutput, error)
+}
+
+func (m *mockIncomingWebhookRepository) CreateIncomingWebhook(ctx context.Context, inp repository.CreateIncomingWebhookInput) (repository.CreateIncomingWebhookOutput, error) {
+	if m.createIncomingWebhookFunc != nil {
+		return m.createIncomingWebhookFunc(ctx, inp)
+	}
+	return repository.CreateIncomingWebhookOutput{}, nil
+}
+
+func (m *mockIncomingWebhookRepository) RotateToken(ctx context.Context, inp repository.RotateTokenInput) error {
+	return nil
+}
+
+func (m *mockIncomingWebhookRepository) RevokeIncomingWebhook(ctx context.Context, inp repository.RevokeIncomingWebhookInput) error {
+	return nil
+}
+
+func (m *mockIncomingWebhookRepository) GetCredential(ctx context.Context, inp repository.GetCredentialInput) (repository.GetCredentialOutput, error) {
+	return repository.GetCredentialOutput{}, nil
+}
+
+func (m *mockIncomingWebhookRepository) ConsumeRateLimit(ctx context.Context, inp repository.ConsumeRateLimitInput) error {
+	return nil
+}
+
+func TestCreateIncomingWebhookController_CreateIncomingWebhook(t *testing.T) {
+	t.Parallel()
+
+	roomID := uuid.NewString()
+	accountID := uuid.NewString()
+
+	t.Run("トークンを含む投稿先の URL を返す", func(t *testing.T) {
+		t.Parallel()
+
+		webhookID := uuid.New()
+		mockRepo := &mockIncomingWebhookRepository{
+			createIncomingWebhookFunc: func(ctx context.Context, inp repository.CreateIncomingWebhookInput) (repository.CreateIncomingWebhookOutput, error) {
+				require.Equal(t, "ci", inp.BotName)
+				require.Equal(t, 30, inp.RateLimit)
+				return repository.CreateIncomingWebhookOutput{ID: webhookID}, nil
+			},
+		}
+
+		c := controller.NewCreateIncomingWebhookController(mockRepo)
+		out, err := c.CreateIncomingWebhook(t.Context(), controller.CreateIncomingWebhookInput{
+			Name:      "ci",
+			RateLimit: 30,
+			RoomID:    roomID,
+			AccountID: accountID,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, webhookID.String(), out.ID)
+		require.Equal(t, "ci", out.Name)
+		require.Equal(t, 30, out.RateLimit)
+		require.NotEmpty(t, out.Token)
+		require.Equal(t, "/v1/hooks/"+webhookID.String()+"/"+out.Token, out.URL)
+	})
+
+	t.Run("不正な入力でエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name     string
+			input    controller.CreateIncomingWebhookInput
+			expected error
+		}{
+			{"empty name", controller.CreateIncomingWebhookInput{Name: ""}, domain.ErrInvalidUserName},
+			{"name with space", controller.CreateIncomingWebhookInput{Name: "ci bot"}, domain.ErrInvalidUserName},
+			{"too large rate limit", controller.CreateIncomingWebhookInput{Name: "ci", RateLimit: 1000}, domain.ErrInvalidIncomingWebhookRateLimit},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				mockRepo := &mockIncomingWebhookRepository{
+					createIncomingWebhookFunc: func(ctx context.Context, inp repository.CreateIncomingWebhookInput) (repository.CreateIncomingWebhookOutput, error) {
+						t.Fatal("should not be called")
+						return repository.CreateIncomingWebhookOutput{}, nil
+					},
+				}
+
+				inp := tt.input
+				inp.RoomID = roomID
+				inp.AccountID = accountID
+				_, err := controller.NewCreateIncomingWebhookController(mockRepo).CreateIncomingWebhook(t.Context(), inp)
+				require.ErrorIs(t, err, tt.expected)
+			})
+		}
+	})
+}
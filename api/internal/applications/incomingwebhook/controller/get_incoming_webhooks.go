@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/usecase/queryprocessor"
+)
+
+type GetIncomingWebhooksInput struct {
+	RoomID    string
+	AccountID string
+}
+
+type GetIncomingWebhooksOutput struct {
+	Webhooks []IncomingWebhook `json:"webhooks"`
+}
+
+// IncomingWebhook の RevokedAt は失効済みの場合のみ設定される
+type IncomingWebhook struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	RateLimit      int    `json:"rateLimit"`
+	CreatedBy      string `json:"createdBy"`
+	CreatedAt      string `json:"createdAt"`
+	TokenRotatedAt string `json:"tokenRotatedAt"`
+	RevokedAt      string `json:"revokedAt,omitempty"`
+}
+
+type GetIncomingWebhooksController struct {
+	query queryprocessor.IncomingWebhookQueryProcessor
+}
+
+func NewGetIncomingWebhooksController(query queryprocessor.IncomingWebhookQueryProcessor) *GetIncomingWebhooksController {
+	return &GetIncomingWebhooksController{query}
+}
+
+func (c *GetIncomingWebhooksController) GetIncomingWebhooks(ctx context.Context, inp GetIncomingWebhooksInput) (GetIncomingWebhooksOutput, error) {
+	roomID, err := uuid.Parse(inp.RoomID)
+	if err != nil {
+		return GetIncomingWebhooksOutput{}, queryprocessor.ErrRoomNotFound
+	}
+	accountID, err := uuid.Parse(inp.AccountID)
+	if err != nil {
+		return GetIncomingWebhooksOutput{}, fmt.Errorf("bad account id: %w", err)
+	}
+
+	res, err := c.query.GetIncomingWebhooks(ctx, queryprocessor.GetIncomingWebhooksInput{
+		RoomID:    roomID,
+		AccountID: accountID,
+	})
+	if err != nil {
+		return GetIncomingWebhooksOutput{}, fmt.Errorf("failed to get incoming webhooks: %w", err)
+	}
+
+	webhooks := make([]IncomingWebhook, len(res.Webhooks))
+	for i, w := range res.Webhooks {
+		webhooks[i] = IncomingWebhook{
+			ID:             w.ID,
+			Name:           w.Name,
+			RateLimit:      w.RateLimit,
+			CreatedBy:      w.CreatedBy,
+			CreatedAt:      w.CreatedAt,
+			TokenRotatedAt: w.TokenRotatedAt,
+			RevokedAt:      w.RevokedAt,
+		}
+	}
+	return GetIncomingWebhooksOutput{Webhooks: webhooks}, nil
+}
@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+)
+
+type RevokeIncomingWebhookInput struct {
+	RoomID    string
+	WebhookID string
+	AccountID string
+}
+
+type RevokeIncomingWebhookController struct {
+	repo repository.IncomingWebhookRepository
+}
+
+func NewRevokeIncomingWebhookController(repo repository.IncomingWebhookRepository) *RevokeIncomingWebhookController {
+	return &RevokeIncomingWebhookController{repo}
+}
+
+func (c *RevokeIncomingWebhookController) RevokeIncomingWebhook(ctx context.Context, inp RevokeIncomingWebhookInput) error {
+	uc := usecase.NewRevokeIncomingWebhookUsecase(c.repo)
+	_, err := tracing.Execute(ctx, "RevokeIncomingWebhookUsecase", uc.Execute, usecase.RevokeIncomingWebhookInput{
+		RoomID:    inp.RoomID,
+		WebhookID: inp.WebhookID,
+		AccountID: inp.AccountID,
+	})
+	return err
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+)
+
+type RotateIncomingWebhookTokenInput struct {
+	RoomID    string
+	WebhookID string
+	AccountID string
+}
+
+type RotateIncomingWebhookTokenOutput struct {
+	ID    string `json:"id"`
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+type RotateIncomingWebhookTokenController struct {
+	repo repository.IncomingWebhookRepository
+}
+
+func NewRotateIncomingWebhookTokenController(repo repository.IncomingWebhookRepository) *RotateIncomingWebhookTokenController {
+	return &RotateIncomingWebhookTokenController{repo}
+}
+
+func (c *RotateIncomingWebhookTokenController) RotateIncomingWebhookToken(ctx context.Context, inp RotateIncomingWebhookTokenInput) (RotateIncomingWebhookTokenOutput, error) {
+	uc := usecase.NewRotateIncomingWebhookTokenUsecase(c.repo)
+	res, err := tracing.Execute(ctx, "RotateIncomingWebhookTokenUsecase", uc.Execute, usecase.RotateIncomingWebhookTokenInput{
+		RoomID:    inp.RoomID,
+		WebhookID: inp.WebhookID,
+		AccountID: inp.AccountID,
+	})
+	if err != nil {
+		return RotateIncomingWebhookTokenOutput{}, err
+	}
+
+	return RotateIncomingWebhookTokenOutput{
+		ID:    inp.WebhookID,
+		Token: res.Token.String(),
+		URL:   incomingWebhookURL(inp.WebhookID, res.Token),
+	}, nil
+}
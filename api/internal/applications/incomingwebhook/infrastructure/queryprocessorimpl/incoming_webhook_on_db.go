@@ -0,0 +1,68 @@
+package queryprocessorimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/usecase/queryprocessor"
+	"github.com/quietsato/toy-small-chat/api/internal/db"
+)
+
+type IncomingWebhookQueryProcessorOnDB struct {
+	queries *db.Queries
+}
+
+func NewIncomingWebhookQueryProcessorOnDB(pool *pgxpool.Pool) *IncomingWebhookQueryProcessorOnDB {
+	return &IncomingWebhookQueryProcessorOnDB{
+		queries: db.New(pool),
+	}
+}
+
+// GetIncomingWebhooks implements queryprocessor.IncomingWebhookQueryProcessor.
+//
+// 受信 Webhook の一覧はルームの作成者だけが参照できる
+func (q *IncomingWebhookQueryProcessorOnDB) GetIncomingWebhooks(ctx context.Context, inp queryprocessor.GetIncomingWebhooksInput) (queryprocessor.GetIncomingWebhooksOutput, error) {
+	owner, err := q.queries.GetRoomOwner(ctx, inp.RoomID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return queryprocessor.GetIncomingWebhooksOutput{}, queryprocessor.ErrRoomNotFound
+	}
+	if err != nil {
+		return queryprocessor.GetIncomingWebhooksOutput{}, fmt.Errorf("failed to get room owner: %w", err)
+	}
+	if owner != inp.AccountID {
+		return queryprocessor.GetIncomingWebhooksOutput{}, queryprocessor.ErrNotRoomOwner
+	}
+
+	rows, err := q.queries.GetIncomingWebhooksByRoomID(ctx, inp.RoomID)
+	if err != nil {
+		return queryprocessor.GetIncomingWebhooksOutput{}, fmt.Errorf("failed to get incoming webhooks: %w", err)
+	}
+
+	webhooks := make([]queryprocessor.IncomingWebhookDTO, len(rows))
+	for i, row := range rows {
+		webhooks[i] = queryprocessor.IncomingWebhookDTO{
+			ID:             row.ID.String(),
+			Name:           row.Name,
+			RateLimit:      int(row.RateLimit),
+			CreatedBy:      row.CreatedBy.String(),
+			CreatedAt:      formatTimestamp(row.CreatedAt),
+			TokenRotatedAt: formatTimestamp(row.TokenRotatedAt),
+			RevokedAt:      formatTimestamp(row.RevokedAt),
+		}
+	}
+	return queryprocessor.GetIncomingWebhooksOutput{Webhooks: webhooks}, nil
+}
+
+func formatTimestamp(t pgtype.Timestamp) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.Format(time.RFC3339)
+}
+
+var _ queryprocessor.IncomingWebhookQueryProcessor = new(IncomingWebhookQueryProcessorOnDB)
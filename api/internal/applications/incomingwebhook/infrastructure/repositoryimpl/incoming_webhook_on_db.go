@@ -0,0 +1,174 @@
+package repositoryimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/db"
+)
+
+// uniqueViolation は一意制約違反を表す PostgreSQL のエラーコード
+const uniqueViolation = "23505"
+
+func NewIncomingWebhookRepositoryOnDB(pool *pgxpool.Pool) *IncomingWebhookRepositoryOnDB {
+	return &IncomingWebhookRepositoryOnDB{pool}
+}
+
+type IncomingWebhookRepositoryOnDB struct {
+	pool *pgxpool.Pool
+}
+
+// CreateIncomingWebhook implements repository.IncomingWebhookRepository.
+//
+// Bot アカウントはユーザーと同じ名前空間に作成するため、既存のユーザー名とは重複できない
+func (r *IncomingWebhookRepositoryOnDB) CreateIncomingWebhook(ctx context.Context, inp repository.CreateIncomingWebhookInput) (repository.CreateIncomingWebhookOutput, error) {
+	var out repository.CreateIncomingWebhookOutput
+	err := r.withOwnerLock(ctx, inp.RoomID, inp.AccountID, func(queries *db.Queries) error {
+		// ルームの行をロックしているため、件数の確認と追加の間に他の登録は割り込まない
+		count, err := queries.CountIncomingWebhooksByRoomID(ctx, inp.RoomID)
+		if err != nil {
+			return fmt.Errorf("failed to count incoming webhooks: %w", err)
+		}
+		if count >= int64(inp.MaxWebhooks) {
+			return repository.ErrTooManyIncomingWebhooks
+		}
+
+		botID, err := queries.CreateBotAccount(ctx, inp.BotName)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return repository.ErrBotNameTaken
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create bot account: %w", err)
+		}
+
+		id, err := queries.CreateIncomingWebhook(ctx, db.CreateIncomingWebhookParams{
+			RoomID:       inp.RoomID,
+			BotAccountID: botID,
+			TokenHash:    inp.TokenHash,
+			RateLimit:    int32(inp.RateLimit),
+			CreatedBy:    inp.AccountID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create incoming webhook: %w", err)
+		}
+		out.ID = id
+		return nil
+	})
+	return out, err
+}
+
+// RotateToken implements repository.IncomingWebhookRepository.
+//
+// 失効済みの受信 Webhook は存在しないものと同じく ErrIncomingWebhookNotFound を返す
+func (r *IncomingWebhookRepositoryOnDB) RotateToken(ctx context.Context, inp repository.RotateTokenInput) error {
+	return r.withOwnerLock(ctx, inp.RoomID, inp.AccountID, func(queries *db.Queries) error {
+		updated, err := queries.RotateIncomingWebhookToken(ctx, db.RotateIncomingWebhookTokenParams{
+			TokenHash: inp.TokenHash,
+			ID:        inp.WebhookID,
+			RoomID:    inp.RoomID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to rotate token: %w", err)
+		}
+		if updated == 0 {
+			return repository.ErrIncomingWebhookNotFound
+		}
+		return nil
+	})
+}
+
+// RevokeIncomingWebhook implements repository.IncomingWebhookRepository.
+//
+// 失効済みの受信 Webhook を再び失効させても失効した時刻は変わらない
+func (r *IncomingWebhookRepositoryOnDB) RevokeIncomingWebhook(ctx context.Context, inp repository.RevokeIncomingWebhookInput) error {
+	return r.withOwnerLock(ctx, inp.RoomID, inp.AccountID, func(queries *db.Queries) error {
+		updated, err := queries.RevokeIncomingWebhook(ctx, db.RevokeIncomingWebhookParams{
+			ID:     inp.WebhookID,
+			RoomID: inp.RoomID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to revoke incoming webhook: %w", err)
+		}
+		if updated == 0 {
+			return repository.ErrIncomingWebhookNotFound
+		}
+		return nil
+	})
+}
+
+// GetCredential implements repository.IncomingWebhookRepository.
+func (r *IncomingWebhookRepositoryOnDB) GetCredential(ctx context.Context, inp repository.GetCredentialInput) (repository.GetCredentialOutput, error) {
+	queries := db.New(r.pool)
+	row, err := queries.GetIncomingWebhookCredential(ctx, inp.WebhookID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.GetCredentialOutput{}, repository.ErrIncomingWebhookNotFound
+	}
+	if err != nil {
+		return repository.GetCredentialOutput{}, fmt.Errorf("failed to get credential: %w", err)
+	}
+	return repository.GetCredentialOutput{
+		RoomID:       row.RoomID,
+		BotAccountID: row.BotAccountID,
+		TokenHash:    row.TokenHash,
+	}, nil
+}
+
+// ConsumeRateLimit implements repository.IncomingWebhookRepository.
+//
+// 1 分ごとの固定の枠で数える。行の更新で数えるため、複数のインスタンスから同時に投稿されても上限を超えない
+func (r *IncomingWebhookRepositoryOnDB) ConsumeRateLimit(ctx context.Context, inp repository.ConsumeRateLimitInput) error {
+	queries := db.New(r.pool)
+	consumed, err := queries.ConsumeIncomingWebhookRateLimit(ctx, inp.WebhookID)
+	if err != nil {
+		return fmt.Errorf("failed to consume rate limit: %w", err)
+	}
+	if consumed == 0 {
+		return repository.ErrRateLimitExceeded
+	}
+	return nil
+}
+
+// withOwnerLock はルームの行をロックし、操作者がルームの作成者であることを確認してから fn を実行する
+func (r *IncomingWebhookRepositoryOnDB) withOwnerLock(ctx context.Context, roomID, accountID uuid.UUID, fn func(queries *db.Queries) error) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rollback", slog.Any("err", err))
+		}
+	}()
+
+	queries := db.New(r.pool).WithTx(tx)
+
+	room, err := queries.GetRoomForUpdate(ctx, roomID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.ErrRoomNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get room: %w", err)
+	}
+	if room.CreatedBy != accountID {
+		return repository.ErrNotRoomOwner
+	}
+
+	if err := fn(queries); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
+
+var _ repository.IncomingWebhookRepository = new(IncomingWebhookRepositoryOnDB)
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+)
+
+type AuthenticateIncomingWebhookUsecase struct {
+	repo repository.IncomingWebhookRepository
+}
+
+type AuthenticateIncomingWebhookInput struct {
+	WebhookID string
+	Token     domain.IncomingWebhookToken
+}
+
+// AuthenticateIncomingWebhookOutput の BotAccountID はメッセージの投稿者として使う
+type AuthenticateIncomingWebhookOutput struct {
+	RoomID       string
+	BotAccountID string
+}
+
+func NewAuthenticateIncomingWebhookUsecase(repo repository.IncomingWebhookRepository) *AuthenticateIncomingWebhookUsecase {
+	return &AuthenticateIncomingWebhookUsecase{repo}
+}
+
+// Execute はトークンを確認してから投稿数を 1 つ消費する
+//
+// 誤ったトークンによるリクエストで正規の投稿枠が減らないよう、投稿数はトークンが一致した場合にだけ数える
+func (u *AuthenticateIncomingWebhookUsecase) Execute(ctx context.Context, inp AuthenticateIncomingWebhookInput) (AuthenticateIncomingWebhookOutput, error) {
+	webhookID, err := uuid.Parse(inp.WebhookID)
+	if err != nil {
+		return AuthenticateIncomingWebhookOutput{}, repository.ErrIncomingWebhookNotFound
+	}
+
+	cred, err := u.repo.GetCredential(ctx, repository.GetCredentialInput{WebhookID: webhookID})
+	if err != nil {
+		return AuthenticateIncomingWebhookOutput{}, fmt.Errorf("failed to get credential: %w", err)
+	}
+	if !inp.Token.Matches(cred.TokenHash) {
+		return AuthenticateIncomingWebhookOutput{}, domain.ErrInvalidIncomingWebhookToken
+	}
+
+	if err := u.repo.ConsumeRateLimit(ctx, repository.ConsumeRateLimitInput{WebhookID: webhookID}); err != nil {
+		return AuthenticateIncomingWebhookOutput{}, fmt.Errorf("failed to consume rate limit: %w", err)
+	}
+
+	return AuthenticateIncomingWebhookOutput{
+		RoomID:       cred.RoomID.String(),
+		BotAccountID: cred.BotAccountID.String(),
+	}, nil
+}
@@ -0,0 +1,130 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticateIncomingWebhookUsecase_Execute(t *testing.T) {
+	t.Parallel()
+
+	webhookID := uuid.New()
+	roomID := uuid.New()
+	botAccountID := uuid.New()
+	token := domain.GenerateIncomingWebhookToken()
+
+	credential := func(ctx context.Context, inp repository.GetCredentialInput) (repository.GetCredentialOutput, error) {
+		return repository.GetCredentialOutput{
+			RoomID:       roomID,
+			BotAccountID: botAccountID,
+			TokenHash:    token.Hash(),
+		}, nil
+	}
+
+	t.Run("トークンが一致する場合に投稿先と Bot を返す", func(t *testing.T) {
+		t.Parallel()
+
+		consumed := false
+		mockRepo := &mockIncomingWebhookRepository{
+			getCredentialFunc: credential,
+			consumeRateLimitFunc: func(ctx context.Context, inp repository.ConsumeRateLimitInput) error {
+				require.Equal(t, webhookID, inp.WebhookID)
+				consumed = true
+				return nil
+			},
+		}
+
+		uc := usecase.NewAuthenticateIncomingWebhookUsecase(mockRepo)
+		out, err := uc.Execute(t.Context(), usecase.AuthenticateIncomingWebhookInput{
+			WebhookID: webhookID.String(),
+			Token:     token,
+		})
+
+		require.NoError(t, err)
+		require.True(t, consumed)
+		require.Equal(t, roomID.String(), out.RoomID)
+		require.Equal(t, botAccountID.String(), out.BotAccountID)
+	})
+
+	t.Run("トークンが一致しない場合は投稿数を消費せずにエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockIncomingWebhookRepository{
+			getCredentialFunc: credential,
+			consumeRateLimitFunc: func(ctx context.Context, inp repository.ConsumeRateLimitInput) error {
+				t.Fatal("should not be called")
+				return nil
+			},
+		}
+
+		uc := usecase.NewAuthenticateIncomingWebhookUsecase(mockRepo)
+		_, err := uc.Execute(t.Context(), usecase.AuthenticateIncomingWebhookInput{
+			WebhookID: webhookID.String(),
+			Token:     domain.GenerateIncomingWebhookToken(),
+		})
+
+		require.ErrorIs(t, err, domain.ErrInvalidIncomingWebhookToken)
+	})
+
+	t.Run("投稿数の上限に達している場合にエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockIncomingWebhookRepository{
+			getCredentialFunc: credential,
+			consumeRateLimitFunc: func(ctx context.Context, inp repository.ConsumeRateLimitInput) error {
+				return repository.ErrRateLimitExceeded
+			},
+		}
+
+		uc := usecase.NewAuthenticateIncomingWebhookUsecase(mockRepo)
+		_, err := uc.Execute(t.Context(), usecase.AuthenticateIncomingWebhookInput{
+			WebhookID: webhookID.String(),
+			Token:     token,
+		})
+
+		require.ErrorIs(t, err, repository.ErrRateLimitExceeded)
+	})
+
+	t.Run("失効済みの場合に見つからないエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockIncomingWebhookRepository{
+			getCredentialFunc: func(ctx context.Context, inp repository.GetCredentialInput) (repository.GetCredentialOutput, error) {
+				return repository.GetCredentialOutput{}, repository.ErrIncomingWebhookNotFound
+			},
+		}
+
+		uc := usecase.NewAuthenticateIncomingWebhookUsecase(mockRepo)
+		_, err := uc.Execute(t.Context(), usecase.AuthenticateIncomingWebhookInput{
+			WebhookID: webhookID.String(),
+			Token:     token,
+		})
+
+		require.ErrorIs(t, err, repository.ErrIncomingWebhookNotFound)
+	})
+
+	t.Run("不正な Webhook ID の場合に見つからないエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockIncomingWebhookRepository{
+			getCredentialFunc: func(ctx context.Context, inp repository.GetCredentialInput) (repository.GetCredentialOutput, error) {
+				t.Fatal("should not be called")
+				return repository.GetCredentialOutput{}, nil
+			},
+		}
+
+		uc := usecase.NewAuthenticateIncomingWebhookUsecase(mockRepo)
+		_, err := uc.Execute(t.Context(), usecase.AuthenticateIncomingWebhookInput{
+			WebhookID: "invalid",
+			Token:     token,
+		})
+
+		require.ErrorIs(t, err, repository.ErrIncomingWebhookNotFound)
+	})
+}
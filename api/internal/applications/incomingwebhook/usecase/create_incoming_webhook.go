@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+)
+
+type CreateIncomingWebhookUsecase struct {
+	repo repository.IncomingWebhookRepository
+}
+
+type CreateIncomingWebhookInput struct {
+	RoomID    string
+	AccountID string
+	BotName   domain.UserName
+	RateLimit domain.IncomingWebhookRateLimit
+}
+
+// CreateIncomingWebhookOutput の Token は保存されないため、発行時にだけ返す
+type CreateIncomingWebhookOutput struct {
+	ID    string
+	Token domain.IncomingWebhookToken
+}
+
+// MaxIncomingWebhooksPerRoom はルームごとに登録できる受信 Webhook 数の上限。失効済みのものも数える
+const MaxIncomingWebhooksPerRoom = 10
+
+func NewCreateIncomingWebhookUsecase(repo repository.IncomingWebhookRepository) *CreateIncomingWebhookUsecase {
+	return &CreateIncomingWebhookUsecase{repo}
+}
+
+// Execute は投稿者となる Bot アカウントを作成し、受信 Webhook のトークンを発行する
+func (u *CreateIncomingWebhookUsecase) Execute(ctx context.Context, inp CreateIncomingWebhookInput) (CreateIncomingWebhookOutput, error) {
+	roomID, accountID, err := parseOwnerIDs(inp.RoomID, inp.AccountID)
+	if err != nil {
+		return CreateIncomingWebhookOutput{}, err
+	}
+
+	token := domain.GenerateIncomingWebhookToken()
+	res, err := u.repo.CreateIncomingWebhook(ctx, repository.CreateIncomingWebhookInput{
+		RoomID:      roomID,
+		AccountID:   accountID,
+		BotName:     inp.BotName.String(),
+		TokenHash:   token.Hash(),
+		RateLimit:   inp.RateLimit.PerMinute(),
+		MaxWebhooks: MaxIncomingWebhooksPerRoom,
+	})
+	if err != nil {
+		return CreateIncomingWebhookOutput{}, fmt.Errorf("failed to create incoming webhook: %w", err)
+	}
+
+	return CreateIncomingWebhookOutput{
+		ID:    res.ID.String(),
+		Token: token,
+	}, nil
+}
+
+// parseOwnerIDs は不正なルーム ID を存在しないルームとして扱う
+func parseOwnerIDs(roomID, accountID string) (uuid.UUID, uuid.UUID, error) {
+	room, err := uuid.Parse(roomID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, repository.ErrRoomNotFound
+	}
+	account, err := uuid.Parse(accountID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("failed to parse account id: %w", err)
+	}
+	return room, account, nil
+}
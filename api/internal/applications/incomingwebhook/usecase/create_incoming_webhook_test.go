@@ -0,0 +1,211 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock implementations
+type mockIncomingWebhookRepository struct {
+	createIncomingWebhookFunc func(ctx context.Context, inp repository.CreateIncomingWebhookInput) (repository.CreateIncomingWebhookOutput, error)
+	rotateTokenFunc           func(ctx context.Context, inp repository.RotateTokenInput) error
+	revokeIncomingWebhookFunc func(ctx context.Context, inp repository.RevokeIncomingWebhookInput) error
+	getCredentialFunc         func(ctx context.Context, inp repository.GetCredentialInput) (repository.GetCredentialOutput, error)
+	consumeRateLimitFunc      func(ctx context.Context, inp repository.ConsumeRateLimitInput) error
+}
+
+func (m *mockIncomingWebhookRepository) CreateIncomingWebhook(ctx context.Context, inp repository.CreateIncomingWebhookInput) (repository.CreateIncomingWebhookOutput, error) {
+	if m.createIncomingWebhookFunc != nil {
+		return m.createIncomingWebhookFunc(ctx, inp)
+	}
+	return repository.CreateIncomingWebhookOutput{}, nil
+}
+
+func (m *mockIncomingWebhookRepository) RotateToken(ctx context.Context, inp repository.RotateTokenInput) error {
+	if m.rotateTokenFunc != nil {
+		return m.rotateTokenFunc(ctx, inp)
+	}
+	return nil
+}
+
+func (m *mockIncomingWebhookRepository) RevokeIncomingWebhook(ctx context.Context, inp repository.RevokeIncomingWebhookInput) error {
+	if m.revokeIncomingWebhookFunc != nil {
+		return m.revokeIncomingWebhookFunc(ctx, inp)
+	}
+	return nil
+}
+
+func (m *mockIncomingWebhookRepository) GetCredential(ctx context.Context, inp repository.GetCredentialInput) (repository.GetCredentialOutput, error) {
+	if m.getCredentialFunc != nil {
+		return m.getCredentialFunc(ctx, inp)
+	}
+	return repository.GetCredentialOutput{}, nil
+}
+
+func (m *mockIncomingWebhookRepository) ConsumeRateLimit(ctx context.Context, inp repository.ConsumeRateLimitInput) error {
+	if m.consumeRateLimitFunc != nil {
+		return m.consumeRateLimitFunc(ctx, inp)
+	}
+	return nil
+}
+
+func TestCreateIncomingWebhookUsecase_Execute(t *testing.T) {
+	t.Parallel()
+
+	roomID := uuid.New()
+	accountID := uuid.New()
+	botName, _ := domain.NewUserName("ci")
+	rateLimit, _ := domain.NewIncomingWebhookRateLimit(0)
+
+	t.Run("トークンのハッシュだけを保存し、トークンを返す", func(t *testing.T) {
+		t.Parallel()
+
+		webhookID := uuid.New()
+		var savedHash []byte
+		mockRepo := &mockIncomingWebhookRepository{
+			createIncomingWebhookFunc: func(ctx context.Context, inp repository.CreateIncomingWebhookInput) (repository.CreateIncomingWebhookOutput, error) {
+				require.Equal(t, roomID, inp.RoomID)
+				require.Equal(t, accountID, inp.AccountID)
+				require.Equal(t, "ci", inp.BotName)
+				require.Equal(t, 60, inp.RateLimit)
+				require.Equal(t, usecase.MaxIncomingWebhooksPerRoom, inp.MaxWebhooks)
+				savedHash = inp.TokenHash
+				return repository.CreateIncomingWebhookOutput{ID: webhookID}, nil
+			},
+		}
+
+		uc := usecase.NewCreateIncomingWebhookUsecase(mockRepo)
+		out, err := uc.Execute(t.Context(), usecase.CreateIncomingWebhookInput{
+			RoomID:    roomID.String(),
+			AccountID: accountID.String(),
+			BotName:   botName,
+			RateLimit: rateLimit,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, webhookID.String(), out.ID)
+		require.True(t, out.Token.Matches(savedHash))
+		require.NotEqual(t, out.Token.String(), string(savedHash))
+	})
+
+	t.Run("不正なルーム ID の場合にルームが見つからないエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockIncomingWebhookRepository{
+			createIncomingWebhookFunc: func(ctx context.Context, inp repository.CreateIncomingWebhookInput) (repository.CreateIncomingWebhookOutput, error) {
+				t.Fatal("should not be called")
+				return repository.CreateIncomingWebhookOutput{}, nil
+			},
+		}
+
+		uc := usecase.NewCreateIncomingWebhookUsecase(mockRepo)
+		_, err := uc.Execute(t.Context(), usecase.CreateIncomingWebhookInput{
+			RoomID:    "invalid",
+			AccountID: accountID.String(),
+			BotName:   botName,
+			RateLimit: rateLimit,
+		})
+
+		require.ErrorIs(t, err, repository.ErrRoomNotFound)
+	})
+
+	t.Run("リポジトリのエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockIncomingWebhookRepository{
+			createIncomingWebhookFunc: func(ctx context.Context, inp repository.CreateIncomingWebhookInput) (repository.CreateIncomingWebhookOutput, error) {
+				return repository.CreateIncomingWebhookOutput{}, repository.ErrBotNameTaken
+			},
+		}
+
+		uc := usecase.NewCreateIncomingWebhookUsecase(mockRepo)
+		_, err := uc.Execute(t.Context(), usecase.CreateIncomingWebhookInput{
+			RoomID:    roomID.String(),
+			AccountID: accountID.String(),
+			BotName:   botName,
+			RateLimit: rateLimit,
+		})
+
+		require.ErrorIs(t, err, repository.ErrBotNameTaken)
+	})
+}
+
+func TestRotateIncomingWebhookTokenUsecase_Execute(t *testing.T) {
+	t.Parallel()
+
+	roomID := uuid.New()
+	accountID := uuid.New()
+
+	t.Run("新しいトークンのハッシュを保存する", func(t *testing.T) {
+		t.Parallel()
+
+		webhookID := uuid.New()
+		var savedHash []byte
+		mockRepo := &mockIncomingWebhookRepository{
+			rotateTokenFunc: func(ctx context.Context, inp repository.RotateTokenInput) error {
+				require.Equal(t, roomID, inp.RoomID)
+				require.Equal(t, webhookID, inp.WebhookID)
+				require.Equal(t, accountID, inp.AccountID)
+				savedHash = inp.TokenHash
+				return nil
+			},
+		}
+
+		uc := usecase.NewRotateIncomingWebhookTokenUsecase(mockRepo)
+		out, err := uc.Execute(t.Context(), usecase.RotateIncomingWebhookTokenInput{
+			RoomID:    roomID.String(),
+			WebhookID: webhookID.String(),
+			AccountID: accountID.String(),
+		})
+
+		require.NoError(t, err)
+		require.True(t, out.Token.Matches(savedHash))
+	})
+
+	t.Run("不正な Webhook ID の場合に見つからないエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockIncomingWebhookRepository{
+			rotateTokenFunc: func(ctx context.Context, inp repository.RotateTokenInput) error {
+				t.Fatal("should not be called")
+				return nil
+			},
+		}
+
+		uc := usecase.NewRotateIncomingWebhookTokenUsecase(mockRepo)
+		_, err := uc.Execute(t.Context(), usecase.RotateIncomingWebhookTokenInput{
+			RoomID:    roomID.String(),
+			WebhookID: "invalid",
+			AccountID: accountID.String(),
+		})
+
+		require.ErrorIs(t, err, repository.ErrIncomingWebhookNotFound)
+	})
+
+	t.Run("リポジトリのエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		dbErr := errors.New("db error")
+		mockRepo := &mockIncomingWebhookRepository{
+			rotateTokenFunc: func(ctx context.Context, inp repository.RotateTokenInput) error {
+				return dbErr
+			},
+		}
+
+		uc := usecase.NewRotateIncomingWebhookTokenUsecase(mockRepo)
+		_, err := uc.Execute(t.Context(), usecase.RotateIncomingWebhookTokenInput{
+			RoomID:    roomID.String(),
+			WebhookID: uuid.NewString(),
+			AccountID: accountID.String(),
+		})
+
+		require.ErrorIs(t, err, dbErr)
+	})
+}
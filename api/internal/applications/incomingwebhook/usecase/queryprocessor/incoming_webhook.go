@@ -0,0 +1,37 @@
+package queryprocessor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// GetIncomingWebhooksInput の AccountID はルームの作成者である必要がある
+type GetIncomingWebhooksInput struct {
+	RoomID    uuid.UUID
+	AccountID uuid.UUID
+}
+type GetIncomingWebhooksOutput struct {
+	Webhooks []IncomingWebhookDTO
+}
+
+// IncomingWebhookDTO はトークンを含まない。RevokedAt は失効済みの場合のみ設定する
+type IncomingWebhookDTO struct {
+	ID             string
+	Name           string
+	RateLimit      int
+	CreatedBy      string
+	CreatedAt      string
+	TokenRotatedAt string
+	RevokedAt      string
+}
+
+var (
+	ErrRoomNotFound = errors.New("room not found")
+	ErrNotRoomOwner = errors.New("not room owner")
+)
+
+type IncomingWebhookQueryProcessor interface {
+	GetIncomingWebhooks(ctx context.Context, inp GetIncomingWebhooksInput) (GetIncomingWebhooksOutput, error)
+}
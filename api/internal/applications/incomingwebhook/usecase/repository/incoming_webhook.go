@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// CreateIncomingWebhookInput の BotName は投稿者として表示する Bot アカウントの名前
+//
+// RateLimit は 1 分あたりの投稿数、MaxWebhooks はルームあたりの受信 Webhook の上限
+type CreateIncomingWebhookInput struct {
+	RoomID      uuid.UUID
+	AccountID   uuid.UUID
+	BotName     string
+	TokenHash   []byte
+	RateLimit   int
+	MaxWebhooks int
+}
+type CreateIncomingWebhookOutput struct {
+	ID uuid.UUID
+}
+
+type RotateTokenInput struct {
+	RoomID    uuid.UUID
+	WebhookID uuid.UUID
+	AccountID uuid.UUID
+	TokenHash []byte
+}
+
+type RevokeIncomingWebhookInput struct {
+	RoomID    uuid.UUID
+	WebhookID uuid.UUID
+	AccountID uuid.UUID
+}
+
+type GetCredentialInput struct {
+	WebhookID uuid.UUID
+}
+
+// GetCredentialOutput の BotAccountID は投稿者として記録するアカウント
+type GetCredentialOutput struct {
+	RoomID       uuid.UUID
+	BotAccountID uuid.UUID
+	TokenHash    []byte
+}
+
+type ConsumeRateLimitInput struct {
+	WebhookID uuid.UUID
+}
+
+var (
+	ErrRoomNotFound = errors.New("room not found")
+	// ErrNotRoomOwner はルームの作成者以外が受信 Webhook を操作しようとした場合に返す
+	ErrNotRoomOwner            = errors.New("not room owner")
+	ErrTooManyIncomingWebhooks = errors.New("too many incoming webhooks")
+	// ErrIncomingWebhookNotFound は存在しないか失効済みの受信 Webhook に投稿したり、トークンを再発行しようとした場合にも返す
+	ErrIncomingWebhookNotFound = errors.New("incoming webhook not found")
+	ErrBotNameTaken            = errors.New("bot name already taken")
+	ErrRateLimitExceeded       = errors.New("rate limit exceeded")
+)
+
+type IncomingWebhookRepository interface {
+	CreateIncomingWebhook(ctx context.Context, inp CreateIncomingWebhookInput) (CreateIncomingWebhookOutput, error)
+	RotateToken(ctx context.Context, inp RotateTokenInput) error
+	RevokeIncomingWebhook(ctx context.Context, inp RevokeIncomingWebhookInput) error
+	GetCredential(ctx context.Context, inp GetCredentialInput) (GetCredentialOutput, error)
+	// ConsumeRateLimit は上限に達している場合に ErrRateLimitExceeded を返す
+	ConsumeRateLimit(ctx context.Context, inp ConsumeRateLimitInput) error
+}
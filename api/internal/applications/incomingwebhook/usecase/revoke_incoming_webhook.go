@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/usecase/repository"
+)
+
+type RevokeIncomingWebhookUsecase struct {
+	repo repository.IncomingWebhookRepository
+}
+
+type RevokeIncomingWebhookInput struct {
+	RoomID    string
+	WebhookID string
+	AccountID string
+}
+
+type RevokeIncomingWebhookOutput struct{}
+
+func NewRevokeIncomingWebhookUsecase(repo repository.IncomingWebhookRepository) *RevokeIncomingWebhookUsecase {
+	return &RevokeIncomingWebhookUsecase{repo}
+}
+
+// Execute はトークンを失効させる。Bot アカウントと投稿済みのメッセージは残す
+func (u *RevokeIncomingWebhookUsecase) Execute(ctx context.Context, inp RevokeIncomingWebhookInput) (RevokeIncomingWebhookOutput, error) {
+	roomID, accountID, err := parseOwnerIDs(inp.RoomID, inp.AccountID)
+	if err != nil {
+		return RevokeIncomingWebhookOutput{}, err
+	}
+	webhookID, err := uuid.Parse(inp.WebhookID)
+	if err != nil {
+		return RevokeIncomingWebhookOutput{}, repository.ErrIncomingWebhookNotFound
+	}
+
+	if err := u.repo.RevokeIncomingWebhook(ctx, repository.RevokeIncomingWebhookInput{
+		RoomID:    roomID,
+		WebhookID: webhookID,
+		AccountID: accountID,
+	}); err != nil {
+		return RevokeIncomingWebhookOutput{}, fmt.Errorf("failed to revoke incoming webhook: %w", err)
+	}
+
+	return RevokeIncomingWebhookOutput{}, nil
+}
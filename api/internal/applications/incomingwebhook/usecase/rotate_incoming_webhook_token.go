@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+)
+
+type RotateIncomingWebhookTokenUsecase struct {
+	repo repository.IncomingWebhookRepository
+}
+
+type RotateIncomingWebhookTokenInput struct {
+	RoomID    string
+	WebhookID string
+	AccountID string
+}
+
+type RotateIncomingWebhookTokenOutput struct {
+	Token domain.IncomingWebhookToken
+}
+
+func NewRotateIncomingWebhookTokenUsecase(repo repository.IncomingWebhookRepository) *RotateIncomingWebhookTokenUsecase {
+	return &RotateIncomingWebhookTokenUsecase{repo}
+}
+
+// Execute は新しいトークンを発行する。以前のトークンはすぐに使えなくなる。失効は取り消せないため、失効済みの場合は発行しない
+func (u *RotateIncomingWebhookTokenUsecase) Execute(ctx context.Context, inp RotateIncomingWebhookTokenInput) (RotateIncomingWebhookTokenOutput, error) {
+	roomID, accountID, err := parseOwnerIDs(inp.RoomID, inp.AccountID)
+	if err != nil {
+		return RotateIncomingWebhookTokenOutput{}, err
+	}
+	webhookID, err := uuid.Parse(inp.WebhookID)
+	if err != nil {
+		return RotateIncomingWebhookTokenOutput{}, repository.ErrIncomingWebhookNotFound
+	}
+
+	token := domain.GenerateIncomingWebhookToken()
+	if err := u.repo.RotateToken(ctx, repository.RotateTokenInput{
+		RoomID:    roomID,
+		WebhookID: webhookID,
+		AccountID: accountID,
+		TokenHash: token.Hash(),
+	}); err != nil {
+		return RotateIncomingWebhookTokenOutput{}, fmt.Errorf("failed to rotate token: %w", err)
+	}
+
+	return RotateIncomingWebhookTokenOutput{Token: token}, nil
+}
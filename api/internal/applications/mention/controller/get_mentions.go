@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/mention/usecase/queryprocessor"
+)
+
+const (
+	defaultMentionsLimit = 50
+	maxMentionsLimit     = 100
+)
+
+var (
+	ErrInvalidPagination = errors.New("invalid pagination")
+)
+
+// GetMentionsInput の Limit が 0 の場合はデフォルト件数を返す
+type GetMentionsInput struct {
+	AccountID  string `json:"-"`
+	UnreadOnly bool   `json:"unreadOnly"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+}
+
+type GetMentionsOutput struct {
+	Mentions []Mention `json:"mentions"`
+	HasMore  bool      `json:"hasMore"`
+}
+
+type Mention struct {
+	ID        string `json:"id"`
+	MessageID string `json:"messageId"`
+	RoomID    string `json:"roomId"`
+	RoomName  string `json:"roomName"`
+	Author    string `json:"author"`
+	Content   string `json:"content"`
+	Read      bool   `json:"read"`
+	CreatedAt string `json:"createdAt"`
+}
+
+type GetMentionsController struct {
+	query queryprocessor.MentionQueryProcessor
+}
+
+func NewGetMentionsController(query queryprocessor.MentionQueryProcessor) *GetMentionsController {
+	return &GetMentionsController{query}
+}
+
+func (c *GetMentionsController) GetMentions(ctx context.Context, inp GetMentionsInput) (GetMentionsOutput, error) {
+	accountID, err := uuid.Parse(inp.AccountID)
+	if err != nil {
+		return GetMentionsOutput{}, fmt.Errorf("bad account id: %w", err)
+	}
+
+	limit := inp.Limit
+	if limit == 0 {
+		limit = defaultMentionsLimit
+	}
+	if limit < 0 || limit > maxMentionsLimit || inp.Offset < 0 {
+		return GetMentionsOutput{}, ErrInvalidPagination
+	}
+
+	// 1 件多く取得して次のページの有無を判定する
+	res, err := c.query.GetMentions(ctx, queryprocessor.GetMentionsInput{
+		AccountID:  accountID,
+		UnreadOnly: inp.UnreadOnly,
+		Limit:      limit + 1,
+		Offset:     inp.Offset,
+	})
+	if err != nil {
+		return GetMentionsOutput{}, fmt.Errorf("failed to get mentions: %w", err)
+	}
+
+	hasMore := len(res.Mentions) > limit
+	if hasMore {
+		res.Mentions = res.Mentions[:limit]
+	}
+
+	mentions := make([]Mention, 0, len(res.Mentions))
+	for _, dto := range res.Mentions {
+		mentions = append(mentions, Mention{
+			ID:        dto.ID,
+			MessageID: dto.MessageID,
+			RoomID:    dto.RoomID,
+			RoomName:  dto.RoomName,
+			Author:    dto.Author,
+			Content:   dto.Content,
+			Read:      dto.Read,
+			CreatedAt: dto.CreatedAt,
+		})
+	}
+
+	return GetMentionsOutput{
+		Mentions: mentions,
+		HasMore:  hasMore,
+	}, nil
+}
@@ -0,0 +1,132 @@
+package controller_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/mention/controller"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/mention/usecase/queryprocessor"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock implementations
+type mockMentionQueryProcessor struct {
+	getMentionsFunc func(ctx context.Context, inp queryprocessor.GetMentionsInput) (queryprocessor.GetMentionsOutput, error)
+}
+
+func (m *mockMentionQueryProcessor) GetMentions(ctx context.Context, inp queryprocessor.GetMentionsInput) (queryprocessor.GetMentionsOutput, error) {
+	if m.getMentionsFunc != nil {
+		return m.getMentionsFunc(ctx, inp)
+	}
+	return queryprocessor.GetMentionsOutput{}, nil
+}
+
+const accountID = "550e8400-e29b-41d4-a716-446655440000"
+
+func TestGetMentionsController_GetMentions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("メンション一覧取得成功", func(t *testing.T) {
+		t.Parallel()
+
+		mockQP := &mockMentionQueryProcessor{
+			getMentionsFunc: func(ctx context.Context, inp queryprocessor.GetMentionsInput) (queryprocessor.GetMentionsOutput, error) {
+				require.Equal(t, accountID, inp.AccountID.String())
+				require.True(t, inp.UnreadOnly)
+				return queryprocessor.GetMentionsOutput{
+					Mentions: []queryprocessor.MentionDTO{
+						{ID: "mention-1", MessageID: "msg-1", RoomName: "General", Author: "bob", Content: "@alice hi"},
+					},
+				}, nil
+			},
+		}
+
+		ctrl := controller.NewGetMentionsController(mockQP)
+
+		out, err := ctrl.GetMentions(t.Context(), controller.GetMentionsInput{
+			AccountID:  accountID,
+			UnreadOnly: true,
+		})
+
+		require.NoError(t, err)
+		require.Len(t, out.Mentions, 1)
+		require.Equal(t, "mention-1", out.Mentions[0].ID)
+		require.False(t, out.HasMore)
+	})
+
+	t.Run("Limit 省略時はデフォルト件数で問い合わせる", func(t *testing.T) {
+		t.Parallel()
+
+		mockQP := &mockMentionQueryProcessor{
+			getMentionsFunc: func(ctx context.Context, inp queryprocessor.GetMentionsInput) (queryprocessor.GetMentionsOutput, error) {
+				require.Equal(t, 51, inp.Limit)
+				require.Equal(t, 0, inp.Offset)
+				return queryprocessor.GetMentionsOutput{}, nil
+			},
+		}
+
+		ctrl := controller.NewGetMentionsController(mockQP)
+
+		_, err := ctrl.GetMentions(t.Context(), controller.GetMentionsInput{AccountID: accountID})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("Limit を超える件数がある場合は HasMore になる", func(t *testing.T) {
+		t.Parallel()
+
+		mockQP := &mockMentionQueryProcessor{
+			getMentionsFunc: func(ctx context.Context, inp queryprocessor.GetMentionsInput) (queryprocessor.GetMentionsOutput, error) {
+				require.Equal(t, 3, inp.Limit)
+				require.Equal(t, 4, inp.Offset)
+				return queryprocessor.GetMentionsOutput{
+					Mentions: []queryprocessor.MentionDTO{{ID: "1"}, {ID: "2"}, {ID: "3"}},
+				}, nil
+			},
+		}
+
+		ctrl := controller.NewGetMentionsController(mockQP)
+
+		out, err := ctrl.GetMentions(t.Context(), controller.GetMentionsInput{
+			AccountID: accountID,
+			Limit:     2,
+			Offset:    4,
+		})
+
+		require.NoError(t, err)
+		require.Len(t, out.Mentions, 2)
+		require.True(t, out.HasMore)
+	})
+
+	t.Run("不正なページ指定でエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := controller.NewGetMentionsController(&mockMentionQueryProcessor{})
+
+		for _, inp := range []controller.GetMentionsInput{
+			{AccountID: accountID, Limit: -1},
+			{AccountID: accountID, Limit: 101},
+			{AccountID: accountID, Offset: -1},
+		} {
+			_, err := ctrl.GetMentions(t.Context(), inp)
+			require.ErrorIs(t, err, controller.ErrInvalidPagination)
+		}
+	})
+
+	t.Run("クエリプロセッサエラー時にエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockQP := &mockMentionQueryProcessor{
+			getMentionsFunc: func(ctx context.Context, inp queryprocessor.GetMentionsInput) (queryprocessor.GetMentionsOutput, error) {
+				return queryprocessor.GetMentionsOutput{}, errors.New("db error")
+			},
+		}
+
+		ctrl := controller.NewGetMentionsController(mockQP)
+
+		_, err := ctrl.GetMentions(t.Context(), controller.GetMentionsInput{AccountID: accountID})
+
+		require.Error(t, err)
+	})
+}
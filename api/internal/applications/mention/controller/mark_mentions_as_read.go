@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/mention/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/mention/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+)
+
+// MarkMentionsAsReadInput の MentionIDs を省略した場合は未読のメンションをすべて既読にする
+type MarkMentionsAsReadInput struct {
+	AccountID  string   `json:"-"`
+	MentionIDs []string `json:"mentionIds"`
+}
+
+type MarkMentionsAsReadOutput struct {
+	Updated int64 `json:"updated"`
+}
+
+type MarkMentionsAsReadController struct {
+	repo repository.MentionRepository
+}
+
+func NewMarkMentionsAsReadController(repo repository.MentionRepository) *MarkMentionsAsReadController {
+	return &MarkMentionsAsReadController{repo}
+}
+
+func (c *MarkMentionsAsReadController) MarkMentionsAsRead(ctx context.Context, inp MarkMentionsAsReadInput) (MarkMentionsAsReadOutput, error) {
+	uc := usecase.NewMarkMentionsAsReadUsecase(c.repo)
+	res, err := tracing.Execute(ctx, "MarkMentionsAsReadUsecase", uc.Execute, usecase.MarkMentionsAsReadInput{
+		AccountID:  inp.AccountID,
+		MentionIDs: inp.MentionIDs,
+	})
+	if err != nil {
+		return MarkMentionsAsReadOutput{}, fmt.Errorf("failed to mark mentions as read: %w", err)
+	}
+
+	return MarkMentionsAsReadOutput{
+		Updated: res.Updated,
+	}, nil
+}
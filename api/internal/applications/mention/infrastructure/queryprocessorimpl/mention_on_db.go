@@ -0,0 +1,54 @@
+package queryprocessorimpl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/mention/usecase/queryprocessor"
+	"github.com/quietsato/toy-small-chat/api/internal/db"
+)
+
+type MentionQueryProcessorOnDB struct {
+	queries *db.Queries
+}
+
+func NewMentionQueryProcessorOnDB(pool *pgxpool.Pool) *MentionQueryProcessorOnDB {
+	return &MentionQueryProcessorOnDB{
+		queries: db.New(pool),
+	}
+}
+
+// GetMentions implements queryprocessor.MentionQueryProcessor.
+func (q *MentionQueryProcessorOnDB) GetMentions(ctx context.Context, inp queryprocessor.GetMentionsInput) (queryprocessor.GetMentionsOutput, error) {
+	rows, err := q.queries.GetMentionsByAccountID(ctx, db.GetMentionsByAccountIDParams{
+		AccountID:   inp.AccountID,
+		UnreadOnly:  inp.UnreadOnly,
+		LimitCount:  int32(inp.Limit),
+		OffsetCount: int32(inp.Offset),
+	})
+	if err != nil {
+		return queryprocessor.GetMentionsOutput{}, fmt.Errorf("failed to get mentions: %w", err)
+	}
+
+	mentions := make([]queryprocessor.MentionDTO, len(rows))
+	for i, row := range rows {
+		mentions[i] = queryprocessor.MentionDTO{
+			ID:        row.ID.String(),
+			MessageID: row.MessageID.String(),
+			RoomID:    row.RoomID.String(),
+			RoomName:  row.RoomName,
+			Author:    row.AuthorName,
+			Content:   row.Content,
+			Read:      row.ReadAt.Valid,
+			CreatedAt: row.CreatedAt.Time.Format(time.RFC3339),
+		}
+	}
+
+	return queryprocessor.GetMentionsOutput{
+		Mentions: mentions,
+	}, nil
+}
+
+var _ queryprocessor.MentionQueryProcessor = (*MentionQueryProcessorOnDB)(nil)
@@ -0,0 +1,36 @@
+package repositoryimpl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/mention/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/db"
+)
+
+func NewMentionRepositoryOnDB(pool *pgxpool.Pool) *MentionRepositoryOnDB {
+	return &MentionRepositoryOnDB{pool}
+}
+
+type MentionRepositoryOnDB struct {
+	pool *pgxpool.Pool
+}
+
+// MarkMentionsAsRead implements repository.MentionRepository.
+func (r *MentionRepositoryOnDB) MarkMentionsAsRead(ctx context.Context, inp repository.MarkMentionsAsReadInput) (repository.MarkMentionsAsReadOutput, error) {
+	queries := db.New(r.pool)
+	updated, err := queries.MarkMentionsAsRead(ctx, db.MarkMentionsAsReadParams{
+		AccountID:  inp.AccountID,
+		MentionIds: inp.MentionIDs,
+	})
+	if err != nil {
+		return repository.MarkMentionsAsReadOutput{}, fmt.Errorf("failed to query: %w", err)
+	}
+
+	return repository.MarkMentionsAsReadOutput{
+		Updated: updated,
+	}, nil
+}
+
+var _ repository.MentionRepository = new(MentionRepositoryOnDB)
@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/mention/usecase/repository"
+)
+
+type MarkMentionsAsReadUsecase struct {
+	repo repository.MentionRepository
+}
+
+type MarkMentionsAsReadInput struct {
+	AccountID  string
+	MentionIDs []string
+}
+
+type MarkMentionsAsReadOutput struct {
+	Updated int64
+}
+
+var (
+	ErrInvalidMentionID = errors.New("invalid mention id")
+)
+
+func NewMarkMentionsAsReadUsecase(repo repository.MentionRepository) *MarkMentionsAsReadUsecase {
+	return &MarkMentionsAsReadUsecase{repo}
+}
+
+func (u *MarkMentionsAsReadUsecase) Execute(ctx context.Context, inp MarkMentionsAsReadInput) (MarkMentionsAsReadOutput, error) {
+	accountID, err := uuid.Parse(inp.AccountID)
+	if err != nil {
+		return MarkMentionsAsReadOutput{}, fmt.Errorf("failed to parse account id: %w", err)
+	}
+
+	mentionIDs := make([]uuid.UUID, 0, len(inp.MentionIDs))
+	for _, s := range inp.MentionIDs {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return MarkMentionsAsReadOutput{}, ErrInvalidMentionID
+		}
+		mentionIDs = append(mentionIDs, id)
+	}
+
+	res, err := u.repo.MarkMentionsAsRead(ctx, repository.MarkMentionsAsReadInput{
+		AccountID:  accountID,
+		MentionIDs: mentionIDs,
+	})
+	if err != nil {
+		return MarkMentionsAsReadOutput{}, fmt.Errorf("failed to mark mentions as read: %w", err)
+	}
+
+	return MarkMentionsAsReadOutput{
+		Updated: res.Updated,
+	}, nil
+}
@@ -0,0 +1,98 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/mention/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/mention/usecase/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock implementations
+type mockMentionRepository struct {
+	markMentionsAsReadFunc func(ctx context.Context, inp repository.MarkMentionsAsReadInput) (repository.MarkMentionsAsReadOutput, error)
+}
+
+func (m *mockMentionRepository) MarkMentionsAsRead(ctx context.Context, inp repository.MarkMentionsAsReadInput) (repository.MarkMentionsAsReadOutput, error) {
+	if m.markMentionsAsReadFunc != nil {
+		return m.markMentionsAsReadFunc(ctx, inp)
+	}
+	return repository.MarkMentionsAsReadOutput{}, nil
+}
+
+const (
+	accountID = "550e8400-e29b-41d4-a716-446655440000"
+	mentionID = "8481027d-d6f6-402f-ae6d-98571e8f6496"
+)
+
+func TestMarkMentionsAsReadUsecase_Execute(t *testing.T) {
+	t.Parallel()
+
+	t.Run("指定したメンションを既読にする", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockMentionRepository{
+			markMentionsAsReadFunc: func(ctx context.Context, inp repository.MarkMentionsAsReadInput) (repository.MarkMentionsAsReadOutput, error) {
+				require.Equal(t, accountID, inp.AccountID.String())
+				require.Len(t, inp.MentionIDs, 1)
+				require.Equal(t, mentionID, inp.MentionIDs[0].String())
+				return repository.MarkMentionsAsReadOutput{Updated: 1}, nil
+			},
+		}
+
+		uc := usecase.NewMarkMentionsAsReadUsecase(mockRepo)
+		out, err := uc.Execute(t.Context(), usecase.MarkMentionsAsReadInput{
+			AccountID:  accountID,
+			MentionIDs: []string{mentionID},
+		})
+
+		require.NoError(t, err)
+		require.EqualValues(t, 1, out.Updated)
+	})
+
+	t.Run("ID 省略時はすべて既読にする", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockMentionRepository{
+			markMentionsAsReadFunc: func(ctx context.Context, inp repository.MarkMentionsAsReadInput) (repository.MarkMentionsAsReadOutput, error) {
+				require.Empty(t, inp.MentionIDs)
+				return repository.MarkMentionsAsReadOutput{Updated: 3}, nil
+			},
+		}
+
+		uc := usecase.NewMarkMentionsAsReadUsecase(mockRepo)
+		out, err := uc.Execute(t.Context(), usecase.MarkMentionsAsReadInput{AccountID: accountID})
+
+		require.NoError(t, err)
+		require.EqualValues(t, 3, out.Updated)
+	})
+
+	t.Run("不正なメンション ID でエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		uc := usecase.NewMarkMentionsAsReadUsecase(&mockMentionRepository{})
+		_, err := uc.Execute(t.Context(), usecase.MarkMentionsAsReadInput{
+			AccountID:  accountID,
+			MentionIDs: []string{"not-a-uuid"},
+		})
+
+		require.ErrorIs(t, err, usecase.ErrInvalidMentionID)
+	})
+
+	t.Run("リポジトリエラー時にエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockMentionRepository{
+			markMentionsAsReadFunc: func(ctx context.Context, inp repository.MarkMentionsAsReadInput) (repository.MarkMentionsAsReadOutput, error) {
+				return repository.MarkMentionsAsReadOutput{}, errors.New("db error")
+			},
+		}
+
+		uc := usecase.NewMarkMentionsAsReadUsecase(mockRepo)
+		_, err := uc.Execute(t.Context(), usecase.MarkMentionsAsReadInput{AccountID: accountID})
+
+		require.Error(t, err)
+	})
+}
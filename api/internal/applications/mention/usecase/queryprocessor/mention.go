@@ -0,0 +1,31 @@
+package queryprocessor
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type GetMentionsInput struct {
+	AccountID  uuid.UUID
+	UnreadOnly bool
+	Limit      int
+	Offset     int
+}
+type GetMentionsOutput struct {
+	Mentions []MentionDTO
+}
+type MentionDTO struct {
+	ID        string
+	MessageID string
+	RoomID    string
+	RoomName  string
+	Author    string
+	Content   string
+	Read      bool
+	CreatedAt string
+}
+
+type MentionQueryProcessor interface {
+	GetMentions(ctx context.Context, inp GetMentionsInput) (GetMentionsOutput, error)
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// MarkMentionsAsReadInput の MentionIDs が空の場合は未読のメンションをすべて既読にする
+type MarkMentionsAsReadInput struct {
+	AccountID  uuid.UUID
+	MentionIDs []uuid.UUID
+}
+type MarkMentionsAsReadOutput struct {
+	Updated int64
+}
+
+type MentionRepository interface {
+	MarkMentionsAsRead(ctx context.Context, inp MarkMentionsAsReadInput) (MarkMentionsAsReadOutput, error)
+}
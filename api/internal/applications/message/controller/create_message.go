@@ -2,35 +2,102 @@ package controller
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase"
 	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
 )
 
 type CreateMessageController struct {
-	repo repository.MessageRepository
+	repo     repository.MessageRepository
+	commands usecase.SlashCommandDispatcher
 }
 
-func NewCreateMessageController(repo repository.MessageRepository) *CreateMessageController {
-	return &CreateMessageController{repo}
+// NewCreateMessageController の commands が nil の場合はスラッシュコマンドを実行しない
+func NewCreateMessageController(repo repository.MessageRepository, commands usecase.SlashCommandDispatcher) *CreateMessageController {
+	return &CreateMessageController{repo, commands}
 }
 
-func (c *CreateMessageController) CreateMessage(ctx context.Context, inp CreateMessageInput) error {
-	err := c.repo.CreateMessage(ctx, repository.CreateMessageInput{
-		AuthorID: inp.AuthorID,
-		RoomID:   inp.RoomID,
-		Content:  inp.Content,
+func (c *CreateMessageController) CreateMessage(ctx context.Context, inp CreateMessageInput) (CreateMessageOutput, error) {
+	if _, err := domain.ParseRoomID(inp.RoomID); err != nil {
+		return CreateMessageOutput{}, fmt.Errorf("bad room id: %w", repository.ErrRoomNotFound)
+	}
+
+	content, err := domain.NewMessageContent(inp.Content)
+	if err != nil {
+		return CreateMessageOutput{}, fmt.Errorf("bad content: %w", err)
+	}
+
+	format, err := domain.NewMessageFormat(inp.Format)
+	if err != nil {
+		return CreateMessageOutput{}, fmt.Errorf("bad format: %w", err)
+	}
+
+	attachmentIDs := make([]domain.AttachmentID, 0, len(inp.AttachmentIDs))
+	for _, s := range inp.AttachmentIDs {
+		id, err := domain.ParseAttachmentID(s)
+		if err != nil {
+			return CreateMessageOutput{}, fmt.Errorf("bad attachment id: %w", repository.ErrAttachmentNotAvailable)
+		}
+		attachmentIDs = append(attachmentIDs, id)
+	}
+
+	uc := usecase.NewCreateMessageUsecase(c.repo, c.commands)
+	out, err := tracing.Execute(ctx, "CreateMessageUsecase", uc.Execute, usecase.CreateMessageInput{
+		AuthorID:                 inp.AuthorID,
+		RoomID:                   inp.RoomID,
+		Content:                  content,
+		Format:                   format,
+		AttachmentIDs:            attachmentIDs,
+		ScheduledMessageID:       inp.ScheduledMessageID,
+		ScheduledMessageAttempts: inp.ScheduledMessageAttempts,
 	})
 	if err != nil {
-		return err
+		return CreateMessageOutput{}, err
 	}
 
-	return nil
+	if out.Ephemeral {
+		return CreateMessageOutput{
+			Ephemeral: &EphemeralMessage{
+				Content: out.Content.String(),
+				Format:  out.Format.String(),
+			},
+		}, nil
+	}
+	return CreateMessageOutput{
+		ID:       out.ID,
+		AuthorID: out.AuthorID,
+		Content:  out.Content.String(),
+		Format:   out.Format.String(),
+	}, nil
 }
 
 type CreateMessageInput struct {
-	RoomID   string `json:"roomID"`
-	Content  string `json:"content"`
-	AuthorID string `json:"-"`
+	RoomID        string   `json:"roomID"`
+	Content       string   `json:"content"`
+	Format        string   `json:"format"`
+	AttachmentIDs []string `json:"attachmentIds"`
+	AuthorID      string   `json:"-"`
+	// ScheduledMessageID と ScheduledMessageAttempts は予約メッセージを投稿する場合に、ワーカーが取り出したメッセージと送信回数を指定する
+	ScheduledMessageID       string `json:"-"`
+	ScheduledMessageAttempts int    `json:"-"`
 }
 
-type CreateMessageOutput struct{}
+// CreateMessageOutput はスラッシュコマンドの応答が ephemeral の場合 ID を持たず、Ephemeral だけを返す
+//
+// AuthorID, Content, Format は保存したメッセージの内容で、送信 Webhook への通知に使う
+type CreateMessageOutput struct {
+	ID        string            `json:"id,omitempty"`
+	Ephemeral *EphemeralMessage `json:"ephemeral,omitempty"`
+	AuthorID  string            `json:"-"`
+	Content   string            `json:"-"`
+	Format    string            `json:"-"`
+}
+
+// EphemeralMessage は保存されず、コマンドの実行者にだけ返す応答
+type EphemeralMessage struct {
+	Content string `json:"content"`
+	Format  string `json:"format"`
+}
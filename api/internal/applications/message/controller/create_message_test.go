@@ -7,20 +7,22 @@ import (
 
 	"github.com/quietsato/toy-small-chat/api/internal/applications/message/controller"
 	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
 	"github.com/stretchr/testify/require"
 )
 
 // Mock implementations
 type mockMessageRepository struct {
 	createMessageFunc func(ctx context.Context, inp repository.CreateMessageInput) error
+	createdMessageID  string
 	getFunc           func() error
 }
 
-func (m *mockMessageRepository) CreateMessage(ctx context.Context, inp repository.CreateMessageInput) error {
+func (m *mockMessageRepository) CreateMessage(ctx context.Context, inp repository.CreateMessageInput) (repository.CreateMessageOutput, error) {
 	if m.createMessageFunc != nil {
-		return m.createMessageFunc(ctx, inp)
+		return repository.CreateMessageOutput{ID: m.createdMessageID}, m.createMessageFunc(ctx, inp)
 	}
-	return nil
+	return repository.CreateMessageOutput{ID: m.createdMessageID}, nil
 }
 
 func (m *mockMessageRepository) Get() error {
@@ -39,21 +41,73 @@ func TestCreateMessageController_CreateMessage(t *testing.T) {
 		mockRepo := &mockMessageRepository{
 			createMessageFunc: func(ctx context.Context, inp repository.CreateMessageInput) error {
 				require.Equal(t, "author-123", inp.AuthorID)
-				require.Equal(t, "room-456", inp.RoomID)
+				require.Equal(t, "a4f2d1c9-6b8e-4f7a-8d25-0e9b7c3a5f62", inp.RoomID)
 				require.Equal(t, "Hello, World!", inp.Content)
 				return nil
 			},
+			createdMessageID: "message-789",
 		}
 
-		ctrl := controller.NewCreateMessageController(mockRepo)
+		ctrl := controller.NewCreateMessageController(mockRepo, nil)
 
-		err := ctrl.CreateMessage(t.Context(), controller.CreateMessageInput{
+		out, err := ctrl.CreateMessage(t.Context(), controller.CreateMessageInput{
 			AuthorID: "author-123",
-			RoomID:   "room-456",
+			RoomID:   "a4f2d1c9-6b8e-4f7a-8d25-0e9b7c3a5f62",
 			Content:  "Hello, World!",
 		})
 
 		require.NoError(t, err)
+		require.Equal(t, "message-789", out.ID)
+	})
+
+	t.Run("空の本文でエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockMessageRepository{
+			createMessageFunc: func(ctx context.Context, inp repository.CreateMessageInput) error {
+				t.Fatal("should not be called")
+				return nil
+			},
+		}
+
+		ctrl := controller.NewCreateMessageController(mockRepo, nil)
+
+		_, err := ctrl.CreateMessage(t.Context(), controller.CreateMessageInput{
+			AuthorID: "author-123",
+			RoomID:   "a4f2d1c9-6b8e-4f7a-8d25-0e9b7c3a5f62",
+			Content:  "",
+		})
+
+		require.ErrorIs(t, err, domain.ErrInvalidMessageContent)
+	})
+
+	t.Run("不正な添付ファイル ID でエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := controller.NewCreateMessageController(&mockMessageRepository{}, nil)
+
+		_, err := ctrl.CreateMessage(t.Context(), controller.CreateMessageInput{
+			AuthorID:      "author-123",
+			RoomID:        "a4f2d1c9-6b8e-4f7a-8d25-0e9b7c3a5f62",
+			Content:       "see attached",
+			AttachmentIDs: []string{"not-a-uuid"},
+		})
+
+		require.ErrorIs(t, err, repository.ErrAttachmentNotAvailable)
+	})
+
+	t.Run("不正なルーム ID の場合は ErrRoomNotFound を返す", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := controller.NewCreateMessageController(&mockMessageRepository{}, nil)
+
+		_, err := ctrl.CreateMessage(t.Context(), controller.CreateMessageInput{
+			AuthorID: "author-123",
+			RoomID:   "not-a-uuid",
+			Content:  "Hello, World!",
+		})
+
+		require.ErrorIs(t, err, repository.ErrRoomNotFound)
 	})
 
 	t.Run("リポジトリエラー時にエラーを返す", func(t *testing.T) {
@@ -65,11 +119,11 @@ func TestCreateMessageController_CreateMessage(t *testing.T) {
 			},
 		}
 
-		ctrl := controller.NewCreateMessageController(mockRepo)
+		ctrl := controller.NewCreateMessageController(mockRepo, nil)
 
-		err := ctrl.CreateMessage(t.Context(), controller.CreateMessageInput{
+		_, err := ctrl.CreateMessage(t.Context(), controller.CreateMessageInput{
 			AuthorID: "author-123",
-			RoomID:   "room-456",
+			RoomID:   "a4f2d1c9-6b8e-4f7a-8d25-0e9b7c3a5f62",
 			Content:  "Hello, World!",
 		})
 
@@ -84,7 +138,7 @@ func TestNewCreateMessageController(t *testing.T) {
 		t.Parallel()
 		mockRepo := &mockMessageRepository{}
 
-		ctrl := controller.NewCreateMessageController(mockRepo)
+		ctrl := controller.NewCreateMessageController(mockRepo, nil)
 
 		require.NotNil(t, ctrl)
 	})
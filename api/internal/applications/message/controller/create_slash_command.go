@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+)
+
+// CreateSlashCommandInput の Secret が空の場合はランダムな共有鍵を生成する
+//
+// BotName はコマンドの応答を投稿する Bot アカウントの名前
+type CreateSlashCommandInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	Secret      string `json:"secret"`
+	BotName     string `json:"botName"`
+	RoomID      string `json:"-"`
+	AccountID   string `json:"-"`
+}
+
+// CreateSlashCommandOutput の Secret は登録時にだけ返す
+type CreateSlashCommandOutput struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	BotName     string `json:"botName"`
+	Secret      string `json:"secret"`
+}
+
+type CreateSlashCommandController struct {
+	repo   repository.SlashCommandRepository
+	policy domain.WebhookNetworkPolicy
+}
+
+// NewCreateSlashCommandController の policy はホストが IP アドレスか localhost の送信先を登録時に拒否するために使う
+func NewCreateSlashCommandController(repo repository.SlashCommandRepository, policy domain.WebhookNetworkPolicy) *CreateSlashCommandController {
+	return &CreateSlashCommandController{repo, policy}
+}
+
+func (c *CreateSlashCommandController) CreateSlashCommand(ctx context.Context, inp CreateSlashCommandInput) (CreateSlashCommandOutput, error) {
+	name, err := domain.NewSlashCommandName(inp.Name)
+	if err != nil {
+		return CreateSlashCommandOutput{}, fmt.Errorf("bad name: %w", err)
+	}
+
+	description, err := domain.NewSlashCommandDescription(inp.Description)
+	if err != nil {
+		return CreateSlashCommandOutput{}, fmt.Errorf("bad description: %w", err)
+	}
+
+	url, err := domain.NewWebhookURL(inp.URL)
+	if err != nil {
+		return CreateSlashCommandOutput{}, fmt.Errorf("bad url: %w", err)
+	}
+	if !c.policy.AllowsURL(url) {
+		return CreateSlashCommandOutput{}, fmt.Errorf("bad url: %w", domain.ErrWebhookURLNotAllowed)
+	}
+
+	secret := domain.GenerateWebhookSecret()
+	if inp.Secret != "" {
+		secret, err = domain.NewWebhookSecret(inp.Secret)
+		if err != nil {
+			return CreateSlashCommandOutput{}, fmt.Errorf("bad secret: %w", err)
+		}
+	}
+
+	botName, err := domain.NewUserName(inp.BotName)
+	if err != nil {
+		return CreateSlashCommandOutput{}, fmt.Errorf("bad bot name: %w", err)
+	}
+
+	uc := usecase.NewCreateSlashCommandUsecase(c.repo)
+	res, err := tracing.Execute(ctx, "CreateSlashCommandUsecase", uc.Execute, usecase.CreateSlashCommandInput{
+		RoomID:      inp.RoomID,
+		AccountID:   inp.AccountID,
+		Name:        name,
+		Description: description,
+		CallbackURL: url,
+		Secret:      secret,
+		BotName:     botName,
+	})
+	if err != nil {
+		return CreateSlashCommandOutput{}, err
+	}
+
+	return CreateSlashCommandOutput{
+		ID:          res.ID,
+		Name:        name.String(),
+		Description: description.String(),
+		URL:         url.String(),
+		BotName:     botName.String(),
+		Secret:      secret.String(),
+	}, nil
+}
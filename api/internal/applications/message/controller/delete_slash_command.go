@@ -0,0 +1,32 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+)
+
+type DeleteSlashCommandInput struct {
+	RoomID    string
+	CommandID string
+	AccountID string
+}
+
+type DeleteSlashCommandController struct {
+	repo repository.SlashCommandRepository
+}
+
+func NewDeleteSlashCommandController(repo repository.SlashCommandRepository) *DeleteSlashCommandController {
+	return &DeleteSlashCommandController{repo}
+}
+
+func (c *DeleteSlashCommandController) DeleteSlashCommand(ctx context.Context, inp DeleteSlashCommandInput) error {
+	uc := usecase.NewDeleteSlashCommandUsecase(c.repo)
+	return tracing.ExecuteNoOutput(ctx, "DeleteSlashCommandUsecase", uc.Execute, usecase.DeleteSlashCommandInput{
+		RoomID:    inp.RoomID,
+		CommandID: inp.CommandID,
+		AccountID: inp.AccountID,
+	})
+}
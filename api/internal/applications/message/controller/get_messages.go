@@ -1,18 +1,27 @@
 package controller
 
 import (
+	"fmt"
+
 	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/queryprocessor"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/service"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
 )
 
 type GetMessagesController struct {
-	query queryprocessor.MessageQueryProcessor
+	query    queryprocessor.MessageQueryProcessor
+	renderer service.MessageRenderer
 }
 
-func NewGetMessagesController(query queryprocessor.MessageQueryProcessor) *GetMessagesController {
-	return &GetMessagesController{query}
+func NewGetMessagesController(query queryprocessor.MessageQueryProcessor, renderer service.MessageRenderer) *GetMessagesController {
+	return &GetMessagesController{query, renderer}
 }
 
 func (c *GetMessagesController) GetMessages(inp GetMessagesInput) (GetMessagesOutput, error) {
+	if _, err := domain.ParseRoomID(inp.RoomID); err != nil {
+		return GetMessagesOutput{}, fmt.Errorf("bad room id: %w", queryprocessor.ErrRoomNotFound)
+	}
+
 	queryResult, err := c.query.GetMessages(inp.RoomID)
 	if err != nil {
 		return GetMessagesOutput{}, err
@@ -20,11 +29,68 @@ func (c *GetMessagesController) GetMessages(inp GetMessagesInput) (GetMessagesOu
 
 	msgs := make([]Message, 0, len(queryResult))
 	for _, msg := range queryResult {
+		mentions := make([]MentionSpan, 0, len(msg.Mentions))
+		for _, m := range msg.Mentions {
+			mentions = append(mentions, MentionSpan{
+				Kind:     m.Kind,
+				UserName: m.UserName,
+				Start:    m.Start,
+				End:      m.End,
+			})
+		}
+
+		attachments := make([]Attachment, 0, len(msg.Attachments))
+		for _, a := range msg.Attachments {
+			url := fmt.Sprintf("/rooms/%s/attachments/%s", inp.RoomID, a.ID)
+			thumbnailURL := ""
+			if a.HasThumbnail {
+				thumbnailURL = url + "/thumbnail"
+			}
+			attachments = append(attachments, Attachment{
+				ID:           a.ID,
+				FileName:     a.FileName,
+				ContentType:  a.ContentType,
+				Size:         a.Size,
+				URL:          url,
+				ThumbnailURL: thumbnailURL,
+			})
+		}
+
+		// 保存済みの値が不正な場合は plain として表示する
+		format, err := domain.NewMessageFormat(msg.Format)
+		if err != nil {
+			format = domain.MessageFormatPlain
+		}
+
+		kind := domain.MessageKindUser
+		var event *SystemEvent
+		if msg.Kind == string(domain.MessageKindSystem) {
+			kind = domain.MessageKindSystem
+			format = domain.MessageFormatPlain
+			if msg.Event != nil {
+				event = &SystemEvent{
+					Type:             msg.Event.Type,
+					MessageID:        msg.Event.MessageID,
+					RoomName:         msg.Event.RoomName,
+					PreviousRoomName: msg.Event.PreviousRoomName,
+					Topic:            msg.Event.Topic,
+				}
+			}
+		}
+
 		msgs = append(msgs, Message{
-			ID:        msg.ID,
-			Content:   msg.Content,
-			Author:    msg.Author,
-			CreatedAt: msg.CreatedAt,
+			ID:          msg.ID,
+			Type:        string(kind),
+			Content:     msg.Content,
+			Format:      format.String(),
+			HTML:        c.renderer.RenderHTML(msg.Content, format),
+			Author:      msg.Author,
+			AuthorIsBot: msg.AuthorIsBot,
+			CreatedAt:   msg.CreatedAt,
+			Pinned:      msg.Pinned,
+			Mentions:    mentions,
+			Attachments: attachments,
+			Event:       event,
 		})
 	}
 
@@ -40,9 +106,46 @@ type GetMessagesOutput struct {
 	Messages []Message `json:"messages"`
 }
 
+// Message の Type は "user" または "system"。system の場合は Event に操作の内容が入る
+//
+// AuthorIsBot は受信 Webhook から Bot として投稿されたメッセージの場合に true
 type Message struct {
-	ID        string `json:"id"`
-	Content   string `json:"content"`
-	Author    string `json:"author"`
-	CreatedAt string `json:"createdAt"`
+	ID          string        `json:"id"`
+	Type        string        `json:"type"`
+	Content     string        `json:"content"`
+	Format      string        `json:"format"`
+	HTML        string        `json:"html"`
+	Author      string        `json:"author"`
+	AuthorIsBot bool          `json:"authorIsBot"`
+	CreatedAt   string        `json:"createdAt"`
+	Pinned      bool          `json:"pinned"`
+	Mentions    []MentionSpan `json:"mentions"`
+	Attachments []Attachment  `json:"attachments"`
+	Event       *SystemEvent  `json:"event,omitempty"`
+}
+
+// SystemEvent の Topic は空の場合にトピックが消去されたことを表す
+type SystemEvent struct {
+	Type             string `json:"type"`
+	MessageID        string `json:"messageId,omitempty"`
+	RoomName         string `json:"roomName,omitempty"`
+	PreviousRoomName string `json:"previousRoomName,omitempty"`
+	Topic            string `json:"topic,omitempty"`
+}
+
+type Attachment struct {
+	ID           string `json:"id"`
+	FileName     string `json:"fileName"`
+	ContentType  string `json:"contentType"`
+	Size         int64  `json:"size"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnailUrl,omitempty"`
+}
+
+// MentionSpan は content 中のメンション箇所 (バイトオフセット、end は含まない)
+type MentionSpan struct {
+	Kind     string `json:"kind"`
+	UserName string `json:"username,omitempty"`
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/quietsato/toy-small-chat/api/internal/applications/message/controller"
 	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/queryprocessor"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
 	"github.com/stretchr/testify/require"
 )
 
@@ -21,6 +22,17 @@ func (m *mockMessageQueryProcessor) GetMessages(roomID string) ([]queryprocessor
 	return nil, nil
 }
 
+type mockMessageRenderer struct {
+	renderHTMLFunc func(content string, format domain.MessageFormat) string
+}
+
+func (m *mockMessageRenderer) RenderHTML(content string, format domain.MessageFormat) string {
+	if m.renderHTMLFunc != nil {
+		return m.renderHTMLFunc(content, format)
+	}
+	return content
+}
+
 func TestGetMessagesController_GetMessages(t *testing.T) {
 	t.Parallel()
 
@@ -29,13 +41,14 @@ func TestGetMessagesController_GetMessages(t *testing.T) {
 
 		mockQP := &mockMessageQueryProcessor{
 			getMessagesFunc: func(roomID string) ([]queryprocessor.Message, error) {
-				require.Equal(t, "room-123", roomID)
+				require.Equal(t, "7d0c5a7e-3f0e-4c53-9a43-3c1f6f0e2b11", roomID)
 				return []queryprocessor.Message{
 					{
 						ID:        "msg-1",
 						Author:    "user-1",
 						Content:   "Hello",
 						CreatedAt: "2024-01-01T00:00:00Z",
+						Pinned:    true,
 					},
 					{
 						ID:        "msg-2",
@@ -47,16 +60,148 @@ func TestGetMessagesController_GetMessages(t *testing.T) {
 			},
 		}
 
-		ctrl := controller.NewGetMessagesController(mockQP)
+		ctrl := controller.NewGetMessagesController(mockQP, &mockMessageRenderer{})
 
 		out, err := ctrl.GetMessages(controller.GetMessagesInput{
-			RoomID: "room-123",
+			RoomID: "7d0c5a7e-3f0e-4c53-9a43-3c1f6f0e2b11",
 		})
 
 		require.NoError(t, err)
 		require.Len(t, out.Messages, 2)
 		require.Equal(t, "msg-1", out.Messages[0].ID)
 		require.Equal(t, "Hello", out.Messages[0].Content)
+		require.True(t, out.Messages[0].Pinned)
+		require.False(t, out.Messages[1].Pinned)
+	})
+
+	t.Run("メンション箇所が変換される", func(t *testing.T) {
+		t.Parallel()
+
+		mockQP := &mockMessageQueryProcessor{
+			getMessagesFunc: func(roomID string) ([]queryprocessor.Message, error) {
+				return []queryprocessor.Message{
+					{
+						ID:        "msg-1",
+						Author:    "user-1",
+						Content:   "@alice @room",
+						CreatedAt: "2024-01-01T00:00:00Z",
+						Mentions: []queryprocessor.MentionSpan{
+							{Kind: "user", UserName: "alice", Start: 0, End: 6},
+							{Kind: "room", Start: 7, End: 12},
+						},
+					},
+				}, nil
+			},
+		}
+
+		ctrl := controller.NewGetMessagesController(mockQP, &mockMessageRenderer{})
+
+		out, err := ctrl.GetMessages(controller.GetMessagesInput{
+			RoomID: "7d0c5a7e-3f0e-4c53-9a43-3c1f6f0e2b11",
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, []controller.MentionSpan{
+			{Kind: "user", UserName: "alice", Start: 0, End: 6},
+			{Kind: "room", Start: 7, End: 12},
+		}, out.Messages[0].Mentions)
+	})
+
+	t.Run("添付ファイルの URL が組み立てられる", func(t *testing.T) {
+		t.Parallel()
+
+		mockQP := &mockMessageQueryProcessor{
+			getMessagesFunc: func(roomID string) ([]queryprocessor.Message, error) {
+				return []queryprocessor.Message{
+					{
+						ID:      "msg-1",
+						Content: "see attached",
+						Attachments: []queryprocessor.Attachment{
+							{ID: "att-1", FileName: "a.png", ContentType: "image/png", Size: 10, HasThumbnail: true},
+							{ID: "att-2", FileName: "b.log", ContentType: "text/plain", Size: 20},
+						},
+					},
+				}, nil
+			},
+		}
+
+		ctrl := controller.NewGetMessagesController(mockQP, &mockMessageRenderer{})
+
+		out, err := ctrl.GetMessages(controller.GetMessagesInput{
+			RoomID: "7d0c5a7e-3f0e-4c53-9a43-3c1f6f0e2b11",
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, []controller.Attachment{
+			{ID: "att-1", FileName: "a.png", ContentType: "image/png", Size: 10, URL: "/rooms/7d0c5a7e-3f0e-4c53-9a43-3c1f6f0e2b11/attachments/att-1", ThumbnailURL: "/rooms/7d0c5a7e-3f0e-4c53-9a43-3c1f6f0e2b11/attachments/att-1/thumbnail"},
+			{ID: "att-2", FileName: "b.log", ContentType: "text/plain", Size: 20, URL: "/rooms/7d0c5a7e-3f0e-4c53-9a43-3c1f6f0e2b11/attachments/att-2"},
+		}, out.Messages[0].Attachments)
+	})
+
+	t.Run("本文のフォーマットに応じて HTML が生成される", func(t *testing.T) {
+		t.Parallel()
+
+		mockQP := &mockMessageQueryProcessor{
+			getMessagesFunc: func(roomID string) ([]queryprocessor.Message, error) {
+				return []queryprocessor.Message{
+					{ID: "msg-1", Content: "**hi**", Format: "markdown"},
+					{ID: "msg-2", Content: "plain", Format: "plain"},
+					{ID: "msg-3", Content: "legacy", Format: "unknown"},
+				}, nil
+			},
+		}
+		mockRenderer := &mockMessageRenderer{
+			renderHTMLFunc: func(content string, format domain.MessageFormat) string {
+				return format.String() + ":" + content
+			},
+		}
+
+		ctrl := controller.NewGetMessagesController(mockQP, mockRenderer)
+
+		out, err := ctrl.GetMessages(controller.GetMessagesInput{
+			RoomID: "7d0c5a7e-3f0e-4c53-9a43-3c1f6f0e2b11",
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, "markdown", out.Messages[0].Format)
+		require.Equal(t, "markdown:**hi**", out.Messages[0].HTML)
+		require.Equal(t, "plain", out.Messages[1].Format)
+		require.Equal(t, "plain:plain", out.Messages[1].HTML)
+		require.Equal(t, "plain", out.Messages[2].Format)
+		require.Equal(t, "plain:legacy", out.Messages[2].HTML)
+	})
+
+	t.Run("システムメッセージは type と event が設定される", func(t *testing.T) {
+		t.Parallel()
+
+		mockQP := &mockMessageQueryProcessor{
+			getMessagesFunc: func(roomID string) ([]queryprocessor.Message, error) {
+				return []queryprocessor.Message{
+					{ID: "msg-1", Kind: "user", Content: "hi", Format: "markdown"},
+					{
+						ID:      "msg-2",
+						Kind:    "system",
+						Author:  "alice",
+						Content: "pinned a message",
+						Format:  "markdown",
+						Event:   &queryprocessor.SystemEvent{Type: "message_pinned", MessageID: "msg-1"},
+					},
+				}, nil
+			},
+		}
+
+		ctrl := controller.NewGetMessagesController(mockQP, &mockMessageRenderer{})
+
+		out, err := ctrl.GetMessages(controller.GetMessagesInput{
+			RoomID: "7d0c5a7e-3f0e-4c53-9a43-3c1f6f0e2b11",
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, "user", out.Messages[0].Type)
+		require.Nil(t, out.Messages[0].Event)
+		require.Equal(t, "system", out.Messages[1].Type)
+		require.Equal(t, "plain", out.Messages[1].Format)
+		require.Equal(t, &controller.SystemEvent{Type: "message_pinned", MessageID: "msg-1"}, out.Messages[1].Event)
 	})
 
 	t.Run("空のメッセージリスト", func(t *testing.T) {
@@ -68,16 +213,28 @@ func TestGetMessagesController_GetMessages(t *testing.T) {
 			},
 		}
 
-		ctrl := controller.NewGetMessagesController(mockQP)
+		ctrl := controller.NewGetMessagesController(mockQP, &mockMessageRenderer{})
 
 		out, err := ctrl.GetMessages(controller.GetMessagesInput{
-			RoomID: "room-123",
+			RoomID: "7d0c5a7e-3f0e-4c53-9a43-3c1f6f0e2b11",
 		})
 
 		require.NoError(t, err)
 		require.Empty(t, out.Messages)
 	})
 
+	t.Run("不正なルーム ID の場合は ErrRoomNotFound を返す", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := controller.NewGetMessagesController(&mockMessageQueryProcessor{}, &mockMessageRenderer{})
+
+		_, err := ctrl.GetMessages(controller.GetMessagesInput{
+			RoomID: "not-a-uuid",
+		})
+
+		require.ErrorIs(t, err, queryprocessor.ErrRoomNotFound)
+	})
+
 	t.Run("クエリプロセッサエラー時にエラーを返す", func(t *testing.T) {
 		t.Parallel()
 
@@ -87,10 +244,10 @@ func TestGetMessagesController_GetMessages(t *testing.T) {
 			},
 		}
 
-		ctrl := controller.NewGetMessagesController(mockQP)
+		ctrl := controller.NewGetMessagesController(mockQP, &mockMessageRenderer{})
 
 		_, err := ctrl.GetMessages(controller.GetMessagesInput{
-			RoomID: "room-123",
+			RoomID: "7d0c5a7e-3f0e-4c53-9a43-3c1f6f0e2b11",
 		})
 
 		require.Error(t, err)
@@ -104,7 +261,7 @@ func TestNewGetMessagesController(t *testing.T) {
 		t.Parallel()
 		mockQP := &mockMessageQueryProcessor{}
 
-		ctrl := controller.NewGetMessagesController(mockQP)
+		ctrl := controller.NewGetMessagesController(mockQP, &mockMessageRenderer{})
 
 		require.NotNil(t, ctrl)
 	})
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/queryprocessor"
+)
+
+type GetSlashCommandsInput struct {
+	RoomID string
+}
+
+type GetSlashCommandsOutput struct {
+	Commands []SlashCommand `json:"commands"`
+}
+
+// SlashCommand は送信先の URL と共有鍵を含まない
+type SlashCommand struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	BotName     string `json:"botName"`
+	CreatedBy   string `json:"createdBy"`
+	CreatedAt   string `json:"createdAt"`
+}
+
+type GetSlashCommandsController struct {
+	query queryprocessor.SlashCommandQueryProcessor
+}
+
+func NewGetSlashCommandsController(query queryprocessor.SlashCommandQueryProcessor) *GetSlashCommandsController {
+	return &GetSlashCommandsController{query}
+}
+
+func (c *GetSlashCommandsController) GetSlashCommands(ctx context.Context, inp GetSlashCommandsInput) (GetSlashCommandsOutput, error) {
+	res, err := c.query.GetSlashCommands(ctx, inp.RoomID)
+	if err != nil {
+		return GetSlashCommandsOutput{}, fmt.Errorf("failed to get slash commands: %w", err)
+	}
+
+	commands := make([]SlashCommand, len(res))
+	for i, cmd := range res {
+		commands[i] = SlashCommand{
+			ID:          cmd.ID,
+			Name:        cmd.Name,
+			Description: cmd.Description,
+			BotName:     cmd.BotName,
+			CreatedBy:   cmd.CreatedBy,
+			CreatedAt:   cmd.CreatedAt,
+		}
+	}
+	return GetSlashCommandsOutput{Commands: commands}, nil
+}
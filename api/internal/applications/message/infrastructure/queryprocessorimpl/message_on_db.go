@@ -2,11 +2,16 @@ package queryprocessorimpl
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/queryprocessor"
 	"github.com/quietsato/toy-small-chat/api/internal/db"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
 )
 
 type MessageQueryProcessorOnDB struct {
@@ -19,13 +24,56 @@ func NewMessageQueryProcessorOnDB(pool *pgxpool.Pool) *MessageQueryProcessorOnDB
 	}
 }
 
+// GetMessages implements queryprocessor.MessageQueryProcessor.
+//
+// 削除したルームは復元できる期間中も存在しないものとして扱う
 func (q *MessageQueryProcessorOnDB) GetMessages(roomID string) ([]queryprocessor.Message, error) {
 	ctx := context.Background()
 
-	dbMessages, err := q.queries.GetMessagesByRoomID(ctx, uuid.MustParse(roomID))
+	id, err := uuid.Parse(roomID)
+	if err != nil {
+		return nil, queryprocessor.ErrRoomNotFound
+	}
+	if _, err := q.queries.GetRoomOwner(ctx, id); errors.Is(err, pgx.ErrNoRows) {
+		return nil, queryprocessor.ErrRoomNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get room: %w", err)
+	}
+
+	dbMessages, err := q.queries.GetMessagesByRoomID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	dbSpans, err := q.queries.GetMentionSpansByRoomID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	spans := make(map[uuid.UUID][]queryprocessor.MentionSpan)
+	for _, s := range dbSpans {
+		spans[s.MessageID] = append(spans[s.MessageID], queryprocessor.MentionSpan{
+			Kind:     s.Kind,
+			UserName: s.Username.String,
+			Start:    int(s.StartOffset),
+			End:      int(s.EndOffset),
+		})
+	}
+
+	dbAttachments, err := q.queries.GetAttachmentsByRoomID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	attachments := make(map[uuid.UUID][]queryprocessor.Attachment)
+	for _, a := range dbAttachments {
+		messageID := uuid.UUID(a.MessageID.Bytes)
+		attachments[messageID] = append(attachments[messageID], queryprocessor.Attachment{
+			ID:           a.ID.String(),
+			FileName:     a.FileName,
+			ContentType:  a.ContentType,
+			Size:         a.Size,
+			HasThumbnail: a.ThumbnailKey.Valid,
+		})
+	}
 
 	messages := make([]queryprocessor.Message, 0, len(dbMessages))
 	for _, dbMsg := range dbMessages {
@@ -34,15 +82,49 @@ func (q *MessageQueryProcessorOnDB) GetMessages(roomID string) ([]queryprocessor
 			createdAtStr = dbMsg.CreatedAt.Time.Format("2006-01-02T15:04:05Z07:00")
 		}
 
+		var event *queryprocessor.SystemEvent
+		if dbMsg.Kind == string(domain.MessageKindSystem) {
+			event, err = toSystemEvent(dbMsg.Event)
+			if err != nil {
+				// 不明なイベントは要約の本文だけを返す
+				slog.WarnContext(ctx, "failed to parse system event", slog.String("messageID", dbMsg.MessageID.String()), slog.Any("err", err))
+			}
+		}
+
 		messages = append(messages, queryprocessor.Message{
-			ID:        dbMsg.MessageID.String(),
-			Author:    dbMsg.AuthorName,
-			Content:   dbMsg.Content,
-			CreatedAt: createdAtStr,
+			ID:          dbMsg.MessageID.String(),
+			Kind:        dbMsg.Kind,
+			Author:      dbMsg.AuthorName,
+			AuthorIsBot: dbMsg.AuthorIsBot,
+			Content:     dbMsg.Content,
+			Format:      dbMsg.Format,
+			Pinned:      dbMsg.Pinned,
+			CreatedAt:   createdAtStr,
+			Mentions:    spans[dbMsg.MessageID],
+			Attachments: attachments[dbMsg.MessageID],
+			Event:       event,
 		})
 	}
 
 	return messages, nil
 }
 
+func toSystemEvent(b []byte) (*queryprocessor.SystemEvent, error) {
+	e, err := domain.ParseSystemEvent(b)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &queryprocessor.SystemEvent{
+		Type:             string(e.Type()),
+		RoomName:         e.RoomName().String(),
+		PreviousRoomName: e.PreviousRoomName().String(),
+		Topic:            e.Topic().String(),
+	}
+	if e.MessageID() != (domain.MessageID{}) {
+		event.MessageID = e.MessageID().String()
+	}
+	return event, nil
+}
+
 var _ queryprocessor.MessageQueryProcessor = (*MessageQueryProcessorOnDB)(nil)
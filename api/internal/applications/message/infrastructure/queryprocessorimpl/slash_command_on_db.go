@@ -0,0 +1,58 @@
+package queryprocessorimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/queryprocessor"
+	"github.com/quietsato/toy-small-chat/api/internal/db"
+)
+
+type SlashCommandQueryProcessorOnDB struct {
+	queries *db.Queries
+}
+
+func NewSlashCommandQueryProcessorOnDB(pool *pgxpool.Pool) *SlashCommandQueryProcessorOnDB {
+	return &SlashCommandQueryProcessorOnDB{
+		queries: db.New(pool),
+	}
+}
+
+// GetSlashCommands implements queryprocessor.SlashCommandQueryProcessor.
+func (q *SlashCommandQueryProcessorOnDB) GetSlashCommands(ctx context.Context, roomID string) ([]queryprocessor.SlashCommandDTO, error) {
+	id, err := uuid.Parse(roomID)
+	if err != nil {
+		return nil, queryprocessor.ErrRoomNotFound
+	}
+
+	if _, err := q.queries.GetRoomOwner(ctx, id); errors.Is(err, pgx.ErrNoRows) {
+		return nil, queryprocessor.ErrRoomNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get room: %w", err)
+	}
+
+	rows, err := q.queries.GetSlashCommandsByRoomID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get slash commands: %w", err)
+	}
+
+	commands := make([]queryprocessor.SlashCommandDTO, len(rows))
+	for i, row := range rows {
+		commands[i] = queryprocessor.SlashCommandDTO{
+			ID:          row.ID.String(),
+			Name:        row.Name,
+			Description: row.Description,
+			BotName:     row.BotName,
+			CreatedBy:   row.CreatedBy.String(),
+			CreatedAt:   row.CreatedAt.Time.Format(time.RFC3339),
+		}
+	}
+	return commands, nil
+}
+
+var _ queryprocessor.SlashCommandQueryProcessor = new(SlashCommandQueryProcessorOnDB)
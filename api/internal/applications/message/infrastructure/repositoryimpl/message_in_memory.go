@@ -22,15 +22,16 @@ func NewInMemoryMessageRepository(ctx context.Context, msgs *[]Message) *InMemor
 	return &InMemoryMessageRepository{msgs}
 }
 
-func (m *InMemoryMessageRepository) CreateMessage(ctx context.Context, inp repository.CreateMessageInput) error {
+func (m *InMemoryMessageRepository) CreateMessage(ctx context.Context, inp repository.CreateMessageInput) (repository.CreateMessageOutput, error) {
+	id := uuid.NewString()
 	*m.msgs = append(*m.msgs, Message{
-		ID:        uuid.NewString(),
+		ID:        id,
 		Content:   inp.Content,
 		Author:    "sample-user",
 		CreatedAt: "today",
 	})
 
-	return nil
+	return repository.CreateMessageOutput{ID: id}, nil
 }
 
 func (m InMemoryMessageRepository) Get() error {
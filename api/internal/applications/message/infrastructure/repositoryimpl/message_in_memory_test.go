@@ -17,7 +17,7 @@ func TestInMemoryMessageRepository_CreateMessage(t *testing.T) {
 		msgs := make([]repositoryimpl.Message, 0)
 		repo := repositoryimpl.NewInMemoryMessageRepository(t.Context(), &msgs)
 
-		err := repo.CreateMessage(t.Context(), repository.CreateMessageInput{
+		out, err := repo.CreateMessage(t.Context(), repository.CreateMessageInput{
 			AuthorID: "author-123",
 			Content:  "Hello, World!",
 			RoomID:   "room-456",
@@ -27,6 +27,7 @@ func TestInMemoryMessageRepository_CreateMessage(t *testing.T) {
 		require.Len(t, msgs, 1)
 		require.Equal(t, "Hello, World!", msgs[0].Content)
 		require.NotEmpty(t, msgs[0].ID)
+		require.Equal(t, msgs[0].ID, out.ID)
 	})
 
 	t.Run("複数メッセージが追加される", func(t *testing.T) {
@@ -35,12 +36,12 @@ func TestInMemoryMessageRepository_CreateMessage(t *testing.T) {
 		msgs := make([]repositoryimpl.Message, 0)
 		repo := repositoryimpl.NewInMemoryMessageRepository(t.Context(), &msgs)
 
-		err := repo.CreateMessage(t.Context(), repository.CreateMessageInput{
+		_, err := repo.CreateMessage(t.Context(), repository.CreateMessageInput{
 			Content: "Message 1",
 		})
 		require.NoError(t, err)
 
-		err = repo.CreateMessage(t.Context(), repository.CreateMessageInput{
+		_, err = repo.CreateMessage(t.Context(), repository.CreateMessageInput{
 			Content: "Message 2",
 		})
 		require.NoError(t, err)
@@ -2,13 +2,18 @@ package repositoryimpl
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/repository"
 	"github.com/quietsato/toy-small-chat/api/internal/db"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
 )
 
 type MessageRepositoryOnDB struct {
@@ -19,12 +24,28 @@ func NewMessageRepositoryOnDB(pool *pgxpool.Pool) *MessageRepositoryOnDB {
 	return &MessageRepositoryOnDB{pool}
 }
 
-func (r *MessageRepositoryOnDB) CreateMessage(ctx context.Context, inp repository.CreateMessageInput) error {
+func (r *MessageRepositoryOnDB) CreateMessage(ctx context.Context, inp repository.CreateMessageInput) (repository.CreateMessageOutput, error) {
+	authorID, err := uuid.Parse(inp.AuthorID)
+	if err != nil {
+		return repository.CreateMessageOutput{}, fmt.Errorf("failed to parse author id: %w", err)
+	}
+	roomID, err := uuid.Parse(inp.RoomID)
+	if err != nil {
+		return repository.CreateMessageOutput{}, repository.ErrRoomNotFound
+	}
+	attachmentIDs := make([]uuid.UUID, 0, len(inp.AttachmentIDs))
+	for _, id := range inp.AttachmentIDs {
+		attachmentID, err := uuid.Parse(id)
+		if err != nil {
+			return repository.CreateMessageOutput{}, repository.ErrAttachmentNotAvailable
+		}
+		attachmentIDs = append(attachmentIDs, attachmentID)
+	}
 
 	// Begin Transaction
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
-		return err
+		return repository.CreateMessageOutput{}, err
 	}
 	defer func() {
 		if err := tx.Rollback(ctx); err != nil {
@@ -35,16 +56,179 @@ func (r *MessageRepositoryOnDB) CreateMessage(ctx context.Context, inp repositor
 	// Exec
 	queries := db.New(r.pool).WithTx(tx)
 
-	if err := queries.CreateMessage(ctx, db.CreateMessageParams{
-		AuthorID: uuid.MustParse(inp.AuthorID),
+	// アーカイブと投稿が競合しないよう、最終アクティビティの更新に先立ってルームの行をロックしてから確認する
+	archivedAt, err := queries.GetRoomArchivedAt(ctx, roomID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.CreateMessageOutput{}, repository.ErrRoomNotFound
+	}
+	if err != nil {
+		return repository.CreateMessageOutput{}, fmt.Errorf("failed to get room: %w", err)
+	}
+	if archivedAt.Valid {
+		return repository.CreateMessageOutput{}, repository.ErrRoomArchived
+	}
+
+	messageID, err := queries.CreateMessage(ctx, db.CreateMessageParams{
+		AuthorID: authorID,
 		Content:  inp.Content,
-		RoomID:   uuid.MustParse(inp.RoomID),
+		Format:   inp.Format,
+		RoomID:   roomID,
+	})
+	if err != nil {
+		return repository.CreateMessageOutput{}, fmt.Errorf("failed to create message: %w", err)
+	}
+
+	if err := queries.UpdateRoomLastActivity(ctx, db.UpdateRoomLastActivityParams{
+		LastMessageID: pgtype.UUID{Bytes: messageID, Valid: true},
+		ID:            roomID,
 	}); err != nil {
-		return fmt.Errorf("failed to create message: %w", err)
+		return repository.CreateMessageOutput{}, fmt.Errorf("failed to update room last activity: %w", err)
+	}
+
+	if err := createMentions(ctx, queries, messageID, roomID, authorID, inp.Mentions); err != nil {
+		return repository.CreateMessageOutput{}, err
+	}
+
+	if len(attachmentIDs) > 0 {
+		attached, err := queries.AttachToMessage(ctx, db.AttachToMessageParams{
+			MessageID:     pgtype.UUID{Bytes: messageID, Valid: true},
+			AttachmentIds: attachmentIDs,
+			RoomID:        roomID,
+			UploadedBy:    authorID,
+		})
+		if err != nil {
+			return repository.CreateMessageOutput{}, fmt.Errorf("failed to attach to message: %w", err)
+		}
+		// 一部でも添付できなければメッセージごと作成しない
+		if attached != int64(len(attachmentIDs)) {
+			return repository.CreateMessageOutput{}, repository.ErrAttachmentNotAvailable
+		}
+	}
+
+	if inp.ScheduledMessageID != "" {
+		scheduledMessageID, err := uuid.Parse(inp.ScheduledMessageID)
+		if err != nil {
+			return repository.CreateMessageOutput{}, repository.ErrScheduledMessageNotClaimed
+		}
+		// メッセージと同じトランザクションで送信済みにするため、取り出し直された予約メッセージを二重に投稿しない
+		completed, err := queries.CompleteScheduledMessage(ctx, db.CompleteScheduledMessageParams{
+			MessageID: pgtype.UUID{Bytes: messageID, Valid: true},
+			ID:        scheduledMessageID,
+			Attempts:  int32(inp.ScheduledMessageAttempts),
+		})
+		if err != nil {
+			return repository.CreateMessageOutput{}, fmt.Errorf("failed to complete scheduled message: %w", err)
+		}
+		if completed == 0 {
+			return repository.CreateMessageOutput{}, repository.ErrScheduledMessageNotClaimed
+		}
 	}
 
 	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit: %w", err)
+		return repository.CreateMessageOutput{}, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return repository.CreateMessageOutput{ID: messageID.String()}, nil
+}
+
+// createMentions はメンション箇所と通知対象アカウントを保存する
+//
+// 存在しないユーザー名へのメンションは無視し、投稿者自身と通知設定で受け取らないアカウントは通知対象から除く
+func createMentions(ctx context.Context, queries *db.Queries, messageID, roomID, authorID uuid.UUID, mentions []repository.MentionInput) error {
+	if len(mentions) == 0 {
+		return nil
+	}
+
+	userNames := make([]string, 0, len(mentions))
+	hasRoomMention := false
+	for _, m := range mentions {
+		if m.Kind == string(domain.MentionKindRoom) {
+			hasRoomMention = true
+			continue
+		}
+		userNames = append(userNames, m.UserName)
+	}
+
+	accounts, err := queries.GetAccountsByUsernames(ctx, userNames)
+	if err != nil {
+		return fmt.Errorf("failed to resolve mentioned accounts: %w", err)
+	}
+	accountIDs := make(map[string]uuid.UUID, len(accounts))
+	for _, a := range accounts {
+		accountIDs[a.Username] = a.ID
+	}
+
+	// 同じアカウントが @username と @room の両方で対象になる場合は @username として扱う
+	recipients := make(map[uuid.UUID]domain.MentionKind, len(accounts))
+	for _, m := range mentions {
+		params := db.CreateMessageMentionParams{
+			MessageID:   messageID,
+			Kind:        m.Kind,
+			StartOffset: int32(m.Start),
+			EndOffset:   int32(m.End),
+		}
+		if m.Kind == string(domain.MentionKindUser) {
+			id, ok := accountIDs[m.UserName]
+			if !ok {
+				continue
+			}
+			params.AccountID = pgtype.UUID{Bytes: id, Valid: true}
+			recipients[id] = domain.MentionKindUser
+		}
+		if err := queries.CreateMessageMention(ctx, params); err != nil {
+			return fmt.Errorf("failed to create message mention: %w", err)
+		}
+	}
+
+	if hasRoomMention {
+		members, err := queries.GetRoomMemberIDs(ctx, roomID)
+		if err != nil {
+			return fmt.Errorf("failed to get room members: %w", err)
+		}
+		for _, id := range members {
+			if _, ok := recipients[id]; !ok {
+				recipients[id] = domain.MentionKindRoom
+			}
+		}
+	}
+	delete(recipients, authorID)
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(recipients))
+	for id := range recipients {
+		ids = append(ids, id)
+	}
+	rows, err := queries.GetNotificationSettingsByAccountIDs(ctx, db.GetNotificationSettingsByAccountIDsParams{
+		RoomID:     roomID,
+		AccountIds: ids,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get notification settings: %w", err)
+	}
+	settings := make(map[uuid.UUID]domain.NotificationSettings, len(rows))
+	for _, row := range rows {
+		// 保存済みの通知レベルは検証を通っているため変換に失敗しない
+		level, _ := domain.NewNotificationLevel(row.Level)
+		settings[row.AccountID] = domain.NewNotificationSettings(level, row.MutedUntil.Time)
+	}
+
+	now := time.Now()
+	for id, kind := range recipients {
+		s, ok := settings[id]
+		if !ok {
+			s = domain.DefaultNotificationSettings
+		}
+		if !s.ShouldNotify(kind, now) {
+			continue
+		}
+		if err := queries.CreateMention(ctx, db.CreateMentionParams{
+			MessageID: messageID,
+			AccountID: id,
+		}); err != nil {
+			return fmt.Errorf("failed to create mention: %w", err)
+		}
 	}
 
 	return nil
@@ -0,0 +1,225 @@
+package repositoryimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/db"
+)
+
+// uniqueViolation は一意制約違反を表す PostgreSQL のエラーコード
+const uniqueViolation = "23505"
+
+type SlashCommandRepositoryOnDB struct {
+	pool *pgxpool.Pool
+}
+
+func NewSlashCommandRepositoryOnDB(pool *pgxpool.Pool) *SlashCommandRepositoryOnDB {
+	return &SlashCommandRepositoryOnDB{pool}
+}
+
+// CreateSlashCommand implements repository.SlashCommandRepository.
+//
+// 同じ名前の Bot アカウントがあり、その Bot のコマンドをすべて同じアカウントが登録していれば再利用するため、
+// 1 つの Bot が複数のコマンドやルームを受け持てる
+func (r *SlashCommandRepositoryOnDB) CreateSlashCommand(ctx context.Context, inp repository.CreateSlashCommandInput) (repository.CreateSlashCommandOutput, error) {
+	roomID, err := uuid.Parse(inp.RoomID)
+	if err != nil {
+		return repository.CreateSlashCommandOutput{}, repository.ErrRoomNotFound
+	}
+	accountID := uuid.MustParse(inp.AccountID)
+
+	var out repository.CreateSlashCommandOutput
+	err = r.withOwnerLock(ctx, roomID, accountID, func(queries *db.Queries) error {
+		// ルームの行をロックしているため、件数と名前の確認と追加の間に他の登録は割り込まない
+		count, err := queries.CountSlashCommandsByRoomID(ctx, roomID)
+		if err != nil {
+			return fmt.Errorf("failed to count slash commands: %w", err)
+		}
+		if count >= int64(inp.MaxCommands) {
+			return repository.ErrTooManySlashCommands
+		}
+
+		exists, err := queries.SlashCommandExistsInRoom(ctx, db.SlashCommandExistsInRoomParams{
+			RoomID: roomID,
+			Name:   inp.Name,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to check slash command: %w", err)
+		}
+		if exists {
+			return repository.ErrSlashCommandExists
+		}
+
+		botID, err := findOrCreateBotAccount(ctx, queries, inp.BotName, accountID)
+		if err != nil {
+			return err
+		}
+
+		id, err := queries.CreateSlashCommand(ctx, db.CreateSlashCommandParams{
+			RoomID:       roomID,
+			Name:         inp.Name,
+			Description:  inp.Description,
+			CallbackUrl:  inp.CallbackURL,
+			Secret:       inp.Secret,
+			BotAccountID: botID,
+			CreatedBy:    accountID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create slash command: %w", err)
+		}
+		out.ID = id.String()
+		return nil
+	})
+	return out, err
+}
+
+// findOrCreateBotAccount は人間のアカウントと同じ名前の Bot を作らない
+//
+// 既存の Bot は ownerID が登録したコマンドだけが使っている場合に再利用する。
+// 他のアカウントのコマンドや受信 Webhook の Bot を再利用すると、その Bot になりすまして投稿できてしまう
+func findOrCreateBotAccount(ctx context.Context, queries *db.Queries, name string, ownerID uuid.UUID) (uuid.UUID, error) {
+	account, err := queries.GetAccountKindByUsername(ctx, name)
+	if err == nil {
+		if account.Kind != "bot" {
+			return uuid.Nil, repository.ErrBotNameTaken
+		}
+		owned, err := queries.SlashCommandBotOwnedBy(ctx, db.SlashCommandBotOwnedByParams{
+			BotAccountID: account.ID,
+			CreatedBy:    ownerID,
+		})
+		if err != nil {
+			return uuid.Nil, fmt.Errorf("failed to check bot owner: %w", err)
+		}
+		if !owned {
+			return uuid.Nil, repository.ErrBotNameTaken
+		}
+		return account.ID, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	id, err := queries.CreateBotAccount(ctx, name)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		// 確認の後に同じ名前のアカウントが作成された
+		return uuid.Nil, repository.ErrBotNameTaken
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to create bot account: %w", err)
+	}
+	return id, nil
+}
+
+// DeleteSlashCommand implements repository.SlashCommandRepository.
+func (r *SlashCommandRepositoryOnDB) DeleteSlashCommand(ctx context.Context, inp repository.DeleteSlashCommandInput) error {
+	roomID, err := uuid.Parse(inp.RoomID)
+	if err != nil {
+		return repository.ErrRoomNotFound
+	}
+	commandID, err := uuid.Parse(inp.CommandID)
+	if err != nil {
+		return repository.ErrSlashCommandNotFound
+	}
+	accountID := uuid.MustParse(inp.AccountID)
+
+	return r.withOwnerLock(ctx, roomID, accountID, func(queries *db.Queries) error {
+		deleted, err := queries.DeleteSlashCommand(ctx, db.DeleteSlashCommandParams{
+			ID:     commandID,
+			RoomID: roomID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete slash command: %w", err)
+		}
+		if deleted == 0 {
+			return repository.ErrSlashCommandNotFound
+		}
+		return nil
+	})
+}
+
+// FindSlashCommand implements repository.SlashCommandRepository.
+func (r *SlashCommandRepositoryOnDB) FindSlashCommand(ctx context.Context, inp repository.FindSlashCommandInput) (repository.FindSlashCommandOutput, error) {
+	roomID, err := uuid.Parse(inp.RoomID)
+	if err != nil {
+		return repository.FindSlashCommandOutput{}, repository.ErrSlashCommandNotFound
+	}
+
+	queries := db.New(r.pool)
+	row, err := queries.GetSlashCommandByName(ctx, db.GetSlashCommandByNameParams{
+		RoomID: roomID,
+		Name:   inp.Name,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.FindSlashCommandOutput{}, repository.ErrSlashCommandNotFound
+	}
+	if err != nil {
+		return repository.FindSlashCommandOutput{}, fmt.Errorf("failed to get slash command: %w", err)
+	}
+	return repository.FindSlashCommandOutput{
+		ID:           row.ID.String(),
+		CallbackURL:  row.CallbackUrl,
+		Secret:       row.Secret,
+		BotAccountID: row.BotAccountID.String(),
+	}, nil
+}
+
+// GetAccountName implements repository.SlashCommandRepository.
+func (r *SlashCommandRepositoryOnDB) GetAccountName(ctx context.Context, accountID string) (string, error) {
+	id, err := uuid.Parse(accountID)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse account id: %w", err)
+	}
+
+	queries := db.New(r.pool)
+	account, err := queries.GetAccountByID(ctx, id)
+	if err != nil {
+		return "", fmt.Errorf("failed to get account: %w", err)
+	}
+	return account.Username, nil
+}
+
+// withOwnerLock はルームの行をロックし、操作者がルームの作成者であることを確認してから fn を実行する
+func (r *SlashCommandRepositoryOnDB) withOwnerLock(ctx context.Context, roomID, accountID uuid.UUID, fn func(queries *db.Queries) error) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rollback", slog.Any("err", err))
+		}
+	}()
+
+	queries := db.New(r.pool).WithTx(tx)
+
+	room, err := queries.GetRoomForUpdate(ctx, roomID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.ErrRoomNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get room: %w", err)
+	}
+	if room.CreatedBy != accountID {
+		return repository.ErrNotRoomOwner
+	}
+
+	if err := fn(queries); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
+
+var _ repository.SlashCommandRepository = new(SlashCommandRepositoryOnDB)
@@ -0,0 +1,35 @@
+package repositoryimpl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/db"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+)
+
+// CreateSystemMessage はルームへの操作をシステムメッセージとしてタイムラインに記録する
+//
+// 操作と記録がずれないよう、ルームやピンのリポジトリが操作と同じトランザクションの queries を渡して呼ぶ
+func CreateSystemMessage(ctx context.Context, queries *db.Queries, roomID, actorID uuid.UUID, event domain.SystemEvent) error {
+	if event.Type() == "" {
+		return domain.ErrInvalidSystemEvent
+	}
+
+	b, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	if _, err := queries.CreateSystemMessage(ctx, db.CreateSystemMessageParams{
+		RoomID:   roomID,
+		AuthorID: actorID,
+		Content:  event.Summary(),
+		Event:    b,
+	}); err != nil {
+		return fmt.Errorf("failed to create system message: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package serviceimpl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/service"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/outbound"
+)
+
+// 送信するリクエストのヘッダ。送信 Webhook と同じ形式で署名する
+const (
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// maxSlashCommandResponseSize は読み込む応答本文の上限
+const maxSlashCommandResponseSize = 64 << 10
+
+// HTTPSlashCommandCaller は HMAC-SHA256 で署名した JSON を POST し、JSON の応答を受け取る
+type HTTPSlashCommandCaller struct {
+	client *http.Client
+}
+
+// NewHTTPSlashCommandCaller の timeout は 1 回の呼び出しにかける時間の上限
+//
+// 実行者は応答を待っているため、送信 Webhook と違って再送はしない。
+// 送信 Webhook と同じく policy が許可しないアドレスには接続せず、リダイレクトにも従わない
+func NewHTTPSlashCommandCaller(timeout time.Duration, policy domain.WebhookNetworkPolicy) *HTTPSlashCommandCaller {
+	return &HTTPSlashCommandCaller{
+		client: outbound.NewClient(timeout, policy),
+	}
+}
+
+// slashCommandResponse は送信先が返す応答の本文
+type slashCommandResponse struct {
+	Text         string `json:"text"`
+	Format       string `json:"format"`
+	ResponseType string `json:"responseType"`
+}
+
+// Call implements service.SlashCommandCaller.
+func (c *HTTPSlashCommandCaller) Call(ctx context.Context, inp service.CallSlashCommandInput) (service.CallSlashCommandOutput, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, inp.URL, bytes.NewReader(inp.Payload))
+	if err != nil {
+		return service.CallSlashCommandOutput{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	now := time.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "toy-small-chat-slash-command")
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(HeaderSignature, domain.SignWebhookPayload(inp.Secret, now, inp.Payload))
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return service.CallSlashCommandOutput{}, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, maxSlashCommandResponseSize))
+		return service.CallSlashCommandOutput{}, fmt.Errorf("unexpected status: %d", res.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxSlashCommandResponseSize))
+	if err != nil {
+		return service.CallSlashCommandOutput{}, fmt.Errorf("failed to read response: %w", err)
+	}
+	var out slashCommandResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		return service.CallSlashCommandOutput{}, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return service.CallSlashCommandOutput{
+		Text:         out.Text,
+		Format:       out.Format,
+		ResponseType: out.ResponseType,
+	}, nil
+}
+
+var _ service.SlashCommandCaller = new(HTTPSlashCommandCaller)
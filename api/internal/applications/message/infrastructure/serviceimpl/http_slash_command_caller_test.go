@@ -0,0 +1,113 @@
+package serviceimpl_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/infrastructure/serviceimpl"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/service"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/outbound"
+	"github.com/stretchr/testify/require"
+)
+
+// allowLoopback は httptest のサーバーを呼び出すために内部のネットワークへの送信を許可する
+var allowLoopback = domain.NewWebhookNetworkPolicy(true)
+
+func TestHTTPSlashCommandCaller_Call(t *testing.T) {
+	t.Parallel()
+
+	t.Run("署名付きの本文を送信し、応答を返す", func(t *testing.T) {
+		t.Parallel()
+
+		payload := []byte(`{"command":"/deploy"}`)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.Equal(t, payload, body)
+
+			sec, err := strconv.ParseInt(r.Header.Get(serviceimpl.HeaderTimestamp), 10, 64)
+			require.NoError(t, err)
+			require.Equal(t,
+				domain.SignWebhookPayload("0123456789abcdef", time.Unix(sec, 0), body),
+				r.Header.Get(serviceimpl.HeaderSignature),
+			)
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"text":"all green","format":"markdown","responseType":"public"}`))
+		}))
+		t.Cleanup(srv.Close)
+
+		caller := serviceimpl.NewHTTPSlashCommandCaller(time.Second, allowLoopback)
+		res, err := caller.Call(t.Context(), service.CallSlashCommandInput{
+			URL:     srv.URL,
+			Secret:  "0123456789abcdef",
+			Payload: payload,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, service.CallSlashCommandOutput{
+			Text:         "all green",
+			Format:       "markdown",
+			ResponseType: "public",
+		}, res)
+	})
+
+	t.Run("失敗した応答はエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name    string
+			handler http.HandlerFunc
+		}{
+			{"server error", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			}},
+			{"redirect", func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, "/elsewhere", http.StatusFound)
+			}},
+			{"not json", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("ok"))
+			}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				srv := httptest.NewServer(tt.handler)
+				t.Cleanup(srv.Close)
+
+				caller := serviceimpl.NewHTTPSlashCommandCaller(time.Second, allowLoopback)
+				_, err := caller.Call(t.Context(), service.CallSlashCommandInput{
+					URL:     srv.URL,
+					Secret:  "0123456789abcdef",
+					Payload: []byte(`{}`),
+				})
+				require.Error(t, err)
+			})
+		}
+	})
+
+	t.Run("内部のネットワークは呼び出さない", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("request should not be sent")
+		}))
+		t.Cleanup(srv.Close)
+
+		caller := serviceimpl.NewHTTPSlashCommandCaller(time.Second, domain.NewWebhookNetworkPolicy(false))
+		_, err := caller.Call(t.Context(), service.CallSlashCommandInput{
+			URL:     srv.URL,
+			Secret:  "0123456789abcdef",
+			Payload: []byte(`{}`),
+		})
+
+		require.ErrorIs(t, err, outbound.ErrAddressNotAllowed)
+	})
+}
@@ -0,0 +1,312 @@
+package serviceimpl
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/service"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+)
+
+// MarkdownRenderer は CommonMark のサブセットを HTML に変換する
+//
+// 対応するのはコードブロック、インラインコード、リンク、強調、リストのみ。
+// 入力中の文字列はすべてエスケープして出力するため、生の HTML は表示されない。
+// チャット用途のため、段落内の改行は <br> として扱う
+type MarkdownRenderer struct{}
+
+func NewMarkdownRenderer() *MarkdownRenderer {
+	return &MarkdownRenderer{}
+}
+
+// RenderHTML implements service.MessageRenderer.
+func (m *MarkdownRenderer) RenderHTML(content string, format domain.MessageFormat) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	if format != domain.MessageFormatMarkdown {
+		return strings.ReplaceAll(html.EscapeString(content), "\n", "<br>\n")
+	}
+	return renderBlocks(strings.Split(content, "\n"))
+}
+
+var (
+	fenceRegExp       = regexp.MustCompile("^ {0,3}(```+|~~~+)\\s*([^`\\s]*)")
+	listItemRegExp    = regexp.MustCompile(`^ {0,3}([-*+]|(\d{1,9})[.)])\s+(.*)$`)
+	languageRegExp    = regexp.MustCompile(`^[a-zA-Z0-9_+-]+$`)
+	allowedLinkScheme = map[string]bool{"http": true, "https": true, "mailto": true}
+)
+
+func renderBlocks(lines []string) string {
+	var b strings.Builder
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+
+		case fenceRegExp.MatchString(line):
+			i = renderCodeBlock(&b, lines, i)
+
+		case listItemRegExp.MatchString(line):
+			i = renderList(&b, lines, i)
+
+		default:
+			i = renderParagraph(&b, lines, i)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func renderCodeBlock(b *strings.Builder, lines []string, i int) int {
+	m := fenceRegExp.FindStringSubmatch(lines[i])
+	fence, lang := m[1], m[2]
+
+	body := make([]string, 0)
+	i++
+	for ; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+			i++
+			break
+		}
+		body = append(body, lines[i])
+	}
+
+	b.WriteString("<pre><code")
+	if languageRegExp.MatchString(lang) {
+		b.WriteString(` class="language-`)
+		b.WriteString(lang)
+		b.WriteString(`"`)
+	}
+	b.WriteString(">")
+	for _, l := range body {
+		b.WriteString(html.EscapeString(l))
+		b.WriteString("\n")
+	}
+	b.WriteString("</code></pre>\n")
+	return i
+}
+
+func renderList(b *strings.Builder, lines []string, i int) int {
+	first := listItemRegExp.FindStringSubmatch(lines[i])
+	ordered := first[2] != ""
+	// 同じ種類・同じ記号の項目が続く限り 1 つのリストとする
+	marker := first[1][len(first[1])-1:]
+
+	if ordered {
+		start, _ := strconv.Atoi(first[2])
+		if start != 1 {
+			b.WriteString(`<ol start="` + strconv.Itoa(start) + `">` + "\n")
+		} else {
+			b.WriteString("<ol>\n")
+		}
+	} else {
+		b.WriteString("<ul>\n")
+	}
+
+	items := make([][]string, 0)
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if m := listItemRegExp.FindStringSubmatch(line); m != nil {
+			if (m[2] != "") != ordered || m[1][len(m[1])-1:] != marker {
+				break
+			}
+			items = append(items, []string{m[3]})
+			continue
+		}
+		// インデントされた行は直前の項目の続きとする
+		if strings.HasPrefix(line, "  ") && strings.TrimSpace(line) != "" {
+			items[len(items)-1] = append(items[len(items)-1], strings.TrimSpace(line))
+			continue
+		}
+		break
+	}
+
+	for _, item := range items {
+		b.WriteString("<li>")
+		b.WriteString(renderLines(item))
+		b.WriteString("</li>\n")
+	}
+
+	if ordered {
+		b.WriteString("</ol>\n")
+	} else {
+		b.WriteString("</ul>\n")
+	}
+	return i
+}
+
+func renderParagraph(b *strings.Builder, lines []string, i int) int {
+	para := make([]string, 0)
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" || fenceRegExp.MatchString(line) || listItemRegExp.MatchString(line) {
+			break
+		}
+		para = append(para, line)
+	}
+
+	b.WriteString("<p>")
+	b.WriteString(renderLines(para))
+	b.WriteString("</p>\n")
+	return i
+}
+
+func renderLines(lines []string) string {
+	return strings.ReplaceAll(renderInline(strings.Join(lines, "\n"), true), "\n", "<br>\n")
+}
+
+// renderInline はインライン要素を変換する。allowLinks が false の場合はリンクを入れ子にしない
+func renderInline(s string, allowLinks bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && isASCIIPunct(s[i+1]):
+			b.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+			continue
+
+		case c == '`':
+			if n, ok := renderCodeSpan(&b, s, i); ok {
+				i = n
+				continue
+			}
+
+		case c == '[' && allowLinks:
+			if n, ok := renderLink(&b, s, i); ok {
+				i = n
+				continue
+			}
+
+		case c == '*' || c == '_':
+			if n, ok := renderEmphasis(&b, s, i, allowLinks); ok {
+				i = n
+				continue
+			}
+		}
+
+		// 強調として解釈されなかった区切り文字の連続はまとめて出力する
+		j := i + 1
+		if c == '*' || c == '_' || c == '`' {
+			for j < len(s) && s[j] == c {
+				j++
+			}
+		}
+		b.WriteString(html.EscapeString(s[i:j]))
+		i = j
+	}
+	return b.String()
+}
+
+func renderCodeSpan(b *strings.Builder, s string, i int) (int, bool) {
+	n := runLength(s, i, '`')
+	delim := s[i : i+n]
+	end := strings.Index(s[i+n:], delim)
+	if end < 0 {
+		return 0, false
+	}
+	code := s[i+n : i+n+end]
+	b.WriteString("<code>")
+	b.WriteString(html.EscapeString(code))
+	b.WriteString("</code>")
+	return i + n + end + n, true
+}
+
+func renderLink(b *strings.Builder, s string, i int) (int, bool) {
+	closeText := strings.Index(s[i:], "](")
+	if closeText < 0 {
+		return 0, false
+	}
+	text := s[i+1 : i+closeText]
+	rest := s[i+closeText+2:]
+	closeURL := strings.IndexByte(rest, ')')
+	if closeURL < 0 || strings.ContainsAny(text, "\n") {
+		return 0, false
+	}
+	rawURL := strings.TrimSpace(rest[:closeURL])
+	next := i + closeText + 2 + closeURL + 1
+
+	if !isSafeURL(rawURL) {
+		// 許可しないスキームのリンクはテキストのみ表示する
+		b.WriteString(renderInline(text, false))
+		return next, true
+	}
+
+	b.WriteString(`<a href="`)
+	b.WriteString(html.EscapeString(rawURL))
+	b.WriteString(`" rel="nofollow noopener noreferrer">`)
+	b.WriteString(renderInline(text, false))
+	b.WriteString("</a>")
+	return next, true
+}
+
+func renderEmphasis(b *strings.Builder, s string, i int, allowLinks bool) (int, bool) {
+	c := s[i]
+	n := min(runLength(s, i, c), 2)
+	delim := s[i : i+n]
+
+	// 開始側の直後は空白であってはならず、"_" は単語の途中では使えない
+	if i+n >= len(s) || isSpace(s[i+n]) {
+		return 0, false
+	}
+	if c == '_' && i > 0 && isAlphaNumeric(s[i-1]) {
+		return 0, false
+	}
+
+	for j := i + n + 1; j+n <= len(s); j++ {
+		if s[j:j+n] != delim || isSpace(s[j-1]) {
+			continue
+		}
+		if c == '_' && j+n < len(s) && isAlphaNumeric(s[j+n]) {
+			continue
+		}
+		// 区切り文字の連続の途中で閉じない
+		if j+n < len(s) && s[j+n] == c && n == 1 {
+			j++
+			continue
+		}
+
+		tag := "em"
+		if n == 2 {
+			tag = "strong"
+		}
+		b.WriteString("<" + tag + ">")
+		b.WriteString(renderInline(s[i+n:j], allowLinks))
+		b.WriteString("</" + tag + ">")
+		return j + n, true
+	}
+	return 0, false
+}
+
+func isSafeURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return allowedLinkScheme[strings.ToLower(u.Scheme)]
+}
+
+func runLength(s string, i int, c byte) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == c {
+		n++
+	}
+	return n
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+func isAlphaNumeric(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+func isASCIIPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
+
+var _ service.MessageRenderer = new(MarkdownRenderer)
@@ -0,0 +1,134 @@
+package serviceimpl_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/infrastructure/serviceimpl"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdownRenderer_RenderHTML(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"paragraph", "hello", "<p>hello</p>"},
+		{"line break", "a\nb", "<p>a<br>\nb</p>"},
+		{"multiple paragraphs", "a\n\nb", "<p>a</p>\n<p>b</p>"},
+		{"strong", "**bold** and __bold__", "<p><strong>bold</strong> and <strong>bold</strong></p>"},
+		{"em", "*it* and _it_", "<p><em>it</em> and <em>it</em></p>"},
+		{"nested emphasis", "**a *b* c**", "<p><strong>a <em>b</em> c</strong></p>"},
+		{"intraword underscore", "snake_case_name", "<p>snake_case_name</p>"},
+		{"unclosed emphasis", "2 * 3 = 6", "<p>2 * 3 = 6</p>"},
+		{"code span", "use `go test`", "<p>use <code>go test</code></p>"},
+		{"code span keeps markup", "`**x** <b>`", "<p><code>**x** &lt;b&gt;</code></p>"},
+		{"backslash escape", `\*not em\*`, "<p>*not em*</p>"},
+		{"link", "[site](https://example.com)", `<p><a href="https://example.com" rel="nofollow noopener noreferrer">site</a></p>`},
+		{"mailto link", "[me](mailto:a@example.com)", `<p><a href="mailto:a@example.com" rel="nofollow noopener noreferrer">me</a></p>`},
+		{"emphasis in link", "[**x**](http://example.com)", `<p><a href="http://example.com" rel="nofollow noopener noreferrer"><strong>x</strong></a></p>`},
+		{"unordered list", "- a\n- b", "<ul>\n<li>a</li>\n<li>b</li>\n</ul>"},
+		{"ordered list", "1. a\n2. b", "<ol>\n<li>a</li>\n<li>b</li>\n</ol>"},
+		{"ordered list start", "3. a\n4. b", "<ol start=\"3\">\n<li>a</li>\n<li>b</li>\n</ol>"},
+		{"list continuation", "- a\n  more\n- b", "<ul>\n<li>a<br>\nmore</li>\n<li>b</li>\n</ul>"},
+		{"list after paragraph", "text\n- a", "<p>text</p>\n<ul>\n<li>a</li>\n</ul>"},
+		{"fenced code block", "```go\nfmt.Println(\"<hi>\")\n```", "<pre><code class=\"language-go\">fmt.Println(&#34;&lt;hi&gt;&#34;)\n</code></pre>"},
+		{"tilde fence", "~~~\n**x**\n~~~", "<pre><code>**x**\n</code></pre>"},
+		{"unclosed fence", "```\ncode", "<pre><code>code\n</code></pre>"},
+		{"crlf", "a\r\nb", "<p>a<br>\nb</p>"},
+	}
+
+	r := serviceimpl.NewMarkdownRenderer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.expected, r.RenderHTML(tt.input, domain.MessageFormatMarkdown))
+		})
+	}
+}
+
+func TestMarkdownRenderer_RenderHTML_Plain(t *testing.T) {
+	t.Parallel()
+
+	t.Run("plain はエスケープと改行のみ行う", func(t *testing.T) {
+		t.Parallel()
+
+		r := serviceimpl.NewMarkdownRenderer()
+		got := r.RenderHTML("**x** <b>\n[a](http://e.com)", domain.MessageFormatPlain)
+
+		require.Equal(t, "**x** &lt;b&gt;<br>\n[a](http://e.com)", got)
+	})
+}
+
+// allowedTagRegExp は出力に含まれてよいタグと属性
+var allowedTagRegExp = regexp.MustCompile(
+	`^<(/?(p|br|strong|em|code|pre|ul|li)|/ol|/a|ol( start="\d+")?|code class="language-[a-zA-Z0-9_+-]+"|a href="(https?|mailto):[^"<>]*" rel="nofollow noopener noreferrer")>$`,
+)
+
+var tagRegExp = regexp.MustCompile(`<[^>]*>?`)
+
+func requireSafeHTML(t *testing.T, out string) {
+	t.Helper()
+
+	for _, tag := range tagRegExp.FindAllString(out, -1) {
+		require.Regexp(t, allowedTagRegExp, tag, "unexpected tag in %q", out)
+	}
+}
+
+func TestMarkdownRenderer_RenderHTML_XSS(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"script tag", "<script>alert(1)</script>"},
+		{"img onerror", `<img src=x onerror="alert(1)">`},
+		{"javascript link", "[x](javascript:alert(1))"},
+		{"mixed case javascript link", "[x](JaVaScRiPt:alert(1))"},
+		{"javascript link with whitespace", "[x]( javascript:alert(1) )"},
+		{"entity encoded javascript", "[x](&#106;avascript:alert(1))"},
+		{"percent encoded javascript", "[x](%6aavascript:alert(1))"},
+		{"data url", "[x](data:text/html;base64,PHNjcmlwdD4=)"},
+		{"vbscript", "[x](vbscript:msgbox(1))"},
+		{"attribute breaking quote", `[x](https://e.com/"onmouseover="alert(1))`},
+		{"tag in link text", "[<script>alert(1)</script>](https://e.com)"},
+		{"tag in emphasis", "**<iframe src=x>**"},
+		{"tag in list", "- <svg onload=alert(1)>"},
+		{"code fence language injection", "```\"><script>\nx\n```"},
+		{"nested link", "[[a](https://e.com)](javascript:alert(1))"},
+		{"escaped angle bracket", `\<script>`},
+		{"html comment", "<!-- <script> -->"},
+	}
+
+	r := serviceimpl.NewMarkdownRenderer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			requireSafeHTML(t, r.RenderHTML(tt.input, domain.MessageFormatMarkdown))
+			requireSafeHTML(t, r.RenderHTML(tt.input, domain.MessageFormatPlain))
+		})
+	}
+}
+
+func FuzzMarkdownRenderer_RenderHTML(f *testing.F) {
+	for _, seed := range []string{
+		"**a** _b_ `c`",
+		"[x](https://e.com)",
+		"[x](javascript:alert(1))",
+		"- a\n- b\n\n1. c",
+		"```js\n<script>\n```",
+		"<img src=x onerror=alert(1)>",
+	} {
+		f.Add(seed)
+	}
+
+	r := serviceimpl.NewMarkdownRenderer()
+	f.Fuzz(func(t *testing.T, s string) {
+		requireSafeHTML(t, r.RenderHTML(s, domain.MessageFormatMarkdown))
+	})
+}
@@ -1,20 +1,127 @@
 package usecase
 
-type CreateMessage struct{}
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+)
+
+type CreateMessageUsecase struct {
+	repo     repository.MessageRepository
+	commands SlashCommandDispatcher
+}
 
 type CreateMessageInput struct {
+	RoomID        string
+	AuthorID      string
+	Content       domain.MessageContent
+	Format        domain.MessageFormat
+	AttachmentIDs []domain.AttachmentID
+	// ScheduledMessageID が空でない場合は予約メッセージを投稿し、同じトランザクションで送信済みにする
+	ScheduledMessageID       string
+	ScheduledMessageAttempts int
+}
+
+// CreateMessageOutput はスラッシュコマンドの応答を含めて、実際に投稿した内容を表す
+//
+// Ephemeral が true の場合はメッセージを保存しておらず ID は空。Content は実行者にだけ返す応答
+type CreateMessageOutput struct {
+	ID        string
+	AuthorID  string
+	Content   domain.MessageContent
+	Format    domain.MessageFormat
+	Ephemeral bool
 }
 
-type CreateMessageOutput struct{}
+const maxAttachmentsPerMessage = 10
+
+var (
+	ErrTooManyAttachments = errors.New("too many attachments")
+	// ErrCommandWithAttachments はスラッシュコマンドにファイルを添付しようとした場合に返す
+	ErrCommandWithAttachments = errors.New("slash command with attachments")
+)
 
-func (c *CreateMessageOutput) Error() string {
-	panic("unimplemented")
+// NewCreateMessageUsecase の commands が nil の場合、"/" で始まる本文もそのまま投稿する
+func NewCreateMessageUsecase(repo repository.MessageRepository, commands SlashCommandDispatcher) *CreateMessageUsecase {
+	return &CreateMessageUsecase{repo, commands}
 }
 
-var _ error = new(CreateMessageOutput)
+// Execute は "/" で始まる本文をスラッシュコマンドとして実行し、public な応答だけをメッセージとして保存する
+//
+// "//" で始まる本文は先頭の "/" を 1 つ取り除いて通常のメッセージとして投稿する
+func (u *CreateMessageUsecase) Execute(ctx context.Context, inp CreateMessageInput) (CreateMessageOutput, error) {
+	if len(inp.AttachmentIDs) > maxAttachmentsPerMessage {
+		return CreateMessageOutput{}, ErrTooManyAttachments
+	}
+
+	if u.commands != nil {
+		if escaped, ok := domain.EscapedSlashCommand(inp.Content); ok {
+			inp.Content = escaped
+		} else if cmd, ok := domain.ParseSlashCommand(inp.Content); ok {
+			if len(inp.AttachmentIDs) > 0 {
+				return CreateMessageOutput{}, ErrCommandWithAttachments
+			}
+			res, err := u.commands.Dispatch(ctx, SlashCommandRequest{
+				RoomID:    inp.RoomID,
+				AccountID: inp.AuthorID,
+				Command:   cmd,
+			})
+			if err != nil {
+				return CreateMessageOutput{}, fmt.Errorf("failed to dispatch slash command: %w", err)
+			}
+			if res.Visibility == domain.SlashCommandEphemeral {
+				return CreateMessageOutput{
+					AuthorID:  inp.AuthorID,
+					Content:   res.Content,
+					Format:    res.Format,
+					Ephemeral: true,
+				}, nil
+			}
+			inp.AuthorID = res.AuthorID
+			inp.Content = res.Content
+			inp.Format = res.Format
+		}
+	}
+	attachmentIDs := make([]string, 0, len(inp.AttachmentIDs))
+	for _, id := range inp.AttachmentIDs {
+		if !slices.Contains(attachmentIDs, id.String()) {
+			attachmentIDs = append(attachmentIDs, id.String())
+		}
+	}
+
+	parsed := domain.ParseMentions(inp.Content)
+	mentions := make([]repository.MentionInput, 0, len(parsed))
+	for _, m := range parsed {
+		mentions = append(mentions, repository.MentionInput{
+			Kind:     string(m.Kind()),
+			UserName: m.UserName().String(),
+			Start:    m.Start(),
+			End:      m.End(),
+		})
+	}
 
-type CreateMessageError struct{}
+	out, err := u.repo.CreateMessage(ctx, repository.CreateMessageInput{
+		AuthorID:                 inp.AuthorID,
+		RoomID:                   inp.RoomID,
+		Content:                  inp.Content.String(),
+		Format:                   inp.Format.String(),
+		Mentions:                 mentions,
+		AttachmentIDs:            attachmentIDs,
+		ScheduledMessageID:       inp.ScheduledMessageID,
+		ScheduledMessageAttempts: inp.ScheduledMessageAttempts,
+	})
+	if err != nil {
+		return CreateMessageOutput{}, fmt.Errorf("failed to create message: %w", err)
+	}
 
-func (uc *CreateMessage) Execute(inp CreateMessageInput) (*CreateMessageOutput, error) {
-	return nil, nil
+	return CreateMessageOutput{
+		ID:       out.ID,
+		AuthorID: inp.AuthorID,
+		Content:  inp.Content,
+		Format:   inp.Format,
+	}, nil
 }
@@ -1,22 +1,325 @@
 package usecase_test
 
 import (
+	"context"
+	"errors"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
 	"github.com/stretchr/testify/require"
 )
 
-func TestCreateMessage_Execute(t *testing.T) {
+// Mock implementations
+type mockMessageRepository struct {
+	createMessageFunc func(ctx context.Context, inp repository.CreateMessageInput) error
+	createdMessageID  string
+}
+
+func (m *mockMessageRepository) CreateMessage(ctx context.Context, inp repository.CreateMessageInput) (repository.CreateMessageOutput, error) {
+	if m.createMessageFunc != nil {
+		return repository.CreateMessageOutput{ID: m.createdMessageID}, m.createMessageFunc(ctx, inp)
+	}
+	return repository.CreateMessageOutput{ID: m.createdMessageID}, nil
+}
+
+func (m *mockMessageRepository) Get() error {
+	return nil
+}
+
+type mockSlashCommandDispatcher struct {
+	dispatchFunc func(ctx context.Context, req usecase.SlashCommandRequest) (usecase.SlashCommandResponse, error)
+}
+
+func (m *mockSlashCommandDispatcher) Dispatch(ctx context.Context, req usecase.SlashCommandRequest) (usecase.SlashCommandResponse, error) {
+	if m.dispatchFunc != nil {
+		return m.dispatchFunc(ctx, req)
+	}
+	return usecase.SlashCommandResponse{}, nil
+}
+
+func TestCreateMessageUsecase_Execute(t *testing.T) {
 	t.Parallel()
 
 	t.Run("メッセージ作成が正常に完了する", func(t *testing.T) {
 		t.Parallel()
 
-		uc := &usecase.CreateMessage{}
-		out, err := uc.Execute(usecase.CreateMessageInput{})
+		mockRepo := &mockMessageRepository{
+			createMessageFunc: func(ctx context.Context, inp repository.CreateMessageInput) error {
+				require.Equal(t, "room-456", inp.RoomID)
+				require.Equal(t, "author-123", inp.AuthorID)
+				require.Equal(t, "Hello, World!", inp.Content)
+				require.Empty(t, inp.Mentions)
+				return nil
+			},
+		}
+
+		content, _ := domain.NewMessageContent("Hello, World!")
+		uc := usecase.NewCreateMessageUsecase(mockRepo, nil)
+		_, err := uc.Execute(t.Context(), usecase.CreateMessageInput{
+			RoomID:   "room-456",
+			AuthorID: "author-123",
+			Content:  content,
+		})
 
 		require.NoError(t, err)
-		require.Nil(t, out)
+	})
+
+	t.Run("本文中のメンションがリポジトリに渡される", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockMessageRepository{
+			createMessageFunc: func(ctx context.Context, inp repository.CreateMessageInput) error {
+				require.Equal(t, []repository.MentionInput{
+					{Kind: "user", UserName: "alice", Start: 0, End: 6},
+					{Kind: "room", UserName: "", Start: 7, End: 12},
+				}, inp.Mentions)
+				return nil
+			},
+		}
+
+		content, _ := domain.NewMessageContent("@alice @room release is out")
+		uc := usecase.NewCreateMessageUsecase(mockRepo, nil)
+		_, err := uc.Execute(t.Context(), usecase.CreateMessageInput{
+			RoomID:   "room-456",
+			AuthorID: "author-123",
+			Content:  content,
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("本文のフォーマットがリポジトリに渡される", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockMessageRepository{
+			createMessageFunc: func(ctx context.Context, inp repository.CreateMessageInput) error {
+				require.Equal(t, "markdown", inp.Format)
+				return nil
+			},
+		}
+
+		content, _ := domain.NewMessageContent("**hello**")
+		uc := usecase.NewCreateMessageUsecase(mockRepo, nil)
+		_, err := uc.Execute(t.Context(), usecase.CreateMessageInput{
+			RoomID:   "room-456",
+			AuthorID: "author-123",
+			Content:  content,
+			Format:   domain.MessageFormatMarkdown,
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("重複した添付ファイル ID はまとめて渡される", func(t *testing.T) {
+		t.Parallel()
+
+		attachmentID := domain.AttachmentIDFromUuid(uuid.New())
+		mockRepo := &mockMessageRepository{
+			createMessageFunc: func(ctx context.Context, inp repository.CreateMessageInput) error {
+				require.Equal(t, []string{attachmentID.String()}, inp.AttachmentIDs)
+				return nil
+			},
+		}
+
+		content, _ := domain.NewMessageContent("see attached")
+		uc := usecase.NewCreateMessageUsecase(mockRepo, nil)
+		_, err := uc.Execute(t.Context(), usecase.CreateMessageInput{
+			RoomID:        "room-456",
+			AuthorID:      "author-123",
+			Content:       content,
+			AttachmentIDs: []domain.AttachmentID{attachmentID, attachmentID},
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("添付ファイルが多すぎる場合はエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		attachmentIDs := make([]domain.AttachmentID, 11)
+		for i := range attachmentIDs {
+			attachmentIDs[i] = domain.AttachmentIDFromUuid(uuid.New())
+		}
+
+		content, _ := domain.NewMessageContent("see attached")
+		uc := usecase.NewCreateMessageUsecase(&mockMessageRepository{}, nil)
+		_, err := uc.Execute(t.Context(), usecase.CreateMessageInput{
+			RoomID:        "room-456",
+			AuthorID:      "author-123",
+			Content:       content,
+			AttachmentIDs: attachmentIDs,
+		})
+
+		require.ErrorIs(t, err, usecase.ErrTooManyAttachments)
+	})
+
+	t.Run("リポジトリエラー時にエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockMessageRepository{
+			createMessageFunc: func(ctx context.Context, inp repository.CreateMessageInput) error {
+				return errors.New("db error")
+			},
+		}
+
+		content, _ := domain.NewMessageContent("Hello, World!")
+		uc := usecase.NewCreateMessageUsecase(mockRepo, nil)
+		_, err := uc.Execute(t.Context(), usecase.CreateMessageInput{
+			RoomID:   "room-456",
+			AuthorID: "author-123",
+			Content:  content,
+		})
+
+		require.Error(t, err)
+	})
+}
+
+func TestCreateMessageUsecase_Execute_SlashCommand(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ephemeral な応答は保存せずに返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockMessageRepository{
+			createMessageFunc: func(ctx context.Context, inp repository.CreateMessageInput) error {
+				t.Fatal("should not be called")
+				return nil
+			},
+		}
+		reply, _ := domain.NewMessageContent("Topic updated.")
+		dispatcher := &mockSlashCommandDispatcher{
+			dispatchFunc: func(ctx context.Context, req usecase.SlashCommandRequest) (usecase.SlashCommandResponse, error) {
+				require.Equal(t, "room-456", req.RoomID)
+				require.Equal(t, "author-123", req.AccountID)
+				require.Equal(t, "topic", req.Command.Name().String())
+				require.Equal(t, "release day", req.Command.Text())
+				return usecase.SlashCommandResponse{
+					Visibility: domain.SlashCommandEphemeral,
+					Content:    reply,
+					Format:     domain.MessageFormatPlain,
+				}, nil
+			},
+		}
+
+		content, _ := domain.NewMessageContent("/topic release day")
+		out, err := usecase.NewCreateMessageUsecase(mockRepo, dispatcher).Execute(t.Context(), usecase.CreateMessageInput{
+			RoomID:   "room-456",
+			AuthorID: "author-123",
+			Content:  content,
+		})
+
+		require.NoError(t, err)
+		require.True(t, out.Ephemeral)
+		require.Empty(t, out.ID)
+		require.Equal(t, "Topic updated.", out.Content.String())
+	})
+
+	t.Run("public な応答は応答の投稿者として保存する", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockMessageRepository{
+			createdMessageID: "message-1",
+			createMessageFunc: func(ctx context.Context, inp repository.CreateMessageInput) error {
+				require.Equal(t, "bot-1", inp.AuthorID)
+				require.Equal(t, "deployed @alice", inp.Content)
+				require.Equal(t, "markdown", inp.Format)
+				require.Len(t, inp.Mentions, 1)
+				return nil
+			},
+		}
+		reply, _ := domain.NewMessageContent("deployed @alice")
+		dispatcher := &mockSlashCommandDispatcher{
+			dispatchFunc: func(ctx context.Context, req usecase.SlashCommandRequest) (usecase.SlashCommandResponse, error) {
+				return usecase.SlashCommandResponse{
+					Visibility: domain.SlashCommandPublic,
+					Content:    reply,
+					Format:     domain.MessageFormatMarkdown,
+					AuthorID:   "bot-1",
+				}, nil
+			},
+		}
+
+		content, _ := domain.NewMessageContent("/deploy status")
+		out, err := usecase.NewCreateMessageUsecase(mockRepo, dispatcher).Execute(t.Context(), usecase.CreateMessageInput{
+			RoomID:   "room-456",
+			AuthorID: "author-123",
+			Content:  content,
+		})
+
+		require.NoError(t, err)
+		require.False(t, out.Ephemeral)
+		require.Equal(t, "message-1", out.ID)
+		require.Equal(t, "bot-1", out.AuthorID)
+	})
+
+	t.Run("コマンドとして解釈しない本文はそのまま保存する", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name     string
+			content  string
+			expected string
+		}{
+			{"escaped", "//deploy status", "/deploy status"},
+			{"path", "/usr/bin is missing", "/usr/bin is missing"},
+			{"plain", "hello", "hello"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				mockRepo := &mockMessageRepository{
+					createMessageFunc: func(ctx context.Context, inp repository.CreateMessageInput) error {
+						require.Equal(t, tt.expected, inp.Content)
+						return nil
+					},
+				}
+				dispatcher := &mockSlashCommandDispatcher{
+					dispatchFunc: func(ctx context.Context, req usecase.SlashCommandRequest) (usecase.SlashCommandResponse, error) {
+						t.Fatal("should not be called")
+						return usecase.SlashCommandResponse{}, nil
+					},
+				}
+
+				content, _ := domain.NewMessageContent(tt.content)
+				_, err := usecase.NewCreateMessageUsecase(mockRepo, dispatcher).Execute(t.Context(), usecase.CreateMessageInput{
+					RoomID:   "room-456",
+					AuthorID: "author-123",
+					Content:  content,
+				})
+				require.NoError(t, err)
+			})
+		}
+	})
+
+	t.Run("コマンドにファイルを添付するとエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		content, _ := domain.NewMessageContent("/shrug")
+		_, err := usecase.NewCreateMessageUsecase(&mockMessageRepository{}, &mockSlashCommandDispatcher{}).Execute(t.Context(), usecase.CreateMessageInput{
+			RoomID:        "room-456",
+			AuthorID:      "author-123",
+			Content:       content,
+			AttachmentIDs: []domain.AttachmentID{domain.AttachmentIDFromUuid(uuid.New())},
+		})
+
+		require.ErrorIs(t, err, usecase.ErrCommandWithAttachments)
+	})
+}
+
+func TestNewCreateMessageUsecase(t *testing.T) {
+	t.Parallel()
+
+	t.Run("正しく初期化される", func(t *testing.T) {
+		t.Parallel()
+		mockRepo := &mockMessageRepository{}
+
+		uc := usecase.NewCreateMessageUsecase(mockRepo, nil)
+
+		require.NotNil(t, uc)
 	})
 }
@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+)
+
+type CreateSlashCommandUsecase struct {
+	repo repository.SlashCommandRepository
+}
+
+// CreateSlashCommandInput の BotName はコマンドの応答を投稿する Bot アカウントの名前
+type CreateSlashCommandInput struct {
+	RoomID      string
+	AccountID   string
+	Name        domain.SlashCommandName
+	Description domain.SlashCommandDescription
+	CallbackURL domain.WebhookURL
+	Secret      domain.WebhookSecret
+	BotName     domain.UserName
+}
+
+type CreateSlashCommandOutput struct {
+	ID string
+}
+
+// MaxSlashCommandsPerRoom はルームごとに登録できるコマンド数の上限
+const MaxSlashCommandsPerRoom = 20
+
+var ErrSlashCommandNameReserved = errors.New("slash command name reserved")
+
+func NewCreateSlashCommandUsecase(repo repository.SlashCommandRepository) *CreateSlashCommandUsecase {
+	return &CreateSlashCommandUsecase{repo}
+}
+
+func (u *CreateSlashCommandUsecase) Execute(ctx context.Context, inp CreateSlashCommandInput) (CreateSlashCommandOutput, error) {
+	if IsBuiltinSlashCommand(inp.Name) {
+		return CreateSlashCommandOutput{}, ErrSlashCommandNameReserved
+	}
+
+	res, err := u.repo.CreateSlashCommand(ctx, repository.CreateSlashCommandInput{
+		RoomID:      inp.RoomID,
+		AccountID:   inp.AccountID,
+		Name:        inp.Name.String(),
+		Description: inp.Description.String(),
+		CallbackURL: inp.CallbackURL.String(),
+		Secret:      inp.Secret.String(),
+		BotName:     inp.BotName.String(),
+		MaxCommands: MaxSlashCommandsPerRoom,
+	})
+	if err != nil {
+		return CreateSlashCommandOutput{}, fmt.Errorf("failed to create slash command: %w", err)
+	}
+
+	return CreateSlashCommandOutput{ID: res.ID}, nil
+}
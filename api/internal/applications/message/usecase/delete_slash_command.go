@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/repository"
+)
+
+type DeleteSlashCommandUsecase struct {
+	repo repository.SlashCommandRepository
+}
+
+type DeleteSlashCommandInput struct {
+	RoomID    string
+	CommandID string
+	AccountID string
+}
+
+func NewDeleteSlashCommandUsecase(repo repository.SlashCommandRepository) *DeleteSlashCommandUsecase {
+	return &DeleteSlashCommandUsecase{repo}
+}
+
+// Execute はコマンドだけを削除し、Bot アカウントは過去の投稿の投稿者として残す
+func (u *DeleteSlashCommandUsecase) Execute(ctx context.Context, inp DeleteSlashCommandInput) error {
+	if err := u.repo.DeleteSlashCommand(ctx, repository.DeleteSlashCommandInput{
+		RoomID:    inp.RoomID,
+		CommandID: inp.CommandID,
+		AccountID: inp.AccountID,
+	}); err != nil {
+		return fmt.Errorf("failed to delete slash command: %w", err)
+	}
+	return nil
+}
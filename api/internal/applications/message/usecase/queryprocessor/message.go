@@ -8,11 +8,42 @@ type GetMessagesOutput struct {
 	Messages []string
 }
 
+// Message の Event は Kind が "system" の場合のみ設定される
 type Message struct {
-	ID        string
-	Author    string
-	Content   string
-	CreatedAt string
+	ID          string
+	Kind        string
+	Author      string
+	AuthorIsBot bool
+	Content     string
+	Format      string
+	CreatedAt   string
+	Pinned      bool
+	Mentions    []MentionSpan
+	Attachments []Attachment
+	Event       *SystemEvent
+}
+
+type SystemEvent struct {
+	Type             string
+	MessageID        string
+	RoomName         string
+	PreviousRoomName string
+	Topic            string
+}
+
+type Attachment struct {
+	ID           string
+	FileName     string
+	ContentType  string
+	Size         int64
+	HasThumbnail bool
+}
+
+type MentionSpan struct {
+	Kind     string
+	UserName string
+	Start    int
+	End      int
 }
 
 type MessageQueryProcessor interface {
@@ -0,0 +1,23 @@
+package queryprocessor
+
+import (
+	"context"
+	"errors"
+)
+
+// SlashCommandDTO は送信先の URL と共有鍵を含まない
+type SlashCommandDTO struct {
+	ID          string
+	Name        string
+	Description string
+	BotName     string
+	CreatedBy   string
+	CreatedAt   string
+}
+
+var ErrRoomNotFound = errors.New("room not found")
+
+// SlashCommandQueryProcessor のコマンド一覧はルームの誰でも参照できる
+type SlashCommandQueryProcessor interface {
+	GetSlashCommands(ctx context.Context, roomID string) ([]SlashCommandDTO, error)
+}
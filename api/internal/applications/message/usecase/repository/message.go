@@ -1,14 +1,50 @@
 package repository
 
-import "context"
+import (
+	"context"
+	"errors"
+)
 
 type CreateMessageInput struct {
-	AuthorID string
-	Content  string
-	RoomID   string
+	AuthorID      string
+	Content       string
+	Format        string
+	RoomID        string
+	Mentions      []MentionInput
+	AttachmentIDs []string
+	// ScheduledMessageID が空でない場合は、メッセージの作成と同じトランザクションで予約メッセージを送信済みにする
+	//
+	// ScheduledMessageAttempts はワーカーが取り出した時の送信回数。
+	// Lease が切れて他のワーカーが取り出し直した場合は送信回数が変わるため、メッセージを作成せずに ErrScheduledMessageNotClaimed を返す
+	ScheduledMessageID       string
+	ScheduledMessageAttempts int
 }
 
+type CreateMessageOutput struct {
+	ID string
+}
+
+// MentionInput は本文中のメンション箇所
+//
+// Kind が "room" の場合 UserName は空で、ルームの全メンバーが通知対象になる
+type MentionInput struct {
+	Kind     string
+	UserName string
+	Start    int
+	End      int
+}
+
+var (
+	// ErrAttachmentNotAvailable は添付ファイルが存在しない、別のルーム・アカウントのもの、または添付済みの場合に返す
+	ErrAttachmentNotAvailable = errors.New("attachment not available")
+	ErrRoomNotFound           = errors.New("room not found")
+	// ErrRoomArchived はアーカイブ済みのルームに投稿しようとした場合に返す
+	ErrRoomArchived = errors.New("room archived")
+	// ErrScheduledMessageNotClaimed は投稿しようとした予約メッセージが、送信済みか他のワーカーに取り出し直されている場合に返す
+	ErrScheduledMessageNotClaimed = errors.New("scheduled message not claimed")
+)
+
 type MessageRepository interface {
-	CreateMessage(ctx context.Context, inp CreateMessageInput) error
+	CreateMessage(ctx context.Context, inp CreateMessageInput) (CreateMessageOutput, error)
 	Get() error
 }
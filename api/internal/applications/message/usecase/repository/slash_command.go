@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"errors"
+)
+
+// CreateSlashCommandInput の BotName はコマンドの応答を投稿する Bot アカウントの名前
+//
+// 同じ名前の Bot アカウントが既にあれば再利用し、なければ作成する。MaxCommands はルームあたりのコマンド数の上限
+type CreateSlashCommandInput struct {
+	RoomID      string
+	AccountID   string
+	Name        string
+	Description string
+	CallbackURL string
+	Secret      string
+	BotName     string
+	MaxCommands int
+}
+type CreateSlashCommandOutput struct {
+	ID string
+}
+
+type DeleteSlashCommandInput struct {
+	RoomID    string
+	CommandID string
+	AccountID string
+}
+
+type FindSlashCommandInput struct {
+	RoomID string
+	Name   string
+}
+
+// FindSlashCommandOutput の BotAccountID は public な応答の投稿者として記録するアカウント
+type FindSlashCommandOutput struct {
+	ID           string
+	CallbackURL  string
+	Secret       string
+	BotAccountID string
+}
+
+var (
+	// ErrNotRoomOwner はルームの作成者以外がコマンドを登録・削除しようとした場合に返す
+	ErrNotRoomOwner         = errors.New("not room owner")
+	ErrSlashCommandExists   = errors.New("slash command already exists")
+	ErrTooManySlashCommands = errors.New("too many slash commands")
+	ErrSlashCommandNotFound = errors.New("slash command not found")
+	// ErrBotNameTaken は Bot の名前が人間のアカウントや、他のアカウントが登録したコマンドなどの Bot で使われている場合に返す
+	ErrBotNameTaken = errors.New("bot name taken")
+)
+
+// SlashCommandRepository は Bot が登録したスラッシュコマンドを管理する
+type SlashCommandRepository interface {
+	CreateSlashCommand(ctx context.Context, inp CreateSlashCommandInput) (CreateSlashCommandOutput, error)
+	DeleteSlashCommand(ctx context.Context, inp DeleteSlashCommandInput) error
+	FindSlashCommand(ctx context.Context, inp FindSlashCommandInput) (FindSlashCommandOutput, error)
+	// GetAccountName はコマンドの送信先に実行者の名前を伝えるために使う
+	GetAccountName(ctx context.Context, accountID string) (string, error)
+}
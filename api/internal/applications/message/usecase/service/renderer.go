@@ -0,0 +1,10 @@
+package service
+
+import "github.com/quietsato/toy-small-chat/api/internal/domain"
+
+// MessageRenderer はメッセージ本文をクライアントにそのまま埋め込める HTML に変換する
+//
+// 実装は生の HTML を一切通さず、許可したタグと属性のみを出力しなければならない
+type MessageRenderer interface {
+	RenderHTML(content string, format domain.MessageFormat) string
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"errors"
+)
+
+// CallSlashCommandInput の Payload は署名の対象になる送信本文
+type CallSlashCommandInput struct {
+	URL     string
+	Secret  string
+	Payload []byte
+}
+
+// CallSlashCommandOutput は送信先が返した応答。ResponseType は "ephemeral" または "public"
+type CallSlashCommandOutput struct {
+	Text         string
+	Format       string
+	ResponseType string
+}
+
+// SlashCommandCaller は Bot が登録したコマンドの送信先に署名付きのリクエストを送り、応答を受け取る
+//
+// 2xx 以外の応答や解釈できない応答は error を返す
+type SlashCommandCaller interface {
+	Call(ctx context.Context, inp CallSlashCommandInput) (CallSlashCommandOutput, error)
+}
+
+type UpdateRoomTopicInput struct {
+	RoomID    string
+	AccountID string
+	Topic     string
+}
+
+var (
+	// ErrRoomTopicForbidden はルームの作成者以外がトピックを変更しようとした場合に返す
+	ErrRoomTopicForbidden = errors.New("room topic forbidden")
+	ErrInvalidRoomTopic   = errors.New("invalid room topic")
+)
+
+// RoomTopicUpdater は組み込みの /topic コマンドからルームのトピックを変更する
+type RoomTopicUpdater interface {
+	UpdateTopic(ctx context.Context, inp UpdateRoomTopicInput) error
+}
@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/service"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+)
+
+// SlashCommandRequest の AccountID はコマンドを実行したアカウント
+type SlashCommandRequest struct {
+	RoomID    string
+	AccountID string
+	Command   domain.SlashCommand
+}
+
+// SlashCommandResponse の AuthorID は Visibility が public の場合に投稿者として記録するアカウント
+type SlashCommandResponse struct {
+	Visibility domain.SlashCommandVisibility
+	Content    domain.MessageContent
+	Format     domain.MessageFormat
+	AuthorID   string
+}
+
+// SlashCommandDispatcher は "/" で始まる投稿をコマンドとして実行する
+type SlashCommandDispatcher interface {
+	Dispatch(ctx context.Context, req SlashCommandRequest) (SlashCommandResponse, error)
+}
+
+var (
+	ErrUnknownSlashCommand = errors.New("unknown slash command")
+	// ErrSlashCommandFailed は Bot のコマンドの送信先が失敗した、または解釈できない応答を返した場合に返す
+	ErrSlashCommandFailed = errors.New("slash command failed")
+)
+
+// builtinSlashCommand はルームに登録しなくても使えるコマンド
+type builtinSlashCommand func(r *SlashCommandRegistry, ctx context.Context, req SlashCommandRequest) (SlashCommandResponse, error)
+
+var builtinSlashCommands = map[string]builtinSlashCommand{
+	"me":    (*SlashCommandRegistry).me,
+	"shrug": (*SlashCommandRegistry).shrug,
+	"topic": (*SlashCommandRegistry).topic,
+}
+
+// IsBuiltinSlashCommand は組み込みのコマンド名かどうかを返す。組み込みのコマンド名は Bot に登録させない
+func IsBuiltinSlashCommand(name domain.SlashCommandName) bool {
+	_, ok := builtinSlashCommands[name.String()]
+	return ok
+}
+
+// SlashCommandRegistry は組み込みのコマンドを優先し、見つからなければルームに登録された Bot のコマンドを呼び出す
+type SlashCommandRegistry struct {
+	repo   repository.SlashCommandRepository
+	caller service.SlashCommandCaller
+	topics service.RoomTopicUpdater
+}
+
+func NewSlashCommandRegistry(repo repository.SlashCommandRepository, caller service.SlashCommandCaller, topics service.RoomTopicUpdater) *SlashCommandRegistry {
+	return &SlashCommandRegistry{repo, caller, topics}
+}
+
+// Dispatch implements SlashCommandDispatcher.
+func (r *SlashCommandRegistry) Dispatch(ctx context.Context, req SlashCommandRequest) (SlashCommandResponse, error) {
+	if builtin, ok := builtinSlashCommands[req.Command.Name().String()]; ok {
+		return builtin(r, ctx, req)
+	}
+	return r.callBot(ctx, req)
+}
+
+// me は実行者の動作を斜体で投稿する
+func (r *SlashCommandRegistry) me(ctx context.Context, req SlashCommandRequest) (SlashCommandResponse, error) {
+	if req.Command.Text() == "" {
+		return r.ephemeral("Usage: /me <action>")
+	}
+	return r.public(req.AccountID, "_"+req.Command.Text()+"_", domain.MessageFormatMarkdown)
+}
+
+// shrug は引数の後に ¯\_(ツ)_/¯ を付けて投稿する
+func (r *SlashCommandRegistry) shrug(ctx context.Context, req SlashCommandRequest) (SlashCommandResponse, error) {
+	content := `¯\_(ツ)_/¯`
+	if req.Command.Text() != "" {
+		content = req.Command.Text() + " " + content
+	}
+	return r.public(req.AccountID, content, domain.MessageFormatPlain)
+}
+
+// topic はルームのトピックを変更する。変更はシステムメッセージとして記録されるため、応答は実行者にだけ返す
+func (r *SlashCommandRegistry) topic(ctx context.Context, req SlashCommandRequest) (SlashCommandResponse, error) {
+	if req.Command.Text() == "" {
+		return r.ephemeral("Usage: /topic <topic>")
+	}
+
+	err := r.topics.UpdateTopic(ctx, service.UpdateRoomTopicInput{
+		RoomID:    req.RoomID,
+		AccountID: req.AccountID,
+		Topic:     req.Command.Text(),
+	})
+	switch {
+	case errors.Is(err, service.ErrRoomTopicForbidden):
+		return r.ephemeral("Only the room owner can change the topic.")
+	case errors.Is(err, service.ErrInvalidRoomTopic):
+		return r.ephemeral("The topic is too long.")
+	case err != nil:
+		return SlashCommandResponse{}, fmt.Errorf("failed to update topic: %w", err)
+	}
+	return r.ephemeral("Topic updated.")
+}
+
+// slashCommandPayload は Bot のコマンドの送信先に POST する本文
+type slashCommandPayload struct {
+	Command   string `json:"command"`
+	Text      string `json:"text"`
+	RoomID    string `json:"roomId"`
+	UserID    string `json:"userId"`
+	UserName  string `json:"userName"`
+	CommandID string `json:"commandId"`
+}
+
+// callBot はルームに登録された Bot のコマンドを呼び出す。public な応答は Bot を投稿者とする
+func (r *SlashCommandRegistry) callBot(ctx context.Context, req SlashCommandRequest) (SlashCommandResponse, error) {
+	cmd, err := r.repo.FindSlashCommand(ctx, repository.FindSlashCommandInput{
+		RoomID: req.RoomID,
+		Name:   req.Command.Name().String(),
+	})
+	if errors.Is(err, repository.ErrSlashCommandNotFound) {
+		return SlashCommandResponse{}, ErrUnknownSlashCommand
+	}
+	if err != nil {
+		return SlashCommandResponse{}, fmt.Errorf("failed to find slash command: %w", err)
+	}
+
+	userName, err := r.repo.GetAccountName(ctx, req.AccountID)
+	if err != nil {
+		return SlashCommandResponse{}, fmt.Errorf("failed to get account name: %w", err)
+	}
+
+	payload, err := json.Marshal(slashCommandPayload{
+		Command:   "/" + req.Command.Name().String(),
+		Text:      req.Command.Text(),
+		RoomID:    req.RoomID,
+		UserID:    req.AccountID,
+		UserName:  userName,
+		CommandID: cmd.ID,
+	})
+	if err != nil {
+		return SlashCommandResponse{}, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	res, err := r.caller.Call(ctx, service.CallSlashCommandInput{
+		URL:     cmd.CallbackURL,
+		Secret:  cmd.Secret,
+		Payload: payload,
+	})
+	if err != nil {
+		return SlashCommandResponse{}, fmt.Errorf("%w: %w", ErrSlashCommandFailed, err)
+	}
+
+	content, err := domain.NewMessageContent(res.Text)
+	if err != nil {
+		return SlashCommandResponse{}, fmt.Errorf("%w: bad text: %w", ErrSlashCommandFailed, err)
+	}
+	format, err := domain.NewMessageFormat(res.Format)
+	if err != nil {
+		return SlashCommandResponse{}, fmt.Errorf("%w: bad format: %w", ErrSlashCommandFailed, err)
+	}
+	visibility, err := domain.NewSlashCommandVisibility(res.ResponseType)
+	if err != nil {
+		return SlashCommandResponse{}, fmt.Errorf("%w: bad response type: %w", ErrSlashCommandFailed, err)
+	}
+
+	return SlashCommandResponse{
+		Visibility: visibility,
+		Content:    content,
+		Format:     format,
+		AuthorID:   cmd.BotAccountID,
+	}, nil
+}
+
+func (r *SlashCommandRegistry) ephemeral(text string) (SlashCommandResponse, error) {
+	content, err := domain.NewMessageContent(text)
+	if err != nil {
+		return SlashCommandResponse{}, err
+	}
+	return SlashCommandResponse{
+		Visibility: domain.SlashCommandEphemeral,
+		Content:    content,
+		Format:     domain.MessageFormatPlain,
+	}, nil
+}
+
+func (r *SlashCommandRegistry) public(authorID, text string, format domain.MessageFormat) (SlashCommandResponse, error) {
+	content, err := domain.NewMessageContent(text)
+	if err != nil {
+		return SlashCommandResponse{}, err
+	}
+	return SlashCommandResponse{
+		Visibility: domain.SlashCommandPublic,
+		Content:    content,
+		Format:     format,
+		AuthorID:   authorID,
+	}, nil
+}
+
+var _ SlashCommandDispatcher = new(SlashCommandRegistry)
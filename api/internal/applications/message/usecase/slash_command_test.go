@@ -0,0 +1,288 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/service"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSlashCommandRepository struct {
+	createSlashCommandFunc func(ctx context.Context, inp repository.CreateSlashCommandInput) (repository.CreateSlashCommandOutput, error)
+	findSlashCommandFunc   func(ctx context.Context, inp repository.FindSlashCommandInput) (repository.FindSlashCommandOutput, error)
+}
+
+func (m *mockSlashCommandRepository) CreateSlashCommand(ctx context.Context, inp repository.CreateSlashCommandInput) (repository.CreateSlashCommandOutput, error) {
+	if m.createSlashCommandFunc != nil {
+		return m.createSlashCommandFunc(ctx, inp)
+	}
+	return repository.CreateSlashCommandOutput{}, nil
+}
+
+func (m *mockSlashCommandRepository) DeleteSlashCommand(ctx context.Context, inp repository.DeleteSlashCommandInput) error {
+	return nil
+}
+
+func (m *mockSlashCommandRepository) FindSlashCommand(ctx context.Context, inp repository.FindSlashCommandInput) (repository.FindSlashCommandOutput, error) {
+	if m.findSlashCommandFunc != nil {
+		return m.findSlashCommandFunc(ctx, inp)
+	}
+	return repository.FindSlashCommandOutput{}, repository.ErrSlashCommandNotFound
+}
+
+func (m *mockSlashCommandRepository) GetAccountName(ctx context.Context, accountID string) (string, error) {
+	return "alice", nil
+}
+
+type mockSlashCommandCaller struct {
+	callFunc func(ctx context.Context, inp service.CallSlashCommandInput) (service.CallSlashCommandOutput, error)
+}
+
+func (m *mockSlashCommandCaller) Call(ctx context.Context, inp service.CallSlashCommandInput) (service.CallSlashCommandOutput, error) {
+	if m.callFunc != nil {
+		return m.callFunc(ctx, inp)
+	}
+	return service.CallSlashCommandOutput{}, nil
+}
+
+type mockRoomTopicUpdater struct {
+	updateTopicFunc func(ctx context.Context, inp service.UpdateRoomTopicInput) error
+}
+
+func (m *mockRoomTopicUpdater) UpdateTopic(ctx context.Context, inp service.UpdateRoomTopicInput) error {
+	if m.updateTopicFunc != nil {
+		return m.updateTopicFunc(ctx, inp)
+	}
+	return nil
+}
+
+func newSlashCommandRequest(t *testing.T, content string) usecase.SlashCommandRequest {
+	t.Helper()
+
+	c, err := domain.NewMessageContent(content)
+	require.NoError(t, err)
+	cmd, ok := domain.ParseSlashCommand(c)
+	require.True(t, ok)
+	return usecase.SlashCommandRequest{RoomID: "room-1", AccountID: "account-1", Command: cmd}
+}
+
+func TestSlashCommandRegistry_Dispatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("組み込みのコマンドは実行者として投稿する", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name    string
+			content string
+			want    string
+			format  domain.MessageFormat
+		}{
+			{"me", "/me waves", "_waves_", domain.MessageFormatMarkdown},
+			{"shrug", "/shrug no idea", `no idea ¯\_(ツ)_/¯`, domain.MessageFormatPlain},
+			{"shrug without text", "/shrug", `¯\_(ツ)_/¯`, domain.MessageFormatPlain},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				r := usecase.NewSlashCommandRegistry(&mockSlashCommandRepository{}, &mockSlashCommandCaller{}, &mockRoomTopicUpdater{})
+				res, err := r.Dispatch(t.Context(), newSlashCommandRequest(t, tt.content))
+
+				require.NoError(t, err)
+				require.Equal(t, domain.SlashCommandPublic, res.Visibility)
+				require.Equal(t, tt.want, res.Content.String())
+				require.Equal(t, tt.format, res.Format)
+				require.Equal(t, "account-1", res.AuthorID)
+			})
+		}
+	})
+
+	t.Run("/topic はトピックを変更して実行者にだけ応答する", func(t *testing.T) {
+		t.Parallel()
+
+		topics := &mockRoomTopicUpdater{
+			updateTopicFunc: func(ctx context.Context, inp service.UpdateRoomTopicInput) error {
+				require.Equal(t, service.UpdateRoomTopicInput{RoomID: "room-1", AccountID: "account-1", Topic: "release day"}, inp)
+				return nil
+			},
+		}
+
+		r := usecase.NewSlashCommandRegistry(&mockSlashCommandRepository{}, &mockSlashCommandCaller{}, topics)
+		res, err := r.Dispatch(t.Context(), newSlashCommandRequest(t, "/topic release day"))
+
+		require.NoError(t, err)
+		require.Equal(t, domain.SlashCommandEphemeral, res.Visibility)
+		require.Equal(t, "Topic updated.", res.Content.String())
+	})
+
+	t.Run("/topic の権限がない場合は実行者にだけ伝える", func(t *testing.T) {
+		t.Parallel()
+
+		topics := &mockRoomTopicUpdater{
+			updateTopicFunc: func(ctx context.Context, inp service.UpdateRoomTopicInput) error {
+				return service.ErrRoomTopicForbidden
+			},
+		}
+
+		r := usecase.NewSlashCommandRegistry(&mockSlashCommandRepository{}, &mockSlashCommandCaller{}, topics)
+		res, err := r.Dispatch(t.Context(), newSlashCommandRequest(t, "/topic release day"))
+
+		require.NoError(t, err)
+		require.Equal(t, domain.SlashCommandEphemeral, res.Visibility)
+	})
+
+	t.Run("Bot のコマンドを呼び出し、public な応答は Bot を投稿者とする", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockSlashCommandRepository{
+			findSlashCommandFunc: func(ctx context.Context, inp repository.FindSlashCommandInput) (repository.FindSlashCommandOutput, error) {
+				require.Equal(t, repository.FindSlashCommandInput{RoomID: "room-1", Name: "deploy"}, inp)
+				return repository.FindSlashCommandOutput{
+					ID:           "command-1",
+					CallbackURL:  "https://example.com/deploy",
+					Secret:       "0123456789abcdef",
+					BotAccountID: "bot-1",
+				}, nil
+			},
+		}
+		caller := &mockSlashCommandCaller{
+			callFunc: func(ctx context.Context, inp service.CallSlashCommandInput) (service.CallSlashCommandOutput, error) {
+				require.Equal(t, "https://example.com/deploy", inp.URL)
+				require.Equal(t, "0123456789abcdef", inp.Secret)
+
+				var payload map[string]string
+				require.NoError(t, json.Unmarshal(inp.Payload, &payload))
+				require.Equal(t, map[string]string{
+					"command":   "/deploy",
+					"text":      "status",
+					"roomId":    "room-1",
+					"userId":    "account-1",
+					"userName":  "alice",
+					"commandId": "command-1",
+				}, payload)
+
+				return service.CallSlashCommandOutput{Text: "all green", ResponseType: "public"}, nil
+			},
+		}
+
+		r := usecase.NewSlashCommandRegistry(repo, caller, &mockRoomTopicUpdater{})
+		res, err := r.Dispatch(t.Context(), newSlashCommandRequest(t, "/deploy status"))
+
+		require.NoError(t, err)
+		require.Equal(t, domain.SlashCommandPublic, res.Visibility)
+		require.Equal(t, "all green", res.Content.String())
+		require.Equal(t, domain.MessageFormatPlain, res.Format)
+		require.Equal(t, "bot-1", res.AuthorID)
+	})
+
+	t.Run("登録されていないコマンドはエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		r := usecase.NewSlashCommandRegistry(&mockSlashCommandRepository{}, &mockSlashCommandCaller{}, &mockRoomTopicUpdater{})
+		_, err := r.Dispatch(t.Context(), newSlashCommandRequest(t, "/deploy status"))
+
+		require.ErrorIs(t, err, usecase.ErrUnknownSlashCommand)
+	})
+
+	t.Run("Bot の応答が不正な場合はエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name string
+			out  service.CallSlashCommandOutput
+			err  error
+		}{
+			{"call failed", service.CallSlashCommandOutput{}, errors.New("timeout")},
+			{"empty text", service.CallSlashCommandOutput{Text: ""}, nil},
+			{"bad format", service.CallSlashCommandOutput{Text: "ok", Format: "html"}, nil},
+			{"bad response type", service.CallSlashCommandOutput{Text: "ok", ResponseType: "everyone"}, nil},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				repo := &mockSlashCommandRepository{
+					findSlashCommandFunc: func(ctx context.Context, inp repository.FindSlashCommandInput) (repository.FindSlashCommandOutput, error) {
+						return repository.FindSlashCommandOutput{ID: "command-1", BotAccountID: "bot-1"}, nil
+					},
+				}
+				caller := &mockSlashCommandCaller{
+					callFunc: func(ctx context.Context, inp service.CallSlashCommandInput) (service.CallSlashCommandOutput, error) {
+						return tt.out, tt.err
+					},
+				}
+
+				r := usecase.NewSlashCommandRegistry(repo, caller, &mockRoomTopicUpdater{})
+				_, err := r.Dispatch(t.Context(), newSlashCommandRequest(t, "/deploy status"))
+
+				require.ErrorIs(t, err, usecase.ErrSlashCommandFailed)
+			})
+		}
+	})
+}
+
+func TestCreateSlashCommandUsecase_Execute(t *testing.T) {
+	t.Parallel()
+
+	url, _ := domain.NewWebhookURL("https://example.com/deploy")
+	secret, _ := domain.NewWebhookSecret("0123456789abcdef")
+	botName, _ := domain.NewUserName("deploybot")
+
+	t.Run("コマンドを登録する", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockSlashCommandRepository{
+			createSlashCommandFunc: func(ctx context.Context, inp repository.CreateSlashCommandInput) (repository.CreateSlashCommandOutput, error) {
+				require.Equal(t, "deploy", inp.Name)
+				require.Equal(t, "deploybot", inp.BotName)
+				require.Equal(t, usecase.MaxSlashCommandsPerRoom, inp.MaxCommands)
+				return repository.CreateSlashCommandOutput{ID: "command-1"}, nil
+			},
+		}
+
+		name, _ := domain.NewSlashCommandName("deploy")
+		out, err := usecase.NewCreateSlashCommandUsecase(repo).Execute(t.Context(), usecase.CreateSlashCommandInput{
+			RoomID:      "room-1",
+			AccountID:   "account-1",
+			Name:        name,
+			CallbackURL: url,
+			Secret:      secret,
+			BotName:     botName,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, "command-1", out.ID)
+	})
+
+	t.Run("組み込みのコマンド名は登録できない", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockSlashCommandRepository{
+			createSlashCommandFunc: func(ctx context.Context, inp repository.CreateSlashCommandInput) (repository.CreateSlashCommandOutput, error) {
+				t.Fatal("should not be called")
+				return repository.CreateSlashCommandOutput{}, nil
+			},
+		}
+
+		name, _ := domain.NewSlashCommandName("topic")
+		_, err := usecase.NewCreateSlashCommandUsecase(repo).Execute(t.Context(), usecase.CreateSlashCommandInput{
+			RoomID:      "room-1",
+			AccountID:   "account-1",
+			Name:        name,
+			CallbackURL: url,
+			Secret:      secret,
+			BotName:     botName,
+		})
+
+		require.ErrorIs(t, err, usecase.ErrSlashCommandNameReserved)
+	})
+}
@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/pin/usecase/queryprocessor"
+)
+
+type GetPinsInput struct {
+	RoomID string
+}
+
+type GetPinsOutput struct {
+	Pins []Pin `json:"pins"`
+}
+
+type Pin struct {
+	MessageID string `json:"messageId"`
+	Author    string `json:"author"`
+	Content   string `json:"content"`
+	Format    string `json:"format"`
+	CreatedAt string `json:"createdAt"`
+	PinnedBy  string `json:"pinnedBy"`
+	PinnedAt  string `json:"pinnedAt"`
+}
+
+type GetPinsController struct {
+	query queryprocessor.PinQueryProcessor
+}
+
+func NewGetPinsController(query queryprocessor.PinQueryProcessor) *GetPinsController {
+	return &GetPinsController{query}
+}
+
+func (c *GetPinsController) GetPins(ctx context.Context, inp GetPinsInput) (GetPinsOutput, error) {
+	roomID, err := uuid.Parse(inp.RoomID)
+	if err != nil {
+		return GetPinsOutput{}, fmt.Errorf("failed to parse room id: %w", queryprocessor.ErrRoomNotFound)
+	}
+
+	res, err := c.query.GetPins(ctx, queryprocessor.GetPinsInput{RoomID: roomID})
+	if err != nil {
+		return GetPinsOutput{}, fmt.Errorf("failed to get pins: %w", err)
+	}
+
+	pins := make([]Pin, len(res.Pins))
+	for i, p := range res.Pins {
+		pins[i] = Pin{
+			MessageID: p.MessageID,
+			Author:    p.Author,
+			Content:   p.Content,
+			Format:    p.Format,
+			CreatedAt: p.CreatedAt,
+			PinnedBy:  p.PinnedBy,
+			PinnedAt:  p.PinnedAt,
+		}
+	}
+
+	return GetPinsOutput{Pins: pins}, nil
+}
@@ -0,0 +1,104 @@
+package controller_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/pin/controller"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/pin/usecase/queryprocessor"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock implementations
+type mockPinQueryProcessor struct {
+	getPinsFunc func(ctx context.Context, inp queryprocessor.GetPinsInput) (queryprocessor.GetPinsOutput, error)
+}
+
+func (m *mockPinQueryProcessor) GetPins(ctx context.Context, inp queryprocessor.GetPinsInput) (queryprocessor.GetPinsOutput, error) {
+	if m.getPinsFunc != nil {
+		return m.getPinsFunc(ctx, inp)
+	}
+	return queryprocessor.GetPinsOutput{}, nil
+}
+
+const roomID = "8481027d-d6f6-402f-ae6d-98571e8f6496"
+
+func TestGetPinsController_GetPins(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ピン留め一覧取得成功", func(t *testing.T) {
+		t.Parallel()
+
+		mockQP := &mockPinQueryProcessor{
+			getPinsFunc: func(ctx context.Context, inp queryprocessor.GetPinsInput) (queryprocessor.GetPinsOutput, error) {
+				require.Equal(t, roomID, inp.RoomID.String())
+				return queryprocessor.GetPinsOutput{
+					Pins: []queryprocessor.PinDTO{
+						{
+							MessageID: "msg-1",
+							Author:    "alice",
+							Content:   "release notes",
+							Format:    "markdown",
+							CreatedAt: "2024-01-01T00:00:00Z",
+							PinnedBy:  "bob",
+							PinnedAt:  "2024-01-02T00:00:00Z",
+						},
+					},
+				}, nil
+			},
+		}
+
+		ctrl := controller.NewGetPinsController(mockQP)
+		out, err := ctrl.GetPins(t.Context(), controller.GetPinsInput{RoomID: roomID})
+
+		require.NoError(t, err)
+		require.Equal(t, []controller.Pin{
+			{
+				MessageID: "msg-1",
+				Author:    "alice",
+				Content:   "release notes",
+				Format:    "markdown",
+				CreatedAt: "2024-01-01T00:00:00Z",
+				PinnedBy:  "bob",
+				PinnedAt:  "2024-01-02T00:00:00Z",
+			},
+		}, out.Pins)
+	})
+
+	t.Run("不正なルーム ID の場合は ErrRoomNotFound を返す", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := controller.NewGetPinsController(&mockPinQueryProcessor{})
+		_, err := ctrl.GetPins(t.Context(), controller.GetPinsInput{RoomID: "invalid"})
+
+		require.ErrorIs(t, err, queryprocessor.ErrRoomNotFound)
+	})
+
+	t.Run("クエリプロセッサエラー時にエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockQP := &mockPinQueryProcessor{
+			getPinsFunc: func(ctx context.Context, inp queryprocessor.GetPinsInput) (queryprocessor.GetPinsOutput, error) {
+				return queryprocessor.GetPinsOutput{}, errors.New("db error")
+			},
+		}
+
+		ctrl := controller.NewGetPinsController(mockQP)
+		_, err := ctrl.GetPins(t.Context(), controller.GetPinsInput{RoomID: roomID})
+
+		require.Error(t, err)
+	})
+}
+
+func TestNewGetPinsController(t *testing.T) {
+	t.Parallel()
+
+	t.Run("正しく初期化される", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := controller.NewGetPinsController(&mockPinQueryProcessor{})
+
+		require.NotNil(t, ctrl)
+	})
+}
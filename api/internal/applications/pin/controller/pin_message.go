@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/pin/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/pin/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+)
+
+type PinMessageInput struct {
+	RoomID    string
+	MessageID string
+	AccountID string
+}
+
+// PinMessageOutput の Changed はピン留めの状態が変わった場合に true
+type PinMessageOutput struct {
+	Changed bool
+}
+
+type PinMessageController struct {
+	repo repository.PinRepository
+}
+
+func NewPinMessageController(repo repository.PinRepository) *PinMessageController {
+	return &PinMessageController{repo}
+}
+
+func (c *PinMessageController) PinMessage(ctx context.Context, inp PinMessageInput) (PinMessageOutput, error) {
+	uc := usecase.NewPinMessageUsecase(c.repo)
+	res, err := tracing.Execute(ctx, "PinMessageUsecase", uc.Execute, usecase.PinMessageInput{
+		RoomID:    inp.RoomID,
+		MessageID: inp.MessageID,
+		AccountID: inp.AccountID,
+	})
+	if err != nil {
+		return PinMessageOutput{}, fmt.Errorf("failed to pin message: %w", err)
+	}
+	return PinMessageOutput{Changed: res.Pinned}, nil
+}
+
+func (c *PinMessageController) UnpinMessage(ctx context.Context, inp PinMessageInput) (PinMessageOutput, error) {
+	uc := usecase.NewUnpinMessageUsecase(c.repo)
+	if _, err := tracing.Execute(ctx, "UnpinMessageUsecase", uc.Execute, usecase.UnpinMessageInput{
+		RoomID:    inp.RoomID,
+		MessageID: inp.MessageID,
+		AccountID: inp.AccountID,
+	}); err != nil {
+		return PinMessageOutput{}, fmt.Errorf("failed to unpin message: %w", err)
+	}
+	return PinMessageOutput{Changed: true}, nil
+}
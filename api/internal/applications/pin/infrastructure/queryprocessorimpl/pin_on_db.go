@@ -0,0 +1,58 @@
+package queryprocessorimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/pin/usecase/queryprocessor"
+	"github.com/quietsato/toy-small-chat/api/internal/db"
+)
+
+type PinQueryProcessorOnDB struct {
+	queries *db.Queries
+}
+
+func NewPinQueryProcessorOnDB(pool *pgxpool.Pool) *PinQueryProcessorOnDB {
+	return &PinQueryProcessorOnDB{
+		queries: db.New(pool),
+	}
+}
+
+// GetPins implements queryprocessor.PinQueryProcessor.
+//
+// 削除したルームは復元できる期間中も存在しないものとして扱う
+func (q *PinQueryProcessorOnDB) GetPins(ctx context.Context, inp queryprocessor.GetPinsInput) (queryprocessor.GetPinsOutput, error) {
+	if _, err := q.queries.GetRoomOwner(ctx, inp.RoomID); errors.Is(err, pgx.ErrNoRows) {
+		return queryprocessor.GetPinsOutput{}, queryprocessor.ErrRoomNotFound
+	} else if err != nil {
+		return queryprocessor.GetPinsOutput{}, fmt.Errorf("failed to get room: %w", err)
+	}
+
+	rows, err := q.queries.GetPinnedMessagesByRoomID(ctx, inp.RoomID)
+	if err != nil {
+		return queryprocessor.GetPinsOutput{}, fmt.Errorf("failed to get pinned messages: %w", err)
+	}
+
+	pins := make([]queryprocessor.PinDTO, len(rows))
+	for i, row := range rows {
+		pins[i] = queryprocessor.PinDTO{
+			MessageID: row.MessageID.String(),
+			Author:    row.AuthorName,
+			Content:   row.Content,
+			Format:    row.Format,
+			CreatedAt: row.CreatedAt.Time.Format(time.RFC3339),
+			PinnedBy:  row.PinnedByName,
+			PinnedAt:  row.PinnedAt.Time.Format(time.RFC3339),
+		}
+	}
+
+	return queryprocessor.GetPinsOutput{
+		Pins: pins,
+	}, nil
+}
+
+var _ queryprocessor.PinQueryProcessor = (*PinQueryProcessorOnDB)(nil)
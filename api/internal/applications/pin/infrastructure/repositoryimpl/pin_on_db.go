@@ -0,0 +1,126 @@
+package repositoryimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	messagerepositoryimpl "github.com/quietsato/toy-small-chat/api/internal/applications/message/infrastructure/repositoryimpl"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/pin/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/db"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+)
+
+func NewPinRepositoryOnDB(pool *pgxpool.Pool) *PinRepositoryOnDB {
+	return &PinRepositoryOnDB{pool}
+}
+
+type PinRepositoryOnDB struct {
+	pool *pgxpool.Pool
+}
+
+// PinMessage implements repository.PinRepository.
+//
+// 既にピン留めされている場合は何もしない
+func (r *PinRepositoryOnDB) PinMessage(ctx context.Context, inp repository.PinMessageInput) (repository.PinMessageOutput, error) {
+	var out repository.PinMessageOutput
+	err := r.withOwnerLock(ctx, inp.RoomID, inp.AccountID, func(queries *db.Queries) error {
+		exists, err := queries.MessageExistsInRoom(ctx, db.MessageExistsInRoomParams{
+			ID:     inp.MessageID,
+			RoomID: inp.RoomID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to check message: %w", err)
+		}
+		if !exists {
+			return repository.ErrMessageNotFound
+		}
+
+		// ルームの行をロックしているため、件数の確認と追加の間に他のピン留めは割り込まない
+		count, err := queries.CountPinnedMessagesByRoomID(ctx, inp.RoomID)
+		if err != nil {
+			return fmt.Errorf("failed to count pins: %w", err)
+		}
+		if count >= int64(inp.MaxPins) {
+			return repository.ErrTooManyPins
+		}
+
+		created, err := queries.CreatePinnedMessage(ctx, db.CreatePinnedMessageParams{
+			MessageID: inp.MessageID,
+			RoomID:    inp.RoomID,
+			PinnedBy:  inp.AccountID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create pin: %w", err)
+		}
+		if created == 0 {
+			return nil
+		}
+		out.Pinned = true
+
+		return messagerepositoryimpl.CreateSystemMessage(ctx, queries, inp.RoomID, inp.AccountID, domain.NewMessagePinnedEvent(domain.MessageIDFromUuid(inp.MessageID)))
+	})
+	return out, err
+}
+
+// UnpinMessage implements repository.PinRepository.
+func (r *PinRepositoryOnDB) UnpinMessage(ctx context.Context, inp repository.UnpinMessageInput) error {
+	return r.withOwnerLock(ctx, inp.RoomID, inp.AccountID, func(queries *db.Queries) error {
+		deleted, err := queries.DeletePinnedMessage(ctx, db.DeletePinnedMessageParams{
+			MessageID: inp.MessageID,
+			RoomID:    inp.RoomID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete pin: %w", err)
+		}
+		if deleted == 0 {
+			return repository.ErrPinNotFound
+		}
+
+		return messagerepositoryimpl.CreateSystemMessage(ctx, queries, inp.RoomID, inp.AccountID, domain.NewMessageUnpinnedEvent(domain.MessageIDFromUuid(inp.MessageID)))
+	})
+}
+
+// withOwnerLock はルームの行をロックし、操作者がルームの作成者であることとルームがアーカイブされていないことを確認してから fn を実行する
+func (r *PinRepositoryOnDB) withOwnerLock(ctx context.Context, roomID, accountID uuid.UUID, fn func(queries *db.Queries) error) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rollback", slog.Any("err", err))
+		}
+	}()
+
+	queries := db.New(r.pool).WithTx(tx)
+
+	room, err := queries.GetRoomForUpdate(ctx, roomID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.ErrRoomNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get room: %w", err)
+	}
+	if room.CreatedBy != accountID {
+		return repository.ErrNotRoomOwner
+	}
+	if room.ArchivedAt.Valid {
+		return repository.ErrRoomArchived
+	}
+
+	if err := fn(queries); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
+
+var _ repository.PinRepository = new(PinRepositoryOnDB)
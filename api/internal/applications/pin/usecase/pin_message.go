@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/pin/usecase/repository"
+)
+
+type PinMessageUsecase struct {
+	repo repository.PinRepository
+}
+
+type PinMessageInput struct {
+	RoomID    string
+	MessageID string
+	AccountID string
+}
+
+// PinMessageOutput の Pinned は今回の操作でピン留めされた場合に true
+type PinMessageOutput struct {
+	Pinned bool
+}
+
+// MaxPinsPerRoom はルームごとにピン留めできるメッセージ数の上限
+const MaxPinsPerRoom = 50
+
+var (
+	ErrInvalidMessageID = errors.New("invalid message id")
+)
+
+func NewPinMessageUsecase(repo repository.PinRepository) *PinMessageUsecase {
+	return &PinMessageUsecase{repo}
+}
+
+func (u *PinMessageUsecase) Execute(ctx context.Context, inp PinMessageInput) (PinMessageOutput, error) {
+	ids, err := parsePinIDs(inp.RoomID, inp.MessageID, inp.AccountID)
+	if err != nil {
+		return PinMessageOutput{}, err
+	}
+
+	res, err := u.repo.PinMessage(ctx, repository.PinMessageInput{
+		RoomID:    ids.roomID,
+		MessageID: ids.messageID,
+		AccountID: ids.accountID,
+		MaxPins:   MaxPinsPerRoom,
+	})
+	if err != nil {
+		return PinMessageOutput{}, fmt.Errorf("failed to pin message: %w", err)
+	}
+
+	return PinMessageOutput{Pinned: res.Pinned}, nil
+}
+
+type pinIDs struct {
+	roomID    uuid.UUID
+	messageID uuid.UUID
+	accountID uuid.UUID
+}
+
+func parsePinIDs(roomID, messageID, accountID string) (pinIDs, error) {
+	room, err := uuid.Parse(roomID)
+	if err != nil {
+		return pinIDs{}, fmt.Errorf("failed to parse room id: %w", err)
+	}
+	message, err := uuid.Parse(messageID)
+	if err != nil {
+		return pinIDs{}, ErrInvalidMessageID
+	}
+	account, err := uuid.Parse(accountID)
+	if err != nil {
+		return pinIDs{}, fmt.Errorf("failed to parse account id: %w", err)
+	}
+	return pinIDs{roomID: room, messageID: message, accountID: account}, nil
+}
@@ -0,0 +1,128 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/pin/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/pin/usecase/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock implementations
+type mockPinRepository struct {
+	pinMessageFunc   func(ctx context.Context, inp repository.PinMessageInput) (repository.PinMessageOutput, error)
+	unpinMessageFunc func(ctx context.Context, inp repository.UnpinMessageInput) error
+}
+
+func (m *mockPinRepository) PinMessage(ctx context.Context, inp repository.PinMessageInput) (repository.PinMessageOutput, error) {
+	if m.pinMessageFunc != nil {
+		return m.pinMessageFunc(ctx, inp)
+	}
+	return repository.PinMessageOutput{}, nil
+}
+
+func (m *mockPinRepository) UnpinMessage(ctx context.Context, inp repository.UnpinMessageInput) error {
+	if m.unpinMessageFunc != nil {
+		return m.unpinMessageFunc(ctx, inp)
+	}
+	return nil
+}
+
+const (
+	roomID    = "8481027d-d6f6-402f-ae6d-98571e8f6496"
+	messageID = "0b6b7f3e-5d5e-4f7c-9a3b-2c1d0e9f8a7b"
+	accountID = "550e8400-e29b-41d4-a716-446655440000"
+)
+
+func TestPinMessageUsecase_Execute(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ピン留めが正常に完了する", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockPinRepository{
+			pinMessageFunc: func(ctx context.Context, inp repository.PinMessageInput) (repository.PinMessageOutput, error) {
+				require.Equal(t, roomID, inp.RoomID.String())
+				require.Equal(t, messageID, inp.MessageID.String())
+				require.Equal(t, accountID, inp.AccountID.String())
+				require.Equal(t, usecase.MaxPinsPerRoom, inp.MaxPins)
+				return repository.PinMessageOutput{Pinned: true}, nil
+			},
+		}
+
+		uc := usecase.NewPinMessageUsecase(mockRepo)
+		out, err := uc.Execute(t.Context(), usecase.PinMessageInput{
+			RoomID:    roomID,
+			MessageID: messageID,
+			AccountID: accountID,
+		})
+
+		require.NoError(t, err)
+		require.True(t, out.Pinned)
+	})
+
+	t.Run("不正なメッセージ ID の場合はエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		uc := usecase.NewPinMessageUsecase(&mockPinRepository{})
+		_, err := uc.Execute(t.Context(), usecase.PinMessageInput{
+			RoomID:    roomID,
+			MessageID: "invalid",
+			AccountID: accountID,
+		})
+
+		require.ErrorIs(t, err, usecase.ErrInvalidMessageID)
+	})
+
+	t.Run("リポジトリエラー時にエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockPinRepository{
+			pinMessageFunc: func(ctx context.Context, inp repository.PinMessageInput) (repository.PinMessageOutput, error) {
+				return repository.PinMessageOutput{}, repository.ErrTooManyPins
+			},
+		}
+
+		uc := usecase.NewPinMessageUsecase(mockRepo)
+		_, err := uc.Execute(t.Context(), usecase.PinMessageInput{
+			RoomID:    roomID,
+			MessageID: messageID,
+			AccountID: accountID,
+		})
+
+		require.ErrorIs(t, err, repository.ErrTooManyPins)
+	})
+
+	t.Run("予期しないリポジトリエラー時にエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockPinRepository{
+			pinMessageFunc: func(ctx context.Context, inp repository.PinMessageInput) (repository.PinMessageOutput, error) {
+				return repository.PinMessageOutput{}, errors.New("db error")
+			},
+		}
+
+		uc := usecase.NewPinMessageUsecase(mockRepo)
+		_, err := uc.Execute(t.Context(), usecase.PinMessageInput{
+			RoomID:    roomID,
+			MessageID: messageID,
+			AccountID: accountID,
+		})
+
+		require.Error(t, err)
+	})
+}
+
+func TestNewPinMessageUsecase(t *testing.T) {
+	t.Parallel()
+
+	t.Run("正しく初期化される", func(t *testing.T) {
+		t.Parallel()
+
+		uc := usecase.NewPinMessageUsecase(&mockPinRepository{})
+
+		require.NotNil(t, uc)
+	})
+}
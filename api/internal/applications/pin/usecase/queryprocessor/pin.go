@@ -0,0 +1,30 @@
+package queryprocessor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+type GetPinsInput struct {
+	RoomID uuid.UUID
+}
+type GetPinsOutput struct {
+	Pins []PinDTO
+}
+type PinDTO struct {
+	MessageID string
+	Author    string
+	Content   string
+	Format    string
+	CreatedAt string
+	PinnedBy  string
+	PinnedAt  string
+}
+
+var ErrRoomNotFound = errors.New("room not found")
+
+type PinQueryProcessor interface {
+	GetPins(ctx context.Context, inp GetPinsInput) (GetPinsOutput, error)
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// PinMessageInput の MaxPins はルームあたりのピン留め上限
+type PinMessageInput struct {
+	RoomID    uuid.UUID
+	MessageID uuid.UUID
+	AccountID uuid.UUID
+	MaxPins   int
+}
+
+// PinMessageOutput の Pinned は今回の操作でピン留めされた場合に true。既にピン留め済みなら false
+type PinMessageOutput struct {
+	Pinned bool
+}
+
+type UnpinMessageInput struct {
+	RoomID    uuid.UUID
+	MessageID uuid.UUID
+	AccountID uuid.UUID
+}
+
+var (
+	ErrRoomNotFound = errors.New("room not found")
+	// ErrNotRoomOwner はルームの作成者以外がピン留めを操作しようとした場合に返す
+	ErrNotRoomOwner = errors.New("not room owner")
+	// ErrRoomArchived はアーカイブ済みのルームでピン留めを操作しようとした場合に返す
+	ErrRoomArchived    = errors.New("room archived")
+	ErrMessageNotFound = errors.New("message not found")
+	ErrTooManyPins     = errors.New("too many pinned messages")
+	ErrPinNotFound     = errors.New("pin not found")
+)
+
+// PinRepository はピン留めの変更と、それに伴うシステムメッセージの記録を同一トランザクションで行う
+type PinRepository interface {
+	PinMessage(ctx context.Context, inp PinMessageInput) (PinMessageOutput, error)
+	UnpinMessage(ctx context.Context, inp UnpinMessageInput) error
+}
@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/pin/usecase/repository"
+)
+
+type UnpinMessageUsecase struct {
+	repo repository.PinRepository
+}
+
+type UnpinMessageInput struct {
+	RoomID    string
+	MessageID string
+	AccountID string
+}
+
+type UnpinMessageOutput struct{}
+
+func NewUnpinMessageUsecase(repo repository.PinRepository) *UnpinMessageUsecase {
+	return &UnpinMessageUsecase{repo}
+}
+
+func (u *UnpinMessageUsecase) Execute(ctx context.Context, inp UnpinMessageInput) (UnpinMessageOutput, error) {
+	ids, err := parsePinIDs(inp.RoomID, inp.MessageID, inp.AccountID)
+	if err != nil {
+		return UnpinMessageOutput{}, err
+	}
+
+	if err := u.repo.UnpinMessage(ctx, repository.UnpinMessageInput{
+		RoomID:    ids.roomID,
+		MessageID: ids.messageID,
+		AccountID: ids.accountID,
+	}); err != nil {
+		return UnpinMessageOutput{}, fmt.Errorf("failed to unpin message: %w", err)
+	}
+
+	return UnpinMessageOutput{}, nil
+}
@@ -0,0 +1,68 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/pin/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/pin/usecase/repository"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnpinMessageUsecase_Execute(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ピン留め解除が正常に完了する", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockPinRepository{
+			unpinMessageFunc: func(ctx context.Context, inp repository.UnpinMessageInput) error {
+				require.Equal(t, roomID, inp.RoomID.String())
+				require.Equal(t, messageID, inp.MessageID.String())
+				require.Equal(t, accountID, inp.AccountID.String())
+				return nil
+			},
+		}
+
+		uc := usecase.NewUnpinMessageUsecase(mockRepo)
+		_, err := uc.Execute(t.Context(), usecase.UnpinMessageInput{
+			RoomID:    roomID,
+			MessageID: messageID,
+			AccountID: accountID,
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("不正なメッセージ ID の場合はエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		uc := usecase.NewUnpinMessageUsecase(&mockPinRepository{})
+		_, err := uc.Execute(t.Context(), usecase.UnpinMessageInput{
+			RoomID:    roomID,
+			MessageID: "invalid",
+			AccountID: accountID,
+		})
+
+		require.ErrorIs(t, err, usecase.ErrInvalidMessageID)
+	})
+
+	t.Run("リポジトリエラー時にエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockPinRepository{
+			unpinMessageFunc: func(ctx context.Context, inp repository.UnpinMessageInput) error {
+				return repository.ErrNotRoomOwner
+			},
+		}
+
+		uc := usecase.NewUnpinMessageUsecase(mockRepo)
+		_, err := uc.Execute(t.Context(), usecase.UnpinMessageInput{
+			RoomID:    roomID,
+			MessageID: messageID,
+			AccountID: accountID,
+		})
+
+		require.ErrorIs(t, err, repository.ErrNotRoomOwner)
+	})
+}
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase/queryprocessor"
+)
+
+type GetRoomRetentionInput struct {
+	RoomID string
+}
+
+// RoomRetention の RetentionDays はルームに指定した保存期間、EffectiveRetentionDays は実際に使う保存期間
+//
+// RetentionDays が null の場合はインスタンスの既定値を使い、EffectiveRetentionDays が null の場合は削除しない
+type RoomRetention struct {
+	RetentionDays          *int `json:"retentionDays"`
+	EffectiveRetentionDays *int `json:"effectiveRetentionDays"`
+}
+
+func newRoomRetention(days *int, defaultDays int) RoomRetention {
+	effective := days
+	if effective == nil && defaultDays > 0 {
+		effective = &defaultDays
+	}
+	return RoomRetention{
+		RetentionDays:          days,
+		EffectiveRetentionDays: effective,
+	}
+}
+
+type GetRoomRetentionController struct {
+	query       queryprocessor.RetentionQueryProcessor
+	defaultDays int
+}
+
+func NewGetRoomRetentionController(query queryprocessor.RetentionQueryProcessor, defaultDays int) *GetRoomRetentionController {
+	return &GetRoomRetentionController{query, defaultDays}
+}
+
+func (c *GetRoomRetentionController) GetRoomRetention(ctx context.Context, inp GetRoomRetentionInput) (RoomRetention, error) {
+	roomID, err := uuid.Parse(inp.RoomID)
+	if err != nil {
+		return RoomRetention{}, queryprocessor.ErrRoomNotFound
+	}
+
+	res, err := c.query.GetRoomRetention(ctx, roomID)
+	if err != nil {
+		return RoomRetention{}, fmt.Errorf("failed to get room retention: %w", err)
+	}
+	return newRoomRetention(res.Days, c.defaultDays), nil
+}
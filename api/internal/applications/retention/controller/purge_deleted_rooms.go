@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase/service"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+)
+
+type PurgeDeletedRoomsInput struct {
+	GracePeriod time.Duration
+	BatchSize   int
+}
+
+type PurgeDeletedRoomsOutput struct {
+	RoomIDs     []string
+	Messages    int
+	Attachments int
+}
+
+type PurgeDeletedRoomsController struct {
+	repo  repository.RetentionRepository
+	blobs service.BlobRemover
+}
+
+func NewPurgeDeletedRoomsController(repo repository.RetentionRepository, blobs service.BlobRemover) *PurgeDeletedRoomsController {
+	return &PurgeDeletedRoomsController{repo, blobs}
+}
+
+func (c *PurgeDeletedRoomsController) PurgeDeletedRooms(ctx context.Context, inp PurgeDeletedRoomsInput) (PurgeDeletedRoomsOutput, error) {
+	uc := usecase.NewPurgeDeletedRoomsUsecase(c.repo, c.blobs)
+	res, err := tracing.Execute(ctx, "PurgeDeletedRoomsUsecase", uc.Execute, usecase.PurgeDeletedRoomsInput{
+		GracePeriod: inp.GracePeriod,
+		BatchSize:   inp.BatchSize,
+	})
+	if err != nil {
+		return PurgeDeletedRoomsOutput{}, err
+	}
+
+	return PurgeDeletedRoomsOutput{
+		RoomIDs:     res.RoomIDs,
+		Messages:    res.Messages,
+		Attachments: res.Attachments,
+	}, nil
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase/service"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+)
+
+type PurgeExpiredMessagesInput struct {
+	DefaultDays int
+	BatchSize   int
+}
+
+// PurgeExpiredMessagesOutput の Rooms はメッセージを削除したルームごとの件数
+type PurgeExpiredMessagesOutput struct {
+	Messages          int
+	Attachments       int
+	WebhookDeliveries int
+	Rooms             []PurgedRoom
+}
+
+type PurgedRoom struct {
+	RoomID   string
+	Messages int
+}
+
+type PurgeExpiredMessagesController struct {
+	repo  repository.RetentionRepository
+	blobs service.BlobRemover
+}
+
+func NewPurgeExpiredMessagesController(repo repository.RetentionRepository, blobs service.BlobRemover) *PurgeExpiredMessagesController {
+	return &PurgeExpiredMessagesController{repo, blobs}
+}
+
+func (c *PurgeExpiredMessagesController) PurgeExpiredMessages(ctx context.Context, inp PurgeExpiredMessagesInput) (PurgeExpiredMessagesOutput, error) {
+	uc := usecase.NewPurgeExpiredMessagesUsecase(c.repo, c.blobs)
+	res, err := tracing.Execute(ctx, "PurgeExpiredMessagesUsecase", uc.Execute, usecase.PurgeExpiredMessagesInput{
+		DefaultDays: inp.DefaultDays,
+		BatchSize:   inp.BatchSize,
+	})
+	if err != nil {
+		return PurgeExpiredMessagesOutput{}, err
+	}
+
+	rooms := make([]PurgedRoom, len(res.Rooms))
+	for i, r := range res.Rooms {
+		rooms[i] = PurgedRoom{RoomID: r.RoomID, Messages: r.Messages}
+	}
+	return PurgeExpiredMessagesOutput{
+		Messages:          res.Messages,
+		Attachments:       res.Attachments,
+		WebhookDeliveries: res.WebhookDeliveries,
+		Rooms:             rooms,
+	}, nil
+}
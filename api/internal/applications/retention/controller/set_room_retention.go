@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+)
+
+// SetRoomRetentionInput の RetentionDays が null の場合はインスタンスの既定の保存期間に戻す
+type SetRoomRetentionInput struct {
+	RetentionDays *int   `json:"retentionDays"`
+	RoomID        string `json:"-"`
+	AccountID     string `json:"-"`
+}
+
+type SetRoomRetentionController struct {
+	repo        repository.RetentionRepository
+	defaultDays int
+}
+
+func NewSetRoomRetentionController(repo repository.RetentionRepository, defaultDays int) *SetRoomRetentionController {
+	return &SetRoomRetentionController{repo, defaultDays}
+}
+
+func (c *SetRoomRetentionController) SetRoomRetention(ctx context.Context, inp SetRoomRetentionInput) (RoomRetention, error) {
+	var period *domain.RetentionPeriod
+	if inp.RetentionDays != nil {
+		p, err := domain.NewRetentionPeriod(*inp.RetentionDays)
+		if err != nil {
+			return RoomRetention{}, fmt.Errorf("bad retention days: %w", err)
+		}
+		period = &p
+	}
+
+	uc := usecase.NewSetRoomRetentionUsecase(c.repo)
+	if err := tracing.ExecuteNoOutput(ctx, "SetRoomRetentionUsecase", uc.Execute, usecase.SetRoomRetentionInput{
+		RoomID:    inp.RoomID,
+		AccountID: inp.AccountID,
+		Period:    period,
+	}); err != nil {
+		return RoomRetention{}, err
+	}
+	return newRoomRetention(inp.RetentionDays, c.defaultDays), nil
+}
@@ -0,0 +1,106 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/controller"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock implementations
+type mockRetentionRepository struct {
+	setRoomRetentionFunc func(ctx context.Context, inp repository.SetRoomRetentionInput) error
+}
+
+func (m *mockRetentionRepository) SetRoomRetention(ctx context.Context, inp repository.SetRoomRetentionInput) error {
+	if m.setRoomRetentionFunc != nil {
+		return m.setRoomRetentionFunc(ctx, inp)
+	}
+	return nil
+}
+
+func (m *mockRetentionRepository) PurgeExpiredMessages(ctx context.Context, inp repository.PurgeExpiredMessagesInput) (repository.PurgeExpiredMessagesOutput, error) {
+	return repository.PurgeExpiredMessagesOutput{}, nil
+}
+
+func (m *mockRetentionRepository) PurgeDeletedRooms(ctx context.Context, inp repository.PurgeDeletedRoomsInput) (repository.PurgeDeletedRoomsOutput, error) {
+	return repository.PurgeDeletedRoomsOutput{}, nil
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func TestSetRoomRetentionController_SetRoomRetention(t *testing.T) {
+	t.Parallel()
+
+	roomID := uuid.New().String()
+	accountID := uuid.New().String()
+
+	t.Run("ルームに指定した保存期間を返す", func(t *testing.T) {
+		t.Parallel()
+
+		c := controller.NewSetRoomRetentionController(&mockRetentionRepository{}, 90)
+		res, err := c.SetRoomRetention(t.Context(), controller.SetRoomRetentionInput{
+			RetentionDays: intPtr(30),
+			RoomID:        roomID,
+			AccountID:     accountID,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, controller.RoomRetention{RetentionDays: intPtr(30), EffectiveRetentionDays: intPtr(30)}, res)
+	})
+
+	t.Run("null を指定すると既定値を使う", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name        string
+			defaultDays int
+			want        *int
+		}{
+			{"default", 90, intPtr(90)},
+			{"no default", 0, nil},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				c := controller.NewSetRoomRetentionController(&mockRetentionRepository{}, tt.defaultDays)
+				res, err := c.SetRoomRetention(t.Context(), controller.SetRoomRetentionInput{
+					RoomID:    roomID,
+					AccountID: accountID,
+				})
+
+				require.NoError(t, err)
+				require.Nil(t, res.RetentionDays)
+				require.Equal(t, tt.want, res.EffectiveRetentionDays)
+			})
+		}
+	})
+
+	t.Run("範囲外の日数はエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockRetentionRepository{
+			setRoomRetentionFunc: func(ctx context.Context, inp repository.SetRoomRetentionInput) error {
+				t.Fatal("should not be called")
+				return nil
+			},
+		}
+
+		c := controller.NewSetRoomRetentionController(repo, 90)
+		_, err := c.SetRoomRetention(t.Context(), controller.SetRoomRetentionInput{
+			RetentionDays: intPtr(0),
+			RoomID:        roomID,
+			AccountID:     accountID,
+		})
+
+		require.ErrorIs(t, err, domain.ErrInvalidRetentionPeriod)
+	})
+}
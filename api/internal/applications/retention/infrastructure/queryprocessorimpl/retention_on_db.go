@@ -0,0 +1,42 @@
+package queryprocessorimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase/queryprocessor"
+	"github.com/quietsato/toy-small-chat/api/internal/db"
+)
+
+type RetentionQueryProcessorOnDB struct {
+	queries *db.Queries
+}
+
+func NewRetentionQueryProcessorOnDB(pool *pgxpool.Pool) *RetentionQueryProcessorOnDB {
+	return &RetentionQueryProcessorOnDB{
+		queries: db.New(pool),
+	}
+}
+
+// GetRoomRetention implements queryprocessor.RetentionQueryProcessor.
+func (q *RetentionQueryProcessorOnDB) GetRoomRetention(ctx context.Context, roomID uuid.UUID) (queryprocessor.GetRoomRetentionOutput, error) {
+	days, err := q.queries.GetRoomRetentionDays(ctx, roomID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return queryprocessor.GetRoomRetentionOutput{}, queryprocessor.ErrRoomNotFound
+	}
+	if err != nil {
+		return queryprocessor.GetRoomRetentionOutput{}, fmt.Errorf("failed to get retention days: %w", err)
+	}
+
+	if !days.Valid {
+		return queryprocessor.GetRoomRetentionOutput{}, nil
+	}
+	d := int(days.Int32)
+	return queryprocessor.GetRoomRetentionOutput{Days: &d}, nil
+}
+
+var _ queryprocessor.RetentionQueryProcessor = new(RetentionQueryProcessorOnDB)
@@ -0,0 +1,226 @@
+package repositoryimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/db"
+)
+
+func NewRetentionRepositoryOnDB(pool *pgxpool.Pool) *RetentionRepositoryOnDB {
+	return &RetentionRepositoryOnDB{pool}
+}
+
+type RetentionRepositoryOnDB struct {
+	pool *pgxpool.Pool
+}
+
+// SetRoomRetention implements repository.RetentionRepository.
+func (r *RetentionRepositoryOnDB) SetRoomRetention(ctx context.Context, inp repository.SetRoomRetentionInput) error {
+	return r.withTx(ctx, func(queries *db.Queries) error {
+		room, err := queries.GetRoomForUpdate(ctx, inp.RoomID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repository.ErrRoomNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get room: %w", err)
+		}
+		if room.CreatedBy != inp.AccountID {
+			return repository.ErrNotRoomOwner
+		}
+
+		days := pgtype.Int4{}
+		if inp.Days != nil {
+			days = pgtype.Int4{Int32: int32(*inp.Days), Valid: true}
+		}
+		if err := queries.SetRoomRetentionDays(ctx, db.SetRoomRetentionDaysParams{
+			RetentionDays: days,
+			ID:            inp.RoomID,
+		}); err != nil {
+			return fmt.Errorf("failed to set retention days: %w", err)
+		}
+		return nil
+	})
+}
+
+// PurgeExpiredMessages implements repository.RetentionRepository.
+//
+// 削除するメッセージを FOR UPDATE SKIP LOCKED でロックするため、複数のインスタンスが同時に実行しても
+// 同じメッセージを取り合わない。メッセージを参照するメンションやピン、添付ファイルも同じトランザクションで削除する。
+// 送信 Webhook の配信履歴も本文の複製を持つため、同じ保存期間を過ぎた送信済みのものを削除する
+func (r *RetentionRepositoryOnDB) PurgeExpiredMessages(ctx context.Context, inp repository.PurgeExpiredMessagesInput) (repository.PurgeExpiredMessagesOutput, error) {
+	var out repository.PurgeExpiredMessagesOutput
+	err := r.withTx(ctx, func(queries *db.Queries) error {
+		deliveries, err := queries.DeleteExpiredWebhookDeliveries(ctx, db.DeleteExpiredWebhookDeliveriesParams{
+			DefaultDays: int32(inp.DefaultDays),
+			LimitCount:  int32(inp.Limit),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+		}
+		out.WebhookDeliveries = int(deliveries)
+
+		rows, err := queries.LockExpiredMessages(ctx, db.LockExpiredMessagesParams{
+			DefaultDays: int32(inp.DefaultDays),
+			LimitCount:  int32(inp.Limit),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to lock expired messages: %w", err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		ids := make([]uuid.UUID, len(rows))
+		messages := make([]repository.PurgedMessage, len(rows))
+		for i, row := range rows {
+			ids[i] = row.ID
+			messages[i] = repository.PurgedMessage{ID: row.ID, RoomID: row.RoomID}
+		}
+
+		if err := queries.DeleteMentionsByMessageIDs(ctx, ids); err != nil {
+			return fmt.Errorf("failed to delete mentions: %w", err)
+		}
+		if err := queries.DeleteMessageMentionsByMessageIDs(ctx, ids); err != nil {
+			return fmt.Errorf("failed to delete message mentions: %w", err)
+		}
+		if err := queries.DeletePinnedMessagesByMessageIDs(ctx, ids); err != nil {
+			return fmt.Errorf("failed to delete pinned messages: %w", err)
+		}
+		// 送信済みの予約メッセージは本文の複製を持つため、投稿したメッセージと一緒に削除する
+		if err := queries.DeleteScheduledMessagesByMessageIDs(ctx, ids); err != nil {
+			return fmt.Errorf("failed to delete scheduled messages: %w", err)
+		}
+		attachments, err := queries.DeleteAttachmentsByMessageIDs(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("failed to delete attachments: %w", err)
+		}
+		if _, err := queries.DeleteMessagesByIDs(ctx, ids); err != nil {
+			return fmt.Errorf("failed to delete messages: %w", err)
+		}
+
+		var keys []string
+		for _, a := range attachments {
+			keys = append(keys, blobKeys(a.StorageKey, a.ThumbnailKey)...)
+		}
+
+		out.Messages = messages
+		out.Attachments = len(attachments)
+		out.BlobKeys = keys
+		return nil
+	})
+	if err != nil {
+		return repository.PurgeExpiredMessagesOutput{}, err
+	}
+	return out, nil
+}
+
+// PurgeDeletedRooms implements repository.RetentionRepository.
+//
+// 猶予期間を過ぎた論理削除済みのルームを、ルームを参照する行と一緒に 1 つのトランザクションで削除する。
+// 送信 Webhook の配信履歴は Webhook と一緒に削除される。受信 Webhook やスラッシュコマンドのボットアカウントは残す
+func (r *RetentionRepositoryOnDB) PurgeDeletedRooms(ctx context.Context, inp repository.PurgeDeletedRoomsInput) (repository.PurgeDeletedRoomsOutput, error) {
+	var out repository.PurgeDeletedRoomsOutput
+	err := r.withTx(ctx, func(queries *db.Queries) error {
+		ids, err := queries.LockPurgeableRooms(ctx, db.LockPurgeableRoomsParams{
+			GracePeriod: pgtype.Interval{Microseconds: inp.GracePeriod.Microseconds(), Valid: true},
+			LimitCount:  int32(inp.Limit),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to lock purgeable rooms: %w", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if err := queries.DeleteMentionsByRoomIDs(ctx, ids); err != nil {
+			return fmt.Errorf("failed to delete mentions: %w", err)
+		}
+		if err := queries.DeleteMessageMentionsByRoomIDs(ctx, ids); err != nil {
+			return fmt.Errorf("failed to delete message mentions: %w", err)
+		}
+		if err := queries.DeletePinnedMessagesByRoomIDs(ctx, ids); err != nil {
+			return fmt.Errorf("failed to delete pinned messages: %w", err)
+		}
+		if err := queries.DeleteScheduledMessagesByRoomIDs(ctx, ids); err != nil {
+			return fmt.Errorf("failed to delete scheduled messages: %w", err)
+		}
+		// メッセージに添付する前のファイルもルームを参照するため、メッセージではなくルームで削除する
+		attachments, err := queries.DeleteAttachmentsByRoomIDs(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("failed to delete attachments: %w", err)
+		}
+		messages, err := queries.DeleteMessagesByRoomIDs(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("failed to delete messages: %w", err)
+		}
+		if err := queries.DeleteNotificationSettingsByRoomIDs(ctx, ids); err != nil {
+			return fmt.Errorf("failed to delete notification settings: %w", err)
+		}
+		if err := queries.DeleteWebhooksByRoomIDs(ctx, ids); err != nil {
+			return fmt.Errorf("failed to delete webhooks: %w", err)
+		}
+		if err := queries.DeleteIncomingWebhooksByRoomIDs(ctx, ids); err != nil {
+			return fmt.Errorf("failed to delete incoming webhooks: %w", err)
+		}
+		if err := queries.DeleteSlashCommandsByRoomIDs(ctx, ids); err != nil {
+			return fmt.Errorf("failed to delete slash commands: %w", err)
+		}
+		if err := queries.DeleteRoomsByIDs(ctx, ids); err != nil {
+			return fmt.Errorf("failed to delete rooms: %w", err)
+		}
+
+		var keys []string
+		for _, a := range attachments {
+			keys = append(keys, blobKeys(a.StorageKey, a.ThumbnailKey)...)
+		}
+
+		out.RoomIDs = ids
+		out.Messages = int(messages)
+		out.Attachments = len(attachments)
+		out.BlobKeys = keys
+		return nil
+	})
+	if err != nil {
+		return repository.PurgeDeletedRoomsOutput{}, err
+	}
+	return out, nil
+}
+
+// blobKeys は添付ファイルの実体とサムネイルのキーを返す
+func blobKeys(storageKey string, thumbnailKey pgtype.Text) []string {
+	if !thumbnailKey.Valid {
+		return []string{storageKey}
+	}
+	return []string{storageKey, thumbnailKey.String}
+}
+
+func (r *RetentionRepositoryOnDB) withTx(ctx context.Context, fn func(queries *db.Queries) error) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rollback", slog.Any("err", err))
+		}
+	}()
+
+	if err := fn(db.New(r.pool).WithTx(tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
+
+var _ repository.RetentionRepository = new(RetentionRepositoryOnDB)
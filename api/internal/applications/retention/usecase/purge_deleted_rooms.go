@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase/service"
+)
+
+// PurgeDeletedRoomsUsecase は削除から猶予期間を過ぎたルームを完全に削除する
+type PurgeDeletedRoomsUsecase struct {
+	repo  repository.RetentionRepository
+	blobs service.BlobRemover
+}
+
+type PurgeDeletedRoomsInput struct {
+	GracePeriod time.Duration
+	BatchSize   int
+}
+
+// PurgeDeletedRoomsOutput の RoomIDs は削除したルームの ID の昇順
+type PurgeDeletedRoomsOutput struct {
+	RoomIDs     []string
+	Messages    int
+	Attachments int
+}
+
+func NewPurgeDeletedRoomsUsecase(repo repository.RetentionRepository, blobs service.BlobRemover) *PurgeDeletedRoomsUsecase {
+	return &PurgeDeletedRoomsUsecase{repo, blobs}
+}
+
+// Execute は猶予期間を過ぎたルームを最大 BatchSize 件、メッセージや添付ファイルなどと一緒に削除する
+//
+// 添付ファイルの実体はデータベースから削除した後に消す。消せなかった実体は参照されないまま残る
+func (u *PurgeDeletedRoomsUsecase) Execute(ctx context.Context, inp PurgeDeletedRoomsInput) (PurgeDeletedRoomsOutput, error) {
+	res, err := u.repo.PurgeDeletedRooms(ctx, repository.PurgeDeletedRoomsInput{
+		GracePeriod: inp.GracePeriod,
+		Limit:       inp.BatchSize,
+	})
+	if err != nil {
+		return PurgeDeletedRoomsOutput{}, fmt.Errorf("failed to purge deleted rooms: %w", err)
+	}
+
+	for _, key := range res.BlobKeys {
+		if err := u.blobs.Delete(ctx, key); err != nil {
+			slog.WarnContext(ctx, "failed to delete purged blob", slog.String("key", key), slog.Any("err", err))
+		}
+	}
+
+	ids := make([]string, len(res.RoomIDs))
+	for i, id := range res.RoomIDs {
+		ids[i] = id.String()
+	}
+	slices.Sort(ids)
+
+	return PurgeDeletedRoomsOutput{
+		RoomIDs:     ids,
+		Messages:    res.Messages,
+		Attachments: res.Attachments,
+	}, nil
+}
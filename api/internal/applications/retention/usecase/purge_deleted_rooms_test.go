@@ -0,0 +1,74 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase/repository"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurgeDeletedRoomsUsecase_Execute(t *testing.T) {
+	t.Parallel()
+
+	t.Run("猶予期間を渡してルームを削除し、添付ファイルの実体を消す", func(t *testing.T) {
+		t.Parallel()
+
+		roomA := uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+		roomB := uuid.MustParse("00000000-0000-0000-0000-00000000000b")
+		repo := &mockRetentionRepository{
+			purgeDeletedRoomsFunc: func(ctx context.Context, inp repository.PurgeDeletedRoomsInput) (repository.PurgeDeletedRoomsOutput, error) {
+				require.Equal(t, repository.PurgeDeletedRoomsInput{GracePeriod: 168 * time.Hour, Limit: 10}, inp)
+				return repository.PurgeDeletedRoomsOutput{
+					RoomIDs:     []uuid.UUID{roomB, roomA},
+					Messages:    5,
+					Attachments: 1,
+					BlobKeys:    []string{"a/file", "a/file.thumb"},
+				}, nil
+			},
+		}
+		var deleted []string
+		blobs := &mockBlobRemover{
+			deleteFunc: func(ctx context.Context, key string) error {
+				deleted = append(deleted, key)
+				return nil
+			},
+		}
+
+		out, err := usecase.NewPurgeDeletedRoomsUsecase(repo, blobs).Execute(t.Context(), usecase.PurgeDeletedRoomsInput{
+			GracePeriod: 168 * time.Hour,
+			BatchSize:   10,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, usecase.PurgeDeletedRoomsOutput{
+			RoomIDs:     []string{roomA.String(), roomB.String()},
+			Messages:    5,
+			Attachments: 1,
+		}, out)
+		require.Equal(t, []string{"a/file", "a/file.thumb"}, deleted)
+	})
+
+	t.Run("削除に失敗した場合は実体を消さずにエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockRetentionRepository{
+			purgeDeletedRoomsFunc: func(ctx context.Context, inp repository.PurgeDeletedRoomsInput) (repository.PurgeDeletedRoomsOutput, error) {
+				return repository.PurgeDeletedRoomsOutput{}, errors.New("db error")
+			},
+		}
+		blobs := &mockBlobRemover{
+			deleteFunc: func(ctx context.Context, key string) error {
+				t.Fatal("should not be called")
+				return nil
+			},
+		}
+
+		_, err := usecase.NewPurgeDeletedRoomsUsecase(repo, blobs).Execute(t.Context(), usecase.PurgeDeletedRoomsInput{BatchSize: 10})
+		require.Error(t, err)
+	})
+}
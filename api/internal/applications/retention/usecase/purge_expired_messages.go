@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase/service"
+)
+
+type PurgeExpiredMessagesUsecase struct {
+	repo  repository.RetentionRepository
+	blobs service.BlobRemover
+}
+
+// PurgeExpiredMessagesInput の DefaultDays が 0 以下の場合、保存期間を指定したルームのメッセージだけを削除する
+type PurgeExpiredMessagesInput struct {
+	DefaultDays int
+	BatchSize   int
+}
+
+// PurgeExpiredMessagesOutput の Rooms はメッセージを削除したルームごとの件数で、ルーム ID の順に並ぶ
+//
+// WebhookDeliveries は削除した送信 Webhook の配信履歴の件数
+type PurgeExpiredMessagesOutput struct {
+	Messages          int
+	Attachments       int
+	WebhookDeliveries int
+	Rooms             []PurgedRoom
+}
+
+type PurgedRoom struct {
+	RoomID   string
+	Messages int
+}
+
+func NewPurgeExpiredMessagesUsecase(repo repository.RetentionRepository, blobs service.BlobRemover) *PurgeExpiredMessagesUsecase {
+	return &PurgeExpiredMessagesUsecase{repo, blobs}
+}
+
+// Execute は保存期間を過ぎたメッセージと送信 Webhook の配信履歴をそれぞれ最大 BatchSize 件削除する
+//
+// 添付ファイルの実体はデータベースから削除した後に消す。消せなかった実体は参照されないまま残る
+func (u *PurgeExpiredMessagesUsecase) Execute(ctx context.Context, inp PurgeExpiredMessagesInput) (PurgeExpiredMessagesOutput, error) {
+	res, err := u.repo.PurgeExpiredMessages(ctx, repository.PurgeExpiredMessagesInput{
+		DefaultDays: inp.DefaultDays,
+		Limit:       inp.BatchSize,
+	})
+	if err != nil {
+		return PurgeExpiredMessagesOutput{}, fmt.Errorf("failed to purge expired messages: %w", err)
+	}
+
+	for _, key := range res.BlobKeys {
+		if err := u.blobs.Delete(ctx, key); err != nil {
+			slog.WarnContext(ctx, "failed to delete purged blob", slog.String("key", key), slog.Any("err", err))
+		}
+	}
+
+	counts := map[string]int{}
+	for _, m := range res.Messages {
+		counts[m.RoomID.String()]++
+	}
+	rooms := make([]PurgedRoom, 0, len(counts))
+	for roomID, n := range counts {
+		rooms = append(rooms, PurgedRoom{RoomID: roomID, Messages: n})
+	}
+	slices.SortFunc(rooms, func(a, b PurgedRoom) int {
+		return cmp.Compare(a.RoomID, b.RoomID)
+	})
+
+	return PurgeExpiredMessagesOutput{
+		Messages:          len(res.Messages),
+		Attachments:       res.Attachments,
+		WebhookDeliveries: res.WebhookDeliveries,
+		Rooms:             rooms,
+	}, nil
+}
@@ -0,0 +1,143 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase/repository"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRetentionRepository struct {
+	setRoomRetentionFunc     func(ctx context.Context, inp repository.SetRoomRetentionInput) error
+	purgeExpiredMessagesFunc func(ctx context.Context, inp repository.PurgeExpiredMessagesInput) (repository.PurgeExpiredMessagesOutput, error)
+	purgeDeletedRoomsFunc    func(ctx context.Context, inp repository.PurgeDeletedRoomsInput) (repository.PurgeDeletedRoomsOutput, error)
+}
+
+func (m *mockRetentionRepository) SetRoomRetention(ctx context.Context, inp repository.SetRoomRetentionInput) error {
+	if m.setRoomRetentionFunc != nil {
+		return m.setRoomRetentionFunc(ctx, inp)
+	}
+	return nil
+}
+
+func (m *mockRetentionRepository) PurgeExpiredMessages(ctx context.Context, inp repository.PurgeExpiredMessagesInput) (repository.PurgeExpiredMessagesOutput, error) {
+	if m.purgeExpiredMessagesFunc != nil {
+		return m.purgeExpiredMessagesFunc(ctx, inp)
+	}
+	return repository.PurgeExpiredMessagesOutput{}, nil
+}
+
+func (m *mockRetentionRepository) PurgeDeletedRooms(ctx context.Context, inp repository.PurgeDeletedRoomsInput) (repository.PurgeDeletedRoomsOutput, error) {
+	if m.purgeDeletedRoomsFunc != nil {
+		return m.purgeDeletedRoomsFunc(ctx, inp)
+	}
+	return repository.PurgeDeletedRoomsOutput{}, nil
+}
+
+type mockBlobRemover struct {
+	deleteFunc func(ctx context.Context, key string) error
+}
+
+func (m *mockBlobRemover) Delete(ctx context.Context, key string) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(ctx, key)
+	}
+	return nil
+}
+
+func TestPurgeExpiredMessagesUsecase_Execute(t *testing.T) {
+	t.Parallel()
+
+	t.Run("削除したメッセージをルームごとに数え、配信履歴の件数を返し、添付ファイルの実体を消す", func(t *testing.T) {
+		t.Parallel()
+
+		roomA := uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+		roomB := uuid.MustParse("00000000-0000-0000-0000-00000000000b")
+		repo := &mockRetentionRepository{
+			purgeExpiredMessagesFunc: func(ctx context.Context, inp repository.PurgeExpiredMessagesInput) (repository.PurgeExpiredMessagesOutput, error) {
+				require.Equal(t, repository.PurgeExpiredMessagesInput{DefaultDays: 90, Limit: 100}, inp)
+				return repository.PurgeExpiredMessagesOutput{
+					Messages: []repository.PurgedMessage{
+						{ID: uuid.New(), RoomID: roomB},
+						{ID: uuid.New(), RoomID: roomA},
+						{ID: uuid.New(), RoomID: roomB},
+					},
+					Attachments:       1,
+					WebhookDeliveries: 4,
+					BlobKeys:          []string{"a/file", "a/file.thumb"},
+				}, nil
+			},
+		}
+		var deleted []string
+		blobs := &mockBlobRemover{
+			deleteFunc: func(ctx context.Context, key string) error {
+				deleted = append(deleted, key)
+				return nil
+			},
+		}
+
+		out, err := usecase.NewPurgeExpiredMessagesUsecase(repo, blobs).Execute(t.Context(), usecase.PurgeExpiredMessagesInput{
+			DefaultDays: 90,
+			BatchSize:   100,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, usecase.PurgeExpiredMessagesOutput{
+			Messages:          3,
+			Attachments:       1,
+			WebhookDeliveries: 4,
+			Rooms: []usecase.PurgedRoom{
+				{RoomID: roomA.String(), Messages: 1},
+				{RoomID: roomB.String(), Messages: 2},
+			},
+		}, out)
+		require.Equal(t, []string{"a/file", "a/file.thumb"}, deleted)
+	})
+
+	t.Run("添付ファイルの実体を消せなくても削除は成功とする", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockRetentionRepository{
+			purgeExpiredMessagesFunc: func(ctx context.Context, inp repository.PurgeExpiredMessagesInput) (repository.PurgeExpiredMessagesOutput, error) {
+				return repository.PurgeExpiredMessagesOutput{
+					Messages:    []repository.PurgedMessage{{ID: uuid.New(), RoomID: uuid.New()}},
+					Attachments: 1,
+					BlobKeys:    []string{"a/file"},
+				}, nil
+			},
+		}
+		blobs := &mockBlobRemover{
+			deleteFunc: func(ctx context.Context, key string) error {
+				return errors.New("disk error")
+			},
+		}
+
+		out, err := usecase.NewPurgeExpiredMessagesUsecase(repo, blobs).Execute(t.Context(), usecase.PurgeExpiredMessagesInput{BatchSize: 100})
+
+		require.NoError(t, err)
+		require.Equal(t, 1, out.Messages)
+	})
+
+	t.Run("削除に失敗した場合は実体を消さずにエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockRetentionRepository{
+			purgeExpiredMessagesFunc: func(ctx context.Context, inp repository.PurgeExpiredMessagesInput) (repository.PurgeExpiredMessagesOutput, error) {
+				return repository.PurgeExpiredMessagesOutput{}, errors.New("db error")
+			},
+		}
+		blobs := &mockBlobRemover{
+			deleteFunc: func(ctx context.Context, key string) error {
+				t.Fatal("should not be called")
+				return nil
+			},
+		}
+
+		_, err := usecase.NewPurgeExpiredMessagesUsecase(repo, blobs).Execute(t.Context(), usecase.PurgeExpiredMessagesInput{BatchSize: 100})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,19 @@
+package queryprocessor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// GetRoomRetentionOutput の Days はルームに指定した保存期間。指定していない場合は nil
+type GetRoomRetentionOutput struct {
+	Days *int
+}
+
+var ErrRoomNotFound = errors.New("room not found")
+
+type RetentionQueryProcessor interface {
+	GetRoomRetention(ctx context.Context, roomID uuid.UUID) (GetRoomRetentionOutput, error)
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SetRoomRetentionInput の Days が nil の場合はインスタンスの既定の保存期間に戻す
+type SetRoomRetentionInput struct {
+	RoomID    uuid.UUID
+	AccountID uuid.UUID
+	Days      *int
+}
+
+// PurgeExpiredMessagesInput の DefaultDays は保存期間を指定していないルームに使う日数
+//
+// DefaultDays が 0 以下の場合、保存期間を指定していないルームのメッセージは削除しない
+type PurgeExpiredMessagesInput struct {
+	DefaultDays int
+	Limit       int
+}
+
+// PurgeExpiredMessagesOutput の BlobKeys は削除した添付ファイルの実体のキー
+//
+// 実体はコミットの後に削除するため、呼び出し側で消す。
+// WebhookDeliveries は保存期間を過ぎた送信 Webhook の配信履歴のうち削除した件数で、最大 Limit 件
+type PurgeExpiredMessagesOutput struct {
+	Messages          []PurgedMessage
+	Attachments       int
+	WebhookDeliveries int
+	BlobKeys          []string
+}
+
+type PurgedMessage struct {
+	ID     uuid.UUID
+	RoomID uuid.UUID
+}
+
+// PurgeDeletedRoomsInput の GracePeriod は削除したルームを復元できる期間で、これを過ぎたルームを最大 Limit 件削除する
+type PurgeDeletedRoomsInput struct {
+	GracePeriod time.Duration
+	Limit       int
+}
+
+// PurgeDeletedRoomsOutput の Messages, Attachments は削除したルームにあった件数
+//
+// BlobKeys は PurgeExpiredMessagesOutput と同じく、コミットの後に呼び出し側で消す
+type PurgeDeletedRoomsOutput struct {
+	RoomIDs     []uuid.UUID
+	Messages    int
+	Attachments int
+	BlobKeys    []string
+}
+
+var (
+	ErrRoomNotFound = errors.New("room not found")
+	ErrNotRoomOwner = errors.New("not room owner")
+)
+
+type RetentionRepository interface {
+	SetRoomRetention(ctx context.Context, inp SetRoomRetentionInput) error
+	PurgeExpiredMessages(ctx context.Context, inp PurgeExpiredMessagesInput) (PurgeExpiredMessagesOutput, error)
+	PurgeDeletedRooms(ctx context.Context, inp PurgeDeletedRoomsInput) (PurgeDeletedRoomsOutput, error)
+}
@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+)
+
+// BlobRemover は削除したメッセージの添付ファイルの実体を消す
+//
+// 既に存在しないキーを渡しても error を返さない
+type BlobRemover interface {
+	Delete(ctx context.Context, key string) error
+}
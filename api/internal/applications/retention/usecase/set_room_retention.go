@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+)
+
+type SetRoomRetentionUsecase struct {
+	repo repository.RetentionRepository
+}
+
+// SetRoomRetentionInput の Period が nil の場合はインスタンスの既定の保存期間に戻す
+type SetRoomRetentionInput struct {
+	RoomID    string
+	AccountID string
+	Period    *domain.RetentionPeriod
+}
+
+func NewSetRoomRetentionUsecase(repo repository.RetentionRepository) *SetRoomRetentionUsecase {
+	return &SetRoomRetentionUsecase{repo}
+}
+
+// Execute はルームの保存期間を変更する。変更できるのはルームの作成者のみ
+func (u *SetRoomRetentionUsecase) Execute(ctx context.Context, inp SetRoomRetentionInput) error {
+	roomID, err := uuid.Parse(inp.RoomID)
+	if err != nil {
+		return repository.ErrRoomNotFound
+	}
+	accountID, err := uuid.Parse(inp.AccountID)
+	if err != nil {
+		return fmt.Errorf("failed to parse account id: %w", err)
+	}
+
+	var days *int
+	if inp.Period != nil {
+		d := inp.Period.Days()
+		days = &d
+	}
+
+	if err := u.repo.SetRoomRetention(ctx, repository.SetRoomRetentionInput{
+		RoomID:    roomID,
+		AccountID: accountID,
+		Days:      days,
+	}); err != nil {
+		return fmt.Errorf("failed to set room retention: %w", err)
+	}
+	return nil
+}
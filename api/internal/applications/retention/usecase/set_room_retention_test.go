@@ -0,0 +1,84 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetRoomRetentionUsecase_Execute(t *testing.T) {
+	t.Parallel()
+
+	roomID := uuid.New()
+	accountID := uuid.New()
+
+	t.Run("ルームの保存期間を変更する", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockRetentionRepository{
+			setRoomRetentionFunc: func(ctx context.Context, inp repository.SetRoomRetentionInput) error {
+				require.Equal(t, roomID, inp.RoomID)
+				require.Equal(t, accountID, inp.AccountID)
+				require.NotNil(t, inp.Days)
+				require.Equal(t, 30, *inp.Days)
+				return nil
+			},
+		}
+
+		period, _ := domain.NewRetentionPeriod(30)
+		err := usecase.NewSetRoomRetentionUsecase(repo).Execute(t.Context(), usecase.SetRoomRetentionInput{
+			RoomID:    roomID.String(),
+			AccountID: accountID.String(),
+			Period:    &period,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("期間を指定しない場合は既定値に戻す", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockRetentionRepository{
+			setRoomRetentionFunc: func(ctx context.Context, inp repository.SetRoomRetentionInput) error {
+				require.Nil(t, inp.Days)
+				return nil
+			},
+		}
+
+		err := usecase.NewSetRoomRetentionUsecase(repo).Execute(t.Context(), usecase.SetRoomRetentionInput{
+			RoomID:    roomID.String(),
+			AccountID: accountID.String(),
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("作成者以外は変更できない", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockRetentionRepository{
+			setRoomRetentionFunc: func(ctx context.Context, inp repository.SetRoomRetentionInput) error {
+				return repository.ErrNotRoomOwner
+			},
+		}
+
+		err := usecase.NewSetRoomRetentionUsecase(repo).Execute(t.Context(), usecase.SetRoomRetentionInput{
+			RoomID:    roomID.String(),
+			AccountID: accountID.String(),
+		})
+		require.ErrorIs(t, err, repository.ErrNotRoomOwner)
+	})
+
+	t.Run("ルーム ID が不正な場合はルームが見つからない", func(t *testing.T) {
+		t.Parallel()
+
+		err := usecase.NewSetRoomRetentionUsecase(&mockRetentionRepository{}).Execute(t.Context(), usecase.SetRoomRetentionInput{
+			RoomID:    "invalid",
+			AccountID: accountID.String(),
+		})
+		require.ErrorIs(t, err, repository.ErrRoomNotFound)
+	})
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+)
+
+type ArchiveRoomInput struct {
+	RoomID    string
+	AccountID string
+}
+
+// ArchiveRoomOutput の Changed はアーカイブの状態が変わった場合に true
+type ArchiveRoomOutput struct {
+	Changed bool
+}
+
+type ArchiveRoomController struct {
+	repo repository.RoomRepository
+}
+
+func NewArchiveRoomController(repo repository.RoomRepository) *ArchiveRoomController {
+	return &ArchiveRoomController{repo}
+}
+
+func (c *ArchiveRoomController) ArchiveRoom(ctx context.Context, inp ArchiveRoomInput) (ArchiveRoomOutput, error) {
+	return c.setArchived(ctx, inp, true)
+}
+
+func (c *ArchiveRoomController) UnarchiveRoom(ctx context.Context, inp ArchiveRoomInput) (ArchiveRoomOutput, error) {
+	return c.setArchived(ctx, inp, false)
+}
+
+func (c *ArchiveRoomController) setArchived(ctx context.Context, inp ArchiveRoomInput, archived bool) (ArchiveRoomOutput, error) {
+	uc := usecase.NewArchiveRoomUsecase(c.repo)
+	res, err := tracing.Execute(ctx, "ArchiveRoomUsecase", uc.Execute, usecase.ArchiveRoomInput{
+		RoomID:    inp.RoomID,
+		AccountID: inp.AccountID,
+		Archived:  archived,
+	})
+	if err != nil {
+		return ArchiveRoomOutput{}, fmt.Errorf("failed to set room archived: %w", err)
+	}
+	return ArchiveRoomOutput{Changed: res.Changed}, nil
+}
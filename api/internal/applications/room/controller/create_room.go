@@ -6,6 +6,7 @@ import (
 
 	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase"
 	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
 )
 
 type CreateRoomInput struct {
@@ -13,7 +14,10 @@ type CreateRoomInput struct {
 	CreatedBy string `json:"-"`
 }
 
-type CreateRoomOutput struct{}
+type CreateRoomOutput struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
 
 type CreateRoomController struct {
 	repo repository.RoomRepository
@@ -25,7 +29,7 @@ func NewCreateRoomController(repo repository.RoomRepository) *CreateRoomControll
 
 func (c *CreateRoomController) CreateRoom(ctx context.Context, inp CreateRoomInput) (CreateRoomOutput, error) {
 	uc := usecase.NewCreateRoomUsecase(c.repo)
-	_, err := uc.Execute(ctx, usecase.CreateRoomInput{
+	res, err := tracing.Execute(ctx, "CreateRoomUsecase", uc.Execute, usecase.CreateRoomInput{
 		Name:      inp.Name,
 		CreatedBy: inp.CreatedBy,
 	})
@@ -33,5 +37,5 @@ func (c *CreateRoomController) CreateRoom(ctx context.Context, inp CreateRoomInp
 		return CreateRoomOutput{}, fmt.Errorf("failed to create room: %w", err)
 	}
 
-	return CreateRoomOutput{}, nil
+	return CreateRoomOutput{ID: res.ID, Name: res.Name.String()}, nil
 }
@@ -12,7 +12,12 @@ import (
 
 // Mock implementations
 type mockRoomRepository struct {
-	createRoomFunc func(ctx context.Context, inp repository.CreateRoomInput) (repository.CreateRoomOutput, error)
+	createRoomFunc      func(ctx context.Context, inp repository.CreateRoomInput) (repository.CreateRoomOutput, error)
+	updateRoomFunc      func(ctx context.Context, inp repository.UpdateRoomInput) (repository.UpdateRoomOutput, error)
+	setRoomArchivedFunc func(ctx context.Context, inp repository.SetRoomArchivedInput) (repository.SetRoomArchivedOutput, error)
+	deleteRoomFunc      func(ctx context.Context, inp repository.DeleteRoomInput) error
+	restoreRoomFunc     func(ctx context.Context, inp repository.RestoreRoomInput) error
+	updateSettingsFunc  func(ctx context.Context, inp repository.UpdateNotificationSettingsInput) error
 }
 
 func (m *mockRoomRepository) CreateRoom(ctx context.Context, inp repository.CreateRoomInput) (repository.CreateRoomOutput, error) {
@@ -22,6 +27,41 @@ func (m *mockRoomRepository) CreateRoom(ctx context.Context, inp repository.Crea
 	return repository.CreateRoomOutput{}, nil
 }
 
+func (m *mockRoomRepository) UpdateRoom(ctx context.Context, inp repository.UpdateRoomInput) (repository.UpdateRoomOutput, error) {
+	if m.updateRoomFunc != nil {
+		return m.updateRoomFunc(ctx, inp)
+	}
+	return repository.UpdateRoomOutput{}, nil
+}
+
+func (m *mockRoomRepository) SetRoomArchived(ctx context.Context, inp repository.SetRoomArchivedInput) (repository.SetRoomArchivedOutput, error) {
+	if m.setRoomArchivedFunc != nil {
+		return m.setRoomArchivedFunc(ctx, inp)
+	}
+	return repository.SetRoomArchivedOutput{}, nil
+}
+
+func (m *mockRoomRepository) DeleteRoom(ctx context.Context, inp repository.DeleteRoomInput) error {
+	if m.deleteRoomFunc != nil {
+		return m.deleteRoomFunc(ctx, inp)
+	}
+	return nil
+}
+
+func (m *mockRoomRepository) RestoreRoom(ctx context.Context, inp repository.RestoreRoomInput) error {
+	if m.restoreRoomFunc != nil {
+		return m.restoreRoomFunc(ctx, inp)
+	}
+	return nil
+}
+
+func (m *mockRoomRepository) UpdateNotificationSettings(ctx context.Context, inp repository.UpdateNotificationSettingsInput) error {
+	if m.updateSettingsFunc != nil {
+		return m.updateSettingsFunc(ctx, inp)
+	}
+	return nil
+}
+
 func TestCreateRoomController_CreateRoom(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+)
+
+// DeleteRoomInput の ByAdmin が true の場合は管理者による操作として作成者を確認せず、AccountID は使わない
+type DeleteRoomInput struct {
+	RoomID    string
+	AccountID string
+	ByAdmin   bool
+}
+
+type DeleteRoomOutput struct{}
+
+// DeleteRoomController はルームの削除と、猶予期間内の復元を行う
+type DeleteRoomController struct {
+	repo        repository.RoomRepository
+	gracePeriod time.Duration
+}
+
+func NewDeleteRoomController(repo repository.RoomRepository, gracePeriod time.Duration) *DeleteRoomController {
+	return &DeleteRoomController{repo, gracePeriod}
+}
+
+func (c *DeleteRoomController) DeleteRoom(ctx context.Context, inp DeleteRoomInput) (DeleteRoomOutput, error) {
+	uc := usecase.NewDeleteRoomUsecase(c.repo)
+	if _, err := tracing.Execute(ctx, "DeleteRoomUsecase", uc.Execute, usecase.DeleteRoomInput{
+		RoomID:    inp.RoomID,
+		AccountID: inp.AccountID,
+		ByAdmin:   inp.ByAdmin,
+	}); err != nil {
+		return DeleteRoomOutput{}, fmt.Errorf("failed to delete room: %w", err)
+	}
+	return DeleteRoomOutput{}, nil
+}
+
+func (c *DeleteRoomController) RestoreRoom(ctx context.Context, inp DeleteRoomInput) (DeleteRoomOutput, error) {
+	uc := usecase.NewRestoreRoomUsecase(c.repo, c.gracePeriod)
+	if _, err := tracing.Execute(ctx, "RestoreRoomUsecase", uc.Execute, usecase.RestoreRoomInput{
+		RoomID:    inp.RoomID,
+		AccountID: inp.AccountID,
+		ByAdmin:   inp.ByAdmin,
+	}); err != nil {
+		return DeleteRoomOutput{}, fmt.Errorf("failed to restore room: %w", err)
+	}
+	return DeleteRoomOutput{}, nil
+}
@@ -2,23 +2,68 @@ package controller
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/google/uuid"
 	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/queryprocessor"
 )
 
-type GetRoomsInput struct{}
+const (
+	defaultRoomsLimit = 50
+	maxRoomsLimit     = 100
+	maxRoomsQueryLen  = 127
+)
+
+var (
+	ErrInvalidPagination = errors.New("invalid pagination")
+	// ErrInvalidRoomsQuery は検索文字列、並び順、絞り込み条件が不正な場合に返す
+	ErrInvalidRoomsQuery = errors.New("invalid rooms query")
+)
+
+// GetRoomsInput の Sort, Filter は queryprocessor.RoomSort, queryprocessor.RoomFilter の値
+//
+// Sort が空の場合は最終アクティビティ順、Limit が 0 の場合はデフォルト件数を返す。
+// Cursor には前のページの NextCursor を指定する
+type GetRoomsInput struct {
+	AccountID string
+	Query     string
+	Sort      string
+	Filter    string
+	Cursor    string
+	Limit     int
+}
 
+// GetRoomsOutput の NextCursor は次のページがない場合に空
 type GetRoomsOutput struct {
-	Rooms []Room `json:"rooms"`
+	Rooms      []Room `json:"rooms"`
+	NextCursor string `json:"nextCursor,omitempty"`
 }
 
+// Room の ArchivedAt はアーカイブされている場合、LastMessage はメッセージがある場合のみ設定する
 type Room struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	CreatedBy string `json:"createdBy"`
-	CreatedAt string `json:"createdAt"`
-	UpdatedAt string `json:"updatedAt"`
+	ID             string               `json:"id"`
+	Name           string               `json:"name"`
+	Topic          string               `json:"topic"`
+	CreatedBy      string               `json:"createdBy"`
+	CreatedAt      string               `json:"createdAt"`
+	UpdatedAt      string               `json:"updatedAt"`
+	LastActivityAt string               `json:"lastActivityAt"`
+	MemberCount    int                  `json:"memberCount"`
+	LastMessage    *LastMessage         `json:"lastMessage,omitempty"`
+	Notification   NotificationSettings `json:"notification"`
+	Archived       bool                 `json:"archived"`
+	ArchivedAt     string               `json:"archivedAt,omitempty"`
+}
+
+type LastMessage struct {
+	Author  string `json:"author"`
+	Snippet string `json:"snippet"`
 }
 
 type GetRoomsController struct {
@@ -30,23 +75,129 @@ func NewGetRoomsController(query queryprocessor.RoomQueryProcessor) *GetRoomsCon
 }
 
 func (c *GetRoomsController) GetRooms(ctx context.Context, inp GetRoomsInput) (GetRoomsOutput, error) {
-	res, err := c.query.GetRooms(ctx, queryprocessor.GetRoomsInput{})
+	accountID, err := uuid.Parse(inp.AccountID)
+	if err != nil {
+		return GetRoomsOutput{}, fmt.Errorf("bad account id: %w", err)
+	}
+
+	query := strings.TrimSpace(inp.Query)
+	if utf8.RuneCountInString(query) > maxRoomsQueryLen {
+		return GetRoomsOutput{}, ErrInvalidRoomsQuery
+	}
+
+	sort := queryprocessor.RoomSort(inp.Sort)
+	switch sort {
+	case "":
+		sort = queryprocessor.RoomSortActivity
+	case queryprocessor.RoomSortName, queryprocessor.RoomSortCreated, queryprocessor.RoomSortActivity:
+	default:
+		return GetRoomsOutput{}, ErrInvalidRoomsQuery
+	}
+
+	filter := queryprocessor.RoomFilter(inp.Filter)
+	switch filter {
+	case queryprocessor.RoomFilterNone, queryprocessor.RoomFilterJoined, queryprocessor.RoomFilterCreated:
+	default:
+		return GetRoomsOutput{}, ErrInvalidRoomsQuery
+	}
+
+	limit := inp.Limit
+	if limit == 0 {
+		limit = defaultRoomsLimit
+	}
+	if limit < 0 || limit > maxRoomsLimit {
+		return GetRoomsOutput{}, ErrInvalidPagination
+	}
+
+	var after *queryprocessor.RoomCursor
+	if inp.Cursor != "" {
+		cursor, err := decodeRoomCursor(inp.Cursor, sort)
+		if err != nil {
+			return GetRoomsOutput{}, err
+		}
+		after = &cursor
+	}
+
+	// 1 件多く取得して次のページの有無を判定する
+	res, err := c.query.GetRooms(ctx, queryprocessor.GetRoomsInput{
+		AccountID: accountID,
+		Query:     query,
+		Sort:      sort,
+		Filter:    filter,
+		After:     after,
+		Limit:     limit + 1,
+	})
 	if err != nil {
 		return GetRoomsOutput{}, fmt.Errorf("failed to get rooms: %w", err)
 	}
 
+	var nextCursor string
+	if len(res.Rooms) > limit {
+		res.Rooms = res.Rooms[:limit]
+		nextCursor = encodeRoomCursor(res.Rooms[limit-1].Cursor, sort)
+	}
+
 	rooms := make([]Room, 0, len(res.Rooms))
 	for _, dto := range res.Rooms {
+		var lastMessage *LastMessage
+		if dto.LastMessage != nil {
+			lastMessage = &LastMessage{
+				Author:  dto.LastMessage.Author,
+				Snippet: dto.LastMessage.Snippet,
+			}
+		}
 		rooms = append(rooms, Room{
-			ID:        dto.ID,
-			Name:      dto.Name,
-			CreatedBy: dto.CreatedBy,
-			CreatedAt: dto.CreatedAt,
-			UpdatedAt: dto.UpdatedAt,
+			ID:             dto.ID,
+			Name:           dto.Name,
+			Topic:          dto.Topic,
+			CreatedBy:      dto.CreatedBy,
+			CreatedAt:      dto.CreatedAt,
+			UpdatedAt:      dto.UpdatedAt,
+			LastActivityAt: dto.LastActivityAt,
+			MemberCount:    dto.MemberCount,
+			LastMessage:    lastMessage,
+			Notification:   toNotificationSettings(dto.Notification),
+			Archived:       dto.ArchivedAt != "",
+			ArchivedAt:     dto.ArchivedAt,
 		})
 	}
 
 	return GetRoomsOutput{
-		Rooms: rooms,
+		Rooms:      rooms,
+		NextCursor: nextCursor,
 	}, nil
 }
+
+// roomCursor はクライアントに渡すカーソルの中身。並び順が変わった場合に検出できるよう Sort を含める
+type roomCursor struct {
+	Sort string    `json:"s"`
+	Name string    `json:"n,omitempty"`
+	Time time.Time `json:"t,omitzero"`
+	ID   uuid.UUID `json:"id"`
+}
+
+func encodeRoomCursor(c queryprocessor.RoomCursor, sort queryprocessor.RoomSort) string {
+	// 固定の型のため失敗しない
+	b, _ := json.Marshal(roomCursor{
+		Sort: string(sort),
+		Name: c.Name,
+		Time: c.Time,
+		ID:   c.ID,
+	})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeRoomCursor(s string, sort queryprocessor.RoomSort) (queryprocessor.RoomCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return queryprocessor.RoomCursor{}, ErrInvalidPagination
+	}
+	var c roomCursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return queryprocessor.RoomCursor{}, ErrInvalidPagination
+	}
+	if c.Sort != string(sort) {
+		return queryprocessor.RoomCursor{}, ErrInvalidPagination
+	}
+	return queryprocessor.RoomCursor{Name: c.Name, Time: c.Time, ID: c.ID}, nil
+}
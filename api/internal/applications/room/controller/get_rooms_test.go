@@ -3,7 +3,11 @@ package controller_test
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/quietsato/toy-small-chat/api/internal/applications/room/controller"
 	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/queryprocessor"
@@ -12,7 +16,8 @@ import (
 
 // Mock implementations
 type mockRoomQueryProcessor struct {
-	getRoomsFunc func(ctx context.Context, inp queryprocessor.GetRoomsInput) (queryprocessor.GetRoomsOutput, error)
+	getRoomsFunc                func(ctx context.Context, inp queryprocessor.GetRoomsInput) (queryprocessor.GetRoomsOutput, error)
+	getNotificationSettingsFunc func(ctx context.Context, inp queryprocessor.GetNotificationSettingsInput) (queryprocessor.GetNotificationSettingsOutput, error)
 }
 
 func (m *mockRoomQueryProcessor) GetRooms(ctx context.Context, inp queryprocessor.GetRoomsInput) (queryprocessor.GetRoomsOutput, error) {
@@ -22,6 +27,15 @@ func (m *mockRoomQueryProcessor) GetRooms(ctx context.Context, inp queryprocesso
 	return queryprocessor.GetRoomsOutput{}, nil
 }
 
+func (m *mockRoomQueryProcessor) GetNotificationSettings(ctx context.Context, inp queryprocessor.GetNotificationSettingsInput) (queryprocessor.GetNotificationSettingsOutput, error) {
+	if m.getNotificationSettingsFunc != nil {
+		return m.getNotificationSettingsFunc(ctx, inp)
+	}
+	return queryprocessor.GetNotificationSettingsOutput{}, nil
+}
+
+const accountID = "550e8400-e29b-41d4-a716-446655440000"
+
 func TestGetRoomsController_GetRooms(t *testing.T) {
 	t.Parallel()
 
@@ -40,11 +54,21 @@ func TestGetRoomsController_GetRooms(t *testing.T) {
 							UpdatedAt: "2024-01-01T00:00:00Z",
 						},
 						{
-							ID:        "room-2",
-							Name:      "Random",
-							CreatedBy: "user-2",
-							CreatedAt: "2024-01-02T00:00:00Z",
-							UpdatedAt: "2024-01-02T00:00:00Z",
+							ID:         "room-2",
+							Name:       "Random",
+							Topic:      "off topic",
+							CreatedBy:  "user-2",
+							CreatedAt:  "2024-01-02T00:00:00Z",
+							UpdatedAt:  "2024-01-03T00:00:00Z",
+							ArchivedAt: "2024-01-03T00:00:00Z",
+							LastMessage: &queryprocessor.LastMessageDTO{
+								Author:  "user-1",
+								Snippet: "see you",
+							},
+							Notification: queryprocessor.NotificationSettingsDTO{
+								Level:      "mentions",
+								MutedUntil: "2024-02-01T00:00:00Z",
+							},
 						},
 					},
 				}, nil
@@ -53,12 +77,119 @@ func TestGetRoomsController_GetRooms(t *testing.T) {
 
 		ctrl := controller.NewGetRoomsController(mockQP)
 
-		out, err := ctrl.GetRooms(t.Context(), controller.GetRoomsInput{})
+		out, err := ctrl.GetRooms(t.Context(), controller.GetRoomsInput{AccountID: accountID})
 
 		require.NoError(t, err)
 		require.Len(t, out.Rooms, 2)
 		require.Equal(t, "room-1", out.Rooms[0].ID)
 		require.Equal(t, "General", out.Rooms[0].Name)
+		require.False(t, out.Rooms[0].Archived)
+		require.Equal(t, "off topic", out.Rooms[1].Topic)
+		require.True(t, out.Rooms[1].Archived)
+		require.Equal(t, "2024-01-03T00:00:00Z", out.Rooms[1].ArchivedAt)
+		require.Nil(t, out.Rooms[0].LastMessage)
+		require.Equal(t, &controller.LastMessage{Author: "user-1", Snippet: "see you"}, out.Rooms[1].LastMessage)
+		require.Equal(t, controller.NotificationSettings{Level: "mentions", Muted: true, MutedUntil: "2024-02-01T00:00:00Z"}, out.Rooms[1].Notification)
+		require.Empty(t, out.NextCursor)
+	})
+
+	t.Run("デフォルトでは最終アクティビティ順で取得する", func(t *testing.T) {
+		t.Parallel()
+
+		mockQP := &mockRoomQueryProcessor{
+			getRoomsFunc: func(ctx context.Context, inp queryprocessor.GetRoomsInput) (queryprocessor.GetRoomsOutput, error) {
+				require.Equal(t, accountID, inp.AccountID.String())
+				require.Equal(t, queryprocessor.RoomSortActivity, inp.Sort)
+				require.Equal(t, queryprocessor.RoomFilterNone, inp.Filter)
+				require.Equal(t, "gen", inp.Query)
+				require.Nil(t, inp.After)
+				require.Equal(t, 51, inp.Limit)
+				return queryprocessor.GetRoomsOutput{}, nil
+			},
+		}
+
+		ctrl := controller.NewGetRoomsController(mockQP)
+
+		_, err := ctrl.GetRooms(t.Context(), controller.GetRoomsInput{
+			AccountID: accountID,
+			Query:     " gen ",
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("次のページのカーソルで続きを取得できる", func(t *testing.T) {
+		t.Parallel()
+
+		last := queryprocessor.RoomCursor{
+			Time: time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC),
+			ID:   uuid.MustParse("8481027d-d6f6-402f-ae6d-98571e8f6496"),
+		}
+		mockQP := &mockRoomQueryProcessor{
+			getRoomsFunc: func(ctx context.Context, inp queryprocessor.GetRoomsInput) (queryprocessor.GetRoomsOutput, error) {
+				if inp.After != nil {
+					require.True(t, last.Time.Equal(inp.After.Time))
+					require.Equal(t, last.ID, inp.After.ID)
+					return queryprocessor.GetRoomsOutput{Rooms: []queryprocessor.RoomDTO{{ID: "room-3"}}}, nil
+				}
+				require.Equal(t, 3, inp.Limit)
+				return queryprocessor.GetRoomsOutput{Rooms: []queryprocessor.RoomDTO{
+					{ID: "room-1"},
+					{ID: "room-2", Cursor: last},
+					{ID: "room-3"},
+				}}, nil
+			},
+		}
+
+		ctrl := controller.NewGetRoomsController(mockQP)
+
+		first, err := ctrl.GetRooms(t.Context(), controller.GetRoomsInput{
+			AccountID: accountID,
+			Sort:      "created",
+			Limit:     2,
+		})
+		require.NoError(t, err)
+		require.Len(t, first.Rooms, 2)
+		require.NotEmpty(t, first.NextCursor)
+
+		second, err := ctrl.GetRooms(t.Context(), controller.GetRoomsInput{
+			AccountID: accountID,
+			Sort:      "created",
+			Cursor:    first.NextCursor,
+			Limit:     2,
+		})
+		require.NoError(t, err)
+		require.Len(t, second.Rooms, 1)
+		require.Empty(t, second.NextCursor)
+
+		// 並び順が異なるカーソルは受け付けない
+		_, err = ctrl.GetRooms(t.Context(), controller.GetRoomsInput{
+			AccountID: accountID,
+			Sort:      "name",
+			Cursor:    first.NextCursor,
+		})
+		require.ErrorIs(t, err, controller.ErrInvalidPagination)
+	})
+
+	t.Run("不正な入力の場合はエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := controller.NewGetRoomsController(&mockRoomQueryProcessor{})
+
+		for _, tt := range []struct {
+			name string
+			inp  controller.GetRoomsInput
+			err  error
+		}{
+			{"不正な並び順", controller.GetRoomsInput{AccountID: accountID, Sort: "size"}, controller.ErrInvalidRoomsQuery},
+			{"不正な絞り込み条件", controller.GetRoomsInput{AccountID: accountID, Filter: "all"}, controller.ErrInvalidRoomsQuery},
+			{"長すぎる検索文字列", controller.GetRoomsInput{AccountID: accountID, Query: strings.Repeat("a", 128)}, controller.ErrInvalidRoomsQuery},
+			{"上限を超える件数", controller.GetRoomsInput{AccountID: accountID, Limit: 101}, controller.ErrInvalidPagination},
+			{"不正なカーソル", controller.GetRoomsInput{AccountID: accountID, Cursor: "!!"}, controller.ErrInvalidPagination},
+		} {
+			_, err := ctrl.GetRooms(t.Context(), tt.inp)
+			require.ErrorIs(t, err, tt.err, tt.name)
+		}
 	})
 
 	t.Run("空のルーム一覧", func(t *testing.T) {
@@ -74,7 +205,7 @@ func TestGetRoomsController_GetRooms(t *testing.T) {
 
 		ctrl := controller.NewGetRoomsController(mockQP)
 
-		out, err := ctrl.GetRooms(t.Context(), controller.GetRoomsInput{})
+		out, err := ctrl.GetRooms(t.Context(), controller.GetRoomsInput{AccountID: accountID})
 
 		require.NoError(t, err)
 		require.Empty(t, out.Rooms)
@@ -91,7 +222,7 @@ func TestGetRoomsController_GetRooms(t *testing.T) {
 
 		ctrl := controller.NewGetRoomsController(mockQP)
 
-		_, err := ctrl.GetRooms(t.Context(), controller.GetRoomsInput{})
+		_, err := ctrl.GetRooms(t.Context(), controller.GetRoomsInput{AccountID: accountID})
 
 		require.Error(t, err)
 	})
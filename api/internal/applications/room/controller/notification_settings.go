@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/queryprocessor"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+)
+
+// NotificationSettings の MutedUntil はミュート中の場合のみ設定する
+type NotificationSettings struct {
+	Level      string `json:"level"`
+	Muted      bool   `json:"muted"`
+	MutedUntil string `json:"mutedUntil,omitempty"`
+}
+
+func toNotificationSettings(dto queryprocessor.NotificationSettingsDTO) NotificationSettings {
+	return NotificationSettings{
+		Level:      dto.Level,
+		Muted:      dto.MutedUntil != "",
+		MutedUntil: dto.MutedUntil,
+	}
+}
+
+type GetNotificationSettingsInput struct {
+	RoomID    string
+	AccountID string
+}
+
+type GetNotificationSettingsController struct {
+	query queryprocessor.RoomQueryProcessor
+}
+
+func NewGetNotificationSettingsController(query queryprocessor.RoomQueryProcessor) *GetNotificationSettingsController {
+	return &GetNotificationSettingsController{query}
+}
+
+func (c *GetNotificationSettingsController) GetNotificationSettings(ctx context.Context, inp GetNotificationSettingsInput) (NotificationSettings, error) {
+	roomID, err := uuid.Parse(inp.RoomID)
+	if err != nil {
+		return NotificationSettings{}, queryprocessor.ErrRoomNotFound
+	}
+	accountID, err := uuid.Parse(inp.AccountID)
+	if err != nil {
+		return NotificationSettings{}, fmt.Errorf("bad account id: %w", err)
+	}
+
+	res, err := c.query.GetNotificationSettings(ctx, queryprocessor.GetNotificationSettingsInput{
+		RoomID:    roomID,
+		AccountID: accountID,
+	})
+	if err != nil {
+		return NotificationSettings{}, fmt.Errorf("failed to get notification settings: %w", err)
+	}
+
+	return toNotificationSettings(res.Settings), nil
+}
+
+// UpdateNotificationSettingsInput の MutedUntil を省略するとミュートを解除する
+type UpdateNotificationSettingsInput struct {
+	RoomID     string     `json:"-"`
+	AccountID  string     `json:"-"`
+	Level      string     `json:"level"`
+	MutedUntil *time.Time `json:"mutedUntil"`
+}
+
+type UpdateNotificationSettingsController struct {
+	repo repository.RoomRepository
+}
+
+func NewUpdateNotificationSettingsController(repo repository.RoomRepository) *UpdateNotificationSettingsController {
+	return &UpdateNotificationSettingsController{repo}
+}
+
+func (c *UpdateNotificationSettingsController) UpdateNotificationSettings(ctx context.Context, inp UpdateNotificationSettingsInput) (NotificationSettings, error) {
+	uc := usecase.NewUpdateNotificationSettingsUsecase(c.repo)
+	res, err := tracing.Execute(ctx, "UpdateNotificationSettingsUsecase", uc.Execute, usecase.UpdateNotificationSettingsInput{
+		RoomID:     inp.RoomID,
+		AccountID:  inp.AccountID,
+		Level:      inp.Level,
+		MutedUntil: inp.MutedUntil,
+	})
+	if err != nil {
+		return NotificationSettings{}, fmt.Errorf("failed to update notification settings: %w", err)
+	}
+
+	settings := NotificationSettings{Level: res.Settings.Level().String()}
+	if !res.Settings.MutedUntil().IsZero() {
+		settings.Muted = true
+		settings.MutedUntil = res.Settings.MutedUntil().Format(time.RFC3339)
+	}
+	return settings, nil
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+)
+
+// UpdateRoomInput の Name, Topic は省略した場合に変更しない
+type UpdateRoomInput struct {
+	RoomID    string  `json:"-"`
+	AccountID string  `json:"-"`
+	Name      *string `json:"name"`
+	Topic     *string `json:"topic"`
+}
+
+// UpdateRoomOutput の Events は変更内容を表すイベントで、リポジトリが更新と同じトランザクションでタイムラインに記録したもの
+type UpdateRoomOutput struct {
+	ID     string               `json:"id"`
+	Name   string               `json:"name"`
+	Topic  string               `json:"topic"`
+	Events []domain.SystemEvent `json:"-"`
+}
+
+type UpdateRoomController struct {
+	repo repository.RoomRepository
+}
+
+func NewUpdateRoomController(repo repository.RoomRepository) *UpdateRoomController {
+	return &UpdateRoomController{repo}
+}
+
+func (c *UpdateRoomController) UpdateRoom(ctx context.Context, inp UpdateRoomInput) (UpdateRoomOutput, error) {
+	uc := usecase.NewUpdateRoomUsecase(c.repo)
+	res, err := tracing.Execute(ctx, "UpdateRoomUsecase", uc.Execute, usecase.UpdateRoomInput{
+		RoomID:    inp.RoomID,
+		AccountID: inp.AccountID,
+		Name:      inp.Name,
+		Topic:     inp.Topic,
+	})
+	if err != nil {
+		return UpdateRoomOutput{}, fmt.Errorf("failed to update room: %w", err)
+	}
+
+	var events []domain.SystemEvent
+	if res.Name != res.PreviousName {
+		events = append(events, domain.NewRoomRenamedEvent(res.PreviousName, res.Name))
+	}
+	if res.Topic != res.PreviousTopic {
+		events = append(events, domain.NewRoomTopicChangedEvent(res.Topic))
+	}
+
+	return UpdateRoomOutput{
+		ID:     inp.RoomID,
+		Name:   res.Name.String(),
+		Topic:  res.Topic.String(),
+		Events: events,
+	}, nil
+}
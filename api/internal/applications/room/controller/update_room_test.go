@@ -0,0 +1,104 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/room/controller"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateRoomController_UpdateRoom(t *testing.T) {
+	t.Parallel()
+
+	const roomID = "8481027d-d6f6-402f-ae6d-98571e8f6496"
+	const accountID = "550e8400-e29b-41d4-a716-446655440000"
+
+	t.Run("変更された項目ごとにイベントを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockRoomRepository{
+			updateRoomFunc: func(ctx context.Context, inp repository.UpdateRoomInput) (repository.UpdateRoomOutput, error) {
+				return repository.UpdateRoomOutput{
+					PreviousName:  "old",
+					Name:          "new",
+					PreviousTopic: "",
+					Topic:         "topic",
+				}, nil
+			},
+		}
+
+		name, topic := "new", "topic"
+		ctrl := controller.NewUpdateRoomController(mockRepo)
+		out, err := ctrl.UpdateRoom(t.Context(), controller.UpdateRoomInput{
+			RoomID:    roomID,
+			AccountID: accountID,
+			Name:      &name,
+			Topic:     &topic,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, roomID, out.ID)
+		require.Equal(t, "new", out.Name)
+		require.Equal(t, "topic", out.Topic)
+		require.Len(t, out.Events, 2)
+		require.Equal(t, domain.SystemEventRoomRenamed, out.Events[0].Type())
+		require.Equal(t, "old", out.Events[0].PreviousRoomName().String())
+		require.Equal(t, domain.SystemEventRoomTopicChanged, out.Events[1].Type())
+	})
+
+	t.Run("値が変わらない場合はイベントを返さない", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockRoomRepository{
+			updateRoomFunc: func(ctx context.Context, inp repository.UpdateRoomInput) (repository.UpdateRoomOutput, error) {
+				return repository.UpdateRoomOutput{PreviousName: "same", Name: "same"}, nil
+			},
+		}
+
+		name := "same"
+		ctrl := controller.NewUpdateRoomController(mockRepo)
+		out, err := ctrl.UpdateRoom(t.Context(), controller.UpdateRoomInput{
+			RoomID:    roomID,
+			AccountID: accountID,
+			Name:      &name,
+		})
+
+		require.NoError(t, err)
+		require.Empty(t, out.Events)
+	})
+
+	t.Run("リポジトリエラー時にエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockRoomRepository{
+			updateRoomFunc: func(ctx context.Context, inp repository.UpdateRoomInput) (repository.UpdateRoomOutput, error) {
+				return repository.UpdateRoomOutput{}, repository.ErrNotRoomOwner
+			},
+		}
+
+		name := "room"
+		ctrl := controller.NewUpdateRoomController(mockRepo)
+		_, err := ctrl.UpdateRoom(t.Context(), controller.UpdateRoomInput{
+			RoomID:    roomID,
+			AccountID: accountID,
+			Name:      &name,
+		})
+
+		require.ErrorIs(t, err, repository.ErrNotRoomOwner)
+	})
+}
+
+func TestNewUpdateRoomController(t *testing.T) {
+	t.Parallel()
+
+	t.Run("正しく初期化される", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := controller.NewUpdateRoomController(&mockRoomRepository{})
+
+		require.NotNil(t, ctrl)
+	})
+}
@@ -14,4 +14,10 @@ func (m *MockRoomQueryProcessor) GetRooms(ctx context.Context, inp queryprocesso
 	}, nil
 }
 
+func (m *MockRoomQueryProcessor) GetNotificationSettings(ctx context.Context, inp queryprocessor.GetNotificationSettingsInput) (queryprocessor.GetNotificationSettingsOutput, error) {
+	return queryprocessor.GetNotificationSettingsOutput{
+		Settings: queryprocessor.NotificationSettingsDTO{Level: "all"},
+	}, nil
+}
+
 var _ queryprocessor.RoomQueryProcessor = new(MockRoomQueryProcessor)
@@ -2,11 +2,17 @@ package queryprocessorimpl
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/queryprocessor"
 	"github.com/quietsato/toy-small-chat/api/internal/db"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
 )
 
 type RoomQueryProcessorOnDB struct {
@@ -19,21 +25,69 @@ func NewRoomQueryProcessorOnDB(pool *pgxpool.Pool) *RoomQueryProcessorOnDB {
 	}
 }
 
+// lastMessageSnippetLength は最新メッセージのプレビューの最大文字数
+const lastMessageSnippetLength = 100
+
+// likeEscaper は LIKE のパターンとして解釈される文字をエスケープする
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 // GetRooms implements queryprocessor.RoomQueryProcessor.
 func (r *RoomQueryProcessorOnDB) GetRooms(ctx context.Context, inp queryprocessor.GetRoomsInput) (queryprocessor.GetRoomsOutput, error) {
-	rows, err := r.queries.GetRooms(ctx)
+	params := db.GetRoomsParams{
+		Query:      inp.Query,
+		NamePrefix: likeEscaper.Replace(inp.Query),
+		Filter:     string(inp.Filter),
+		AccountID:  inp.AccountID,
+		Sort:       string(inp.Sort),
+		LimitCount: int32(inp.Limit),
+	}
+	if inp.After != nil {
+		params.HasCursor = true
+		params.CursorName = inp.After.Name
+		params.CursorTime = pgtype.Timestamp{Time: inp.After.Time, Valid: true}
+		params.CursorID = inp.After.ID
+	}
+
+	rows, err := r.queries.GetRooms(ctx, params)
 	if err != nil {
 		return queryprocessor.GetRoomsOutput{}, fmt.Errorf("failed to get rooms: %w", err)
 	}
 
 	rooms := make([]queryprocessor.RoomDTO, len(rows))
 	for i, row := range rows {
+		cursor := queryprocessor.RoomCursor{ID: row.ID}
+		switch inp.Sort {
+		case queryprocessor.RoomSortName:
+			cursor.Name = row.Name
+		case queryprocessor.RoomSortCreated:
+			cursor.Time = row.CreatedAt.Time
+		default:
+			cursor.Time = row.LastActivityAt.Time
+		}
+
+		var lastMessage *queryprocessor.LastMessageDTO
+		if row.LastMessageContent.Valid {
+			// 保存済みの本文は検証を通っているため変換に失敗しない
+			content, _ := domain.NewMessageContent(row.LastMessageContent.String)
+			lastMessage = &queryprocessor.LastMessageDTO{
+				Author:  row.LastMessageAuthorName.String,
+				Snippet: content.Snippet(lastMessageSnippetLength),
+			}
+		}
+
 		rooms[i] = queryprocessor.RoomDTO{
-			ID:        row.ID.String(),
-			Name:      row.Name,
-			CreatedBy: row.CreatedBy.String(),
-			CreatedAt: row.CreatedAt.Time.Format(""),
-			UpdatedAt: row.CreatedAt.Time.Format(""),
+			ID:             row.ID.String(),
+			Name:           row.Name,
+			Topic:          row.Topic,
+			CreatedBy:      row.CreatedBy.String(),
+			CreatedAt:      formatTimestamp(row.CreatedAt),
+			UpdatedAt:      formatTimestamp(row.UpdatedAt),
+			ArchivedAt:     formatTimestamp(row.ArchivedAt),
+			LastActivityAt: formatTimestamp(row.LastActivityAt),
+			MemberCount:    int(row.MemberCount),
+			LastMessage:    lastMessage,
+			Notification:   toNotificationSettings(row.NotificationLevel, row.NotificationMutedUntil),
+			Cursor:         cursor,
 		}
 	}
 
@@ -41,3 +95,40 @@ func (r *RoomQueryProcessorOnDB) GetRooms(ctx context.Context, inp queryprocesso
 		Rooms: rooms,
 	}, nil
 }
+
+// GetNotificationSettings implements queryprocessor.RoomQueryProcessor.
+func (r *RoomQueryProcessorOnDB) GetNotificationSettings(ctx context.Context, inp queryprocessor.GetNotificationSettingsInput) (queryprocessor.GetNotificationSettingsOutput, error) {
+	row, err := r.queries.GetNotificationSettings(ctx, db.GetNotificationSettingsParams{
+		AccountID: inp.AccountID,
+		RoomID:    inp.RoomID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return queryprocessor.GetNotificationSettingsOutput{}, queryprocessor.ErrRoomNotFound
+	}
+	if err != nil {
+		return queryprocessor.GetNotificationSettingsOutput{}, fmt.Errorf("failed to get notification settings: %w", err)
+	}
+
+	return queryprocessor.GetNotificationSettingsOutput{
+		Settings: toNotificationSettings(row.Level, row.MutedUntil),
+	}, nil
+}
+
+// toNotificationSettings は設定を保存していない場合にデフォルトの設定を返す
+func toNotificationSettings(level pgtype.Text, mutedUntil pgtype.Timestamp) queryprocessor.NotificationSettingsDTO {
+	settings := queryprocessor.NotificationSettingsDTO{
+		Level:      domain.DefaultNotificationSettings.Level().String(),
+		MutedUntil: formatTimestamp(mutedUntil),
+	}
+	if level.Valid {
+		settings.Level = level.String
+	}
+	return settings
+}
+
+func formatTimestamp(t pgtype.Timestamp) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.Format(time.RFC3339)
+}
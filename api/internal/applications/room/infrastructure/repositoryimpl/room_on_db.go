@@ -2,13 +2,18 @@ package repositoryimpl
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
+	messagerepositoryimpl "github.com/quietsato/toy-small-chat/api/internal/applications/message/infrastructure/repositoryimpl"
 	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/repository"
 	"github.com/quietsato/toy-small-chat/api/internal/db"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
 )
 
 func NewRoomRepositoryOnDB(pool *pgxpool.Pool) *RoomRepositoryOnDB {
@@ -21,6 +26,16 @@ type RoomRepositoryOnDB struct {
 
 // CreateRoom implements repository.RoomRepository.
 func (r *RoomRepositoryOnDB) CreateRoom(ctx context.Context, inp repository.CreateRoomInput) (repository.CreateRoomOutput, error) {
+	createdBy, err := uuid.Parse(inp.CreatedBy)
+	if err != nil {
+		return repository.CreateRoomOutput{}, fmt.Errorf("failed to parse account id: %w", err)
+	}
+	// 名前は usecase で検証済み
+	name, err := domain.NewRoomName(inp.Name)
+	if err != nil {
+		return repository.CreateRoomOutput{}, err
+	}
+
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return repository.CreateRoomOutput{}, fmt.Errorf("failed to begin tx: %w", err)
@@ -32,19 +47,232 @@ func (r *RoomRepositoryOnDB) CreateRoom(ctx context.Context, inp repository.Crea
 	}()
 
 	queries := db.New(r.pool).WithTx(tx)
-	err = queries.CreateRoom(ctx, db.CreateRoomParams{
+	id, err := queries.CreateRoom(ctx, db.CreateRoomParams{
 		Name:      inp.Name,
-		CreatedBy: uuid.MustParse(inp.CreatedBy),
+		CreatedBy: createdBy,
 	})
 	if err != nil {
 		return repository.CreateRoomOutput{}, fmt.Errorf("failed to query: %w", err)
 	}
 
+	if err := messagerepositoryimpl.CreateSystemMessage(ctx, queries, id, createdBy, domain.NewRoomCreatedEvent(name)); err != nil {
+		return repository.CreateRoomOutput{}, err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return repository.CreateRoomOutput{}, fmt.Errorf("failed to commit: %w", err)
 	}
 
-	return repository.CreateRoomOutput{}, nil
+	return repository.CreateRoomOutput{ID: id.String()}, nil
+}
+
+// UpdateRoom implements repository.RoomRepository.
+func (r *RoomRepositoryOnDB) UpdateRoom(ctx context.Context, inp repository.UpdateRoomInput) (repository.UpdateRoomOutput, error) {
+	var out repository.UpdateRoomOutput
+	err := r.withOwnerLock(ctx, inp.RoomID, inp.AccountID, func(queries *db.Queries, room db.GetRoomForUpdateRow) error {
+		if room.ArchivedAt.Valid {
+			return repository.ErrRoomArchived
+		}
+
+		out = repository.UpdateRoomOutput{
+			PreviousName:  room.Name,
+			Name:          room.Name,
+			PreviousTopic: room.Topic,
+			Topic:         room.Topic,
+		}
+		if inp.Name != nil {
+			out.Name = *inp.Name
+		}
+		if inp.Topic != nil {
+			out.Topic = *inp.Topic
+		}
+
+		if err := queries.UpdateRoom(ctx, db.UpdateRoomParams{
+			ID:    inp.RoomID,
+			Name:  out.Name,
+			Topic: out.Topic,
+		}); err != nil {
+			return fmt.Errorf("failed to update room: %w", err)
+		}
+
+		// 保存済みの値と usecase で検証済みの値は変換に失敗しない
+		var events []domain.SystemEvent
+		if out.Name != out.PreviousName {
+			previous, _ := domain.NewRoomName(out.PreviousName)
+			name, _ := domain.NewRoomName(out.Name)
+			events = append(events, domain.NewRoomRenamedEvent(previous, name))
+		}
+		if out.Topic != out.PreviousTopic {
+			topic, _ := domain.NewRoomTopic(out.Topic)
+			events = append(events, domain.NewRoomTopicChangedEvent(topic))
+		}
+		for _, event := range events {
+			if err := messagerepositoryimpl.CreateSystemMessage(ctx, queries, inp.RoomID, inp.AccountID, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return out, err
+}
+
+// SetRoomArchived implements repository.RoomRepository.
+//
+// 既に指定の状態であれば何もしない
+func (r *RoomRepositoryOnDB) SetRoomArchived(ctx context.Context, inp repository.SetRoomArchivedInput) (repository.SetRoomArchivedOutput, error) {
+	var out repository.SetRoomArchivedOutput
+	err := r.withOwnerLock(ctx, inp.RoomID, inp.AccountID, func(queries *db.Queries, room db.GetRoomForUpdateRow) error {
+		if room.ArchivedAt.Valid == inp.Archived {
+			return nil
+		}
+
+		if err := queries.SetRoomArchived(ctx, db.SetRoomArchivedParams{
+			Archived: inp.Archived,
+			ID:       inp.RoomID,
+		}); err != nil {
+			return fmt.Errorf("failed to set room archived: %w", err)
+		}
+
+		event := domain.NewRoomUnarchivedEvent()
+		if inp.Archived {
+			event = domain.NewRoomArchivedEvent()
+		}
+		if err := messagerepositoryimpl.CreateSystemMessage(ctx, queries, inp.RoomID, inp.AccountID, event); err != nil {
+			return err
+		}
+		out.Changed = true
+		return nil
+	})
+	return out, err
+}
+
+// DeleteRoom implements repository.RoomRepository.
+//
+// ルームは論理削除され、猶予期間の間は RestoreRoom で復元できる。
+// 管理者はアカウントを持たずシステムメッセージの投稿者にできないため、管理者による削除はタイムラインに記録しない
+func (r *RoomRepositoryOnDB) DeleteRoom(ctx context.Context, inp repository.DeleteRoomInput) error {
+	deleteRoom := func(queries *db.Queries, _ db.GetRoomForUpdateRow) error {
+		if err := queries.SoftDeleteRoom(ctx, inp.RoomID); err != nil {
+			return fmt.Errorf("failed to delete room: %w", err)
+		}
+		if inp.ByAdmin {
+			return nil
+		}
+		return messagerepositoryimpl.CreateSystemMessage(ctx, queries, inp.RoomID, inp.AccountID, domain.NewRoomDeletedEvent())
+	}
+	if inp.ByAdmin {
+		return r.withRoomLock(ctx, inp.RoomID, deleteRoom)
+	}
+	return r.withOwnerLock(ctx, inp.RoomID, inp.AccountID, deleteRoom)
+}
+
+// RestoreRoom implements repository.RoomRepository.
+//
+// DeleteRoom と同じく、管理者による復元はタイムラインに記録しない
+func (r *RoomRepositoryOnDB) RestoreRoom(ctx context.Context, inp repository.RestoreRoomInput) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rollback", slog.Any("err", err))
+		}
+	}()
+
+	queries := db.New(r.pool).WithTx(tx)
+
+	ownerID, err := queries.GetDeletedRoomForUpdate(ctx, inp.RoomID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.ErrRoomNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get deleted room: %w", err)
+	}
+	if !inp.ByAdmin && ownerID != inp.AccountID {
+		return repository.ErrNotRoomOwner
+	}
+
+	restored, err := queries.RestoreRoom(ctx, db.RestoreRoomParams{
+		ID:          inp.RoomID,
+		GracePeriod: pgtype.Interval{Microseconds: inp.GracePeriod.Microseconds(), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to restore room: %w", err)
+	}
+	if restored == 0 {
+		return repository.ErrRestorePeriodExpired
+	}
+	if !inp.ByAdmin {
+		if err := messagerepositoryimpl.CreateSystemMessage(ctx, queries, inp.RoomID, inp.AccountID, domain.NewRoomRestoredEvent()); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
+
+// UpdateNotificationSettings implements repository.RoomRepository.
+func (r *RoomRepositoryOnDB) UpdateNotificationSettings(ctx context.Context, inp repository.UpdateNotificationSettingsInput) error {
+	queries := db.New(r.pool)
+	updated, err := queries.UpsertNotificationSettings(ctx, db.UpsertNotificationSettingsParams{
+		AccountID:  inp.AccountID,
+		Level:      inp.Level,
+		MutedUntil: pgtype.Timestamp{Time: inp.MutedUntil, Valid: !inp.MutedUntil.IsZero()},
+		RoomID:     inp.RoomID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upsert notification settings: %w", err)
+	}
+	if updated == 0 {
+		return repository.ErrRoomNotFound
+	}
+	return nil
+}
+
+// withOwnerLock は削除されていないルームの行をロックし、操作者がルームの作成者であることを確認してから fn を実行する
+func (r *RoomRepositoryOnDB) withOwnerLock(ctx context.Context, roomID, accountID uuid.UUID, fn func(queries *db.Queries, room db.GetRoomForUpdateRow) error) error {
+	return r.withRoomLock(ctx, roomID, func(queries *db.Queries, room db.GetRoomForUpdateRow) error {
+		if room.CreatedBy != accountID {
+			return repository.ErrNotRoomOwner
+		}
+		return fn(queries, room)
+	})
+}
+
+// withRoomLock は削除されていないルームの行をロックしてから fn を実行する
+func (r *RoomRepositoryOnDB) withRoomLock(ctx context.Context, roomID uuid.UUID, fn func(queries *db.Queries, room db.GetRoomForUpdateRow) error) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rollback", slog.Any("err", err))
+		}
+	}()
+
+	queries := db.New(r.pool).WithTx(tx)
+
+	room, err := queries.GetRoomForUpdate(ctx, roomID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.ErrRoomNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get room: %w", err)
+	}
+
+	if err := fn(queries, room); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
 }
 
 var _ repository.RoomRepository = new(RoomRepositoryOnDB)
@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/repository"
+)
+
+// ArchiveRoomUsecase はルームのアーカイブと解除を行う。アーカイブ済みのルームは履歴を残したまま読み取り専用になる
+type ArchiveRoomUsecase struct {
+	repo repository.RoomRepository
+}
+
+type ArchiveRoomInput struct {
+	RoomID    string
+	AccountID string
+	Archived  bool
+}
+
+// ArchiveRoomOutput の Changed はアーカイブの状態が変わった場合に true
+type ArchiveRoomOutput struct {
+	Changed bool
+}
+
+func NewArchiveRoomUsecase(repo repository.RoomRepository) *ArchiveRoomUsecase {
+	return &ArchiveRoomUsecase{repo}
+}
+
+func (u *ArchiveRoomUsecase) Execute(ctx context.Context, inp ArchiveRoomInput) (ArchiveRoomOutput, error) {
+	ids, err := parseRoomIDs(inp.RoomID, inp.AccountID)
+	if err != nil {
+		return ArchiveRoomOutput{}, err
+	}
+
+	res, err := u.repo.SetRoomArchived(ctx, repository.SetRoomArchivedInput{
+		RoomID:    ids.roomID,
+		AccountID: ids.accountID,
+		Archived:  inp.Archived,
+	})
+	if err != nil {
+		return ArchiveRoomOutput{}, fmt.Errorf("failed to set room archived: %w", err)
+	}
+
+	return ArchiveRoomOutput{Changed: res.Changed}, nil
+}
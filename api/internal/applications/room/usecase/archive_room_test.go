@@ -0,0 +1,67 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/repository"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveRoomUsecase_Execute(t *testing.T) {
+	t.Parallel()
+
+	t.Run("アーカイブの状態を変更できる", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockRoomRepository{
+			setRoomArchivedFunc: func(ctx context.Context, inp repository.SetRoomArchivedInput) (repository.SetRoomArchivedOutput, error) {
+				require.Equal(t, roomID, inp.RoomID.String())
+				require.Equal(t, accountID, inp.AccountID.String())
+				require.True(t, inp.Archived)
+				return repository.SetRoomArchivedOutput{Changed: true}, nil
+			},
+		}
+
+		uc := usecase.NewArchiveRoomUsecase(mockRepo)
+		out, err := uc.Execute(t.Context(), usecase.ArchiveRoomInput{
+			RoomID:    roomID,
+			AccountID: accountID,
+			Archived:  true,
+		})
+
+		require.NoError(t, err)
+		require.True(t, out.Changed)
+	})
+
+	t.Run("リポジトリエラー時にエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockRoomRepository{
+			setRoomArchivedFunc: func(ctx context.Context, inp repository.SetRoomArchivedInput) (repository.SetRoomArchivedOutput, error) {
+				return repository.SetRoomArchivedOutput{}, repository.ErrNotRoomOwner
+			},
+		}
+
+		uc := usecase.NewArchiveRoomUsecase(mockRepo)
+		_, err := uc.Execute(t.Context(), usecase.ArchiveRoomInput{
+			RoomID:    roomID,
+			AccountID: accountID,
+		})
+
+		require.ErrorIs(t, err, repository.ErrNotRoomOwner)
+	})
+}
+
+func TestNewArchiveRoomUsecase(t *testing.T) {
+	t.Parallel()
+
+	t.Run("正しく初期化される", func(t *testing.T) {
+		t.Parallel()
+
+		uc := usecase.NewArchiveRoomUsecase(&mockRoomRepository{})
+
+		require.NotNil(t, uc)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
 )
 
 type CreateRoomUsecase struct {
@@ -16,20 +17,28 @@ type CreateRoomInput struct {
 	CreatedBy string
 }
 
-type CreateRoomOutput struct{}
+type CreateRoomOutput struct {
+	ID   string
+	Name domain.RoomName
+}
 
 func NewCreateRoomUsecase(repo repository.RoomRepository) *CreateRoomUsecase {
 	return &CreateRoomUsecase{repo}
 }
 
 func (u *CreateRoomUsecase) Execute(ctx context.Context, inp CreateRoomInput) (CreateRoomOutput, error) {
-	_, err := u.repo.CreateRoom(ctx, repository.CreateRoomInput{
-		Name:      inp.Name,
+	name, err := domain.NewRoomName(inp.Name)
+	if err != nil {
+		return CreateRoomOutput{}, err
+	}
+
+	res, err := u.repo.CreateRoom(ctx, repository.CreateRoomInput{
+		Name:      name.String(),
 		CreatedBy: inp.CreatedBy,
 	})
 	if err != nil {
 		return CreateRoomOutput{}, fmt.Errorf("failed to create room: %w", err)
 	}
 
-	return CreateRoomOutput{}, nil
+	return CreateRoomOutput{ID: res.ID, Name: name}, nil
 }
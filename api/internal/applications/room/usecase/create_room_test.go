@@ -7,12 +7,18 @@ import (
 
 	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase"
 	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
 	"github.com/stretchr/testify/require"
 )
 
 // Mock implementations
 type mockRoomRepository struct {
-	createRoomFunc func(ctx context.Context, inp repository.CreateRoomInput) (repository.CreateRoomOutput, error)
+	createRoomFunc      func(ctx context.Context, inp repository.CreateRoomInput) (repository.CreateRoomOutput, error)
+	updateRoomFunc      func(ctx context.Context, inp repository.UpdateRoomInput) (repository.UpdateRoomOutput, error)
+	setRoomArchivedFunc func(ctx context.Context, inp repository.SetRoomArchivedInput) (repository.SetRoomArchivedOutput, error)
+	deleteRoomFunc      func(ctx context.Context, inp repository.DeleteRoomInput) error
+	restoreRoomFunc     func(ctx context.Context, inp repository.RestoreRoomInput) error
+	updateSettingsFunc  func(ctx context.Context, inp repository.UpdateNotificationSettingsInput) error
 }
 
 func (m *mockRoomRepository) CreateRoom(ctx context.Context, inp repository.CreateRoomInput) (repository.CreateRoomOutput, error) {
@@ -22,6 +28,41 @@ func (m *mockRoomRepository) CreateRoom(ctx context.Context, inp repository.Crea
 	return repository.CreateRoomOutput{}, nil
 }
 
+func (m *mockRoomRepository) UpdateRoom(ctx context.Context, inp repository.UpdateRoomInput) (repository.UpdateRoomOutput, error) {
+	if m.updateRoomFunc != nil {
+		return m.updateRoomFunc(ctx, inp)
+	}
+	return repository.UpdateRoomOutput{}, nil
+}
+
+func (m *mockRoomRepository) SetRoomArchived(ctx context.Context, inp repository.SetRoomArchivedInput) (repository.SetRoomArchivedOutput, error) {
+	if m.setRoomArchivedFunc != nil {
+		return m.setRoomArchivedFunc(ctx, inp)
+	}
+	return repository.SetRoomArchivedOutput{}, nil
+}
+
+func (m *mockRoomRepository) DeleteRoom(ctx context.Context, inp repository.DeleteRoomInput) error {
+	if m.deleteRoomFunc != nil {
+		return m.deleteRoomFunc(ctx, inp)
+	}
+	return nil
+}
+
+func (m *mockRoomRepository) RestoreRoom(ctx context.Context, inp repository.RestoreRoomInput) error {
+	if m.restoreRoomFunc != nil {
+		return m.restoreRoomFunc(ctx, inp)
+	}
+	return nil
+}
+
+func (m *mockRoomRepository) UpdateNotificationSettings(ctx context.Context, inp repository.UpdateNotificationSettingsInput) error {
+	if m.updateSettingsFunc != nil {
+		return m.updateSettingsFunc(ctx, inp)
+	}
+	return nil
+}
+
 func TestCreateRoomUsecase_Execute(t *testing.T) {
 	t.Parallel()
 
@@ -32,18 +73,40 @@ func TestCreateRoomUsecase_Execute(t *testing.T) {
 			createRoomFunc: func(ctx context.Context, inp repository.CreateRoomInput) (repository.CreateRoomOutput, error) {
 				require.Equal(t, "test-room", inp.Name)
 				require.Equal(t, "user-123", inp.CreatedBy)
-				return repository.CreateRoomOutput{}, nil
+				return repository.CreateRoomOutput{ID: "room-456"}, nil
 			},
 		}
 
 		uc := usecase.NewCreateRoomUsecase(mockRepo)
 
-		_, err := uc.Execute(t.Context(), usecase.CreateRoomInput{
+		out, err := uc.Execute(t.Context(), usecase.CreateRoomInput{
 			Name:      "test-room",
 			CreatedBy: "user-123",
 		})
 
 		require.NoError(t, err)
+		require.Equal(t, "room-456", out.ID)
+		require.Equal(t, "test-room", out.Name.String())
+	})
+
+	t.Run("不正なルーム名の場合はエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockRoomRepository{
+			createRoomFunc: func(ctx context.Context, inp repository.CreateRoomInput) (repository.CreateRoomOutput, error) {
+				t.Fatal("repository should not be called")
+				return repository.CreateRoomOutput{}, nil
+			},
+		}
+
+		uc := usecase.NewCreateRoomUsecase(mockRepo)
+
+		_, err := uc.Execute(t.Context(), usecase.CreateRoomInput{
+			Name:      "  \n ",
+			CreatedBy: "user-123",
+		})
+
+		require.ErrorIs(t, err, domain.ErrInvalidRoomName)
 	})
 
 	t.Run("リポジトリエラー時にエラーを返す", func(t *testing.T) {
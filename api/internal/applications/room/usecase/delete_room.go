@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/repository"
+)
+
+type DeleteRoomUsecase struct {
+	repo repository.RoomRepository
+}
+
+// DeleteRoomInput の ByAdmin が true の場合は管理者による操作として作成者を確認せず、AccountID は使わない
+type DeleteRoomInput struct {
+	RoomID    string
+	AccountID string
+	ByAdmin   bool
+}
+
+type DeleteRoomOutput struct{}
+
+func NewDeleteRoomUsecase(repo repository.RoomRepository) *DeleteRoomUsecase {
+	return &DeleteRoomUsecase{repo}
+}
+
+func (u *DeleteRoomUsecase) Execute(ctx context.Context, inp DeleteRoomInput) (DeleteRoomOutput, error) {
+	ids, err := parseDeletionIDs(inp.RoomID, inp.AccountID, inp.ByAdmin)
+	if err != nil {
+		return DeleteRoomOutput{}, err
+	}
+
+	if err := u.repo.DeleteRoom(ctx, repository.DeleteRoomInput{
+		RoomID:    ids.roomID,
+		AccountID: ids.accountID,
+		ByAdmin:   inp.ByAdmin,
+	}); err != nil {
+		return DeleteRoomOutput{}, fmt.Errorf("failed to delete room: %w", err)
+	}
+
+	return DeleteRoomOutput{}, nil
+}
+
+// RestoreRoomUsecase は削除から gracePeriod 以内のルームを復元する
+type RestoreRoomUsecase struct {
+	repo        repository.RoomRepository
+	gracePeriod time.Duration
+}
+
+// RestoreRoomInput の ByAdmin は DeleteRoomInput と同じ
+type RestoreRoomInput struct {
+	RoomID    string
+	AccountID string
+	ByAdmin   bool
+}
+
+type RestoreRoomOutput struct{}
+
+func NewRestoreRoomUsecase(repo repository.RoomRepository, gracePeriod time.Duration) *RestoreRoomUsecase {
+	return &RestoreRoomUsecase{repo, gracePeriod}
+}
+
+func (u *RestoreRoomUsecase) Execute(ctx context.Context, inp RestoreRoomInput) (RestoreRoomOutput, error) {
+	ids, err := parseDeletionIDs(inp.RoomID, inp.AccountID, inp.ByAdmin)
+	if err != nil {
+		return RestoreRoomOutput{}, err
+	}
+
+	if err := u.repo.RestoreRoom(ctx, repository.RestoreRoomInput{
+		RoomID:      ids.roomID,
+		AccountID:   ids.accountID,
+		ByAdmin:     inp.ByAdmin,
+		GracePeriod: u.gracePeriod,
+	}); err != nil {
+		return RestoreRoomOutput{}, fmt.Errorf("failed to restore room: %w", err)
+	}
+
+	return RestoreRoomOutput{}, nil
+}
+
+// parseDeletionIDs は削除・復元の ID を解析する。管理者による操作ではアカウント ID を使わないため解析しない
+func parseDeletionIDs(roomID, accountID string, byAdmin bool) (roomIDs, error) {
+	if !byAdmin {
+		return parseRoomIDs(roomID, accountID)
+	}
+	room, err := uuid.Parse(roomID)
+	if err != nil {
+		return roomIDs{}, repository.ErrRoomNotFound
+	}
+	return roomIDs{roomID: room}, nil
+}
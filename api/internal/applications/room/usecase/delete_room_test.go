@@ -0,0 +1,161 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/repository"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteRoomUsecase_Execute(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ルームを削除できる", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockRoomRepository{
+			deleteRoomFunc: func(ctx context.Context, inp repository.DeleteRoomInput) error {
+				require.Equal(t, roomID, inp.RoomID.String())
+				require.Equal(t, accountID, inp.AccountID.String())
+				return nil
+			},
+		}
+
+		uc := usecase.NewDeleteRoomUsecase(mockRepo)
+		_, err := uc.Execute(t.Context(), usecase.DeleteRoomInput{
+			RoomID:    roomID,
+			AccountID: accountID,
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("管理者はアカウントなしでルームを削除できる", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockRoomRepository{
+			deleteRoomFunc: func(ctx context.Context, inp repository.DeleteRoomInput) error {
+				require.Equal(t, roomID, inp.RoomID.String())
+				require.True(t, inp.ByAdmin)
+				return nil
+			},
+		}
+
+		uc := usecase.NewDeleteRoomUsecase(mockRepo)
+		_, err := uc.Execute(t.Context(), usecase.DeleteRoomInput{
+			RoomID:  roomID,
+			ByAdmin: true,
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("管理者による削除でもルーム ID が不正な場合は ErrRoomNotFound を返す", func(t *testing.T) {
+		t.Parallel()
+
+		uc := usecase.NewDeleteRoomUsecase(&mockRoomRepository{})
+		_, err := uc.Execute(t.Context(), usecase.DeleteRoomInput{
+			RoomID:  "invalid",
+			ByAdmin: true,
+		})
+
+		require.ErrorIs(t, err, repository.ErrRoomNotFound)
+	})
+
+	t.Run("リポジトリエラー時にエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockRoomRepository{
+			deleteRoomFunc: func(ctx context.Context, inp repository.DeleteRoomInput) error {
+				return errors.New("db error")
+			},
+		}
+
+		uc := usecase.NewDeleteRoomUsecase(mockRepo)
+		_, err := uc.Execute(t.Context(), usecase.DeleteRoomInput{
+			RoomID:    roomID,
+			AccountID: accountID,
+		})
+
+		require.Error(t, err)
+	})
+}
+
+func TestRestoreRoomUsecase_Execute(t *testing.T) {
+	t.Parallel()
+
+	t.Run("猶予期間を渡してルームを復元する", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockRoomRepository{
+			restoreRoomFunc: func(ctx context.Context, inp repository.RestoreRoomInput) error {
+				require.Equal(t, roomID, inp.RoomID.String())
+				require.Equal(t, accountID, inp.AccountID.String())
+				require.Equal(t, 48*time.Hour, inp.GracePeriod)
+				return nil
+			},
+		}
+
+		uc := usecase.NewRestoreRoomUsecase(mockRepo, 48*time.Hour)
+		_, err := uc.Execute(t.Context(), usecase.RestoreRoomInput{
+			RoomID:    roomID,
+			AccountID: accountID,
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("管理者はアカウントなしでルームを復元できる", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockRoomRepository{
+			restoreRoomFunc: func(ctx context.Context, inp repository.RestoreRoomInput) error {
+				require.Equal(t, roomID, inp.RoomID.String())
+				require.True(t, inp.ByAdmin)
+				require.Equal(t, 48*time.Hour, inp.GracePeriod)
+				return nil
+			},
+		}
+
+		uc := usecase.NewRestoreRoomUsecase(mockRepo, 48*time.Hour)
+		_, err := uc.Execute(t.Context(), usecase.RestoreRoomInput{
+			RoomID:  roomID,
+			ByAdmin: true,
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("猶予期間を過ぎている場合はエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockRoomRepository{
+			restoreRoomFunc: func(ctx context.Context, inp repository.RestoreRoomInput) error {
+				return repository.ErrRestorePeriodExpired
+			},
+		}
+
+		uc := usecase.NewRestoreRoomUsecase(mockRepo, time.Hour)
+		_, err := uc.Execute(t.Context(), usecase.RestoreRoomInput{
+			RoomID:    roomID,
+			AccountID: accountID,
+		})
+
+		require.ErrorIs(t, err, repository.ErrRestorePeriodExpired)
+	})
+}
+
+func TestNewDeleteRoomUsecase(t *testing.T) {
+	t.Parallel()
+
+	t.Run("正しく初期化される", func(t *testing.T) {
+		t.Parallel()
+
+		require.NotNil(t, usecase.NewDeleteRoomUsecase(&mockRoomRepository{}))
+		require.NotNil(t, usecase.NewRestoreRoomUsecase(&mockRoomRepository{}, time.Hour))
+	})
+}
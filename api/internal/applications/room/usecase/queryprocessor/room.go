@@ -1,19 +1,98 @@
 package queryprocessor
 
-import "context"
+import (
+	"context"
+	"errors"
+	"time"
 
-type GetRoomsInput struct{}
+	"github.com/google/uuid"
+)
+
+// RoomSort はルーム一覧の並び順。名前は昇順、それ以外は新しい順
+type RoomSort string
+
+const (
+	RoomSortName     RoomSort = "name"
+	RoomSortCreated  RoomSort = "created"
+	RoomSortActivity RoomSort = "activity"
+)
+
+// RoomFilter はルーム一覧の絞り込み条件。空の場合は絞り込まない
+type RoomFilter string
+
+const (
+	RoomFilterNone    RoomFilter = ""
+	RoomFilterJoined  RoomFilter = "joined"
+	RoomFilterCreated RoomFilter = "created"
+)
+
+// RoomCursor はページの最後のルームの並び替えキー
+//
+// Name は RoomSortName、Time はそれ以外の並び順で使う
+type RoomCursor struct {
+	Name string
+	Time time.Time
+	ID   uuid.UUID
+}
+
+// GetRoomsInput の Query はルーム名の前方一致・あいまい一致で絞り込む。空の場合は絞り込まない
+//
+// After が nil の場合は先頭から取得する
+type GetRoomsInput struct {
+	AccountID uuid.UUID
+	Query     string
+	Sort      RoomSort
+	Filter    RoomFilter
+	After     *RoomCursor
+	Limit     int
+}
 type GetRoomsOutput struct {
 	Rooms []RoomDTO
 }
+
+// RoomDTO の ArchivedAt はアーカイブされていない場合に空文字列、LastMessage はメッセージがない場合に nil
+//
+// Cursor は入力の並び順でこのルームの次から取得するためのキー
 type RoomDTO struct {
-	ID        string
-	Name      string
-	CreatedBy string
-	CreatedAt string
-	UpdatedAt string
+	ID             string
+	Name           string
+	Topic          string
+	CreatedBy      string
+	CreatedAt      string
+	UpdatedAt      string
+	ArchivedAt     string
+	LastActivityAt string
+	MemberCount    int
+	LastMessage    *LastMessageDTO
+	Notification   NotificationSettingsDTO
+	Cursor         RoomCursor
 }
 
+// LastMessageDTO はルームの最新メッセージのプレビュー
+type LastMessageDTO struct {
+	Author  string
+	Snippet string
+}
+
+// NotificationSettingsDTO の MutedUntil はミュート中でない場合に空文字列
+type NotificationSettingsDTO struct {
+	Level      string
+	MutedUntil string
+}
+
+type GetNotificationSettingsInput struct {
+	RoomID    uuid.UUID
+	AccountID uuid.UUID
+}
+type GetNotificationSettingsOutput struct {
+	Settings NotificationSettingsDTO
+}
+
+var (
+	ErrRoomNotFound = errors.New("room not found")
+)
+
 type RoomQueryProcessor interface {
 	GetRooms(ctx context.Context, inp GetRoomsInput) (GetRoomsOutput, error)
+	GetNotificationSettings(ctx context.Context, inp GetNotificationSettingsInput) (GetNotificationSettingsOutput, error)
 }
@@ -1,13 +1,89 @@
 package repository
 
-import "context"
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type CreateRoomInput struct {
 	Name      string
 	CreatedBy string
 }
-type CreateRoomOutput struct{}
+type CreateRoomOutput struct {
+	ID string
+}
+
+// UpdateRoomInput の Name, Topic は nil の場合に変更しない
+type UpdateRoomInput struct {
+	RoomID    uuid.UUID
+	AccountID uuid.UUID
+	Name      *string
+	Topic     *string
+}
+
+// UpdateRoomOutput は変更前後のルーム名とトピックを返す
+type UpdateRoomOutput struct {
+	PreviousName  string
+	Name          string
+	PreviousTopic string
+	Topic         string
+}
+
+type SetRoomArchivedInput struct {
+	RoomID    uuid.UUID
+	AccountID uuid.UUID
+	Archived  bool
+}
+
+// SetRoomArchivedOutput の Changed はアーカイブの状態が変わった場合に true
+type SetRoomArchivedOutput struct {
+	Changed bool
+}
+
+// DeleteRoomInput の ByAdmin が true の場合は管理者による操作として作成者を確認せず、AccountID は使わない
+type DeleteRoomInput struct {
+	RoomID    uuid.UUID
+	AccountID uuid.UUID
+	ByAdmin   bool
+}
+
+// RestoreRoomInput の GracePeriod は削除から復元できるまでの期間
+//
+// ByAdmin は DeleteRoomInput と同じ
+type RestoreRoomInput struct {
+	RoomID      uuid.UUID
+	AccountID   uuid.UUID
+	ByAdmin     bool
+	GracePeriod time.Duration
+}
+
+// UpdateNotificationSettingsInput の MutedUntil がゼロ値の場合はミュートを解除する
+type UpdateNotificationSettingsInput struct {
+	RoomID     uuid.UUID
+	AccountID  uuid.UUID
+	Level      string
+	MutedUntil time.Time
+}
+
+var (
+	ErrRoomNotFound = errors.New("room not found")
+	// ErrNotRoomOwner はルームの作成者以外がルームを変更しようとした場合に返す
+	ErrNotRoomOwner = errors.New("not room owner")
+	// ErrRoomArchived はアーカイブ済みのルームを変更しようとした場合に返す
+	ErrRoomArchived = errors.New("room archived")
+	// ErrRestorePeriodExpired は削除から猶予期間が過ぎたルームを復元しようとした場合に返す
+	ErrRestorePeriodExpired = errors.New("restore period expired")
+)
 
+// RoomRepository はルームの変更と、それに伴うシステムメッセージの記録を同一トランザクションで行う
 type RoomRepository interface {
 	CreateRoom(ctx context.Context, inp CreateRoomInput) (CreateRoomOutput, error)
+	UpdateRoom(ctx context.Context, inp UpdateRoomInput) (UpdateRoomOutput, error)
+	SetRoomArchived(ctx context.Context, inp SetRoomArchivedInput) (SetRoomArchivedOutput, error)
+	DeleteRoom(ctx context.Context, inp DeleteRoomInput) error
+	RestoreRoom(ctx context.Context, inp RestoreRoomInput) error
+	UpdateNotificationSettings(ctx context.Context, inp UpdateNotificationSettingsInput) error
 }
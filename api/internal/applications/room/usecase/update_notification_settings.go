@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+)
+
+type UpdateNotificationSettingsUsecase struct {
+	repo repository.RoomRepository
+}
+
+// UpdateNotificationSettingsInput の MutedUntil が nil の場合はミュートを解除する
+type UpdateNotificationSettingsInput struct {
+	RoomID     string
+	AccountID  string
+	Level      string
+	MutedUntil *time.Time
+}
+
+type UpdateNotificationSettingsOutput struct {
+	Settings domain.NotificationSettings
+}
+
+var (
+	// ErrInvalidMutedUntil はミュートの期限が過去の場合に返す
+	ErrInvalidMutedUntil = errors.New("invalid muted until")
+)
+
+func NewUpdateNotificationSettingsUsecase(repo repository.RoomRepository) *UpdateNotificationSettingsUsecase {
+	return &UpdateNotificationSettingsUsecase{repo}
+}
+
+func (u *UpdateNotificationSettingsUsecase) Execute(ctx context.Context, inp UpdateNotificationSettingsInput) (UpdateNotificationSettingsOutput, error) {
+	level, err := domain.NewNotificationLevel(inp.Level)
+	if err != nil {
+		return UpdateNotificationSettingsOutput{}, err
+	}
+
+	var mutedUntil time.Time
+	if inp.MutedUntil != nil {
+		if !inp.MutedUntil.After(time.Now()) {
+			return UpdateNotificationSettingsOutput{}, ErrInvalidMutedUntil
+		}
+		mutedUntil = inp.MutedUntil.UTC()
+	}
+
+	ids, err := parseRoomIDs(inp.RoomID, inp.AccountID)
+	if err != nil {
+		return UpdateNotificationSettingsOutput{}, err
+	}
+
+	if err := u.repo.UpdateNotificationSettings(ctx, repository.UpdateNotificationSettingsInput{
+		RoomID:     ids.roomID,
+		AccountID:  ids.accountID,
+		Level:      level.String(),
+		MutedUntil: mutedUntil,
+	}); err != nil {
+		return UpdateNotificationSettingsOutput{}, fmt.Errorf("failed to update notification settings: %w", err)
+	}
+
+	return UpdateNotificationSettingsOutput{
+		Settings: domain.NewNotificationSettings(level, mutedUntil),
+	}, nil
+}
@@ -0,0 +1,118 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateNotificationSettingsUsecase_Execute(t *testing.T) {
+	t.Parallel()
+
+	t.Run("通知レベルとミュートの期限を保存する", func(t *testing.T) {
+		t.Parallel()
+
+		mutedUntil := time.Now().Add(time.Hour)
+		mockRepo := &mockRoomRepository{
+			updateSettingsFunc: func(ctx context.Context, inp repository.UpdateNotificationSettingsInput) error {
+				require.Equal(t, roomID, inp.RoomID.String())
+				require.Equal(t, accountID, inp.AccountID.String())
+				require.Equal(t, "mentions", inp.Level)
+				require.True(t, mutedUntil.Equal(inp.MutedUntil))
+				return nil
+			},
+		}
+
+		uc := usecase.NewUpdateNotificationSettingsUsecase(mockRepo)
+		out, err := uc.Execute(t.Context(), usecase.UpdateNotificationSettingsInput{
+			RoomID:     roomID,
+			AccountID:  accountID,
+			Level:      "mentions",
+			MutedUntil: &mutedUntil,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, domain.NotificationLevelMentions, out.Settings.Level())
+		require.True(t, out.Settings.IsMuted(time.Now()))
+	})
+
+	t.Run("期限を省略するとミュートを解除する", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockRoomRepository{
+			updateSettingsFunc: func(ctx context.Context, inp repository.UpdateNotificationSettingsInput) error {
+				require.Equal(t, "all", inp.Level)
+				require.True(t, inp.MutedUntil.IsZero())
+				return nil
+			},
+		}
+
+		uc := usecase.NewUpdateNotificationSettingsUsecase(mockRepo)
+		_, err := uc.Execute(t.Context(), usecase.UpdateNotificationSettingsInput{
+			RoomID:    roomID,
+			AccountID: accountID,
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("不正な通知レベルの場合はエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		uc := usecase.NewUpdateNotificationSettingsUsecase(&mockRoomRepository{})
+		_, err := uc.Execute(t.Context(), usecase.UpdateNotificationSettingsInput{
+			RoomID:    roomID,
+			AccountID: accountID,
+			Level:     "loud",
+		})
+
+		require.ErrorIs(t, err, domain.ErrInvalidNotificationLevel)
+	})
+
+	t.Run("過去の期限の場合はエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		past := time.Now().Add(-time.Minute)
+		uc := usecase.NewUpdateNotificationSettingsUsecase(&mockRoomRepository{})
+		_, err := uc.Execute(t.Context(), usecase.UpdateNotificationSettingsInput{
+			RoomID:     roomID,
+			AccountID:  accountID,
+			MutedUntil: &past,
+		})
+
+		require.ErrorIs(t, err, usecase.ErrInvalidMutedUntil)
+	})
+
+	t.Run("リポジトリエラー時にエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockRoomRepository{
+			updateSettingsFunc: func(ctx context.Context, inp repository.UpdateNotificationSettingsInput) error {
+				return repository.ErrRoomNotFound
+			},
+		}
+
+		uc := usecase.NewUpdateNotificationSettingsUsecase(mockRepo)
+		_, err := uc.Execute(t.Context(), usecase.UpdateNotificationSettingsInput{
+			RoomID:    roomID,
+			AccountID: accountID,
+		})
+
+		require.ErrorIs(t, err, repository.ErrRoomNotFound)
+	})
+}
+
+func TestNewUpdateNotificationSettingsUsecase(t *testing.T) {
+	t.Parallel()
+
+	t.Run("正しく初期化される", func(t *testing.T) {
+		t.Parallel()
+
+		require.NotNil(t, usecase.NewUpdateNotificationSettingsUsecase(&mockRoomRepository{}))
+	})
+}
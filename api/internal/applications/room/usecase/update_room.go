@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+)
+
+type UpdateRoomUsecase struct {
+	repo repository.RoomRepository
+}
+
+// UpdateRoomInput の Name, Topic は nil の場合に変更しない
+type UpdateRoomInput struct {
+	RoomID    string
+	AccountID string
+	Name      *string
+	Topic     *string
+}
+
+type UpdateRoomOutput struct {
+	PreviousName  domain.RoomName
+	Name          domain.RoomName
+	PreviousTopic domain.RoomTopic
+	Topic         domain.RoomTopic
+}
+
+var (
+	// ErrNoRoomChanges は変更する項目が 1 つも指定されていない場合に返す
+	ErrNoRoomChanges = errors.New("no room changes")
+)
+
+func NewUpdateRoomUsecase(repo repository.RoomRepository) *UpdateRoomUsecase {
+	return &UpdateRoomUsecase{repo}
+}
+
+func (u *UpdateRoomUsecase) Execute(ctx context.Context, inp UpdateRoomInput) (UpdateRoomOutput, error) {
+	if inp.Name == nil && inp.Topic == nil {
+		return UpdateRoomOutput{}, ErrNoRoomChanges
+	}
+
+	ids, err := parseRoomIDs(inp.RoomID, inp.AccountID)
+	if err != nil {
+		return UpdateRoomOutput{}, err
+	}
+
+	repoInp := repository.UpdateRoomInput{
+		RoomID:    ids.roomID,
+		AccountID: ids.accountID,
+	}
+	if inp.Name != nil {
+		name, err := domain.NewRoomName(*inp.Name)
+		if err != nil {
+			return UpdateRoomOutput{}, err
+		}
+		s := name.String()
+		repoInp.Name = &s
+	}
+	if inp.Topic != nil {
+		topic, err := domain.NewRoomTopic(*inp.Topic)
+		if err != nil {
+			return UpdateRoomOutput{}, err
+		}
+		s := topic.String()
+		repoInp.Topic = &s
+	}
+
+	res, err := u.repo.UpdateRoom(ctx, repoInp)
+	if err != nil {
+		return UpdateRoomOutput{}, fmt.Errorf("failed to update room: %w", err)
+	}
+
+	// 保存済みの値は検証を通っているため変換に失敗しない
+	previousName, _ := domain.NewRoomName(res.PreviousName)
+	name, _ := domain.NewRoomName(res.Name)
+	previousTopic, _ := domain.NewRoomTopic(res.PreviousTopic)
+	topic, _ := domain.NewRoomTopic(res.Topic)
+	return UpdateRoomOutput{
+		PreviousName:  previousName,
+		Name:          name,
+		PreviousTopic: previousTopic,
+		Topic:         topic,
+	}, nil
+}
+
+type roomIDs struct {
+	roomID    uuid.UUID
+	accountID uuid.UUID
+}
+
+// parseRoomIDs は ID を変換する。ルーム ID が不正な場合は存在しないルームとして扱う
+func parseRoomIDs(roomID, accountID string) (roomIDs, error) {
+	room, err := uuid.Parse(roomID)
+	if err != nil {
+		return roomIDs{}, repository.ErrRoomNotFound
+	}
+	account, err := uuid.Parse(accountID)
+	if err != nil {
+		return roomIDs{}, fmt.Errorf("failed to parse account id: %w", err)
+	}
+	return roomIDs{roomID: room, accountID: account}, nil
+}
@@ -0,0 +1,154 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	roomID    = "8481027d-d6f6-402f-ae6d-98571e8f6496"
+	accountID = "550e8400-e29b-41d4-a716-446655440000"
+)
+
+func ptr(s string) *string {
+	return &s
+}
+
+func TestUpdateRoomUsecase_Execute(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ルーム名とトピックを変更できる", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockRoomRepository{
+			updateRoomFunc: func(ctx context.Context, inp repository.UpdateRoomInput) (repository.UpdateRoomOutput, error) {
+				require.Equal(t, roomID, inp.RoomID.String())
+				require.Equal(t, accountID, inp.AccountID.String())
+				require.Equal(t, "new-room", *inp.Name)
+				require.Equal(t, "about this room", *inp.Topic)
+				return repository.UpdateRoomOutput{
+					PreviousName:  "old-room",
+					Name:          *inp.Name,
+					PreviousTopic: "",
+					Topic:         *inp.Topic,
+				}, nil
+			},
+		}
+
+		uc := usecase.NewUpdateRoomUsecase(mockRepo)
+		out, err := uc.Execute(t.Context(), usecase.UpdateRoomInput{
+			RoomID:    roomID,
+			AccountID: accountID,
+			Name:      ptr("  new-room "),
+			Topic:     ptr("about\nthis room"),
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, "old-room", out.PreviousName.String())
+		require.Equal(t, "new-room", out.Name.String())
+		require.Equal(t, "", out.PreviousTopic.String())
+		require.Equal(t, "about this room", out.Topic.String())
+	})
+
+	t.Run("省略した項目は変更しない", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockRoomRepository{
+			updateRoomFunc: func(ctx context.Context, inp repository.UpdateRoomInput) (repository.UpdateRoomOutput, error) {
+				require.Nil(t, inp.Name)
+				require.Equal(t, "", *inp.Topic)
+				return repository.UpdateRoomOutput{PreviousName: "room", Name: "room", PreviousTopic: "old"}, nil
+			},
+		}
+
+		uc := usecase.NewUpdateRoomUsecase(mockRepo)
+		_, err := uc.Execute(t.Context(), usecase.UpdateRoomInput{
+			RoomID:    roomID,
+			AccountID: accountID,
+			Topic:     ptr(""),
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("変更する項目がない場合はエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		uc := usecase.NewUpdateRoomUsecase(&mockRoomRepository{})
+		_, err := uc.Execute(t.Context(), usecase.UpdateRoomInput{
+			RoomID:    roomID,
+			AccountID: accountID,
+		})
+
+		require.ErrorIs(t, err, usecase.ErrNoRoomChanges)
+	})
+
+	t.Run("不正なルーム名の場合はエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockRoomRepository{
+			updateRoomFunc: func(ctx context.Context, inp repository.UpdateRoomInput) (repository.UpdateRoomOutput, error) {
+				t.Fatal("repository should not be called")
+				return repository.UpdateRoomOutput{}, nil
+			},
+		}
+
+		uc := usecase.NewUpdateRoomUsecase(mockRepo)
+		_, err := uc.Execute(t.Context(), usecase.UpdateRoomInput{
+			RoomID:    roomID,
+			AccountID: accountID,
+			Name:      ptr(" "),
+		})
+
+		require.ErrorIs(t, err, domain.ErrInvalidRoomName)
+	})
+
+	t.Run("不正なルーム ID の場合はルームが存在しないエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		uc := usecase.NewUpdateRoomUsecase(&mockRoomRepository{})
+		_, err := uc.Execute(t.Context(), usecase.UpdateRoomInput{
+			RoomID:    "invalid",
+			AccountID: accountID,
+			Name:      ptr("room"),
+		})
+
+		require.ErrorIs(t, err, repository.ErrRoomNotFound)
+	})
+
+	t.Run("リポジトリエラー時にエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockRoomRepository{
+			updateRoomFunc: func(ctx context.Context, inp repository.UpdateRoomInput) (repository.UpdateRoomOutput, error) {
+				return repository.UpdateRoomOutput{}, repository.ErrRoomArchived
+			},
+		}
+
+		uc := usecase.NewUpdateRoomUsecase(mockRepo)
+		_, err := uc.Execute(t.Context(), usecase.UpdateRoomInput{
+			RoomID:    roomID,
+			AccountID: accountID,
+			Name:      ptr("room"),
+		})
+
+		require.ErrorIs(t, err, repository.ErrRoomArchived)
+	})
+}
+
+func TestNewUpdateRoomUsecase(t *testing.T) {
+	t.Parallel()
+
+	t.Run("正しく初期化される", func(t *testing.T) {
+		t.Parallel()
+
+		uc := usecase.NewUpdateRoomUsecase(&mockRoomRepository{})
+
+		require.NotNil(t, uc)
+	})
+}
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ExportFormatVersion はエクスポート形式のバージョン。互換性のない変更をしたら上げる
+const ExportFormatVersion = 1
+
+// エクスポートの 1 行は type でどのレコードかを表す
+//
+// 先頭に header が 1 行あり、その後にルームごとに room, member, message の順に並ぶ
+const (
+	recordTypeHeader  = "header"
+	recordTypeRoom    = "room"
+	recordTypeMember  = "member"
+	recordTypeMessage = "message"
+)
+
+// exportTimeLayout は日時の形式。作成日時を失わないよう秒未満も出力する
+const exportTimeLayout = time.RFC3339Nano
+
+type exportRecordType struct {
+	Type string `json:"type"`
+}
+
+type exportHeader struct {
+	Type       string `json:"type"`
+	Version    int    `json:"version"`
+	ExportedAt string `json:"exportedAt"`
+}
+
+type exportRoom struct {
+	Type          string  `json:"type"`
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	Topic         string  `json:"topic"`
+	OwnerID       string  `json:"ownerId"`
+	CreatedAt     string  `json:"createdAt"`
+	ArchivedAt    *string `json:"archivedAt"`
+	RetentionDays *int    `json:"retentionDays"`
+}
+
+// exportMember の Kind は user, bot, placeholder のいずれか。インポート時には使わない
+type exportMember struct {
+	Type     string `json:"type"`
+	RoomID   string `json:"roomId"`
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Kind     string `json:"kind"`
+}
+
+// exportMessage の Event はシステムメッセージの場合のみ出力する
+type exportMessage struct {
+	Type      string          `json:"type"`
+	RoomID    string          `json:"roomId"`
+	ID        string          `json:"id"`
+	AuthorID  string          `json:"authorId"`
+	Kind      string          `json:"kind"`
+	Content   string          `json:"content"`
+	Format    string          `json:"format"`
+	Event     json.RawMessage `json:"event,omitempty"`
+	CreatedAt string          `json:"createdAt"`
+	UpdatedAt string          `json:"updatedAt"`
+}
+
+func formatExportTime(t time.Time) string {
+	return t.UTC().Format(exportTimeLayout)
+}
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/roomtransfer/usecase/queryprocessor"
+)
+
+// ExportRoomsInput の RoomID が空の場合はすべてのルームをエクスポートする
+type ExportRoomsInput struct {
+	RoomID string
+}
+
+type ExportRoomsController struct {
+	query queryprocessor.RoomExportQueryProcessor
+}
+
+func NewExportRoomsController(query queryprocessor.RoomExportQueryProcessor) *ExportRoomsController {
+	return &ExportRoomsController{query}
+}
+
+// ExportRooms はルームを NDJSON で w に書き出す
+//
+// ルームが見つからない場合は何も書き出さずにエラーを返す
+func (c *ExportRoomsController) ExportRooms(ctx context.Context, inp ExportRoomsInput, w io.Writer) error {
+	var qinp queryprocessor.ExportRoomsInput
+	if inp.RoomID != "" {
+		roomID, err := uuid.Parse(inp.RoomID)
+		if err != nil {
+			return queryprocessor.ErrRoomNotFound
+		}
+		qinp.RoomID = &roomID
+	}
+
+	ew := newNDJSONExportWriter(w)
+	if err := c.query.ExportRooms(ctx, qinp, ew); err != nil {
+		return err
+	}
+	// ルームが 1 つもなくてもヘッダーは出力する
+	return ew.writeHeader()
+}
+
+// ndjsonExportWriter は最初のレコードを書く直前にヘッダーを書く
+type ndjsonExportWriter struct {
+	enc           *json.Encoder
+	headerWritten bool
+}
+
+func newNDJSONExportWriter(w io.Writer) *ndjsonExportWriter {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &ndjsonExportWriter{enc: enc}
+}
+
+func (w *ndjsonExportWriter) writeHeader() error {
+	if w.headerWritten {
+		return nil
+	}
+	w.headerWritten = true
+	return w.enc.Encode(exportHeader{
+		Type:       recordTypeHeader,
+		Version:    ExportFormatVersion,
+		ExportedAt: formatExportTime(time.Now()),
+	})
+}
+
+// WriteRoom implements queryprocessor.RoomExportWriter.
+func (w *ndjsonExportWriter) WriteRoom(room queryprocessor.RoomDTO) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	rec := exportRoom{
+		Type:          recordTypeRoom,
+		ID:            room.ID.String(),
+		Name:          room.Name,
+		Topic:         room.Topic,
+		OwnerID:       room.OwnerID.String(),
+		CreatedAt:     formatExportTime(room.CreatedAt),
+		RetentionDays: room.RetentionDays,
+	}
+	if room.ArchivedAt != nil {
+		archivedAt := formatExportTime(*room.ArchivedAt)
+		rec.ArchivedAt = &archivedAt
+	}
+	return w.enc.Encode(rec)
+}
+
+// WriteMember implements queryprocessor.RoomExportWriter.
+func (w *ndjsonExportWriter) WriteMember(member queryprocessor.MemberDTO) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	return w.enc.Encode(exportMember{
+		Type:     recordTypeMember,
+		RoomID:   member.RoomID.String(),
+		ID:       member.ID.String(),
+		Username: member.UserName,
+		Kind:     member.Kind,
+	})
+}
+
+// WriteMessage implements queryprocessor.RoomExportWriter.
+func (w *ndjsonExportWriter) WriteMessage(message queryprocessor.MessageDTO) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	return w.enc.Encode(exportMessage{
+		Type:      recordTypeMessage,
+		RoomID:    message.RoomID.String(),
+		ID:        message.ID.String(),
+		AuthorID:  message.AuthorID.String(),
+		Kind:      message.Kind,
+		Content:   message.Content,
+		Format:    message.Format,
+		Event:     message.Event,
+		CreatedAt: formatExportTime(message.CreatedAt),
+		UpdatedAt: formatExportTime(message.UpdatedAt),
+	})
+}
+
+var _ queryprocessor.RoomExportWriter = new(ndjsonExportWriter)
@@ -0,0 +1,291 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/roomtransfer/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/roomtransfer/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+)
+
+type ImportRoomsOutput struct {
+	Rooms               []ImportedRoom `json:"rooms"`
+	PlaceholderAccounts int            `json:"placeholderAccounts"`
+}
+
+// ImportedRoom の OriginalID はエクスポート元のルーム ID、ID は作成したルームの ID
+type ImportedRoom struct {
+	OriginalID string `json:"originalId"`
+	ID         string `json:"id"`
+	Messages   int    `json:"messages"`
+}
+
+type ImportRoomsController struct {
+	repo repository.RoomImportRepository
+}
+
+func NewImportRoomsController(repo repository.RoomImportRepository) *ImportRoomsController {
+	return &ImportRoomsController{repo}
+}
+
+// ImportRooms は ExportRooms が書き出した NDJSON を読み、ルームとメッセージを作成する
+func (c *ImportRoomsController) ImportRooms(ctx context.Context, r io.Reader) (ImportRoomsOutput, error) {
+	uc := usecase.NewImportRoomsUsecase(c.repo)
+	res, err := tracing.Execute(ctx, "ImportRoomsUsecase", uc.Execute, usecase.ImportRecordReader(newNDJSONImportReader(r)))
+	if err != nil {
+		return ImportRoomsOutput{}, err
+	}
+
+	rooms := make([]ImportedRoom, len(res.Rooms))
+	for i, room := range res.Rooms {
+		rooms[i] = ImportedRoom{
+			OriginalID: room.OriginalID.String(),
+			ID:         room.ID.String(),
+			Messages:   room.Messages,
+		}
+	}
+	return ImportRoomsOutput{
+		Rooms:               rooms,
+		PlaceholderAccounts: res.PlaceholderAccounts,
+	}, nil
+}
+
+// ndjsonImportReader は 1 行ずつレコードを読み、ドメインの値に変換する
+type ndjsonImportReader struct {
+	dec *json.Decoder
+	// line は最後に読んだレコードの番号。ヘッダーが 1
+	line int
+}
+
+func newNDJSONImportReader(r io.Reader) *ndjsonImportReader {
+	return &ndjsonImportReader{dec: json.NewDecoder(r)}
+}
+
+// Next implements usecase.ImportRecordReader.
+func (r *ndjsonImportReader) Next() (usecase.ImportRecord, error) {
+	if r.line == 0 {
+		if err := r.readHeader(); err != nil {
+			return usecase.ImportRecord{}, err
+		}
+	}
+
+	raw, err := r.read()
+	if err != nil {
+		return usecase.ImportRecord{}, err
+	}
+	rec, err := parseImportRecord(raw)
+	if err != nil {
+		return usecase.ImportRecord{}, fmt.Errorf("%w: record %d: %w", usecase.ErrInvalidImport, r.line, err)
+	}
+	return rec, nil
+}
+
+func (r *ndjsonImportReader) read() (json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := r.dec.Decode(&raw); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("%w: record %d: %w", usecase.ErrInvalidImport, r.line+1, err)
+	}
+	r.line++
+	return raw, nil
+}
+
+func (r *ndjsonImportReader) readHeader() error {
+	raw, err := r.read()
+	if errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: missing header", usecase.ErrInvalidImport)
+	}
+	if err != nil {
+		return err
+	}
+
+	var h exportHeader
+	if err := json.Unmarshal(raw, &h); err != nil {
+		return fmt.Errorf("%w: bad header: %w", usecase.ErrInvalidImport, err)
+	}
+	if h.Type != recordTypeHeader {
+		return fmt.Errorf("%w: first record must be a header", usecase.ErrInvalidImport)
+	}
+	if h.Version != ExportFormatVersion {
+		return fmt.Errorf("%w: unsupported version %d", usecase.ErrInvalidImport, h.Version)
+	}
+	return nil
+}
+
+func parseImportRecord(raw json.RawMessage) (usecase.ImportRecord, error) {
+	var t exportRecordType
+	if err := json.Unmarshal(raw, &t); err != nil {
+		return usecase.ImportRecord{}, err
+	}
+
+	switch t.Type {
+	case recordTypeRoom:
+		var v exportRoom
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return usecase.ImportRecord{}, err
+		}
+		room, err := parseImportRoom(v)
+		if err != nil {
+			return usecase.ImportRecord{}, err
+		}
+		return usecase.ImportRecord{Room: &room}, nil
+
+	case recordTypeMember:
+		var v exportMember
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return usecase.ImportRecord{}, err
+		}
+		member, err := parseImportMember(v)
+		if err != nil {
+			return usecase.ImportRecord{}, err
+		}
+		return usecase.ImportRecord{Member: &member}, nil
+
+	case recordTypeMessage:
+		var v exportMessage
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return usecase.ImportRecord{}, err
+		}
+		message, err := parseImportMessage(v)
+		if err != nil {
+			return usecase.ImportRecord{}, err
+		}
+		return usecase.ImportRecord{Message: &message}, nil
+
+	default:
+		return usecase.ImportRecord{}, fmt.Errorf("unknown record type %q", t.Type)
+	}
+}
+
+func parseImportRoom(v exportRoom) (usecase.ImportRoom, error) {
+	id, err := uuid.Parse(v.ID)
+	if err != nil {
+		return usecase.ImportRoom{}, fmt.Errorf("bad room id: %w", err)
+	}
+	name, err := domain.NewRoomName(v.Name)
+	if err != nil {
+		return usecase.ImportRoom{}, fmt.Errorf("bad room name: %w", err)
+	}
+	topic, err := domain.NewRoomTopic(v.Topic)
+	if err != nil {
+		return usecase.ImportRoom{}, fmt.Errorf("bad room topic: %w", err)
+	}
+	ownerID, err := uuid.Parse(v.OwnerID)
+	if err != nil {
+		return usecase.ImportRoom{}, fmt.Errorf("bad owner id: %w", err)
+	}
+	createdAt, err := time.Parse(exportTimeLayout, v.CreatedAt)
+	if err != nil {
+		return usecase.ImportRoom{}, fmt.Errorf("bad created at: %w", err)
+	}
+
+	room := usecase.ImportRoom{
+		ID:        id,
+		Name:      name,
+		Topic:     topic,
+		OwnerID:   ownerID,
+		CreatedAt: createdAt.UTC(),
+	}
+	if v.ArchivedAt != nil {
+		archivedAt, err := time.Parse(exportTimeLayout, *v.ArchivedAt)
+		if err != nil {
+			return usecase.ImportRoom{}, fmt.Errorf("bad archived at: %w", err)
+		}
+		archivedAt = archivedAt.UTC()
+		room.ArchivedAt = &archivedAt
+	}
+	if v.RetentionDays != nil {
+		period, err := domain.NewRetentionPeriod(*v.RetentionDays)
+		if err != nil {
+			return usecase.ImportRoom{}, fmt.Errorf("bad retention days: %w", err)
+		}
+		room.Retention = &period
+	}
+	return room, nil
+}
+
+func parseImportMember(v exportMember) (usecase.ImportMember, error) {
+	roomID, err := uuid.Parse(v.RoomID)
+	if err != nil {
+		return usecase.ImportMember{}, fmt.Errorf("bad room id: %w", err)
+	}
+	id, err := uuid.Parse(v.ID)
+	if err != nil {
+		return usecase.ImportMember{}, fmt.Errorf("bad account id: %w", err)
+	}
+	name, err := domain.NewUserName(v.Username)
+	if err != nil {
+		return usecase.ImportMember{}, fmt.Errorf("bad username: %w", err)
+	}
+	return usecase.ImportMember{
+		RoomID:   roomID,
+		ID:       id,
+		UserName: name,
+	}, nil
+}
+
+func parseImportMessage(v exportMessage) (usecase.ImportMessage, error) {
+	roomID, err := uuid.Parse(v.RoomID)
+	if err != nil {
+		return usecase.ImportMessage{}, fmt.Errorf("bad room id: %w", err)
+	}
+	id, err := uuid.Parse(v.ID)
+	if err != nil {
+		return usecase.ImportMessage{}, fmt.Errorf("bad message id: %w", err)
+	}
+	authorID, err := uuid.Parse(v.AuthorID)
+	if err != nil {
+		return usecase.ImportMessage{}, fmt.Errorf("bad author id: %w", err)
+	}
+	content, err := domain.NewMessageContent(v.Content)
+	if err != nil {
+		return usecase.ImportMessage{}, fmt.Errorf("bad content: %w", err)
+	}
+	format, err := domain.NewMessageFormat(v.Format)
+	if err != nil {
+		return usecase.ImportMessage{}, fmt.Errorf("bad format: %w", err)
+	}
+	createdAt, err := time.Parse(exportTimeLayout, v.CreatedAt)
+	if err != nil {
+		return usecase.ImportMessage{}, fmt.Errorf("bad created at: %w", err)
+	}
+	updatedAt, err := time.Parse(exportTimeLayout, v.UpdatedAt)
+	if err != nil {
+		return usecase.ImportMessage{}, fmt.Errorf("bad updated at: %w", err)
+	}
+
+	message := usecase.ImportMessage{
+		RoomID:    roomID,
+		ID:        id,
+		AuthorID:  authorID,
+		Content:   content,
+		Format:    format,
+		CreatedAt: createdAt.UTC(),
+		UpdatedAt: updatedAt.UTC(),
+	}
+	switch domain.MessageKind(v.Kind) {
+	case domain.MessageKindUser:
+		message.Kind = domain.MessageKindUser
+	case domain.MessageKindSystem:
+		event, err := domain.ParseSystemEvent(v.Event)
+		if err != nil {
+			return usecase.ImportMessage{}, fmt.Errorf("bad event: %w", err)
+		}
+		message.Kind = domain.MessageKindSystem
+		message.Event = &event
+	default:
+		return usecase.ImportMessage{}, fmt.Errorf("unknown message kind %q", v.Kind)
+	}
+	return message, nil
+}
+
+var _ usecase.ImportRecordReader = new(ndjsonImportReader)
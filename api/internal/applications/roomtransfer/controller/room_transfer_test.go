@@ -0,0 +1,263 @@
+package controller_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/roomtransfer/controller"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/roomtransfer/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/roomtransfer/usecase/queryprocessor"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/roomtransfer/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock implementations
+type mockRoomExportQueryProcessor struct {
+	exportRoomsFunc func(ctx context.Context, inp queryprocessor.ExportRoomsInput, w queryprocessor.RoomExportWriter) error
+}
+
+func (m *mockRoomExportQueryProcessor) ExportRooms(ctx context.Context, inp queryprocessor.ExportRoomsInput, w queryprocessor.RoomExportWriter) error {
+	if m.exportRoomsFunc != nil {
+		return m.exportRoomsFunc(ctx, inp, w)
+	}
+	return nil
+}
+
+type mockRoomImportRepository struct {
+	resolveAccountsFunc func(ctx context.Context, inp repository.ResolveAccountsInput) (repository.ResolveAccountsOutput, error)
+	importRoomFunc      func(ctx context.Context, inp repository.ImportRoomInput) (repository.ImportRoomOutput, error)
+}
+
+func (m *mockRoomImportRepository) ResolveAccounts(ctx context.Context, inp repository.ResolveAccountsInput) (repository.ResolveAccountsOutput, error) {
+	if m.resolveAccountsFunc != nil {
+		return m.resolveAccountsFunc(ctx, inp)
+	}
+	return repository.ResolveAccountsOutput{}, nil
+}
+
+func (m *mockRoomImportRepository) ImportRoom(ctx context.Context, inp repository.ImportRoomInput) (repository.ImportRoomOutput, error) {
+	if m.importRoomFunc != nil {
+		return m.importRoomFunc(ctx, inp)
+	}
+	return repository.ImportRoomOutput{}, nil
+}
+
+func TestRoomTransfer_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	roomID, emptyRoomID := uuid.New(), uuid.New()
+	alice, ghost := uuid.New(), uuid.New()
+	messageID, pinID := uuid.New(), uuid.New()
+
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 123456000, time.UTC)
+	archivedAt := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	postedAt := time.Date(2024, 1, 2, 3, 5, 0, 987654000, time.UTC)
+	editedAt := postedAt.Add(time.Minute)
+	retention := 30
+	event, err := json.Marshal(domain.NewMessagePinnedEvent(domain.MessageIDFromUuid(messageID)))
+	require.NoError(t, err)
+
+	query := &mockRoomExportQueryProcessor{
+		exportRoomsFunc: func(ctx context.Context, inp queryprocessor.ExportRoomsInput, w queryprocessor.RoomExportWriter) error {
+			require.Nil(t, inp.RoomID)
+			require.NoError(t, w.WriteRoom(queryprocessor.RoomDTO{
+				ID:            roomID,
+				Name:          "general",
+				Topic:         "<dev> & chat",
+				OwnerID:       alice,
+				CreatedAt:     createdAt,
+				ArchivedAt:    &archivedAt,
+				RetentionDays: &retention,
+			}))
+			require.NoError(t, w.WriteMember(queryprocessor.MemberDTO{RoomID: roomID, ID: alice, UserName: "alice", Kind: "user"}))
+			require.NoError(t, w.WriteMember(queryprocessor.MemberDTO{RoomID: roomID, ID: ghost, UserName: "ghost", Kind: "user"}))
+			require.NoError(t, w.WriteMessage(queryprocessor.MessageDTO{
+				RoomID:    roomID,
+				ID:        messageID,
+				AuthorID:  ghost,
+				Kind:      "user",
+				Content:   "こんにちは **world**\n<b>",
+				Format:    "markdown",
+				CreatedAt: postedAt,
+				UpdatedAt: editedAt,
+			}))
+			require.NoError(t, w.WriteMessage(queryprocessor.MessageDTO{
+				RoomID:    roomID,
+				ID:        pinID,
+				AuthorID:  alice,
+				Kind:      "system",
+				Content:   "pinned a message",
+				Format:    "plain",
+				Event:     event,
+				CreatedAt: editedAt,
+				UpdatedAt: editedAt,
+			}))
+			require.NoError(t, w.WriteRoom(queryprocessor.RoomDTO{
+				ID:        emptyRoomID,
+				Name:      "empty",
+				OwnerID:   alice,
+				CreatedAt: createdAt,
+			}))
+			return w.WriteMember(queryprocessor.MemberDTO{RoomID: emptyRoomID, ID: alice, UserName: "alice", Kind: "user"})
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, controller.NewExportRoomsController(query).ExportRooms(t.Context(), controller.ExportRoomsInput{}, &buf))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 8)
+	require.Contains(t, lines[0], `"type":"header","version":1`)
+
+	// インポート先には同じ ID の alice と、ID の違う別人の ghost がいる。
+	// 投稿者は ID だけで対応付けるため、エクスポート元の ghost はプレースホルダーになる
+	localGhost, placeholderGhost := uuid.New(), uuid.New()
+	local := map[uuid.UUID]string{alice: "alice", localGhost: "ghost"}
+	newRoomID, newEmptyRoomID := uuid.New(), uuid.New()
+	var imported []repository.ImportRoomInput
+	repo := &mockRoomImportRepository{
+		resolveAccountsFunc: func(ctx context.Context, inp repository.ResolveAccountsInput) (repository.ResolveAccountsOutput, error) {
+			out := repository.ResolveAccountsOutput{IDs: map[uuid.UUID]uuid.UUID{}}
+			for _, a := range inp.Accounts {
+				if _, ok := local[a.ID]; ok {
+					out.IDs[a.ID] = a.ID
+					continue
+				}
+				require.Equal(t, ghost, a.ID)
+				require.Equal(t, "ghost", a.UserName)
+				out.IDs[a.ID] = placeholderGhost
+				out.Placeholders++
+			}
+			return out, nil
+		},
+		importRoomFunc: func(ctx context.Context, inp repository.ImportRoomInput) (repository.ImportRoomOutput, error) {
+			imported = append(imported, inp)
+			if len(imported) == 1 {
+				return repository.ImportRoomOutput{ID: newRoomID}, nil
+			}
+			return repository.ImportRoomOutput{ID: newEmptyRoomID}, nil
+		},
+	}
+
+	out, err := controller.NewImportRoomsController(repo).ImportRooms(t.Context(), &buf)
+	require.NoError(t, err)
+	require.Equal(t, controller.ImportRoomsOutput{
+		Rooms: []controller.ImportedRoom{
+			{OriginalID: roomID.String(), ID: newRoomID.String(), Messages: 2},
+			{OriginalID: emptyRoomID.String(), ID: newEmptyRoomID.String(), Messages: 0},
+		},
+		PlaceholderAccounts: 1,
+	}, out)
+
+	require.Len(t, imported, 2)
+	room := imported[0]
+	require.Equal(t, "general", room.Name)
+	require.Equal(t, "<dev> & chat", room.Topic)
+	require.Equal(t, alice, room.OwnerID)
+	require.True(t, createdAt.Equal(room.CreatedAt))
+	require.NotNil(t, room.ArchivedAt)
+	require.True(t, archivedAt.Equal(*room.ArchivedAt))
+	require.Equal(t, &retention, room.RetentionDays)
+
+	require.Len(t, room.Messages, 2)
+	msg := room.Messages[0]
+	require.NotEqual(t, messageID, msg.ID)
+	require.Equal(t, placeholderGhost, msg.AuthorID)
+	require.NotEqual(t, localGhost, msg.AuthorID)
+	require.Equal(t, "user", msg.Kind)
+	require.Equal(t, "こんにちは **world**\n<b>", msg.Content)
+	require.Equal(t, "markdown", msg.Format)
+	require.Nil(t, msg.Event)
+	require.True(t, postedAt.Equal(msg.CreatedAt))
+	require.True(t, editedAt.Equal(msg.UpdatedAt))
+
+	pin := room.Messages[1]
+	require.Equal(t, alice, pin.AuthorID)
+	require.Equal(t, "system", pin.Kind)
+	restored, err := domain.ParseSystemEvent(pin.Event)
+	require.NoError(t, err)
+	require.Equal(t, domain.SystemEventMessagePinned, restored.Type())
+	require.Equal(t, domain.MessageIDFromUuid(msg.ID), restored.MessageID())
+
+	require.Equal(t, "empty", imported[1].Name)
+	require.Nil(t, imported[1].ArchivedAt)
+	require.Nil(t, imported[1].RetentionDays)
+	require.Empty(t, imported[1].Messages)
+}
+
+func TestExportRoomsController_ExportRooms(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ルームがない場合もヘッダーを出力する", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		err := controller.NewExportRoomsController(&mockRoomExportQueryProcessor{}).ExportRooms(t.Context(), controller.ExportRoomsInput{}, &buf)
+
+		require.NoError(t, err)
+		require.Equal(t, 1, strings.Count(buf.String(), "\n"))
+		require.Contains(t, buf.String(), `"type":"header"`)
+	})
+
+	t.Run("不正なルーム ID の場合は何も出力せず ErrRoomNotFound", func(t *testing.T) {
+		t.Parallel()
+
+		query := &mockRoomExportQueryProcessor{
+			exportRoomsFunc: func(ctx context.Context, inp queryprocessor.ExportRoomsInput, w queryprocessor.RoomExportWriter) error {
+				t.Fatal("should not be called")
+				return nil
+			},
+		}
+
+		var buf bytes.Buffer
+		err := controller.NewExportRoomsController(query).ExportRooms(t.Context(), controller.ExportRoomsInput{RoomID: "invalid"}, &buf)
+
+		require.ErrorIs(t, err, queryprocessor.ErrRoomNotFound)
+		require.Zero(t, buf.Len())
+	})
+}
+
+func TestImportRoomsController_ImportRooms(t *testing.T) {
+	t.Parallel()
+
+	roomID, alice := uuid.NewString(), uuid.NewString()
+	header := `{"type":"header","version":1,"exportedAt":"2024-01-01T00:00:00Z"}`
+	room := `{"type":"room","id":"` + roomID + `","name":"general","topic":"","ownerId":"` + alice + `","createdAt":"2024-01-01T00:00:00Z","archivedAt":null,"retentionDays":null}`
+	member := `{"type":"member","roomId":"` + roomID + `","id":"` + alice + `","username":"alice","kind":"user"}`
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"空の入力", ""},
+		{"ヘッダーがない", room},
+		{"未対応のバージョン", `{"type":"header","version":2}`},
+		{"JSON として不正", header + "\n{"},
+		{"未知のレコード", header + "\n" + `{"type":"reaction"}`},
+		{"不正なユーザー名", header + "\n" + room + "\n" + strings.Replace(member, "alice", "al ice", 1)},
+		{"イベントのないシステムメッセージ", header + "\n" + room + "\n" + member + "\n" +
+			`{"type":"message","roomId":"` + roomID + `","id":"` + uuid.NewString() + `","authorId":"` + alice + `","kind":"system","content":"x","format":"plain","createdAt":"2024-01-01T00:00:00Z","updatedAt":"2024-01-01T00:00:00Z"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRoomImportRepository{
+				importRoomFunc: func(ctx context.Context, inp repository.ImportRoomInput) (repository.ImportRoomOutput, error) {
+					t.Fatal("should not be called")
+					return repository.ImportRoomOutput{}, nil
+				},
+			}
+
+			_, err := controller.NewImportRoomsController(repo).ImportRooms(t.Context(), strings.NewReader(tt.input))
+			require.ErrorIs(t, err, usecase.ErrInvalidImport)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package queryprocessorimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/roomtransfer/usecase/queryprocessor"
+	"github.com/quietsato/toy-small-chat/api/internal/db"
+)
+
+// exportMessagePageSize は 1 回のクエリで読むメッセージの件数
+const exportMessagePageSize = 500
+
+type RoomExportQueryProcessorOnDB struct {
+	pool *pgxpool.Pool
+}
+
+func NewRoomExportQueryProcessorOnDB(pool *pgxpool.Pool) *RoomExportQueryProcessorOnDB {
+	return &RoomExportQueryProcessorOnDB{pool}
+}
+
+// ExportRooms implements queryprocessor.RoomExportQueryProcessor.
+//
+// エクスポート中に投稿されたメッセージが一部のルームにだけ含まれないよう、
+// 読み取り専用の REPEATABLE READ トランザクションで同じスナップショットから読む
+func (q *RoomExportQueryProcessorOnDB) ExportRooms(ctx context.Context, inp queryprocessor.ExportRoomsInput, w queryprocessor.RoomExportWriter) error {
+	tx, err := q.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rollback", slog.Any("err", err))
+		}
+	}()
+	queries := db.New(q.pool).WithTx(tx)
+
+	var rooms []db.GetRoomsForExportRow
+	if inp.RoomID != nil {
+		row, err := queries.GetRoomForExport(ctx, *inp.RoomID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return queryprocessor.ErrRoomNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get room: %w", err)
+		}
+		rooms = []db.GetRoomsForExportRow{db.GetRoomsForExportRow(row)}
+	} else {
+		rooms, err = queries.GetRoomsForExport(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get rooms: %w", err)
+		}
+	}
+
+	for _, room := range rooms {
+		if err := exportRoom(ctx, queries, room, w); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
+
+func exportRoom(ctx context.Context, queries *db.Queries, room db.GetRoomsForExportRow, w queryprocessor.RoomExportWriter) error {
+	dto := queryprocessor.RoomDTO{
+		ID:        room.ID,
+		Name:      room.Name,
+		Topic:     room.Topic,
+		OwnerID:   room.CreatedBy,
+		CreatedAt: room.CreatedAt.Time,
+	}
+	if room.ArchivedAt.Valid {
+		dto.ArchivedAt = &room.ArchivedAt.Time
+	}
+	if room.RetentionDays.Valid {
+		days := int(room.RetentionDays.Int32)
+		dto.RetentionDays = &days
+	}
+	if err := w.WriteRoom(dto); err != nil {
+		return err
+	}
+
+	members, err := queries.GetRoomMembersForExport(ctx, room.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get room members: %w", err)
+	}
+	for _, m := range members {
+		if err := w.WriteMember(queryprocessor.MemberDTO{
+			RoomID:   room.ID,
+			ID:       m.ID,
+			UserName: m.Username,
+			Kind:     m.Kind,
+		}); err != nil {
+			return err
+		}
+	}
+
+	params := db.GetMessagesForExportParams{
+		RoomID:         room.ID,
+		AfterCreatedAt: pgtype.Timestamp{InfinityModifier: pgtype.NegativeInfinity, Valid: true},
+		AfterID:        uuid.Nil,
+		LimitCount:     exportMessagePageSize,
+	}
+	for {
+		rows, err := queries.GetMessagesForExport(ctx, params)
+		if err != nil {
+			return fmt.Errorf("failed to get messages: %w", err)
+		}
+		for _, m := range rows {
+			if err := w.WriteMessage(queryprocessor.MessageDTO{
+				RoomID:    room.ID,
+				ID:        m.ID,
+				AuthorID:  m.AuthorID,
+				Kind:      m.Kind,
+				Content:   m.Content,
+				Format:    m.Format,
+				Event:     m.Event,
+				CreatedAt: m.CreatedAt.Time,
+				UpdatedAt: m.UpdatedAt.Time,
+			}); err != nil {
+				return err
+			}
+		}
+		if len(rows) < exportMessagePageSize {
+			return nil
+		}
+
+		last := rows[len(rows)-1]
+		params.AfterCreatedAt = last.CreatedAt
+		params.AfterID = last.ID
+	}
+}
+
+var _ queryprocessor.RoomExportQueryProcessor = new(RoomExportQueryProcessorOnDB)
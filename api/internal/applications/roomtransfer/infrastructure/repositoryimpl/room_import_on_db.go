@@ -0,0 +1,166 @@
+package repositoryimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/roomtransfer/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/db"
+)
+
+func NewRoomImportRepositoryOnDB(pool *pgxpool.Pool) *RoomImportRepositoryOnDB {
+	return &RoomImportRepositoryOnDB{pool}
+}
+
+type RoomImportRepositoryOnDB struct {
+	pool *pgxpool.Pool
+}
+
+// ResolveAccounts implements repository.RoomImportRepository.
+//
+// 別の環境で同じユーザー名のアカウントが同じ人とは限らないため、ユーザー名では対応付けない。
+// プレースホルダーは元のユーザー名で作成し、使われている場合は末尾にランダムな文字列を付ける
+func (r *RoomImportRepositoryOnDB) ResolveAccounts(ctx context.Context, inp repository.ResolveAccountsInput) (repository.ResolveAccountsOutput, error) {
+	out := repository.ResolveAccountsOutput{IDs: make(map[uuid.UUID]uuid.UUID, len(inp.Accounts))}
+	err := r.withTx(ctx, func(queries *db.Queries) error {
+		ids := make([]uuid.UUID, len(inp.Accounts))
+		for i, a := range inp.Accounts {
+			ids[i] = a.ID
+		}
+		byID, err := queries.GetAccountsByIDs(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("failed to get accounts by ids: %w", err)
+		}
+		for _, row := range byID {
+			out.IDs[row.ID] = row.ID
+		}
+
+		for _, a := range inp.Accounts {
+			if _, ok := out.IDs[a.ID]; ok {
+				continue
+			}
+			id, err := createPlaceholderAccount(ctx, queries, a.UserName)
+			if err != nil {
+				return err
+			}
+			out.IDs[a.ID] = id
+			out.Placeholders++
+		}
+		return nil
+	})
+	if err != nil {
+		return repository.ResolveAccountsOutput{}, err
+	}
+	return out, nil
+}
+
+const (
+	// placeholderSuffixLength はユーザー名が使われている場合に付けるランダムな文字列の長さ
+	placeholderSuffixLength = 8
+	// maxPlaceholderAttempts はプレースホルダーのユーザー名を選び直す回数の上限
+	maxPlaceholderAttempts = 5
+	maxUserNameLength      = 32
+)
+
+// createPlaceholderAccount は userName か、使われている場合は末尾を置き換えた名前でプレースホルダーを作成する
+func createPlaceholderAccount(ctx context.Context, queries *db.Queries, userName string) (uuid.UUID, error) {
+	name := userName
+	for range maxPlaceholderAttempts {
+		id, err := queries.CreatePlaceholderAccount(ctx, name)
+		if err == nil {
+			return id, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, fmt.Errorf("failed to create placeholder account: %w", err)
+		}
+		// UUID の先頭はユーザー名に使える 16 進数の文字だけからなる
+		name = userName[:min(len(userName), maxUserNameLength-placeholderSuffixLength)] + uuid.NewString()[:placeholderSuffixLength]
+	}
+	return uuid.Nil, fmt.Errorf("failed to choose a username for placeholder account %s", userName)
+}
+
+// ImportRoom implements repository.RoomImportRepository.
+//
+// メッセージは COPY でまとめて作成し、最後にルームの最終アクティビティをメッセージから計算し直す
+func (r *RoomImportRepositoryOnDB) ImportRoom(ctx context.Context, inp repository.ImportRoomInput) (repository.ImportRoomOutput, error) {
+	var out repository.ImportRoomOutput
+	err := r.withTx(ctx, func(queries *db.Queries) error {
+		params := db.ImportRoomParams{
+			Name:      inp.Name,
+			Topic:     inp.Topic,
+			CreatedBy: inp.OwnerID,
+			CreatedAt: pgtype.Timestamp{Time: inp.CreatedAt, Valid: true},
+		}
+		if inp.ArchivedAt != nil {
+			params.ArchivedAt = pgtype.Timestamp{Time: *inp.ArchivedAt, Valid: true}
+		}
+		if inp.RetentionDays != nil {
+			params.RetentionDays = pgtype.Int4{Int32: int32(*inp.RetentionDays), Valid: true}
+		}
+		roomID, err := queries.ImportRoom(ctx, params)
+		if err != nil {
+			return fmt.Errorf("failed to import room: %w", err)
+		}
+		out.ID = roomID
+
+		if len(inp.Messages) == 0 {
+			return nil
+		}
+
+		messages := make([]db.ImportMessagesParams, len(inp.Messages))
+		for i, m := range inp.Messages {
+			messages[i] = db.ImportMessagesParams{
+				ID:        m.ID,
+				RoomID:    roomID,
+				AuthorID:  m.AuthorID,
+				Kind:      m.Kind,
+				Content:   m.Content,
+				Format:    m.Format,
+				Event:     m.Event,
+				CreatedAt: pgtype.Timestamp{Time: m.CreatedAt, Valid: true},
+				UpdatedAt: pgtype.Timestamp{Time: m.UpdatedAt, Valid: true},
+			}
+		}
+		if _, err := queries.ImportMessages(ctx, messages); err != nil {
+			return fmt.Errorf("failed to import messages: %w", err)
+		}
+
+		if err := queries.RefreshRoomLastActivity(ctx, roomID); err != nil {
+			return fmt.Errorf("failed to refresh last activity: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return repository.ImportRoomOutput{}, err
+	}
+	return out, nil
+}
+
+func (r *RoomImportRepositoryOnDB) withTx(ctx context.Context, fn func(queries *db.Queries) error) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rollback", slog.Any("err", err))
+		}
+	}()
+
+	if err := fn(db.New(r.pool).WithTx(tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
+
+var _ repository.RoomImportRepository = new(RoomImportRepositoryOnDB)
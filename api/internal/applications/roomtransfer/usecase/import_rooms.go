@@ -0,0 +1,248 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/roomtransfer/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+)
+
+// ImportRoom の ID と OwnerID はエクスポート元の ID
+type ImportRoom struct {
+	ID         uuid.UUID
+	Name       domain.RoomName
+	Topic      domain.RoomTopic
+	OwnerID    uuid.UUID
+	CreatedAt  time.Time
+	ArchivedAt *time.Time
+	Retention  *domain.RetentionPeriod
+}
+
+// ImportMember はルームの作成者またはメッセージの投稿者。ID はエクスポート元のアカウント ID
+type ImportMember struct {
+	RoomID   uuid.UUID
+	ID       uuid.UUID
+	UserName domain.UserName
+}
+
+// ImportMessage の Event はシステムメッセージの場合のみ設定する
+type ImportMessage struct {
+	RoomID    uuid.UUID
+	ID        uuid.UUID
+	AuthorID  uuid.UUID
+	Kind      domain.MessageKind
+	Content   domain.MessageContent
+	Format    domain.MessageFormat
+	Event     *domain.SystemEvent
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ImportRecord は Room, Member, Message のいずれか 1 つだけを持つ
+type ImportRecord struct {
+	Room    *ImportRoom
+	Member  *ImportMember
+	Message *ImportMessage
+}
+
+// ImportRecordReader はインポートするレコードを先頭から 1 件ずつ返す。読み終えたら io.EOF を返す
+type ImportRecordReader interface {
+	Next() (ImportRecord, error)
+}
+
+var ErrInvalidImport = errors.New("invalid import")
+
+type ImportRoomsUsecase struct {
+	repo repository.RoomImportRepository
+}
+
+// ImportRoomsOutput の Rooms はインポートした順に並ぶ
+type ImportRoomsOutput struct {
+	Rooms               []ImportedRoom
+	PlaceholderAccounts int
+}
+
+type ImportedRoom struct {
+	OriginalID uuid.UUID
+	ID         uuid.UUID
+	Messages   int
+}
+
+func NewImportRoomsUsecase(repo repository.RoomImportRepository) *ImportRoomsUsecase {
+	return &ImportRoomsUsecase{repo}
+}
+
+// Execute はルームごとにレコードをまとめ、読み終えたルームから順に作成する
+//
+// ルームのレコードの後にそのルームのメンバー、メッセージの順に並んでいる必要がある。
+// 途中でエラーになった場合、それまでに作成したルームは残る
+func (u *ImportRoomsUsecase) Execute(ctx context.Context, r ImportRecordReader) (ImportRoomsOutput, error) {
+	imp := &roomImport{
+		repo:     u.repo,
+		accounts: map[uuid.UUID]uuid.UUID{},
+	}
+
+	for {
+		rec, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return ImportRoomsOutput{}, err
+		}
+
+		switch {
+		case rec.Room != nil:
+			if err := imp.flush(ctx); err != nil {
+				return ImportRoomsOutput{}, err
+			}
+			imp.room = rec.Room
+			imp.members = map[uuid.UUID]ImportMember{}
+			imp.messages = nil
+
+		case rec.Member != nil:
+			if imp.room == nil || rec.Member.RoomID != imp.room.ID {
+				return ImportRoomsOutput{}, fmt.Errorf("%w: member %s is not preceded by its room", ErrInvalidImport, rec.Member.ID)
+			}
+			imp.members[rec.Member.ID] = *rec.Member
+
+		case rec.Message != nil:
+			if imp.room == nil || rec.Message.RoomID != imp.room.ID {
+				return ImportRoomsOutput{}, fmt.Errorf("%w: message %s is not preceded by its room", ErrInvalidImport, rec.Message.ID)
+			}
+			if _, ok := imp.members[rec.Message.AuthorID]; !ok {
+				return ImportRoomsOutput{}, fmt.Errorf("%w: author %s of message %s is not a member", ErrInvalidImport, rec.Message.AuthorID, rec.Message.ID)
+			}
+			imp.messages = append(imp.messages, *rec.Message)
+		}
+	}
+
+	if err := imp.flush(ctx); err != nil {
+		return ImportRoomsOutput{}, err
+	}
+	return imp.out, nil
+}
+
+// roomImport は読み込み中のルームと、ルームをまたいで使うアカウントの対応を持つ
+type roomImport struct {
+	repo repository.RoomImportRepository
+	// accounts はエクスポート元のアカウント ID からこの環境のアカウント ID への対応
+	accounts map[uuid.UUID]uuid.UUID
+
+	room     *ImportRoom
+	members  map[uuid.UUID]ImportMember
+	messages []ImportMessage
+
+	out ImportRoomsOutput
+}
+
+// flush は読み込み中のルームを作成する
+func (i *roomImport) flush(ctx context.Context) error {
+	if i.room == nil {
+		return nil
+	}
+	room := i.room
+	i.room = nil
+
+	if _, ok := i.members[room.OwnerID]; !ok {
+		return fmt.Errorf("%w: owner %s of room %s is not a member", ErrInvalidImport, room.OwnerID, room.ID)
+	}
+
+	var unresolved []repository.ImportAccount
+	for _, m := range i.members {
+		if _, ok := i.accounts[m.ID]; !ok {
+			unresolved = append(unresolved, repository.ImportAccount{ID: m.ID, UserName: m.UserName.String()})
+		}
+	}
+	if len(unresolved) > 0 {
+		res, err := i.repo.ResolveAccounts(ctx, repository.ResolveAccountsInput{Accounts: unresolved})
+		if err != nil {
+			return fmt.Errorf("failed to resolve accounts: %w", err)
+		}
+		for from, to := range res.IDs {
+			i.accounts[from] = to
+		}
+		i.out.PlaceholderAccounts += res.Placeholders
+	}
+
+	// ピン留めのイベントが新しい ID を指すよう、先にすべてのメッセージの ID を決める
+	messageIDs := make(map[domain.MessageID]uuid.UUID, len(i.messages))
+	for _, m := range i.messages {
+		messageIDs[domain.MessageIDFromUuid(m.ID)] = uuid.New()
+	}
+
+	messages := make([]repository.ImportMessage, 0, len(i.messages))
+	for _, m := range i.messages {
+		var event []byte
+		if m.Event != nil {
+			e := remapSystemEvent(*m.Event, messageIDs)
+			b, err := json.Marshal(e)
+			if err != nil {
+				return fmt.Errorf("failed to marshal system event: %w", err)
+			}
+			event = b
+		}
+
+		messages = append(messages, repository.ImportMessage{
+			ID:        messageIDs[domain.MessageIDFromUuid(m.ID)],
+			AuthorID:  i.accounts[m.AuthorID],
+			Kind:      string(m.Kind),
+			Content:   m.Content.String(),
+			Format:    m.Format.String(),
+			Event:     event,
+			CreatedAt: m.CreatedAt,
+			UpdatedAt: m.UpdatedAt,
+		})
+	}
+
+	var retentionDays *int
+	if room.Retention != nil {
+		days := room.Retention.Days()
+		retentionDays = &days
+	}
+
+	res, err := i.repo.ImportRoom(ctx, repository.ImportRoomInput{
+		Name:          room.Name.String(),
+		Topic:         room.Topic.String(),
+		OwnerID:       i.accounts[room.OwnerID],
+		CreatedAt:     room.CreatedAt,
+		ArchivedAt:    room.ArchivedAt,
+		RetentionDays: retentionDays,
+		Messages:      messages,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to import room %s: %w", room.ID, err)
+	}
+
+	i.out.Rooms = append(i.out.Rooms, ImportedRoom{
+		OriginalID: room.ID,
+		ID:         res.ID,
+		Messages:   len(messages),
+	})
+	return nil
+}
+
+// remapSystemEvent はピン留めのイベントが指すメッセージを新しい ID に置き換える
+//
+// 指しているメッセージがエクスポートに含まれていない場合は元の ID のまま残す
+func remapSystemEvent(e domain.SystemEvent, messageIDs map[domain.MessageID]uuid.UUID) domain.SystemEvent {
+	newID, ok := messageIDs[e.MessageID()]
+	if !ok {
+		return e
+	}
+	id := domain.MessageIDFromUuid(newID)
+	switch e.Type() {
+	case domain.SystemEventMessagePinned:
+		return domain.NewMessagePinnedEvent(id)
+	case domain.SystemEventMessageUnpinned:
+		return domain.NewMessageUnpinnedEvent(id)
+	default:
+		return e
+	}
+}
@@ -0,0 +1,236 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/roomtransfer/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/roomtransfer/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRoomImportRepository struct {
+	resolveAccountsFunc func(ctx context.Context, inp repository.ResolveAccountsInput) (repository.ResolveAccountsOutput, error)
+	importRoomFunc      func(ctx context.Context, inp repository.ImportRoomInput) (repository.ImportRoomOutput, error)
+}
+
+func (m *mockRoomImportRepository) ResolveAccounts(ctx context.Context, inp repository.ResolveAccountsInput) (repository.ResolveAccountsOutput, error) {
+	if m.resolveAccountsFunc != nil {
+		return m.resolveAccountsFunc(ctx, inp)
+	}
+	return repository.ResolveAccountsOutput{}, nil
+}
+
+func (m *mockRoomImportRepository) ImportRoom(ctx context.Context, inp repository.ImportRoomInput) (repository.ImportRoomOutput, error) {
+	if m.importRoomFunc != nil {
+		return m.importRoomFunc(ctx, inp)
+	}
+	return repository.ImportRoomOutput{}, nil
+}
+
+// sliceReader は用意したレコードを順に返す
+type sliceReader struct {
+	records []usecase.ImportRecord
+}
+
+func (r *sliceReader) Next() (usecase.ImportRecord, error) {
+	if len(r.records) == 0 {
+		return usecase.ImportRecord{}, io.EOF
+	}
+	rec := r.records[0]
+	r.records = r.records[1:]
+	return rec, nil
+}
+
+func roomRecord(t *testing.T, id, ownerID uuid.UUID) usecase.ImportRecord {
+	t.Helper()
+	name, err := domain.NewRoomName("general")
+	require.NoError(t, err)
+	return usecase.ImportRecord{Room: &usecase.ImportRoom{
+		ID:        id,
+		Name:      name,
+		OwnerID:   ownerID,
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}}
+}
+
+func memberRecord(t *testing.T, roomID, id uuid.UUID, username string) usecase.ImportRecord {
+	t.Helper()
+	name, err := domain.NewUserName(username)
+	require.NoError(t, err)
+	return usecase.ImportRecord{Member: &usecase.ImportMember{RoomID: roomID, ID: id, UserName: name}}
+}
+
+func messageRecord(t *testing.T, roomID, id, authorID uuid.UUID, content string) usecase.ImportRecord {
+	t.Helper()
+	c, err := domain.NewMessageContent(content)
+	require.NoError(t, err)
+	return usecase.ImportRecord{Message: &usecase.ImportMessage{
+		RoomID:    roomID,
+		ID:        id,
+		AuthorID:  authorID,
+		Kind:      domain.MessageKindUser,
+		Content:   c,
+		Format:    domain.MessageFormatPlain,
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 6, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 1, 2, 3, 4, 6, 0, time.UTC),
+	}}
+}
+
+func TestImportRoomsUsecase_Execute(t *testing.T) {
+	t.Parallel()
+
+	t.Run("アカウントはルームをまたいで 1 回だけ解決する", func(t *testing.T) {
+		t.Parallel()
+
+		roomA, roomB := uuid.New(), uuid.New()
+		alice, bob := uuid.New(), uuid.New()
+		resolved := map[uuid.UUID]uuid.UUID{alice: uuid.New(), bob: uuid.New()}
+
+		var resolveCalls [][]uuid.UUID
+		var imported []repository.ImportRoomInput
+		repo := &mockRoomImportRepository{
+			resolveAccountsFunc: func(ctx context.Context, inp repository.ResolveAccountsInput) (repository.ResolveAccountsOutput, error) {
+				out := repository.ResolveAccountsOutput{IDs: map[uuid.UUID]uuid.UUID{}, Placeholders: len(inp.Accounts)}
+				var ids []uuid.UUID
+				for _, a := range inp.Accounts {
+					ids = append(ids, a.ID)
+					out.IDs[a.ID] = resolved[a.ID]
+				}
+				resolveCalls = append(resolveCalls, ids)
+				return out, nil
+			},
+			importRoomFunc: func(ctx context.Context, inp repository.ImportRoomInput) (repository.ImportRoomOutput, error) {
+				imported = append(imported, inp)
+				return repository.ImportRoomOutput{ID: uuid.New()}, nil
+			},
+		}
+
+		out, err := usecase.NewImportRoomsUsecase(repo).Execute(t.Context(), &sliceReader{records: []usecase.ImportRecord{
+			roomRecord(t, roomA, alice),
+			memberRecord(t, roomA, alice, "alice"),
+			messageRecord(t, roomA, uuid.New(), alice, "hello"),
+			roomRecord(t, roomB, alice),
+			memberRecord(t, roomB, alice, "alice"),
+			memberRecord(t, roomB, bob, "bob"),
+			messageRecord(t, roomB, uuid.New(), bob, "hi"),
+		}})
+
+		require.NoError(t, err)
+		require.Equal(t, [][]uuid.UUID{{alice}, {bob}}, resolveCalls)
+		require.Equal(t, 2, out.PlaceholderAccounts)
+		require.Len(t, out.Rooms, 2)
+		require.Equal(t, roomA, out.Rooms[0].OriginalID)
+		require.Equal(t, roomB, out.Rooms[1].OriginalID)
+
+		require.Len(t, imported, 2)
+		require.Equal(t, resolved[alice], imported[1].OwnerID)
+		require.Equal(t, resolved[bob], imported[1].Messages[0].AuthorID)
+	})
+
+	t.Run("ピン留めのイベントは新しいメッセージ ID を指す", func(t *testing.T) {
+		t.Parallel()
+
+		roomID, alice := uuid.New(), uuid.New()
+		pinnedID, missingID := uuid.New(), uuid.New()
+		pinned := domain.NewMessagePinnedEvent(domain.MessageIDFromUuid(pinnedID))
+		unpinned := domain.NewMessageUnpinnedEvent(domain.MessageIDFromUuid(missingID))
+		systemRecord := func(event domain.SystemEvent) usecase.ImportRecord {
+			c, err := domain.NewMessageContent(event.Summary())
+			require.NoError(t, err)
+			return usecase.ImportRecord{Message: &usecase.ImportMessage{
+				RoomID:   roomID,
+				ID:       uuid.New(),
+				AuthorID: alice,
+				Kind:     domain.MessageKindSystem,
+				Content:  c,
+				Format:   domain.MessageFormatPlain,
+				Event:    &event,
+			}}
+		}
+
+		var imported repository.ImportRoomInput
+		repo := &mockRoomImportRepository{
+			resolveAccountsFunc: func(ctx context.Context, inp repository.ResolveAccountsInput) (repository.ResolveAccountsOutput, error) {
+				return repository.ResolveAccountsOutput{IDs: map[uuid.UUID]uuid.UUID{alice: alice}}, nil
+			},
+			importRoomFunc: func(ctx context.Context, inp repository.ImportRoomInput) (repository.ImportRoomOutput, error) {
+				imported = inp
+				return repository.ImportRoomOutput{ID: uuid.New()}, nil
+			},
+		}
+
+		_, err := usecase.NewImportRoomsUsecase(repo).Execute(t.Context(), &sliceReader{records: []usecase.ImportRecord{
+			roomRecord(t, roomID, alice),
+			memberRecord(t, roomID, alice, "alice"),
+			messageRecord(t, roomID, pinnedID, alice, "important"),
+			systemRecord(pinned),
+			systemRecord(unpinned),
+		}})
+		require.NoError(t, err)
+		require.Len(t, imported.Messages, 3)
+
+		newID := imported.Messages[0].ID
+		require.NotEqual(t, pinnedID, newID)
+
+		var event struct {
+			MessageID string `json:"messageId"`
+		}
+		require.NoError(t, json.Unmarshal(imported.Messages[1].Event, &event))
+		require.Equal(t, newID.String(), event.MessageID)
+		// エクスポートに含まれないメッセージは元の ID のまま
+		require.NoError(t, json.Unmarshal(imported.Messages[2].Event, &event))
+		require.Equal(t, missingID.String(), event.MessageID)
+	})
+
+	t.Run("レコードの並びが不正な場合は ErrInvalidImport", func(t *testing.T) {
+		t.Parallel()
+
+		roomID, otherRoomID := uuid.New(), uuid.New()
+		alice, bob := uuid.New(), uuid.New()
+
+		tests := []struct {
+			name    string
+			records []usecase.ImportRecord
+		}{
+			{"room より前の member", []usecase.ImportRecord{
+				memberRecord(t, roomID, alice, "alice"),
+			}},
+			{"別のルームの message", []usecase.ImportRecord{
+				roomRecord(t, roomID, alice),
+				memberRecord(t, roomID, alice, "alice"),
+				messageRecord(t, otherRoomID, uuid.New(), alice, "hello"),
+			}},
+			{"member にない投稿者", []usecase.ImportRecord{
+				roomRecord(t, roomID, alice),
+				memberRecord(t, roomID, alice, "alice"),
+				messageRecord(t, roomID, uuid.New(), bob, "hello"),
+			}},
+			{"member にない作成者", []usecase.ImportRecord{
+				roomRecord(t, roomID, alice),
+				memberRecord(t, roomID, bob, "bob"),
+			}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				repo := &mockRoomImportRepository{
+					importRoomFunc: func(ctx context.Context, inp repository.ImportRoomInput) (repository.ImportRoomOutput, error) {
+						t.Fatal("should not be called")
+						return repository.ImportRoomOutput{}, nil
+					},
+				}
+
+				_, err := usecase.NewImportRoomsUsecase(repo).Execute(t.Context(), &sliceReader{records: tt.records})
+				require.ErrorIs(t, err, usecase.ErrInvalidImport)
+			})
+		}
+	})
+}
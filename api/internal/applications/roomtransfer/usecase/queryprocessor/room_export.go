@@ -0,0 +1,62 @@
+package queryprocessor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportRoomsInput の RoomID が nil の場合は削除されていないすべてのルームをエクスポートする
+type ExportRoomsInput struct {
+	RoomID *uuid.UUID
+}
+
+// RoomDTO の ArchivedAt はアーカイブ済みの場合のみ、RetentionDays は保存期間を指定した場合のみ設定する
+type RoomDTO struct {
+	ID            uuid.UUID
+	Name          string
+	Topic         string
+	OwnerID       uuid.UUID
+	CreatedAt     time.Time
+	ArchivedAt    *time.Time
+	RetentionDays *int
+}
+
+// MemberDTO はルームの作成者とメッセージの投稿者
+type MemberDTO struct {
+	RoomID   uuid.UUID
+	ID       uuid.UUID
+	UserName string
+	Kind     string
+}
+
+// MessageDTO の Event はシステムメッセージの場合のみ設定する
+type MessageDTO struct {
+	RoomID    uuid.UUID
+	ID        uuid.UUID
+	AuthorID  uuid.UUID
+	Kind      string
+	Content   string
+	Format    string
+	Event     json.RawMessage
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// RoomExportWriter はエクスポートしたレコードを順に受け取る
+//
+// ルームごとに WriteRoom, WriteMember, WriteMessage の順に呼ばれ、メッセージは投稿日時の古い順に渡される
+type RoomExportWriter interface {
+	WriteRoom(room RoomDTO) error
+	WriteMember(member MemberDTO) error
+	WriteMessage(message MessageDTO) error
+}
+
+var ErrRoomNotFound = errors.New("room not found")
+
+type RoomExportQueryProcessor interface {
+	ExportRooms(ctx context.Context, inp ExportRoomsInput, w RoomExportWriter) error
+}
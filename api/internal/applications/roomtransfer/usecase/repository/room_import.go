@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImportAccount の ID はエクスポート元のアカウント ID
+type ImportAccount struct {
+	ID       uuid.UUID
+	UserName string
+}
+
+type ResolveAccountsInput struct {
+	Accounts []ImportAccount
+}
+
+// ResolveAccountsOutput の IDs はエクスポート元のアカウント ID からこの環境のアカウント ID への対応
+//
+// Placeholders は新たに作成したプレースホルダーのアカウントの数
+type ResolveAccountsOutput struct {
+	IDs          map[uuid.UUID]uuid.UUID
+	Placeholders int
+}
+
+// ImportRoomInput の OwnerID とメッセージの AuthorID は ResolveAccounts で解決したアカウント ID
+type ImportRoomInput struct {
+	Name          string
+	Topic         string
+	OwnerID       uuid.UUID
+	CreatedAt     time.Time
+	ArchivedAt    *time.Time
+	RetentionDays *int
+	Messages      []ImportMessage
+}
+
+// ImportMessage の ID はこの環境で使う新しい ID
+type ImportMessage struct {
+	ID        uuid.UUID
+	AuthorID  uuid.UUID
+	Kind      string
+	Content   string
+	Format    string
+	Event     []byte
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type ImportRoomOutput struct {
+	ID uuid.UUID
+}
+
+type RoomImportRepository interface {
+	// ResolveAccounts は同じ ID のアカウントを探し、なければプレースホルダーのアカウントを作成する
+	//
+	// 同じユーザー名のアカウントは別人の可能性があるため対応付けない
+	ResolveAccounts(ctx context.Context, inp ResolveAccountsInput) (ResolveAccountsOutput, error)
+	// ImportRoom はルームとメッセージを 1 つのトランザクションで作成する
+	ImportRoom(ctx context.Context, inp ImportRoomInput) (ImportRoomOutput, error)
+}
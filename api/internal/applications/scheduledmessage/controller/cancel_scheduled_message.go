@@ -0,0 +1,32 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+)
+
+type CancelScheduledMessageInput struct {
+	RoomID             string
+	ScheduledMessageID string
+	AuthorID           string
+}
+
+type CancelScheduledMessageController struct {
+	repo repository.ScheduledMessageRepository
+}
+
+func NewCancelScheduledMessageController(repo repository.ScheduledMessageRepository) *CancelScheduledMessageController {
+	return &CancelScheduledMessageController{repo}
+}
+
+func (c *CancelScheduledMessageController) CancelScheduledMessage(ctx context.Context, inp CancelScheduledMessageInput) error {
+	uc := usecase.NewCancelScheduledMessageUsecase(c.repo)
+	return tracing.ExecuteNoOutput(ctx, "CancelScheduledMessageUsecase", uc.Execute, usecase.CancelScheduledMessageInput{
+		RoomID:             inp.RoomID,
+		ScheduledMessageID: inp.ScheduledMessageID,
+		AuthorID:           inp.AuthorID,
+	})
+}
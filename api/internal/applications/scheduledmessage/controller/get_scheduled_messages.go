@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase/queryprocessor"
+)
+
+const (
+	defaultScheduledMessagesLimit = 50
+	maxScheduledMessagesLimit     = 100
+)
+
+var (
+	ErrInvalidScheduledMessagesLimit = errors.New("invalid scheduled messages limit")
+)
+
+// GetScheduledMessagesInput の Limit が 0 の場合はデフォルト件数を返す
+type GetScheduledMessagesInput struct {
+	RoomID   string
+	AuthorID string
+	Limit    int
+}
+
+type GetScheduledMessagesOutput struct {
+	ScheduledMessages []ScheduledMessage `json:"scheduledMessages"`
+}
+
+// ScheduledMessage の Status は pending, sent, canceled, failed のいずれか
+type ScheduledMessage struct {
+	ID        string `json:"id"`
+	Content   string `json:"content"`
+	Format    string `json:"format"`
+	SendAt    string `json:"sendAt"`
+	Status    string `json:"status"`
+	MessageID string `json:"messageId,omitempty"`
+	Error     string `json:"error,omitempty"`
+	CreatedAt string `json:"createdAt"`
+	SentAt    string `json:"sentAt,omitempty"`
+}
+
+type GetScheduledMessagesController struct {
+	query queryprocessor.ScheduledMessageQueryProcessor
+}
+
+func NewGetScheduledMessagesController(query queryprocessor.ScheduledMessageQueryProcessor) *GetScheduledMessagesController {
+	return &GetScheduledMessagesController{query}
+}
+
+// GetScheduledMessages は作成者が予約したメッセージを送信時刻の新しい順に返す
+func (c *GetScheduledMessagesController) GetScheduledMessages(ctx context.Context, inp GetScheduledMessagesInput) (GetScheduledMessagesOutput, error) {
+	limit := inp.Limit
+	if limit == 0 {
+		limit = defaultScheduledMessagesLimit
+	}
+	if limit < 0 || limit > maxScheduledMessagesLimit {
+		return GetScheduledMessagesOutput{}, ErrInvalidScheduledMessagesLimit
+	}
+
+	roomID, err := uuid.Parse(inp.RoomID)
+	if err != nil {
+		return GetScheduledMessagesOutput{}, queryprocessor.ErrRoomNotFound
+	}
+	authorID, err := uuid.Parse(inp.AuthorID)
+	if err != nil {
+		return GetScheduledMessagesOutput{}, fmt.Errorf("bad author id: %w", err)
+	}
+
+	res, err := c.query.GetScheduledMessages(ctx, queryprocessor.GetScheduledMessagesInput{
+		RoomID:   roomID,
+		AuthorID: authorID,
+		Limit:    limit,
+	})
+	if err != nil {
+		return GetScheduledMessagesOutput{}, fmt.Errorf("failed to get scheduled messages: %w", err)
+	}
+
+	messages := make([]ScheduledMessage, len(res.Messages))
+	for i, m := range res.Messages {
+		messages[i] = ScheduledMessage{
+			ID:        m.ID,
+			Content:   m.Content,
+			Format:    m.Format,
+			SendAt:    m.SendAt,
+			Status:    m.Status,
+			MessageID: m.MessageID,
+			Error:     m.Error,
+			CreatedAt: m.CreatedAt,
+			SentAt:    m.SentAt,
+		}
+	}
+	return GetScheduledMessagesOutput{ScheduledMessages: messages}, nil
+}
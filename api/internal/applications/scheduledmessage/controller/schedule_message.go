@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+)
+
+// ScheduleMessageInput の SendAt は RFC 3339 形式の送信時刻
+type ScheduleMessageInput struct {
+	Content  string    `json:"content"`
+	Format   string    `json:"format"`
+	SendAt   time.Time `json:"sendAt"`
+	RoomID   string    `json:"-"`
+	AuthorID string    `json:"-"`
+}
+
+type ScheduleMessageOutput struct {
+	ID     string `json:"id"`
+	SendAt string `json:"sendAt"`
+}
+
+type ScheduleMessageController struct {
+	repo repository.ScheduledMessageRepository
+}
+
+func NewScheduleMessageController(repo repository.ScheduledMessageRepository) *ScheduleMessageController {
+	return &ScheduleMessageController{repo}
+}
+
+func (c *ScheduleMessageController) ScheduleMessage(ctx context.Context, inp ScheduleMessageInput) (ScheduleMessageOutput, error) {
+	content, err := domain.NewMessageContent(inp.Content)
+	if err != nil {
+		return ScheduleMessageOutput{}, fmt.Errorf("bad content: %w", err)
+	}
+
+	format, err := domain.NewMessageFormat(inp.Format)
+	if err != nil {
+		return ScheduleMessageOutput{}, fmt.Errorf("bad format: %w", err)
+	}
+
+	uc := usecase.NewScheduleMessageUsecase(c.repo)
+	res, err := tracing.Execute(ctx, "ScheduleMessageUsecase", uc.Execute, usecase.ScheduleMessageInput{
+		RoomID:   inp.RoomID,
+		AuthorID: inp.AuthorID,
+		Content:  content,
+		Format:   format,
+		SendAt:   inp.SendAt,
+	})
+	if err != nil {
+		return ScheduleMessageOutput{}, err
+	}
+
+	return ScheduleMessageOutput{
+		ID:     res.ID,
+		SendAt: res.SendAt.Format(time.RFC3339),
+	}, nil
+}
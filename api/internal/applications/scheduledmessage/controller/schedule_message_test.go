@@ -0,0 +1,103 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/controller"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock implementations
+type mockScheduledMessageRepository struct {
+	createScheduledMessageFunc func(ctx context.Context, inp repository.CreateScheduledMessageInput) (repository.CreateScheduledMessageOutput, error)
+}
+
+func (m *mockScheduledMessageRepository) CreateScheduledMessage(ctx context.Context, inp repository.CreateScheduledMessageInput) (repository.CreateScheduledMessageOutput, error) {
+	if m.createScheduledMessageFunc != nil {
+		return m.createScheduledMessageFunc(ctx, inp)
+	}
+	return repository.CreateScheduledMessageOutput{}, nil
+}
+
+func (m *mockScheduledMessageRepository) CancelScheduledMessage(ctx context.Context, inp repository.CancelScheduledMessageInput) error {
+	return nil
+}
+
+func (m *mockScheduledMessageRepository) ClaimScheduledMessages(ctx context.Context, inp repository.ClaimScheduledMessagesInput) (repository.ClaimScheduledMessagesOutput, error) {
+	return repository.ClaimScheduledMessagesOutput{}, nil
+}
+
+func (m *mockScheduledMessageRepository) FailScheduledMessage(ctx context.Context, inp repository.FailScheduledMessageInput) error {
+	return nil
+}
+
+func TestScheduleMessageController_ScheduleMessage(t *testing.T) {
+	t.Parallel()
+
+	roomID := uuid.NewString()
+	authorID := uuid.NewString()
+
+	t.Run("予約した送信時刻を RFC 3339 形式で返す", func(t *testing.T) {
+		t.Parallel()
+
+		id := uuid.New()
+		sendAt := time.Now().Add(time.Hour).Truncate(time.Second)
+		mockRepo := &mockScheduledMessageRepository{
+			createScheduledMessageFunc: func(ctx context.Context, inp repository.CreateScheduledMessageInput) (repository.CreateScheduledMessageOutput, error) {
+				require.Equal(t, "**stand-up**", inp.Content)
+				require.Equal(t, "markdown", inp.Format)
+				return repository.CreateScheduledMessageOutput{ID: id}, nil
+			},
+		}
+
+		out, err := controller.NewScheduleMessageController(mockRepo).ScheduleMessage(t.Context(), controller.ScheduleMessageInput{
+			Content:  "**stand-up**",
+			Format:   "markdown",
+			SendAt:   sendAt,
+			RoomID:   roomID,
+			AuthorID: authorID,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, id.String(), out.ID)
+		require.Equal(t, sendAt.UTC().Format(time.RFC3339), out.SendAt)
+	})
+
+	t.Run("不正な入力でエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name     string
+			input    controller.ScheduleMessageInput
+			expected error
+		}{
+			{"empty content", controller.ScheduleMessageInput{Content: ""}, domain.ErrInvalidMessageContent},
+			{"bad format", controller.ScheduleMessageInput{Content: "hi", Format: "html"}, domain.ErrInvalidMessageFormat},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				mockRepo := &mockScheduledMessageRepository{
+					createScheduledMessageFunc: func(ctx context.Context, inp repository.CreateScheduledMessageInput) (repository.CreateScheduledMessageOutput, error) {
+						t.Fatal("should not be called")
+						return repository.CreateScheduledMessageOutput{}, nil
+					},
+				}
+
+				inp := tt.input
+				inp.SendAt = time.Now().Add(time.Hour)
+				inp.RoomID = roomID
+				inp.AuthorID = authorID
+				_, err := controller.NewScheduleMessageController(mockRepo).ScheduleMessage(t.Context(), inp)
+				require.ErrorIs(t, err, tt.expected)
+			})
+		}
+	})
+}
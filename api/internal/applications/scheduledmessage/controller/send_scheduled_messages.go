@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase/service"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+)
+
+type SendScheduledMessagesInput struct {
+	BatchSize int
+	Lease     time.Duration
+}
+
+// SendScheduledMessagesOutput の Claimed は今回取り出したメッセージの数
+type SendScheduledMessagesOutput struct {
+	Claimed int
+	Sent    int
+	Failed  int
+	Lost    int
+}
+
+type SendScheduledMessagesController struct {
+	repo   repository.ScheduledMessageRepository
+	poster service.MessagePoster
+}
+
+func NewSendScheduledMessagesController(repo repository.ScheduledMessageRepository, poster service.MessagePoster) *SendScheduledMessagesController {
+	return &SendScheduledMessagesController{repo, poster}
+}
+
+func (c *SendScheduledMessagesController) SendScheduledMessages(ctx context.Context, inp SendScheduledMessagesInput) (SendScheduledMessagesOutput, error) {
+	uc := usecase.NewSendScheduledMessagesUsecase(c.repo, c.poster)
+	res, err := tracing.Execute(ctx, "SendScheduledMessagesUsecase", uc.Execute, usecase.SendScheduledMessagesInput{
+		BatchSize: inp.BatchSize,
+		Lease:     inp.Lease,
+	})
+	return SendScheduledMessagesOutput{
+		Claimed: res.Claimed,
+		Sent:    res.Sent,
+		Failed:  res.Failed,
+		Lost:    res.Lost,
+	}, err
+}
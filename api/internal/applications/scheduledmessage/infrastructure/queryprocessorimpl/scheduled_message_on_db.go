@@ -0,0 +1,72 @@
+package queryprocessorimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase/queryprocessor"
+	"github.com/quietsato/toy-small-chat/api/internal/db"
+)
+
+type ScheduledMessageQueryProcessorOnDB struct {
+	queries *db.Queries
+}
+
+func NewScheduledMessageQueryProcessorOnDB(pool *pgxpool.Pool) *ScheduledMessageQueryProcessorOnDB {
+	return &ScheduledMessageQueryProcessorOnDB{
+		queries: db.New(pool),
+	}
+}
+
+// GetScheduledMessages implements queryprocessor.ScheduledMessageQueryProcessor.
+func (q *ScheduledMessageQueryProcessorOnDB) GetScheduledMessages(ctx context.Context, inp queryprocessor.GetScheduledMessagesInput) (queryprocessor.GetScheduledMessagesOutput, error) {
+	if _, err := q.queries.GetRoomOwner(ctx, inp.RoomID); errors.Is(err, pgx.ErrNoRows) {
+		return queryprocessor.GetScheduledMessagesOutput{}, queryprocessor.ErrRoomNotFound
+	} else if err != nil {
+		return queryprocessor.GetScheduledMessagesOutput{}, fmt.Errorf("failed to get room: %w", err)
+	}
+
+	rows, err := q.queries.GetScheduledMessages(ctx, db.GetScheduledMessagesParams{
+		RoomID:     inp.RoomID,
+		AuthorID:   inp.AuthorID,
+		LimitCount: int32(inp.Limit),
+	})
+	if err != nil {
+		return queryprocessor.GetScheduledMessagesOutput{}, fmt.Errorf("failed to get scheduled messages: %w", err)
+	}
+
+	messages := make([]queryprocessor.ScheduledMessageDTO, len(rows))
+	for i, row := range rows {
+		messageID := ""
+		if row.MessageID.Valid {
+			messageID = uuid.UUID(row.MessageID.Bytes).String()
+		}
+		messages[i] = queryprocessor.ScheduledMessageDTO{
+			ID:        row.ID.String(),
+			Content:   row.Content,
+			Format:    row.Format,
+			SendAt:    formatTimestamp(row.SendAt),
+			Status:    row.Status,
+			MessageID: messageID,
+			Error:     row.LastError.String,
+			CreatedAt: formatTimestamp(row.CreatedAt),
+			SentAt:    formatTimestamp(row.SentAt),
+		}
+	}
+	return queryprocessor.GetScheduledMessagesOutput{Messages: messages}, nil
+}
+
+func formatTimestamp(t pgtype.Timestamp) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.Format(time.RFC3339)
+}
+
+var _ queryprocessor.ScheduledMessageQueryProcessor = new(ScheduledMessageQueryProcessorOnDB)
@@ -0,0 +1,186 @@
+package repositoryimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/db"
+)
+
+func NewScheduledMessageRepositoryOnDB(pool *pgxpool.Pool) *ScheduledMessageRepositoryOnDB {
+	return &ScheduledMessageRepositoryOnDB{pool}
+}
+
+type ScheduledMessageRepositoryOnDB struct {
+	pool *pgxpool.Pool
+}
+
+// CreateScheduledMessage implements repository.ScheduledMessageRepository.
+func (r *ScheduledMessageRepositoryOnDB) CreateScheduledMessage(ctx context.Context, inp repository.CreateScheduledMessageInput) (repository.CreateScheduledMessageOutput, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return repository.CreateScheduledMessageOutput{}, err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rollback", slog.Any("err", err))
+		}
+	}()
+
+	queries := db.New(r.pool).WithTx(tx)
+
+	// ルームの行をロックしているため、件数の確認と追加の間に同じルームへの予約は割り込まない
+	archivedAt, err := queries.GetRoomArchivedAt(ctx, inp.RoomID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.CreateScheduledMessageOutput{}, repository.ErrRoomNotFound
+	}
+	if err != nil {
+		return repository.CreateScheduledMessageOutput{}, fmt.Errorf("failed to get room: %w", err)
+	}
+	if archivedAt.Valid {
+		return repository.CreateScheduledMessageOutput{}, repository.ErrRoomArchived
+	}
+
+	count, err := queries.CountPendingScheduledMessages(ctx, db.CountPendingScheduledMessagesParams{
+		RoomID:   inp.RoomID,
+		AuthorID: inp.AuthorID,
+	})
+	if err != nil {
+		return repository.CreateScheduledMessageOutput{}, fmt.Errorf("failed to count scheduled messages: %w", err)
+	}
+	if count >= int64(inp.MaxPending) {
+		return repository.CreateScheduledMessageOutput{}, repository.ErrTooManyScheduledMessages
+	}
+
+	id, err := queries.CreateScheduledMessage(ctx, db.CreateScheduledMessageParams{
+		RoomID:   inp.RoomID,
+		AuthorID: inp.AuthorID,
+		Content:  inp.Content,
+		Format:   inp.Format,
+		SendAt:   pgtype.Timestamp{Time: inp.SendAt, Valid: true},
+	})
+	if err != nil {
+		return repository.CreateScheduledMessageOutput{}, fmt.Errorf("failed to create scheduled message: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return repository.CreateScheduledMessageOutput{}, fmt.Errorf("failed to commit: %w", err)
+	}
+	return repository.CreateScheduledMessageOutput{ID: id}, nil
+}
+
+// CancelScheduledMessage implements repository.ScheduledMessageRepository.
+//
+// 行をロックしてから状態を確かめるため、取り出しと同時に取り消しても、投稿したメッセージが取り消し済みとして残ることはない
+func (r *ScheduledMessageRepositoryOnDB) CancelScheduledMessage(ctx context.Context, inp repository.CancelScheduledMessageInput) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rollback", slog.Any("err", err))
+		}
+	}()
+
+	queries := db.New(r.pool).WithTx(tx)
+
+	row, err := queries.GetScheduledMessageForUpdate(ctx, db.GetScheduledMessageForUpdateParams{
+		ID:       inp.ScheduledMessageID,
+		RoomID:   inp.RoomID,
+		AuthorID: inp.AuthorID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.ErrScheduledMessageNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get scheduled message: %w", err)
+	}
+	if row.Status != "pending" {
+		return repository.ErrScheduledMessageNotFound
+	}
+	if row.Leased {
+		return repository.ErrScheduledMessageSending
+	}
+
+	if _, err := queries.CancelScheduledMessage(ctx, db.CancelScheduledMessageParams{
+		ID:       inp.ScheduledMessageID,
+		RoomID:   inp.RoomID,
+		AuthorID: inp.AuthorID,
+	}); err != nil {
+		return fmt.Errorf("failed to cancel scheduled message: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
+
+// ClaimScheduledMessages implements repository.ScheduledMessageRepository.
+//
+// SKIP LOCKED で取り出すため、複数のインスタンスが同時に取り出しても同じメッセージは 1 つのワーカーにだけ渡る
+func (r *ScheduledMessageRepositoryOnDB) ClaimScheduledMessages(ctx context.Context, inp repository.ClaimScheduledMessagesInput) (repository.ClaimScheduledMessagesOutput, error) {
+	queries := db.New(r.pool)
+	rows, err := queries.ClaimScheduledMessages(ctx, db.ClaimScheduledMessagesParams{
+		Lease:      toInterval(inp.Lease),
+		LimitCount: int32(inp.Limit),
+	})
+	if err != nil {
+		return repository.ClaimScheduledMessagesOutput{}, fmt.Errorf("failed to claim scheduled messages: %w", err)
+	}
+
+	messages := make([]repository.ScheduledMessage, len(rows))
+	for i, row := range rows {
+		messages[i] = repository.ScheduledMessage{
+			ID:       row.ID,
+			RoomID:   row.RoomID,
+			AuthorID: row.AuthorID,
+			Content:  row.Content,
+			Format:   row.Format,
+			Attempts: int(row.Attempts),
+		}
+	}
+	return repository.ClaimScheduledMessagesOutput{Messages: messages}, nil
+}
+
+// FailScheduledMessage implements repository.ScheduledMessageRepository.
+//
+// 再送する場合は取り出した時に延ばした次の送信時刻をそのまま使う。未送信でなくなったメッセージと取り出し直されたメッセージは変更しない
+func (r *ScheduledMessageRepositoryOnDB) FailScheduledMessage(ctx context.Context, inp repository.FailScheduledMessageInput) error {
+	queries := db.New(r.pool)
+	lastError := pgtype.Text{String: inp.Error, Valid: true}
+
+	if inp.Retry {
+		if err := queries.RetryScheduledMessage(ctx, db.RetryScheduledMessageParams{
+			LastError: lastError,
+			ID:        inp.ScheduledMessageID,
+			Attempts:  int32(inp.Attempts),
+		}); err != nil {
+			return fmt.Errorf("failed to record retry: %w", err)
+		}
+		return nil
+	}
+
+	if err := queries.FailScheduledMessage(ctx, db.FailScheduledMessageParams{
+		LastError: lastError,
+		ID:        inp.ScheduledMessageID,
+		Attempts:  int32(inp.Attempts),
+	}); err != nil {
+		return fmt.Errorf("failed to fail scheduled message: %w", err)
+	}
+	return nil
+}
+
+func toInterval(d time.Duration) pgtype.Interval {
+	return pgtype.Interval{Microseconds: d.Microseconds(), Valid: true}
+}
+
+var _ repository.ScheduledMessageRepository = new(ScheduledMessageRepositoryOnDB)
@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase/repository"
+)
+
+type CancelScheduledMessageUsecase struct {
+	repo repository.ScheduledMessageRepository
+}
+
+type CancelScheduledMessageInput struct {
+	RoomID             string
+	ScheduledMessageID string
+	AuthorID           string
+}
+
+func NewCancelScheduledMessageUsecase(repo repository.ScheduledMessageRepository) *CancelScheduledMessageUsecase {
+	return &CancelScheduledMessageUsecase{repo}
+}
+
+// Execute は作成者の未送信のメッセージを取り消す。取り消したメッセージは履歴として残る
+func (u *CancelScheduledMessageUsecase) Execute(ctx context.Context, inp CancelScheduledMessageInput) error {
+	roomID, authorID, err := parseAuthorIDs(inp.RoomID, inp.AuthorID)
+	if err != nil {
+		return err
+	}
+	id, err := uuid.Parse(inp.ScheduledMessageID)
+	if err != nil {
+		return repository.ErrScheduledMessageNotFound
+	}
+
+	if err := u.repo.CancelScheduledMessage(ctx, repository.CancelScheduledMessageInput{
+		RoomID:             roomID,
+		ScheduledMessageID: id,
+		AuthorID:           authorID,
+	}); err != nil {
+		return fmt.Errorf("failed to cancel scheduled message: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package usecase_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase/service"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCancelScheduledMessageUsecase_Execute(t *testing.T) {
+	t.Parallel()
+
+	roomID := uuid.New()
+	authorID := uuid.New()
+
+	t.Run("作成者の予約を取り消す", func(t *testing.T) {
+		t.Parallel()
+
+		id := uuid.New()
+		mockRepo := &mockScheduledMessageRepository{
+			cancelScheduledMessageFunc: func(ctx context.Context, inp repository.CancelScheduledMessageInput) error {
+				require.Equal(t, repository.CancelScheduledMessageInput{RoomID: roomID, ScheduledMessageID: id, AuthorID: authorID}, inp)
+				return nil
+			},
+		}
+
+		err := usecase.NewCancelScheduledMessageUsecase(mockRepo).Execute(t.Context(), usecase.CancelScheduledMessageInput{
+			RoomID:             roomID.String(),
+			ScheduledMessageID: id.String(),
+			AuthorID:           authorID.String(),
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("不正な ID は見つからないものとして扱う", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockScheduledMessageRepository{
+			cancelScheduledMessageFunc: func(ctx context.Context, inp repository.CancelScheduledMessageInput) error {
+				t.Fatal("should not be called")
+				return nil
+			},
+		}
+
+		err := usecase.NewCancelScheduledMessageUsecase(mockRepo).Execute(t.Context(), usecase.CancelScheduledMessageInput{
+			RoomID:             roomID.String(),
+			ScheduledMessageID: "not-a-uuid",
+			AuthorID:           authorID.String(),
+		})
+
+		require.ErrorIs(t, err, repository.ErrScheduledMessageNotFound)
+	})
+
+	t.Run("ワーカーが取り出した後の取り消しは拒否し、投稿したメッセージを送信済みにする", func(t *testing.T) {
+		t.Parallel()
+
+		scheduled := repository.ScheduledMessage{ID: uuid.New(), RoomID: roomID, AuthorID: authorID, Content: "hello", Format: "plain", Attempts: 1}
+		messageID := uuid.New()
+
+		// 取り出しから結果の記録までをリースとして扱い、その間の取り消しを拒否する
+		var mu sync.Mutex
+		leased := map[uuid.UUID]bool{}
+		status := map[uuid.UUID]string{scheduled.ID: "pending"}
+		mockRepo := &mockScheduledMessageRepository{
+			claimScheduledMessagesFunc: func(ctx context.Context, inp repository.ClaimScheduledMessagesInput) (repository.ClaimScheduledMessagesOutput, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				leased[scheduled.ID] = true
+				return repository.ClaimScheduledMessagesOutput{Messages: []repository.ScheduledMessage{scheduled}}, nil
+			},
+			cancelScheduledMessageFunc: func(ctx context.Context, inp repository.CancelScheduledMessageInput) error {
+				mu.Lock()
+				defer mu.Unlock()
+				if status[inp.ScheduledMessageID] != "pending" {
+					return repository.ErrScheduledMessageNotFound
+				}
+				if leased[inp.ScheduledMessageID] {
+					return repository.ErrScheduledMessageSending
+				}
+				status[inp.ScheduledMessageID] = "canceled"
+				return nil
+			},
+		}
+
+		cancel := usecase.NewCancelScheduledMessageUsecase(mockRepo)
+		var cancelErr error
+		poster := &mockMessagePoster{
+			postMessageFunc: func(ctx context.Context, inp service.PostMessageInput) (service.PostMessageOutput, error) {
+				// 投稿している最中に作成者が取り消す
+				cancelErr = cancel.Execute(ctx, usecase.CancelScheduledMessageInput{
+					RoomID:             roomID.String(),
+					ScheduledMessageID: scheduled.ID.String(),
+					AuthorID:           authorID.String(),
+				})
+
+				// メッセージの作成と同じトランザクションで送信済みにする
+				mu.Lock()
+				defer mu.Unlock()
+				if status[inp.ScheduledMessageID] != "pending" {
+					return service.PostMessageOutput{}, service.ErrClaimLost
+				}
+				status[inp.ScheduledMessageID] = "sent"
+				delete(leased, inp.ScheduledMessageID)
+				return service.PostMessageOutput{MessageID: messageID}, nil
+			},
+		}
+
+		out, err := usecase.NewSendScheduledMessagesUsecase(mockRepo, poster).Execute(t.Context(), usecase.SendScheduledMessagesInput{
+			BatchSize: 10,
+			Lease:     time.Minute,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, usecase.SendScheduledMessagesOutput{Claimed: 1, Sent: 1}, out)
+		require.ErrorIs(t, cancelErr, repository.ErrScheduledMessageSending)
+		require.Equal(t, "sent", status[scheduled.ID])
+
+		// 送信済みになった後の取り消しは見つからないものとして扱う
+		err = cancel.Execute(t.Context(), usecase.CancelScheduledMessageInput{
+			RoomID:             roomID.String(),
+			ScheduledMessageID: scheduled.ID.String(),
+			AuthorID:           authorID.String(),
+		})
+		require.ErrorIs(t, err, repository.ErrScheduledMessageNotFound)
+	})
+}
@@ -0,0 +1,39 @@
+package queryprocessor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// GetScheduledMessagesInput の AuthorID のメッセージだけを返す
+type GetScheduledMessagesInput struct {
+	RoomID   uuid.UUID
+	AuthorID uuid.UUID
+	Limit    int
+}
+type GetScheduledMessagesOutput struct {
+	Messages []ScheduledMessageDTO
+}
+
+// ScheduledMessageDTO の Status は "pending", "sent", "canceled", "failed" のいずれか
+//
+// MessageID と SentAt は送信済みの場合のみ、Error は送信に失敗した場合のみ設定する
+type ScheduledMessageDTO struct {
+	ID        string
+	Content   string
+	Format    string
+	SendAt    string
+	Status    string
+	MessageID string
+	Error     string
+	CreatedAt string
+	SentAt    string
+}
+
+var ErrRoomNotFound = errors.New("room not found")
+
+type ScheduledMessageQueryProcessor interface {
+	GetScheduledMessages(ctx context.Context, inp GetScheduledMessagesInput) (GetScheduledMessagesOutput, error)
+}
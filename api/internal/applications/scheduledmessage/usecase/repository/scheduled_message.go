@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateScheduledMessageInput の MaxPending は作成者がルームごとに予約できる未送信のメッセージ数の上限
+type CreateScheduledMessageInput struct {
+	RoomID     uuid.UUID
+	AuthorID   uuid.UUID
+	Content    string
+	Format     string
+	SendAt     time.Time
+	MaxPending int
+}
+type CreateScheduledMessageOutput struct {
+	ID uuid.UUID
+}
+
+// CancelScheduledMessageInput は作成者の未送信のメッセージだけを取り消す
+//
+// ワーカーが取り出したメッセージは Lease が切れるまで取り消せない
+type CancelScheduledMessageInput struct {
+	RoomID             uuid.UUID
+	ScheduledMessageID uuid.UUID
+	AuthorID           uuid.UUID
+}
+
+// ClaimScheduledMessagesInput の Lease は取り出したメッセージを他のワーカーに渡さない期間
+//
+// Lease のうちに結果を記録しなかったメッセージは、ワーカーが停止したものとみなして再び取り出される
+type ClaimScheduledMessagesInput struct {
+	Limit int
+	Lease time.Duration
+}
+type ClaimScheduledMessagesOutput struct {
+	Messages []ScheduledMessage
+}
+
+// ScheduledMessage の Attempts は今回の送信を含めた送信回数
+type ScheduledMessage struct {
+	ID       uuid.UUID
+	RoomID   uuid.UUID
+	AuthorID uuid.UUID
+	Content  string
+	Format   string
+	Attempts int
+}
+
+// FailScheduledMessageInput の Retry が true の場合は Lease が切れた後に再び送信する
+//
+// Attempts は取り出した時の送信回数で、他のワーカーが取り出し直したメッセージは変更しない
+type FailScheduledMessageInput struct {
+	ScheduledMessageID uuid.UUID
+	Attempts           int
+	Error              string
+	Retry              bool
+}
+
+var (
+	ErrRoomNotFound = errors.New("room not found")
+	// ErrRoomArchived はアーカイブ済みのルームにメッセージを予約しようとした場合に返す
+	ErrRoomArchived             = errors.New("room archived")
+	ErrTooManyScheduledMessages = errors.New("too many scheduled messages")
+	// ErrScheduledMessageNotFound は送信済みや取り消し済みのメッセージを取り消そうとした場合にも返す
+	ErrScheduledMessageNotFound = errors.New("scheduled message not found")
+	// ErrScheduledMessageSending はワーカーが取り出して投稿している最中のメッセージを取り消そうとした場合に返す
+	ErrScheduledMessageSending = errors.New("scheduled message is being sent")
+)
+
+type ScheduledMessageRepository interface {
+	CreateScheduledMessage(ctx context.Context, inp CreateScheduledMessageInput) (CreateScheduledMessageOutput, error)
+	CancelScheduledMessage(ctx context.Context, inp CancelScheduledMessageInput) error
+	ClaimScheduledMessages(ctx context.Context, inp ClaimScheduledMessagesInput) (ClaimScheduledMessagesOutput, error)
+	FailScheduledMessage(ctx context.Context, inp FailScheduledMessageInput) error
+}
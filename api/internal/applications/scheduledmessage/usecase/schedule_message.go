@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+)
+
+type ScheduleMessageUsecase struct {
+	repo repository.ScheduledMessageRepository
+}
+
+type ScheduleMessageInput struct {
+	RoomID   string
+	AuthorID string
+	Content  domain.MessageContent
+	Format   domain.MessageFormat
+	SendAt   time.Time
+}
+
+type ScheduleMessageOutput struct {
+	ID     string
+	SendAt time.Time
+}
+
+const (
+	// MaxPendingScheduledMessages は作成者がルームごとに予約できる未送信のメッセージ数の上限
+	MaxPendingScheduledMessages = 100
+	// MaxScheduleAhead は予約できる送信時刻の上限
+	MaxScheduleAhead = 365 * 24 * time.Hour
+)
+
+// ErrInvalidSendAt は送信時刻が過去、または MaxScheduleAhead より先の場合に返す
+var ErrInvalidSendAt = errors.New("invalid send at")
+
+func NewScheduleMessageUsecase(repo repository.ScheduledMessageRepository) *ScheduleMessageUsecase {
+	return &ScheduleMessageUsecase{repo}
+}
+
+// Execute は送信時刻を秒単位に切り捨てて予約する
+func (u *ScheduleMessageUsecase) Execute(ctx context.Context, inp ScheduleMessageInput) (ScheduleMessageOutput, error) {
+	now := time.Now()
+	sendAt := inp.SendAt.UTC().Truncate(time.Second)
+	if !sendAt.After(now) || sendAt.After(now.Add(MaxScheduleAhead)) {
+		return ScheduleMessageOutput{}, ErrInvalidSendAt
+	}
+
+	roomID, authorID, err := parseAuthorIDs(inp.RoomID, inp.AuthorID)
+	if err != nil {
+		return ScheduleMessageOutput{}, err
+	}
+
+	res, err := u.repo.CreateScheduledMessage(ctx, repository.CreateScheduledMessageInput{
+		RoomID:     roomID,
+		AuthorID:   authorID,
+		Content:    inp.Content.String(),
+		Format:     inp.Format.String(),
+		SendAt:     sendAt,
+		MaxPending: MaxPendingScheduledMessages,
+	})
+	if err != nil {
+		return ScheduleMessageOutput{}, fmt.Errorf("failed to create scheduled message: %w", err)
+	}
+
+	return ScheduleMessageOutput{ID: res.ID.String(), SendAt: sendAt}, nil
+}
+
+// parseAuthorIDs は不正なルーム ID を存在しないルームとして扱う
+func parseAuthorIDs(roomID, authorID string) (uuid.UUID, uuid.UUID, error) {
+	room, err := uuid.Parse(roomID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, repository.ErrRoomNotFound
+	}
+	author, err := uuid.Parse(authorID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("failed to parse author id: %w", err)
+	}
+	return room, author, nil
+}
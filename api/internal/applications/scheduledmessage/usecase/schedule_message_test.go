@@ -0,0 +1,133 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock implementations
+type mockScheduledMessageRepository struct {
+	createScheduledMessageFunc func(ctx context.Context, inp repository.CreateScheduledMessageInput) (repository.CreateScheduledMessageOutput, error)
+	cancelScheduledMessageFunc func(ctx context.Context, inp repository.CancelScheduledMessageInput) error
+	claimScheduledMessagesFunc func(ctx context.Context, inp repository.ClaimScheduledMessagesInput) (repository.ClaimScheduledMessagesOutput, error)
+	failScheduledMessageFunc   func(ctx context.Context, inp repository.FailScheduledMessageInput) error
+}
+
+func (m *mockScheduledMessageRepository) CreateScheduledMessage(ctx context.Context, inp repository.CreateScheduledMessageInput) (repository.CreateScheduledMessageOutput, error) {
+	if m.createScheduledMessageFunc != nil {
+		return m.createScheduledMessageFunc(ctx, inp)
+	}
+	return repository.CreateScheduledMessageOutput{}, nil
+}
+
+func (m *mockScheduledMessageRepository) CancelScheduledMessage(ctx context.Context, inp repository.CancelScheduledMessageInput) error {
+	if m.cancelScheduledMessageFunc != nil {
+		return m.cancelScheduledMessageFunc(ctx, inp)
+	}
+	return nil
+}
+
+func (m *mockScheduledMessageRepository) ClaimScheduledMessages(ctx context.Context, inp repository.ClaimScheduledMessagesInput) (repository.ClaimScheduledMessagesOutput, error) {
+	if m.claimScheduledMessagesFunc != nil {
+		return m.claimScheduledMessagesFunc(ctx, inp)
+	}
+	return repository.ClaimScheduledMessagesOutput{}, nil
+}
+
+func (m *mockScheduledMessageRepository) FailScheduledMessage(ctx context.Context, inp repository.FailScheduledMessageInput) error {
+	if m.failScheduledMessageFunc != nil {
+		return m.failScheduledMessageFunc(ctx, inp)
+	}
+	return nil
+}
+
+func TestScheduleMessageUsecase_Execute(t *testing.T) {
+	t.Parallel()
+
+	roomID := uuid.New()
+	authorID := uuid.New()
+	content, _ := domain.NewMessageContent("stand-up time")
+
+	t.Run("送信時刻を UTC の秒単位にして予約する", func(t *testing.T) {
+		t.Parallel()
+
+		sendAt := time.Now().Add(time.Hour).In(time.FixedZone("JST", 9*60*60))
+		id := uuid.New()
+		mockRepo := &mockScheduledMessageRepository{
+			createScheduledMessageFunc: func(ctx context.Context, inp repository.CreateScheduledMessageInput) (repository.CreateScheduledMessageOutput, error) {
+				require.Equal(t, roomID, inp.RoomID)
+				require.Equal(t, authorID, inp.AuthorID)
+				require.Equal(t, "stand-up time", inp.Content)
+				require.Equal(t, "plain", inp.Format)
+				require.Equal(t, time.UTC, inp.SendAt.Location())
+				require.True(t, inp.SendAt.Equal(sendAt.Truncate(time.Second)))
+				require.Equal(t, usecase.MaxPendingScheduledMessages, inp.MaxPending)
+				return repository.CreateScheduledMessageOutput{ID: id}, nil
+			},
+		}
+
+		out, err := usecase.NewScheduleMessageUsecase(mockRepo).Execute(t.Context(), usecase.ScheduleMessageInput{
+			RoomID:   roomID.String(),
+			AuthorID: authorID.String(),
+			Content:  content,
+			Format:   domain.MessageFormatPlain,
+			SendAt:   sendAt,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, id.String(), out.ID)
+	})
+
+	t.Run("不正な送信時刻でエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name   string
+			sendAt time.Time
+		}{
+			{"past", time.Now().Add(-time.Minute)},
+			{"zero", time.Time{}},
+			{"too far", time.Now().Add(usecase.MaxScheduleAhead + time.Hour)},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				mockRepo := &mockScheduledMessageRepository{
+					createScheduledMessageFunc: func(ctx context.Context, inp repository.CreateScheduledMessageInput) (repository.CreateScheduledMessageOutput, error) {
+						t.Fatal("should not be called")
+						return repository.CreateScheduledMessageOutput{}, nil
+					},
+				}
+
+				_, err := usecase.NewScheduleMessageUsecase(mockRepo).Execute(t.Context(), usecase.ScheduleMessageInput{
+					RoomID:   roomID.String(),
+					AuthorID: authorID.String(),
+					Content:  content,
+					SendAt:   tt.sendAt,
+				})
+				require.ErrorIs(t, err, usecase.ErrInvalidSendAt)
+			})
+		}
+	})
+
+	t.Run("不正なルーム ID は存在しないルームとして扱う", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := usecase.NewScheduleMessageUsecase(&mockScheduledMessageRepository{}).Execute(t.Context(), usecase.ScheduleMessageInput{
+			RoomID:   "not-a-uuid",
+			AuthorID: authorID.String(),
+			Content:  content,
+			SendAt:   time.Now().Add(time.Hour),
+		})
+		require.ErrorIs(t, err, repository.ErrRoomNotFound)
+	})
+}
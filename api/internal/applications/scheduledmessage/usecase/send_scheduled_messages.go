@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase/service"
+)
+
+type SendScheduledMessagesUsecase struct {
+	repo   repository.ScheduledMessageRepository
+	poster service.MessagePoster
+}
+
+// SendScheduledMessagesInput の Lease は 1 件の投稿にかかる時間より長くする
+type SendScheduledMessagesInput struct {
+	BatchSize int
+	Lease     time.Duration
+}
+
+// SendScheduledMessagesOutput の Failed には再送待ちになったメッセージも含む
+//
+// Lost は投稿を終える前に Lease が切れて他のワーカーが取り出し直したため、投稿しなかったメッセージ
+type SendScheduledMessagesOutput struct {
+	Claimed int
+	Sent    int
+	Failed  int
+	Lost    int
+}
+
+const (
+	// maxScheduledMessageAttempts は一時的なエラーで投稿できなかった場合に試みる回数の上限
+	maxScheduledMessageAttempts = 5
+	// maxScheduledMessageErrorLength は履歴に残すエラーメッセージの最大バイト数
+	maxScheduledMessageErrorLength = 1024
+)
+
+func NewSendScheduledMessagesUsecase(repo repository.ScheduledMessageRepository, poster service.MessagePoster) *SendScheduledMessagesUsecase {
+	return &SendScheduledMessagesUsecase{repo, poster}
+}
+
+// Execute は送信時刻を過ぎたメッセージを最大 BatchSize 件取り出して投稿し、結果を記録する
+//
+// 同じ時刻に予約したメッセージの順序が入れ替わらないよう、取り出したメッセージは順に投稿する
+func (u *SendScheduledMessagesUsecase) Execute(ctx context.Context, inp SendScheduledMessagesInput) (SendScheduledMessagesOutput, error) {
+	res, err := u.repo.ClaimScheduledMessages(ctx, repository.ClaimScheduledMessagesInput{
+		Limit: inp.BatchSize,
+		Lease: inp.Lease,
+	})
+	if err != nil {
+		return SendScheduledMessagesOutput{}, fmt.Errorf("failed to claim scheduled messages: %w", err)
+	}
+
+	out := SendScheduledMessagesOutput{Claimed: len(res.Messages)}
+	var errs []error
+	for _, m := range res.Messages {
+		result, err := u.send(ctx, m)
+		switch result {
+		case sendResultSent:
+			out.Sent++
+		case sendResultFailed:
+			out.Failed++
+		case sendResultLost:
+			out.Lost++
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return out, errors.Join(errs...)
+}
+
+type sendResult int
+
+const (
+	sendResultSent sendResult = iota
+	sendResultFailed
+	sendResultLost
+)
+
+// send は 1 件のメッセージを投稿して結果を記録する。返す error は結果の記録に失敗した場合のみ
+//
+// 投稿と送信済みへの変更は MessagePoster が 1 つのトランザクションで行うため、ここでは失敗だけを記録する
+func (u *SendScheduledMessagesUsecase) send(ctx context.Context, m repository.ScheduledMessage) (sendResult, error) {
+	_, postErr := u.poster.PostMessage(ctx, service.PostMessageInput{
+		ScheduledMessageID: m.ID,
+		Attempts:           m.Attempts,
+		RoomID:             m.RoomID,
+		AuthorID:           m.AuthorID,
+		Content:            m.Content,
+		Format:             m.Format,
+	})
+	if postErr == nil {
+		return sendResultSent, nil
+	}
+	if errors.Is(postErr, service.ErrClaimLost) {
+		// 取り出し直したワーカーが投稿と結果の記録を行う
+		return sendResultLost, nil
+	}
+
+	msg := postErr.Error()
+	if len(msg) > maxScheduledMessageErrorLength {
+		msg = strings.ToValidUTF8(msg[:maxScheduledMessageErrorLength], "")
+	}
+	if err := u.repo.FailScheduledMessage(ctx, repository.FailScheduledMessageInput{
+		ScheduledMessageID: m.ID,
+		Attempts:           m.Attempts,
+		Error:              msg,
+		Retry:              !errors.Is(postErr, service.ErrMessageRejected) && m.Attempts < maxScheduledMessageAttempts,
+	}); err != nil {
+		return sendResultFailed, fmt.Errorf("failed to record scheduled message failure %s: %w", m.ID, err)
+	}
+	return sendResultFailed, nil
+}
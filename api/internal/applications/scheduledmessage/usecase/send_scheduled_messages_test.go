@@ -0,0 +1,202 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase/service"
+	"github.com/stretchr/testify/require"
+)
+
+type mockMessagePoster struct {
+	postMessageFunc func(ctx context.Context, inp service.PostMessageInput) (service.PostMessageOutput, error)
+}
+
+func (m *mockMessagePoster) PostMessage(ctx context.Context, inp service.PostMessageInput) (service.PostMessageOutput, error) {
+	if m.postMessageFunc != nil {
+		return m.postMessageFunc(ctx, inp)
+	}
+	return service.PostMessageOutput{}, nil
+}
+
+func TestSendScheduledMessagesUsecase_Execute(t *testing.T) {
+	t.Parallel()
+
+	claim := func(messages ...repository.ScheduledMessage) func(ctx context.Context, inp repository.ClaimScheduledMessagesInput) (repository.ClaimScheduledMessagesOutput, error) {
+		return func(ctx context.Context, inp repository.ClaimScheduledMessagesInput) (repository.ClaimScheduledMessagesOutput, error) {
+			return repository.ClaimScheduledMessagesOutput{Messages: messages}, nil
+		}
+	}
+
+	t.Run("取り出したメッセージを順に投稿して送信済みにする", func(t *testing.T) {
+		t.Parallel()
+
+		first := repository.ScheduledMessage{ID: uuid.New(), RoomID: uuid.New(), AuthorID: uuid.New(), Content: "first", Format: "plain", Attempts: 1}
+		second := repository.ScheduledMessage{ID: uuid.New(), RoomID: first.RoomID, AuthorID: first.AuthorID, Content: "second", Format: "markdown", Attempts: 1}
+
+		var posted []string
+		claims := map[uuid.UUID]int{}
+		poster := &mockMessagePoster{
+			postMessageFunc: func(ctx context.Context, inp service.PostMessageInput) (service.PostMessageOutput, error) {
+				require.Equal(t, first.RoomID, inp.RoomID)
+				require.Equal(t, first.AuthorID, inp.AuthorID)
+				posted = append(posted, inp.Content)
+				claims[inp.ScheduledMessageID] = inp.Attempts
+				return service.PostMessageOutput{MessageID: uuid.New()}, nil
+			},
+		}
+
+		mockRepo := &mockScheduledMessageRepository{
+			claimScheduledMessagesFunc: func(ctx context.Context, inp repository.ClaimScheduledMessagesInput) (repository.ClaimScheduledMessagesOutput, error) {
+				require.Equal(t, 10, inp.Limit)
+				require.Equal(t, time.Minute, inp.Lease)
+				return repository.ClaimScheduledMessagesOutput{Messages: []repository.ScheduledMessage{first, second}}, nil
+			},
+			failScheduledMessageFunc: func(ctx context.Context, inp repository.FailScheduledMessageInput) error {
+				t.Fatal("should not be called")
+				return nil
+			},
+		}
+
+		out, err := usecase.NewSendScheduledMessagesUsecase(mockRepo, poster).Execute(t.Context(), usecase.SendScheduledMessagesInput{
+			BatchSize: 10,
+			Lease:     time.Minute,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, usecase.SendScheduledMessagesOutput{Claimed: 2, Sent: 2}, out)
+		require.Equal(t, []string{"first", "second"}, posted)
+		// 送信済みへの変更は投稿と同じトランザクションで行うため、取り出した時の送信回数を渡す
+		require.Equal(t, map[uuid.UUID]int{first.ID: 1, second.ID: 1}, claims)
+	})
+
+	t.Run("投稿に失敗した場合は再送するかどうかを記録する", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name      string
+			err       error
+			attempts  int
+			wantRetry bool
+		}{
+			{"transient", errors.New("db error"), 1, true},
+			{"transient at max attempts", errors.New("db error"), 5, false},
+			{"rejected", fmt.Errorf("room archived: %w", service.ErrMessageRejected), 1, false},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				m := repository.ScheduledMessage{ID: uuid.New(), Attempts: tt.attempts}
+				var failed *repository.FailScheduledMessageInput
+				mockRepo := &mockScheduledMessageRepository{
+					claimScheduledMessagesFunc: claim(m),
+					failScheduledMessageFunc: func(ctx context.Context, inp repository.FailScheduledMessageInput) error {
+						failed = &inp
+						return nil
+					},
+				}
+				poster := &mockMessagePoster{
+					postMessageFunc: func(ctx context.Context, inp service.PostMessageInput) (service.PostMessageOutput, error) {
+						return service.PostMessageOutput{}, tt.err
+					},
+				}
+
+				out, err := usecase.NewSendScheduledMessagesUsecase(mockRepo, poster).Execute(t.Context(), usecase.SendScheduledMessagesInput{BatchSize: 10})
+
+				require.NoError(t, err)
+				require.Equal(t, usecase.SendScheduledMessagesOutput{Claimed: 1, Failed: 1}, out)
+				require.NotNil(t, failed)
+				require.Equal(t, m.ID, failed.ScheduledMessageID)
+				require.Equal(t, tt.attempts, failed.Attempts)
+				require.Equal(t, tt.err.Error(), failed.Error)
+				require.Equal(t, tt.wantRetry, failed.Retry)
+			})
+		}
+	})
+
+	t.Run("Lease が切れて取り出し直されたメッセージは、元のワーカーが再び投稿しない", func(t *testing.T) {
+		t.Parallel()
+
+		// DB と同じく、取り出すたびに送信回数を増やし、投稿は取り出した時の送信回数が変わっていない場合だけ送信済みにする
+		var mu sync.Mutex
+		m := repository.ScheduledMessage{ID: uuid.New(), RoomID: uuid.New(), AuthorID: uuid.New(), Content: "hello", Format: "plain"}
+		status := "pending"
+		var posted int
+		mockRepo := &mockScheduledMessageRepository{
+			claimScheduledMessagesFunc: func(ctx context.Context, inp repository.ClaimScheduledMessagesInput) (repository.ClaimScheduledMessagesOutput, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				if status != "pending" {
+					return repository.ClaimScheduledMessagesOutput{}, nil
+				}
+				m.Attempts++
+				return repository.ClaimScheduledMessagesOutput{Messages: []repository.ScheduledMessage{m}}, nil
+			},
+			failScheduledMessageFunc: func(ctx context.Context, inp repository.FailScheduledMessageInput) error {
+				t.Fatal("should not be called")
+				return nil
+			},
+		}
+		commit := func(inp service.PostMessageInput) (service.PostMessageOutput, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if status != "pending" || inp.Attempts != m.Attempts {
+				return service.PostMessageOutput{}, service.ErrClaimLost
+			}
+			status = "sent"
+			posted++
+			return service.PostMessageOutput{MessageID: uuid.New()}, nil
+		}
+
+		var second usecase.SendScheduledMessagesOutput
+		var secondErr error
+		stalled := &mockMessagePoster{
+			postMessageFunc: func(ctx context.Context, inp service.PostMessageInput) (service.PostMessageOutput, error) {
+				// 投稿に時間がかかっている間に Lease が切れ、別のインスタンスが取り出し直して投稿する
+				second, secondErr = usecase.NewSendScheduledMessagesUsecase(mockRepo, &mockMessagePoster{
+					postMessageFunc: func(ctx context.Context, inp service.PostMessageInput) (service.PostMessageOutput, error) {
+						return commit(inp)
+					},
+				}).Execute(ctx, usecase.SendScheduledMessagesInput{BatchSize: 10})
+				return commit(inp)
+			},
+		}
+
+		first, err := usecase.NewSendScheduledMessagesUsecase(mockRepo, stalled).Execute(t.Context(), usecase.SendScheduledMessagesInput{BatchSize: 10})
+
+		require.NoError(t, err)
+		require.NoError(t, secondErr)
+		require.Equal(t, usecase.SendScheduledMessagesOutput{Claimed: 1, Lost: 1}, first)
+		require.Equal(t, usecase.SendScheduledMessagesOutput{Claimed: 1, Sent: 1}, second)
+		require.Equal(t, 1, posted)
+		require.Equal(t, "sent", status)
+
+		// 送信済みになったメッセージは、さらに後から取り出そうとしても投稿しない
+		third, err := usecase.NewSendScheduledMessagesUsecase(mockRepo, stalled).Execute(t.Context(), usecase.SendScheduledMessagesInput{BatchSize: 10})
+		require.NoError(t, err)
+		require.Equal(t, usecase.SendScheduledMessagesOutput{}, third)
+		require.Equal(t, 1, posted)
+	})
+
+	t.Run("取り出しに失敗した場合はエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockScheduledMessageRepository{
+			claimScheduledMessagesFunc: func(ctx context.Context, inp repository.ClaimScheduledMessagesInput) (repository.ClaimScheduledMessagesOutput, error) {
+				return repository.ClaimScheduledMessagesOutput{}, errors.New("db error")
+			},
+		}
+
+		_, err := usecase.NewSendScheduledMessagesUsecase(mockRepo, &mockMessagePoster{}).Execute(t.Context(), usecase.SendScheduledMessagesInput{BatchSize: 10})
+		require.Error(t, err)
+	})
+}
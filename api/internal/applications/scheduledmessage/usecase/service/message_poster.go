@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// PostMessageInput の ScheduledMessageID と Attempts は取り出した予約メッセージとその時の送信回数
+type PostMessageInput struct {
+	ScheduledMessageID uuid.UUID
+	Attempts           int
+	RoomID             uuid.UUID
+	AuthorID           uuid.UUID
+	Content            string
+	Format             string
+}
+
+type PostMessageOutput struct {
+	MessageID uuid.UUID
+}
+
+var (
+	// ErrMessageRejected はルームが削除・アーカイブされているなど、再送しても投稿できない場合に返す
+	ErrMessageRejected = errors.New("message rejected")
+	// ErrClaimLost は Lease が切れて他のワーカーが取り出し直したか、すでに送信済みのため投稿しなかった場合に返す
+	ErrClaimLost = errors.New("scheduled message claim lost")
+)
+
+// MessagePoster は予約したメッセージを通常の投稿と同じ経路で投稿する
+//
+// メッセージの作成と予約メッセージの送信済みへの変更は 1 つのトランザクションで行い、
+// 取り出した時から送信回数が変わっている場合はどちらも行わずに ErrClaimLost を返す
+type MessagePoster interface {
+	PostMessage(ctx context.Context, inp PostMessageInput) (PostMessageOutput, error)
+}
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+)
+
+// CreateWebhookInput の Secret が空の場合はランダムな共有鍵を生成する
+type CreateWebhookInput struct {
+	URL       string   `json:"url"`
+	Secret    string   `json:"secret"`
+	Events    []string `json:"events"`
+	RoomID    string   `json:"-"`
+	AccountID string   `json:"-"`
+}
+
+// CreateWebhookOutput の Secret は登録時にだけ返す
+type CreateWebhookOutput struct {
+	ID     string   `json:"id"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret"`
+}
+
+type CreateWebhookController struct {
+	repo   repository.WebhookRepository
+	policy domain.WebhookNetworkPolicy
+}
+
+// NewCreateWebhookController の policy はホストが IP アドレスか localhost の送信先を登録時に拒否するために使う
+func NewCreateWebhookController(repo repository.WebhookRepository, policy domain.WebhookNetworkPolicy) *CreateWebhookController {
+	return &CreateWebhookController{repo, policy}
+}
+
+func (c *CreateWebhookController) CreateWebhook(ctx context.Context, inp CreateWebhookInput) (CreateWebhookOutput, error) {
+	url, err := domain.NewWebhookURL(inp.URL)
+	if err != nil {
+		return CreateWebhookOutput{}, fmt.Errorf("bad url: %w", err)
+	}
+	if !c.policy.AllowsURL(url) {
+		return CreateWebhookOutput{}, fmt.Errorf("bad url: %w", domain.ErrWebhookURLNotAllowed)
+	}
+
+	secret := domain.GenerateWebhookSecret()
+	if inp.Secret != "" {
+		secret, err = domain.NewWebhookSecret(inp.Secret)
+		if err != nil {
+			return CreateWebhookOutput{}, fmt.Errorf("bad secret: %w", err)
+		}
+	}
+
+	eventTypes := make([]domain.WebhookEventType, 0, len(inp.Events))
+	for _, s := range inp.Events {
+		t, err := domain.NewWebhookEventType(s)
+		if err != nil {
+			return CreateWebhookOutput{}, fmt.Errorf("bad event type: %w", err)
+		}
+		eventTypes = append(eventTypes, t)
+	}
+
+	uc := usecase.NewCreateWebhookUsecase(c.repo)
+	res, err := tracing.Execute(ctx, "CreateWebhookUsecase", uc.Execute, usecase.CreateWebhookInput{
+		RoomID:     inp.RoomID,
+		AccountID:  inp.AccountID,
+		URL:        url,
+		Secret:     secret,
+		EventTypes: eventTypes,
+	})
+	if err != nil {
+		return CreateWebhookOutput{}, err
+	}
+
+	events := make([]string, len(res.EventTypes))
+	for i, t := range res.EventTypes {
+		events[i] = t.String()
+	}
+	return CreateWebhookOutput{
+		ID:     res.ID,
+		URL:    url.String(),
+		Events: events,
+		Secret: secret.String(),
+	}, nil
+}
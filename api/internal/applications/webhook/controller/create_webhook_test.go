@@ -0,0 +1,136 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/controller"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock implementations
+type mockWebhookRepository struct {
+	createWebhookFunc func(ctx context.Context, inp repository.CreateWebhookInput) (repository.CreateWebhookOutput, error)
+}
+
+func (m *mockWebhookRepository) CreateWebhook(ctx context.Context, inp repository.CreateWebhookInput) (repository.CreateWebhookOutput, error) {
+	if m.createWebhookFunc != nil {
+		return m.createWebhookFunc(ctx, inp)
+	}
+	return repository.CreateWebhookOutput{}, nil
+}
+
+func (m *mockWebhookRepository) DeleteWebhook(ctx context.Context, inp repository.DeleteWebhookInput) error {
+	return nil
+}
+
+func (m *mockWebhookRepository) EnqueueDeliveries(ctx context.Context, inp repository.EnqueueDeliveriesInput) (repository.EnqueueDeliveriesOutput, error) {
+	return repository.EnqueueDeliveriesOutput{}, nil
+}
+
+func (m *mockWebhookRepository) ClaimDeliveries(ctx context.Context, inp repository.ClaimDeliveriesInput) (repository.ClaimDeliveriesOutput, error) {
+	return repository.ClaimDeliveriesOutput{}, nil
+}
+
+func (m *mockWebhookRepository) CompleteDelivery(ctx context.Context, inp repository.CompleteDeliveryInput) error {
+	return nil
+}
+
+func (m *mockWebhookRepository) FailDelivery(ctx context.Context, inp repository.FailDeliveryInput) error {
+	return nil
+}
+
+func TestCreateWebhookController_CreateWebhook(t *testing.T) {
+	t.Parallel()
+
+	roomID := uuid.NewString()
+	accountID := uuid.NewString()
+
+	t.Run("共有鍵を省略した場合は生成した鍵を返す", func(t *testing.T) {
+		t.Parallel()
+
+		webhookID := uuid.New()
+		var saved string
+		mockRepo := &mockWebhookRepository{
+			createWebhookFunc: func(ctx context.Context, inp repository.CreateWebhookInput) (repository.CreateWebhookOutput, error) {
+				saved = inp.Secret
+				return repository.CreateWebhookOutput{ID: webhookID}, nil
+			},
+		}
+
+		c := controller.NewCreateWebhookController(mockRepo, domain.NewWebhookNetworkPolicy(false))
+		out, err := c.CreateWebhook(t.Context(), controller.CreateWebhookInput{
+			URL:       "https://example.com/hook",
+			Events:    []string{"message.created"},
+			RoomID:    roomID,
+			AccountID: accountID,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, webhookID.String(), out.ID)
+		require.Equal(t, "https://example.com/hook", out.URL)
+		require.Equal(t, []string{"message.created"}, out.Events)
+		require.NotEmpty(t, out.Secret)
+		require.Equal(t, saved, out.Secret)
+	})
+
+	t.Run("指定した共有鍵を保存する", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockWebhookRepository{
+			createWebhookFunc: func(ctx context.Context, inp repository.CreateWebhookInput) (repository.CreateWebhookOutput, error) {
+				require.Equal(t, "0123456789abcdef", inp.Secret)
+				return repository.CreateWebhookOutput{ID: uuid.New()}, nil
+			},
+		}
+
+		c := controller.NewCreateWebhookController(mockRepo, domain.NewWebhookNetworkPolicy(false))
+		_, err := c.CreateWebhook(t.Context(), controller.CreateWebhookInput{
+			URL:       "https://example.com/hook",
+			Secret:    "0123456789abcdef",
+			Events:    []string{"room.updated"},
+			RoomID:    roomID,
+			AccountID: accountID,
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("不正な入力でエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name     string
+			input    controller.CreateWebhookInput
+			expected error
+		}{
+			{"bad url", controller.CreateWebhookInput{URL: "example.com", Events: []string{"message.created"}}, domain.ErrInvalidWebhookURL},
+			{"short secret", controller.CreateWebhookInput{URL: "https://example.com", Secret: "short", Events: []string{"message.created"}}, domain.ErrInvalidWebhookSecret},
+			{"unknown event", controller.CreateWebhookInput{URL: "https://example.com", Events: []string{"room.created"}}, domain.ErrInvalidWebhookEventType},
+			{"loopback url", controller.CreateWebhookInput{URL: "http://127.0.0.1:8080/hook", Events: []string{"message.created"}}, domain.ErrWebhookURLNotAllowed},
+			{"localhost url", controller.CreateWebhookInput{URL: "http://localhost/hook", Events: []string{"message.created"}}, domain.ErrWebhookURLNotAllowed},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				mockRepo := &mockWebhookRepository{
+					createWebhookFunc: func(ctx context.Context, inp repository.CreateWebhookInput) (repository.CreateWebhookOutput, error) {
+						t.Fatal("should not be called")
+						return repository.CreateWebhookOutput{}, nil
+					},
+				}
+
+				inp := tt.input
+				inp.RoomID = roomID
+				inp.AccountID = accountID
+				_, err := controller.NewCreateWebhookController(mockRepo, domain.NewWebhookNetworkPolicy(false)).CreateWebhook(t.Context(), inp)
+				require.ErrorIs(t, err, tt.expected)
+			})
+		}
+	})
+}
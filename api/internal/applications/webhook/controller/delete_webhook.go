@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+)
+
+type DeleteWebhookInput struct {
+	RoomID    string
+	WebhookID string
+	AccountID string
+}
+
+type DeleteWebhookController struct {
+	repo repository.WebhookRepository
+}
+
+func NewDeleteWebhookController(repo repository.WebhookRepository) *DeleteWebhookController {
+	return &DeleteWebhookController{repo}
+}
+
+func (c *DeleteWebhookController) DeleteWebhook(ctx context.Context, inp DeleteWebhookInput) error {
+	uc := usecase.NewDeleteWebhookUsecase(c.repo)
+	_, err := tracing.Execute(ctx, "DeleteWebhookUsecase", uc.Execute, usecase.DeleteWebhookInput{
+		RoomID:    inp.RoomID,
+		WebhookID: inp.WebhookID,
+		AccountID: inp.AccountID,
+	})
+	return err
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/service"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+)
+
+type DeliverWebhooksInput struct {
+	BatchSize int
+	Lease     time.Duration
+}
+
+// DeliverWebhooksOutput の Claimed は今回取り出した配信の数
+type DeliverWebhooksOutput struct {
+	Claimed   int
+	Succeeded int
+	Failed    int
+}
+
+type DeliverWebhooksController struct {
+	repo   repository.WebhookRepository
+	sender service.WebhookSender
+	policy domain.WebhookRetryPolicy
+}
+
+func NewDeliverWebhooksController(repo repository.WebhookRepository, sender service.WebhookSender, policy domain.WebhookRetryPolicy) *DeliverWebhooksController {
+	return &DeliverWebhooksController{repo, sender, policy}
+}
+
+func (c *DeliverWebhooksController) DeliverWebhooks(ctx context.Context, inp DeliverWebhooksInput) (DeliverWebhooksOutput, error) {
+	uc := usecase.NewDeliverWebhooksUsecase(c.repo, c.sender, c.policy)
+	res, err := tracing.Execute(ctx, "DeliverWebhooksUsecase", uc.Execute, usecase.DeliverWebhooksInput{
+		BatchSize: inp.BatchSize,
+		Lease:     inp.Lease,
+	})
+	return DeliverWebhooksOutput{
+		Claimed:   res.Claimed,
+		Succeeded: res.Succeeded,
+		Failed:    res.Failed,
+	}, err
+}
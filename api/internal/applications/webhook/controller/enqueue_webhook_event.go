@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+)
+
+// EnqueueWebhookEventInput の Data は送信本文の data に入る
+type EnqueueWebhookEventInput struct {
+	RoomID    string
+	EventType domain.WebhookEventType
+	Data      any
+}
+
+type EnqueueWebhookEventController struct {
+	repo repository.WebhookRepository
+}
+
+func NewEnqueueWebhookEventController(repo repository.WebhookRepository) *EnqueueWebhookEventController {
+	return &EnqueueWebhookEventController{repo}
+}
+
+func (c *EnqueueWebhookEventController) EnqueueWebhookEvent(ctx context.Context, inp EnqueueWebhookEventInput) error {
+	uc := usecase.NewEnqueueWebhookEventUsecase(c.repo)
+	_, err := tracing.Execute(ctx, "EnqueueWebhookEventUsecase", uc.Execute, usecase.EnqueueWebhookEventInput{
+		RoomID:    inp.RoomID,
+		EventType: inp.EventType,
+		Data:      inp.Data,
+	})
+	return err
+}
+
+// MessageCreatedData は message.created の data
+type MessageCreatedData struct {
+	ID            string   `json:"id"`
+	AuthorID      string   `json:"authorId"`
+	Content       string   `json:"content"`
+	Format        string   `json:"format"`
+	AttachmentIDs []string `json:"attachmentIds"`
+}
+
+// RoomUpdatedData は room.updated の data。変更後のルーム名とトピックを送る
+type RoomUpdatedData struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Topic string `json:"topic"`
+}
+
+// RoomData は room.archived, room.unarchived, room.deleted の data
+type RoomData struct {
+	ID string `json:"id"`
+}
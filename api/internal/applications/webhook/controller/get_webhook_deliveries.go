@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/queryprocessor"
+)
+
+const (
+	defaultDeliveriesLimit = 50
+	maxDeliveriesLimit     = 100
+)
+
+var (
+	ErrInvalidDeliveriesLimit = errors.New("invalid deliveries limit")
+)
+
+// GetWebhookDeliveriesInput の Limit が 0 の場合はデフォルト件数を返す
+type GetWebhookDeliveriesInput struct {
+	RoomID    string
+	WebhookID string
+	AccountID string
+	Limit     int
+}
+
+type GetWebhookDeliveriesOutput struct {
+	Deliveries []Delivery `json:"deliveries"`
+}
+
+// Delivery の Status は pending, succeeded, failed のいずれか
+type Delivery struct {
+	ID             string `json:"id"`
+	Event          string `json:"event"`
+	Status         string `json:"status"`
+	Attempts       int    `json:"attempts"`
+	LastStatusCode int    `json:"lastStatusCode,omitempty"`
+	LastError      string `json:"lastError,omitempty"`
+	CreatedAt      string `json:"createdAt"`
+	NextAttemptAt  string `json:"nextAttemptAt,omitempty"`
+	DeliveredAt    string `json:"deliveredAt,omitempty"`
+}
+
+type GetWebhookDeliveriesController struct {
+	query queryprocessor.WebhookQueryProcessor
+}
+
+func NewGetWebhookDeliveriesController(query queryprocessor.WebhookQueryProcessor) *GetWebhookDeliveriesController {
+	return &GetWebhookDeliveriesController{query}
+}
+
+// GetWebhookDeliveries は新しい順に配信の履歴を返す
+func (c *GetWebhookDeliveriesController) GetWebhookDeliveries(ctx context.Context, inp GetWebhookDeliveriesInput) (GetWebhookDeliveriesOutput, error) {
+	limit := inp.Limit
+	if limit == 0 {
+		limit = defaultDeliveriesLimit
+	}
+	if limit < 0 || limit > maxDeliveriesLimit {
+		return GetWebhookDeliveriesOutput{}, ErrInvalidDeliveriesLimit
+	}
+
+	roomID, accountID, err := parseQueryIDs(inp.RoomID, inp.AccountID)
+	if err != nil {
+		return GetWebhookDeliveriesOutput{}, err
+	}
+	webhookID, err := uuid.Parse(inp.WebhookID)
+	if err != nil {
+		return GetWebhookDeliveriesOutput{}, queryprocessor.ErrWebhookNotFound
+	}
+
+	res, err := c.query.GetDeliveries(ctx, queryprocessor.GetDeliveriesInput{
+		RoomID:    roomID,
+		WebhookID: webhookID,
+		AccountID: accountID,
+		Limit:     limit,
+	})
+	if err != nil {
+		return GetWebhookDeliveriesOutput{}, fmt.Errorf("failed to get deliveries: %w", err)
+	}
+
+	deliveries := make([]Delivery, len(res.Deliveries))
+	for i, d := range res.Deliveries {
+		deliveries[i] = Delivery{
+			ID:             d.ID,
+			Event:          d.EventType,
+			Status:         d.Status,
+			Attempts:       d.Attempts,
+			LastStatusCode: d.LastStatusCode,
+			LastError:      d.LastError,
+			CreatedAt:      d.CreatedAt,
+			NextAttemptAt:  d.NextAttemptAt,
+			DeliveredAt:    d.DeliveredAt,
+		}
+	}
+	return GetWebhookDeliveriesOutput{Deliveries: deliveries}, nil
+}
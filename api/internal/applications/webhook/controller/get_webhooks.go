@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/queryprocessor"
+)
+
+type GetWebhooksInput struct {
+	RoomID    string
+	AccountID string
+}
+
+type GetWebhooksOutput struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+type Webhook struct {
+	ID        string   `json:"id"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	CreatedBy string   `json:"createdBy"`
+	CreatedAt string   `json:"createdAt"`
+}
+
+type GetWebhooksController struct {
+	query queryprocessor.WebhookQueryProcessor
+}
+
+func NewGetWebhooksController(query queryprocessor.WebhookQueryProcessor) *GetWebhooksController {
+	return &GetWebhooksController{query}
+}
+
+func (c *GetWebhooksController) GetWebhooks(ctx context.Context, inp GetWebhooksInput) (GetWebhooksOutput, error) {
+	roomID, accountID, err := parseQueryIDs(inp.RoomID, inp.AccountID)
+	if err != nil {
+		return GetWebhooksOutput{}, err
+	}
+
+	res, err := c.query.GetWebhooks(ctx, queryprocessor.GetWebhooksInput{
+		RoomID:    roomID,
+		AccountID: accountID,
+	})
+	if err != nil {
+		return GetWebhooksOutput{}, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	webhooks := make([]Webhook, len(res.Webhooks))
+	for i, w := range res.Webhooks {
+		webhooks[i] = Webhook{
+			ID:        w.ID,
+			URL:       w.URL,
+			Events:    w.EventTypes,
+			CreatedBy: w.CreatedBy,
+			CreatedAt: w.CreatedAt,
+		}
+	}
+	return GetWebhooksOutput{Webhooks: webhooks}, nil
+}
+
+// parseQueryIDs は不正なルーム ID を存在しないルームとして扱う
+func parseQueryIDs(roomID, accountID string) (uuid.UUID, uuid.UUID, error) {
+	room, err := uuid.Parse(roomID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, queryprocessor.ErrRoomNotFound
+	}
+	account, err := uuid.Parse(accountID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("bad account id: %w", err)
+	}
+	return room, account, nil
+}
@@ -0,0 +1,118 @@
+package queryprocessorimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/queryprocessor"
+	"github.com/quietsato/toy-small-chat/api/internal/db"
+)
+
+type WebhookQueryProcessorOnDB struct {
+	queries *db.Queries
+}
+
+func NewWebhookQueryProcessorOnDB(pool *pgxpool.Pool) *WebhookQueryProcessorOnDB {
+	return &WebhookQueryProcessorOnDB{
+		queries: db.New(pool),
+	}
+}
+
+// GetWebhooks implements queryprocessor.WebhookQueryProcessor.
+func (q *WebhookQueryProcessorOnDB) GetWebhooks(ctx context.Context, inp queryprocessor.GetWebhooksInput) (queryprocessor.GetWebhooksOutput, error) {
+	if err := q.checkOwner(ctx, inp.RoomID, inp.AccountID); err != nil {
+		return queryprocessor.GetWebhooksOutput{}, err
+	}
+
+	rows, err := q.queries.GetWebhooksByRoomID(ctx, inp.RoomID)
+	if err != nil {
+		return queryprocessor.GetWebhooksOutput{}, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	webhooks := make([]queryprocessor.WebhookDTO, len(rows))
+	for i, row := range rows {
+		webhooks[i] = queryprocessor.WebhookDTO{
+			ID:         row.ID.String(),
+			URL:        row.Url,
+			EventTypes: row.EventTypes,
+			CreatedBy:  row.CreatedBy.String(),
+			CreatedAt:  formatTimestamp(row.CreatedAt),
+		}
+	}
+	return queryprocessor.GetWebhooksOutput{Webhooks: webhooks}, nil
+}
+
+// GetDeliveries implements queryprocessor.WebhookQueryProcessor.
+func (q *WebhookQueryProcessorOnDB) GetDeliveries(ctx context.Context, inp queryprocessor.GetDeliveriesInput) (queryprocessor.GetDeliveriesOutput, error) {
+	if err := q.checkOwner(ctx, inp.RoomID, inp.AccountID); err != nil {
+		return queryprocessor.GetDeliveriesOutput{}, err
+	}
+
+	exists, err := q.queries.WebhookExistsInRoom(ctx, db.WebhookExistsInRoomParams{
+		ID:     inp.WebhookID,
+		RoomID: inp.RoomID,
+	})
+	if err != nil {
+		return queryprocessor.GetDeliveriesOutput{}, fmt.Errorf("failed to check webhook: %w", err)
+	}
+	if !exists {
+		return queryprocessor.GetDeliveriesOutput{}, queryprocessor.ErrWebhookNotFound
+	}
+
+	rows, err := q.queries.GetWebhookDeliveries(ctx, db.GetWebhookDeliveriesParams{
+		WebhookID:  inp.WebhookID,
+		LimitCount: int32(inp.Limit),
+	})
+	if err != nil {
+		return queryprocessor.GetDeliveriesOutput{}, fmt.Errorf("failed to get deliveries: %w", err)
+	}
+
+	deliveries := make([]queryprocessor.DeliveryDTO, len(rows))
+	for i, row := range rows {
+		d := queryprocessor.DeliveryDTO{
+			ID:             row.ID.String(),
+			EventType:      row.EventType,
+			Status:         row.Status,
+			Attempts:       int(row.Attempts),
+			LastStatusCode: int(row.LastStatusCode.Int32),
+			LastError:      row.LastError.String,
+			CreatedAt:      formatTimestamp(row.CreatedAt),
+			DeliveredAt:    formatTimestamp(row.DeliveredAt),
+		}
+		if row.Status == "pending" {
+			d.NextAttemptAt = formatTimestamp(row.NextAttemptAt)
+		}
+		deliveries[i] = d
+	}
+	return queryprocessor.GetDeliveriesOutput{Deliveries: deliveries}, nil
+}
+
+// checkOwner は Webhook の設定と配信履歴をルームの作成者だけが参照できるようにする
+func (q *WebhookQueryProcessorOnDB) checkOwner(ctx context.Context, roomID, accountID uuid.UUID) error {
+	owner, err := q.queries.GetRoomOwner(ctx, roomID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return queryprocessor.ErrRoomNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get room owner: %w", err)
+	}
+	if owner != accountID {
+		return queryprocessor.ErrNotRoomOwner
+	}
+	return nil
+}
+
+func formatTimestamp(t pgtype.Timestamp) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.Format(time.RFC3339)
+}
+
+var _ queryprocessor.WebhookQueryProcessor = new(WebhookQueryProcessorOnDB)
@@ -0,0 +1,199 @@
+package repositoryimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/db"
+)
+
+func NewWebhookRepositoryOnDB(pool *pgxpool.Pool) *WebhookRepositoryOnDB {
+	return &WebhookRepositoryOnDB{pool}
+}
+
+type WebhookRepositoryOnDB struct {
+	pool *pgxpool.Pool
+}
+
+// CreateWebhook implements repository.WebhookRepository.
+func (r *WebhookRepositoryOnDB) CreateWebhook(ctx context.Context, inp repository.CreateWebhookInput) (repository.CreateWebhookOutput, error) {
+	var out repository.CreateWebhookOutput
+	err := r.withOwnerLock(ctx, inp.RoomID, inp.AccountID, func(queries *db.Queries) error {
+		// ルームの行をロックしているため、件数の確認と追加の間に他の登録は割り込まない
+		count, err := queries.CountWebhooksByRoomID(ctx, inp.RoomID)
+		if err != nil {
+			return fmt.Errorf("failed to count webhooks: %w", err)
+		}
+		if count >= int64(inp.MaxWebhooks) {
+			return repository.ErrTooManyWebhooks
+		}
+
+		id, err := queries.CreateWebhook(ctx, db.CreateWebhookParams{
+			RoomID:     inp.RoomID,
+			Url:        inp.URL,
+			Secret:     inp.Secret,
+			EventTypes: inp.EventTypes,
+			CreatedBy:  inp.AccountID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create webhook: %w", err)
+		}
+		out.ID = id
+		return nil
+	})
+	return out, err
+}
+
+// DeleteWebhook implements repository.WebhookRepository.
+func (r *WebhookRepositoryOnDB) DeleteWebhook(ctx context.Context, inp repository.DeleteWebhookInput) error {
+	return r.withOwnerLock(ctx, inp.RoomID, inp.AccountID, func(queries *db.Queries) error {
+		deleted, err := queries.DeleteWebhook(ctx, db.DeleteWebhookParams{
+			ID:     inp.WebhookID,
+			RoomID: inp.RoomID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete webhook: %w", err)
+		}
+		if deleted == 0 {
+			return repository.ErrWebhookNotFound
+		}
+		return nil
+	})
+}
+
+// EnqueueDeliveries implements repository.WebhookRepository.
+func (r *WebhookRepositoryOnDB) EnqueueDeliveries(ctx context.Context, inp repository.EnqueueDeliveriesInput) (repository.EnqueueDeliveriesOutput, error) {
+	queries := db.New(r.pool)
+	count, err := queries.EnqueueWebhookDeliveries(ctx, db.EnqueueWebhookDeliveriesParams{
+		EventType: inp.EventType,
+		Payload:   inp.Payload,
+		RoomID:    inp.RoomID,
+	})
+	if err != nil {
+		return repository.EnqueueDeliveriesOutput{}, fmt.Errorf("failed to enqueue deliveries: %w", err)
+	}
+	return repository.EnqueueDeliveriesOutput{Count: int(count)}, nil
+}
+
+// ClaimDeliveries implements repository.WebhookRepository.
+//
+// 取り出した配信は送信回数を増やし、次の送信時刻を Lease の後にずらす。
+// FOR UPDATE SKIP LOCKED で取り出すため、複数のワーカーが同じ配信を送ることはない
+func (r *WebhookRepositoryOnDB) ClaimDeliveries(ctx context.Context, inp repository.ClaimDeliveriesInput) (repository.ClaimDeliveriesOutput, error) {
+	queries := db.New(r.pool)
+	rows, err := queries.ClaimWebhookDeliveries(ctx, db.ClaimWebhookDeliveriesParams{
+		Lease:      toInterval(inp.Lease),
+		LimitCount: int32(inp.Limit),
+	})
+	if err != nil {
+		return repository.ClaimDeliveriesOutput{}, fmt.Errorf("failed to claim deliveries: %w", err)
+	}
+
+	deliveries := make([]repository.Delivery, len(rows))
+	for i, row := range rows {
+		deliveries[i] = repository.Delivery{
+			ID:        row.ID,
+			EventType: row.EventType,
+			Payload:   row.Payload,
+			Attempts:  int(row.Attempts),
+			URL:       row.Url,
+			Secret:    row.Secret,
+		}
+	}
+	return repository.ClaimDeliveriesOutput{Deliveries: deliveries}, nil
+}
+
+// CompleteDelivery implements repository.WebhookRepository.
+func (r *WebhookRepositoryOnDB) CompleteDelivery(ctx context.Context, inp repository.CompleteDeliveryInput) error {
+	queries := db.New(r.pool)
+	if err := queries.CompleteWebhookDelivery(ctx, db.CompleteWebhookDeliveryParams{
+		StatusCode: toStatusCode(inp.StatusCode),
+		ID:         inp.DeliveryID,
+	}); err != nil {
+		return fmt.Errorf("failed to complete delivery: %w", err)
+	}
+	return nil
+}
+
+// FailDelivery implements repository.WebhookRepository.
+func (r *WebhookRepositoryOnDB) FailDelivery(ctx context.Context, inp repository.FailDeliveryInput) error {
+	queries := db.New(r.pool)
+	lastError := pgtype.Text{String: inp.Error, Valid: true}
+
+	if inp.RetryAfter <= 0 {
+		if err := queries.FailWebhookDelivery(ctx, db.FailWebhookDeliveryParams{
+			StatusCode: toStatusCode(inp.StatusCode),
+			LastError:  lastError,
+			ID:         inp.DeliveryID,
+		}); err != nil {
+			return fmt.Errorf("failed to fail delivery: %w", err)
+		}
+		return nil
+	}
+
+	if err := queries.RetryWebhookDelivery(ctx, db.RetryWebhookDeliveryParams{
+		StatusCode: toStatusCode(inp.StatusCode),
+		LastError:  lastError,
+		Delay:      toInterval(inp.RetryAfter),
+		ID:         inp.DeliveryID,
+	}); err != nil {
+		return fmt.Errorf("failed to schedule retry: %w", err)
+	}
+	return nil
+}
+
+// withOwnerLock はルームの行をロックし、操作者がルームの作成者であることを確認してから fn を実行する
+//
+// Webhook の設定はアーカイブ済みのルームでも変更できる
+func (r *WebhookRepositoryOnDB) withOwnerLock(ctx context.Context, roomID, accountID uuid.UUID, fn func(queries *db.Queries) error) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rollback", slog.Any("err", err))
+		}
+	}()
+
+	queries := db.New(r.pool).WithTx(tx)
+
+	room, err := queries.GetRoomForUpdate(ctx, roomID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.ErrRoomNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get room: %w", err)
+	}
+	if room.CreatedBy != accountID {
+		return repository.ErrNotRoomOwner
+	}
+
+	if err := fn(queries); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
+
+func toInterval(d time.Duration) pgtype.Interval {
+	return pgtype.Interval{Microseconds: d.Microseconds(), Valid: true}
+}
+
+// toStatusCode は応答を受け取れなかった場合の 0 を NULL として保存する
+func toStatusCode(code int) pgtype.Int4 {
+	return pgtype.Int4{Int32: int32(code), Valid: code != 0}
+}
+
+var _ repository.WebhookRepository = new(WebhookRepositoryOnDB)
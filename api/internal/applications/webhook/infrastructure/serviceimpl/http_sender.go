@@ -0,0 +1,67 @@
+package serviceimpl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/service"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/outbound"
+)
+
+// 送信するリクエストのヘッダ。受信側は HeaderSignature を HeaderTimestamp と本文から検証する
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// maxResponseBodySize は接続を再利用するために読み捨てる応答本文の上限
+const maxResponseBodySize = 64 << 10
+
+// HTTPWebhookSender は HMAC-SHA256 で署名した JSON を POST する
+type HTTPWebhookSender struct {
+	client *http.Client
+}
+
+// NewHTTPWebhookSender の timeout は 1 回の送信にかける時間の上限
+//
+// policy が許可しないアドレスには接続しない。リダイレクトには従わず、3xx の応答は失敗として扱う
+func NewHTTPWebhookSender(timeout time.Duration, policy domain.WebhookNetworkPolicy) *HTTPWebhookSender {
+	return &HTTPWebhookSender{
+		client: outbound.NewClient(timeout, policy),
+	}
+}
+
+// Send implements service.WebhookSender.
+func (s *HTTPWebhookSender) Send(ctx context.Context, inp service.SendWebhookInput) (service.SendWebhookOutput, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, inp.URL, bytes.NewReader(inp.Payload))
+	if err != nil {
+		return service.SendWebhookOutput{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	now := time.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "toy-small-chat-webhook")
+	req.Header.Set(HeaderEvent, inp.EventType)
+	req.Header.Set(HeaderDelivery, inp.DeliveryID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(HeaderSignature, domain.SignWebhookPayload(inp.Secret, now, inp.Payload))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return service.SendWebhookOutput{}, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, maxResponseBodySize))
+
+	return service.SendWebhookOutput{StatusCode: res.StatusCode}, nil
+}
+
+var _ service.WebhookSender = new(HTTPWebhookSender)
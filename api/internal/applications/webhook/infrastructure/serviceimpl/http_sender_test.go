@@ -0,0 +1,127 @@
+package serviceimpl_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/infrastructure/serviceimpl"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/service"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/outbound"
+	"github.com/stretchr/testify/require"
+)
+
+// allowLoopback は httptest のサーバーに送信するために内部のネットワークへの送信を許可する
+var allowLoopback = domain.NewWebhookNetworkPolicy(true)
+
+func TestHTTPWebhookSender_Send(t *testing.T) {
+	t.Parallel()
+
+	t.Run("署名付きの本文を送信する", func(t *testing.T) {
+		t.Parallel()
+
+		payload := []byte(`{"type":"message.created"}`)
+		received := make(chan *http.Request, 1)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.Equal(t, payload, body)
+
+			sec, err := strconv.ParseInt(r.Header.Get(serviceimpl.HeaderTimestamp), 10, 64)
+			require.NoError(t, err)
+			require.Equal(t,
+				domain.SignWebhookPayload("0123456789abcdef", time.Unix(sec, 0), body),
+				r.Header.Get(serviceimpl.HeaderSignature),
+			)
+
+			received <- r
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		t.Cleanup(srv.Close)
+
+		sender := serviceimpl.NewHTTPWebhookSender(time.Second, allowLoopback)
+		res, err := sender.Send(t.Context(), service.SendWebhookInput{
+			URL:        srv.URL,
+			Secret:     "0123456789abcdef",
+			DeliveryID: "delivery-1",
+			EventType:  "message.created",
+			Payload:    payload,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNoContent, res.StatusCode)
+
+		r := <-received
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.Equal(t, "message.created", r.Header.Get(serviceimpl.HeaderEvent))
+		require.Equal(t, "delivery-1", r.Header.Get(serviceimpl.HeaderDelivery))
+	})
+
+	t.Run("2xx 以外の応答はステータスコードを返す", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		}))
+		t.Cleanup(srv.Close)
+
+		sender := serviceimpl.NewHTTPWebhookSender(time.Second, allowLoopback)
+		res, err := sender.Send(t.Context(), service.SendWebhookInput{URL: srv.URL})
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+	})
+
+	t.Run("リダイレクトに従わない", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/moved" {
+				t.Error("redirect should not be followed")
+			}
+			http.Redirect(w, r, "/moved", http.StatusFound)
+		}))
+		t.Cleanup(srv.Close)
+
+		sender := serviceimpl.NewHTTPWebhookSender(time.Second, allowLoopback)
+		res, err := sender.Send(t.Context(), service.SendWebhookInput{URL: srv.URL})
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusFound, res.StatusCode)
+	})
+
+	t.Run("タイムアウトした場合にエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		release := make(chan struct{})
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		t.Cleanup(srv.Close)
+		t.Cleanup(func() { close(release) })
+
+		sender := serviceimpl.NewHTTPWebhookSender(50*time.Millisecond, allowLoopback)
+		_, err := sender.Send(t.Context(), service.SendWebhookInput{URL: srv.URL})
+
+		require.Error(t, err)
+	})
+
+	t.Run("内部のネットワークには送信しない", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("request should not be sent")
+		}))
+		t.Cleanup(srv.Close)
+
+		sender := serviceimpl.NewHTTPWebhookSender(time.Second, domain.NewWebhookNetworkPolicy(false))
+		_, err := sender.Send(t.Context(), service.SendWebhookInput{URL: srv.URL})
+
+		require.ErrorIs(t, err, outbound.ErrAddressNotAllowed)
+	})
+}
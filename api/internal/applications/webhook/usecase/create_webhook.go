@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+)
+
+type CreateWebhookUsecase struct {
+	repo repository.WebhookRepository
+}
+
+type CreateWebhookInput struct {
+	RoomID     string
+	AccountID  string
+	URL        domain.WebhookURL
+	Secret     domain.WebhookSecret
+	EventTypes []domain.WebhookEventType
+}
+
+type CreateWebhookOutput struct {
+	ID         string
+	EventTypes []domain.WebhookEventType
+}
+
+// MaxWebhooksPerRoom はルームごとに登録できる Webhook 数の上限
+const MaxWebhooksPerRoom = 10
+
+var (
+	// ErrNoWebhookEvents は購読するイベントが 1 つも指定されていない場合に返す
+	ErrNoWebhookEvents = errors.New("no webhook events")
+)
+
+func NewCreateWebhookUsecase(repo repository.WebhookRepository) *CreateWebhookUsecase {
+	return &CreateWebhookUsecase{repo}
+}
+
+func (u *CreateWebhookUsecase) Execute(ctx context.Context, inp CreateWebhookInput) (CreateWebhookOutput, error) {
+	eventTypes := make([]domain.WebhookEventType, 0, len(inp.EventTypes))
+	for _, t := range inp.EventTypes {
+		if !slices.Contains(eventTypes, t) {
+			eventTypes = append(eventTypes, t)
+		}
+	}
+	if len(eventTypes) == 0 {
+		return CreateWebhookOutput{}, ErrNoWebhookEvents
+	}
+
+	ids, err := parseWebhookIDs(inp.RoomID, inp.AccountID)
+	if err != nil {
+		return CreateWebhookOutput{}, err
+	}
+
+	types := make([]string, len(eventTypes))
+	for i, t := range eventTypes {
+		types[i] = t.String()
+	}
+	res, err := u.repo.CreateWebhook(ctx, repository.CreateWebhookInput{
+		RoomID:      ids.roomID,
+		AccountID:   ids.accountID,
+		URL:         inp.URL.String(),
+		Secret:      inp.Secret.String(),
+		EventTypes:  types,
+		MaxWebhooks: MaxWebhooksPerRoom,
+	})
+	if err != nil {
+		return CreateWebhookOutput{}, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return CreateWebhookOutput{
+		ID:         res.ID.String(),
+		EventTypes: eventTypes,
+	}, nil
+}
+
+type webhookIDs struct {
+	roomID    uuid.UUID
+	accountID uuid.UUID
+}
+
+// parseWebhookIDs は不正なルーム ID を存在しないルームとして扱う
+func parseWebhookIDs(roomID, accountID string) (webhookIDs, error) {
+	room, err := uuid.Parse(roomID)
+	if err != nil {
+		return webhookIDs{}, repository.ErrRoomNotFound
+	}
+	account, err := uuid.Parse(accountID)
+	if err != nil {
+		return webhookIDs{}, fmt.Errorf("failed to parse account id: %w", err)
+	}
+	return webhookIDs{roomID: room, accountID: account}, nil
+}
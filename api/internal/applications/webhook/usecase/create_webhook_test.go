@@ -0,0 +1,213 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock implementations
+type mockWebhookRepository struct {
+	createWebhookFunc     func(ctx context.Context, inp repository.CreateWebhookInput) (repository.CreateWebhookOutput, error)
+	deleteWebhookFunc     func(ctx context.Context, inp repository.DeleteWebhookInput) error
+	enqueueDeliveriesFunc func(ctx context.Context, inp repository.EnqueueDeliveriesInput) (repository.EnqueueDeliveriesOutput, error)
+	claimDeliveriesFunc   func(ctx context.Context, inp repository.ClaimDeliveriesInput) (repository.ClaimDeliveriesOutput, error)
+	completeDeliveryFunc  func(ctx context.Context, inp repository.CompleteDeliveryInput) error
+	failDeliveryFunc      func(ctx context.Context, inp repository.FailDeliveryInput) error
+}
+
+func (m *mockWebhookRepository) CreateWebhook(ctx context.Context, inp repository.CreateWebhookInput) (repository.CreateWebhookOutput, error) {
+	if m.createWebhookFunc != nil {
+		return m.createWebhookFunc(ctx, inp)
+	}
+	return repository.CreateWebhookOutput{}, nil
+}
+
+func (m *mockWebhookRepository) DeleteWebhook(ctx context.Context, inp repository.DeleteWebhookInput) error {
+	if m.deleteWebhookFunc != nil {
+		return m.deleteWebhookFunc(ctx, inp)
+	}
+	return nil
+}
+
+func (m *mockWebhookRepository) EnqueueDeliveries(ctx context.Context, inp repository.EnqueueDeliveriesInput) (repository.EnqueueDeliveriesOutput, error) {
+	if m.enqueueDeliveriesFunc != nil {
+		return m.enqueueDeliveriesFunc(ctx, inp)
+	}
+	return repository.EnqueueDeliveriesOutput{}, nil
+}
+
+func (m *mockWebhookRepository) ClaimDeliveries(ctx context.Context, inp repository.ClaimDeliveriesInput) (repository.ClaimDeliveriesOutput, error) {
+	if m.claimDeliveriesFunc != nil {
+		return m.claimDeliveriesFunc(ctx, inp)
+	}
+	return repository.ClaimDeliveriesOutput{}, nil
+}
+
+func (m *mockWebhookRepository) CompleteDelivery(ctx context.Context, inp repository.CompleteDeliveryInput) error {
+	if m.completeDeliveryFunc != nil {
+		return m.completeDeliveryFunc(ctx, inp)
+	}
+	return nil
+}
+
+func (m *mockWebhookRepository) FailDelivery(ctx context.Context, inp repository.FailDeliveryInput) error {
+	if m.failDeliveryFunc != nil {
+		return m.failDeliveryFunc(ctx, inp)
+	}
+	return nil
+}
+
+func TestCreateWebhookUsecase_Execute(t *testing.T) {
+	t.Parallel()
+
+	roomID := uuid.New()
+	accountID := uuid.New()
+	url, _ := domain.NewWebhookURL("https://example.com/hook")
+	secret, _ := domain.NewWebhookSecret("0123456789abcdef")
+
+	t.Run("重複したイベントをまとめて登録する", func(t *testing.T) {
+		t.Parallel()
+
+		webhookID := uuid.New()
+		mockRepo := &mockWebhookRepository{
+			createWebhookFunc: func(ctx context.Context, inp repository.CreateWebhookInput) (repository.CreateWebhookOutput, error) {
+				require.Equal(t, roomID, inp.RoomID)
+				require.Equal(t, accountID, inp.AccountID)
+				require.Equal(t, "https://example.com/hook", inp.URL)
+				require.Equal(t, "0123456789abcdef", inp.Secret)
+				require.Equal(t, []string{"message.created", "room.updated"}, inp.EventTypes)
+				require.Equal(t, usecase.MaxWebhooksPerRoom, inp.MaxWebhooks)
+				return repository.CreateWebhookOutput{ID: webhookID}, nil
+			},
+		}
+
+		uc := usecase.NewCreateWebhookUsecase(mockRepo)
+		out, err := uc.Execute(t.Context(), usecase.CreateWebhookInput{
+			RoomID:    roomID.String(),
+			AccountID: accountID.String(),
+			URL:       url,
+			Secret:    secret,
+			EventTypes: []domain.WebhookEventType{
+				domain.WebhookEventMessageCreated,
+				domain.WebhookEventRoomUpdated,
+				domain.WebhookEventMessageCreated,
+			},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, webhookID.String(), out.ID)
+		require.Len(t, out.EventTypes, 2)
+	})
+
+	t.Run("イベントが指定されていない場合にエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockWebhookRepository{
+			createWebhookFunc: func(ctx context.Context, inp repository.CreateWebhookInput) (repository.CreateWebhookOutput, error) {
+				t.Fatal("should not be called")
+				return repository.CreateWebhookOutput{}, nil
+			},
+		}
+
+		uc := usecase.NewCreateWebhookUsecase(mockRepo)
+		_, err := uc.Execute(t.Context(), usecase.CreateWebhookInput{
+			RoomID:    roomID.String(),
+			AccountID: accountID.String(),
+			URL:       url,
+			Secret:    secret,
+		})
+
+		require.ErrorIs(t, err, usecase.ErrNoWebhookEvents)
+	})
+
+	t.Run("不正なルーム ID は存在しないルームとして扱う", func(t *testing.T) {
+		t.Parallel()
+
+		uc := usecase.NewCreateWebhookUsecase(&mockWebhookRepository{})
+		_, err := uc.Execute(t.Context(), usecase.CreateWebhookInput{
+			RoomID:     "invalid",
+			AccountID:  accountID.String(),
+			URL:        url,
+			Secret:     secret,
+			EventTypes: []domain.WebhookEventType{domain.WebhookEventRoomDeleted},
+		})
+
+		require.ErrorIs(t, err, repository.ErrRoomNotFound)
+	})
+
+	t.Run("リポジトリエラー時にエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockWebhookRepository{
+			createWebhookFunc: func(ctx context.Context, inp repository.CreateWebhookInput) (repository.CreateWebhookOutput, error) {
+				return repository.CreateWebhookOutput{}, repository.ErrTooManyWebhooks
+			},
+		}
+
+		uc := usecase.NewCreateWebhookUsecase(mockRepo)
+		_, err := uc.Execute(t.Context(), usecase.CreateWebhookInput{
+			RoomID:     roomID.String(),
+			AccountID:  accountID.String(),
+			URL:        url,
+			Secret:     secret,
+			EventTypes: []domain.WebhookEventType{domain.WebhookEventRoomDeleted},
+		})
+
+		require.ErrorIs(t, err, repository.ErrTooManyWebhooks)
+	})
+}
+
+func TestEnqueueWebhookEventUsecase_Execute(t *testing.T) {
+	t.Parallel()
+
+	t.Run("イベントを共通の形式で登録する", func(t *testing.T) {
+		t.Parallel()
+
+		roomID := uuid.New()
+		mockRepo := &mockWebhookRepository{
+			enqueueDeliveriesFunc: func(ctx context.Context, inp repository.EnqueueDeliveriesInput) (repository.EnqueueDeliveriesOutput, error) {
+				require.Equal(t, roomID, inp.RoomID)
+				require.Equal(t, "room.deleted", inp.EventType)
+				require.Contains(t, string(inp.Payload), `"type":"room.deleted"`)
+				require.Contains(t, string(inp.Payload), `"roomId":"`+roomID.String()+`"`)
+				require.Contains(t, string(inp.Payload), `"data":{"id":"`+roomID.String()+`"}`)
+				return repository.EnqueueDeliveriesOutput{Count: 2}, nil
+			},
+		}
+
+		uc := usecase.NewEnqueueWebhookEventUsecase(mockRepo)
+		out, err := uc.Execute(t.Context(), usecase.EnqueueWebhookEventInput{
+			RoomID:    roomID.String(),
+			EventType: domain.WebhookEventRoomDeleted,
+			Data:      map[string]string{"id": roomID.String()},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, 2, out.Count)
+	})
+
+	t.Run("リポジトリエラー時にエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockWebhookRepository{
+			enqueueDeliveriesFunc: func(ctx context.Context, inp repository.EnqueueDeliveriesInput) (repository.EnqueueDeliveriesOutput, error) {
+				return repository.EnqueueDeliveriesOutput{}, errors.New("database error")
+			},
+		}
+
+		uc := usecase.NewEnqueueWebhookEventUsecase(mockRepo)
+		_, err := uc.Execute(t.Context(), usecase.EnqueueWebhookEventInput{
+			RoomID:    uuid.NewString(),
+			EventType: domain.WebhookEventRoomDeleted,
+		})
+
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/repository"
+)
+
+type DeleteWebhookUsecase struct {
+	repo repository.WebhookRepository
+}
+
+type DeleteWebhookInput struct {
+	RoomID    string
+	WebhookID string
+	AccountID string
+}
+
+type DeleteWebhookOutput struct{}
+
+func NewDeleteWebhookUsecase(repo repository.WebhookRepository) *DeleteWebhookUsecase {
+	return &DeleteWebhookUsecase{repo}
+}
+
+// Execute は Webhook と一緒に配信の履歴も削除する
+func (u *DeleteWebhookUsecase) Execute(ctx context.Context, inp DeleteWebhookInput) (DeleteWebhookOutput, error) {
+	ids, err := parseWebhookIDs(inp.RoomID, inp.AccountID)
+	if err != nil {
+		return DeleteWebhookOutput{}, err
+	}
+	webhookID, err := uuid.Parse(inp.WebhookID)
+	if err != nil {
+		return DeleteWebhookOutput{}, repository.ErrWebhookNotFound
+	}
+
+	if err := u.repo.DeleteWebhook(ctx, repository.DeleteWebhookInput{
+		RoomID:    ids.roomID,
+		WebhookID: webhookID,
+		AccountID: ids.accountID,
+	}); err != nil {
+		return DeleteWebhookOutput{}, fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	return DeleteWebhookOutput{}, nil
+}
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/service"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+)
+
+type DeliverWebhooksUsecase struct {
+	repo   repository.WebhookRepository
+	sender service.WebhookSender
+	policy domain.WebhookRetryPolicy
+}
+
+// DeliverWebhooksInput の Lease は 1 件の送信にかかる時間より長くする
+type DeliverWebhooksInput struct {
+	BatchSize int
+	Lease     time.Duration
+}
+
+// DeliverWebhooksOutput の Failed には再送待ちになった配信も含む
+type DeliverWebhooksOutput struct {
+	Claimed   int
+	Succeeded int
+	Failed    int
+}
+
+// maxDeliveryErrorLength は配信履歴に残すエラーメッセージの最大バイト数
+const maxDeliveryErrorLength = 1024
+
+func NewDeliverWebhooksUsecase(repo repository.WebhookRepository, sender service.WebhookSender, policy domain.WebhookRetryPolicy) *DeliverWebhooksUsecase {
+	return &DeliverWebhooksUsecase{repo, sender, policy}
+}
+
+// Execute は送信時刻を過ぎた配信を最大 BatchSize 件取り出して並行に送信し、結果を記録する
+//
+// 2xx 以外の応答と通信エラーは失敗として扱い、再送ポリシーに従って次の送信時刻を決める
+func (u *DeliverWebhooksUsecase) Execute(ctx context.Context, inp DeliverWebhooksInput) (DeliverWebhooksOutput, error) {
+	res, err := u.repo.ClaimDeliveries(ctx, repository.ClaimDeliveriesInput{
+		Limit: inp.BatchSize,
+		Lease: inp.Lease,
+	})
+	if err != nil {
+		return DeliverWebhooksOutput{}, fmt.Errorf("failed to claim deliveries: %w", err)
+	}
+
+	out := DeliverWebhooksOutput{Claimed: len(res.Deliveries)}
+	var (
+		mu   sync.Mutex
+		errs []error
+		wg   sync.WaitGroup
+	)
+	for _, d := range res.Deliveries {
+		wg.Go(func() {
+			succeeded, err := u.deliver(ctx, d)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if succeeded {
+				out.Succeeded++
+			} else {
+				out.Failed++
+			}
+			if err != nil {
+				errs = append(errs, err)
+			}
+		})
+	}
+	wg.Wait()
+
+	return out, errors.Join(errs...)
+}
+
+// deliver は 1 件の配信を送信して結果を記録する。返す error は結果の記録に失敗した場合のみ
+func (u *DeliverWebhooksUsecase) deliver(ctx context.Context, d repository.Delivery) (bool, error) {
+	res, sendErr := u.sender.Send(ctx, service.SendWebhookInput{
+		URL:        d.URL,
+		Secret:     d.Secret,
+		DeliveryID: d.ID.String(),
+		EventType:  d.EventType,
+		Payload:    d.Payload,
+	})
+	if sendErr == nil && res.StatusCode >= 200 && res.StatusCode < 300 {
+		if err := u.repo.CompleteDelivery(ctx, repository.CompleteDeliveryInput{
+			DeliveryID: d.ID,
+			StatusCode: res.StatusCode,
+		}); err != nil {
+			return true, fmt.Errorf("failed to complete delivery %s: %w", d.ID, err)
+		}
+		return true, nil
+	}
+
+	msg := fmt.Sprintf("unexpected status code: %d", res.StatusCode)
+	if sendErr != nil {
+		msg = sendErr.Error()
+	}
+	if len(msg) > maxDeliveryErrorLength {
+		msg = strings.ToValidUTF8(msg[:maxDeliveryErrorLength], "")
+	}
+	retryAfter, _ := u.policy.NextDelay(d.Attempts)
+	if err := u.repo.FailDelivery(ctx, repository.FailDeliveryInput{
+		DeliveryID: d.ID,
+		StatusCode: res.StatusCode,
+		Error:      msg,
+		RetryAfter: retryAfter,
+	}); err != nil {
+		return false, fmt.Errorf("failed to record delivery failure %s: %w", d.ID, err)
+	}
+	return false, nil
+}
@@ -0,0 +1,181 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/service"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+type mockWebhookSender struct {
+	sendFunc func(ctx context.Context, inp service.SendWebhookInput) (service.SendWebhookOutput, error)
+}
+
+func (m *mockWebhookSender) Send(ctx context.Context, inp service.SendWebhookInput) (service.SendWebhookOutput, error) {
+	if m.sendFunc != nil {
+		return m.sendFunc(ctx, inp)
+	}
+	return service.SendWebhookOutput{StatusCode: http.StatusOK}, nil
+}
+
+func TestDeliverWebhooksUsecase_Execute(t *testing.T) {
+	t.Parallel()
+
+	policy := domain.NewWebhookRetryPolicy(3, time.Second, time.Minute)
+
+	claim := func(deliveries ...repository.Delivery) func(ctx context.Context, inp repository.ClaimDeliveriesInput) (repository.ClaimDeliveriesOutput, error) {
+		return func(ctx context.Context, inp repository.ClaimDeliveriesInput) (repository.ClaimDeliveriesOutput, error) {
+			return repository.ClaimDeliveriesOutput{Deliveries: deliveries}, nil
+		}
+	}
+
+	t.Run("2xx の応答を成功として記録する", func(t *testing.T) {
+		t.Parallel()
+
+		delivery := repository.Delivery{ID: uuid.New(), EventType: "message.created", Payload: []byte(`{}`), Attempts: 1, URL: "https://example.com", Secret: "secret"}
+		var completed []repository.CompleteDeliveryInput
+		mockRepo := &mockWebhookRepository{
+			claimDeliveriesFunc: func(ctx context.Context, inp repository.ClaimDeliveriesInput) (repository.ClaimDeliveriesOutput, error) {
+				require.Equal(t, 10, inp.Limit)
+				require.Equal(t, time.Minute, inp.Lease)
+				return repository.ClaimDeliveriesOutput{Deliveries: []repository.Delivery{delivery}}, nil
+			},
+			completeDeliveryFunc: func(ctx context.Context, inp repository.CompleteDeliveryInput) error {
+				completed = append(completed, inp)
+				return nil
+			},
+			failDeliveryFunc: func(ctx context.Context, inp repository.FailDeliveryInput) error {
+				t.Fatal("should not be called")
+				return nil
+			},
+		}
+		sender := &mockWebhookSender{
+			sendFunc: func(ctx context.Context, inp service.SendWebhookInput) (service.SendWebhookOutput, error) {
+				require.Equal(t, "https://example.com", inp.URL)
+				require.Equal(t, "secret", inp.Secret)
+				require.Equal(t, delivery.ID.String(), inp.DeliveryID)
+				require.Equal(t, "message.created", inp.EventType)
+				return service.SendWebhookOutput{StatusCode: http.StatusAccepted}, nil
+			},
+		}
+
+		uc := usecase.NewDeliverWebhooksUsecase(mockRepo, sender, policy)
+		out, err := uc.Execute(t.Context(), usecase.DeliverWebhooksInput{BatchSize: 10, Lease: time.Minute})
+
+		require.NoError(t, err)
+		require.Equal(t, usecase.DeliverWebhooksOutput{Claimed: 1, Succeeded: 1}, out)
+		require.Equal(t, []repository.CompleteDeliveryInput{{DeliveryID: delivery.ID, StatusCode: http.StatusAccepted}}, completed)
+	})
+
+	t.Run("失敗した配信は回数に応じて再送を予約する", func(t *testing.T) {
+		t.Parallel()
+
+		first := repository.Delivery{ID: uuid.New(), Attempts: 1}
+		second := repository.Delivery{ID: uuid.New(), Attempts: 2}
+		var (
+			mu     sync.Mutex
+			failed = map[uuid.UUID]repository.FailDeliveryInput{}
+		)
+		mockRepo := &mockWebhookRepository{
+			claimDeliveriesFunc: claim(first, second),
+			failDeliveryFunc: func(ctx context.Context, inp repository.FailDeliveryInput) error {
+				mu.Lock()
+				defer mu.Unlock()
+				failed[inp.DeliveryID] = inp
+				return nil
+			},
+		}
+		sender := &mockWebhookSender{
+			sendFunc: func(ctx context.Context, inp service.SendWebhookInput) (service.SendWebhookOutput, error) {
+				if inp.DeliveryID == first.ID.String() {
+					return service.SendWebhookOutput{StatusCode: http.StatusInternalServerError}, nil
+				}
+				return service.SendWebhookOutput{}, errors.New("connection refused")
+			},
+		}
+
+		uc := usecase.NewDeliverWebhooksUsecase(mockRepo, sender, policy)
+		out, err := uc.Execute(t.Context(), usecase.DeliverWebhooksInput{BatchSize: 10, Lease: time.Minute})
+
+		require.NoError(t, err)
+		require.Equal(t, usecase.DeliverWebhooksOutput{Claimed: 2, Failed: 2}, out)
+		require.Equal(t, repository.FailDeliveryInput{
+			DeliveryID: first.ID,
+			StatusCode: http.StatusInternalServerError,
+			Error:      "unexpected status code: 500",
+			RetryAfter: time.Second,
+		}, failed[first.ID])
+		require.Equal(t, repository.FailDeliveryInput{
+			DeliveryID: second.ID,
+			Error:      "connection refused",
+			RetryAfter: 2 * time.Second,
+		}, failed[second.ID])
+	})
+
+	t.Run("送信回数が上限に達した配信は再送しない", func(t *testing.T) {
+		t.Parallel()
+
+		delivery := repository.Delivery{ID: uuid.New(), Attempts: 3}
+		var failed repository.FailDeliveryInput
+		mockRepo := &mockWebhookRepository{
+			claimDeliveriesFunc: claim(delivery),
+			failDeliveryFunc: func(ctx context.Context, inp repository.FailDeliveryInput) error {
+				failed = inp
+				return nil
+			},
+		}
+		sender := &mockWebhookSender{
+			sendFunc: func(ctx context.Context, inp service.SendWebhookInput) (service.SendWebhookOutput, error) {
+				return service.SendWebhookOutput{StatusCode: http.StatusGone}, nil
+			},
+		}
+
+		uc := usecase.NewDeliverWebhooksUsecase(mockRepo, sender, policy)
+		_, err := uc.Execute(t.Context(), usecase.DeliverWebhooksInput{BatchSize: 10, Lease: time.Minute})
+
+		require.NoError(t, err)
+		require.Equal(t, delivery.ID, failed.DeliveryID)
+		require.Zero(t, failed.RetryAfter)
+	})
+
+	t.Run("結果の記録に失敗した場合にエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockWebhookRepository{
+			claimDeliveriesFunc: claim(repository.Delivery{ID: uuid.New(), Attempts: 1}),
+			completeDeliveryFunc: func(ctx context.Context, inp repository.CompleteDeliveryInput) error {
+				return errors.New("database error")
+			},
+		}
+
+		uc := usecase.NewDeliverWebhooksUsecase(mockRepo, &mockWebhookSender{}, policy)
+		out, err := uc.Execute(t.Context(), usecase.DeliverWebhooksInput{BatchSize: 10, Lease: time.Minute})
+
+		require.Error(t, err)
+		require.Equal(t, 1, out.Succeeded)
+	})
+
+	t.Run("取り出しに失敗した場合にエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := &mockWebhookRepository{
+			claimDeliveriesFunc: func(ctx context.Context, inp repository.ClaimDeliveriesInput) (repository.ClaimDeliveriesOutput, error) {
+				return repository.ClaimDeliveriesOutput{}, errors.New("database error")
+			},
+		}
+
+		uc := usecase.NewDeliverWebhooksUsecase(mockRepo, &mockWebhookSender{}, policy)
+		_, err := uc.Execute(t.Context(), usecase.DeliverWebhooksInput{BatchSize: 10, Lease: time.Minute})
+
+		require.Error(t, err)
+	})
+}
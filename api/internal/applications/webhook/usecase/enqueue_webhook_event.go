@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+)
+
+type EnqueueWebhookEventUsecase struct {
+	repo repository.WebhookRepository
+}
+
+// EnqueueWebhookEventInput の Data はイベントの種類ごとの内容で、JSON に変換して送信する
+type EnqueueWebhookEventInput struct {
+	RoomID    string
+	EventType domain.WebhookEventType
+	Data      any
+}
+
+// EnqueueWebhookEventOutput の Count は配信を登録した Webhook の数
+type EnqueueWebhookEventOutput struct {
+	Count int
+}
+
+// webhookEvent は送信本文。ID は同じイベントの再送を受信側で判別するために使う
+type webhookEvent struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	RoomID     string `json:"roomId"`
+	OccurredAt string `json:"occurredAt"`
+	Data       any    `json:"data"`
+}
+
+func NewEnqueueWebhookEventUsecase(repo repository.WebhookRepository) *EnqueueWebhookEventUsecase {
+	return &EnqueueWebhookEventUsecase{repo}
+}
+
+// Execute はイベントを購読しているルームの Webhook ごとに配信を登録する。送信はワーカーが非同期に行う
+func (u *EnqueueWebhookEventUsecase) Execute(ctx context.Context, inp EnqueueWebhookEventInput) (EnqueueWebhookEventOutput, error) {
+	roomID, err := uuid.Parse(inp.RoomID)
+	if err != nil {
+		return EnqueueWebhookEventOutput{}, fmt.Errorf("failed to parse room id: %w", err)
+	}
+
+	payload, err := json.Marshal(webhookEvent{
+		ID:         uuid.NewString(),
+		Type:       inp.EventType.String(),
+		RoomID:     roomID.String(),
+		OccurredAt: time.Now().UTC().Format(time.RFC3339),
+		Data:       inp.Data,
+	})
+	if err != nil {
+		return EnqueueWebhookEventOutput{}, fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	res, err := u.repo.EnqueueDeliveries(ctx, repository.EnqueueDeliveriesInput{
+		RoomID:    roomID,
+		EventType: inp.EventType.String(),
+		Payload:   payload,
+	})
+	if err != nil {
+		return EnqueueWebhookEventOutput{}, fmt.Errorf("failed to enqueue deliveries: %w", err)
+	}
+
+	return EnqueueWebhookEventOutput{Count: res.Count}, nil
+}
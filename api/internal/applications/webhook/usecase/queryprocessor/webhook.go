@@ -0,0 +1,63 @@
+package queryprocessor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// GetWebhooksInput の AccountID はルームの作成者である必要がある
+type GetWebhooksInput struct {
+	RoomID    uuid.UUID
+	AccountID uuid.UUID
+}
+type GetWebhooksOutput struct {
+	Webhooks []WebhookDTO
+}
+
+// WebhookDTO は共有鍵を含まない
+type WebhookDTO struct {
+	ID         string
+	URL        string
+	EventTypes []string
+	CreatedBy  string
+	CreatedAt  string
+}
+
+// GetDeliveriesInput は新しい順に Limit 件までの配信を返す
+type GetDeliveriesInput struct {
+	RoomID    uuid.UUID
+	WebhookID uuid.UUID
+	AccountID uuid.UUID
+	Limit     int
+}
+type GetDeliveriesOutput struct {
+	Deliveries []DeliveryDTO
+}
+
+// DeliveryDTO の LastStatusCode は応答を受け取っていない場合に 0
+//
+// NextAttemptAt は再送待ちの場合のみ、DeliveredAt は送信に成功した場合のみ設定する
+type DeliveryDTO struct {
+	ID             string
+	EventType      string
+	Status         string
+	Attempts       int
+	LastStatusCode int
+	LastError      string
+	CreatedAt      string
+	NextAttemptAt  string
+	DeliveredAt    string
+}
+
+var (
+	ErrRoomNotFound    = errors.New("room not found")
+	ErrNotRoomOwner    = errors.New("not room owner")
+	ErrWebhookNotFound = errors.New("webhook not found")
+)
+
+type WebhookQueryProcessor interface {
+	GetWebhooks(ctx context.Context, inp GetWebhooksInput) (GetWebhooksOutput, error)
+	GetDeliveries(ctx context.Context, inp GetDeliveriesInput) (GetDeliveriesOutput, error)
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateWebhookInput の MaxWebhooks はルームあたりの Webhook の上限
+type CreateWebhookInput struct {
+	RoomID      uuid.UUID
+	AccountID   uuid.UUID
+	URL         string
+	Secret      string
+	EventTypes  []string
+	MaxWebhooks int
+}
+type CreateWebhookOutput struct {
+	ID uuid.UUID
+}
+
+type DeleteWebhookInput struct {
+	RoomID    uuid.UUID
+	WebhookID uuid.UUID
+	AccountID uuid.UUID
+}
+
+// EnqueueDeliveriesInput の Payload は送信する JSON の本文
+type EnqueueDeliveriesInput struct {
+	RoomID    uuid.UUID
+	EventType string
+	Payload   []byte
+}
+
+// EnqueueDeliveriesOutput の Count はイベントを購読していて配信を登録した Webhook の数
+type EnqueueDeliveriesOutput struct {
+	Count int
+}
+
+// ClaimDeliveriesInput の Lease は取り出した配信を他のワーカーに渡さない期間
+//
+// Lease のうちに結果を記録しなかった配信は、ワーカーが停止したものとみなして再び取り出される
+type ClaimDeliveriesInput struct {
+	Limit int
+	Lease time.Duration
+}
+type ClaimDeliveriesOutput struct {
+	Deliveries []Delivery
+}
+
+// Delivery の Attempts は今回の送信を含めた送信回数
+type Delivery struct {
+	ID        uuid.UUID
+	EventType string
+	Payload   []byte
+	Attempts  int
+	URL       string
+	Secret    string
+}
+
+// CompleteDeliveryInput の StatusCode は送信先が返したステータスコード
+type CompleteDeliveryInput struct {
+	DeliveryID uuid.UUID
+	StatusCode int
+}
+
+// FailDeliveryInput の StatusCode は応答を受け取れなかった場合に 0
+//
+// RetryAfter が 0 の場合は再送せず失敗として確定する
+type FailDeliveryInput struct {
+	DeliveryID uuid.UUID
+	StatusCode int
+	Error      string
+	RetryAfter time.Duration
+}
+
+var (
+	ErrRoomNotFound = errors.New("room not found")
+	// ErrNotRoomOwner はルームの作成者以外が Webhook を操作しようとした場合に返す
+	ErrNotRoomOwner    = errors.New("not room owner")
+	ErrTooManyWebhooks = errors.New("too many webhooks")
+	ErrWebhookNotFound = errors.New("webhook not found")
+)
+
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, inp CreateWebhookInput) (CreateWebhookOutput, error)
+	DeleteWebhook(ctx context.Context, inp DeleteWebhookInput) error
+	EnqueueDeliveries(ctx context.Context, inp EnqueueDeliveriesInput) (EnqueueDeliveriesOutput, error)
+	ClaimDeliveries(ctx context.Context, inp ClaimDeliveriesInput) (ClaimDeliveriesOutput, error)
+	CompleteDelivery(ctx context.Context, inp CompleteDeliveryInput) error
+	FailDelivery(ctx context.Context, inp FailDeliveryInput) error
+}
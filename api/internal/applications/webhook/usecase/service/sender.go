@@ -0,0 +1,23 @@
+package service
+
+import "context"
+
+// SendWebhookInput の Payload は署名の対象になる送信本文
+type SendWebhookInput struct {
+	URL        string
+	Secret     string
+	DeliveryID string
+	EventType  string
+	Payload    []byte
+}
+
+type SendWebhookOutput struct {
+	StatusCode int
+}
+
+// WebhookSender は署名付きのリクエストを送信先に送る
+//
+// 応答を受け取れた場合は 2xx 以外のステータスコードでも error を返さない
+type WebhookSender interface {
+	Send(ctx context.Context, inp SendWebhookInput) (SendWebhookOutput, error)
+}
@@ -1,35 +1,256 @@
+// Package config は API の設定を、既定値、設定ファイル、環境変数、コマンドラインの順に重ねて読み込む
+//
+// 設定項目はこのファイルの構造体のフィールドで、タグで次のことを表す
+//   - env: 環境変数の名前。入れ子の構造体は親の名前を _ でつなぐ (DATABASE_POOL_MAX_CONNS など)。
+//     設定ファイルでは小文字にしたものをキーにする (database.pool.max_conns)
+//   - default: どこでも指定しなかった場合の値
+//   - required: "true" の場合は空にできない
+//   - secret: "true" の場合は config print で値を伏せる
 package config
 
 import (
-	"fmt"
+	"errors"
 	"log/slog"
-
-	"github.com/kelseyhightower/envconfig"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 )
 
+type Server struct {
+	// Addr は API を公開するアドレス
+	Addr              string        `env:"ADDR" default:":8080" required:"true"`
+	ReadHeaderTimeout time.Duration `env:"READ_HEADER_TIMEOUT" default:"10s"`
+	// ReadTimeout はリクエストボディを読み終えるまでの時間の上限。添付ファイルのアップロードも含む
+	ReadTimeout time.Duration `env:"READ_TIMEOUT" default:"60s"`
+	// WriteTimeout はレスポンスを書き終えるまでの時間の上限。0 の場合は制限しない。
+	// ルームのエクスポートは件数に応じて書き続けるため、既定では制限しない
+	WriteTimeout time.Duration `env:"WRITE_TIMEOUT" default:"0s"`
+	IdleTimeout  time.Duration `env:"IDLE_TIMEOUT" default:"120s"`
+	// RequestTimeout はハンドラがリクエストを処理する時間の上限
+	RequestTimeout time.Duration `env:"REQUEST_TIMEOUT" default:"60s"`
+	// ShutdownTimeout は終了時に処理中のリクエストとバックグラウンドの処理を待つ時間の上限
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" default:"30s"`
+	// TrustedProxies はクライアントの IP アドレスを転送ヘッダーで伝えるリバースプロキシの CIDR。
+	// ここに含まれない接続元から届いた X-Forwarded-For などは無視する
+	TrustedProxies []string `env:"TRUSTED_PROXIES"`
+}
+
+type CORS struct {
+	// AllowedOrigins はブラウザからの呼び出しを許可するオリジン。
+	// https://*.example.com のようにサブドメインを * で表せる。"*" の場合はすべてのオリジンを許可する
+	AllowedOrigins []string `env:"ALLOWED_ORIGINS" default:"http://localhost:18080,http://localhost:5173"`
+	AllowedMethods []string `env:"ALLOWED_METHODS" default:"GET,HEAD,POST,PUT,PATCH,DELETE"`
+	AllowedHeaders []string `env:"ALLOWED_HEADERS" default:"Accept,Authorization,Content-Type,X-CSRF-Token"`
+	// AllowCredentials は Cookie などの資格情報を付けた呼び出しを許可するかどうか。"*" のオリジンとは併用できない
+	AllowCredentials bool `env:"ALLOW_CREDENTIALS" default:"false"`
+	// MaxAge はプリフライトリクエストの結果をブラウザがキャッシュする時間
+	MaxAge time.Duration `env:"MAX_AGE" default:"24h"`
+}
+
+type SecurityHeaders struct {
+	// Preset は development か production。production では HSTS を送る
+	Preset string `env:"PRESET" default:"development"`
+	// FrameAncestors はレスポンスを iframe に埋め込めるオリジンか 'self'。空の場合はどこにも埋め込ませない
+	FrameAncestors []string `env:"FRAME_ANCESTORS"`
+}
+
+type Log struct {
+	// Level は debug, info, warn, error のいずれか
+	Level slog.Level `env:"LEVEL" default:"info"`
+}
+
+type Auth struct {
+	// TokenLifetime はログインで発行するアクセストークンの有効期間
+	TokenLifetime time.Duration `env:"TOKEN_LIFETIME" default:"1h"`
+}
+
+type DatabasePool struct {
+	MaxConns int `env:"MAX_CONNS" default:"10"`
+	// MinConns は使われていなくても保つコネクションの数
+	MinConns        int           `env:"MIN_CONNS" default:"0"`
+	MaxConnLifetime time.Duration `env:"MAX_CONN_LIFETIME" default:"1h"`
+	MaxConnIdleTime time.Duration `env:"MAX_CONN_IDLE_TIME" default:"30m"`
+	// HealthCheckPeriod は使われていないコネクションを確認する間隔
+	HealthCheckPeriod time.Duration `env:"HEALTH_CHECK_PERIOD" default:"1m"`
+}
+
 type Database struct {
-	Host string `envconfig:"HOST"`
-	Port int    `envconfig:"PORT"`
-	User string `envconfig:"USER"`
-	Pass string `envconfig:"PASS"`
-	Name string `envconfig:"NAME"`
+	Host string `env:"HOST" required:"true"`
+	Port int    `env:"PORT" default:"5432"`
+	User string `env:"USER" required:"true"`
+	Pass string `env:"PASS" secret:"true"`
+	Name string `env:"NAME" required:"true"`
+	// SSLMode は PostgreSQL の sslmode。disable, allow, prefer, require, verify-ca, verify-full のいずれか
+	SSLMode string       `env:"SSLMODE" default:"prefer"`
+	Pool    DatabasePool `env:"POOL"`
+	// AutoMigrate は起動時に未適用のマイグレーションを適用するかどうか
+	AutoMigrate bool `env:"AUTO_MIGRATE" default:"false"`
 }
 
+// URL は接続文字列を返す。コネクションプールの設定は pgxpool が読む pool_* のパラメータとして含める
 func (d *Database) URL() string {
-	return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=disable", d.User, d.Pass, d.Host, d.Port, d.Name)
+	q := url.Values{}
+	q.Set("sslmode", d.SSLMode)
+	q.Set("pool_max_conns", strconv.Itoa(d.Pool.MaxConns))
+	q.Set("pool_min_conns", strconv.Itoa(d.Pool.MinConns))
+	q.Set("pool_max_conn_lifetime", d.Pool.MaxConnLifetime.String())
+	q.Set("pool_max_conn_idle_time", d.Pool.MaxConnIdleTime.String())
+	q.Set("pool_health_check_period", d.Pool.HealthCheckPeriod.String())
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(d.User, d.Pass),
+		Host:     net.JoinHostPort(d.Host, strconv.Itoa(d.Port)),
+		Path:     "/" + d.Name,
+		RawQuery: q.Encode(),
+	}
+	return u.String()
 }
 
-type Config struct {
-	Database     Database `envconfig:"DATABASE"`
-	OtlpEndpoint string   `envconfig:"OTLP_ENDPOINT"`
-	JWTSecretKey string   `envconfig:"JWT_SECRET_KEY"`
+type Attachment struct {
+	Dir              string   `env:"DIR" default:"./data/attachments" required:"true"`
+	MaxSize          int64    `env:"MAX_SIZE" default:"10485760"` // 10 MiB
+	AllowedMIMETypes []string `env:"ALLOWED_MIME_TYPES" default:"image/png,image/jpeg,image/gif,text/plain"`
+}
+
+type Room struct {
+	// DeletionGracePeriod は削除したルームを復元できる期間
+	DeletionGracePeriod time.Duration `env:"DELETION_GRACE_PERIOD" default:"168h"`
+}
+
+type Webhook struct {
+	// PollInterval は配信キューを確認する間隔
+	PollInterval time.Duration `env:"POLL_INTERVAL" default:"5s"`
+	// BatchSize は 1 回に並行して送信する配信の数
+	BatchSize int `env:"BATCH_SIZE" default:"20"`
+	// Timeout は 1 回の送信にかける時間の上限
+	Timeout        time.Duration `env:"TIMEOUT" default:"10s"`
+	MaxAttempts    int           `env:"MAX_ATTEMPTS" default:"8"`
+	RetryBaseDelay time.Duration `env:"RETRY_BASE_DELAY" default:"30s"`
+	RetryMaxDelay  time.Duration `env:"RETRY_MAX_DELAY" default:"1h"`
+	// AllowPrivateNetworks はループバックやプライベートアドレスへの送信を許可する。スラッシュコマンドの呼び出しにも適用する。開発環境で使う
+	AllowPrivateNetworks bool `env:"ALLOW_PRIVATE_NETWORKS" default:"false"`
+}
+
+type ScheduledMessage struct {
+	// PollInterval は送信時刻を過ぎたメッセージを確認する間隔
+	PollInterval time.Duration `env:"POLL_INTERVAL" default:"5s"`
+	// BatchSize は 1 回に取り出すメッセージの数
+	BatchSize int `env:"BATCH_SIZE" default:"20"`
 }
 
-func Load() Config {
-	var c Config
-	if err := envconfig.Process("", &c); err != nil {
-		slog.Error("failed to load config from env", slog.Any("err", err))
-		panic(err) // 回復手段がないため panic
+type Retention struct {
+	// DefaultDays は保存期間を指定していないルームのメッセージを残す日数。0 の場合は削除しない
+	DefaultDays int `env:"DEFAULT_DAYS" default:"0"`
+	// PurgeInterval は保存期間を過ぎたメッセージを確認する間隔
+	PurgeInterval time.Duration `env:"PURGE_INTERVAL" default:"1h"`
+	// BatchSize は 1 つのトランザクションで削除するメッセージの数
+	BatchSize int `env:"BATCH_SIZE" default:"500"`
+}
+
+type Admin struct {
+	// Token は管理用 API の Bearer トークン。空の場合は管理用 API を公開しない
+	Token string `env:"TOKEN" secret:"true"`
+}
+
+type SlashCommand struct {
+	// Timeout は Bot のコマンドの呼び出しを待つ時間の上限
+	Timeout time.Duration `env:"TIMEOUT" default:"5s"`
+}
+
+type Health struct {
+	// ReadyTimeout は /readyz でデータベースの応答を待つ時間の上限
+	ReadyTimeout time.Duration `env:"READY_TIMEOUT" default:"2s"`
+	// DrainDelay は終了処理を始めてから新しいリクエストの受け付けを止めるまでの時間。
+	// この間 /readyz は 503 を返し、ロードバランサーが振り分け先から外すのを待つ
+	DrainDelay time.Duration `env:"DRAIN_DELAY" default:"5s"`
+}
+
+type Metrics struct {
+	// PrometheusAddr は Prometheus 形式の /metrics を公開するアドレス。空の場合は公開せず、OTLP でのみ送る
+	PrometheusAddr string `env:"PROMETHEUS_ADDR"`
+}
+
+// Rate は "30/1m" のように書く、Per の間に Requests 回までの上限。"0" の場合は制限しない
+type Rate struct {
+	Requests int
+	Per      time.Duration
+}
+
+func (r Rate) MarshalText() ([]byte, error) {
+	if r.Requests == 0 {
+		return []byte("0"), nil
 	}
-	return c
+	per := r.Per.String()
+	switch {
+	case r.Per%time.Hour == 0:
+		per = strconv.FormatInt(int64(r.Per/time.Hour), 10) + "h"
+	case r.Per%time.Minute == 0:
+		per = strconv.FormatInt(int64(r.Per/time.Minute), 10) + "m"
+	}
+	return []byte(strconv.Itoa(r.Requests) + "/" + per), nil
+}
+
+func (r *Rate) UnmarshalText(b []byte) error {
+	s := strings.TrimSpace(string(b))
+	if s == "" || s == "0" {
+		*r = Rate{}
+		return nil
+	}
+	requests, per, ok := strings.Cut(s, "/")
+	if !ok {
+		return errors.New("must be requests/duration such as 30/1m")
+	}
+	n, err := strconv.Atoi(requests)
+	if err != nil || n < 0 {
+		return errors.New("requests must be a non-negative integer")
+	}
+	d, err := time.ParseDuration(per)
+	if err != nil || d <= 0 {
+		return errors.New("duration must be positive such as 1m")
+	}
+	*r = Rate{Requests: n, Per: d}
+	return nil
+}
+
+type RateLimit struct {
+	// Store は memory か postgres。複数のインスタンスで動かす場合は postgres にしてインスタンス間で上限を共有する
+	Store string `env:"STORE" default:"memory"`
+	// Signup はアカウント作成の IP アドレスごとの上限
+	Signup Rate `env:"SIGNUP" default:"5/1h"`
+	// Login はログインの IP アドレスごとの上限
+	Login Rate `env:"LOGIN" default:"10/1m"`
+	// Messages はメッセージ投稿のアカウントごとの上限
+	Messages Rate `env:"MESSAGES" default:"30/1m"`
+}
+
+type Config struct {
+	Server           Server           `env:"SERVER"`
+	CORS             CORS             `env:"CORS"`
+	SecurityHeaders  SecurityHeaders  `env:"SECURITY_HEADERS"`
+	Log              Log              `env:"LOG"`
+	Auth             Auth             `env:"AUTH"`
+	RateLimit        RateLimit        `env:"RATE_LIMIT"`
+	Database         Database         `env:"DATABASE"`
+	Attachment       Attachment       `env:"ATTACHMENT"`
+	Room             Room             `env:"ROOM"`
+	Webhook          Webhook          `env:"WEBHOOK"`
+	SlashCommand     SlashCommand     `env:"SLASH_COMMAND"`
+	ScheduledMessage ScheduledMessage `env:"SCHEDULED_MESSAGE"`
+	Retention        Retention        `env:"RETENTION"`
+	Admin            Admin            `env:"ADMIN"`
+	Health           Health           `env:"HEALTH"`
+	Metrics          Metrics          `env:"METRICS"`
+	OtlpEndpoint     string           `env:"OTLP_ENDPOINT"`
+	// JWTSecretKey はアクセストークンの署名に使う鍵。HS256 の鍵として 32 バイト以上にする
+	JWTSecretKey string `env:"JWT_SECRET_KEY" required:"true" secret:"true"`
+}
+
+// Load は src から設定を読み込み、検証する
+//
+// 読み込みと検証の問題はすべてまとめて返す
+func Load(src Sources) (Config, error) {
+	c, _, errs := load(src)
+	return c, check(&c, errs)
 }
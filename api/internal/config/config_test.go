@@ -0,0 +1,365 @@
+package config_test
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quietsato/toy-small-chat/api/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+// requiredEnv は必須の設定だけを与える環境変数
+var requiredEnv = map[string]string{
+	"DATABASE_HOST":  "db",
+	"DATABASE_USER":  "postgres",
+	"DATABASE_PASS":  "p@ss/word",
+	"DATABASE_NAME":  "toy_small_chat",
+	"JWT_SECRET_KEY": strings.Repeat("k", 32),
+}
+
+func lookupEnv(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+}
+
+func withEnv(overrides map[string]string) func(string) (string, bool) {
+	env := map[string]string{}
+	for k, v := range requiredEnv {
+		env[k] = v
+	}
+	for k, v := range overrides {
+		env[k] = v
+	}
+	return lookupEnv(env)
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	t.Run("指定しなかった項目には既定値を使う", func(t *testing.T) {
+		t.Parallel()
+
+		c, err := config.Load(config.Sources{LookupEnv: withEnv(nil)})
+
+		require.NoError(t, err)
+		require.Equal(t, ":8080", c.Server.Addr)
+		require.Equal(t, 60*time.Second, c.Server.RequestTimeout)
+		require.Equal(t, slog.LevelInfo, c.Log.Level)
+		require.Equal(t, time.Hour, c.Auth.TokenLifetime)
+		require.Equal(t, 5432, c.Database.Port)
+		require.Equal(t, "prefer", c.Database.SSLMode)
+		require.Equal(t, 10, c.Database.Pool.MaxConns)
+		require.Equal(t, []string{"http://localhost:18080", "http://localhost:5173"}, c.CORS.AllowedOrigins)
+		require.Equal(t, []string{"image/png", "image/jpeg", "image/gif", "text/plain"}, c.Attachment.AllowedMIMETypes)
+	})
+
+	t.Run("既定値、設定ファイル、環境変数、フラグの順に後のものを優先する", func(t *testing.T) {
+		t.Parallel()
+
+		file := writeFile(t, "config.yaml", `
+server:
+  addr: ":9000"
+  request_timeout: 30s
+log:
+  level: warn
+database:
+  pool:
+    max_conns: 20
+    min_conns: 2
+cors:
+  allowed_origins:
+    - https://chat.example.com
+    - https://admin.example.com
+`)
+		c, err := config.Load(config.Sources{
+			File:      file,
+			LookupEnv: withEnv(map[string]string{"SERVER_ADDR": ":9001", "DATABASE_POOL_MAX_CONNS": "30"}),
+			Flags:     map[string]string{"SERVER_ADDR": ":9002"},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, ":9002", c.Server.Addr)
+		require.Equal(t, 30*time.Second, c.Server.RequestTimeout)
+		require.Equal(t, slog.LevelWarn, c.Log.Level)
+		require.Equal(t, 30, c.Database.Pool.MaxConns)
+		require.Equal(t, 2, c.Database.Pool.MinConns)
+		require.Equal(t, []string{"https://chat.example.com", "https://admin.example.com"}, c.CORS.AllowedOrigins)
+	})
+
+	t.Run("TOML の設定ファイルを読み込む", func(t *testing.T) {
+		t.Parallel()
+
+		file := writeFile(t, "config.toml", `
+[server]
+addr = ":9000"
+
+[database]
+sslmode = "require"
+
+[database.pool]
+max_conns = 5
+
+[cors]
+allowed_origins = ["https://chat.example.com"]
+`)
+		c, err := config.Load(config.Sources{File: file, LookupEnv: withEnv(nil)})
+
+		require.NoError(t, err)
+		require.Equal(t, ":9000", c.Server.Addr)
+		require.Equal(t, "require", c.Database.SSLMode)
+		require.Equal(t, 5, c.Database.Pool.MaxConns)
+		require.Equal(t, []string{"https://chat.example.com"}, c.CORS.AllowedOrigins)
+	})
+
+	t.Run("問題をすべてまとめて返す", func(t *testing.T) {
+		t.Parallel()
+
+		file := writeFile(t, "config.yaml", `
+server:
+  adr: ":9000"
+`)
+		_, err := config.Load(config.Sources{
+			File: file,
+			LookupEnv: lookupEnv(map[string]string{
+				"DATABASE_HOST":           "db",
+				"DATABASE_USER":           "postgres",
+				"DATABASE_NAME":           "toy_small_chat",
+				"JWT_SECRET_KEY":          "secretKey",
+				"DATABASE_PORT":           "not-a-number",
+				"DATABASE_SSLMODE":        "off",
+				"DATABASE_POOL_MIN_CONNS": "20",
+				"WEBHOOK_RETRY_MAX_DELAY": "1s",
+				"CORS_ALLOWED_ORIGINS":    "chat.example.com",
+				"SERVER_TRUSTED_PROXIES":  "10.0.0.0/8,10.0.0.1",
+				"LOG_LEVEL":               "verbose",
+			}),
+		})
+
+		require.Error(t, err)
+		for _, want := range []string{
+			`unknown key "server.adr"`,
+			`DATABASE_PORT: invalid value "not-a-number" from env`,
+			"DATABASE_SSLMODE: must be one of",
+			"DATABASE_POOL_MIN_CONNS: must not exceed DATABASE_POOL_MAX_CONNS",
+			"WEBHOOK_RETRY_MAX_DELAY: must not be shorter than WEBHOOK_RETRY_BASE_DELAY",
+			`CORS_ALLOWED_ORIGINS: "chat.example.com" must be`,
+			`SERVER_TRUSTED_PROXIES: "10.0.0.1" must be a CIDR`,
+			`LOG_LEVEL: invalid value "verbose"`,
+			"JWT_SECRET_KEY: must be at least 32 bytes",
+		} {
+			require.Contains(t, err.Error(), want)
+		}
+		// 読み込めなかった項目の範囲の問題は重ねて報告しない
+		require.NotContains(t, err.Error(), "DATABASE_PORT: must be between")
+	})
+
+	t.Run("CORS とセキュリティヘッダーの設定を検証する", func(t *testing.T) {
+		t.Parallel()
+
+		c, err := config.Load(config.Sources{LookupEnv: withEnv(map[string]string{
+			"CORS_ALLOWED_ORIGINS":             "https://chat.example.com,https://*.preview.example.com",
+			"CORS_ALLOW_CREDENTIALS":           "true",
+			"SECURITY_HEADERS_PRESET":          "production",
+			"SECURITY_HEADERS_FRAME_ANCESTORS": "'self',https://admin.example.com",
+		})})
+		require.NoError(t, err)
+		require.Equal(t, []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"}, c.CORS.AllowedMethods)
+		require.Equal(t, []string{"'self'", "https://admin.example.com"}, c.SecurityHeaders.FrameAncestors)
+
+		_, err = config.Load(config.Sources{LookupEnv: withEnv(map[string]string{
+			"CORS_ALLOWED_ORIGINS":             "*,https://*.*.example.com,https://chat.*.example.com",
+			"CORS_ALLOW_CREDENTIALS":           "true",
+			"CORS_ALLOWED_METHODS":             "GET,patch,TRACE",
+			"SECURITY_HEADERS_PRESET":          "staging",
+			"SECURITY_HEADERS_FRAME_ANCESTORS": "self",
+		})})
+		for _, want := range []string{
+			`CORS_ALLOWED_ORIGINS: must not contain "*" when CORS_ALLOW_CREDENTIALS is true`,
+			`CORS_ALLOWED_ORIGINS: "https://*.*.example.com" must be`,
+			`CORS_ALLOWED_ORIGINS: "https://chat.*.example.com" must be`,
+			`CORS_ALLOWED_METHODS: must be some of [GET HEAD POST PUT PATCH DELETE], got "patch"`,
+			`CORS_ALLOWED_METHODS: must be some of [GET HEAD POST PUT PATCH DELETE], got "TRACE"`,
+			`SECURITY_HEADERS_PRESET: must be one of [development production], got "staging"`,
+			`SECURITY_HEADERS_FRAME_ANCESTORS: "self" must be`,
+		} {
+			require.ErrorContains(t, err, want)
+		}
+	})
+
+	t.Run("レート制限の上限を読み込み、検証する", func(t *testing.T) {
+		t.Parallel()
+
+		c, err := config.Load(config.Sources{LookupEnv: withEnv(map[string]string{
+			"RATE_LIMIT_STORE":    "postgres",
+			"RATE_LIMIT_LOGIN":    "20/30s",
+			"RATE_LIMIT_MESSAGES": "0",
+		})})
+		require.NoError(t, err)
+		require.Equal(t, "postgres", c.RateLimit.Store)
+		require.Equal(t, config.Rate{Requests: 5, Per: time.Hour}, c.RateLimit.Signup)
+		require.Equal(t, config.Rate{Requests: 20, Per: 30 * time.Second}, c.RateLimit.Login)
+		require.Equal(t, config.Rate{}, c.RateLimit.Messages)
+
+		_, err = config.Load(config.Sources{LookupEnv: withEnv(map[string]string{
+			"RATE_LIMIT_STORE":    "redis",
+			"RATE_LIMIT_SIGNUP":   "5",
+			"RATE_LIMIT_LOGIN":    "-1/1m",
+			"RATE_LIMIT_MESSAGES": "30/0s",
+		})})
+		for _, want := range []string{
+			`RATE_LIMIT_STORE: must be one of [memory postgres], got "redis"`,
+			`RATE_LIMIT_SIGNUP: invalid value "5" from env: must be requests/duration such as 30/1m`,
+			`RATE_LIMIT_LOGIN: invalid value "-1/1m" from env: requests must be a non-negative integer`,
+			`RATE_LIMIT_MESSAGES: invalid value "30/0s" from env: duration must be positive such as 1m`,
+		} {
+			require.ErrorContains(t, err, want)
+		}
+	})
+
+	t.Run("必須の項目が空の場合はエラー", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := config.Load(config.Sources{LookupEnv: withEnv(map[string]string{"JWT_SECRET_KEY": "", "DATABASE_HOST": ""})})
+
+		require.ErrorContains(t, err, "JWT_SECRET_KEY: is required")
+		require.ErrorContains(t, err, "DATABASE_HOST: is required")
+	})
+
+	t.Run("存在しない項目をフラグで指定した場合はエラー", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := config.Load(config.Sources{LookupEnv: withEnv(nil), Flags: map[string]string{"SERVER_ADR": ":9000"}})
+
+		require.ErrorContains(t, err, `unknown config key "SERVER_ADR"`)
+	})
+
+	t.Run("対応していない拡張子の設定ファイルはエラー", func(t *testing.T) {
+		t.Parallel()
+
+		file := writeFile(t, "config.json", `{}`)
+		_, err := config.Load(config.Sources{File: file, LookupEnv: withEnv(nil)})
+
+		require.ErrorContains(t, err, `unsupported config file extension ".json"`)
+	})
+}
+
+func TestDatabase_URL(t *testing.T) {
+	t.Parallel()
+
+	t.Run("パスワードをエスケープし、sslmode とコネクションプールの設定を含める", func(t *testing.T) {
+		t.Parallel()
+
+		c, err := config.Load(config.Sources{LookupEnv: withEnv(map[string]string{"DATABASE_SSLMODE": "verify-full"})})
+		require.NoError(t, err)
+
+		require.Equal(t,
+			"postgres://postgres:p%40ss%2Fword@db:5432/toy_small_chat?pool_health_check_period=1m0s&pool_max_conn_idle_time=30m0s&pool_max_conn_lifetime=1h0m0s&pool_max_conns=10&pool_min_conns=0&sslmode=verify-full",
+			c.Database.URL(),
+		)
+	})
+}
+
+func TestPrint(t *testing.T) {
+	t.Parallel()
+
+	t.Run("秘密の値を伏せ、読み込み元を付けて設定ファイルと同じ形で書き出す", func(t *testing.T) {
+		t.Parallel()
+
+		file := writeFile(t, "config.yaml", "log:\n  level: debug\n")
+		var buf bytes.Buffer
+		err := config.Print(&buf, config.Sources{
+			File:      file,
+			LookupEnv: withEnv(map[string]string{"ADMIN_TOKEN": "admin-token"}),
+			Flags:     map[string]string{"SERVER_ADDR": ":9000"},
+		})
+
+		require.NoError(t, err)
+		out := buf.String()
+		require.Contains(t, out, "server:\n  addr: :9000 # flag\n")
+		require.Contains(t, out, "log:\n  level: debug # file\n")
+		require.Contains(t, out, "  request_timeout: 1m0s # default\n")
+		require.Contains(t, out, "  pool:\n    max_conns: 10 # default\n")
+		require.Contains(t, out, "  allowed_origins: ['http://localhost:18080', 'http://localhost:5173'] # default\n")
+		require.Contains(t, out, "jwt_secret_key: REDACTED # env\n")
+		require.Contains(t, out, "  token: REDACTED # env\n")
+		require.Contains(t, out, "  pass: REDACTED # env\n")
+		require.NotContains(t, out, "admin-token")
+		require.NotContains(t, out, "p@ss/word")
+		require.NotContains(t, out, requiredEnv["JWT_SECRET_KEY"])
+	})
+
+	t.Run("書き出した設定は設定ファイルとしてそのまま読み込める", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		require.NoError(t, config.Print(&buf, config.Sources{LookupEnv: withEnv(map[string]string{"LOG_LEVEL": "error"})}))
+		file := writeFile(t, "config.yaml", buf.String())
+
+		c, err := config.Load(config.Sources{File: file, LookupEnv: withEnv(nil)})
+
+		require.NoError(t, err)
+		require.Equal(t, slog.LevelError, c.Log.Level)
+		require.Equal(t, 168*time.Hour, c.Room.DeletionGracePeriod)
+	})
+
+	t.Run("設定に問題がある場合も書き出したうえでエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		err := config.Print(&buf, config.Sources{LookupEnv: withEnv(map[string]string{"JWT_SECRET_KEY": ""})})
+
+		require.ErrorContains(t, err, "JWT_SECRET_KEY: is required")
+		require.Contains(t, buf.String(), "jwt_secret_key: \"\" # env\n")
+	})
+}
+
+func TestParseArgs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("コマンドの前のフラグを読み、残りの引数を返す", func(t *testing.T) {
+		t.Parallel()
+
+		src, args, err := config.ParseArgs(
+			[]string{"-config", "api.yaml", "-set", "webhook_batch_size=5", "-set", "LOG_LEVEL=debug", "-addr", ":9000", "migrate", "up"},
+			lookupEnv(map[string]string{"CONFIG_FILE": "ignored.yaml"}),
+		)
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"migrate", "up"}, args)
+		require.Equal(t, "api.yaml", src.File)
+		require.Equal(t, map[string]string{"WEBHOOK_BATCH_SIZE": "5", "LOG_LEVEL": "debug", "SERVER_ADDR": ":9000"}, src.Flags)
+	})
+
+	t.Run("-config がない場合は CONFIG_FILE の設定ファイルを使う", func(t *testing.T) {
+		t.Parallel()
+
+		src, args, err := config.ParseArgs(nil, lookupEnv(map[string]string{"CONFIG_FILE": "/etc/api.toml"}))
+
+		require.NoError(t, err)
+		require.Empty(t, args)
+		require.Equal(t, "/etc/api.toml", src.File)
+	})
+
+	t.Run("-set の形式が不正な場合はエラー", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := config.ParseArgs([]string{"-set", "LOG_LEVEL"}, lookupEnv(nil))
+
+		require.ErrorContains(t, err, "must be KEY=VALUE")
+	})
+}
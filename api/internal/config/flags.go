@@ -0,0 +1,62 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// FlagUsage はコマンドの前に置けるフラグの説明
+const FlagUsage = `flags:
+  -config FILE        YAML (.yaml, .yml) or TOML (.toml) config file (default $CONFIG_FILE)
+  -set KEY=VALUE      override a config value; KEY is the environment variable name (repeatable)
+  -addr ADDR          same as -set SERVER_ADDR=ADDR
+  -log-level LEVEL    same as -set LOG_LEVEL=LEVEL
+`
+
+// setFlag は -set KEY=VALUE を繰り返し受け取る
+type setFlag map[string]string
+
+func (f setFlag) String() string {
+	return fmt.Sprint(map[string]string(f))
+}
+
+func (f setFlag) Set(s string) error {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok || k == "" {
+		return fmt.Errorf("must be KEY=VALUE, got %q", s)
+	}
+	f[strings.ToUpper(k)] = v
+	return nil
+}
+
+// ParseArgs はコマンドの前に置いたフラグを読み、設定の読み込み元と残りの引数を返す
+//
+// lookupEnv は CONFIG_FILE と、返す Sources の LookupEnv に使う。nil の場合は os.LookupEnv を使う
+func ParseArgs(args []string, lookupEnv func(key string) (string, bool)) (Sources, []string, error) {
+	src := Sources{LookupEnv: lookupEnv, Flags: map[string]string{}}
+
+	fs := flag.NewFlagSet("api", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&src.File, "config", "", "")
+	fs.Var(setFlag(src.Flags), "set", "")
+	addr := fs.String("addr", "", "")
+	logLevel := fs.String("log-level", "", "")
+	if err := fs.Parse(args); err != nil {
+		return Sources{}, nil, err
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "addr":
+			src.Flags["SERVER_ADDR"] = *addr
+		case "log-level":
+			src.Flags["LOG_LEVEL"] = *logLevel
+		}
+	})
+	if src.File == "" {
+		src.File, _ = src.lookupEnv()("CONFIG_FILE")
+	}
+	return src, fs.Args(), nil
+}
@@ -0,0 +1,276 @@
+package config
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Sources は設定の読み込み元。既定値、File、LookupEnv、Flags の順に、後のものほど優先する
+type Sources struct {
+	// File は YAML (.yaml, .yml) か TOML (.toml) の設定ファイルのパス。空の場合は読まない
+	File string
+	// LookupEnv は環境変数を引く関数。nil の場合は os.LookupEnv を使う
+	LookupEnv func(key string) (string, bool)
+	// Flags はコマンドラインで指定した値。キーは環境変数の名前
+	Flags map[string]string
+}
+
+func (s Sources) lookupEnv() func(key string) (string, bool) {
+	if s.LookupEnv == nil {
+		return os.LookupEnv
+	}
+	return s.LookupEnv
+}
+
+// Origin は設定値をどこから読み込んだか
+type Origin string
+
+const (
+	OriginDefault Origin = "default"
+	OriginFile    Origin = "file"
+	OriginEnv     Origin = "env"
+	OriginFlag    Origin = "flag"
+)
+
+// field は設定項目 1 つ
+type field struct {
+	// key は環境変数の名前
+	key string
+	// path は設定ファイルでのキー
+	path  string
+	value reflect.Value
+	tag   reflect.StructTag
+}
+
+var (
+	durationType        = reflect.TypeFor[time.Duration]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// fields は v の設定項目を宣言順に返す
+func fields(v reflect.Value, key, path string) []field {
+	var fs []field
+	for i := range v.NumField() {
+		sf := v.Type().Field(i)
+		name := sf.Tag.Get("env")
+		if name == "" {
+			continue
+		}
+		k, p := name, strings.ToLower(name)
+		if key != "" {
+			k, p = key+"_"+k, path+"."+p
+		}
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Struct && !reflect.PointerTo(fv.Type()).Implements(textUnmarshalerType) {
+			fs = append(fs, fields(fv, k, p)...)
+			continue
+		}
+		fs = append(fs, field{key: k, path: p, value: fv, tag: sf.Tag})
+	}
+	return fs
+}
+
+// keyError は key の設定値の問題
+type keyError struct {
+	key string
+	err error
+}
+
+func (e *keyError) Error() string {
+	return e.key + ": " + e.err.Error()
+}
+
+func (e *keyError) Unwrap() error {
+	return e.err
+}
+
+// load は src から設定を読み込み、各設定値の読み込み元を返す。検証はしない
+//
+// 値の形式が不正な項目はゼロ値のままにし、設定ファイルを読めない場合もほかの読み込み元から読み込んで、問題をすべて返す
+func load(src Sources) (Config, map[string]Origin, []error) {
+	lookupEnv := src.lookupEnv()
+
+	var c Config
+	fs := fields(reflect.ValueOf(&c).Elem(), "", "")
+	var errs []error
+
+	file := map[string]string{}
+	if src.File != "" {
+		var err error
+		if file, err = readFile(src.File); err != nil {
+			errs = append(errs, err)
+		}
+		known := map[string]bool{}
+		for _, f := range fs {
+			known[f.path] = true
+		}
+		for _, p := range slices.Sorted(maps.Keys(file)) {
+			if !known[p] {
+				errs = append(errs, fmt.Errorf("%s: unknown key %q", src.File, p))
+			}
+		}
+	}
+	for _, k := range slices.Sorted(maps.Keys(src.Flags)) {
+		if !slices.ContainsFunc(fs, func(f field) bool { return f.key == k }) {
+			errs = append(errs, fmt.Errorf("unknown config key %q in flags", k))
+		}
+	}
+
+	origins := make(map[string]Origin, len(fs))
+	for _, f := range fs {
+		s, origin := f.tag.Get("default"), OriginDefault
+		if v, ok := file[f.path]; ok {
+			s, origin = v, OriginFile
+		}
+		if v, ok := lookupEnv(f.key); ok {
+			s, origin = v, OriginEnv
+		}
+		if v, ok := src.Flags[f.key]; ok {
+			s, origin = v, OriginFlag
+		}
+		origins[f.key] = origin
+
+		if f.tag.Get("required") == "true" && strings.TrimSpace(s) == "" {
+			errs = append(errs, &keyError{f.key, errors.New("is required")})
+			continue
+		}
+		if err := setValue(f.value, s); err != nil {
+			errs = append(errs, &keyError{f.key, fmt.Errorf("invalid value %q from %s: %w", s, origin, err)})
+		}
+	}
+	return c, origins, errs
+}
+
+// check は読み込みの問題に、検証の問題を加えてまとめる。読み込めなかった項目の検証の問題は重ねて報告しない
+func check(c *Config, errs []error) error {
+	failed := map[string]bool{}
+	for _, err := range errs {
+		if ke, ok := err.(*keyError); ok {
+			failed[ke.key] = true
+		}
+	}
+	if err := c.Validate(); err != nil {
+		for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+			if ke, ok := err.(*keyError); ok && failed[ke.key] {
+				continue
+			}
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid config:\n%w", errors.Join(errs...))
+}
+
+// setValue は文字列 s を v の型に変換して設定する。空文字列はゼロ値として扱う
+func setValue(v reflect.Value, s string) error {
+	if v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	if s == "" {
+		v.SetZero()
+		return nil
+	}
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", v.Type())
+		}
+		var items []string
+		for item := range strings.SplitSeq(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// readFile は設定ファイルを読み込み、キーを . でつないだパスごとの値を返す
+//
+// リストの値は環境変数と同じく , でつなぐ
+func readFile(path string) (map[string]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	doc := map[string]any{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &doc)
+	case ".toml":
+		err = toml.Unmarshal(b, &doc)
+	default:
+		return nil, fmt.Errorf("unsupported config file extension %q: use .yaml, .yml or .toml", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	values := map[string]string{}
+	flatten(values, "", doc)
+	return values, nil
+}
+
+func flatten(values map[string]string, prefix string, v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			p := strings.ToLower(k)
+			if prefix != "" {
+				p = prefix + "." + p
+			}
+			flatten(values, p, child)
+		}
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, fmt.Sprint(item))
+		}
+		values[prefix] = strings.Join(items, ",")
+	case nil:
+		values[prefix] = ""
+	default:
+		values[prefix] = fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redacted は config print で伏せた秘密の値の代わりに出す文字列
+const redacted = "REDACTED"
+
+// Print は src から読み込んだ設定を、設定ファイルと同じ形の YAML で w に書き出す
+//
+// 各値には読み込み元をコメントで付け、secret の値は伏せる。
+// 設定に問題がある場合も書き出したうえで、Load と同じ問題を返す
+func Print(w io.Writer, src Sources) error {
+	c, origins, errs := load(src)
+
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	sections := map[string]*yaml.Node{"": root}
+	for _, f := range fields(reflect.ValueOf(&c).Elem(), "", "") {
+		parent, name := "", f.path
+		if i := strings.LastIndex(f.path, "."); i >= 0 {
+			parent, name = f.path[:i], f.path[i+1:]
+		}
+		section := sectionNode(sections, parent)
+
+		value, err := valueNode(f)
+		if err != nil {
+			return err
+		}
+		value.LineComment = string(origins[f.key])
+		section.Content = append(section.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, value)
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(root); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	return check(&c, errs)
+}
+
+// sectionNode は path の節のマッピングを返す。まだない場合は親に加える
+func sectionNode(sections map[string]*yaml.Node, path string) *yaml.Node {
+	if n, ok := sections[path]; ok {
+		return n
+	}
+	parent, name := "", path
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		parent, name = path[:i], path[i+1:]
+	}
+	n := &yaml.Node{Kind: yaml.MappingNode}
+	p := sectionNode(sections, parent)
+	p.Content = append(p.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, n)
+	sections[path] = n
+	return n
+}
+
+// valueNode は設定ファイルにそのまま書ける形で f の値を表す
+func valueNode(f field) (*yaml.Node, error) {
+	str := func(s string) *yaml.Node {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s}
+	}
+
+	if f.tag.Get("secret") == "true" {
+		if f.value.IsZero() {
+			return str(""), nil
+		}
+		return str(redacted), nil
+	}
+	switch v := f.value.Interface().(type) {
+	case time.Duration:
+		return str(v.String()), nil
+	case encoding.TextMarshaler:
+		b, err := v.MarshalText()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", f.key, err)
+		}
+		return str(strings.ToLower(string(b))), nil
+	case []string:
+		n := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for _, item := range v {
+			n.Content = append(n.Content, str(item))
+		}
+		return n, nil
+	}
+
+	n := &yaml.Node{}
+	if err := n.Encode(f.value.Interface()); err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", f.key, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// minJWTSecretKeyLength は HS256 の鍵として必要なバイト数
+const minJWTSecretKeyLength = 32
+
+var (
+	sslModes               = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+	corsMethods            = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"}
+	securityHeadersPresets = []string{"development", "production"}
+	rateLimitStores        = []string{"memory", "postgres"}
+)
+
+// problems は検証で見つかった問題を集める
+type problems []error
+
+func (p *problems) add(key, format string, args ...any) {
+	*p = append(*p, &keyError{key, fmt.Errorf(format, args...)})
+}
+
+func (p *problems) positive(key string, d time.Duration) {
+	if d <= 0 {
+		p.add(key, "must be positive, got %s", d)
+	}
+}
+
+func (p *problems) nonNegative(key string, d time.Duration) {
+	if d < 0 {
+		p.add(key, "must not be negative, got %s", d)
+	}
+}
+
+func (p *problems) atLeast(key string, n, min int64) {
+	if n < min {
+		p.add(key, "must be at least %d, got %d", min, n)
+	}
+}
+
+func (p *problems) addr(key, addr string) {
+	if addr == "" {
+		return
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		p.add(key, "must be host:port, got %q", addr)
+	}
+}
+
+// origin は https://chat.example.com のようなオリジンか、https://*.example.com のようにサブドメインを * で表したものかを確認する
+func (p *problems) origin(key, origin string) {
+	u, err := url.Parse(origin)
+	ok := err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && (u.Path == "" || u.Path == "/") && u.RawQuery == "" && u.User == nil
+	if ok && strings.Contains(u.Host, "*") {
+		ok = strings.HasPrefix(u.Host, "*.") && !strings.Contains(u.Host[2:], "*")
+	}
+	if !ok {
+		p.add(key, "%q must be an origin such as https://chat.example.com or https://*.example.com", origin)
+	}
+}
+
+// Validate は設定値の組み合わせと範囲を検証し、見つかった問題をすべてまとめて返す
+func (c *Config) Validate() error {
+	var p problems
+
+	p.addr("SERVER_ADDR", c.Server.Addr)
+	p.nonNegative("SERVER_READ_HEADER_TIMEOUT", c.Server.ReadHeaderTimeout)
+	p.nonNegative("SERVER_READ_TIMEOUT", c.Server.ReadTimeout)
+	p.nonNegative("SERVER_WRITE_TIMEOUT", c.Server.WriteTimeout)
+	p.nonNegative("SERVER_IDLE_TIMEOUT", c.Server.IdleTimeout)
+	p.positive("SERVER_REQUEST_TIMEOUT", c.Server.RequestTimeout)
+	p.positive("SERVER_SHUTDOWN_TIMEOUT", c.Server.ShutdownTimeout)
+	for _, proxy := range c.Server.TrustedProxies {
+		if _, err := netip.ParsePrefix(proxy); err != nil {
+			p.add("SERVER_TRUSTED_PROXIES", "%q must be a CIDR such as 10.0.0.0/8", proxy)
+		}
+	}
+
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" {
+			if c.CORS.AllowCredentials {
+				p.add("CORS_ALLOWED_ORIGINS", "must not contain \"*\" when CORS_ALLOW_CREDENTIALS is true")
+			}
+			continue
+		}
+		p.origin("CORS_ALLOWED_ORIGINS", origin)
+	}
+	for _, method := range c.CORS.AllowedMethods {
+		if !slices.Contains(corsMethods, method) {
+			p.add("CORS_ALLOWED_METHODS", "must be some of %v, got %q", corsMethods, method)
+		}
+	}
+	p.nonNegative("CORS_MAX_AGE", c.CORS.MaxAge)
+
+	if !slices.Contains(securityHeadersPresets, c.SecurityHeaders.Preset) {
+		p.add("SECURITY_HEADERS_PRESET", "must be one of %v, got %q", securityHeadersPresets, c.SecurityHeaders.Preset)
+	}
+	for _, origin := range c.SecurityHeaders.FrameAncestors {
+		if origin != "'self'" {
+			p.origin("SECURITY_HEADERS_FRAME_ANCESTORS", origin)
+		}
+	}
+
+	p.positive("AUTH_TOKEN_LIFETIME", c.Auth.TokenLifetime)
+	if c.JWTSecretKey != "" && len(c.JWTSecretKey) < minJWTSecretKeyLength {
+		p.add("JWT_SECRET_KEY", "must be at least %d bytes, got %d", minJWTSecretKeyLength, len(c.JWTSecretKey))
+	}
+
+	if !slices.Contains(rateLimitStores, c.RateLimit.Store) {
+		p.add("RATE_LIMIT_STORE", "must be one of %v, got %q", rateLimitStores, c.RateLimit.Store)
+	}
+
+	if c.Database.Port < 1 || c.Database.Port > math.MaxUint16 {
+		p.add("DATABASE_PORT", "must be between 1 and %d, got %d", math.MaxUint16, c.Database.Port)
+	}
+	if !slices.Contains(sslModes, c.Database.SSLMode) {
+		p.add("DATABASE_SSLMODE", "must be one of %v, got %q", sslModes, c.Database.SSLMode)
+	}
+	p.atLeast("DATABASE_POOL_MAX_CONNS", int64(c.Database.Pool.MaxConns), 1)
+	if c.Database.Pool.MaxConns > math.MaxInt32 {
+		p.add("DATABASE_POOL_MAX_CONNS", "must be at most %d, got %d", math.MaxInt32, c.Database.Pool.MaxConns)
+	}
+	p.atLeast("DATABASE_POOL_MIN_CONNS", int64(c.Database.Pool.MinConns), 0)
+	if c.Database.Pool.MinConns > c.Database.Pool.MaxConns {
+		p.add("DATABASE_POOL_MIN_CONNS", "must not exceed DATABASE_POOL_MAX_CONNS (%d), got %d", c.Database.Pool.MaxConns, c.Database.Pool.MinConns)
+	}
+	p.positive("DATABASE_POOL_MAX_CONN_LIFETIME", c.Database.Pool.MaxConnLifetime)
+	p.positive("DATABASE_POOL_MAX_CONN_IDLE_TIME", c.Database.Pool.MaxConnIdleTime)
+	p.positive("DATABASE_POOL_HEALTH_CHECK_PERIOD", c.Database.Pool.HealthCheckPeriod)
+
+	p.atLeast("ATTACHMENT_MAX_SIZE", c.Attachment.MaxSize, 1)
+	p.positive("ROOM_DELETION_GRACE_PERIOD", c.Room.DeletionGracePeriod)
+
+	p.positive("WEBHOOK_POLL_INTERVAL", c.Webhook.PollInterval)
+	p.atLeast("WEBHOOK_BATCH_SIZE", int64(c.Webhook.BatchSize), 1)
+	p.positive("WEBHOOK_TIMEOUT", c.Webhook.Timeout)
+	p.atLeast("WEBHOOK_MAX_ATTEMPTS", int64(c.Webhook.MaxAttempts), 1)
+	p.positive("WEBHOOK_RETRY_BASE_DELAY", c.Webhook.RetryBaseDelay)
+	if c.Webhook.RetryMaxDelay < c.Webhook.RetryBaseDelay {
+		p.add("WEBHOOK_RETRY_MAX_DELAY", "must not be shorter than WEBHOOK_RETRY_BASE_DELAY (%s), got %s", c.Webhook.RetryBaseDelay, c.Webhook.RetryMaxDelay)
+	}
+
+	p.positive("SLASH_COMMAND_TIMEOUT", c.SlashCommand.Timeout)
+	p.positive("SCHEDULED_MESSAGE_POLL_INTERVAL", c.ScheduledMessage.PollInterval)
+	p.atLeast("SCHEDULED_MESSAGE_BATCH_SIZE", int64(c.ScheduledMessage.BatchSize), 1)
+
+	p.atLeast("RETENTION_DEFAULT_DAYS", int64(c.Retention.DefaultDays), 0)
+	p.positive("RETENTION_PURGE_INTERVAL", c.Retention.PurgeInterval)
+	p.atLeast("RETENTION_BATCH_SIZE", int64(c.Retention.BatchSize), 1)
+
+	p.positive("HEALTH_READY_TIMEOUT", c.Health.ReadyTimeout)
+	p.nonNegative("HEALTH_DRAIN_DELAY", c.Health.DrainDelay)
+	p.addr("METRICS_PROMETHEUS_ADDR", c.Metrics.PrometheusAddr)
+
+	return errors.Join(p...)
+}
@@ -30,6 +30,19 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (u
 	return id, err
 }
 
+const createBotAccount = `-- name: CreateBotAccount :one
+INSERT INTO accounts (username, password_hash, kind)
+VALUES ($1, '', 'bot')
+RETURNING id
+`
+
+func (q *Queries) CreateBotAccount(ctx context.Context, username string) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, createBotAccount, username)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
 const getAccountByID = `-- name: GetAccountByID :one
 SELECT id, username, created_at, updated_at
 FROM accounts
@@ -80,10 +93,59 @@ func (q *Queries) GetAccountByUsername(ctx context.Context, username string) (Ge
 	return i, err
 }
 
+const getAccountsByUsernames = `-- name: GetAccountsByUsernames :many
+SELECT id, username
+FROM accounts
+WHERE username = ANY($1::text[])
+`
+
+type GetAccountsByUsernamesRow struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+}
+
+func (q *Queries) GetAccountsByUsernames(ctx context.Context, usernames []string) ([]GetAccountsByUsernamesRow, error) {
+	rows, err := q.db.Query(ctx, getAccountsByUsernames, usernames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetAccountsByUsernamesRow{}
+	for rows.Next() {
+		var i GetAccountsByUsernamesRow
+		if err := rows.Scan(&i.ID, &i.Username); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAccountKindByUsername = `-- name: GetAccountKindByUsername :one
+SELECT id, kind
+FROM accounts
+WHERE username = $1
+`
+
+type GetAccountKindByUsernameRow struct {
+	ID   uuid.UUID `json:"id"`
+	Kind string    `json:"kind"`
+}
+
+func (q *Queries) GetAccountKindByUsername(ctx context.Context, username string) (GetAccountKindByUsernameRow, error) {
+	row := q.db.QueryRow(ctx, getAccountKindByUsername, username)
+	var i GetAccountKindByUsernameRow
+	err := row.Scan(&i.ID, &i.Kind)
+	return i, err
+}
+
 const getLoginCredential = `-- name: GetLoginCredential :one
 SELECT id, username, password_hash
 FROM accounts
-WHERE username = $1
+WHERE username = $1 AND kind = 'user'
 `
 
 type GetLoginCredentialRow struct {
@@ -0,0 +1,24 @@
+package db
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed sql/schema/*.sql
+var schemaFS embed.FS
+
+//go:embed sql/seed/*.sql
+var seedFS embed.FS
+
+// Migrations はバイナリに埋め込んだスキーマのマイグレーション (sql/schema/*.{up,down}.sql)
+func Migrations() fs.FS {
+	sub, _ := fs.Sub(schemaFS, "sql/schema") // 埋め込み時にパスが存在することは保証される
+	return sub
+}
+
+// Seeds はバイナリに埋め込んだ開発用のサンプルデータ (sql/seed/*.sql)
+func Seeds() fs.FS {
+	sub, _ := fs.Sub(seedFS, "sql/seed")
+	return sub
+}
@@ -12,31 +12,72 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const createMessage = `-- name: CreateMessage :exec
-INSERT INTO messages (room_id, author_id, content)
-VALUES ($1, $2, $3)
+const createMessage = `-- name: CreateMessage :one
+INSERT INTO messages (room_id, author_id, content, format)
+VALUES ($1, $2, $3, $4)
+RETURNING id
 `
 
 type CreateMessageParams struct {
 	RoomID   uuid.UUID `json:"room_id"`
 	AuthorID uuid.UUID `json:"author_id"`
 	Content  string    `json:"content"`
+	Format   string    `json:"format"`
 }
 
-func (q *Queries) CreateMessage(ctx context.Context, arg CreateMessageParams) error {
-	_, err := q.db.Exec(ctx, createMessage, arg.RoomID, arg.AuthorID, arg.Content)
-	return err
+func (q *Queries) CreateMessage(ctx context.Context, arg CreateMessageParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, createMessage,
+		arg.RoomID,
+		arg.AuthorID,
+		arg.Content,
+		arg.Format,
+	)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const createSystemMessage = `-- name: CreateSystemMessage :one
+INSERT INTO messages (room_id, author_id, content, kind, event)
+VALUES ($1, $2, $3, 'system', $4)
+RETURNING id
+`
+
+type CreateSystemMessageParams struct {
+	RoomID   uuid.UUID `json:"room_id"`
+	AuthorID uuid.UUID `json:"author_id"`
+	Content  string    `json:"content"`
+	Event    []byte    `json:"event"`
+}
+
+func (q *Queries) CreateSystemMessage(ctx context.Context, arg CreateSystemMessageParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, createSystemMessage,
+		arg.RoomID,
+		arg.AuthorID,
+		arg.Content,
+		arg.Event,
+	)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
 }
 
 const getMessagesByRoomID = `-- name: GetMessagesByRoomID :many
 SELECT
     m.id AS message_id,
     m.room_id,
+    m.kind,
     m.content,
+    m.format,
+    m.event,
     m.created_at,
     m.updated_at,
     m.author_id,
-    a.username AS author_name
+    a.username AS author_name,
+    a.kind = 'bot' AS author_is_bot,
+    EXISTS (
+        SELECT 1 FROM pinned_messages AS p WHERE p.message_id = m.id
+    ) AS pinned
 FROM messages AS m
 INNER JOIN accounts AS a ON m.author_id = a.id
 WHERE m.room_id = $1
@@ -44,13 +85,18 @@ ORDER BY m.created_at ASC
 `
 
 type GetMessagesByRoomIDRow struct {
-	MessageID  uuid.UUID        `json:"message_id"`
-	RoomID     uuid.UUID        `json:"room_id"`
-	Content    string           `json:"content"`
-	CreatedAt  pgtype.Timestamp `json:"created_at"`
-	UpdatedAt  pgtype.Timestamp `json:"updated_at"`
-	AuthorID   uuid.UUID        `json:"author_id"`
-	AuthorName string           `json:"author_name"`
+	MessageID   uuid.UUID        `json:"message_id"`
+	RoomID      uuid.UUID        `json:"room_id"`
+	Kind        string           `json:"kind"`
+	Content     string           `json:"content"`
+	Format      string           `json:"format"`
+	Event       []byte           `json:"event"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+	AuthorID    uuid.UUID        `json:"author_id"`
+	AuthorName  string           `json:"author_name"`
+	AuthorIsBot bool             `json:"author_is_bot"`
+	Pinned      bool             `json:"pinned"`
 }
 
 func (q *Queries) GetMessagesByRoomID(ctx context.Context, roomID uuid.UUID) ([]GetMessagesByRoomIDRow, error) {
@@ -65,11 +111,16 @@ func (q *Queries) GetMessagesByRoomID(ctx context.Context, roomID uuid.UUID) ([]
 		if err := rows.Scan(
 			&i.MessageID,
 			&i.RoomID,
+			&i.Kind,
 			&i.Content,
+			&i.Format,
+			&i.Event,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.AuthorID,
 			&i.AuthorName,
+			&i.AuthorIsBot,
+			&i.Pinned,
 		); err != nil {
 			return nil, err
 		}
@@ -80,3 +131,23 @@ func (q *Queries) GetMessagesByRoomID(ctx context.Context, roomID uuid.UUID) ([]
 	}
 	return items, nil
 }
+
+const messageExistsInRoom = `-- name: MessageExistsInRoom :one
+SELECT EXISTS (
+    SELECT 1
+    FROM messages
+    WHERE id = $1 AND room_id = $2 AND kind = 'user'
+)
+`
+
+type MessageExistsInRoomParams struct {
+	ID     uuid.UUID `json:"id"`
+	RoomID uuid.UUID `json:"room_id"`
+}
+
+func (q *Queries) MessageExistsInRoom(ctx context.Context, arg MessageExistsInRoomParams) (bool, error) {
+	row := q.db.QueryRow(ctx, messageExistsInRoom, arg.ID, arg.RoomID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
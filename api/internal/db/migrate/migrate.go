@@ -0,0 +1,359 @@
+// Package migrate はバイナリに埋め込んだ SQL でスキーマをマイグレーションする
+//
+// マイグレーションは <version>_<name>.up.sql と <version>_<name>.down.sql の組で、
+// 適用済みのバージョンは schema_migrations テーブルに記録する
+package migrate
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// lockKey は pg_advisory_lock のキー。同じデータベースに対する複数の実行を直列にする
+const lockKey int64 = 0x746f795f6d696772 // "toy_migr"
+
+// undefinedTable は PostgreSQL の undefined_table エラーの SQLSTATE
+const undefinedTable = "42P01"
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	// Down は空の場合は戻せない
+	Down string
+}
+
+// Status はマイグレーションの適用状況
+//
+// AppliedAt がゼロ値の場合は未適用
+type Status struct {
+	Version   int64
+	Name      string
+	AppliedAt time.Time
+	// Missing は適用済みだがバイナリに含まれないマイグレーション
+	Missing bool
+}
+
+// Load は fsys の直下にあるマイグレーションをバージョンの昇順で返す
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, e := range entries {
+		if e.IsDir() || path.Ext(e.Name()) != ".sql" {
+			continue
+		}
+		m := fileName.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", e.Name())
+		}
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %q: %w", e.Name(), err)
+		}
+		body, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		}
+		if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, mig.Name, m[2])
+		}
+		sql := &mig.Up
+		if m[3] == "down" {
+			sql = &mig.Down
+		}
+		if *sql != "" {
+			return nil, fmt.Errorf("duplicate migration %q", e.Name())
+		}
+		*sql = string(body)
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int { return cmp.Compare(a.Version, b.Version) })
+	return migrations, nil
+}
+
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+func New(pool *pgxpool.Pool, migrations []Migration) *Migrator {
+	return &Migrator{pool, migrations}
+}
+
+// Up は未適用のマイグレーションをすべて適用し、適用したものを返す
+//
+// マイグレーションごとに 1 つのトランザクションで適用するため、途中で失敗した場合もそれまでのものは残る。
+// バイナリが知らないバージョンが適用済みの場合は、古いバイナリで新しいスキーマを扱わないよう何もせずにエラーを返す
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for v := range applied {
+			if !slices.ContainsFunc(m.migrations, func(mig Migration) bool { return mig.Version == v }) {
+				return fmt.Errorf("database has migration %d applied, which is unknown to this binary", v)
+			}
+		}
+
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, mig.Up); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", mig.Version, mig.Name)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("failed to apply migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down は適用済みのマイグレーションを新しいものから steps 件戻し、戻したものを返す
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range slices.Backward(m.migrations) {
+			if len(done) >= steps {
+				break
+			}
+			if _, ok := applied[mig.Version]; !ok {
+				continue
+			}
+			if mig.Down == "" {
+				return fmt.Errorf("migration %d_%s cannot be reverted", mig.Version, mig.Name)
+			}
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, mig.Down); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, "DELETE FROM schema_migrations WHERE version = $1", mig.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("failed to revert migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Baseline は version までのマイグレーションを、SQL を実行せずに適用済みとして記録し、記録したものを返す
+//
+// マイグレーションを使わずにスキーマを作成したデータベースを、以降 Up で管理できるようにする。
+// version がバイナリに含まれない場合は何もせずにエラーを返す
+func (m *Migrator) Baseline(ctx context.Context, version int64) ([]Migration, error) {
+	if !slices.ContainsFunc(m.migrations, func(mig Migration) bool { return mig.Version == version }) {
+		return nil, fmt.Errorf("migration %d is unknown to this binary", version)
+	}
+
+	var done []Migration
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		var pending []Migration
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; !ok && mig.Version <= version {
+				pending = append(pending, mig)
+			}
+		}
+		// 一部だけ記録されることのないよう、まとめて 1 つのトランザクションで記録する
+		err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			for _, mig := range pending {
+				if _, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", mig.Version, mig.Name); err != nil {
+					return fmt.Errorf("failed to record migration %d_%s: %w", mig.Version, mig.Name, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		done = pending
+		return nil
+	})
+	return done, err
+}
+
+// Status はバイナリに含まれるマイグレーションと適用済みのマイグレーションをバージョンの昇順で返す
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		rows, err := conn.Query(ctx, "SELECT version, name, applied_at FROM schema_migrations")
+		if err != nil {
+			return err
+		}
+		applied, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (Status, error) {
+			var s Status
+			err := row.Scan(&s.Version, &s.Name, &s.AppliedAt)
+			return s, err
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			s := Status{Version: mig.Version, Name: mig.Name}
+			if i := slices.IndexFunc(applied, func(a Status) bool { return a.Version == mig.Version }); i >= 0 {
+				s.AppliedAt = applied[i].AppliedAt
+				applied = slices.Delete(applied, i, i+1)
+			}
+			statuses = append(statuses, s)
+		}
+		for _, a := range applied {
+			a.Missing = true
+			statuses = append(statuses, a)
+		}
+		slices.SortFunc(statuses, func(a, b Status) int { return cmp.Compare(a.Version, b.Version) })
+		return nil
+	})
+	return statuses, err
+}
+
+// Pending は未適用のマイグレーションをバージョンの昇順で返す
+//
+// ロックを取らずに読むため、実行中のマイグレーションは未適用として返すことがある
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	applied, err := appliedVersions(ctx, m.pool)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == undefinedTable {
+		// 一度もマイグレーションしていない
+		applied, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, mig := range m.migrations {
+		if _, ok := applied[mig.Version]; !ok {
+			pending = append(pending, mig)
+		}
+	}
+	return pending, nil
+}
+
+// Seed は fsys の直下にある SQL をファイル名の順に 1 つのトランザクションで実行する
+//
+// 何度実行してもよいよう、SQL は既存の行と衝突しないように書く
+func Seed(ctx context.Context, pool *pgxpool.Pool, fsys fs.FS) error {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return err
+	}
+	return pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		for _, name := range names {
+			body, err := fs.ReadFile(fsys, name)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Exec(ctx, string(body)); err != nil {
+				return fmt.Errorf("failed to seed %s: %w", name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// withLock は advisory lock を取った接続で fn を実行する
+//
+// ロックはセッションに紐づくため、取得から解放まで同じ接続を使う
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) (err error) {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// ctx がキャンセルされてもロックを解放できるようにする
+		if _, unlockErr := conn.Exec(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", lockKey); unlockErr != nil {
+			// ロックを持ったままプールに戻さないよう、セッションごと閉じる
+			_ = conn.Hijack().Close(context.WithoutCancel(ctx))
+			err = errors.Join(err, unlockErr)
+			return
+		}
+		conn.Release()
+	}()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	if _, err := conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT PRIMARY KEY,
+    name TEXT NOT NULL,
+    applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+)`); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// querier は *pgxpool.Pool と *pgxpool.Conn に共通するメソッド
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+func appliedVersions(ctx context.Context, q querier) (map[int64]struct{}, error) {
+	rows, err := q.Query(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	versions, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return nil, err
+	}
+	applied := make(map[int64]struct{}, len(versions))
+	for _, v := range versions {
+		applied[v] = struct{}{}
+	}
+	return applied, nil
+}
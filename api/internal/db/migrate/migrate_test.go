@@ -0,0 +1,124 @@
+package migrate_test
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/quietsato/toy-small-chat/api/internal/db"
+	"github.com/quietsato/toy-small-chat/api/internal/db/migrate"
+	"github.com/stretchr/testify/require"
+)
+
+func file(body string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(body)}
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	t.Run("up と down の組をバージョンの昇順で返す", func(t *testing.T) {
+		t.Parallel()
+
+		fsys := fstest.MapFS{
+			"010_webhooks.up.sql":   file("CREATE TABLE webhooks ();"),
+			"010_webhooks.down.sql": file("DROP TABLE webhooks;"),
+			"002_mentions.up.sql":   file("CREATE TABLE mentions ();"),
+			"002_mentions.down.sql": file("DROP TABLE mentions;"),
+			"README.md":             file("ignored"),
+		}
+
+		migrations, err := migrate.Load(fsys)
+
+		require.NoError(t, err)
+		require.Equal(t, []migrate.Migration{
+			{Version: 2, Name: "mentions", Up: "CREATE TABLE mentions ();", Down: "DROP TABLE mentions;"},
+			{Version: 10, Name: "webhooks", Up: "CREATE TABLE webhooks ();", Down: "DROP TABLE webhooks;"},
+		}, migrations)
+	})
+
+	t.Run("down がないマイグレーションも読み込む", func(t *testing.T) {
+		t.Parallel()
+
+		migrations, err := migrate.Load(fstest.MapFS{"001_init.up.sql": file("SELECT 1;")})
+
+		require.NoError(t, err)
+		require.Len(t, migrations, 1)
+		require.Empty(t, migrations[0].Down)
+	})
+
+	t.Run("不正なファイルはエラー", func(t *testing.T) {
+		t.Parallel()
+
+		cases := map[string]fstest.MapFS{
+			"ファイル名の形式が違う": {"001_init.sql": file("SELECT 1;")},
+			"up がない":      {"001_init.down.sql": file("SELECT 1;")},
+			"同じバージョンで名前が違う": {
+				"001_init.up.sql":    file("SELECT 1;"),
+				"001_other.down.sql": file("SELECT 1;"),
+			},
+			"同じバージョンが重複する": {
+				"001_init.up.sql":  file("SELECT 1;"),
+				"0001_init.up.sql": file("SELECT 2;"),
+			},
+		}
+		for name, fsys := range cases {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				_, err := migrate.Load(fsys)
+
+				require.Error(t, err)
+			})
+		}
+	})
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("バージョンが 1 から連続し、すべて戻せる", func(t *testing.T) {
+		t.Parallel()
+
+		migrations, err := migrate.Load(db.Migrations())
+
+		require.NoError(t, err)
+		require.NotEmpty(t, migrations)
+		for i, mig := range migrations {
+			require.Equal(t, int64(i+1), mig.Version)
+			require.NotEmpty(t, strings.TrimSpace(mig.Down), "migration %d_%s has no down", mig.Version, mig.Name)
+		}
+	})
+
+	t.Run("サンプルデータはスキーマに含めない", func(t *testing.T) {
+		t.Parallel()
+
+		migrations, err := migrate.Load(db.Migrations())
+
+		require.NoError(t, err)
+		for _, mig := range migrations {
+			require.NotContains(t, mig.Up, "INSERT INTO", "migration %d_%s inserts rows", mig.Version, mig.Name)
+		}
+		seeds, err := fs.Glob(db.Seeds(), "*.sql")
+		require.NoError(t, err)
+		require.NotEmpty(t, seeds)
+	})
+}
+
+func TestMigrator_Baseline(t *testing.T) {
+	t.Parallel()
+
+	t.Run("バイナリが知らないバージョンは記録せずにエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		migrations, err := migrate.Load(fstest.MapFS{"001_init.up.sql": file("SELECT 1;")})
+		require.NoError(t, err)
+
+		// データベースに触れる前に失敗するため、プールは使わない
+		done, err := migrate.New(nil, migrations).Baseline(t.Context(), 2)
+
+		require.ErrorContains(t, err, "migration 2 is unknown")
+		require.Empty(t, done)
+	})
+}
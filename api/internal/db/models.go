@@ -15,6 +15,42 @@ type Account struct {
 	PasswordHash []byte           `json:"password_hash"`
 	CreatedAt    pgtype.Timestamp `json:"created_at"`
 	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	Kind         string           `json:"kind"`
+}
+
+type Attachment struct {
+	ID           uuid.UUID        `json:"id"`
+	RoomID       uuid.UUID        `json:"room_id"`
+	UploadedBy   uuid.UUID        `json:"uploaded_by"`
+	MessageID    pgtype.UUID      `json:"message_id"`
+	FileName     string           `json:"file_name"`
+	ContentType  string           `json:"content_type"`
+	Size         int64            `json:"size"`
+	StorageKey   string           `json:"storage_key"`
+	ThumbnailKey pgtype.Text      `json:"thumbnail_key"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+}
+
+type IncomingWebhook struct {
+	ID              uuid.UUID        `json:"id"`
+	RoomID          uuid.UUID        `json:"room_id"`
+	BotAccountID    uuid.UUID        `json:"bot_account_id"`
+	TokenHash       []byte           `json:"token_hash"`
+	RateLimit       int32            `json:"rate_limit"`
+	WindowStartedAt pgtype.Timestamp `json:"window_started_at"`
+	WindowCount     int32            `json:"window_count"`
+	CreatedBy       uuid.UUID        `json:"created_by"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	TokenRotatedAt  pgtype.Timestamp `json:"token_rotated_at"`
+	RevokedAt       pgtype.Timestamp `json:"revoked_at"`
+}
+
+type Mention struct {
+	ID        uuid.UUID        `json:"id"`
+	MessageID uuid.UUID        `json:"message_id"`
+	AccountID uuid.UUID        `json:"account_id"`
+	ReadAt    pgtype.Timestamp `json:"read_at"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
 }
 
 type Message struct {
@@ -24,12 +60,104 @@ type Message struct {
 	Content   string           `json:"content"`
 	CreatedAt pgtype.Timestamp `json:"created_at"`
 	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+	Format    string           `json:"format"`
+	Kind      string           `json:"kind"`
+	Event     []byte           `json:"event"`
 }
 
-type Room struct {
-	ID        uuid.UUID        `json:"id"`
-	Name      string           `json:"name"`
-	CreatedBy uuid.UUID        `json:"created_by"`
-	CreatedAt pgtype.Timestamp `json:"created_at"`
+type MessageMention struct {
+	ID          uuid.UUID   `json:"id"`
+	MessageID   uuid.UUID   `json:"message_id"`
+	Kind        string      `json:"kind"`
+	AccountID   pgtype.UUID `json:"account_id"`
+	StartOffset int32       `json:"start_offset"`
+	EndOffset   int32       `json:"end_offset"`
+}
+
+type PinnedMessage struct {
+	MessageID uuid.UUID        `json:"message_id"`
+	RoomID    uuid.UUID        `json:"room_id"`
+	PinnedBy  uuid.UUID        `json:"pinned_by"`
+	PinnedAt  pgtype.Timestamp `json:"pinned_at"`
+}
+
+type RateLimitBucket struct {
+	Key       string           `json:"key"`
+	Tokens    float64          `json:"tokens"`
 	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+	FullAt    pgtype.Timestamp `json:"full_at"`
+}
+
+type Room struct {
+	ID             uuid.UUID        `json:"id"`
+	Name           string           `json:"name"`
+	CreatedBy      uuid.UUID        `json:"created_by"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+	UpdatedAt      pgtype.Timestamp `json:"updated_at"`
+	Topic          string           `json:"topic"`
+	ArchivedAt     pgtype.Timestamp `json:"archived_at"`
+	DeletedAt      pgtype.Timestamp `json:"deleted_at"`
+	LastActivityAt pgtype.Timestamp `json:"last_activity_at"`
+	LastMessageID  pgtype.UUID      `json:"last_message_id"`
+	RetentionDays  pgtype.Int4      `json:"retention_days"`
+}
+
+type RoomNotificationSetting struct {
+	AccountID  uuid.UUID        `json:"account_id"`
+	RoomID     uuid.UUID        `json:"room_id"`
+	Level      string           `json:"level"`
+	MutedUntil pgtype.Timestamp `json:"muted_until"`
+	UpdatedAt  pgtype.Timestamp `json:"updated_at"`
+}
+
+type ScheduledMessage struct {
+	ID            uuid.UUID        `json:"id"`
+	RoomID        uuid.UUID        `json:"room_id"`
+	AuthorID      uuid.UUID        `json:"author_id"`
+	Content       string           `json:"content"`
+	Format        string           `json:"format"`
+	SendAt        pgtype.Timestamp `json:"send_at"`
+	Status        string           `json:"status"`
+	Attempts      int32            `json:"attempts"`
+	NextAttemptAt pgtype.Timestamp `json:"next_attempt_at"`
+	MessageID     pgtype.UUID      `json:"message_id"`
+	LastError     pgtype.Text      `json:"last_error"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+	SentAt        pgtype.Timestamp `json:"sent_at"`
+}
+
+type SlashCommand struct {
+	ID           uuid.UUID        `json:"id"`
+	RoomID       uuid.UUID        `json:"room_id"`
+	Name         string           `json:"name"`
+	Description  string           `json:"description"`
+	CallbackUrl  string           `json:"callback_url"`
+	Secret       string           `json:"secret"`
+	BotAccountID uuid.UUID        `json:"bot_account_id"`
+	CreatedBy    uuid.UUID        `json:"created_by"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+}
+
+type Webhook struct {
+	ID         uuid.UUID        `json:"id"`
+	RoomID     uuid.UUID        `json:"room_id"`
+	Url        string           `json:"url"`
+	Secret     string           `json:"secret"`
+	EventTypes []string         `json:"event_types"`
+	CreatedBy  uuid.UUID        `json:"created_by"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+}
+
+type WebhookDelivery struct {
+	ID             uuid.UUID        `json:"id"`
+	WebhookID      uuid.UUID        `json:"webhook_id"`
+	EventType      string           `json:"event_type"`
+	Payload        []byte           `json:"payload"`
+	Status         string           `json:"status"`
+	Attempts       int32            `json:"attempts"`
+	NextAttemptAt  pgtype.Timestamp `json:"next_attempt_at"`
+	LastStatusCode pgtype.Int4      `json:"last_status_code"`
+	LastError      pgtype.Text      `json:"last_error"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+	DeliveredAt    pgtype.Timestamp `json:"delivered_at"`
 }
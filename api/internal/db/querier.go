@@ -8,17 +8,130 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
+	AttachToMessage(ctx context.Context, arg AttachToMessageParams) (int64, error)
+	CancelScheduledMessage(ctx context.Context, arg CancelScheduledMessageParams) (int64, error)
+	ClaimScheduledMessages(ctx context.Context, arg ClaimScheduledMessagesParams) ([]ClaimScheduledMessagesRow, error)
+	ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]ClaimWebhookDeliveriesRow, error)
+	// Attempts is the value returned by the claim; a worker whose lease expired and was re-claimed updates no rows
+	CompleteScheduledMessage(ctx context.Context, arg CompleteScheduledMessageParams) (int64, error)
+	CompleteWebhookDelivery(ctx context.Context, arg CompleteWebhookDeliveryParams) error
+	ConsumeIncomingWebhookRateLimit(ctx context.Context, id uuid.UUID) (int64, error)
+	CountIncomingWebhooksByRoomID(ctx context.Context, roomID uuid.UUID) (int64, error)
+	CountPendingScheduledMessages(ctx context.Context, arg CountPendingScheduledMessagesParams) (int64, error)
+	CountPinnedMessagesByRoomID(ctx context.Context, roomID uuid.UUID) (int64, error)
+	CountSlashCommandsByRoomID(ctx context.Context, roomID uuid.UUID) (int64, error)
+	CountWebhooksByRoomID(ctx context.Context, roomID uuid.UUID) (int64, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (uuid.UUID, error)
-	CreateMessage(ctx context.Context, arg CreateMessageParams) error
-	CreateRoom(ctx context.Context, arg CreateRoomParams) error
+	CreateAttachment(ctx context.Context, arg CreateAttachmentParams) error
+	CreateBotAccount(ctx context.Context, username string) (uuid.UUID, error)
+	CreateIncomingWebhook(ctx context.Context, arg CreateIncomingWebhookParams) (uuid.UUID, error)
+	CreateMention(ctx context.Context, arg CreateMentionParams) error
+	CreateMessage(ctx context.Context, arg CreateMessageParams) (uuid.UUID, error)
+	CreateMessageMention(ctx context.Context, arg CreateMessageMentionParams) error
+	CreatePinnedMessage(ctx context.Context, arg CreatePinnedMessageParams) (int64, error)
+	// Returns no rows when the username is taken
+	CreatePlaceholderAccount(ctx context.Context, username string) (uuid.UUID, error)
+	CreateRoom(ctx context.Context, arg CreateRoomParams) (uuid.UUID, error)
+	CreateScheduledMessage(ctx context.Context, arg CreateScheduledMessageParams) (uuid.UUID, error)
+	CreateSlashCommand(ctx context.Context, arg CreateSlashCommandParams) (uuid.UUID, error)
+	CreateSystemMessage(ctx context.Context, arg CreateSystemMessageParams) (uuid.UUID, error)
+	CreateWebhook(ctx context.Context, arg CreateWebhookParams) (uuid.UUID, error)
+	DeleteAttachmentsByMessageIDs(ctx context.Context, messageIds []uuid.UUID) ([]DeleteAttachmentsByMessageIDsRow, error)
+	DeleteAttachmentsByRoomIDs(ctx context.Context, roomIds []uuid.UUID) ([]DeleteAttachmentsByRoomIDsRow, error)
+	// Delivery payloads copy message content, so finished deliveries follow the room's retention period.
+	// Pending deliveries are left to the webhook worker and purged once they finish
+	DeleteExpiredWebhookDeliveries(ctx context.Context, arg DeleteExpiredWebhookDeliveriesParams) (int64, error)
+	DeleteFullRateLimitBuckets(ctx context.Context, now pgtype.Timestamp) (int64, error)
+	DeleteIncomingWebhooksByRoomIDs(ctx context.Context, roomIds []uuid.UUID) error
+	DeleteMentionsByMessageIDs(ctx context.Context, messageIds []uuid.UUID) error
+	DeleteMentionsByRoomIDs(ctx context.Context, roomIds []uuid.UUID) error
+	DeleteMessageMentionsByMessageIDs(ctx context.Context, messageIds []uuid.UUID) error
+	DeleteMessageMentionsByRoomIDs(ctx context.Context, roomIds []uuid.UUID) error
+	DeleteMessagesByIDs(ctx context.Context, ids []uuid.UUID) (int64, error)
+	DeleteMessagesByRoomIDs(ctx context.Context, roomIds []uuid.UUID) (int64, error)
+	DeleteNotificationSettingsByRoomIDs(ctx context.Context, roomIds []uuid.UUID) error
+	DeletePinnedMessage(ctx context.Context, arg DeletePinnedMessageParams) (int64, error)
+	DeletePinnedMessagesByMessageIDs(ctx context.Context, messageIds []uuid.UUID) error
+	DeletePinnedMessagesByRoomIDs(ctx context.Context, roomIds []uuid.UUID) error
+	DeleteRoomsByIDs(ctx context.Context, ids []uuid.UUID) error
+	DeleteScheduledMessagesByMessageIDs(ctx context.Context, messageIds []uuid.UUID) error
+	DeleteScheduledMessagesByRoomIDs(ctx context.Context, roomIds []uuid.UUID) error
+	DeleteSlashCommand(ctx context.Context, arg DeleteSlashCommandParams) (int64, error)
+	DeleteSlashCommandsByRoomIDs(ctx context.Context, roomIds []uuid.UUID) error
+	DeleteWebhook(ctx context.Context, arg DeleteWebhookParams) (int64, error)
+	// Deliveries are deleted with their webhook by ON DELETE CASCADE
+	DeleteWebhooksByRoomIDs(ctx context.Context, roomIds []uuid.UUID) error
+	EnqueueWebhookDeliveries(ctx context.Context, arg EnqueueWebhookDeliveriesParams) (int64, error)
+	FailScheduledMessage(ctx context.Context, arg FailScheduledMessageParams) error
+	FailWebhookDelivery(ctx context.Context, arg FailWebhookDeliveryParams) error
 	GetAccountByID(ctx context.Context, id uuid.UUID) (GetAccountByIDRow, error)
 	GetAccountByUsername(ctx context.Context, username string) (GetAccountByUsernameRow, error)
+	GetAccountKindByUsername(ctx context.Context, username string) (GetAccountKindByUsernameRow, error)
+	GetAccountsByIDs(ctx context.Context, ids []uuid.UUID) ([]GetAccountsByIDsRow, error)
+	GetAccountsByUsernames(ctx context.Context, usernames []string) ([]GetAccountsByUsernamesRow, error)
+	GetAttachmentByID(ctx context.Context, id uuid.UUID) (Attachment, error)
+	GetAttachmentsByRoomID(ctx context.Context, roomID uuid.UUID) ([]GetAttachmentsByRoomIDRow, error)
+	GetDeletedRoomForUpdate(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+	GetIncomingWebhookCredential(ctx context.Context, id uuid.UUID) (GetIncomingWebhookCredentialRow, error)
+	GetIncomingWebhooksByRoomID(ctx context.Context, roomID uuid.UUID) ([]GetIncomingWebhooksByRoomIDRow, error)
 	GetLoginCredential(ctx context.Context, username string) (GetLoginCredentialRow, error)
+	GetMentionSpansByRoomID(ctx context.Context, roomID uuid.UUID) ([]GetMentionSpansByRoomIDRow, error)
+	GetMentionsByAccountID(ctx context.Context, arg GetMentionsByAccountIDParams) ([]GetMentionsByAccountIDRow, error)
 	GetMessagesByRoomID(ctx context.Context, roomID uuid.UUID) ([]GetMessagesByRoomIDRow, error)
-	GetRooms(ctx context.Context) ([]Room, error)
+	GetMessagesForExport(ctx context.Context, arg GetMessagesForExportParams) ([]GetMessagesForExportRow, error)
+	GetNotificationSettings(ctx context.Context, arg GetNotificationSettingsParams) (GetNotificationSettingsRow, error)
+	GetNotificationSettingsByAccountIDs(ctx context.Context, arg GetNotificationSettingsByAccountIDsParams) ([]GetNotificationSettingsByAccountIDsRow, error)
+	GetPinnedMessagesByRoomID(ctx context.Context, roomID uuid.UUID) ([]GetPinnedMessagesByRoomIDRow, error)
+	GetRoomArchivedAt(ctx context.Context, id uuid.UUID) (pgtype.Timestamp, error)
+	GetRoomForExport(ctx context.Context, id uuid.UUID) (GetRoomForExportRow, error)
+	GetRoomForUpdate(ctx context.Context, id uuid.UUID) (GetRoomForUpdateRow, error)
+	GetRoomMemberIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
+	GetRoomMembersForExport(ctx context.Context, roomID uuid.UUID) ([]GetRoomMembersForExportRow, error)
+	GetRoomOwner(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+	GetRoomRetentionDays(ctx context.Context, id uuid.UUID) (pgtype.Int4, error)
+	GetRooms(ctx context.Context, arg GetRoomsParams) ([]GetRoomsRow, error)
+	GetRoomsForExport(ctx context.Context) ([]GetRoomsForExportRow, error)
+	// Leased is true while a worker that claimed the message may still be posting it
+	GetScheduledMessageForUpdate(ctx context.Context, arg GetScheduledMessageForUpdateParams) (GetScheduledMessageForUpdateRow, error)
+	GetScheduledMessages(ctx context.Context, arg GetScheduledMessagesParams) ([]GetScheduledMessagesRow, error)
+	GetSlashCommandByName(ctx context.Context, arg GetSlashCommandByNameParams) (GetSlashCommandByNameRow, error)
+	GetSlashCommandsByRoomID(ctx context.Context, roomID uuid.UUID) ([]GetSlashCommandsByRoomIDRow, error)
+	GetWebhookDeliveries(ctx context.Context, arg GetWebhookDeliveriesParams) ([]GetWebhookDeliveriesRow, error)
+	GetWebhooksByRoomID(ctx context.Context, roomID uuid.UUID) ([]GetWebhooksByRoomIDRow, error)
+	ImportMessages(ctx context.Context, arg []ImportMessagesParams) (int64, error)
+	ImportRoom(ctx context.Context, arg ImportRoomParams) (uuid.UUID, error)
+	// A non-positive default keeps messages in rooms without an override forever
+	LockExpiredMessages(ctx context.Context, arg LockExpiredMessagesParams) ([]LockExpiredMessagesRow, error)
+	// A new bucket starts full. The no-op update locks an existing row so that concurrent takes wait for each other
+	LockRateLimitBucket(ctx context.Context, arg LockRateLimitBucketParams) (LockRateLimitBucketRow, error)
+	// Rooms deleted longer ago than the grace period can no longer be restored.
+	// A restore locks the row first, so a room being restored is skipped
+	LockPurgeableRooms(ctx context.Context, arg LockPurgeableRoomsParams) ([]uuid.UUID, error)
+	MarkMentionsAsRead(ctx context.Context, arg MarkMentionsAsReadParams) (int64, error)
+	MessageExistsInRoom(ctx context.Context, arg MessageExistsInRoomParams) (bool, error)
+	RefreshRoomLastActivity(ctx context.Context, id uuid.UUID) error
+	RestoreRoom(ctx context.Context, arg RestoreRoomParams) (int64, error)
+	RetryScheduledMessage(ctx context.Context, arg RetryScheduledMessageParams) error
+	RetryWebhookDelivery(ctx context.Context, arg RetryWebhookDeliveryParams) error
+	RevokeIncomingWebhook(ctx context.Context, arg RevokeIncomingWebhookParams) (int64, error)
+	// Revocation is final, so a revoked webhook is not updated
+	RotateIncomingWebhookToken(ctx context.Context, arg RotateIncomingWebhookTokenParams) (int64, error)
+	SetRoomArchived(ctx context.Context, arg SetRoomArchivedParams) error
+	SetRoomRetentionDays(ctx context.Context, arg SetRoomRetentionDaysParams) error
+	// A bot is reused only for the account that registered every command answering as it,
+	// so that nobody else can post as someone else's bot. Bots of incoming webhooks are never reused
+	SlashCommandBotOwnedBy(ctx context.Context, arg SlashCommandBotOwnedByParams) (bool, error)
+	SlashCommandExistsInRoom(ctx context.Context, arg SlashCommandExistsInRoomParams) (bool, error)
+	SoftDeleteRoom(ctx context.Context, id uuid.UUID) error
+	UpdateRateLimitBucket(ctx context.Context, arg UpdateRateLimitBucketParams) error
+	UpdateRoom(ctx context.Context, arg UpdateRoomParams) error
+	UpdateRoomLastActivity(ctx context.Context, arg UpdateRoomLastActivityParams) error
+	UpsertNotificationSettings(ctx context.Context, arg UpsertNotificationSettingsParams) (int64, error)
+	WebhookExistsInRoom(ctx context.Context, arg WebhookExistsInRoomParams) (bool, error)
 }
 
 var _ Querier = (*Queries)(nil)
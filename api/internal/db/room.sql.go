@@ -9,11 +9,13 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const createRoom = `-- name: CreateRoom :exec
+const createRoom = `-- name: CreateRoom :one
 INSERT INTO rooms (name, created_by)
 VALUES ($1, $2)
+RETURNING id
 `
 
 type CreateRoomParams struct {
@@ -21,32 +23,236 @@ type CreateRoomParams struct {
 	CreatedBy uuid.UUID `json:"created_by"`
 }
 
-func (q *Queries) CreateRoom(ctx context.Context, arg CreateRoomParams) error {
-	_, err := q.db.Exec(ctx, createRoom, arg.Name, arg.CreatedBy)
-	return err
+func (q *Queries) CreateRoom(ctx context.Context, arg CreateRoomParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, createRoom, arg.Name, arg.CreatedBy)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
 }
 
-const getRooms = `-- name: GetRooms :many
-SELECT id, name, created_by, created_at, updated_at
+const getDeletedRoomForUpdate = `-- name: GetDeletedRoomForUpdate :one
+SELECT created_by
+FROM rooms
+WHERE id = $1 AND deleted_at IS NOT NULL
+FOR UPDATE
+`
+
+func (q *Queries) GetDeletedRoomForUpdate(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, getDeletedRoomForUpdate, id)
+	var created_by uuid.UUID
+	err := row.Scan(&created_by)
+	return created_by, err
+}
+
+const getRoomArchivedAt = `-- name: GetRoomArchivedAt :one
+SELECT archived_at
+FROM rooms
+WHERE id = $1 AND deleted_at IS NULL
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetRoomArchivedAt(ctx context.Context, id uuid.UUID) (pgtype.Timestamp, error) {
+	row := q.db.QueryRow(ctx, getRoomArchivedAt, id)
+	var archived_at pgtype.Timestamp
+	err := row.Scan(&archived_at)
+	return archived_at, err
+}
+
+const getRoomForUpdate = `-- name: GetRoomForUpdate :one
+SELECT created_by, name, topic, archived_at
+FROM rooms
+WHERE id = $1 AND deleted_at IS NULL
+FOR UPDATE
+`
+
+type GetRoomForUpdateRow struct {
+	CreatedBy  uuid.UUID        `json:"created_by"`
+	Name       string           `json:"name"`
+	Topic      string           `json:"topic"`
+	ArchivedAt pgtype.Timestamp `json:"archived_at"`
+}
+
+func (q *Queries) GetRoomForUpdate(ctx context.Context, id uuid.UUID) (GetRoomForUpdateRow, error) {
+	row := q.db.QueryRow(ctx, getRoomForUpdate, id)
+	var i GetRoomForUpdateRow
+	err := row.Scan(
+		&i.CreatedBy,
+		&i.Name,
+		&i.Topic,
+		&i.ArchivedAt,
+	)
+	return i, err
+}
+
+const getRoomMemberIDs = `-- name: GetRoomMemberIDs :many
+SELECT created_by AS account_id
 FROM rooms
-ORDER BY updated_at
+WHERE rooms.id = $1
+UNION
+SELECT author_id AS account_id
+FROM messages
+WHERE messages.room_id = $1
 `
 
-func (q *Queries) GetRooms(ctx context.Context) ([]Room, error) {
-	rows, err := q.db.Query(ctx, getRooms)
+func (q *Queries) GetRoomMemberIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, getRoomMemberIDs, id)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []Room{}
+	items := []uuid.UUID{}
 	for rows.Next() {
-		var i Room
+		var account_id uuid.UUID
+		if err := rows.Scan(&account_id); err != nil {
+			return nil, err
+		}
+		items = append(items, account_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRoomOwner = `-- name: GetRoomOwner :one
+SELECT created_by
+FROM rooms
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) GetRoomOwner(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, getRoomOwner, id)
+	var created_by uuid.UUID
+	err := row.Scan(&created_by)
+	return created_by, err
+}
+
+const getRooms = `-- name: GetRooms :many
+SELECT
+    rl.id,
+    rl.name,
+    rl.topic,
+    rl.created_by,
+    rl.created_at,
+    rl.updated_at,
+    rl.archived_at,
+    rl.last_activity_at,
+    rl.member_count,
+    lm.content AS last_message_content,
+    la.username AS last_message_author_name,
+    ns.level AS notification_level,
+    CASE WHEN ns.muted_until > NOW() THEN ns.muted_until END::timestamp AS notification_muted_until
+FROM (
+    SELECT
+        r.id,
+        r.name,
+        r.topic,
+        r.created_by,
+        r.created_at,
+        r.updated_at,
+        r.archived_at,
+        r.last_activity_at,
+        r.last_message_id,
+        (
+            SELECT COUNT(*)
+            FROM (
+                SELECT r.created_by AS account_id
+                UNION
+                SELECT m.author_id FROM messages AS m WHERE m.room_id = r.id
+            ) AS members
+        )::bigint AS member_count
+    FROM rooms AS r
+    WHERE r.deleted_at IS NULL
+      AND ($1::text = '' OR r.name ILIKE $2::text || '%' OR r.name % $1::text)
+      AND ($3::text <> 'created' OR r.created_by = $4::uuid)
+      AND ($3::text <> 'joined' OR r.created_by = $4::uuid OR EXISTS (
+          SELECT 1 FROM messages AS m WHERE m.room_id = r.id AND m.author_id = $4::uuid
+      ))
+) AS rl
+LEFT JOIN messages AS lm ON rl.last_message_id = lm.id
+LEFT JOIN accounts AS la ON lm.author_id = la.id
+LEFT JOIN room_notification_settings AS ns
+    ON ns.room_id = rl.id AND ns.account_id = $4::uuid
+WHERE NOT $5::boolean
+   OR CASE $6::text
+        WHEN 'name' THEN rl.name > $7::text
+            OR (rl.name = $7::text AND rl.id > $8::uuid)
+        WHEN 'created' THEN rl.created_at < $9::timestamp
+            OR (rl.created_at = $9::timestamp AND rl.id > $8::uuid)
+        ELSE rl.last_activity_at < $9::timestamp
+            OR (rl.last_activity_at = $9::timestamp AND rl.id > $8::uuid)
+      END
+ORDER BY
+    CASE WHEN $6::text = 'name' THEN rl.name END ASC,
+    CASE WHEN $6::text = 'created' THEN rl.created_at END DESC,
+    CASE WHEN $6::text = 'activity' THEN rl.last_activity_at END DESC,
+    rl.id ASC
+LIMIT $10
+`
+
+type GetRoomsParams struct {
+	Query      string           `json:"query"`
+	NamePrefix string           `json:"name_prefix"`
+	Filter     string           `json:"filter"`
+	AccountID  uuid.UUID        `json:"account_id"`
+	HasCursor  bool             `json:"has_cursor"`
+	Sort       string           `json:"sort"`
+	CursorName string           `json:"cursor_name"`
+	CursorID   uuid.UUID        `json:"cursor_id"`
+	CursorTime pgtype.Timestamp `json:"cursor_time"`
+	LimitCount int32            `json:"limit_count"`
+}
+
+type GetRoomsRow struct {
+	ID                     uuid.UUID        `json:"id"`
+	Name                   string           `json:"name"`
+	Topic                  string           `json:"topic"`
+	CreatedBy              uuid.UUID        `json:"created_by"`
+	CreatedAt              pgtype.Timestamp `json:"created_at"`
+	UpdatedAt              pgtype.Timestamp `json:"updated_at"`
+	ArchivedAt             pgtype.Timestamp `json:"archived_at"`
+	LastActivityAt         pgtype.Timestamp `json:"last_activity_at"`
+	MemberCount            int64            `json:"member_count"`
+	LastMessageContent     pgtype.Text      `json:"last_message_content"`
+	LastMessageAuthorName  pgtype.Text      `json:"last_message_author_name"`
+	NotificationLevel      pgtype.Text      `json:"notification_level"`
+	NotificationMutedUntil pgtype.Timestamp `json:"notification_muted_until"`
+}
+
+func (q *Queries) GetRooms(ctx context.Context, arg GetRoomsParams) ([]GetRoomsRow, error) {
+	rows, err := q.db.Query(ctx, getRooms,
+		arg.Query,
+		arg.NamePrefix,
+		arg.Filter,
+		arg.AccountID,
+		arg.HasCursor,
+		arg.Sort,
+		arg.CursorName,
+		arg.CursorID,
+		arg.CursorTime,
+		arg.LimitCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetRoomsRow{}
+	for rows.Next() {
+		var i GetRoomsRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Name,
+			&i.Topic,
 			&i.CreatedBy,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ArchivedAt,
+			&i.LastActivityAt,
+			&i.MemberCount,
+			&i.LastMessageContent,
+			&i.LastMessageAuthorName,
+			&i.NotificationLevel,
+			&i.NotificationMutedUntil,
 		); err != nil {
 			return nil, err
 		}
@@ -57,3 +263,84 @@ func (q *Queries) GetRooms(ctx context.Context) ([]Room, error) {
 	}
 	return items, nil
 }
+
+const restoreRoom = `-- name: RestoreRoom :execrows
+UPDATE rooms
+SET deleted_at = NULL
+WHERE id = $1
+  AND deleted_at > NOW() - $2::interval
+`
+
+type RestoreRoomParams struct {
+	ID          uuid.UUID       `json:"id"`
+	GracePeriod pgtype.Interval `json:"grace_period"`
+}
+
+func (q *Queries) RestoreRoom(ctx context.Context, arg RestoreRoomParams) (int64, error) {
+	result, err := q.db.Exec(ctx, restoreRoom, arg.ID, arg.GracePeriod)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setRoomArchived = `-- name: SetRoomArchived :exec
+UPDATE rooms
+SET archived_at = CASE WHEN $1::boolean THEN NOW() ELSE NULL END,
+    updated_at = NOW()
+WHERE id = $2
+`
+
+type SetRoomArchivedParams struct {
+	Archived bool      `json:"archived"`
+	ID       uuid.UUID `json:"id"`
+}
+
+func (q *Queries) SetRoomArchived(ctx context.Context, arg SetRoomArchivedParams) error {
+	_, err := q.db.Exec(ctx, setRoomArchived, arg.Archived, arg.ID)
+	return err
+}
+
+const softDeleteRoom = `-- name: SoftDeleteRoom :exec
+UPDATE rooms
+SET deleted_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) SoftDeleteRoom(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, softDeleteRoom, id)
+	return err
+}
+
+const updateRoom = `-- name: UpdateRoom :exec
+UPDATE rooms
+SET name = $2, topic = $3, updated_at = NOW()
+WHERE id = $1
+`
+
+type UpdateRoomParams struct {
+	ID    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	Topic string    `json:"topic"`
+}
+
+func (q *Queries) UpdateRoom(ctx context.Context, arg UpdateRoomParams) error {
+	_, err := q.db.Exec(ctx, updateRoom, arg.ID, arg.Name, arg.Topic)
+	return err
+}
+
+const updateRoomLastActivity = `-- name: UpdateRoomLastActivity :exec
+UPDATE rooms
+SET last_activity_at = NOW(), last_message_id = $1
+WHERE id = $2
+`
+
+type UpdateRoomLastActivityParams struct {
+	LastMessageID pgtype.UUID `json:"last_message_id"`
+	ID            uuid.UUID   `json:"id"`
+}
+
+func (q *Queries) UpdateRoomLastActivity(ctx context.Context, arg UpdateRoomLastActivityParams) error {
+	_, err := q.db.Exec(ctx, updateRoomLastActivity, arg.LastMessageID, arg.ID)
+	return err
+}
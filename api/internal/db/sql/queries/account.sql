@@ -3,6 +3,11 @@ INSERT INTO accounts (username, password_hash)
 VALUES ($1, $2)
 RETURNING id;
 
+-- name: CreateBotAccount :one
+INSERT INTO accounts (username, password_hash, kind)
+VALUES ($1, '', 'bot')
+RETURNING id;
+
 -- name: GetAccountByID :one
 SELECT id, username, created_at, updated_at
 FROM accounts
@@ -13,7 +18,17 @@ SELECT id, username, created_at, updated_at
 FROM accounts
 WHERE username = $1;
 
+-- name: GetAccountKindByUsername :one
+SELECT id, kind
+FROM accounts
+WHERE username = $1;
+
 -- name: GetLoginCredential :one
 SELECT id, username, password_hash
 FROM accounts
-WHERE username = $1;
+WHERE username = $1 AND kind = 'user';
+
+-- name: GetAccountsByUsernames :many
+SELECT id, username
+FROM accounts
+WHERE username = ANY(@usernames::text[]);
@@ -0,0 +1,23 @@
+-- name: CreateAttachment :exec
+INSERT INTO attachments (id, room_id, uploaded_by, file_name, content_type, size, storage_key, thumbnail_key)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: GetAttachmentByID :one
+SELECT id, room_id, uploaded_by, message_id, file_name, content_type, size, storage_key, thumbnail_key, created_at
+FROM attachments
+WHERE id = $1;
+
+-- name: GetAttachmentsByRoomID :many
+SELECT id, message_id, file_name, content_type, size, thumbnail_key
+FROM attachments
+WHERE room_id = $1
+  AND message_id IS NOT NULL
+ORDER BY created_at;
+
+-- name: AttachToMessage :execrows
+UPDATE attachments
+SET message_id = @message_id
+WHERE id = ANY(@attachment_ids::uuid[])
+  AND room_id = @room_id
+  AND uploaded_by = @uploaded_by
+  AND message_id IS NULL;
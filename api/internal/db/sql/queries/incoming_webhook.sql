@@ -0,0 +1,40 @@
+-- name: CreateIncomingWebhook :one
+INSERT INTO incoming_webhooks (room_id, bot_account_id, token_hash, rate_limit, created_by)
+VALUES (@room_id, @bot_account_id, @token_hash, @rate_limit, @created_by)
+RETURNING id;
+
+-- name: CountIncomingWebhooksByRoomID :one
+SELECT COUNT(*)
+FROM incoming_webhooks
+WHERE room_id = @room_id;
+
+-- name: GetIncomingWebhooksByRoomID :many
+SELECT w.id, a.username AS name, w.rate_limit, w.created_by, w.created_at, w.token_rotated_at, w.revoked_at
+FROM incoming_webhooks AS w
+INNER JOIN accounts AS a ON w.bot_account_id = a.id
+WHERE w.room_id = @room_id
+ORDER BY w.created_at, w.id;
+
+-- name: GetIncomingWebhookCredential :one
+SELECT room_id, bot_account_id, token_hash
+FROM incoming_webhooks
+WHERE id = @id AND revoked_at IS NULL;
+
+-- name: ConsumeIncomingWebhookRateLimit :execrows
+UPDATE incoming_webhooks
+SET window_started_at = CASE WHEN window_started_at <= NOW() - INTERVAL '1 minute' THEN NOW() ELSE window_started_at END,
+    window_count = CASE WHEN window_started_at <= NOW() - INTERVAL '1 minute' THEN 1 ELSE window_count + 1 END
+WHERE id = @id
+  AND (window_started_at <= NOW() - INTERVAL '1 minute' OR window_count < rate_limit);
+
+-- name: RotateIncomingWebhookToken :execrows
+-- Revocation is final, so a revoked webhook is not updated
+UPDATE incoming_webhooks
+SET token_hash = @token_hash,
+    token_rotated_at = NOW()
+WHERE id = @id AND room_id = @room_id AND revoked_at IS NULL;
+
+-- name: RevokeIncomingWebhook :execrows
+UPDATE incoming_webhooks
+SET revoked_at = COALESCE(revoked_at, NOW())
+WHERE id = @id AND room_id = @room_id;
@@ -0,0 +1,48 @@
+-- name: CreateMessageMention :exec
+INSERT INTO message_mentions (message_id, kind, account_id, start_offset, end_offset)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: CreateMention :exec
+INSERT INTO mentions (message_id, account_id)
+VALUES ($1, $2)
+ON CONFLICT (message_id, account_id) DO NOTHING;
+
+-- name: GetMentionSpansByRoomID :many
+SELECT
+    mm.message_id,
+    mm.kind,
+    mm.start_offset,
+    mm.end_offset,
+    a.username
+FROM message_mentions AS mm
+INNER JOIN messages AS m ON mm.message_id = m.id
+LEFT JOIN accounts AS a ON mm.account_id = a.id
+WHERE m.room_id = $1
+ORDER BY mm.message_id, mm.start_offset;
+
+-- name: GetMentionsByAccountID :many
+SELECT
+    mn.id,
+    mn.message_id,
+    mn.read_at,
+    mn.created_at,
+    m.room_id,
+    r.name AS room_name,
+    m.content,
+    a.username AS author_name
+FROM mentions AS mn
+INNER JOIN messages AS m ON mn.message_id = m.id
+INNER JOIN rooms AS r ON m.room_id = r.id
+INNER JOIN accounts AS a ON m.author_id = a.id
+WHERE mn.account_id = @account_id
+  AND r.deleted_at IS NULL
+  AND (NOT @unread_only::boolean OR mn.read_at IS NULL)
+ORDER BY mn.created_at DESC, mn.id DESC
+LIMIT @limit_count OFFSET @offset_count;
+
+-- name: MarkMentionsAsRead :execrows
+UPDATE mentions
+SET read_at = NOW()
+WHERE account_id = @account_id
+  AND read_at IS NULL
+  AND (cardinality(@mention_ids::uuid[]) = 0 OR id = ANY(@mention_ids::uuid[]));
@@ -1,16 +1,36 @@
--- name: CreateMessage :exec
-INSERT INTO messages (room_id, author_id, content)
-VALUES ($1, $2, $3);
+-- name: CreateMessage :one
+INSERT INTO messages (room_id, author_id, content, format)
+VALUES ($1, $2, $3, $4)
+RETURNING id;
+
+-- name: CreateSystemMessage :one
+INSERT INTO messages (room_id, author_id, content, kind, event)
+VALUES ($1, $2, $3, 'system', $4)
+RETURNING id;
+
+-- name: MessageExistsInRoom :one
+SELECT EXISTS (
+    SELECT 1
+    FROM messages
+    WHERE id = $1 AND room_id = $2 AND kind = 'user'
+);
 
 -- name: GetMessagesByRoomID :many
 SELECT
     m.id AS message_id,
     m.room_id,
+    m.kind,
     m.content,
+    m.format,
+    m.event,
     m.created_at,
     m.updated_at,
     m.author_id,
-    a.username AS author_name
+    a.username AS author_name,
+    a.kind = 'bot' AS author_is_bot,
+    EXISTS (
+        SELECT 1 FROM pinned_messages AS p WHERE p.message_id = m.id
+    ) AS pinned
 FROM messages AS m
 INNER JOIN accounts AS a ON m.author_id = a.id
 WHERE m.room_id = $1
@@ -0,0 +1,24 @@
+-- name: GetNotificationSettings :one
+SELECT
+    ns.level,
+    CASE WHEN ns.muted_until > NOW() THEN ns.muted_until END::timestamp AS muted_until
+FROM rooms AS r
+LEFT JOIN room_notification_settings AS ns
+    ON ns.room_id = r.id AND ns.account_id = @account_id
+WHERE r.id = @room_id AND r.deleted_at IS NULL;
+
+-- name: GetNotificationSettingsByAccountIDs :many
+SELECT account_id, level, muted_until
+FROM room_notification_settings
+WHERE room_id = @room_id
+  AND account_id = ANY(@account_ids::uuid[]);
+
+-- name: UpsertNotificationSettings :execrows
+INSERT INTO room_notification_settings (account_id, room_id, level, muted_until)
+SELECT @account_id, r.id, @level, @muted_until
+FROM rooms AS r
+WHERE r.id = @room_id AND r.deleted_at IS NULL
+ON CONFLICT (account_id, room_id) DO UPDATE
+SET level = EXCLUDED.level,
+    muted_until = EXCLUDED.muted_until,
+    updated_at = NOW();
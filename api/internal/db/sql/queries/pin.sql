@@ -0,0 +1,29 @@
+-- name: CreatePinnedMessage :execrows
+INSERT INTO pinned_messages (message_id, room_id, pinned_by)
+VALUES ($1, $2, $3)
+ON CONFLICT (message_id) DO NOTHING;
+
+-- name: DeletePinnedMessage :execrows
+DELETE FROM pinned_messages
+WHERE message_id = $1 AND room_id = $2;
+
+-- name: CountPinnedMessagesByRoomID :one
+SELECT COUNT(*)
+FROM pinned_messages
+WHERE room_id = $1;
+
+-- name: GetPinnedMessagesByRoomID :many
+SELECT
+    m.id AS message_id,
+    m.content,
+    m.format,
+    m.created_at,
+    a.username AS author_name,
+    pa.username AS pinned_by_name,
+    p.pinned_at
+FROM pinned_messages AS p
+INNER JOIN messages AS m ON p.message_id = m.id
+INNER JOIN accounts AS a ON m.author_id = a.id
+INNER JOIN accounts AS pa ON p.pinned_by = pa.id
+WHERE p.room_id = $1
+ORDER BY p.pinned_at DESC;
@@ -0,0 +1,17 @@
+-- name: LockRateLimitBucket :one
+-- A new bucket starts full. The no-op update locks an existing row so that concurrent takes wait for each other
+INSERT INTO rate_limit_buckets (key, tokens, updated_at, full_at)
+VALUES (@key, @tokens, @now, @now)
+ON CONFLICT (key) DO UPDATE SET key = EXCLUDED.key
+RETURNING tokens, updated_at;
+
+-- name: UpdateRateLimitBucket :exec
+UPDATE rate_limit_buckets
+SET tokens = @tokens,
+    updated_at = @updated_at,
+    full_at = @full_at
+WHERE key = @key;
+
+-- name: DeleteFullRateLimitBuckets :execrows
+DELETE FROM rate_limit_buckets
+WHERE full_at <= @now;
@@ -0,0 +1,118 @@
+-- name: GetRoomRetentionDays :one
+SELECT retention_days
+FROM rooms
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: SetRoomRetentionDays :exec
+UPDATE rooms
+SET retention_days = @retention_days
+WHERE id = @id;
+
+-- name: LockExpiredMessages :many
+-- A non-positive default keeps messages in rooms without an override forever
+SELECT m.id, m.room_id
+FROM messages AS m
+INNER JOIN rooms AS r ON m.room_id = r.id
+WHERE COALESCE(r.retention_days, @default_days::int) > 0
+  AND m.created_at < NOW() - make_interval(days => COALESCE(r.retention_days, @default_days::int))
+ORDER BY m.created_at
+LIMIT @limit_count
+FOR UPDATE OF m SKIP LOCKED;
+
+-- name: DeleteMentionsByMessageIDs :exec
+DELETE FROM mentions
+WHERE message_id = ANY(@message_ids::uuid[]);
+
+-- name: DeleteMessageMentionsByMessageIDs :exec
+DELETE FROM message_mentions
+WHERE message_id = ANY(@message_ids::uuid[]);
+
+-- name: DeletePinnedMessagesByMessageIDs :exec
+DELETE FROM pinned_messages
+WHERE message_id = ANY(@message_ids::uuid[]);
+
+-- name: DeleteScheduledMessagesByMessageIDs :exec
+DELETE FROM scheduled_messages
+WHERE message_id = ANY(@message_ids::uuid[]);
+
+-- name: DeleteAttachmentsByMessageIDs :many
+DELETE FROM attachments
+WHERE message_id = ANY(@message_ids::uuid[])
+RETURNING storage_key, thumbnail_key;
+
+-- name: DeleteMessagesByIDs :execrows
+DELETE FROM messages
+WHERE id = ANY(@ids::uuid[]);
+
+-- name: DeleteExpiredWebhookDeliveries :execrows
+-- Delivery payloads copy message content, so finished deliveries follow the room's retention period.
+-- Pending deliveries are left to the webhook worker and purged once they finish
+DELETE FROM webhook_deliveries
+WHERE id IN (
+    SELECT d.id
+    FROM webhook_deliveries AS d
+    INNER JOIN webhooks AS w ON d.webhook_id = w.id
+    INNER JOIN rooms AS r ON w.room_id = r.id
+    WHERE d.status <> 'pending'
+      AND COALESCE(r.retention_days, @default_days::int) > 0
+      AND d.created_at < NOW() - make_interval(days => COALESCE(r.retention_days, @default_days::int))
+    ORDER BY d.created_at
+    LIMIT @limit_count
+    FOR UPDATE OF d SKIP LOCKED
+);
+
+-- name: LockPurgeableRooms :many
+-- Rooms deleted longer ago than the grace period can no longer be restored.
+-- A restore locks the row first, so a room being restored is skipped
+SELECT id
+FROM rooms
+WHERE deleted_at < NOW() - @grace_period::interval
+ORDER BY deleted_at
+LIMIT @limit_count
+FOR UPDATE SKIP LOCKED;
+
+-- name: DeleteMentionsByRoomIDs :exec
+DELETE FROM mentions
+WHERE message_id IN (SELECT id FROM messages WHERE room_id = ANY(@room_ids::uuid[]));
+
+-- name: DeleteMessageMentionsByRoomIDs :exec
+DELETE FROM message_mentions
+WHERE message_id IN (SELECT id FROM messages WHERE room_id = ANY(@room_ids::uuid[]));
+
+-- name: DeletePinnedMessagesByRoomIDs :exec
+DELETE FROM pinned_messages
+WHERE room_id = ANY(@room_ids::uuid[]);
+
+-- name: DeleteScheduledMessagesByRoomIDs :exec
+DELETE FROM scheduled_messages
+WHERE room_id = ANY(@room_ids::uuid[]);
+
+-- name: DeleteAttachmentsByRoomIDs :many
+DELETE FROM attachments
+WHERE room_id = ANY(@room_ids::uuid[])
+RETURNING storage_key, thumbnail_key;
+
+-- name: DeleteMessagesByRoomIDs :execrows
+DELETE FROM messages
+WHERE room_id = ANY(@room_ids::uuid[]);
+
+-- name: DeleteNotificationSettingsByRoomIDs :exec
+DELETE FROM room_notification_settings
+WHERE room_id = ANY(@room_ids::uuid[]);
+
+-- name: DeleteWebhooksByRoomIDs :exec
+-- Deliveries are deleted with their webhook by ON DELETE CASCADE
+DELETE FROM webhooks
+WHERE room_id = ANY(@room_ids::uuid[]);
+
+-- name: DeleteIncomingWebhooksByRoomIDs :exec
+DELETE FROM incoming_webhooks
+WHERE room_id = ANY(@room_ids::uuid[]);
+
+-- name: DeleteSlashCommandsByRoomIDs :exec
+DELETE FROM slash_commands
+WHERE room_id = ANY(@room_ids::uuid[]);
+
+-- name: DeleteRoomsByIDs :exec
+DELETE FROM rooms
+WHERE id = ANY(@ids::uuid[]);
@@ -1,8 +1,125 @@
--- name: CreateRoom :exec
+-- name: CreateRoom :one
 INSERT INTO rooms (name, created_by)
-VALUES ($1, $2);
+VALUES ($1, $2)
+RETURNING id;
 
 -- name: GetRooms :many
-SELECT id, name, created_by, created_at, updated_at
+SELECT
+    rl.id,
+    rl.name,
+    rl.topic,
+    rl.created_by,
+    rl.created_at,
+    rl.updated_at,
+    rl.archived_at,
+    rl.last_activity_at,
+    rl.member_count,
+    lm.content AS last_message_content,
+    la.username AS last_message_author_name,
+    ns.level AS notification_level,
+    CASE WHEN ns.muted_until > NOW() THEN ns.muted_until END::timestamp AS notification_muted_until
+FROM (
+    SELECT
+        r.id,
+        r.name,
+        r.topic,
+        r.created_by,
+        r.created_at,
+        r.updated_at,
+        r.archived_at,
+        r.last_activity_at,
+        r.last_message_id,
+        (
+            SELECT COUNT(*)
+            FROM (
+                SELECT r.created_by AS account_id
+                UNION
+                SELECT m.author_id FROM messages AS m WHERE m.room_id = r.id
+            ) AS members
+        )::bigint AS member_count
+    FROM rooms AS r
+    WHERE r.deleted_at IS NULL
+      AND (@query::text = '' OR r.name ILIKE @name_prefix::text || '%' OR r.name % @query::text)
+      AND (@filter::text <> 'created' OR r.created_by = @account_id::uuid)
+      AND (@filter::text <> 'joined' OR r.created_by = @account_id::uuid OR EXISTS (
+          SELECT 1 FROM messages AS m WHERE m.room_id = r.id AND m.author_id = @account_id::uuid
+      ))
+) AS rl
+LEFT JOIN messages AS lm ON rl.last_message_id = lm.id
+LEFT JOIN accounts AS la ON lm.author_id = la.id
+LEFT JOIN room_notification_settings AS ns
+    ON ns.room_id = rl.id AND ns.account_id = @account_id::uuid
+WHERE NOT @has_cursor::boolean
+   OR CASE @sort::text
+        WHEN 'name' THEN rl.name > @cursor_name::text
+            OR (rl.name = @cursor_name::text AND rl.id > @cursor_id::uuid)
+        WHEN 'created' THEN rl.created_at < @cursor_time::timestamp
+            OR (rl.created_at = @cursor_time::timestamp AND rl.id > @cursor_id::uuid)
+        ELSE rl.last_activity_at < @cursor_time::timestamp
+            OR (rl.last_activity_at = @cursor_time::timestamp AND rl.id > @cursor_id::uuid)
+      END
+ORDER BY
+    CASE WHEN @sort::text = 'name' THEN rl.name END ASC,
+    CASE WHEN @sort::text = 'created' THEN rl.created_at END DESC,
+    CASE WHEN @sort::text = 'activity' THEN rl.last_activity_at END DESC,
+    rl.id ASC
+LIMIT @limit_count;
+
+-- name: GetRoomMemberIDs :many
+SELECT created_by AS account_id
+FROM rooms
+WHERE rooms.id = $1
+UNION
+SELECT author_id AS account_id
+FROM messages
+WHERE messages.room_id = $1;
+
+-- name: GetRoomOwner :one
+SELECT created_by
+FROM rooms
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetRoomForUpdate :one
+SELECT created_by, name, topic, archived_at
+FROM rooms
+WHERE id = $1 AND deleted_at IS NULL
+FOR UPDATE;
+
+-- name: GetDeletedRoomForUpdate :one
+SELECT created_by
 FROM rooms
-ORDER BY updated_at;
+WHERE id = $1 AND deleted_at IS NOT NULL
+FOR UPDATE;
+
+-- name: GetRoomArchivedAt :one
+SELECT archived_at
+FROM rooms
+WHERE id = $1 AND deleted_at IS NULL
+FOR NO KEY UPDATE;
+
+-- name: UpdateRoom :exec
+UPDATE rooms
+SET name = $2, topic = $3, updated_at = NOW()
+WHERE id = $1;
+
+-- name: SetRoomArchived :exec
+UPDATE rooms
+SET archived_at = CASE WHEN @archived::boolean THEN NOW() ELSE NULL END,
+    updated_at = NOW()
+WHERE id = @id;
+
+-- name: SoftDeleteRoom :exec
+UPDATE rooms
+SET deleted_at = NOW()
+WHERE id = $1;
+
+-- name: RestoreRoom :execrows
+UPDATE rooms
+SET deleted_at = NULL
+WHERE id = @id
+  AND deleted_at > NOW() - @grace_period::interval;
+
+-- name: UpdateRoomLastActivity :exec
+UPDATE rooms
+SET last_activity_at = NOW(), last_message_id = @last_message_id
+WHERE id = @id;
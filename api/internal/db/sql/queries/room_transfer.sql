@@ -0,0 +1,65 @@
+-- name: GetRoomsForExport :many
+SELECT id, name, topic, created_by, created_at, archived_at, retention_days
+FROM rooms
+WHERE deleted_at IS NULL
+ORDER BY created_at, id;
+
+-- name: GetRoomForExport :one
+SELECT id, name, topic, created_by, created_at, archived_at, retention_days
+FROM rooms
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetRoomMembersForExport :many
+SELECT id, username, kind
+FROM accounts
+WHERE id IN (
+    SELECT created_by FROM rooms WHERE rooms.id = @room_id
+    UNION
+    SELECT author_id FROM messages WHERE messages.room_id = @room_id
+)
+ORDER BY username;
+
+-- name: GetMessagesForExport :many
+SELECT id, author_id, kind, content, format, event, created_at, updated_at
+FROM messages
+WHERE room_id = @room_id
+  AND (created_at, id) > (@after_created_at::timestamp, @after_id::uuid)
+ORDER BY created_at, id
+LIMIT @limit_count;
+
+-- name: GetAccountsByIDs :many
+SELECT id, username
+FROM accounts
+WHERE id = ANY(@ids::uuid[]);
+
+-- name: CreatePlaceholderAccount :one
+-- Returns no rows when the username is taken
+INSERT INTO accounts (username, password_hash, kind)
+VALUES ($1, '', 'placeholder')
+ON CONFLICT (username) DO NOTHING
+RETURNING id;
+
+-- name: ImportRoom :one
+INSERT INTO rooms (name, topic, created_by, created_at, updated_at, archived_at, retention_days, last_activity_at)
+VALUES (@name, @topic, @created_by, @created_at, @created_at, @archived_at, @retention_days, @created_at)
+RETURNING id;
+
+-- name: ImportMessages :copyfrom
+INSERT INTO messages (id, room_id, author_id, kind, content, format, event, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+-- name: RefreshRoomLastActivity :exec
+UPDATE rooms
+SET last_message_id = (
+        SELECT m.id
+        FROM messages AS m
+        WHERE m.room_id = rooms.id AND m.kind = 'user'
+        ORDER BY m.created_at DESC
+        LIMIT 1
+    ),
+    last_activity_at = COALESCE((
+        SELECT MAX(m.created_at)
+        FROM messages AS m
+        WHERE m.room_id = rooms.id AND m.kind = 'user'
+    ), rooms.created_at)
+WHERE id = $1;
@@ -0,0 +1,62 @@
+-- name: CreateScheduledMessage :one
+INSERT INTO scheduled_messages (room_id, author_id, content, format, send_at, next_attempt_at)
+VALUES (@room_id, @author_id, @content, @format, @send_at, @send_at)
+RETURNING id;
+
+-- name: CountPendingScheduledMessages :one
+SELECT COUNT(*)
+FROM scheduled_messages
+WHERE room_id = @room_id AND author_id = @author_id AND status = 'pending';
+
+-- name: GetScheduledMessageForUpdate :one
+-- Leased is true while a worker that claimed the message may still be posting it
+SELECT status, (attempts > 0 AND next_attempt_at > NOW())::boolean AS leased
+FROM scheduled_messages
+WHERE id = @id AND room_id = @room_id AND author_id = @author_id
+FOR UPDATE;
+
+-- name: CancelScheduledMessage :execrows
+UPDATE scheduled_messages
+SET status = 'canceled'
+WHERE id = @id AND room_id = @room_id AND author_id = @author_id AND status = 'pending';
+
+-- name: GetScheduledMessages :many
+SELECT id, content, format, send_at, status, message_id, last_error, created_at, sent_at
+FROM scheduled_messages
+WHERE room_id = @room_id AND author_id = @author_id
+ORDER BY send_at DESC, id DESC
+LIMIT @limit_count;
+
+-- name: ClaimScheduledMessages :many
+UPDATE scheduled_messages
+SET attempts = attempts + 1,
+    next_attempt_at = NOW() + @lease::interval
+WHERE id IN (
+    SELECT id
+    FROM scheduled_messages
+    WHERE status = 'pending' AND next_attempt_at <= NOW()
+    ORDER BY next_attempt_at
+    LIMIT @limit_count
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, room_id, author_id, content, format, attempts;
+
+-- name: CompleteScheduledMessage :execrows
+-- Attempts is the value returned by the claim; a worker whose lease expired and was re-claimed updates no rows
+UPDATE scheduled_messages
+SET status = 'sent',
+    message_id = @message_id,
+    last_error = NULL,
+    sent_at = NOW()
+WHERE id = @id AND status = 'pending' AND attempts = @attempts;
+
+-- name: RetryScheduledMessage :exec
+UPDATE scheduled_messages
+SET last_error = @last_error
+WHERE id = @id AND status = 'pending' AND attempts = @attempts;
+
+-- name: FailScheduledMessage :exec
+UPDATE scheduled_messages
+SET status = 'failed',
+    last_error = @last_error
+WHERE id = @id AND status = 'pending' AND attempts = @attempts;
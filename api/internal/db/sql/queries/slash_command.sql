@@ -0,0 +1,50 @@
+-- name: CreateSlashCommand :one
+INSERT INTO slash_commands (room_id, name, description, callback_url, secret, bot_account_id, created_by)
+VALUES (@room_id, @name, @description, @callback_url, @secret, @bot_account_id, @created_by)
+RETURNING id;
+
+-- name: DeleteSlashCommand :execrows
+DELETE FROM slash_commands
+WHERE id = @id AND room_id = @room_id;
+
+-- name: CountSlashCommandsByRoomID :one
+SELECT COUNT(*)
+FROM slash_commands
+WHERE room_id = @room_id;
+
+-- name: SlashCommandExistsInRoom :one
+SELECT EXISTS (
+    SELECT 1
+    FROM slash_commands
+    WHERE room_id = @room_id AND name = @name
+);
+
+-- name: GetSlashCommandByName :one
+SELECT c.id, c.callback_url, c.secret, c.bot_account_id
+FROM slash_commands AS c
+INNER JOIN rooms AS r ON c.room_id = r.id
+WHERE c.room_id = @room_id AND c.name = @name AND r.deleted_at IS NULL;
+
+-- name: GetSlashCommandsByRoomID :many
+SELECT c.id, c.name, c.description, a.username AS bot_name, c.created_by, c.created_at
+FROM slash_commands AS c
+INNER JOIN accounts AS a ON c.bot_account_id = a.id
+WHERE c.room_id = @room_id
+ORDER BY c.name;
+
+-- name: SlashCommandBotOwnedBy :one
+-- A bot is reused only for the account that registered every command answering as it,
+-- so that nobody else can post as someone else's bot. Bots of incoming webhooks are never reused
+SELECT EXISTS (
+    SELECT 1
+    FROM slash_commands
+    WHERE bot_account_id = @bot_account_id AND created_by = @created_by
+) AND NOT EXISTS (
+    SELECT 1
+    FROM slash_commands
+    WHERE bot_account_id = @bot_account_id AND created_by <> @created_by
+) AND NOT EXISTS (
+    SELECT 1
+    FROM incoming_webhooks
+    WHERE bot_account_id = @bot_account_id
+) AS owned;
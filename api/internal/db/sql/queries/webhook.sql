@@ -0,0 +1,77 @@
+-- name: CreateWebhook :one
+INSERT INTO webhooks (room_id, url, secret, event_types, created_by)
+VALUES (@room_id, @url, @secret, @event_types, @created_by)
+RETURNING id;
+
+-- name: DeleteWebhook :execrows
+DELETE FROM webhooks
+WHERE id = @id AND room_id = @room_id;
+
+-- name: CountWebhooksByRoomID :one
+SELECT COUNT(*)
+FROM webhooks
+WHERE room_id = @room_id;
+
+-- name: WebhookExistsInRoom :one
+SELECT EXISTS (
+    SELECT 1
+    FROM webhooks
+    WHERE id = @id AND room_id = @room_id
+);
+
+-- name: GetWebhooksByRoomID :many
+SELECT id, url, event_types, created_by, created_at
+FROM webhooks
+WHERE room_id = @room_id
+ORDER BY created_at, id;
+
+-- name: EnqueueWebhookDeliveries :execrows
+INSERT INTO webhook_deliveries (webhook_id, event_type, payload)
+SELECT id, @event_type, @payload
+FROM webhooks
+WHERE room_id = @room_id AND @event_type = ANY(event_types);
+
+-- name: ClaimWebhookDeliveries :many
+UPDATE webhook_deliveries AS d
+SET attempts = d.attempts + 1,
+    next_attempt_at = NOW() + @lease::interval
+FROM webhooks AS w
+WHERE d.webhook_id = w.id
+  AND d.id IN (
+    SELECT id
+    FROM webhook_deliveries
+    WHERE status = 'pending' AND next_attempt_at <= NOW()
+    ORDER BY next_attempt_at
+    LIMIT @limit_count
+    FOR UPDATE SKIP LOCKED
+  )
+RETURNING d.id, d.event_type, d.payload, d.attempts, w.url, w.secret;
+
+-- name: CompleteWebhookDelivery :exec
+UPDATE webhook_deliveries
+SET status = 'succeeded',
+    last_status_code = @status_code,
+    last_error = NULL,
+    delivered_at = NOW()
+WHERE id = @id;
+
+-- name: RetryWebhookDelivery :exec
+UPDATE webhook_deliveries
+SET last_status_code = @status_code,
+    last_error = @last_error,
+    next_attempt_at = NOW() + @delay::interval
+WHERE id = @id;
+
+-- name: FailWebhookDelivery :exec
+UPDATE webhook_deliveries
+SET status = 'failed',
+    last_status_code = @status_code,
+    last_error = @last_error
+WHERE id = @id;
+
+-- name: GetWebhookDeliveries :many
+SELECT id, event_type, status, attempts, next_attempt_at, last_status_code, last_error, created_at, delivered_at
+FROM webhook_deliveries
+WHERE webhook_id = @webhook_id
+ORDER BY created_at DESC, id DESC
+LIMIT @limit_count;
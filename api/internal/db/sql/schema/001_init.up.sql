@@ -27,15 +27,8 @@ CREATE TABLE IF NOT EXISTS messages (
 );
 
 -- Indexes
-CREATE INDEX idx_accounts_username ON accounts(username);
-CREATE INDEX idx_rooms_created_by ON rooms(created_by);
-CREATE INDEX idx_messages_room_id ON messages(room_id);
-CREATE INDEX idx_messages_author ON messages(author_id);
-CREATE INDEX idx_messages_created_at ON messages(created_at);
-
--- Sample data
-INSERT INTO accounts (id, username, password_hash) VALUES
-    ('5e305dee-d8d8-49b3-ad6c-73037e58601a', 'testuser', '$2a$10$ZQ9Z9Z9Z9Z9Z9Z9Z9Z9Z9eKGX7JQ7J7J7J7J7J7J7J7J7J7J7J7J7');
-
-INSERT INTO rooms (id, name, created_by) VALUES
-    ('8481027d-d6f6-402f-ae6d-98571e8f6496', 'General', '5e305dee-d8d8-49b3-ad6c-73037e58601a');
+CREATE INDEX IF NOT EXISTS idx_accounts_username ON accounts(username);
+CREATE INDEX IF NOT EXISTS idx_rooms_created_by ON rooms(created_by);
+CREATE INDEX IF NOT EXISTS idx_messages_room_id ON messages(room_id);
+CREATE INDEX IF NOT EXISTS idx_messages_author ON messages(author_id);
+CREATE INDEX IF NOT EXISTS idx_messages_created_at ON messages(created_at);
@@ -0,0 +1,23 @@
+-- Mention spans in message content
+CREATE TABLE IF NOT EXISTS message_mentions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    message_id UUID NOT NULL REFERENCES messages(id),
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('user', 'room')),
+    account_id UUID REFERENCES accounts(id),
+    start_offset INTEGER NOT NULL,
+    end_offset INTEGER NOT NULL
+);
+
+-- Mentions inbox (one row per notified account)
+CREATE TABLE IF NOT EXISTS mentions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    message_id UUID NOT NULL REFERENCES messages(id),
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    read_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (message_id, account_id)
+);
+
+-- Indexes
+CREATE INDEX idx_message_mentions_message_id ON message_mentions(message_id);
+CREATE INDEX idx_mentions_account_id_created_at ON mentions(account_id, created_at DESC);
@@ -0,0 +1,17 @@
+-- Attachments table
+CREATE TABLE IF NOT EXISTS attachments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    room_id UUID NOT NULL REFERENCES rooms(id),
+    uploaded_by UUID NOT NULL REFERENCES accounts(id),
+    message_id UUID REFERENCES messages(id),
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(127) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key TEXT NOT NULL,
+    thumbnail_key TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Indexes
+CREATE INDEX idx_attachments_room_id ON attachments(room_id);
+CREATE INDEX idx_attachments_message_id ON attachments(message_id);
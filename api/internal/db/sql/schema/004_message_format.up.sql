@@ -0,0 +1,2 @@
+-- Message body format ('plain' or 'markdown')
+ALTER TABLE messages ADD COLUMN format VARCHAR(16) NOT NULL DEFAULT 'plain';
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS pinned_messages;
+
+ALTER TABLE messages DROP COLUMN event;
+ALTER TABLE messages DROP COLUMN kind;
@@ -0,0 +1,14 @@
+-- System messages (pins, etc.) share the messages table with user messages
+ALTER TABLE messages ADD COLUMN kind VARCHAR(16) NOT NULL DEFAULT 'user';
+ALTER TABLE messages ADD COLUMN event JSONB;
+
+-- Pinned messages table
+CREATE TABLE IF NOT EXISTS pinned_messages (
+    message_id UUID PRIMARY KEY REFERENCES messages(id),
+    room_id UUID NOT NULL REFERENCES rooms(id),
+    pinned_by UUID NOT NULL REFERENCES accounts(id),
+    pinned_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Indexes
+CREATE INDEX idx_pinned_messages_room_id ON pinned_messages(room_id);
@@ -0,0 +1,4 @@
+-- Room topic, archive and soft delete
+ALTER TABLE rooms ADD COLUMN topic VARCHAR(250) NOT NULL DEFAULT '';
+ALTER TABLE rooms ADD COLUMN archived_at TIMESTAMP;
+ALTER TABLE rooms ADD COLUMN deleted_at TIMESTAMP;
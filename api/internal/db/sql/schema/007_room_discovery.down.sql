@@ -0,0 +1,4 @@
+-- The pg_trgm extension is left installed; other databases in the cluster may use it
+DROP INDEX IF EXISTS idx_messages_room_id_created_at;
+DROP INDEX IF EXISTS idx_messages_room_id_author_id;
+DROP INDEX IF EXISTS idx_rooms_name_trgm;
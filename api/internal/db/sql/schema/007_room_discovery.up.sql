@@ -0,0 +1,7 @@
+-- Room name search
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Indexes
+CREATE INDEX idx_rooms_name_trgm ON rooms USING gin (name gin_trgm_ops);
+CREATE INDEX idx_messages_room_id_author_id ON messages(room_id, author_id);
+CREATE INDEX idx_messages_room_id_created_at ON messages(room_id, created_at DESC);
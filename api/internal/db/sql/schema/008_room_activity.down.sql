@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_rooms_last_activity_at;
+
+ALTER TABLE rooms DROP COLUMN last_message_id;
+ALTER TABLE rooms DROP COLUMN last_activity_at;
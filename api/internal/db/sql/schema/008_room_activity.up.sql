@@ -0,0 +1,19 @@
+-- Room last activity and last message preview
+ALTER TABLE rooms ADD COLUMN last_activity_at TIMESTAMP NOT NULL DEFAULT NOW();
+ALTER TABLE rooms ADD COLUMN last_message_id UUID REFERENCES messages(id) ON DELETE SET NULL;
+
+-- Backfill from existing messages
+UPDATE rooms SET last_activity_at = created_at;
+UPDATE rooms AS r
+SET last_activity_at = lm.created_at,
+    last_message_id = lm.id
+FROM (
+    SELECT DISTINCT ON (room_id) id, room_id, created_at
+    FROM messages
+    WHERE kind = 'user'
+    ORDER BY room_id, created_at DESC
+) AS lm
+WHERE lm.room_id = r.id;
+
+-- Indexes
+CREATE INDEX idx_rooms_last_activity_at ON rooms(last_activity_at DESC, id);
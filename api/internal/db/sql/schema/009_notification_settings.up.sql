@@ -0,0 +1,12 @@
+-- Per-account, per-room notification settings
+CREATE TABLE IF NOT EXISTS room_notification_settings (
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    room_id UUID NOT NULL REFERENCES rooms(id),
+    level VARCHAR(16) NOT NULL DEFAULT 'all' CHECK (level IN ('all', 'mentions')),
+    muted_until TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, room_id)
+);
+
+-- Indexes
+CREATE INDEX idx_room_notification_settings_room_id ON room_notification_settings(room_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS incoming_webhooks;
+
+ALTER TABLE accounts DROP COLUMN kind;
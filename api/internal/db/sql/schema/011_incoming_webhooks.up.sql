@@ -0,0 +1,21 @@
+-- Bot accounts post through incoming webhooks and cannot log in
+ALTER TABLE accounts ADD COLUMN kind VARCHAR(16) NOT NULL DEFAULT 'user' CHECK (kind IN ('user', 'bot'));
+
+-- Incoming webhooks post messages to a room as their bot account.
+-- Only the SHA-256 hash of the token is stored; the rate limit is a fixed one-minute window
+CREATE TABLE IF NOT EXISTS incoming_webhooks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    room_id UUID NOT NULL REFERENCES rooms(id),
+    bot_account_id UUID NOT NULL UNIQUE REFERENCES accounts(id),
+    token_hash BYTEA NOT NULL,
+    rate_limit INTEGER NOT NULL CHECK (rate_limit > 0),
+    window_started_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    window_count INTEGER NOT NULL DEFAULT 0,
+    created_by UUID NOT NULL REFERENCES accounts(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    token_rotated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMP
+);
+
+-- Indexes
+CREATE INDEX idx_incoming_webhooks_room_id ON incoming_webhooks(room_id);
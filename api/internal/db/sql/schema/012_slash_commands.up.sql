@@ -0,0 +1,13 @@
+-- Slash commands registered per room; invocations are sent to callback_url and answered as the bot account
+CREATE TABLE IF NOT EXISTS slash_commands (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    room_id UUID NOT NULL REFERENCES rooms(id),
+    name VARCHAR(32) NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    callback_url TEXT NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    bot_account_id UUID NOT NULL REFERENCES accounts(id),
+    created_by UUID NOT NULL REFERENCES accounts(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (room_id, name)
+);
@@ -0,0 +1,20 @@
+-- Messages queued to be posted at a later time; sent and canceled rows are kept for the author's history
+CREATE TABLE IF NOT EXISTS scheduled_messages (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    room_id UUID NOT NULL REFERENCES rooms(id),
+    author_id UUID NOT NULL REFERENCES accounts(id),
+    content TEXT NOT NULL,
+    format VARCHAR(16) NOT NULL DEFAULT 'plain',
+    send_at TIMESTAMP NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'canceled', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    message_id UUID REFERENCES messages(id),
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMP
+);
+
+-- Indexes
+CREATE INDEX idx_scheduled_messages_author ON scheduled_messages(author_id, room_id, send_at);
+CREATE INDEX idx_scheduled_messages_due ON scheduled_messages(next_attempt_at) WHERE status = 'pending';
@@ -0,0 +1,2 @@
+-- Per-room message retention in days; NULL falls back to the instance default
+ALTER TABLE rooms ADD COLUMN retention_days INTEGER CHECK (retention_days > 0);
@@ -0,0 +1,4 @@
+-- Placeholder accounts become bots, which also cannot log in
+UPDATE accounts SET kind = 'bot' WHERE kind = 'placeholder';
+ALTER TABLE accounts DROP CONSTRAINT accounts_kind_check;
+ALTER TABLE accounts ADD CONSTRAINT accounts_kind_check CHECK (kind IN ('user', 'bot'));
@@ -0,0 +1,3 @@
+-- Placeholder accounts stand in for authors of imported messages who have no account here; they cannot log in
+ALTER TABLE accounts DROP CONSTRAINT accounts_kind_check;
+ALTER TABLE accounts ADD CONSTRAINT accounts_kind_check CHECK (kind IN ('user', 'bot', 'placeholder'));
@@ -0,0 +1,11 @@
+-- Token buckets shared by API instances for per-account and per-IP rate limits.
+-- A bucket is full again at full_at, so rows past it can be deleted without changing any limit
+CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+    key VARCHAR(255) PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    full_at TIMESTAMP NOT NULL
+);
+
+-- Indexes
+CREATE INDEX idx_rate_limit_buckets_full_at ON rate_limit_buckets(full_at);
@@ -0,0 +1,8 @@
+-- Sample account and room for local development
+INSERT INTO accounts (id, username, password_hash) VALUES
+    ('5e305dee-d8d8-49b3-ad6c-73037e58601a', 'testuser', '$2a$10$ZQ9Z9Z9Z9Z9Z9Z9Z9Z9Z9eKGX7JQ7J7J7J7J7J7J7J7J7J7J7J7J7')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO rooms (id, name, created_by) VALUES
+    ('8481027d-d6f6-402f-ae6d-98571e8f6496', 'General', '5e305dee-d8d8-49b3-ad6c-73037e58601a')
+ON CONFLICT DO NOTHING;
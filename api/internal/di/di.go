@@ -1,6 +1,8 @@
 package di
 
 import (
+	"time"
+
 	"github.com/jackc/pgx/v5/pgxpool"
 	accountqueryimpl "github.com/quietsato/toy-small-chat/api/internal/applications/account/infrastructure/queryprocessorimpl"
 	accountrepoimpl "github.com/quietsato/toy-small-chat/api/internal/applications/account/infrastructure/repositoryimpl"
@@ -8,15 +10,59 @@ import (
 	accountquery "github.com/quietsato/toy-small-chat/api/internal/applications/account/usecase/queryprocessor"
 	accountrepo "github.com/quietsato/toy-small-chat/api/internal/applications/account/usecase/repository"
 	accountservice "github.com/quietsato/toy-small-chat/api/internal/applications/account/usecase/service"
+	attachmentqueryimpl "github.com/quietsato/toy-small-chat/api/internal/applications/attachment/infrastructure/queryprocessorimpl"
+	attachmentrepoimpl "github.com/quietsato/toy-small-chat/api/internal/applications/attachment/infrastructure/repositoryimpl"
+	attachmentserviceimpl "github.com/quietsato/toy-small-chat/api/internal/applications/attachment/infrastructure/serviceimpl"
+	attachmentquery "github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase/queryprocessor"
+	attachmentrepo "github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase/repository"
+	attachmentservice "github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase/service"
+	incomingwebhookqueryimpl "github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/infrastructure/queryprocessorimpl"
+	incomingwebhookrepoimpl "github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/infrastructure/repositoryimpl"
+	incomingwebhookquery "github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/usecase/queryprocessor"
+	incomingwebhookrepo "github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/usecase/repository"
+	mentionqueryimpl "github.com/quietsato/toy-small-chat/api/internal/applications/mention/infrastructure/queryprocessorimpl"
+	mentionrepoimpl "github.com/quietsato/toy-small-chat/api/internal/applications/mention/infrastructure/repositoryimpl"
+	mentionquery "github.com/quietsato/toy-small-chat/api/internal/applications/mention/usecase/queryprocessor"
+	mentionrepo "github.com/quietsato/toy-small-chat/api/internal/applications/mention/usecase/repository"
 	messagequeryimpl "github.com/quietsato/toy-small-chat/api/internal/applications/message/infrastructure/queryprocessorimpl"
 	messagerepoimpl "github.com/quietsato/toy-small-chat/api/internal/applications/message/infrastructure/repositoryimpl"
+	messageserviceimpl "github.com/quietsato/toy-small-chat/api/internal/applications/message/infrastructure/serviceimpl"
 	messagequery "github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/queryprocessor"
 	messagerepo "github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/repository"
+	messageservice "github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/service"
+	pinqueryimpl "github.com/quietsato/toy-small-chat/api/internal/applications/pin/infrastructure/queryprocessorimpl"
+	pinrepoimpl "github.com/quietsato/toy-small-chat/api/internal/applications/pin/infrastructure/repositoryimpl"
+	pinquery "github.com/quietsato/toy-small-chat/api/internal/applications/pin/usecase/queryprocessor"
+	pinrepo "github.com/quietsato/toy-small-chat/api/internal/applications/pin/usecase/repository"
+	retentionqueryimpl "github.com/quietsato/toy-small-chat/api/internal/applications/retention/infrastructure/queryprocessorimpl"
+	retentionrepoimpl "github.com/quietsato/toy-small-chat/api/internal/applications/retention/infrastructure/repositoryimpl"
+	retentionquery "github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase/queryprocessor"
+	retentionrepo "github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase/repository"
+	retentionservice "github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase/service"
 	roomqueryimpl "github.com/quietsato/toy-small-chat/api/internal/applications/room/infrastructure/queryprocessorimpl"
 	roomrepoimpl "github.com/quietsato/toy-small-chat/api/internal/applications/room/infrastructure/repositoryimpl"
 	roomquery "github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/queryprocessor"
 	roomrepo "github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/repository"
+	roomtransferqueryimpl "github.com/quietsato/toy-small-chat/api/internal/applications/roomtransfer/infrastructure/queryprocessorimpl"
+	roomtransferrepoimpl "github.com/quietsato/toy-small-chat/api/internal/applications/roomtransfer/infrastructure/repositoryimpl"
+	roomtransferquery "github.com/quietsato/toy-small-chat/api/internal/applications/roomtransfer/usecase/queryprocessor"
+	roomtransferrepo "github.com/quietsato/toy-small-chat/api/internal/applications/roomtransfer/usecase/repository"
+	scheduledmessagequeryimpl "github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/infrastructure/queryprocessorimpl"
+	scheduledmessagerepoimpl "github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/infrastructure/repositoryimpl"
+	scheduledmessagequery "github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase/queryprocessor"
+	scheduledmessagerepo "github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase/repository"
+	webhookqueryimpl "github.com/quietsato/toy-small-chat/api/internal/applications/webhook/infrastructure/queryprocessorimpl"
+	webhookrepoimpl "github.com/quietsato/toy-small-chat/api/internal/applications/webhook/infrastructure/repositoryimpl"
+	webhookserviceimpl "github.com/quietsato/toy-small-chat/api/internal/applications/webhook/infrastructure/serviceimpl"
+	webhookquery "github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/queryprocessor"
+	webhookrepo "github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/repository"
+	webhookservice "github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/service"
+	"github.com/quietsato/toy-small-chat/api/internal/config"
+	"github.com/quietsato/toy-small-chat/api/internal/db/migrate"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/health"
 	authmiddleware "github.com/quietsato/toy-small-chat/api/internal/server/middlewares/auth"
+	"github.com/quietsato/toy-small-chat/api/internal/server/middlewares/ratelimit"
 )
 
 type AccountDeps struct {
@@ -24,30 +70,123 @@ type AccountDeps struct {
 	Query accountquery.AccountQueryProcessor
 }
 
+// MessageDeps の Commands, CommandQuery, CommandCaller はスラッシュコマンドのためのもの
 type MessageDeps struct {
-	Repo  messagerepo.MessageRepository
-	Query messagequery.MessageQueryProcessor
+	Repo          messagerepo.MessageRepository
+	Query         messagequery.MessageQueryProcessor
+	Renderer      messageservice.MessageRenderer
+	Commands      messagerepo.SlashCommandRepository
+	CommandQuery  messagequery.SlashCommandQueryProcessor
+	CommandCaller messageservice.SlashCommandCaller
+}
+
+type MentionDeps struct {
+	Repo  mentionrepo.MentionRepository
+	Query mentionquery.MentionQueryProcessor
+}
+
+type PinDeps struct {
+	Repo  pinrepo.PinRepository
+	Query pinquery.PinQueryProcessor
 }
 
 type RoomDeps struct {
-	Repo  roomrepo.RoomRepository
-	Query roomquery.RoomQueryProcessor
+	Repo                roomrepo.RoomRepository
+	Query               roomquery.RoomQueryProcessor
+	DeletionGracePeriod time.Duration
+}
+
+type AttachmentDeps struct {
+	Repo        attachmentrepo.AttachmentRepository
+	Query       attachmentquery.AttachmentQueryProcessor
+	Storage     attachmentservice.BlobStorage
+	Thumbnailer attachmentservice.Thumbnailer
+	Policy      domain.AttachmentPolicy
+}
+
+// WebhookDeps の PollInterval, BatchSize, Timeout は配信ワーカーの設定
+type WebhookDeps struct {
+	Repo          webhookrepo.WebhookRepository
+	Query         webhookquery.WebhookQueryProcessor
+	Sender        webhookservice.WebhookSender
+	RetryPolicy   domain.WebhookRetryPolicy
+	NetworkPolicy domain.WebhookNetworkPolicy
+	PollInterval  time.Duration
+	BatchSize     int
+	Timeout       time.Duration
+}
+
+type IncomingWebhookDeps struct {
+	Repo  incomingwebhookrepo.IncomingWebhookRepository
+	Query incomingwebhookquery.IncomingWebhookQueryProcessor
+}
+
+// ScheduledMessageDeps の PollInterval, BatchSize は送信ワーカーの設定
+type ScheduledMessageDeps struct {
+	Repo         scheduledmessagerepo.ScheduledMessageRepository
+	Query        scheduledmessagequery.ScheduledMessageQueryProcessor
+	PollInterval time.Duration
+	BatchSize    int
+}
+
+// RetentionDeps の DefaultDays はルームに保存期間を指定していない場合の日数。PurgeInterval, BatchSize は削除ワーカーの設定
+type RetentionDeps struct {
+	Repo          retentionrepo.RetentionRepository
+	Query         retentionquery.RetentionQueryProcessor
+	Blobs         retentionservice.BlobRemover
+	DefaultDays   int
+	PurgeInterval time.Duration
+	BatchSize     int
+}
+
+type RoomTransferDeps struct {
+	Repo  roomtransferrepo.RoomImportRepository
+	Query roomtransferquery.RoomExportQueryProcessor
 }
 
+// AuthDeps の AdminToken は管理用 API の Bearer トークン。空の場合は管理用 API を公開しない
 type AuthDeps struct {
 	Service    accountservice.AuthService
 	Middleware authmiddleware.Provider
+	AdminToken string
+}
+
+// HealthDeps の State はプロセスの状態で、終了処理を始めたときに main が更新する
+type HealthDeps struct {
+	Database     health.DatabaseChecker
+	State        *health.State
+	ReadyTimeout time.Duration
+}
+
+// RateLimitDeps の Store が nil の場合はリクエストの頻度を制限しない
+type RateLimitDeps struct {
+	Store    ratelimit.Store
+	Signup   ratelimit.Limit
+	Login    ratelimit.Limit
+	Messages ratelimit.Limit
 }
 
 type Container struct {
-	Account AccountDeps
-	Message MessageDeps
-	Room    RoomDeps
-	Auth    AuthDeps
+	Account          AccountDeps
+	Message          MessageDeps
+	Mention          MentionDeps
+	Pin              PinDeps
+	Room             RoomDeps
+	Attachment       AttachmentDeps
+	Webhook          WebhookDeps
+	IncomingWebhook  IncomingWebhookDeps
+	ScheduledMessage ScheduledMessageDeps
+	Retention        RetentionDeps
+	RoomTransfer     RoomTransferDeps
+	Auth             AuthDeps
+	RateLimit        RateLimitDeps
+	Health           HealthDeps
 }
 
-func New(pool *pgxpool.Pool, jwtSecretKey string) *Container {
-	auth := accountserviceimpl.NewAuthService([]byte(jwtSecretKey))
+func New(pool *pgxpool.Pool, cfg config.Config, migrations []migrate.Migration, state *health.State) *Container {
+	auth := accountserviceimpl.NewAuthService([]byte(cfg.JWTSecretKey), cfg.Auth.TokenLifetime)
+	storage := attachmentserviceimpl.NewLocalBlobStorage(cfg.Attachment.Dir)
+	networkPolicy := domain.NewWebhookNetworkPolicy(cfg.Webhook.AllowPrivateNetworks)
 
 	return &Container{
 		Account: AccountDeps{
@@ -55,16 +194,88 @@ func New(pool *pgxpool.Pool, jwtSecretKey string) *Container {
 			Query: accountqueryimpl.NewAccountQueryProcessorOnDB(pool),
 		},
 		Message: MessageDeps{
-			Repo:  messagerepoimpl.NewMessageRepositoryOnDB(pool),
-			Query: messagequeryimpl.NewMessageQueryProcessorOnDB(pool),
+			Repo:          messagerepoimpl.NewMessageRepositoryOnDB(pool),
+			Query:         messagequeryimpl.NewMessageQueryProcessorOnDB(pool),
+			Renderer:      messageserviceimpl.NewMarkdownRenderer(),
+			Commands:      messagerepoimpl.NewSlashCommandRepositoryOnDB(pool),
+			CommandQuery:  messagequeryimpl.NewSlashCommandQueryProcessorOnDB(pool),
+			CommandCaller: messageserviceimpl.NewHTTPSlashCommandCaller(cfg.SlashCommand.Timeout, networkPolicy),
+		},
+		Mention: MentionDeps{
+			Repo:  mentionrepoimpl.NewMentionRepositoryOnDB(pool),
+			Query: mentionqueryimpl.NewMentionQueryProcessorOnDB(pool),
+		},
+		Pin: PinDeps{
+			Repo:  pinrepoimpl.NewPinRepositoryOnDB(pool),
+			Query: pinqueryimpl.NewPinQueryProcessorOnDB(pool),
 		},
 		Room: RoomDeps{
-			Repo:  roomrepoimpl.NewRoomRepositoryOnDB(pool),
-			Query: roomqueryimpl.NewRoomQueryProcessorOnDB(pool),
+			Repo:                roomrepoimpl.NewRoomRepositoryOnDB(pool),
+			Query:               roomqueryimpl.NewRoomQueryProcessorOnDB(pool),
+			DeletionGracePeriod: cfg.Room.DeletionGracePeriod,
+		},
+		Attachment: AttachmentDeps{
+			Repo:        attachmentrepoimpl.NewAttachmentRepositoryOnDB(pool),
+			Query:       attachmentqueryimpl.NewAttachmentQueryProcessorOnDB(pool),
+			Storage:     storage,
+			Thumbnailer: attachmentserviceimpl.NewPNGThumbnailer(),
+			Policy:      domain.NewAttachmentPolicy(cfg.Attachment.MaxSize, cfg.Attachment.AllowedMIMETypes),
+		},
+		Webhook: WebhookDeps{
+			Repo:          webhookrepoimpl.NewWebhookRepositoryOnDB(pool),
+			Query:         webhookqueryimpl.NewWebhookQueryProcessorOnDB(pool),
+			Sender:        webhookserviceimpl.NewHTTPWebhookSender(cfg.Webhook.Timeout, networkPolicy),
+			RetryPolicy:   domain.NewWebhookRetryPolicy(cfg.Webhook.MaxAttempts, cfg.Webhook.RetryBaseDelay, cfg.Webhook.RetryMaxDelay),
+			NetworkPolicy: networkPolicy,
+			PollInterval:  cfg.Webhook.PollInterval,
+			BatchSize:     cfg.Webhook.BatchSize,
+			Timeout:       cfg.Webhook.Timeout,
+		},
+		IncomingWebhook: IncomingWebhookDeps{
+			Repo:  incomingwebhookrepoimpl.NewIncomingWebhookRepositoryOnDB(pool),
+			Query: incomingwebhookqueryimpl.NewIncomingWebhookQueryProcessorOnDB(pool),
+		},
+		ScheduledMessage: ScheduledMessageDeps{
+			Repo:         scheduledmessagerepoimpl.NewScheduledMessageRepositoryOnDB(pool),
+			Query:        scheduledmessagequeryimpl.NewScheduledMessageQueryProcessorOnDB(pool),
+			PollInterval: cfg.ScheduledMessage.PollInterval,
+			BatchSize:    cfg.ScheduledMessage.BatchSize,
+		},
+		Retention: RetentionDeps{
+			Repo:          retentionrepoimpl.NewRetentionRepositoryOnDB(pool),
+			Query:         retentionqueryimpl.NewRetentionQueryProcessorOnDB(pool),
+			Blobs:         storage,
+			DefaultDays:   cfg.Retention.DefaultDays,
+			PurgeInterval: cfg.Retention.PurgeInterval,
+			BatchSize:     cfg.Retention.BatchSize,
+		},
+		RoomTransfer: RoomTransferDeps{
+			Repo:  roomtransferrepoimpl.NewRoomImportRepositoryOnDB(pool),
+			Query: roomtransferqueryimpl.NewRoomExportQueryProcessorOnDB(pool),
 		},
 		Auth: AuthDeps{
 			Service:    auth,
 			Middleware: auth,
+			AdminToken: cfg.Admin.Token,
+		},
+		RateLimit: RateLimitDeps{
+			Store:    newRateLimitStore(pool, cfg.RateLimit.Store),
+			Signup:   ratelimit.Limit(cfg.RateLimit.Signup),
+			Login:    ratelimit.Limit(cfg.RateLimit.Login),
+			Messages: ratelimit.Limit(cfg.RateLimit.Messages),
 		},
+		Health: HealthDeps{
+			Database:     health.NewDatabaseCheckerOnDB(pool, migrations),
+			State:        state,
+			ReadyTimeout: cfg.Health.ReadyTimeout,
+		},
+	}
+}
+
+// newRateLimitStore は config で検証した名前のストアを返す
+func newRateLimitStore(pool *pgxpool.Pool, name string) ratelimit.Store {
+	if name == "postgres" {
+		return ratelimit.NewPostgresStore(pool)
 	}
+	return ratelimit.NewMemoryStore()
 }
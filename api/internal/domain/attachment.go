@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+type AttachmentID struct {
+	uuid uuid.UUID
+}
+
+func NewAttachmentID() AttachmentID {
+	return AttachmentID{uuid: uuid.New()}
+}
+
+func ParseAttachmentID(s string) (AttachmentID, error) {
+	u, err := uuid.Parse(s)
+	if err != nil {
+		return AttachmentID{}, fmt.Errorf("failed to parse uuid: %w", err)
+	}
+	return AttachmentID{uuid: u}, nil
+}
+
+func AttachmentIDFromUuid(u uuid.UUID) AttachmentID {
+	return AttachmentID{uuid: u}
+}
+
+func (a AttachmentID) String() string {
+	return a.uuid.String()
+}
+
+type AttachmentFileName struct {
+	name string
+}
+
+const attachmentFileNameMaxLength = 255
+
+var (
+	ErrInvalidAttachmentFileName = errors.New("invalid attachment file name")
+)
+
+// NewAttachmentFileName はクライアントから渡されたファイル名を正規化する
+//
+// ディレクトリ部分と制御文字を取り除き、ダウンロード時のヘッダインジェクションを防ぐ
+func NewAttachmentFileName(s string) (AttachmentFileName, error) {
+	normalized := path.Base(strings.ReplaceAll(s, `\`, "/"))
+	normalized = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, normalized)
+	normalized = strings.TrimSpace(normalized)
+
+	if normalized == "" || normalized == "." || normalized == ".." || normalized == "/" || len(normalized) > attachmentFileNameMaxLength {
+		return AttachmentFileName{}, ErrInvalidAttachmentFileName
+	}
+	return AttachmentFileName{name: normalized}, nil
+}
+
+func (a AttachmentFileName) String() string {
+	return a.name
+}
+
+var (
+	ErrAttachmentTooLarge        = errors.New("attachment too large")
+	ErrAttachmentTypeNotAllowed  = errors.New("attachment type not allowed")
+	ErrAttachmentEmpty           = errors.New("attachment is empty")
+	thumbnailableAttachmentTypes = []string{"image/png", "image/jpeg", "image/gif"}
+)
+
+// AttachmentPolicy はアップロード可能なファイルのサイズと種類の制限
+type AttachmentPolicy struct {
+	maxSize          int64
+	allowedMIMETypes []string
+}
+
+func NewAttachmentPolicy(maxSize int64, allowedMIMETypes []string) AttachmentPolicy {
+	return AttachmentPolicy{maxSize: maxSize, allowedMIMETypes: allowedMIMETypes}
+}
+
+func (p AttachmentPolicy) MaxSize() int64 {
+	return p.maxSize
+}
+
+// Validate は実際の内容から判定した MIME タイプとサイズを検証する
+func (p AttachmentPolicy) Validate(size int64, mimeType string) error {
+	if size <= 0 {
+		return ErrAttachmentEmpty
+	}
+	if size > p.maxSize {
+		return ErrAttachmentTooLarge
+	}
+	if !slices.Contains(p.allowedMIMETypes, mimeType) {
+		return ErrAttachmentTypeNotAllowed
+	}
+	return nil
+}
+
+// IsThumbnailable はサムネイルを生成できる画像形式かを返す
+func IsThumbnailable(mimeType string) bool {
+	return slices.Contains(thumbnailableAttachmentTypes, mimeType)
+}
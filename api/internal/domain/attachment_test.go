@@ -0,0 +1,105 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAttachmentID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid UUID", func(t *testing.T) {
+		t.Parallel()
+		validUUID := "550e8400-e29b-41d4-a716-446655440000"
+		attachmentID, err := domain.ParseAttachmentID(validUUID)
+		require.NoError(t, err)
+		require.Equal(t, validUUID, attachmentID.String())
+	})
+
+	t.Run("invalid UUID", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := domain.ParseAttachmentID("not-a-uuid")
+		require.Error(t, err)
+	})
+}
+
+func TestNewAttachmentFileName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		input     string
+		expected  string
+		wantError bool
+	}{
+		{"plain name", "screenshot.png", "screenshot.png", false},
+		{"unicode name", "ログ.txt", "ログ.txt", false},
+		{"unix path is stripped", "/var/log/app.log", "app.log", false},
+		{"windows path is stripped", `C:\Users\me\app.log`, "app.log", false},
+		{"traversal is stripped", "../../etc/passwd", "passwd", false},
+		{"control chars are removed", "a\r\nb.txt", "ab.txt", false},
+		{"max length 255", strings.Repeat("a", 255), strings.Repeat("a", 255), false},
+		{"empty string", "", "", true},
+		{"only spaces", "   ", "", true},
+		{"parent directory only", "..", "", true},
+		{"too long 256 chars", strings.Repeat("a", 256), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fileName, err := domain.NewAttachmentFileName(tt.input)
+			if tt.wantError {
+				require.ErrorIs(t, err, domain.ErrInvalidAttachmentFileName)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expected, fileName.String())
+			}
+		})
+	}
+}
+
+func TestAttachmentPolicy_Validate(t *testing.T) {
+	t.Parallel()
+
+	policy := domain.NewAttachmentPolicy(100, []string{"image/png", "text/plain"})
+
+	tests := []struct {
+		name     string
+		size     int64
+		mimeType string
+		expected error
+	}{
+		{"allowed type", 10, "image/png", nil},
+		{"max size", 100, "text/plain", nil},
+		{"too large", 101, "image/png", domain.ErrAttachmentTooLarge},
+		{"empty", 0, "image/png", domain.ErrAttachmentEmpty},
+		{"not allowed type", 10, "application/zip", domain.ErrAttachmentTypeNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := policy.Validate(tt.size, tt.mimeType)
+			if tt.expected == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsThumbnailable(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, domain.IsThumbnailable("image/png"))
+	require.True(t, domain.IsThumbnailable("image/jpeg"))
+	require.False(t, domain.IsThumbnailable("text/plain"))
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+)
+
+// IncomingWebhookToken は受信 Webhook の URL に含める秘密のトークン
+//
+// データベースにはハッシュ値だけを保存し、トークン自体は発行時にだけ返す
+type IncomingWebhookToken struct {
+	token string
+}
+
+const incomingWebhookTokenBytes = 32
+
+var (
+	ErrInvalidIncomingWebhookToken     = errors.New("invalid incoming webhook token")
+	ErrInvalidIncomingWebhookRateLimit = errors.New("invalid incoming webhook rate limit")
+)
+
+// GenerateIncomingWebhookToken は 256 bit の乱数からトークンを生成する
+func GenerateIncomingWebhookToken() IncomingWebhookToken {
+	b := make([]byte, incomingWebhookTokenBytes)
+	// crypto/rand.Read はエラーを返さない
+	_, _ = rand.Read(b)
+	return IncomingWebhookToken{token: hex.EncodeToString(b)}
+}
+
+func ParseIncomingWebhookToken(s string) (IncomingWebhookToken, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != incomingWebhookTokenBytes {
+		return IncomingWebhookToken{}, ErrInvalidIncomingWebhookToken
+	}
+	return IncomingWebhookToken{token: s}, nil
+}
+
+func (t IncomingWebhookToken) String() string {
+	return t.token
+}
+
+// Hash は保存用の SHA-256 ハッシュを返す
+//
+// トークンは十分な長さの乱数のため、パスワードのような低速なハッシュは使わない
+func (t IncomingWebhookToken) Hash() []byte {
+	sum := sha256.Sum256([]byte(t.token))
+	return sum[:]
+}
+
+// Matches は保存されたハッシュとトークンを定数時間で比較する
+func (t IncomingWebhookToken) Matches(hash []byte) bool {
+	return subtle.ConstantTimeCompare(t.Hash(), hash) == 1
+}
+
+// IncomingWebhookRateLimit は受信 Webhook ごとの 1 分あたりの投稿数の上限
+type IncomingWebhookRateLimit struct {
+	perMinute int
+}
+
+const (
+	incomingWebhookRateLimitDefault = 60
+	incomingWebhookRateLimitMax     = 600
+)
+
+// NewIncomingWebhookRateLimit は 0 の場合に既定の 60 件とする
+func NewIncomingWebhookRateLimit(perMinute int) (IncomingWebhookRateLimit, error) {
+	if perMinute == 0 {
+		return IncomingWebhookRateLimit{perMinute: incomingWebhookRateLimitDefault}, nil
+	}
+	if perMinute < 0 || perMinute > incomingWebhookRateLimitMax {
+		return IncomingWebhookRateLimit{}, ErrInvalidIncomingWebhookRateLimit
+	}
+	return IncomingWebhookRateLimit{perMinute: perMinute}, nil
+}
+
+func (l IncomingWebhookRateLimit) PerMinute() int {
+	return l.perMinute
+}
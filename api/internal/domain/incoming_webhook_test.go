@@ -0,0 +1,90 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIncomingWebhookToken(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+	}{
+		{"valid", strings.Repeat("ab", 32), false},
+		{"empty", "", true},
+		{"too short", strings.Repeat("ab", 31), true},
+		{"not hex", strings.Repeat("zz", 32), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			token, err := domain.ParseIncomingWebhookToken(tt.input)
+			if tt.wantError {
+				require.ErrorIs(t, err, domain.ErrInvalidIncomingWebhookToken)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.input, token.String())
+			}
+		})
+	}
+}
+
+func TestIncomingWebhookToken_Matches(t *testing.T) {
+	t.Parallel()
+
+	t.Run("生成したトークンは自身のハッシュに一致する", func(t *testing.T) {
+		t.Parallel()
+
+		token := domain.GenerateIncomingWebhookToken()
+		parsed, err := domain.ParseIncomingWebhookToken(token.String())
+		require.NoError(t, err)
+		require.True(t, parsed.Matches(token.Hash()))
+	})
+
+	t.Run("異なるトークンのハッシュには一致しない", func(t *testing.T) {
+		t.Parallel()
+
+		token := domain.GenerateIncomingWebhookToken()
+		other := domain.GenerateIncomingWebhookToken()
+		require.False(t, token.Matches(other.Hash()))
+	})
+}
+
+func TestNewIncomingWebhookRateLimit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		input     int
+		expected  int
+		wantError bool
+	}{
+		{"default", 0, 60, false},
+		{"min", 1, 1, false},
+		{"max", 600, 600, false},
+		{"negative", -1, 0, true},
+		{"too large", 601, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			limit, err := domain.NewIncomingWebhookRateLimit(tt.input)
+			if tt.wantError {
+				require.ErrorIs(t, err, domain.ErrInvalidIncomingWebhookRateLimit)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expected, limit.PerMinute())
+			}
+		})
+	}
+}
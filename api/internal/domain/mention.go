@@ -0,0 +1,78 @@
+package domain
+
+import "regexp"
+
+type MentionKind string
+
+const (
+	// MentionKindUser は特定のアカウント宛てのメンション (@username)
+	MentionKindUser MentionKind = "user"
+	// MentionKindRoom はルームの全メンバー宛てのメンション (@room)
+	MentionKindRoom MentionKind = "room"
+)
+
+// roomMentionName は @room として予約されているメンション名
+const roomMentionName = "room"
+
+// mentionRegExp は @ に続く英数字を候補として切り出す
+//
+// UserName の最大長を超える候補は NewUserName で弾く
+var mentionRegExp = regexp.MustCompile(`@[a-zA-Z0-9]+`)
+
+// Mention はメッセージ本文中のメンション箇所を表す
+//
+// start, end は本文のバイトオフセット (end は含まない)
+type Mention struct {
+	kind     MentionKind
+	userName UserName
+	start    int
+	end      int
+}
+
+func (m Mention) Kind() MentionKind {
+	return m.kind
+}
+
+// UserName はユーザーメンションの宛先を返す。ルームメンションの場合はゼロ値
+func (m Mention) UserName() UserName {
+	return m.userName
+}
+
+func (m Mention) Start() int {
+	return m.start
+}
+
+func (m Mention) End() int {
+	return m.end
+}
+
+// ParseMentions はメッセージ本文からメンションを抽出する
+//
+// メールアドレスのような英数字に続く @ はメンションとして扱わない
+func ParseMentions(c MessageContent) []Mention {
+	content := c.String()
+	mentions := make([]Mention, 0)
+	for _, loc := range mentionRegExp.FindAllStringIndex(content, -1) {
+		start, end := loc[0], loc[1]
+		if start > 0 && isAlphaNumeric(content[start-1]) {
+			continue
+		}
+
+		name := content[start+1 : end]
+		if name == roomMentionName {
+			mentions = append(mentions, Mention{kind: MentionKindRoom, start: start, end: end})
+			continue
+		}
+
+		userName, err := NewUserName(name)
+		if err != nil {
+			continue
+		}
+		mentions = append(mentions, Mention{kind: MentionKindUser, userName: userName, start: start, end: end})
+	}
+	return mentions
+}
+
+func isAlphaNumeric(b byte) bool {
+	return ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z') || ('0' <= b && b <= '9')
+}
@@ -0,0 +1,56 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMentions(t *testing.T) {
+	t.Parallel()
+
+	type span struct {
+		kind     domain.MentionKind
+		userName string
+		start    int
+		end      int
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected []span
+	}{
+		{"no mention", "hello", []span{}},
+		{"single user mention", "@alice hi", []span{{domain.MentionKindUser, "alice", 0, 6}}},
+		{"mention in the middle", "hi @alice!", []span{{domain.MentionKindUser, "alice", 3, 9}}},
+		{"multiple mentions", "@alice @bob", []span{
+			{domain.MentionKindUser, "alice", 0, 6},
+			{domain.MentionKindUser, "bob", 7, 11},
+		}},
+		{"room mention", "@room deploy done", []span{{domain.MentionKindRoom, "", 0, 5}}},
+		{"mention after unicode", "こんにちは@alice", []span{{domain.MentionKindUser, "alice", 15, 21}}},
+		{"email address is ignored", "mail me at alice@example.com", []span{}},
+		{"lone at sign is ignored", "@ alice", []span{}},
+		{"too long username is ignored", "@" + strings.Repeat("a", 33), []span{}},
+		{"max length username", "@" + strings.Repeat("a", 32), []span{{domain.MentionKindUser, strings.Repeat("a", 32), 0, 33}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			content, err := domain.NewMessageContent(tt.input)
+			require.NoError(t, err)
+
+			mentions := domain.ParseMentions(content)
+			got := make([]span, 0, len(mentions))
+			for _, m := range mentions {
+				got = append(got, span{m.Kind(), m.UserName().String(), m.Start(), m.End()})
+			}
+			require.Equal(t, tt.expected, got)
+		})
+	}
+}
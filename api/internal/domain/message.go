@@ -3,7 +3,9 @@ package domain
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
@@ -52,11 +54,49 @@ func (m MessageContent) String() string {
 	return m.content
 }
 
+// Snippet は一覧表示用に空白と改行を 1 つの空白にまとめ、maxRunes 文字を超える分を "…" で省略した本文を返す
+func (m MessageContent) Snippet(maxRunes int) string {
+	s := strings.Join(strings.Fields(m.content), " ")
+	if utf8.RuneCountInString(s) <= maxRunes {
+		return s
+	}
+	return string([]rune(s)[:maxRunes]) + "…"
+}
+
+type MessageFormat string
+
+const (
+	MessageFormatPlain    MessageFormat = "plain"
+	MessageFormatMarkdown MessageFormat = "markdown"
+)
+
+var (
+	ErrInvalidMessageFormat = errors.New("invalid message format")
+)
+
+// NewMessageFormat は空文字の場合 plain として扱う
+func NewMessageFormat(s string) (MessageFormat, error) {
+	switch MessageFormat(s) {
+	case "", MessageFormatPlain:
+		return MessageFormatPlain, nil
+	case MessageFormatMarkdown:
+		return MessageFormatMarkdown, nil
+	default:
+		return "", ErrInvalidMessageFormat
+	}
+}
+
+func (f MessageFormat) String() string {
+	return string(f)
+}
+
 type Message struct {
 	id        MessageID
 	roomID    RoomID
 	senderID  AccountID
+	kind      MessageKind
 	content   MessageContent
+	event     SystemEvent
 	createdAt time.Time
 }
 
@@ -65,11 +105,27 @@ func NewMessage(id MessageID, roomID RoomID, senderID AccountID, content Message
 		id:        id,
 		roomID:    roomID,
 		senderID:  senderID,
+		kind:      MessageKindUser,
 		content:   content,
 		createdAt: createdAt,
 	}
 }
 
+// NewSystemMessage はルームに対する操作を記録するメッセージを作る
+//
+// senderID は操作したアカウント。本文にはイベントの要約が入る
+func NewSystemMessage(id MessageID, roomID RoomID, senderID AccountID, event SystemEvent, createdAt time.Time) Message {
+	return Message{
+		id:        id,
+		roomID:    roomID,
+		senderID:  senderID,
+		kind:      MessageKindSystem,
+		content:   MessageContent{content: event.Summary()},
+		event:     event,
+		createdAt: createdAt,
+	}
+}
+
 func (m Message) ID() MessageID {
 	return m.id
 }
@@ -82,10 +138,19 @@ func (m Message) SenderID() AccountID {
 	return m.senderID
 }
 
+func (m Message) Kind() MessageKind {
+	return m.kind
+}
+
 func (m Message) Content() MessageContent {
 	return m.content
 }
 
+// Event はシステムメッセージのペイロードを返す。ユーザーメッセージの場合はゼロ値
+func (m Message) Event() SystemEvent {
+	return m.event
+}
+
 func (m Message) CreatedAt() time.Time {
 	return m.createdAt
 }
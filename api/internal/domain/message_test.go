@@ -77,6 +77,64 @@ func TestNewMessageContent(t *testing.T) {
 	}
 }
 
+func TestMessageContent_Snippet(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		maxRunes int
+		expected string
+	}{
+		{"short message", "Hello", 10, "Hello"},
+		{"collapse whitespace", "  Hello\n\n  world\t!  ", 20, "Hello world !"},
+		{"exact length", "abcde", 5, "abcde"},
+		{"truncated", "abcdef", 5, "abcde…"},
+		{"truncated unicode", "こんにちは世界", 5, "こんにちは…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			content, err := domain.NewMessageContent(tt.input)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, content.Snippet(tt.maxRunes))
+		})
+	}
+}
+
+func TestNewMessageFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		input     string
+		expected  domain.MessageFormat
+		wantError bool
+	}{
+		{"empty defaults to plain", "", domain.MessageFormatPlain, false},
+		{"plain", "plain", domain.MessageFormatPlain, false},
+		{"markdown", "markdown", domain.MessageFormatMarkdown, false},
+		{"unknown", "html", "", true},
+		{"case sensitive", "Markdown", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			format, err := domain.NewMessageFormat(tt.input)
+			if tt.wantError {
+				require.ErrorIs(t, err, domain.ErrInvalidMessageFormat)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expected, format)
+			}
+		})
+	}
+}
+
 func TestNewMessage(t *testing.T) {
 	t.Parallel()
 
@@ -91,10 +149,27 @@ func TestNewMessage(t *testing.T) {
 	require.Equal(t, messageID.String(), message.ID().String())
 	require.Equal(t, roomID.String(), message.RoomID().String())
 	require.Equal(t, senderID.String(), message.SenderID().String())
+	require.Equal(t, domain.MessageKindUser, message.Kind())
 	require.Equal(t, content.String(), message.Content().String())
 	require.True(t, message.CreatedAt().Equal(now))
 }
 
+func TestNewSystemMessage(t *testing.T) {
+	t.Parallel()
+
+	messageID := domain.MessageIDFromUuid(uuid.New())
+	roomID := domain.RoomIDFromUuid(uuid.New())
+	senderID := domain.AccountIDFromUuid(uuid.New())
+	event := domain.NewMessagePinnedEvent(domain.MessageIDFromUuid(uuid.New()))
+	now := time.Now()
+
+	message := domain.NewSystemMessage(messageID, roomID, senderID, event, now)
+
+	require.Equal(t, domain.MessageKindSystem, message.Kind())
+	require.Equal(t, event, message.Event())
+	require.Equal(t, "pinned a message", message.Content().String())
+}
+
 func TestNewMessages(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// NotificationLevel はルームごとの通知の受け取り方
+type NotificationLevel string
+
+const (
+	// NotificationLevelAll は @room を含むすべてのメンションを通知する
+	NotificationLevelAll NotificationLevel = "all"
+	// NotificationLevelMentions は自分宛ての @username のみ通知する
+	NotificationLevelMentions NotificationLevel = "mentions"
+)
+
+var (
+	ErrInvalidNotificationLevel = errors.New("invalid notification level")
+)
+
+// NewNotificationLevel は空文字の場合 all として扱う
+func NewNotificationLevel(s string) (NotificationLevel, error) {
+	switch NotificationLevel(s) {
+	case "", NotificationLevelAll:
+		return NotificationLevelAll, nil
+	case NotificationLevelMentions:
+		return NotificationLevelMentions, nil
+	default:
+		return "", ErrInvalidNotificationLevel
+	}
+}
+
+func (l NotificationLevel) String() string {
+	return string(l)
+}
+
+// NotificationSettings はアカウントのルームごとの通知設定
+//
+// mutedUntil がゼロ値の場合はミュートしていない
+type NotificationSettings struct {
+	level      NotificationLevel
+	mutedUntil time.Time
+}
+
+// DefaultNotificationSettings は設定を保存していないルームの通知設定
+var DefaultNotificationSettings = NotificationSettings{level: NotificationLevelAll}
+
+func NewNotificationSettings(level NotificationLevel, mutedUntil time.Time) NotificationSettings {
+	return NotificationSettings{level: level, mutedUntil: mutedUntil}
+}
+
+func (s NotificationSettings) Level() NotificationLevel {
+	return s.level
+}
+
+func (s NotificationSettings) MutedUntil() time.Time {
+	return s.mutedUntil
+}
+
+// IsMuted は now の時点でミュート中かどうかを返す
+func (s NotificationSettings) IsMuted(now time.Time) bool {
+	return now.Before(s.mutedUntil)
+}
+
+// ShouldNotify は now の時点で kind のメンションを通知するかどうかを返す
+func (s NotificationSettings) ShouldNotify(kind MentionKind, now time.Time) bool {
+	if s.IsMuted(now) {
+		return false
+	}
+	return s.level == NotificationLevelAll || kind == MentionKindUser
+}
@@ -0,0 +1,68 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNotificationLevel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		input     string
+		expected  domain.NotificationLevel
+		wantError bool
+	}{
+		{"empty defaults to all", "", domain.NotificationLevelAll, false},
+		{"all", "all", domain.NotificationLevelAll, false},
+		{"mentions", "mentions", domain.NotificationLevelMentions, false},
+		{"unknown", "none", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			level, err := domain.NewNotificationLevel(tt.input)
+			if tt.wantError {
+				require.ErrorIs(t, err, domain.ErrInvalidNotificationLevel)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expected, level)
+			}
+		})
+	}
+}
+
+func TestNotificationSettings_ShouldNotify(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		settings domain.NotificationSettings
+		kind     domain.MentionKind
+		expected bool
+	}{
+		{"default user mention", domain.DefaultNotificationSettings, domain.MentionKindUser, true},
+		{"default room mention", domain.DefaultNotificationSettings, domain.MentionKindRoom, true},
+		{"mentions only user mention", domain.NewNotificationSettings(domain.NotificationLevelMentions, time.Time{}), domain.MentionKindUser, true},
+		{"mentions only room mention", domain.NewNotificationSettings(domain.NotificationLevelMentions, time.Time{}), domain.MentionKindRoom, false},
+		{"muted", domain.NewNotificationSettings(domain.NotificationLevelAll, now.Add(time.Hour)), domain.MentionKindUser, false},
+		{"mute expired", domain.NewNotificationSettings(domain.NotificationLevelAll, now.Add(-time.Hour)), domain.MentionKindRoom, true},
+		{"mute ends now", domain.NewNotificationSettings(domain.NotificationLevelAll, now), domain.MentionKindUser, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.expected, tt.settings.ShouldNotify(tt.kind, now))
+		})
+	}
+}
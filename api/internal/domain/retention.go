@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"errors"
+)
+
+// RetentionPeriod はメッセージを保存しておく日数
+type RetentionPeriod struct {
+	days int
+}
+
+const (
+	retentionPeriodMinDays = 1
+	retentionPeriodMaxDays = 3650
+)
+
+var (
+	ErrInvalidRetentionPeriod = errors.New("invalid retention period")
+)
+
+func NewRetentionPeriod(days int) (RetentionPeriod, error) {
+	if days < retentionPeriodMinDays || days > retentionPeriodMaxDays {
+		return RetentionPeriod{}, ErrInvalidRetentionPeriod
+	}
+	return RetentionPeriod{days: days}, nil
+}
+
+func (p RetentionPeriod) Days() int {
+	return p.days
+}
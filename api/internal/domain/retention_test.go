@@ -0,0 +1,31 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRetentionPeriod(t *testing.T) {
+	t.Parallel()
+
+	t.Run("1 日から 3650 日まで指定できる", func(t *testing.T) {
+		t.Parallel()
+
+		for _, days := range []int{1, 90, 3650} {
+			p, err := domain.NewRetentionPeriod(days)
+			require.NoError(t, err)
+			require.Equal(t, days, p.Days())
+		}
+	})
+
+	t.Run("範囲外の日数はエラーを返す", func(t *testing.T) {
+		t.Parallel()
+
+		for _, days := range []int{-1, 0, 3651} {
+			_, err := domain.NewRetentionPeriod(days)
+			require.ErrorIs(t, err, domain.ErrInvalidRetentionPeriod)
+		}
+	})
+}
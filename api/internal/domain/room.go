@@ -60,6 +60,33 @@ func (r RoomName) String() string {
 	return r.name
 }
 
+// RoomTopic はルームの説明。空文字はトピック未設定を表す
+type RoomTopic struct {
+	topic string
+}
+
+const (
+	roomTopicMaxLength = 250
+)
+
+var (
+	ErrInvalidRoomTopic = errors.New("invalid room topic")
+)
+
+func NewRoomTopic(s string) (RoomTopic, error) {
+	normalized := newlineRegExp.ReplaceAllString(s, " ")
+	normalized = strings.TrimSpace(normalized)
+
+	if len(normalized) > roomTopicMaxLength {
+		return RoomTopic{}, ErrInvalidRoomTopic
+	}
+	return RoomTopic{topic: normalized}, nil
+}
+
+func (r RoomTopic) String() string {
+	return r.topic
+}
+
 type Room struct {
 	id        RoomID
 	name      RoomName
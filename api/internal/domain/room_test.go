@@ -114,6 +114,38 @@ func TestNewRoomName(t *testing.T) {
 	}
 }
 
+func TestNewRoomTopic(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		input     string
+		expected  string
+		wantError bool
+	}{
+		{"valid topic", "Release planning", "Release planning", false},
+		{"empty clears topic", "", "", false},
+		{"only spaces clears topic", "   ", "", false},
+		{"newline converted to space", "line1\nline2", "line1 line2", false},
+		{"valid max length 250", strings.Repeat("a", 250), strings.Repeat("a", 250), false},
+		{"too long 251 chars", strings.Repeat("a", 251), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			topic, err := domain.NewRoomTopic(tt.input)
+			if tt.wantError {
+				require.ErrorIs(t, err, domain.ErrInvalidRoomTopic)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expected, topic.String())
+			}
+		})
+	}
+}
+
 func TestRoomName_String(t *testing.T) {
 	t.Parallel()
 
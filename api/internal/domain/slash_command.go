@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SlashCommandName は "/" を除いたコマンド名
+type SlashCommandName struct {
+	name string
+}
+
+var slashCommandNameRegExp = regexp.MustCompile("^[a-z][a-z0-9_-]{0,31}$")
+
+var (
+	ErrInvalidSlashCommandName        = errors.New("invalid slash command name")
+	ErrInvalidSlashCommandDescription = errors.New("invalid slash command description")
+	ErrInvalidSlashCommandVisibility  = errors.New("invalid slash command visibility")
+)
+
+func NewSlashCommandName(s string) (SlashCommandName, error) {
+	if !slashCommandNameRegExp.MatchString(s) {
+		return SlashCommandName{}, ErrInvalidSlashCommandName
+	}
+	return SlashCommandName{name: s}, nil
+}
+
+func (n SlashCommandName) String() string {
+	return n.name
+}
+
+// SlashCommand は "/deploy status" のような本文を解析したもの。Text はコマンド名の後の引数
+type SlashCommand struct {
+	name SlashCommandName
+	text string
+}
+
+// ParseSlashCommand は "/" とコマンド名で始まる本文をコマンドとして解析する
+//
+// "/path/to/file" のようにコマンド名として不正な本文は通常のメッセージとして扱うため ok が false になる
+func ParseSlashCommand(content MessageContent) (cmd SlashCommand, ok bool) {
+	rest, found := strings.CutPrefix(content.String(), "/")
+	if !found {
+		return SlashCommand{}, false
+	}
+
+	nameEnd := strings.IndexFunc(rest, unicode.IsSpace)
+	if nameEnd < 0 {
+		nameEnd = len(rest)
+	}
+	name, err := NewSlashCommandName(rest[:nameEnd])
+	if err != nil {
+		return SlashCommand{}, false
+	}
+	return SlashCommand{name: name, text: strings.TrimSpace(rest[nameEnd:])}, true
+}
+
+// EscapedSlashCommand は "//" で始まる本文から先頭の "/" を 1 つ取り除き、コマンドとして解釈しない本文を返す
+func EscapedSlashCommand(content MessageContent) (MessageContent, bool) {
+	rest, found := strings.CutPrefix(content.String(), "/")
+	if !found || !strings.HasPrefix(rest, "/") {
+		return MessageContent{}, false
+	}
+	return MessageContent{content: rest}, true
+}
+
+func (c SlashCommand) Name() SlashCommandName {
+	return c.name
+}
+
+func (c SlashCommand) Text() string {
+	return c.text
+}
+
+// SlashCommandDescription はコマンド一覧に表示する説明
+type SlashCommandDescription struct {
+	description string
+}
+
+const slashCommandDescriptionMaxLength = 255
+
+// NewSlashCommandDescription は空の説明を許す
+func NewSlashCommandDescription(s string) (SlashCommandDescription, error) {
+	if utf8.RuneCountInString(s) > slashCommandDescriptionMaxLength || !utf8.ValidString(s) {
+		return SlashCommandDescription{}, ErrInvalidSlashCommandDescription
+	}
+	return SlashCommandDescription{description: s}, nil
+}
+
+func (d SlashCommandDescription) String() string {
+	return d.description
+}
+
+// SlashCommandVisibility はコマンドの応答を誰に見せるか
+type SlashCommandVisibility string
+
+const (
+	// SlashCommandEphemeral の応答は保存せず、実行者にだけ返す
+	SlashCommandEphemeral SlashCommandVisibility = "ephemeral"
+	// SlashCommandPublic の応答はメッセージとしてルームに投稿する
+	SlashCommandPublic SlashCommandVisibility = "public"
+)
+
+// NewSlashCommandVisibility は空文字の場合 ephemeral として扱う
+func NewSlashCommandVisibility(s string) (SlashCommandVisibility, error) {
+	switch v := SlashCommandVisibility(s); v {
+	case "":
+		return SlashCommandEphemeral, nil
+	case SlashCommandEphemeral, SlashCommandPublic:
+		return v, nil
+	default:
+		return "", ErrInvalidSlashCommandVisibility
+	}
+}
+
+func (v SlashCommandVisibility) String() string {
+	return string(v)
+}
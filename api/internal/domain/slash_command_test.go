@@ -0,0 +1,135 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSlashCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		ok       bool
+		expected string
+		text     string
+	}{
+		{"command only", "/shrug", true, "shrug", ""},
+		{"with text", "/deploy status  ", true, "deploy", "status"},
+		{"with newline", "/me waves\nhello", true, "me", "waves\nhello"},
+		{"plain message", "hello /me", false, "", ""},
+		{"path", "/path/to/file", false, "", ""},
+		{"uppercase", "/Deploy", false, "", ""},
+		{"slash only", "/", false, "", ""},
+		{"space after slash", "/ me", false, "", ""},
+		{"escaped", "//me", false, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			content, err := domain.NewMessageContent(tt.input)
+			require.NoError(t, err)
+
+			cmd, ok := domain.ParseSlashCommand(content)
+			require.Equal(t, tt.ok, ok)
+			if tt.ok {
+				require.Equal(t, tt.expected, cmd.Name().String())
+				require.Equal(t, tt.text, cmd.Text())
+			}
+		})
+	}
+}
+
+func TestEscapedSlashCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		ok       bool
+		expected string
+	}{
+		{"escaped", "//me waves", true, "/me waves"},
+		{"command", "/me waves", false, ""},
+		{"plain message", "hello", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			content, err := domain.NewMessageContent(tt.input)
+			require.NoError(t, err)
+
+			escaped, ok := domain.EscapedSlashCommand(content)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.expected, escaped.String())
+		})
+	}
+}
+
+func TestNewSlashCommandName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+	}{
+		{"simple", "deploy", false},
+		{"with digits and symbols", "ci-2_status", false},
+		{"empty", "", true},
+		{"leading digit", "2fa", true},
+		{"uppercase", "Deploy", true},
+		{"too long", "a" + strings.Repeat("b", 32), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := domain.NewSlashCommandName(tt.input)
+			if tt.wantError {
+				require.ErrorIs(t, err, domain.ErrInvalidSlashCommandName)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewSlashCommandVisibility(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		input     string
+		expected  domain.SlashCommandVisibility
+		wantError bool
+	}{
+		{"empty", "", domain.SlashCommandEphemeral, false},
+		{"ephemeral", "ephemeral", domain.SlashCommandEphemeral, false},
+		{"public", "public", domain.SlashCommandPublic, false},
+		{"unknown", "in_channel", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			v, err := domain.NewSlashCommandVisibility(tt.input)
+			if tt.wantError {
+				require.ErrorIs(t, err, domain.ErrInvalidSlashCommandVisibility)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expected, v)
+			}
+		})
+	}
+}
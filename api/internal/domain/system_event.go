@@ -0,0 +1,203 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+type MessageKind string
+
+const (
+	// MessageKindUser はアカウントが投稿したメッセージ
+	MessageKindUser MessageKind = "user"
+	// MessageKindSystem はルームに対する操作を記録したメッセージ
+	MessageKindSystem MessageKind = "system"
+)
+
+type SystemEventType string
+
+const (
+	SystemEventRoomCreated      SystemEventType = "room_created"
+	SystemEventRoomRenamed      SystemEventType = "room_renamed"
+	SystemEventRoomTopicChanged SystemEventType = "room_topic_changed"
+	SystemEventRoomArchived     SystemEventType = "room_archived"
+	SystemEventRoomUnarchived   SystemEventType = "room_unarchived"
+	SystemEventRoomDeleted      SystemEventType = "room_deleted"
+	SystemEventRoomRestored     SystemEventType = "room_restored"
+	SystemEventMessagePinned    SystemEventType = "message_pinned"
+	SystemEventMessageUnpinned  SystemEventType = "message_unpinned"
+)
+
+var (
+	ErrInvalidSystemEvent = errors.New("invalid system event")
+)
+
+// SystemEvent はシステムメッセージの構造化ペイロード
+//
+// イベントの種類によって使うフィールドが異なり、使わないフィールドはゼロ値になる
+type SystemEvent struct {
+	eventType        SystemEventType
+	messageID        MessageID
+	roomName         RoomName
+	previousRoomName RoomName
+	topic            RoomTopic
+}
+
+func NewRoomCreatedEvent(name RoomName) SystemEvent {
+	return SystemEvent{eventType: SystemEventRoomCreated, roomName: name}
+}
+
+func NewRoomRenamedEvent(previous, name RoomName) SystemEvent {
+	return SystemEvent{eventType: SystemEventRoomRenamed, roomName: name, previousRoomName: previous}
+}
+
+func NewRoomTopicChangedEvent(topic RoomTopic) SystemEvent {
+	return SystemEvent{eventType: SystemEventRoomTopicChanged, topic: topic}
+}
+
+func NewRoomArchivedEvent() SystemEvent {
+	return SystemEvent{eventType: SystemEventRoomArchived}
+}
+
+func NewRoomUnarchivedEvent() SystemEvent {
+	return SystemEvent{eventType: SystemEventRoomUnarchived}
+}
+
+func NewRoomDeletedEvent() SystemEvent {
+	return SystemEvent{eventType: SystemEventRoomDeleted}
+}
+
+func NewRoomRestoredEvent() SystemEvent {
+	return SystemEvent{eventType: SystemEventRoomRestored}
+}
+
+func NewMessagePinnedEvent(messageID MessageID) SystemEvent {
+	return SystemEvent{eventType: SystemEventMessagePinned, messageID: messageID}
+}
+
+func NewMessageUnpinnedEvent(messageID MessageID) SystemEvent {
+	return SystemEvent{eventType: SystemEventMessageUnpinned, messageID: messageID}
+}
+
+func (e SystemEvent) Type() SystemEventType {
+	return e.eventType
+}
+
+// MessageID はピン留め・解除の対象メッセージを返す
+func (e SystemEvent) MessageID() MessageID {
+	return e.messageID
+}
+
+// RoomName はルーム作成時・名前変更後の名前を返す
+func (e SystemEvent) RoomName() RoomName {
+	return e.roomName
+}
+
+// PreviousRoomName は名前変更前の名前を返す
+func (e SystemEvent) PreviousRoomName() RoomName {
+	return e.previousRoomName
+}
+
+// Topic は変更後のトピックを返す。トピックが消去された場合は空
+func (e SystemEvent) Topic() RoomTopic {
+	return e.topic
+}
+
+// Summary はイベントを表示できないクライアント向けの本文
+func (e SystemEvent) Summary() string {
+	switch e.eventType {
+	case SystemEventRoomCreated:
+		return fmt.Sprintf("created the room %q", e.roomName.String())
+	case SystemEventRoomRenamed:
+		return fmt.Sprintf("renamed the room from %q to %q", e.previousRoomName.String(), e.roomName.String())
+	case SystemEventRoomTopicChanged:
+		if e.topic.String() == "" {
+			return "cleared the topic"
+		}
+		return fmt.Sprintf("changed the topic to %q", e.topic.String())
+	case SystemEventRoomArchived:
+		return "archived the room"
+	case SystemEventRoomUnarchived:
+		return "unarchived the room"
+	case SystemEventRoomDeleted:
+		return "deleted the room"
+	case SystemEventRoomRestored:
+		return "restored the room"
+	case SystemEventMessagePinned:
+		return "pinned a message"
+	case SystemEventMessageUnpinned:
+		return "unpinned a message"
+	default:
+		return ""
+	}
+}
+
+type systemEventJSON struct {
+	Type             SystemEventType `json:"type"`
+	MessageID        string          `json:"messageId,omitempty"`
+	RoomName         string          `json:"roomName,omitempty"`
+	PreviousRoomName string          `json:"previousRoomName,omitempty"`
+	Topic            string          `json:"topic,omitempty"`
+}
+
+func (e SystemEvent) MarshalJSON() ([]byte, error) {
+	v := systemEventJSON{
+		Type:             e.eventType,
+		RoomName:         e.roomName.String(),
+		PreviousRoomName: e.previousRoomName.String(),
+		Topic:            e.topic.String(),
+	}
+	if e.messageID != (MessageID{}) {
+		v.MessageID = e.messageID.String()
+	}
+	return json.Marshal(v)
+}
+
+// ParseSystemEvent は MarshalJSON で保存したペイロードを復元する
+func ParseSystemEvent(b []byte) (SystemEvent, error) {
+	var v systemEventJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return SystemEvent{}, fmt.Errorf("%w: %w", ErrInvalidSystemEvent, err)
+	}
+
+	switch v.Type {
+	case SystemEventRoomCreated:
+		name, err := NewRoomName(v.RoomName)
+		if err != nil {
+			return SystemEvent{}, fmt.Errorf("%w: %w", ErrInvalidSystemEvent, err)
+		}
+		return NewRoomCreatedEvent(name), nil
+
+	case SystemEventRoomRenamed:
+		name, err := NewRoomName(v.RoomName)
+		if err != nil {
+			return SystemEvent{}, fmt.Errorf("%w: %w", ErrInvalidSystemEvent, err)
+		}
+		previous, err := NewRoomName(v.PreviousRoomName)
+		if err != nil {
+			return SystemEvent{}, fmt.Errorf("%w: %w", ErrInvalidSystemEvent, err)
+		}
+		return NewRoomRenamedEvent(previous, name), nil
+
+	case SystemEventRoomTopicChanged:
+		topic, err := NewRoomTopic(v.Topic)
+		if err != nil {
+			return SystemEvent{}, fmt.Errorf("%w: %w", ErrInvalidSystemEvent, err)
+		}
+		return NewRoomTopicChangedEvent(topic), nil
+
+	case SystemEventRoomArchived, SystemEventRoomUnarchived, SystemEventRoomDeleted, SystemEventRoomRestored:
+		return SystemEvent{eventType: v.Type}, nil
+
+	case SystemEventMessagePinned, SystemEventMessageUnpinned:
+		id, err := ParseMessageID(v.MessageID)
+		if err != nil {
+			return SystemEvent{}, fmt.Errorf("%w: %w", ErrInvalidSystemEvent, err)
+		}
+		return SystemEvent{eventType: v.Type, messageID: id}, nil
+
+	default:
+		return SystemEvent{}, fmt.Errorf("%w: unknown type %q", ErrInvalidSystemEvent, v.Type)
+	}
+}
@@ -0,0 +1,126 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemEvent_JSON(t *testing.T) {
+	t.Parallel()
+
+	roomName, _ := domain.NewRoomName("General")
+	newRoomName, _ := domain.NewRoomName("Random")
+	topic, _ := domain.NewRoomTopic("Release planning")
+	messageID := domain.MessageIDFromUuid(uuid.MustParse("550e8400-e29b-41d4-a716-446655440000"))
+
+	tests := []struct {
+		name     string
+		event    domain.SystemEvent
+		expected string
+		summary  string
+	}{
+		{
+			"room created",
+			domain.NewRoomCreatedEvent(roomName),
+			`{"type":"room_created","roomName":"General"}`,
+			`created the room "General"`,
+		},
+		{
+			"room renamed",
+			domain.NewRoomRenamedEvent(roomName, newRoomName),
+			`{"type":"room_renamed","roomName":"Random","previousRoomName":"General"}`,
+			`renamed the room from "General" to "Random"`,
+		},
+		{
+			"room topic changed",
+			domain.NewRoomTopicChangedEvent(topic),
+			`{"type":"room_topic_changed","topic":"Release planning"}`,
+			`changed the topic to "Release planning"`,
+		},
+		{
+			"room topic cleared",
+			domain.NewRoomTopicChangedEvent(domain.RoomTopic{}),
+			`{"type":"room_topic_changed"}`,
+			"cleared the topic",
+		},
+		{
+			"room archived",
+			domain.NewRoomArchivedEvent(),
+			`{"type":"room_archived"}`,
+			"archived the room",
+		},
+		{
+			"room unarchived",
+			domain.NewRoomUnarchivedEvent(),
+			`{"type":"room_unarchived"}`,
+			"unarchived the room",
+		},
+		{
+			"room deleted",
+			domain.NewRoomDeletedEvent(),
+			`{"type":"room_deleted"}`,
+			"deleted the room",
+		},
+		{
+			"room restored",
+			domain.NewRoomRestoredEvent(),
+			`{"type":"room_restored"}`,
+			"restored the room",
+		},
+		{
+			"message pinned",
+			domain.NewMessagePinnedEvent(messageID),
+			`{"type":"message_pinned","messageId":"550e8400-e29b-41d4-a716-446655440000"}`,
+			"pinned a message",
+		},
+		{
+			"message unpinned",
+			domain.NewMessageUnpinnedEvent(messageID),
+			`{"type":"message_unpinned","messageId":"550e8400-e29b-41d4-a716-446655440000"}`,
+			"unpinned a message",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			b, err := json.Marshal(tt.event)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.expected, string(b))
+			require.Equal(t, tt.summary, tt.event.Summary())
+
+			parsed, err := domain.ParseSystemEvent(b)
+			require.NoError(t, err)
+			require.Equal(t, tt.event, parsed)
+		})
+	}
+}
+
+func TestParseSystemEvent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"not json", "{"},
+		{"unknown type", `{"type":"unknown"}`},
+		{"pinned without message id", `{"type":"message_pinned"}`},
+		{"room created without name", `{"type":"room_created"}`},
+		{"room renamed without previous name", `{"type":"room_renamed","roomName":"Random"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := domain.ParseSystemEvent([]byte(tt.input))
+			require.ErrorIs(t, err, domain.ErrInvalidSystemEvent)
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// WebhookEventType は送信 Webhook で購読できるイベントの種類
+type WebhookEventType string
+
+const (
+	WebhookEventMessageCreated WebhookEventType = "message.created"
+	// WebhookEventRoomUpdated はルーム名・トピックの変更
+	WebhookEventRoomUpdated    WebhookEventType = "room.updated"
+	WebhookEventRoomArchived   WebhookEventType = "room.archived"
+	WebhookEventRoomUnarchived WebhookEventType = "room.unarchived"
+	WebhookEventRoomDeleted    WebhookEventType = "room.deleted"
+)
+
+var (
+	ErrInvalidWebhookEventType = errors.New("invalid webhook event type")
+	ErrInvalidWebhookURL       = errors.New("invalid webhook url")
+	ErrWebhookURLNotAllowed    = errors.New("webhook url points to a private network")
+	ErrInvalidWebhookSecret    = errors.New("invalid webhook secret")
+)
+
+func NewWebhookEventType(s string) (WebhookEventType, error) {
+	switch t := WebhookEventType(s); t {
+	case WebhookEventMessageCreated,
+		WebhookEventRoomUpdated,
+		WebhookEventRoomArchived,
+		WebhookEventRoomUnarchived,
+		WebhookEventRoomDeleted:
+		return t, nil
+	default:
+		return "", ErrInvalidWebhookEventType
+	}
+}
+
+func (t WebhookEventType) String() string {
+	return string(t)
+}
+
+// WebhookURL は送信先の URL。http または https の絶対 URL のみ受け付ける
+type WebhookURL struct {
+	url string
+}
+
+const webhookURLMaxLength = 2048
+
+// NewWebhookURL は認証情報を含む URL を拒否する。署名で検証できるため送信先に資格情報を埋め込む必要はない
+func NewWebhookURL(s string) (WebhookURL, error) {
+	if s == "" || len(s) > webhookURLMaxLength {
+		return WebhookURL{}, ErrInvalidWebhookURL
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return WebhookURL{}, ErrInvalidWebhookURL
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User != nil || u.Fragment != "" {
+		return WebhookURL{}, ErrInvalidWebhookURL
+	}
+	return WebhookURL{url: u.String()}, nil
+}
+
+func (u WebhookURL) String() string {
+	return u.url
+}
+
+// WebhookNetworkPolicy は送信 Webhook とスラッシュコマンドの送信先に許可するネットワーク
+//
+// 既定ではグローバルユニキャストのうち、プライベートと deniedPrefixes を除いたアドレスにだけ送信し、
+// サーバーから内部のネットワークへリクエストを送らせないようにする
+type WebhookNetworkPolicy struct {
+	allowPrivateNetworks bool
+}
+
+// NewWebhookNetworkPolicy の allowPrivateNetworks は開発環境などで内部のネットワークへの送信を許可する場合に true
+func NewWebhookNetworkPolicy(allowPrivateNetworks bool) WebhookNetworkPolicy {
+	return WebhookNetworkPolicy{allowPrivateNetworks: allowPrivateNetworks}
+}
+
+// deniedPrefixes はグローバルユニキャストに含まれるが、内部のネットワークに届きうるアドレス
+var deniedPrefixes = []netip.Prefix{
+	// "this network"。Linux では 0.0.0.0/8 への接続がローカルホストに届く
+	netip.MustParsePrefix("0.0.0.0/8"),
+	// Carrier-grade NAT の共有アドレス
+	netip.MustParsePrefix("100.64.0.0/10"),
+	// IETF プロトコルの割り当て
+	netip.MustParsePrefix("192.0.0.0/24"),
+	// ベンチマーク用
+	netip.MustParsePrefix("198.18.0.0/15"),
+	// 将来の利用のために予約
+	netip.MustParsePrefix("240.0.0.0/4"),
+	// NAT64 と 6to4 は IPv4 のアドレスを埋め込むため、内部の IPv4 アドレスに変換されうる
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("2002::/16"),
+}
+
+// AllowsAddr は ip に送信してよいかを返す
+//
+// ループバック、リンクローカル、マルチキャスト、未指定のアドレスはグローバルユニキャストに含まれないため拒否する
+func (p WebhookNetworkPolicy) AllowsAddr(ip netip.Addr) bool {
+	if p.allowPrivateNetworks {
+		return true
+	}
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, prefix := range deniedPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// AllowsURL は u のホストが IP アドレスか localhost の場合に送信してよいかを返す
+//
+// ホスト名は登録後に解決先が変わりうるため、解決したアドレスは送信するときに AllowsAddr で検証する
+func (p WebhookNetworkPolicy) AllowsURL(u WebhookURL) bool {
+	if p.allowPrivateNetworks {
+		return true
+	}
+	parsed, err := url.Parse(u.url)
+	if err != nil {
+		return false
+	}
+	host := strings.TrimSuffix(strings.ToLower(parsed.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if ip, err := netip.ParseAddr(host); err == nil {
+		return p.AllowsAddr(ip)
+	}
+	return true
+}
+
+// WebhookSecret は署名に使う共有鍵
+type WebhookSecret struct {
+	secret string
+}
+
+const (
+	webhookSecretMinLength = 16
+	webhookSecretMaxLength = 255
+)
+
+func NewWebhookSecret(s string) (WebhookSecret, error) {
+	if len(s) < webhookSecretMinLength || len(s) > webhookSecretMaxLength || !utf8.ValidString(s) {
+		return WebhookSecret{}, ErrInvalidWebhookSecret
+	}
+	return WebhookSecret{secret: s}, nil
+}
+
+// GenerateWebhookSecret は 256 bit の乱数から共有鍵を生成する
+func GenerateWebhookSecret() WebhookSecret {
+	b := make([]byte, 32)
+	// crypto/rand.Read はエラーを返さない
+	_, _ = rand.Read(b)
+	return WebhookSecret{secret: hex.EncodeToString(b)}
+}
+
+func (s WebhookSecret) String() string {
+	return s.secret
+}
+
+// SignWebhookPayload は送信時刻と本文に対する HMAC-SHA256 の署名を "sha256=<hex>" の形式で返す
+//
+// 署名対象は "<UNIX 秒>.<本文>" で、受信側は時刻を確認することで古いリクエストの再送を拒否できる
+func SignWebhookPayload(secret string, timestamp time.Time, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookRetryPolicy は送信に失敗した配信の再送間隔と回数の上限
+type WebhookRetryPolicy struct {
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+}
+
+func NewWebhookRetryPolicy(maxAttempts int, baseDelay, maxDelay time.Duration) WebhookRetryPolicy {
+	return WebhookRetryPolicy{
+		maxAttempts: maxAttempts,
+		baseDelay:   baseDelay,
+		maxDelay:    maxDelay,
+	}
+}
+
+// NextDelay は attempts 回目の送信に失敗した後、次の送信までの待ち時間を返す
+//
+// 待ち時間は失敗するごとに倍になり maxDelay で頭打ちになる。ok が false の場合はこれ以上再送しない
+func (p WebhookRetryPolicy) NextDelay(attempts int) (delay time.Duration, ok bool) {
+	if attempts >= p.maxAttempts {
+		return 0, false
+	}
+	delay = p.baseDelay
+	for i := 1; i < attempts && delay < p.maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, p.maxDelay), true
+}
@@ -0,0 +1,126 @@
+// Package health はプロセスとその依存の状態を表す
+package health
+
+import (
+	"context"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/quietsato/toy-small-chat/api/internal/db/migrate"
+)
+
+// State はプロセスの起動日時と、終了処理中かどうか
+type State struct {
+	startedAt time.Time
+	draining  atomic.Bool
+}
+
+func NewState(startedAt time.Time) *State {
+	return &State{startedAt: startedAt}
+}
+
+func (s *State) StartedAt() time.Time {
+	return s.startedAt
+}
+
+// StartDraining は終了処理を始めたことを記録する。以降はリクエストを受け付けられないものとして扱う
+func (s *State) StartDraining() {
+	s.draining.Store(true)
+}
+
+func (s *State) Draining() bool {
+	return s.draining.Load()
+}
+
+// PoolStat はデータベースのコネクションプールの統計
+type PoolStat struct {
+	TotalConns           int32         `json:"totalConns"`
+	IdleConns            int32         `json:"idleConns"`
+	AcquiredConns        int32         `json:"acquiredConns"`
+	ConstructingConns    int32         `json:"constructingConns"`
+	MaxConns             int32         `json:"maxConns"`
+	AcquireCount         int64         `json:"acquireCount"`
+	EmptyAcquireCount    int64         `json:"emptyAcquireCount"`
+	CanceledAcquireCount int64         `json:"canceledAcquireCount"`
+	AcquireDuration      time.Duration `json:"-"`
+}
+
+// DatabaseChecker はデータベースが使える状態かを確認する
+type DatabaseChecker interface {
+	Ping(ctx context.Context) error
+	// PendingMigrations はバイナリに含まれるが未適用のマイグレーションのバージョンを返す
+	PendingMigrations(ctx context.Context) ([]int64, error)
+	Stat() PoolStat
+}
+
+type databaseCheckerOnDB struct {
+	pool     *pgxpool.Pool
+	migrator *migrate.Migrator
+}
+
+func NewDatabaseCheckerOnDB(pool *pgxpool.Pool, migrations []migrate.Migration) DatabaseChecker {
+	return &databaseCheckerOnDB{pool, migrate.New(pool, migrations)}
+}
+
+func (c *databaseCheckerOnDB) Ping(ctx context.Context) error {
+	return c.pool.Ping(ctx)
+}
+
+func (c *databaseCheckerOnDB) PendingMigrations(ctx context.Context) ([]int64, error) {
+	pending, err := c.migrator.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]int64, 0, len(pending))
+	for _, mig := range pending {
+		versions = append(versions, mig.Version)
+	}
+	return versions, nil
+}
+
+func (c *databaseCheckerOnDB) Stat() PoolStat {
+	s := c.pool.Stat()
+	return PoolStat{
+		TotalConns:           s.TotalConns(),
+		IdleConns:            s.IdleConns(),
+		AcquiredConns:        s.AcquiredConns(),
+		ConstructingConns:    s.ConstructingConns(),
+		MaxConns:             s.MaxConns(),
+		AcquireCount:         s.AcquireCount(),
+		EmptyAcquireCount:    s.EmptyAcquireCount(),
+		CanceledAcquireCount: s.CanceledAcquireCount(),
+		AcquireDuration:      s.AcquireDuration(),
+	}
+}
+
+// BuildInfo はバイナリのビルド情報
+type BuildInfo struct {
+	GoVersion string `json:"goVersion"`
+	Version   string `json:"version"`
+	// Revision, RevisionTime, Modified は VCS の情報で、VCS の外でビルドした場合は空
+	Revision     string `json:"revision"`
+	RevisionTime string `json:"revisionTime"`
+	Modified     bool   `json:"modified"`
+}
+
+// ReadBuildInfo はバイナリに埋め込まれたビルド情報を返す
+func ReadBuildInfo() BuildInfo {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return BuildInfo{}
+	}
+	info := BuildInfo{GoVersion: bi.GoVersion, Version: bi.Main.Version}
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			info.Revision = s.Value
+		case "vcs.time":
+			info.RevisionTime = s.Value
+		case "vcs.modified":
+			info.Modified = s.Value == "true"
+		}
+	}
+	return info
+}
@@ -0,0 +1,58 @@
+package instrument
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// MessageSource is the way a message was posted
+type MessageSource string
+
+const (
+	MessageSourceUser            MessageSource = "user"
+	MessageSourceIncomingWebhook MessageSource = "incoming_webhook"
+	MessageSourceScheduled       MessageSource = "scheduled"
+)
+
+// Counters of application events
+//
+// Instruments created from the global meter before the MeterProvider is set are delegated to it once set.
+// The global meter returns no-op instruments instead of failing, so errors are ignored
+var (
+	meter = otel.Meter("github.com/quietsato/toy-small-chat/api/internal/instrument")
+
+	messagesCreated, _ = meter.Int64Counter("chat.messages.created",
+		metric.WithDescription("Number of messages created"),
+		metric.WithUnit("{message}"),
+	)
+	logins, _ = meter.Int64Counter("chat.logins",
+		metric.WithDescription("Number of login attempts with valid input, by result"),
+		metric.WithUnit("{login}"),
+	)
+	accountsCreated, _ = meter.Int64Counter("chat.accounts.created",
+		metric.WithDescription("Number of accounts created"),
+		metric.WithUnit("{account}"),
+	)
+)
+
+// RecordMessageCreated counts a stored message
+func RecordMessageCreated(ctx context.Context, source MessageSource) {
+	messagesCreated.Add(ctx, 1, metric.WithAttributes(attribute.String("source", string(source))))
+}
+
+// RecordLogin counts a login attempt by its result
+func RecordLogin(ctx context.Context, succeeded bool) {
+	result := "failed"
+	if succeeded {
+		result = "succeeded"
+	}
+	logins.Add(ctx, 1, metric.WithAttributes(attribute.String("result", result)))
+}
+
+// RecordAccountCreated counts a created account
+func RecordAccountCreated(ctx context.Context) {
+	accountsCreated.Add(ctx, 1)
+}
@@ -2,13 +2,14 @@ package db
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/exaring/otelpgx"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.opentelemetry.io/otel"
 )
 
-// NewPool creates a new pgxpool.Pool with OpenTelemetry tracing enabled
+// NewPool creates a new pgxpool.Pool with OpenTelemetry tracing and pool metrics enabled
 func NewPool(ctx context.Context, connString string) (*pgxpool.Pool, error) {
 	config, err := pgxpool.ParseConfig(connString)
 	if err != nil {
@@ -25,5 +26,11 @@ func NewPool(ctx context.Context, connString string) (*pgxpool.Pool, error) {
 		return nil, err
 	}
 
+	// Record pool statistics such as idle and acquired connections as gauges
+	if err := otelpgx.RecordStats(pool); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to record pool stats: %w", err)
+	}
+
 	return pool, nil
 }
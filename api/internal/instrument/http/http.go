@@ -2,18 +2,29 @@ package http
 
 import (
 	"net/http"
+	"strings"
 
+	"github.com/go-chi/chi/v5"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// spanName names the server span by method only until the route is matched
+//
+// The URL path contains room and message IDs, which would create a span name per resource
+func spanName(operation string, r *http.Request) string {
+	return r.Method
+}
+
 // NewHandler wraps an http.Handler with OpenTelemetry tracing
-func NewHandler(handler http.Handler, serviceName string) http.Handler {
+//
+// opts are passed to otelhttp after the defaults of this package
+func NewHandler(handler http.Handler, serviceName string, opts ...otelhttp.Option) http.Handler {
 	return otelhttp.NewHandler(
 		handler,
 		serviceName,
-		otelhttp.WithSpanNameFormatter(func(operation string, r *http.Request) string {
-			return r.Method + " " + r.URL.Path
-		}),
+		append([]otelhttp.Option{otelhttp.WithSpanNameFormatter(spanName)}, opts...)...,
 	)
 }
 
@@ -23,9 +34,44 @@ func Middleware(serviceName string) func(next http.Handler) http.Handler {
 		return otelhttp.NewHandler(
 			next,
 			serviceName,
-			otelhttp.WithSpanNameFormatter(func(operation string, r *http.Request) string {
-				return r.Method + " " + r.URL.Path
-			}),
+			otelhttp.WithSpanNameFormatter(spanName),
 		)
 	}
 }
+
+// RouteTagger is a chi middleware that names the server span "METHOD /route/pattern"
+// and adds the matched route pattern as http.route to the span and the otelhttp metrics
+//
+// The pattern contains no path parameters, so the number of span names and series does not grow with rooms or messages.
+// It must be registered on the root router of a handler wrapped by NewHandler
+func RouteTagger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+
+		pattern := RoutePattern(r)
+		if pattern == "" {
+			return
+		}
+		span := trace.SpanFromContext(r.Context())
+		span.SetName(r.Method + " " + pattern)
+		span.SetAttributes(semconv.HTTPRoute(pattern))
+		labeler, _ := otelhttp.LabelerFromContext(r.Context())
+		labeler.Add(semconv.HTTPRoute(pattern))
+	})
+}
+
+// RoutePattern returns the chi route pattern matched by r, such as /v1/rooms/{roomID}
+//
+// Routes registered as "/" on a sub-router have a trailing slash in chi, which is removed.
+// It returns an empty string before routing or when r is not served by chi
+func RoutePattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return ""
+	}
+	pattern := rctx.RoutePattern()
+	if len(pattern) > 1 {
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+	return pattern
+}
@@ -0,0 +1,102 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	instrumenthttp "github.com/quietsato/toy-small-chat/api/internal/instrument/http"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// requestRoutes は http.server.request.duration の系列ごとの http.route と件数を返す
+func requestRoutes(t *testing.T, reader sdkmetric.Reader) map[string]uint64 {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(t.Context(), &rm))
+
+	routes := map[string]uint64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "http.server.request.duration" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Histogram[float64]).DataPoints {
+				route, _ := dp.Attributes.Value("http.route")
+				routes[route.AsString()] += dp.Count
+			}
+		}
+	}
+	return routes
+}
+
+func TestRouteTagger(t *testing.T) {
+	t.Parallel()
+
+	t.Run("リクエストのメトリクスにパスパラメータを含まないルートパターンを付ける", func(t *testing.T) {
+		t.Parallel()
+
+		reader := sdkmetric.NewManualReader()
+		mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+		r := chi.NewRouter()
+		r.Use(instrumenthttp.RouteTagger)
+		r.Route("/v1/rooms", func(r chi.Router) {
+			r.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+			r.Get("/{roomID}/messages", func(w http.ResponseWriter, r *http.Request) {})
+		})
+		handler := otelhttp.NewHandler(r, "test", otelhttp.WithMeterProvider(mp))
+
+		for _, path := range []string{"/v1/rooms", "/v1/rooms/a/messages", "/v1/rooms/b/messages", "/unknown"} {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		}
+
+		require.Equal(t, map[string]uint64{
+			"/v1/rooms":                   1,
+			"/v1/rooms/{roomID}/messages": 2,
+			// ルートに一致しなかったリクエストには付けない
+			"": 1,
+		}, requestRoutes(t, reader))
+	})
+
+	t.Run("スパン名にパスパラメータを含まないルートパターンを使う", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := tracetest.NewSpanRecorder()
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+		r := chi.NewRouter()
+		r.Use(instrumenthttp.RouteTagger)
+		r.Post("/v1/rooms/{roomID}/messages", func(w http.ResponseWriter, r *http.Request) {})
+		handler := instrumenthttp.NewHandler(r, "test", otelhttp.WithTracerProvider(tp))
+
+		for _, path := range []string{"/v1/rooms/a/messages", "/v1/rooms/b/messages", "/unknown"} {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, path, nil))
+		}
+
+		names := []string{}
+		routes := []string{}
+		for _, span := range recorder.Ended() {
+			names = append(names, span.Name())
+			for _, attr := range span.Attributes() {
+				if attr.Key == "http.route" {
+					routes = append(routes, attr.Value.AsString())
+				}
+			}
+		}
+		require.Equal(t, []string{
+			"POST /v1/rooms/{roomID}/messages",
+			"POST /v1/rooms/{roomID}/messages",
+			// ルートに一致しなかったリクエストはメソッドだけにする
+			"POST",
+		}, names)
+		require.Equal(t, []string{"/v1/rooms/{roomID}/messages", "/v1/rooms/{roomID}/messages"}, routes)
+	})
+}
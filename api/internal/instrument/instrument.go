@@ -3,6 +3,7 @@ package instrument
 import (
 	"context"
 	"log/slog"
+	"net/http"
 	"os"
 
 	slogmulti "github.com/samber/slog-multi"
@@ -13,15 +14,18 @@ func setupLogger(logLevel slog.Level) {
 	customLogger := slog.New(
 		slogmulti.Fanout(
 			otelslog.NewHandler("toy-small-chat"),
-			slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+			NewTraceContextHandler(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
 				Level: logLevel,
-			}),
+			})),
 		),
 	)
 	slog.SetDefault(customLogger)
 }
 
-func Init(ctx context.Context, logLevel slog.Level, otlpEndpoint string) func(ctx context.Context) {
+// Init initializes logs, traces and metrics, and returns a shutdown function and a handler serving metrics in the Prometheus format
+//
+// The handler is nil when prometheus is false
+func Init(ctx context.Context, logLevel slog.Level, otlpEndpoint string, prometheus bool) (func(ctx context.Context), http.Handler) {
 	setupLogger(logLevel)
 
 	resource, _ := NewResource()
@@ -35,6 +39,11 @@ func Init(ctx context.Context, logLevel slog.Level, otlpEndpoint string) func(ct
 		slog.Error("failed to initialize logger", slog.Any("err", err))
 	}
 
+	meterProvider, metricsHandler, err := InitMeter(ctx, resource, otlpEndpoint, prometheus)
+	if err != nil {
+		slog.Error("failed to initialize meter", slog.Any("err", err))
+	}
+
 	return func(ctx context.Context) {
 		if err := shutdown(ctx); err != nil {
 			slog.WarnContext(ctx, "failed to shutdown tracer", slog.Any("err", err))
@@ -42,5 +51,10 @@ func Init(ctx context.Context, logLevel slog.Level, otlpEndpoint string) func(ct
 		if err := loggerProvider.Shutdown(ctx); err != nil {
 			slog.WarnContext(ctx, "failed to shutdown logger provider", slog.Any("err", err))
 		}
-	}
+		if meterProvider != nil {
+			if err := meterProvider.Shutdown(ctx); err != nil {
+				slog.WarnContext(ctx, "failed to shutdown meter provider", slog.Any("err", err))
+			}
+		}
+	}, metricsHandler
 }
@@ -0,0 +1,67 @@
+package instrument
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// InitMeter initializes the OpenTelemetry meter provider exporting over OTLP
+//
+// When prometheus is true, metrics are also exposed in the Prometheus format and the returned handler serves them
+func InitMeter(ctx context.Context, res *resource.Resource, otlpEndpoint string, prometheus bool) (*sdkmetric.MeterProvider, http.Handler, error) {
+	metricExporter, err := otlpmetricgrpc.New(ctx,
+		otlpmetricgrpc.WithEndpoint(otlpEndpoint),
+		otlpmetricgrpc.WithInsecure(),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create metric exporter: %w", err)
+	}
+
+	opts := []sdkmetric.Option{
+		sdkmetric.WithResource(res),
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter, sdkmetric.WithInterval(15*time.Second))),
+	}
+
+	var handler http.Handler
+	if prometheus {
+		reader, h, err := newPrometheusReader()
+		if err != nil {
+			return nil, nil, err
+		}
+		opts = append(opts, sdkmetric.WithReader(reader))
+		handler = h
+	}
+
+	mp := sdkmetric.NewMeterProvider(opts...)
+	otel.SetMeterProvider(mp)
+
+	slog.Info("OpenTelemetry meter initialized", slog.String("endpoint", otlpEndpoint), slog.Bool("prometheus", prometheus))
+	return mp, handler, nil
+}
+
+// newPrometheusReader creates a reader that is collected on each scrape, together with Go runtime and process metrics
+func newPrometheusReader() (sdkmetric.Reader, http.Handler, error) {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	exporter, err := otelprometheus.New(otelprometheus.WithRegisterer(reg))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create prometheus exporter: %w", err)
+	}
+	return exporter, promhttp.HandlerFor(reg, promhttp.HandlerOpts{}), nil
+}
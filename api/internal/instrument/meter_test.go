@@ -0,0 +1,43 @@
+package instrument_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/quietsato/toy-small-chat/api/internal/instrument"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func TestInitMeter(t *testing.T) {
+	// グローバルの MeterProvider を設定するため並列にしない
+
+	t.Run("MeterProvider を設定する前に作ったカウンタも Prometheus 形式で公開する", func(t *testing.T) {
+		// OTLP の送信先には接続しない
+		_, handler, err := instrument.InitMeter(t.Context(), resource.Empty(), "127.0.0.1:0", true)
+		require.NoError(t, err)
+		require.NotNil(t, handler)
+
+		instrument.RecordMessageCreated(t.Context(), instrument.MessageSourceIncomingWebhook)
+		instrument.RecordLogin(t.Context(), false)
+		instrument.RecordAccountCreated(t.Context())
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		body := rr.Body.String()
+		require.Regexp(t, `chat_messages_created_total\{[^}]*source="incoming_webhook"[^}]*\} 1`, body)
+		require.Regexp(t, `chat_logins_total\{[^}]*result="failed"[^}]*\} 1`, body)
+		require.Regexp(t, `chat_accounts_created_total\{[^}]*\} 1`, body)
+		require.Contains(t, body, "go_goroutines")
+	})
+
+	t.Run("Prometheus を使わない場合はハンドラを返さない", func(t *testing.T) {
+		_, handler, err := instrument.InitMeter(t.Context(), resource.Empty(), "127.0.0.1:0", false)
+
+		require.NoError(t, err)
+		require.Nil(t, handler)
+	})
+}
@@ -0,0 +1,40 @@
+package instrument
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// traceContextHandler adds trace_id and span_id to records logged with a context that carries a span
+//
+// otelslog correlates records with traces by itself; this is for handlers such as stdout that know nothing about OpenTelemetry,
+// so that a log line can be looked up in the tracing backend
+type traceContextHandler struct {
+	slog.Handler
+}
+
+// NewTraceContextHandler wraps h so that records logged inside a span carry its trace_id and span_id
+func NewTraceContextHandler(h slog.Handler) slog.Handler {
+	return &traceContextHandler{h}
+}
+
+func (h *traceContextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r = r.Clone()
+		r.AddAttrs(
+			slog.String("trace_id", sc.TraceID().String()),
+			slog.String("span_id", sc.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *traceContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &traceContextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h *traceContextHandler) WithGroup(name string) slog.Handler {
+	return &traceContextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,46 @@
+package instrument_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/quietsato/toy-small-chat/api/internal/instrument"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestTraceContextHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("スパンの中で出したログにトレース ID とスパン ID を付ける", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		logger := slog.New(instrument.NewTraceContextHandler(slog.NewTextHandler(&buf, nil))).With(slog.String("component", "test"))
+
+		tp := sdktrace.NewTracerProvider()
+		ctx, span := tp.Tracer("test").Start(t.Context(), "test")
+		defer span.End()
+
+		logger.InfoContext(ctx, "hello")
+
+		sc := span.SpanContext()
+		require.Contains(t, buf.String(), "component=test")
+		require.Contains(t, buf.String(), "trace_id="+sc.TraceID().String())
+		require.Contains(t, buf.String(), "span_id="+sc.SpanID().String())
+	})
+
+	t.Run("スパンがない場合は何も付けない", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		logger := slog.New(instrument.NewTraceContextHandler(slog.NewTextHandler(&buf, nil)))
+
+		logger.InfoContext(context.Background(), "hello")
+
+		require.NotContains(t, buf.String(), "trace_id")
+		require.NotContains(t, buf.String(), "span_id")
+	})
+}
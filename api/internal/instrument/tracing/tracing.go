@@ -0,0 +1,52 @@
+// Package tracing provides helpers for adding spans and span attributes outside of the HTTP layer
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+
+// Attribute keys for the IDs of the resources a request works on
+const (
+	RoomIDKey    = attribute.Key("chat.room.id")
+	MessageIDKey = attribute.Key("chat.message.id")
+)
+
+// Execute runs a usecase inside a child span named name, such as "CreateRoomUsecase"
+//
+// An error returned by fn is recorded on the span and sets its status to Error
+func Execute[I, O any](ctx context.Context, name string, fn func(context.Context, I) (O, error), inp I) (O, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attribute.String("usecase", name)))
+	defer span.End()
+
+	out, err := fn(ctx, inp)
+	recordError(span, err)
+	return out, err
+}
+
+// ExecuteNoOutput is Execute for usecases that return only an error
+func ExecuteNoOutput[I any](ctx context.Context, name string, fn func(context.Context, I) error, inp I) error {
+	_, err := Execute(ctx, name, func(ctx context.Context, inp I) (struct{}, error) {
+		return struct{}{}, fn(ctx, inp)
+	}, inp)
+	return err
+}
+
+// SetAttributes adds attributes to the span in ctx, if any
+func SetAttributes(ctx context.Context, attrs ...attribute.KeyValue) {
+	trace.SpanFromContext(ctx).SetAttributes(attrs...)
+}
+
+func recordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,60 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestExecute(t *testing.T) {
+	// グローバルの TracerProvider を設定するため並列にしない
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	t.Run("リクエストのスパンの子としてユースケースのスパンを作る", func(t *testing.T) {
+		ctx, parent := tp.Tracer("test").Start(t.Context(), "parent")
+		tracing.SetAttributes(ctx, tracing.RoomIDKey.String("room-1"))
+
+		out, err := tracing.Execute(ctx, "CreateRoomUsecase", func(ctx context.Context, inp string) (string, error) {
+			return inp + "!", nil
+		}, "room")
+		parent.End()
+
+		require.NoError(t, err)
+		require.Equal(t, "room!", out)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 2)
+		require.Equal(t, "CreateRoomUsecase", spans[0].Name())
+		require.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+		require.Equal(t, codes.Unset, spans[0].Status().Code)
+		require.Contains(t, spans[1].Attributes(), tracing.RoomIDKey.String("room-1"))
+	})
+
+	t.Run("ユースケースのエラーをスパンに記録する", func(t *testing.T) {
+		wantErr := errors.New("room not found")
+		err := tracing.ExecuteNoOutput(t.Context(), "DeleteRoomUsecase", func(ctx context.Context, inp string) error {
+			return wantErr
+		}, "room")
+
+		require.ErrorIs(t, err, wantErr)
+
+		spans := recorder.Ended()
+		span := spans[len(spans)-1]
+		require.Equal(t, "DeleteRoomUsecase", span.Name())
+		require.Equal(t, codes.Error, span.Status().Code)
+		require.Equal(t, "room not found", span.Status().Description)
+		require.Len(t, span.Events(), 1)
+		require.Equal(t, "exception", span.Events()[0].Name)
+	})
+}
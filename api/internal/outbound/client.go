@@ -0,0 +1,53 @@
+// Package outbound は利用者が登録した URL へリクエストを送る HTTP クライアントを作る
+package outbound
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+)
+
+var ErrAddressNotAllowed = errors.New("destination address is not allowed")
+
+// NewClient の timeout は 1 回のリクエストにかける時間の上限
+//
+// 名前解決した後の接続先のアドレスを policy で検証するため、登録後に解決先を内部のアドレスへ変えられても送信しない。
+// 検証を迂回しないよう環境変数のプロキシは使わず、リダイレクトにも従わない
+func NewClient(timeout time.Duration, policy domain.WebhookNetworkPolicy) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+		Control:   control(policy),
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// control は接続する直前に解決済みのアドレスを検証する
+func control(policy domain.WebhookNetworkPolicy) func(network, address string, c syscall.RawConn) error {
+	return func(network, address string, _ syscall.RawConn) error {
+		addr, err := netip.ParseAddrPort(address)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrAddressNotAllowed, address)
+		}
+		if !policy.AllowsAddr(addr.Addr()) {
+			return fmt.Errorf("%w: %s", ErrAddressNotAllowed, addr.Addr())
+		}
+		return nil
+	}
+}
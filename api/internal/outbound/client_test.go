@@ -0,0 +1,52 @@
+package outbound_test
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/outbound"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClient(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	t.Run("内部のネットワークのアドレスには接続しない", func(t *testing.T) {
+		t.Parallel()
+
+		client := outbound.NewClient(time.Second, domain.NewWebhookNetworkPolicy(false))
+		_, err := client.Get(srv.URL)
+
+		require.ErrorIs(t, err, outbound.ErrAddressNotAllowed)
+	})
+
+	t.Run("名前解決したアドレスも検証する", func(t *testing.T) {
+		t.Parallel()
+
+		client := outbound.NewClient(time.Second, domain.NewWebhookNetworkPolicy(false))
+		_, err := client.Get("http://localhost:" + strconv.Itoa(srv.Listener.Addr().(*net.TCPAddr).Port))
+
+		require.ErrorIs(t, err, outbound.ErrAddressNotAllowed)
+	})
+
+	t.Run("許可した場合は内部のネットワークにも接続する", func(t *testing.T) {
+		t.Parallel()
+
+		client := outbound.NewClient(time.Second, domain.NewWebhookNetworkPolicy(true))
+		res, err := client.Get(srv.URL)
+
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = res.Body.Close() })
+		require.Equal(t, http.StatusNoContent, res.StatusCode)
+	})
+}
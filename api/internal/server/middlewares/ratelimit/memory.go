@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval は満たされたバケットを捨てる間隔
+const sweepInterval = time.Minute
+
+// MemoryStore はプロセスのメモリにバケットを保存する。複数のインスタンスで動かす場合は上限を共有しない
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]memoryBucket
+	sweptAt time.Time
+}
+
+type memoryBucket struct {
+	bucket
+	// fullAt はバケットが満たされる日時。これを過ぎたバケットは捨てても結果が変わらない
+	fullAt time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]memoryBucket{}}
+}
+
+// Take implements Store.
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.sweptAt) >= sweepInterval {
+		for k, b := range s.buckets {
+			if !now.Before(b.fullAt) {
+				delete(s.buckets, k)
+			}
+		}
+		s.sweptAt = now
+	}
+
+	b, found := s.buckets[key]
+	next, res := take(b.bucket, found, limit, now)
+	s.buckets[key] = memoryBucket{next, now.Add(res.Reset)}
+	return res, nil
+}
+
+// Len は保存しているバケットの数
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.buckets)
+}
@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// KeyFunc はリクエストを数えるキーを返す。false の場合は制限しない
+type KeyFunc func(r *http.Request) (string, bool)
+
+// ByIP はクライアントの IP アドレスをキーにする。realip.Handler の後に使う
+func ByIP(r *http.Request) (string, bool) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		// realip.Handler はポートを含まないアドレスに置き換える
+		host = r.RemoteAddr
+	}
+	return host, host != ""
+}
+
+// Policy はルートのまとまりに適用する上限
+type Policy struct {
+	// Name はバケットのキーの接頭辞。同じ Name のポリシーはバケットを共有する
+	Name  string
+	Limit Limit
+	Key   KeyFunc
+}
+
+// Handler は p の上限を超えたリクエストを拒否するミドルウェアを返す
+//
+// 応答には RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, RateLimit-Policy を付け、
+// 拒否する場合は Retry-After を付けて deny を呼ぶ。
+// store が nil か上限が無効な場合は制限しない。store のエラーではリクエストを拒否せずに通す
+func Handler(store Store, p Policy, deny http.HandlerFunc) func(http.Handler) http.Handler {
+	if store == nil || !p.Limit.Enabled() {
+		return func(next http.Handler) http.Handler {
+			return next
+		}
+	}
+	policy := strconv.Itoa(p.Limit.Requests) + ";w=" + seconds(p.Limit.Per)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key, ok := p.Key(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			res, err := store.Take(r.Context(), p.Name+":"+key, p.Limit, time.Now())
+			if err != nil {
+				slog.WarnContext(r.Context(), "failed to take rate limit token", slog.String("policy", p.Name), slog.Any("err", err))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			h.Set("RateLimit-Reset", seconds(res.Reset))
+			h.Set("RateLimit-Policy", policy)
+			if !res.Allowed {
+				h.Set("Retry-After", seconds(max(res.RetryAfter, time.Second)))
+				deny(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// seconds は d を切り上げた秒数で表す
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/quietsato/toy-small-chat/api/internal/db"
+)
+
+// PostgresStore はデータベースにバケットを保存し、複数のインスタンスで上限を共有する
+//
+// バケットの行をロックして更新するため、同じキーへの同時のリクエストも上限を超えない。
+// 日時は各インスタンスの時計で数えるため、インスタンスの時計は合わせておく
+type PostgresStore struct {
+	pool *pgxpool.Pool
+
+	mu      sync.Mutex
+	sweptAt time.Time
+}
+
+func NewPostgresStore(pool *pgxpool.Pool) *PostgresStore {
+	return &PostgresStore{pool: pool}
+}
+
+// Take implements Store.
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	now = now.UTC()
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rollback", slog.Any("err", err))
+		}
+	}()
+
+	queries := db.New(s.pool).WithTx(tx)
+
+	row, err := queries.LockRateLimitBucket(ctx, db.LockRateLimitBucketParams{
+		Key:    key,
+		Tokens: float64(limit.Requests),
+		Now:    timestamp(now),
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to lock rate limit bucket: %w", err)
+	}
+
+	next, res := take(bucket{row.Tokens, row.UpdatedAt.Time}, true, limit, now)
+	if err := queries.UpdateRateLimitBucket(ctx, db.UpdateRateLimitBucketParams{
+		Tokens:    next.tokens,
+		UpdatedAt: timestamp(next.updatedAt),
+		FullAt:    timestamp(now.Add(res.Reset)),
+		Key:       key,
+	}); err != nil {
+		return Result{}, fmt.Errorf("failed to update rate limit bucket: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return Result{}, err
+	}
+
+	s.sweep(ctx, now)
+	return res, nil
+}
+
+// sweep は sweepInterval ごとに満たされたバケットの行を削除する。失敗してもリクエストは拒否しない
+func (s *PostgresStore) sweep(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.sweptAt) < sweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.sweptAt = now
+	s.mu.Unlock()
+
+	if _, err := db.New(s.pool).DeleteFullRateLimitBuckets(ctx, timestamp(now)); err != nil {
+		slog.WarnContext(ctx, "failed to delete full rate limit buckets", slog.Any("err", err))
+	}
+}
+
+func timestamp(t time.Time) pgtype.Timestamp {
+	return pgtype.Timestamp{Time: t, Valid: true}
+}
@@ -0,0 +1,76 @@
+// Package ratelimit はトークンバケットでリクエストの頻度を制限するミドルウェアを提供します
+//
+// バケットはキーごとに Limit.Requests 個のトークンを持ち、Limit.Per の間に Limit.Requests 個の割合で補充する。
+// リクエストごとに 1 個を取り出し、残っていない場合は拒否する
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit は Per の間に Requests 回までリクエストを受け付ける上限。Requests が 0 の場合は制限しない
+//
+// 続けて送れるのも Requests 回までで、以降は Per / Requests ごとに 1 回ずつ送れるようになる
+type Limit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// Enabled は制限するかどうか
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Per > 0
+}
+
+// interval はトークンを 1 個補充する間隔
+func (l Limit) interval() float64 {
+	return float64(l.Per) / float64(l.Requests)
+}
+
+// Result はトークンを取り出した結果
+type Result struct {
+	// Allowed はトークンを取り出せたかどうか
+	Allowed bool
+	Limit   int
+	// Remaining は取り出した後に残っているトークンの数
+	Remaining int
+	// Reset はバケットが満たされるまでの時間
+	Reset time.Duration
+	// RetryAfter は拒否した場合に次のトークンが補充されるまでの時間
+	RetryAfter time.Duration
+}
+
+// Store はバケットを保存する
+type Store interface {
+	// Take は key のバケットからトークンを 1 個取り出す。残っていない場合は Result.Allowed が false になる
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// bucket は最後に取り出した時点のトークンの数
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// take は now までに補充したうえで b からトークンを 1 個取り出す。found が false の場合は満たされたバケットから取り出す
+func take(b bucket, found bool, limit Limit, now time.Time) (bucket, Result) {
+	capacity := float64(limit.Requests)
+	interval := limit.interval()
+
+	tokens := capacity
+	if found {
+		elapsed := max(now.Sub(b.updatedAt), 0)
+		tokens = min(capacity, b.tokens+float64(elapsed)/interval)
+	}
+
+	res := Result{Limit: limit.Requests}
+	if tokens >= 1 {
+		tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = time.Duration((1 - tokens) * interval)
+	}
+	res.Remaining = int(tokens)
+	res.Reset = time.Duration((capacity - tokens) * interval)
+	return bucket{tokens, now}, res
+}
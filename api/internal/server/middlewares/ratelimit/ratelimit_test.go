@@ -0,0 +1,183 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/quietsato/toy-small-chat/api/internal/server/middlewares/ratelimit"
+	"github.com/stretchr/testify/require"
+)
+
+type mockStore struct {
+	takeFunc func(ctx context.Context, key string, limit ratelimit.Limit, now time.Time) (ratelimit.Result, error)
+}
+
+func (m *mockStore) Take(ctx context.Context, key string, limit ratelimit.Limit, now time.Time) (ratelimit.Result, error) {
+	return m.takeFunc(ctx, key, limit, now)
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	t.Parallel()
+
+	limit := ratelimit.Limit{Requests: 3, Per: time.Minute}
+	start := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	t.Run("上限の数まで続けて受け付け、使い切ったら補充されるまで拒否する", func(t *testing.T) {
+		t.Parallel()
+
+		store := ratelimit.NewMemoryStore()
+		for i := range 3 {
+			res, err := store.Take(t.Context(), "a", limit, start)
+			require.NoError(t, err)
+			require.True(t, res.Allowed)
+			require.Equal(t, 3, res.Limit)
+			require.Equal(t, 2-i, res.Remaining)
+		}
+
+		res, err := store.Take(t.Context(), "a", limit, start)
+		require.NoError(t, err)
+		require.False(t, res.Allowed)
+		require.Equal(t, 0, res.Remaining)
+		require.Equal(t, 20*time.Second, res.RetryAfter)
+		require.Equal(t, time.Minute, res.Reset)
+
+		// 拒否したリクエストはトークンを使わない
+		res, err = store.Take(t.Context(), "a", limit, start.Add(20*time.Second))
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+		require.Equal(t, 0, res.Remaining)
+		require.Equal(t, time.Minute, res.Reset)
+	})
+
+	t.Run("キーごとに数え、補充は上限を超えない", func(t *testing.T) {
+		t.Parallel()
+
+		store := ratelimit.NewMemoryStore()
+		_, err := store.Take(t.Context(), "a", limit, start)
+		require.NoError(t, err)
+
+		res, err := store.Take(t.Context(), "b", limit, start)
+		require.NoError(t, err)
+		require.Equal(t, 2, res.Remaining)
+
+		res, err = store.Take(t.Context(), "a", limit, start.Add(time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, 2, res.Remaining)
+		require.Equal(t, 20*time.Second, res.Reset)
+	})
+
+	t.Run("満たされたバケットは捨てる", func(t *testing.T) {
+		t.Parallel()
+
+		store := ratelimit.NewMemoryStore()
+		_, err := store.Take(t.Context(), "a", limit, start)
+		require.NoError(t, err)
+		_, err = store.Take(t.Context(), "b", ratelimit.Limit{Requests: 1, Per: time.Hour}, start.Add(time.Second))
+		require.NoError(t, err)
+		require.Equal(t, 2, store.Len())
+
+		_, err = store.Take(t.Context(), "c", limit, start.Add(2*time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, 2, store.Len())
+	})
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	deny := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}
+	policy := ratelimit.Policy{Name: "login", Limit: ratelimit.Limit{Requests: 1, Per: time.Minute}, Key: ratelimit.ByIP}
+
+	serve := func(h http.Handler, remoteAddr string) *http.Response {
+		req := httptest.NewRequest(http.MethodPost, "/login", nil)
+		req.RemoteAddr = remoteAddr
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr.Result()
+	}
+
+	t.Run("上限のヘッダを付け、超えた場合は Retry-After を付けて拒否する", func(t *testing.T) {
+		t.Parallel()
+
+		h := ratelimit.Handler(ratelimit.NewMemoryStore(), policy, deny)(ok)
+
+		res := serve(h, "192.0.2.1:1234")
+		require.Equal(t, http.StatusNoContent, res.StatusCode)
+		require.Equal(t, "1", res.Header.Get("RateLimit-Limit"))
+		require.Equal(t, "0", res.Header.Get("RateLimit-Remaining"))
+		require.Equal(t, "60", res.Header.Get("RateLimit-Reset"))
+		require.Equal(t, "1;w=60", res.Header.Get("RateLimit-Policy"))
+		require.Empty(t, res.Header.Get("Retry-After"))
+
+		res = serve(h, "192.0.2.1:5678")
+		require.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+		require.Equal(t, "60", res.Header.Get("Retry-After"))
+
+		// realip.Handler で置き換えたポートのないアドレスも数える
+		res = serve(h, "192.0.2.2")
+		require.Equal(t, http.StatusNoContent, res.StatusCode)
+		res = serve(h, "192.0.2.2")
+		require.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+	})
+
+	t.Run("キーに名前を付けて数える", func(t *testing.T) {
+		t.Parallel()
+
+		var key string
+		store := &mockStore{
+			takeFunc: func(ctx context.Context, k string, limit ratelimit.Limit, now time.Time) (ratelimit.Result, error) {
+				key = k
+				return ratelimit.Result{Allowed: true, Limit: limit.Requests}, nil
+			},
+		}
+
+		serve(ratelimit.Handler(store, policy, deny)(ok), "192.0.2.1:1234")
+
+		require.Equal(t, "login:192.0.2.1", key)
+	})
+
+	t.Run("ストアのエラーでは拒否せずに通す", func(t *testing.T) {
+		t.Parallel()
+
+		store := &mockStore{
+			takeFunc: func(ctx context.Context, key string, limit ratelimit.Limit, now time.Time) (ratelimit.Result, error) {
+				return ratelimit.Result{}, errors.New("connection refused")
+			},
+		}
+
+		res := serve(ratelimit.Handler(store, policy, deny)(ok), "192.0.2.1:1234")
+
+		require.Equal(t, http.StatusNoContent, res.StatusCode)
+		require.Empty(t, res.Header.Get("RateLimit-Limit"))
+	})
+
+	t.Run("ストアがない場合、上限が 0 の場合、キーがない場合は制限しない", func(t *testing.T) {
+		t.Parallel()
+
+		noKey := policy
+		noKey.Key = func(r *http.Request) (string, bool) { return "", false }
+		disabled := policy
+		disabled.Limit = ratelimit.Limit{}
+
+		for _, h := range []http.Handler{
+			ratelimit.Handler(nil, policy, deny)(ok),
+			ratelimit.Handler(ratelimit.NewMemoryStore(), disabled, deny)(ok),
+			ratelimit.Handler(ratelimit.NewMemoryStore(), noKey, deny)(ok),
+		} {
+			for range 3 {
+				res := serve(h, "192.0.2.1:1234")
+				require.Equal(t, http.StatusNoContent, res.StatusCode)
+				require.Empty(t, res.Header.Get("RateLimit-Limit"))
+			}
+		}
+	})
+}
@@ -0,0 +1,72 @@
+// Package realip は信頼するプロキシから届いたリクエストに限り、転送ヘッダーからクライアントの IP アドレスを求めるミドルウェアを提供します
+package realip
+
+import (
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// Handler は r.RemoteAddr をクライアントの IP アドレスに置き換えるミドルウェアを返す
+//
+// 接続元が trusted に含まれる場合だけ X-Forwarded-For, X-Real-IP, True-Client-IP の順に読む。
+// X-Forwarded-For は右から読み、trusted に含まれない最初のアドレスをクライアントとする。
+// それ以外の接続元から届いたヘッダーはクライアントが自由に付けられるため無視し、r.RemoteAddr をそのまま使う
+func Handler(trusted []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if addr, ok := clientAddr(r, trusted); ok {
+				r.RemoteAddr = addr.String()
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientAddr は転送ヘッダーから求めたクライアントのアドレスを返す。ヘッダーを信頼できない場合は false を返す
+func clientAddr(r *http.Request, trusted []netip.Prefix) (netip.Addr, bool) {
+	peer, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil || !contains(trusted, peer.Addr().Unmap()) {
+		return netip.Addr{}, false
+	}
+
+	if hops := r.Header.Values("X-Forwarded-For"); len(hops) > 0 {
+		if addr, ok := forwardedFor(strings.Join(hops, ","), trusted); ok {
+			return addr, true
+		}
+	}
+	for _, name := range []string{"X-Real-IP", "True-Client-IP"} {
+		if addr, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get(name))); err == nil {
+			return addr.Unmap(), true
+		}
+	}
+	return netip.Addr{}, false
+}
+
+// forwardedFor は X-Forwarded-For を右から読み、trusted に含まれない最初のアドレスを返す
+//
+// すべて trusted に含まれる場合は最も左のアドレスを返す。読めない値より左はクライアントが付けたものとみなして読まない
+func forwardedFor(v string, trusted []netip.Prefix) (netip.Addr, bool) {
+	hops := strings.Split(v, ",")
+	var client netip.Addr
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		client = addr.Unmap()
+		if !contains(trusted, client) {
+			break
+		}
+	}
+	return client, client.IsValid()
+}
+
+func contains(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, p := range prefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package realip_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/quietsato/toy-small-chat/api/internal/server/middlewares/realip"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("fd00::/8")}
+
+	serve := func(remoteAddr string, header http.Header) string {
+		var got string
+		h := realip.Handler(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r.RemoteAddr
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header = header
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		return got
+	}
+
+	t.Run("信頼しない接続元から届いた転送ヘッダーは無視する", func(t *testing.T) {
+		t.Parallel()
+
+		got := serve("198.51.100.7:4321", http.Header{
+			"X-Forwarded-For": {"192.0.2.1"},
+			"X-Real-Ip":       {"192.0.2.2"},
+			"True-Client-Ip":  {"192.0.2.3"},
+		})
+
+		require.Equal(t, "198.51.100.7:4321", got)
+	})
+
+	t.Run("X-Forwarded-For は右から読み、信頼しない最初のアドレスを使う", func(t *testing.T) {
+		t.Parallel()
+
+		// 最も左はクライアントが付けた偽のアドレス
+		got := serve("10.0.0.2:4321", http.Header{
+			"X-Forwarded-For": {"203.0.113.9, 192.0.2.1", "10.0.0.3"},
+			"X-Real-Ip":       {"192.0.2.2"},
+		})
+
+		require.Equal(t, "192.0.2.1", got)
+	})
+
+	t.Run("X-Forwarded-For がすべて信頼するプロキシの場合は最も左を使う", func(t *testing.T) {
+		t.Parallel()
+
+		got := serve("[fd00::1]:4321", http.Header{"X-Forwarded-For": {"10.0.0.4, 10.0.0.3"}})
+
+		require.Equal(t, "10.0.0.4", got)
+	})
+
+	t.Run("読めない X-Forwarded-For より左は使わない", func(t *testing.T) {
+		t.Parallel()
+
+		got := serve("10.0.0.2:4321", http.Header{"X-Forwarded-For": {"192.0.2.1, unknown, 10.0.0.3"}})
+
+		require.Equal(t, "10.0.0.3", got)
+	})
+
+	t.Run("X-Forwarded-For がなければ X-Real-IP, True-Client-IP の順に使う", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, "192.0.2.2", serve("10.0.0.2:4321", http.Header{
+			"X-Forwarded-For": {"unknown"},
+			"X-Real-Ip":       {"192.0.2.2"},
+			"True-Client-Ip":  {"192.0.2.3"},
+		}))
+		require.Equal(t, "192.0.2.3", serve("10.0.0.2:4321", http.Header{"True-Client-Ip": {"192.0.2.3"}}))
+	})
+
+	t.Run("信頼する接続元でも転送ヘッダーがなければ置き換えない", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, "10.0.0.2:4321", serve("10.0.0.2:4321", http.Header{}))
+	})
+}
@@ -0,0 +1,71 @@
+package securityheaders_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/quietsato/toy-small-chat/api/internal/server/middlewares/securityheaders"
+	"github.com/stretchr/testify/require"
+)
+
+func serve(t *testing.T, opts securityheaders.Options) http.Header {
+	t.Helper()
+
+	handler := securityheaders.Handler(opts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	return rr.Header()
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("development では HSTS を送らない", func(t *testing.T) {
+		t.Parallel()
+
+		opts, ok := securityheaders.Preset(securityheaders.PresetDevelopment, nil)
+		require.True(t, ok)
+
+		h := serve(t, opts)
+
+		require.Equal(t, "default-src 'none'; frame-ancestors 'none'", h.Get("Content-Security-Policy"))
+		require.Equal(t, "nosniff", h.Get("X-Content-Type-Options"))
+		require.Equal(t, "DENY", h.Get("X-Frame-Options"))
+		require.Equal(t, "strict-origin-when-cross-origin", h.Get("Referrer-Policy"))
+		require.Empty(t, h.Get("Strict-Transport-Security"))
+	})
+
+	t.Run("production では HSTS を送り、リファラーを送らせない", func(t *testing.T) {
+		t.Parallel()
+
+		opts, ok := securityheaders.Preset(securityheaders.PresetProduction, nil)
+		require.True(t, ok)
+
+		h := serve(t, opts)
+
+		require.Equal(t, "max-age=63072000; includeSubDomains", h.Get("Strict-Transport-Security"))
+		require.Equal(t, "no-referrer", h.Get("Referrer-Policy"))
+		require.Equal(t, "nosniff", h.Get("X-Content-Type-Options"))
+	})
+
+	t.Run("埋め込みを許可したオリジンを frame-ancestors に並べ、X-Frame-Options は送らない", func(t *testing.T) {
+		t.Parallel()
+
+		opts, ok := securityheaders.Preset(securityheaders.PresetProduction, []string{"'self'", "https://*.example.com"})
+		require.True(t, ok)
+
+		h := serve(t, opts)
+
+		require.Equal(t, "default-src 'none'; frame-ancestors 'self' https://*.example.com", h.Get("Content-Security-Policy"))
+		require.Empty(t, h.Get("X-Frame-Options"))
+	})
+
+	t.Run("知らないプリセットの場合は false", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok := securityheaders.Preset("staging", nil)
+
+		require.False(t, ok)
+	})
+}
@@ -3,6 +3,8 @@ package routes
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
@@ -10,9 +12,21 @@ import (
 	"github.com/go-chi/jwtauth/v5"
 	"github.com/quietsato/toy-small-chat/api/internal/applications/account/controller"
 	authserviceimpl "github.com/quietsato/toy-small-chat/api/internal/applications/account/infrastructure/serviceimpl"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/account/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/account/usecase/repository"
 	"github.com/quietsato/toy-small-chat/api/internal/di"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
 )
 
+// accountProblems はログインに失敗した理由を区別せず、どちらも invalid_credentials として返す
+var accountProblems = []problemSpec{
+	{Err: repository.ErrUserNameAlreadyRegistered, Status: http.StatusConflict, Code: codeUserNameAlreadyRegistered, Detail: "username is already registered"},
+	{Err: usecase.ErrAccountNotFound, Status: http.StatusUnauthorized, Code: codeInvalidCredentials, Detail: "username or password is incorrect"},
+	{Err: usecase.ErrPasswordIsNotMatch, Status: http.StatusUnauthorized, Code: codeInvalidCredentials, Detail: "username or password is incorrect"},
+}
+
 type ctxKeyAccountID struct{}
 
 func getAccountIDFromContext(ctx context.Context) *string {
@@ -22,6 +36,20 @@ func getAccountIDFromContext(ctx context.Context) *string {
 	return nil
 }
 
+// authenticate は jwtauth.Verifier で検証したトークンがない、または不正なリクエストを拒否する
+//
+// jwtauth.Authenticator と同じ判定で、エラーを problem+json で返す
+func authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, _, err := jwtauth.FromContext(r.Context())
+		if err != nil || token == nil {
+			writeProblem(w, r, http.StatusUnauthorized, codeUnauthorized, "a valid bearer token is required")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 func accountCtx(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
@@ -30,6 +58,9 @@ func accountCtx(next http.Handler) http.Handler {
 		if ok {
 			ctx = context.WithValue(ctx, ctxKeyAccountID{}, accountId)
 		}
+		if id, ok := accountId.(string); ok {
+			tracing.SetAttributes(ctx, semconv.EnduserID(id))
+		}
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
@@ -42,26 +73,26 @@ func createAccount(dic *di.Container) http.HandlerFunc {
 		bytes, err := io.ReadAll(r.Body)
 		defer r.Body.Close()
 		if err != nil {
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "failed to read request body")
 			return
 		}
 
 		inp := controller.CreateAccountInput{}
 		if err := json.Unmarshal(bytes, &inp); err != nil {
-			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			writeInvalidJSON(w, r)
 			return
 		}
 
 		res, err := c.CreateAccount(ctx, inp)
 		if err != nil {
-			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			writeError(w, r, err, accountProblems...)
 			return
 		}
+		instrument.RecordAccountCreated(ctx)
 
 		resBytes, err := json.Marshal(res)
 		if err != nil {
-			slog.ErrorContext(ctx, "failed to login", slog.Any("err", err))
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
 			return
 		}
 
@@ -78,26 +109,29 @@ func login(dic *di.Container) http.HandlerFunc {
 		bytes, err := io.ReadAll(r.Body)
 		defer r.Body.Close()
 		if err != nil {
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "failed to read request body")
 			return
 		}
 
 		inp := controller.LoginInput{}
 		if err := json.Unmarshal(bytes, &inp); err != nil {
-			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			writeInvalidJSON(w, r)
 			return
 		}
 
-		res, err := controller.NewLoginController(dic.Account.Query).Login(ctx, inp)
+		res, err := controller.NewLoginController(dic.Account.Query, dic.Auth.Service).Login(ctx, inp)
+		if errors.Is(err, usecase.ErrAccountNotFound) || errors.Is(err, usecase.ErrPasswordIsNotMatch) {
+			instrument.RecordLogin(ctx, false)
+		}
 		if err != nil {
-			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			writeError(w, r, err, accountProblems...)
 			return
 		}
+		instrument.RecordLogin(ctx, true)
 
 		resBytes, err := json.Marshal(res)
 		if err != nil {
-			slog.ErrorContext(ctx, "failed to login", slog.Any("err", err))
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
 			return
 		}
 
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// adminAuth は管理用 API を Bearer トークンで認証する
+//
+// トークンが設定されていない場合は管理用 API が存在しないものとして扱う
+func adminAuth(token string) func(http.Handler) http.Handler {
+	expected := sha256.Sum256([]byte(token))
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token == "" {
+				writeProblem(w, r, http.StatusNotFound, codeNotFound, "")
+				return
+			}
+
+			given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			// 長さからトークンを推測されないよう、ハッシュ値を比較する
+			actual := sha256.Sum256([]byte(given))
+			if !ok || subtle.ConstantTimeCompare(expected[:], actual[:]) != 1 {
+				writeProblem(w, r, http.StatusUnauthorized, codeUnauthorized, "admin token is missing or invalid")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/attachment/controller"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/di"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+)
+
+// multipart のヘッダ等のために添付ファイル上限に上乗せするバイト数
+const multipartOverhead = 1 << 20
+
+var attachmentProblems = []problemSpec{
+	{Err: domain.ErrAttachmentTooLarge, Status: http.StatusRequestEntityTooLarge, Code: codePayloadTooLarge, Detail: "attachment exceeds the size limit"},
+	{Err: domain.ErrAttachmentTypeNotAllowed, Status: http.StatusUnsupportedMediaType, Code: codeUnsupportedMediaType, Detail: "attachment type is not allowed"},
+	{Err: domain.ErrAttachmentEmpty, Code: codeEmptyAttachment, Field: "file", Detail: "file must not be empty"},
+	{Err: domain.ErrInvalidAttachmentFileName, Code: codeInvalidFileName, Field: "file", Detail: "file name is invalid"},
+	{Err: usecase.ErrAttachmentNotFound, Status: http.StatusNotFound, Code: codeAttachmentNotFound, Detail: "attachment not found"},
+	{Err: usecase.ErrRoomNotFound, Status: http.StatusNotFound, Code: codeRoomNotFound, Detail: "room not found"},
+}
+
+func uploadAttachment(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		roomID := getRoomIDFromContext(ctx)
+		accountID := getAccountIDFromContext(ctx)
+		if roomID == nil || accountID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, dic.Attachment.Policy.MaxSize()+multipartOverhead)
+		defer r.Body.Close()
+
+		mr, err := r.MultipartReader()
+		if err != nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "request body must be multipart/form-data")
+			return
+		}
+
+		// "file" フィールドが見つかるまで読み飛ばす
+		var part *multipart.Part
+		for {
+			p, err := mr.NextPart()
+			if err != nil {
+				writeValidationProblem(w, r, fieldError{Field: "file", Code: codeRequired, Detail: "file is required"})
+				return
+			}
+			if p.FormName() == "file" {
+				part = p
+				break
+			}
+		}
+
+		c := controller.NewUploadAttachmentController(
+			dic.Attachment.Repo,
+			dic.Attachment.Storage,
+			dic.Attachment.Thumbnailer,
+			dic.Attachment.Policy,
+		)
+		out, err := c.UploadAttachment(ctx, controller.UploadAttachmentInput{
+			RoomID:     *roomID,
+			UploadedBy: *accountID,
+			FileName:   part.FileName(),
+			Body:       part,
+		})
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeProblem(w, r, http.StatusRequestEntityTooLarge, codePayloadTooLarge, "attachment exceeds the size limit")
+			return
+		}
+		if err != nil {
+			writeError(w, r, err, attachmentProblems...)
+			return
+		}
+
+		res, err := json.Marshal(out)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		if _, err := w.Write(res); err != nil {
+			slog.ErrorContext(ctx, "failed to write response", slog.Any("err", err))
+		}
+	})
+}
+
+func getAttachment(dic *di.Container, thumbnail bool) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		roomID := getRoomIDFromContext(ctx)
+		if roomID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		c := controller.NewGetAttachmentController(dic.Attachment.Query, dic.Attachment.Storage)
+		out, err := c.GetAttachment(ctx, controller.GetAttachmentInput{
+			RoomID:       *roomID,
+			AttachmentID: chi.URLParam(r, "attachmentID"),
+			Thumbnail:    thumbnail,
+		})
+		if err != nil {
+			writeError(w, r, err, attachmentProblems...)
+			return
+		}
+		defer out.Body.Close()
+
+		// 画像以外はブラウザで開かずダウンロードさせる
+		disposition := "attachment"
+		if strings.HasPrefix(out.ContentType, "image/") {
+			disposition = "inline"
+		}
+		w.Header().Set("Content-Type", out.ContentType)
+		w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": out.FileName}))
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+
+		if _, err := io.Copy(w, out.Body); err != nil {
+			slog.ErrorContext(ctx, "failed to write response", slog.Any("err", err))
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// deprecation は廃止予定のルートやフィールド
+//
+// Since は廃止予定とした日時、Sunset は削除する日時でゼロ値の場合は未定
+type deprecation struct {
+	Since  time.Time
+	Sunset time.Time
+}
+
+// setDeprecationHeaders は RFC 9745 の Deprecation と RFC 8594 の Sunset をレスポンスに付ける
+//
+// successor は代わりに使う URL で、空でない場合は Link ヘッダに含める。
+// ルートとフィールドのように 1 つのレスポンスに廃止予定が重なった場合は、先に付けたものを優先する
+func setDeprecationHeaders(w http.ResponseWriter, d deprecation, successor string) {
+	h := w.Header()
+	if h.Get("Deprecation") == "" {
+		h.Set("Deprecation", fmt.Sprintf("@%d", d.Since.Unix()))
+		if !d.Sunset.IsZero() {
+			h.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+		}
+	}
+	if successor != "" {
+		h.Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+	}
+}
+
+// deprecated はルートを廃止予定として、すべてのレスポンスに廃止予定のヘッダを付ける
+//
+// successor はリクエストから代わりに使う URL を求める。nil の場合は Link ヘッダを付けない
+func deprecated(d deprecation, successor func(r *http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var link string
+			if successor != nil {
+				link = successor(r)
+			}
+			setDeprecationHeaders(w, d, link)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/quietsato/toy-small-chat/api/internal/di"
+	"github.com/quietsato/toy-small-chat/api/internal/health"
+)
+
+const (
+	checkOK          = "ok"
+	checkDraining    = "draining"
+	checkUnreachable = "unreachable"
+	checkPending     = "pending"
+	checkUnknown     = "unknown"
+)
+
+// readiness はリクエストを受け付けられるかどうかと、その判断に使った確認ごとの結果
+type readiness struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+func (r readiness) ready() bool {
+	return r.Status == "ready"
+}
+
+// checkReadiness は終了処理中でないこと、データベースに接続できること、マイグレーションがすべて適用済みであることを確認する
+//
+// 失敗の詳細はログにだけ記録する
+func checkReadiness(ctx context.Context, h di.HealthDeps) (readiness, []int64) {
+	ctx, cancel := context.WithTimeout(ctx, h.ReadyTimeout)
+	defer cancel()
+
+	res := readiness{Status: "ready", Checks: map[string]string{
+		"draining":   checkOK,
+		"database":   checkOK,
+		"migrations": checkOK,
+	}}
+	fail := func(name, result string) {
+		res.Status = "unavailable"
+		res.Checks[name] = result
+	}
+
+	if h.State.Draining() {
+		fail("draining", checkDraining)
+	}
+
+	if err := h.Database.Ping(ctx); err != nil {
+		slog.WarnContext(ctx, "database is unreachable", slog.Any("err", err))
+		fail("database", checkUnreachable)
+		fail("migrations", checkUnknown)
+		return res, nil
+	}
+
+	pending, err := h.Database.PendingMigrations(ctx)
+	switch {
+	case err != nil:
+		slog.WarnContext(ctx, "failed to check migrations", slog.Any("err", err))
+		fail("migrations", checkUnknown)
+	case len(pending) > 0:
+		fail("migrations", checkPending)
+	}
+	return res, pending
+}
+
+// getHealthz はプロセスが応答できることだけを返す。依存の状態は見ない
+func getHealthz() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := w.Write([]byte(`{"status":"ok"}`)); err != nil {
+			slog.ErrorContext(r.Context(), "failed to write response", slog.Any("err", err))
+		}
+	})
+}
+
+// getReadyz はリクエストを受け付けられる場合に 200 を、そうでない場合に 503 を返す
+func getReadyz(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		res, _ := checkReadiness(ctx, dic.Health)
+
+		body, err := json.Marshal(res)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		if !res.ready() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if _, err := w.Write(body); err != nil {
+			slog.ErrorContext(ctx, "failed to write response", slog.Any("err", err))
+		}
+	})
+}
+
+type poolStatResponse struct {
+	health.PoolStat
+	AcquireDurationMs int64 `json:"acquireDurationMs"`
+}
+
+type debugStatusResponse struct {
+	readiness
+	StartedAt     time.Time        `json:"startedAt"`
+	UptimeSeconds int64            `json:"uptimeSeconds"`
+	Build         health.BuildInfo `json:"build"`
+	Database      struct {
+		PendingMigrations []int64          `json:"pendingMigrations"`
+		Pool              poolStatResponse `json:"pool"`
+	} `json:"database"`
+}
+
+// getDebugStatus は管理者向けに、レディネスの結果に加えてビルド情報、稼働時間、コネクションプールの統計を返す
+func getDebugStatus(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		res := debugStatusResponse{
+			StartedAt:     dic.Health.State.StartedAt().UTC(),
+			UptimeSeconds: int64(time.Since(dic.Health.State.StartedAt()).Seconds()),
+			Build:         health.ReadBuildInfo(),
+		}
+		var pending []int64
+		res.readiness, pending = checkReadiness(ctx, dic.Health)
+		res.Database.PendingMigrations = append([]int64{}, pending...)
+		stat := dic.Health.Database.Stat()
+		res.Database.Pool = poolStatResponse{stat, stat.AcquireDuration.Milliseconds()}
+
+		body, err := json.Marshal(res)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		if _, err := w.Write(body); err != nil {
+			slog.ErrorContext(ctx, "failed to write response", slog.Any("err", err))
+		}
+	})
+}
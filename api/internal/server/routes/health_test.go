@@ -0,0 +1,198 @@
+package routes_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/account/infrastructure/serviceimpl"
+	"github.com/quietsato/toy-small-chat/api/internal/di"
+	"github.com/quietsato/toy-small-chat/api/internal/health"
+	"github.com/quietsato/toy-small-chat/api/internal/server/routes"
+	"github.com/stretchr/testify/require"
+)
+
+type mockDatabaseChecker struct {
+	pingFunc              func(ctx context.Context) error
+	pendingMigrationsFunc func(ctx context.Context) ([]int64, error)
+}
+
+func (m *mockDatabaseChecker) Ping(ctx context.Context) error {
+	if m.pingFunc != nil {
+		return m.pingFunc(ctx)
+	}
+	return nil
+}
+
+func (m *mockDatabaseChecker) PendingMigrations(ctx context.Context) ([]int64, error) {
+	if m.pendingMigrationsFunc != nil {
+		return m.pendingMigrationsFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *mockDatabaseChecker) Stat() health.PoolStat {
+	return health.PoolStat{TotalConns: 4, IdleConns: 3, AcquiredConns: 1, MaxConns: 8, AcquireCount: 42, AcquireDuration: 1500 * time.Millisecond}
+}
+
+func newHealthRouter(db health.DatabaseChecker, state *health.State) *chi.Mux {
+	auth := serviceimpl.NewAuthService([]byte("dummy"), time.Hour)
+
+	r := chi.NewRouter()
+	routes.Setup(r, &di.Container{
+		Auth:   di.AuthDeps{Service: auth, Middleware: auth, AdminToken: "admin-token"},
+		Health: di.HealthDeps{Database: db, State: state, ReadyTimeout: 100 * time.Millisecond},
+	})
+	return r
+}
+
+func TestHealthRoutes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("healthz はデータベースに接続できなくても 200 を返す", func(t *testing.T) {
+		t.Parallel()
+
+		db := &mockDatabaseChecker{pingFunc: func(ctx context.Context) error { return errors.New("connection refused") }}
+		r := newHealthRouter(db, health.NewState(time.Now()))
+
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.JSONEq(t, `{"status":"ok"}`, rr.Body.String())
+	})
+
+	tests := []struct {
+		name     string
+		db       *mockDatabaseChecker
+		draining bool
+		expected int
+		checks   map[string]string
+	}{
+		{
+			name:     "すべての確認に成功した場合は 200",
+			db:       &mockDatabaseChecker{},
+			expected: http.StatusOK,
+			checks:   map[string]string{"draining": "ok", "database": "ok", "migrations": "ok"},
+		},
+		{
+			name:     "データベースに接続できない場合は 503",
+			db:       &mockDatabaseChecker{pingFunc: func(ctx context.Context) error { return errors.New("connection refused") }},
+			expected: http.StatusServiceUnavailable,
+			checks:   map[string]string{"draining": "ok", "database": "unreachable", "migrations": "unknown"},
+		},
+		{
+			name: "データベースの応答がタイムアウトした場合は 503",
+			db: &mockDatabaseChecker{pingFunc: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}},
+			expected: http.StatusServiceUnavailable,
+			checks:   map[string]string{"draining": "ok", "database": "unreachable", "migrations": "unknown"},
+		},
+		{
+			name:     "未適用のマイグレーションがある場合は 503",
+			db:       &mockDatabaseChecker{pendingMigrationsFunc: func(ctx context.Context) ([]int64, error) { return []int64{16}, nil }},
+			expected: http.StatusServiceUnavailable,
+			checks:   map[string]string{"draining": "ok", "database": "ok", "migrations": "pending"},
+		},
+		{
+			name:     "終了処理中の場合は 503",
+			db:       &mockDatabaseChecker{},
+			draining: true,
+			expected: http.StatusServiceUnavailable,
+			checks:   map[string]string{"draining": "draining", "database": "ok", "migrations": "ok"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("readyz: "+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			state := health.NewState(time.Now())
+			if tt.draining {
+				state.StartDraining()
+			}
+			r := newHealthRouter(tt.db, state)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			require.Equal(t, tt.expected, rr.Code)
+			require.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+			var res struct {
+				Status string            `json:"status"`
+				Checks map[string]string `json:"checks"`
+			}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+			require.Equal(t, tt.checks, res.Checks)
+			if tt.expected == http.StatusOK {
+				require.Equal(t, "ready", res.Status)
+			} else {
+				require.Equal(t, "unavailable", res.Status)
+			}
+		})
+	}
+
+	t.Run("debug/status は管理用トークンがない場合は UnauthorizedError", func(t *testing.T) {
+		t.Parallel()
+
+		r := newHealthRouter(&mockDatabaseChecker{}, health.NewState(time.Now()))
+
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/debug/status", nil))
+
+		require.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+
+	t.Run("debug/status はコネクションプールの統計と稼働時間を返す", func(t *testing.T) {
+		t.Parallel()
+
+		startedAt := time.Now().Add(-90 * time.Second)
+		db := &mockDatabaseChecker{pendingMigrationsFunc: func(ctx context.Context) ([]int64, error) { return []int64{16, 17}, nil }}
+		r := newHealthRouter(db, health.NewState(startedAt))
+
+		req := httptest.NewRequest(http.MethodGet, "/debug/status", nil)
+		req.Header.Set("Authorization", "Bearer admin-token")
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		var res struct {
+			Status        string            `json:"status"`
+			Checks        map[string]string `json:"checks"`
+			StartedAt     time.Time         `json:"startedAt"`
+			UptimeSeconds int64             `json:"uptimeSeconds"`
+			Build         struct {
+				GoVersion string `json:"goVersion"`
+			} `json:"build"`
+			Database struct {
+				PendingMigrations []int64        `json:"pendingMigrations"`
+				Pool              map[string]any `json:"pool"`
+			} `json:"database"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+		require.Equal(t, "unavailable", res.Status)
+		require.Equal(t, "pending", res.Checks["migrations"])
+		require.True(t, res.StartedAt.Equal(startedAt.Truncate(0).UTC()))
+		require.GreaterOrEqual(t, res.UptimeSeconds, int64(90))
+		require.NotEmpty(t, res.Build.GoVersion)
+		require.Equal(t, []int64{16, 17}, res.Database.PendingMigrations)
+		require.Equal(t, map[string]any{
+			"totalConns":           float64(4),
+			"idleConns":            float64(3),
+			"acquiredConns":        float64(1),
+			"constructingConns":    float64(0),
+			"maxConns":             float64(8),
+			"acquireCount":         float64(42),
+			"emptyAcquireCount":    float64(0),
+			"canceledAcquireCount": float64(0),
+			"acquireDurationMs":    float64(1500),
+		}, res.Database.Pool)
+	})
+}
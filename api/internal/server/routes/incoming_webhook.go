@@ -0,0 +1,224 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/controller"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/usecase/queryprocessor"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/usecase/repository"
+	messagecontroller "github.com/quietsato/toy-small-chat/api/internal/applications/message/controller"
+	"github.com/quietsato/toy-small-chat/api/internal/di"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument"
+)
+
+// incomingWebhookMaxBodySize は認証前に読み込む受信 Webhook の本文の上限
+const incomingWebhookMaxBodySize = 64 << 10
+
+// incomingWebhookPayload は受信 Webhook で受け付ける JSON の本文
+type incomingWebhookPayload struct {
+	Content string `json:"content"`
+	Format  string `json:"format"`
+}
+
+func getIncomingWebhooks(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		roomID := getRoomIDFromContext(ctx)
+		accountID := getAccountIDFromContext(ctx)
+		if roomID == nil || accountID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		c := controller.NewGetIncomingWebhooksController(dic.IncomingWebhook.Query)
+		webhooks, err := c.GetIncomingWebhooks(ctx, controller.GetIncomingWebhooksInput{
+			RoomID:    *roomID,
+			AccountID: *accountID,
+		})
+		if err != nil {
+			writeError(w, r, err, incomingWebhookProblems...)
+			return
+		}
+
+		res, err := json.Marshal(webhooks)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
+			return
+		}
+
+		if _, err := w.Write(res); err != nil {
+			slog.ErrorContext(ctx, "failed to write response", slog.Any("err", err))
+		}
+	})
+}
+
+func createIncomingWebhook(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		roomID := getRoomIDFromContext(ctx)
+		accountID := getAccountIDFromContext(ctx)
+		if roomID == nil || accountID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		defer r.Body.Close()
+		bytes, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "failed to read request body")
+			return
+		}
+
+		inp := controller.CreateIncomingWebhookInput{}
+		if err := json.Unmarshal(bytes, &inp); err != nil {
+			writeInvalidJSON(w, r)
+			return
+		}
+		inp.RoomID = *roomID
+		inp.AccountID = *accountID
+
+		c := controller.NewCreateIncomingWebhookController(dic.IncomingWebhook.Repo)
+		webhook, err := c.CreateIncomingWebhook(ctx, inp)
+		if err != nil {
+			writeError(w, r, err, incomingWebhookProblems...)
+			return
+		}
+
+		res, err := json.Marshal(webhook)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		if _, err := w.Write(res); err != nil {
+			slog.ErrorContext(ctx, "failed to write response", slog.Any("err", err))
+		}
+	})
+}
+
+func rotateIncomingWebhookToken(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		roomID := getRoomIDFromContext(ctx)
+		accountID := getAccountIDFromContext(ctx)
+		if roomID == nil || accountID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		c := controller.NewRotateIncomingWebhookTokenController(dic.IncomingWebhook.Repo)
+		webhook, err := c.RotateIncomingWebhookToken(ctx, controller.RotateIncomingWebhookTokenInput{
+			RoomID:    *roomID,
+			WebhookID: chi.URLParam(r, "webhookID"),
+			AccountID: *accountID,
+		})
+		if err != nil {
+			writeError(w, r, err, incomingWebhookProblems...)
+			return
+		}
+
+		res, err := json.Marshal(webhook)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
+			return
+		}
+
+		if _, err := w.Write(res); err != nil {
+			slog.ErrorContext(ctx, "failed to write response", slog.Any("err", err))
+		}
+	})
+}
+
+func revokeIncomingWebhook(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		roomID := getRoomIDFromContext(ctx)
+		accountID := getAccountIDFromContext(ctx)
+		if roomID == nil || accountID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		c := controller.NewRevokeIncomingWebhookController(dic.IncomingWebhook.Repo)
+		if err := c.RevokeIncomingWebhook(ctx, controller.RevokeIncomingWebhookInput{
+			RoomID:    *roomID,
+			WebhookID: chi.URLParam(r, "webhookID"),
+			AccountID: *accountID,
+		}); err != nil {
+			writeError(w, r, err, incomingWebhookProblems...)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// postIncomingWebhook は受信 Webhook の Bot としてルームにメッセージを投稿する
+func postIncomingWebhook(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		r.Body = http.MaxBytesReader(w, r.Body, incomingWebhookMaxBodySize)
+		defer r.Body.Close()
+		bytes, err := io.ReadAll(r.Body)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeProblem(w, r, http.StatusRequestEntityTooLarge, codePayloadTooLarge, "request body exceeds the size limit")
+			return
+		}
+		if err != nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "failed to read request body")
+			return
+		}
+
+		payload := incomingWebhookPayload{}
+		if err := json.Unmarshal(bytes, &payload); err != nil {
+			writeInvalidJSON(w, r)
+			return
+		}
+
+		c := controller.NewAuthenticateIncomingWebhookController(dic.IncomingWebhook.Repo)
+		auth, err := c.AuthenticateIncomingWebhook(ctx, controller.AuthenticateIncomingWebhookInput{
+			WebhookID: chi.URLParam(r, "webhookID"),
+			Token:     chi.URLParam(r, "token"),
+		})
+		if err != nil {
+			writeError(w, r, err, incomingWebhookProblems...)
+			return
+		}
+
+		// Bot 同士の呼び出しが連鎖しないよう、受信 Webhook からの投稿ではスラッシュコマンドを実行しない
+		writeCreatedMessage(w, r, dic, messagecontroller.CreateMessageInput{
+			RoomID:   auth.RoomID,
+			Content:  payload.Content,
+			Format:   payload.Format,
+			AuthorID: auth.BotAccountID,
+		}, nil, instrument.MessageSourceIncomingWebhook)
+	})
+}
+
+var incomingWebhookProblems = []problemSpec{
+	{Err: domain.ErrInvalidUserName, Code: codeInvalidUserName, Field: "name", Detail: "name must be 1 to 32 alphanumeric characters"},
+	{Err: domain.ErrInvalidIncomingWebhookRateLimit, Code: codeInvalidRateLimit, Field: "rateLimit", Detail: "rateLimit is out of range"},
+	{Err: domain.ErrInvalidIncomingWebhookToken, Status: http.StatusUnauthorized, Code: codeUnauthorized, Detail: "webhook token is invalid"},
+	{Err: repository.ErrNotRoomOwner, Status: http.StatusForbidden, Code: codeNotRoomOwner, Detail: "only the room owner can manage incoming webhooks"},
+	{Err: queryprocessor.ErrNotRoomOwner, Status: http.StatusForbidden, Code: codeNotRoomOwner, Detail: "only the room owner can manage incoming webhooks"},
+	{Err: repository.ErrRoomNotFound, Status: http.StatusNotFound, Code: codeRoomNotFound, Detail: "room not found"},
+	{Err: queryprocessor.ErrRoomNotFound, Status: http.StatusNotFound, Code: codeRoomNotFound, Detail: "room not found"},
+	{Err: repository.ErrIncomingWebhookNotFound, Status: http.StatusNotFound, Code: codeWebhookNotFound, Detail: "incoming webhook not found"},
+	{Err: repository.ErrTooManyIncomingWebhooks, Status: http.StatusConflict, Code: codeLimitExceeded, Detail: "the room has too many incoming webhooks"},
+	{Err: repository.ErrBotNameTaken, Status: http.StatusConflict, Code: codeNameTaken, Detail: "name is already taken"},
+	{Err: repository.ErrRateLimitExceeded, Status: http.StatusTooManyRequests, Code: codeTooManyRequests, Detail: "incoming webhook rate limit exceeded"},
+}
@@ -0,0 +1,115 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/mention/controller"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/mention/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/di"
+)
+
+var mentionProblems = []problemSpec{
+	{Err: controller.ErrInvalidPagination, Status: http.StatusBadRequest, Code: codeInvalidPagination, Detail: "limit or offset is out of range"},
+	{Err: usecase.ErrInvalidMentionID, Code: codeInvalidID, Field: "mentionIds", Detail: "mentionIds must be UUIDs"},
+}
+
+func getMentions(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		accountID := getAccountIDFromContext(ctx)
+		if accountID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		inp := controller.GetMentionsInput{AccountID: *accountID}
+		q := r.URL.Query()
+		var err error
+		if v := q.Get("limit"); v != "" {
+			if inp.Limit, err = strconv.Atoi(v); err != nil {
+				writeValidationProblem(w, r, fieldError{Field: "limit", Code: codeInvalidLimit, Detail: "limit must be an integer"})
+				return
+			}
+		}
+		if v := q.Get("offset"); v != "" {
+			if inp.Offset, err = strconv.Atoi(v); err != nil {
+				writeValidationProblem(w, r, fieldError{Field: "offset", Code: codeInvalidPagination, Detail: "offset must be an integer"})
+				return
+			}
+		}
+		if v := q.Get("unread"); v != "" {
+			if inp.UnreadOnly, err = strconv.ParseBool(v); err != nil {
+				writeValidationProblem(w, r, fieldError{Field: "unread", Code: codeInvalidBoolean, Detail: "unread must be a boolean"})
+				return
+			}
+		}
+
+		c := controller.NewGetMentionsController(dic.Mention.Query)
+		mentions, err := c.GetMentions(ctx, inp)
+		if err != nil {
+			writeError(w, r, err, mentionProblems...)
+			return
+		}
+
+		res, err := json.Marshal(mentions)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
+			return
+		}
+
+		if _, err := w.Write(res); err != nil {
+			slog.ErrorContext(ctx, "failed to write response", slog.Any("err", err))
+		}
+	})
+}
+
+func markMentionsAsRead(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		accountID := getAccountIDFromContext(ctx)
+		if accountID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		defer r.Body.Close()
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "failed to read request body")
+			return
+		}
+
+		inp := controller.MarkMentionsAsReadInput{}
+		if len(body) > 0 {
+			if err := json.Unmarshal(body, &inp); err != nil {
+				writeInvalidJSON(w, r)
+				return
+			}
+		}
+		inp.AccountID = *accountID
+
+		c := controller.NewMarkMentionsAsReadController(dic.Mention.Repo)
+		out, err := c.MarkMentionsAsRead(ctx, inp)
+		if err != nil {
+			writeError(w, r, err, mentionProblems...)
+			return
+		}
+
+		res, err := json.Marshal(out)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
+			return
+		}
+
+		if _, err := w.Write(res); err != nil {
+			slog.ErrorContext(ctx, "failed to write response", slog.Any("err", err))
+		}
+	})
+}
@@ -2,44 +2,68 @@ package routes
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/quietsato/toy-small-chat/api/internal/applications/message/controller"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/queryprocessor"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/repository"
+	webhookcontroller "github.com/quietsato/toy-small-chat/api/internal/applications/webhook/controller"
 	"github.com/quietsato/toy-small-chat/api/internal/di"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
 )
 
+var messageProblems = []problemSpec{
+	{Err: repository.ErrAttachmentNotAvailable, Code: codeAttachmentNotAvailable, Field: "attachmentIds", Detail: "attachments must be uploaded to this room and not yet attached"},
+	{Err: usecase.ErrTooManyAttachments, Code: codeTooManyAttachments, Field: "attachmentIds", Detail: "too many attachments"},
+	{Err: usecase.ErrCommandWithAttachments, Code: codeCommandWithAttachments, Field: "attachmentIds", Detail: "slash commands cannot have attachments"},
+	{Err: usecase.ErrUnknownSlashCommand, Status: http.StatusBadRequest, Code: codeUnknownSlashCommand, Detail: "unknown slash command"},
+	{Err: usecase.ErrSlashCommandFailed, Status: http.StatusBadGateway, Code: codeSlashCommandFailed, Detail: "slash command did not respond successfully"},
+	{Err: repository.ErrRoomNotFound, Status: http.StatusNotFound, Code: codeRoomNotFound, Detail: "room not found"},
+	{Err: queryprocessor.ErrRoomNotFound, Status: http.StatusNotFound, Code: codeRoomNotFound, Detail: "room not found"},
+	{Err: repository.ErrRoomArchived, Status: http.StatusConflict, Code: codeRoomArchived, Detail: "room is archived"},
+}
+
 func getMessages(dic *di.Container) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		roomID, ok := ctx.Value(ctxKeyRoomID{}).(string)
 		if !ok {
-			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
 			return
 		}
 
-		c := controller.NewGetMessagesController(dic.Message.Query)
+		c := controller.NewGetMessagesController(dic.Message.Query, dic.Message.Renderer)
 		msgs, err := c.GetMessages(controller.GetMessagesInput{RoomID: roomID})
 		if err != nil {
-			slog.Error("failed to get messages", slog.Any("err", err))
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			writeError(w, r, err, messageProblems...)
 			return
 		}
 
 		res, err := json.Marshal(msgs)
 		if err != nil {
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
 			return
 		}
 
-		_, err = w.Write(res)
-		if err != nil {
-			panic(err) // TODO: fix
+		if _, err := w.Write(res); err != nil {
+			slog.WarnContext(ctx, "failed to write response", slog.Any("err", err))
 		}
 	})
 }
 
+// messageRoomIDDeprecation は本文の roomID の廃止予定。ルームはパスで指定するため、本文の値は使っていない
+var messageRoomIDDeprecation = deprecation{
+	Since: time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+}
+
 func createMessage(dic *di.Container) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
@@ -47,43 +71,77 @@ func createMessage(dic *di.Container) http.HandlerFunc {
 		roomID := getRoomIDFromContext(ctx)
 		accountID := getAccountIDFromContext(ctx)
 		if roomID == nil || accountID == nil {
-			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
 			return
 		}
 
 		defer r.Body.Close()
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
-			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "failed to read request body")
 			return
 		}
 
 		inp := controller.CreateMessageInput{}
 		if err := json.Unmarshal(body, &inp); err != nil {
-			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			writeInvalidJSON(w, r)
 			return
 		}
+		if inp.RoomID != "" {
+			setDeprecationHeaders(w, messageRoomIDDeprecation, "")
+		}
 		inp.RoomID = *roomID
 		inp.AuthorID = *accountID
 
-		c := controller.NewCreateMessageController(dic.Message.Repo)
-		err = c.CreateMessage(ctx, inp)
-		if err != nil {
-			slog.Error("failed to create message", slog.Any("err", err))
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
-			return
-		}
+		writeCreatedMessage(w, r, dic, inp, newSlashCommandRegistry(dic), instrument.MessageSourceUser)
+	})
+}
 
-		res, err := json.Marshal(controller.CreateMessageOutput{})
-		if err != nil {
-			slog.Warn("failed to marshal response", slog.Any("err", err))
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
-			return
-		}
+// writeCreatedMessage はメッセージを作成して送信 Webhook に通知し、作成したメッセージの ID を返す
+//
+// ユーザーの投稿と受信 Webhook からの投稿で共通の処理。commands が nil の場合はスラッシュコマンドを実行しない。
+// source は作成したメッセージの数を記録するときの投稿の経路
+func writeCreatedMessage(w http.ResponseWriter, r *http.Request, dic *di.Container, inp controller.CreateMessageInput, commands usecase.SlashCommandDispatcher, source instrument.MessageSource) {
+	ctx := r.Context()
 
-		_, err = w.Write(res)
-		if err != nil {
-			slog.Warn("failed to write response", slog.Any("err", err))
+	// incoming webhook は roomCtx を通らないため、ここでもルーム ID を付ける
+	tracing.SetAttributes(ctx, tracing.RoomIDKey.String(inp.RoomID))
+
+	c := controller.NewCreateMessageController(dic.Message.Repo, commands)
+	msg, err := c.CreateMessage(ctx, inp)
+	if errors.Is(err, usecase.ErrSlashCommandFailed) {
+		slog.WarnContext(ctx, "slash command failed", slog.Any("err", err))
+	}
+	if err != nil {
+		writeError(w, r, err, messageProblems...)
+		return
+	}
+
+	// ephemeral なコマンドの応答は保存していないため通知しない
+	if msg.Ephemeral == nil {
+		tracing.SetAttributes(ctx, tracing.MessageIDKey.String(msg.ID))
+		instrument.RecordMessageCreated(ctx, source)
+		attachmentIDs := inp.AttachmentIDs
+		if attachmentIDs == nil {
+			attachmentIDs = []string{}
 		}
-	})
+		enqueueWebhookEvent(ctx, dic, inp.RoomID, domain.WebhookEventMessageCreated, webhookcontroller.MessageCreatedData{
+			ID:            msg.ID,
+			AuthorID:      msg.AuthorID,
+			Content:       msg.Content,
+			Format:        msg.Format,
+			AttachmentIDs: attachmentIDs,
+		})
+	}
+
+	res, err := json.Marshal(msg)
+	if err != nil {
+		writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
+		return
+	}
+
+	_, err = w.Write(res)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to write response", slog.Any("err", err))
+	}
 }
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	_ "embed"
+	"log/slog"
+	"net/http"
+)
+
+// openAPIDocument は routes.Setup が登録するすべてのルートを記述した OpenAPI 3.1 ドキュメント
+//
+// ルートやリクエスト・レスポンスの形を変えた場合はあわせて更新する。食い違いはテストで検出する
+//
+//go:embed openapi.json
+var openAPIDocument []byte
+
+func getOpenAPI() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(openAPIDocument); err != nil {
+			slog.ErrorContext(r.Context(), "failed to write response", slog.Any("err", err))
+		}
+	})
+}
@@ -0,0 +1,358 @@
+package routes_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/account/infrastructure/serviceimpl"
+	attachmentserviceimpl "github.com/quietsato/toy-small-chat/api/internal/applications/attachment/infrastructure/serviceimpl"
+	messageserviceimpl "github.com/quietsato/toy-small-chat/api/internal/applications/message/infrastructure/serviceimpl"
+	"github.com/quietsato/toy-small-chat/api/internal/di"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/server/routes"
+	"github.com/stretchr/testify/require"
+)
+
+const conformanceAdminToken = "admin-token"
+
+// newStubContainer はすべての依存をスタブにしたコンテナを返す
+func newStubContainer(hookToken domain.IncomingWebhookToken) *di.Container {
+	auth := serviceimpl.NewAuthService([]byte("dummy"), time.Hour)
+	return &di.Container{
+		Account: di.AccountDeps{Repo: stubAccountRepository{}, Query: newStubAccountQueryProcessor()},
+		Message: di.MessageDeps{
+			Repo:          stubMessageRepository{},
+			Query:         stubMessageQueryProcessor{},
+			Renderer:      messageserviceimpl.NewMarkdownRenderer(),
+			Commands:      stubSlashCommandRepository{},
+			CommandQuery:  stubSlashCommandQueryProcessor{},
+			CommandCaller: stubSlashCommandCaller{},
+		},
+		Mention: di.MentionDeps{Repo: stubMentionRepository{}, Query: stubMentionQueryProcessor{}},
+		Pin:     di.PinDeps{Repo: stubPinRepository{}, Query: stubPinQueryProcessor{}},
+		Room:    di.RoomDeps{Repo: stubRoomRepository{}, Query: stubRoomQueryProcessor{}, DeletionGracePeriod: 24 * time.Hour},
+		Attachment: di.AttachmentDeps{
+			Repo:        stubAttachmentRepository{},
+			Query:       stubAttachmentQueryProcessor{},
+			Storage:     stubBlobStorage{},
+			Thumbnailer: attachmentserviceimpl.NewPNGThumbnailer(),
+			Policy:      domain.NewAttachmentPolicy(1<<20, []string{"image/png"}),
+		},
+		Webhook:          di.WebhookDeps{Repo: stubWebhookRepository{}, Query: stubWebhookQueryProcessor{}},
+		IncomingWebhook:  di.IncomingWebhookDeps{Repo: stubIncomingWebhookRepository{token: hookToken}, Query: stubIncomingWebhookQueryProcessor{}},
+		ScheduledMessage: di.ScheduledMessageDeps{Repo: stubScheduledMessageRepository{}, Query: stubScheduledMessageQueryProcessor{}},
+		Retention:        di.RetentionDeps{Repo: stubRetentionRepository{}, Query: stubRetentionQueryProcessor{}, DefaultDays: 90},
+		RoomTransfer:     di.RoomTransferDeps{Repo: stubRoomImportRepository{}, Query: stubRoomExportQueryProcessor{}},
+		Auth:             di.AuthDeps{Service: auth, Middleware: auth, AdminToken: conformanceAdminToken},
+	}
+}
+
+func loadOpenAPIDocument(t *testing.T) *openAPIDoc {
+	t.Helper()
+
+	r := chi.NewRouter()
+	routes.Setup(r, newStubContainer(domain.GenerateIncomingWebhookToken()))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/openapi.json", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	doc := &openAPIDoc{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), doc))
+	return doc
+}
+
+// specPath は chi のルートパターンを OpenAPI のパスに変換する
+//
+// ドキュメントのパスは servers の /v1 からの相対パスのため、/v1 を取り除く。
+// サブルーターの "/" に登録したルートは末尾に "/" が付くため、これも取り除く
+func specPath(pattern string) string {
+	pattern = strings.TrimPrefix(pattern, "/v1")
+	if pattern == "/" {
+		return pattern
+	}
+	return strings.TrimSuffix(pattern, "/")
+}
+
+// operationalRoutes は死活監視のためのルート。API ではないため OpenAPI ドキュメントに含めない
+var operationalRoutes = []string{"GET /healthz", "GET /readyz", "GET /debug/status"}
+
+func TestOpenAPIDocument(t *testing.T) {
+	t.Parallel()
+
+	doc := loadOpenAPIDocument(t)
+
+	t.Run("Setup のルートと OpenAPI ドキュメントのパスが一致する", func(t *testing.T) {
+		t.Parallel()
+
+		r := chi.NewRouter()
+		routes.Setup(r, newStubContainer(domain.GenerateIncomingWebhookToken()))
+
+		var v1, unversioned []string
+		require.NoError(t, chi.Walk(r, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+			switch {
+			case slices.Contains(operationalRoutes, method+" "+route):
+			case strings.HasPrefix(route, "/v1/"):
+				v1 = append(v1, method+" "+specPath(route))
+			default:
+				unversioned = append(unversioned, method+" "+specPath(route))
+			}
+			return nil
+		}))
+
+		var documented []string
+		for path, item := range doc.Paths {
+			for method := range item {
+				if slices.Contains(httpMethods, method) {
+					documented = append(documented, strings.ToUpper(method)+" "+path)
+				}
+			}
+		}
+
+		require.ElementsMatch(t, v1, documented)
+		// バージョンのないパスは v1 の別名
+		require.ElementsMatch(t, unversioned, documented)
+	})
+
+	t.Run("すべてのスキーマの $ref が解決できる", func(t *testing.T) {
+		t.Parallel()
+
+		var raw any
+		for _, item := range doc.Paths {
+			b, err := json.Marshal(item)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(b, &raw))
+			assertRefsResolve(t, doc, raw)
+		}
+		for _, schema := range doc.Components.Schemas {
+			require.NoError(t, json.Unmarshal(schema, &raw))
+			assertRefsResolve(t, doc, raw)
+		}
+	})
+}
+
+func assertRefsResolve(t *testing.T, doc *openAPIDoc, node any) {
+	t.Helper()
+
+	switch n := node.(type) {
+	case map[string]any:
+		if ref, ok := n["$ref"].(string); ok {
+			components := map[string]map[string]json.RawMessage{
+				"schemas":    doc.Components.Schemas,
+				"responses":  doc.Components.Responses,
+				"parameters": doc.Components.Parameters,
+				"headers":    doc.Components.Headers,
+			}
+			kind, name, _ := strings.Cut(strings.TrimPrefix(ref, "#/components/"), "/")
+			_, found := components[kind][name]
+			require.True(t, found, "unresolved $ref %q", ref)
+		}
+		for _, v := range n {
+			assertRefsResolve(t, doc, v)
+		}
+	case []any:
+		for _, v := range n {
+			assertRefsResolve(t, doc, v)
+		}
+	}
+}
+
+func TestOpenAPIConformance(t *testing.T) {
+	t.Parallel()
+
+	doc := loadOpenAPIDocument(t)
+	validator := schemaValidator{doc: doc}
+
+	hookToken := domain.GenerateIncomingWebhookToken()
+	dic := newStubContainer(hookToken)
+	userToken := "Bearer " + dic.Auth.Service.GenerateToken(stubAccountID)
+	adminToken := "Bearer " + conformanceAdminToken
+
+	var upload bytes.Buffer
+	mw := multipart.NewWriter(&upload)
+	fw, err := mw.CreateFormFile("file", "dot.png")
+	require.NoError(t, err)
+	_, err = fw.Write(stubPNG())
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+
+	exported := strings.Join([]string{
+		`{"type":"header","version":1,"exportedAt":"2024-06-01T00:00:00Z"}`,
+		`{"type":"room","id":"` + stubRoomID + `","name":"general","topic":"","ownerId":"` + stubAccountID + `","createdAt":"2024-06-01T09:00:00Z","archivedAt":null,"retentionDays":null}`,
+		`{"type":"member","roomId":"` + stubRoomID + `","id":"` + stubAccountID + `","username":"alice","kind":"user"}`,
+		`{"type":"message","roomId":"` + stubRoomID + `","id":"` + stubMessageID + `","authorId":"` + stubAccountID + `","kind":"user","content":"hello","format":"plain","createdAt":"2024-06-01T09:00:00Z","updatedAt":"2024-06-01T09:00:00Z"}`,
+	}, "\n") + "\n"
+
+	room := "/v1/rooms/" + stubRoomID
+	tests := []struct {
+		name          string
+		method        string
+		path          string
+		authorization string
+		contentType   string
+		body          string
+		expected      int
+	}{
+		{"OpenAPI ドキュメント", http.MethodGet, "/v1/openapi.json", "", "", "", http.StatusOK},
+		{"ログイン", http.MethodPost, "/v1/login", "", "application/json", `{"username":"alice","password":"password1"}`, http.StatusOK},
+		{"アカウント作成", http.MethodPost, "/v1/accounts", "", "application/json", `{"username":"alice","password":"password1"}`, http.StatusOK},
+		{"アカウント作成の検証エラー", http.MethodPost, "/v1/accounts", "", "application/json", `{"username":"a-b","password":"password1"}`, http.StatusBadRequest},
+		{"受信 Webhook への投稿", http.MethodPost, "/v1/hooks/" + stubWebhookID + "/" + hookToken.String(), "", "application/json", `{"content":"build passed","format":"markdown"}`, http.StatusOK},
+		{"ルームの書き出し", http.MethodGet, "/v1/admin/export?roomId=" + stubRoomID, adminToken, "", "", http.StatusOK},
+		{"ルームの取り込み", http.MethodPost, "/v1/admin/import", adminToken, "application/x-ndjson", exported, http.StatusCreated},
+		{"管理者によるルームの削除", http.MethodDelete, "/v1/admin/rooms/" + stubRoomID, adminToken, "", "", http.StatusNoContent},
+		{"管理者によるルームの復元", http.MethodPost, "/v1/admin/rooms/" + stubRoomID + "/restore", adminToken, "", "", http.StatusNoContent},
+		{"ルーム一覧", http.MethodGet, "/v1/rooms?sort=activity&limit=1", userToken, "", "", http.StatusOK},
+		{"ルーム一覧の認証エラー", http.MethodGet, "/v1/rooms", "", "", "", http.StatusUnauthorized},
+		{"ルーム作成", http.MethodPost, "/v1/rooms", userToken, "application/json", `{"name":"general"}`, http.StatusOK},
+		{"ルームの変更", http.MethodPatch, room, userToken, "application/json", `{"name":"general","topic":"news"}`, http.StatusOK},
+		{"ルームの削除", http.MethodDelete, room, userToken, "", "", http.StatusNoContent},
+		{"ルームのアーカイブ", http.MethodPost, room + "/archive", userToken, "", "", http.StatusNoContent},
+		{"ルームのアーカイブ解除", http.MethodPost, room + "/unarchive", userToken, "", "", http.StatusNoContent},
+		{"ルームの復元", http.MethodPost, room + "/restore", userToken, "", "", http.StatusNoContent},
+		{"通知設定の取得", http.MethodGet, room + "/settings", userToken, "", "", http.StatusOK},
+		{"通知設定の変更", http.MethodPut, room + "/settings", userToken, "application/json", `{"level":"mentions","mutedUntil":"2999-01-01T00:00:00Z"}`, http.StatusOK},
+		{"送信 Webhook の一覧", http.MethodGet, room + "/webhooks", userToken, "", "", http.StatusOK},
+		{"送信 Webhook の登録", http.MethodPost, room + "/webhooks", userToken, "application/json", `{"url":"https://example.com/hook","events":["message.created"]}`, http.StatusCreated},
+		{"送信 Webhook の削除", http.MethodDelete, room + "/webhooks/" + stubWebhookID, userToken, "", "", http.StatusNoContent},
+		{"送信 Webhook の配信履歴", http.MethodGet, room + "/webhooks/" + stubWebhookID + "/deliveries?limit=10", userToken, "", "", http.StatusOK},
+		{"受信 Webhook の一覧", http.MethodGet, room + "/incoming-webhooks", userToken, "", "", http.StatusOK},
+		{"受信 Webhook の作成", http.MethodPost, room + "/incoming-webhooks", userToken, "application/json", `{"name":"ci","rateLimit":30}`, http.StatusCreated},
+		{"受信 Webhook のトークン再発行", http.MethodPost, room + "/incoming-webhooks/" + stubWebhookID + "/rotate", userToken, "", "", http.StatusOK},
+		{"受信 Webhook の失効", http.MethodPost, room + "/incoming-webhooks/" + stubWebhookID + "/revoke", userToken, "", "", http.StatusNoContent},
+		{"スラッシュコマンドの一覧", http.MethodGet, room + "/commands", userToken, "", "", http.StatusOK},
+		{"スラッシュコマンドの登録", http.MethodPost, room + "/commands", userToken, "application/json", `{"name":"deploy","description":"deploy the app","url":"https://example.com/commands","botName":"deploybot"}`, http.StatusCreated},
+		{"スラッシュコマンドの削除", http.MethodDelete, room + "/commands/" + stubCommandID, userToken, "", "", http.StatusNoContent},
+		{"予約メッセージの一覧", http.MethodGet, room + "/scheduled-messages?limit=10", userToken, "", "", http.StatusOK},
+		{"メッセージの予約", http.MethodPost, room + "/scheduled-messages", userToken, "application/json", `{"content":"good morning","format":"plain","sendAt":"` + time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + `"}`, http.StatusCreated},
+		{"予約の取り消し", http.MethodDelete, room + "/scheduled-messages/" + stubScheduledID, userToken, "", "", http.StatusNoContent},
+		{"保存期間の取得", http.MethodGet, room + "/retention", userToken, "", "", http.StatusOK},
+		{"保存期間の変更", http.MethodPut, room + "/retention", userToken, "application/json", `{"retentionDays":30}`, http.StatusOK},
+		{"メッセージ一覧", http.MethodGet, room + "/messages", userToken, "", "", http.StatusOK},
+		{"メッセージの投稿", http.MethodPost, room + "/messages", userToken, "application/json", `{"content":"@bob hello","format":"plain","attachmentIds":["` + stubAttachID + `"]}`, http.StatusOK},
+		{"廃止予定の roomID を指定したメッセージの投稿", http.MethodPost, room + "/messages", userToken, "application/json", `{"roomID":"` + stubRoomID + `","content":"hello"}`, http.StatusOK},
+		{"スラッシュコマンドの実行", http.MethodPost, room + "/messages", userToken, "application/json", `{"content":"/deploy now"}`, http.StatusOK},
+		{"ピン留めの一覧", http.MethodGet, room + "/pins", userToken, "", "", http.StatusOK},
+		{"ピン留め", http.MethodPut, room + "/pins/" + stubMessageID, userToken, "", "", http.StatusNoContent},
+		{"ピン留めの解除", http.MethodDelete, room + "/pins/" + stubMessageID, userToken, "", "", http.StatusNoContent},
+		{"添付ファイルのアップロード", http.MethodPost, room + "/attachments", userToken, mw.FormDataContentType(), upload.String(), http.StatusCreated},
+		{"添付ファイルのダウンロード", http.MethodGet, room + "/attachments/" + stubAttachID, userToken, "", "", http.StatusOK},
+		{"サムネイルのダウンロード", http.MethodGet, room + "/attachments/" + stubAttachID + "/thumbnail", userToken, "", "", http.StatusOK},
+		{"メンション一覧", http.MethodGet, "/v1/me/mentions?limit=1&offset=0&unread=true", userToken, "", "", http.StatusOK},
+		{"メンションの既読", http.MethodPost, "/v1/me/mentions/read", userToken, "application/json", `{"mentionIds":["` + stubMentionID + `"]}`, http.StatusOK},
+		{"すべてのメンションの既読", http.MethodPost, "/v1/me/mentions/read", userToken, "", "", http.StatusOK},
+	}
+
+	var (
+		mu        sync.Mutex
+		exercised = map[string]bool{}
+	)
+
+	t.Run("ハンドラのリクエストとレスポンスが OpenAPI ドキュメントに従う", func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				var pattern string
+				r := chi.NewRouter()
+				r.Use(middleware.RequestID)
+				r.Use(func(next http.Handler) http.Handler {
+					return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						next.ServeHTTP(w, r)
+						pattern = chi.RouteContext(r.Context()).RoutePattern()
+					})
+				})
+				routes.Setup(r, dic)
+
+				var body io.Reader
+				if tt.body != "" {
+					body = strings.NewReader(tt.body)
+				}
+				req := httptest.NewRequest(tt.method, tt.path, body)
+				if tt.contentType != "" {
+					req.Header.Set("Content-Type", tt.contentType)
+				}
+				if tt.authorization != "" {
+					req.Header.Set("Authorization", tt.authorization)
+				}
+				rr := httptest.NewRecorder()
+
+				r.ServeHTTP(rr, req)
+
+				require.Equal(t, tt.expected, rr.Code, rr.Body.String())
+
+				path := specPath(pattern)
+				op, ok := doc.operation(tt.method, path)
+				require.True(t, ok, "%s %s is not documented", tt.method, path)
+
+				mu.Lock()
+				exercised[tt.method+" "+path] = true
+				mu.Unlock()
+
+				// リクエスト
+				if tt.body != "" {
+					require.NotNil(t, op.RequestBody, "request body is not documented")
+					mediaType, _, err := mime.ParseMediaType(tt.contentType)
+					require.NoError(t, err)
+					content, ok := op.RequestBody.Content[mediaType]
+					require.True(t, ok, "request content type %q is not documented", mediaType)
+					if mediaType == "application/json" {
+						var v any
+						require.NoError(t, json.Unmarshal([]byte(tt.body), &v))
+						// 4xx を期待するリクエストはドキュメント上も不正でなければならない
+						if tt.expected >= http.StatusBadRequest {
+							require.Error(t, validator.validate(content.Schema, v), "request")
+						} else {
+							require.NoError(t, validator.validate(content.Schema, v), "request")
+						}
+					}
+				}
+
+				// レスポンス
+				res, err := doc.response(op, rr.Code)
+				require.NoError(t, err)
+				if len(res.Content) == 0 {
+					require.Empty(t, rr.Body.Bytes(), "response body is not documented")
+					return
+				}
+
+				mediaType, _, err := mime.ParseMediaType(rr.Header().Get("Content-Type"))
+				require.NoError(t, err)
+				content, ok := res.Content[mediaType]
+				if !ok {
+					content, ok = res.Content["*/*"]
+				}
+				require.True(t, ok, "response content type %q is not documented", mediaType)
+				if mediaType == "application/json" || mediaType == "application/problem+json" {
+					var v any
+					require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &v))
+					require.NoError(t, validator.validate(content.Schema, v), "response: %s", rr.Body.String())
+				}
+			})
+		}
+	})
+
+	t.Run("すべての operation をテストしている", func(t *testing.T) {
+		for path, item := range doc.Paths {
+			for method := range item {
+				if slices.Contains(httpMethods, method) {
+					key := strings.ToUpper(method) + " " + path
+					require.True(t, exercised[key], "%s is not exercised", key)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/pin/controller"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/pin/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/pin/usecase/queryprocessor"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/pin/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/di"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
+)
+
+var pinProblems = []problemSpec{
+	{Err: usecase.ErrInvalidMessageID, Status: http.StatusBadRequest, Code: codeInvalidID, Detail: "message ID must be a UUID"},
+	{Err: repository.ErrNotRoomOwner, Status: http.StatusForbidden, Code: codeNotRoomOwner, Detail: "only the room owner can change pins"},
+	{Err: repository.ErrRoomNotFound, Status: http.StatusNotFound, Code: codeRoomNotFound, Detail: "room not found"},
+	{Err: queryprocessor.ErrRoomNotFound, Status: http.StatusNotFound, Code: codeRoomNotFound, Detail: "room not found"},
+	{Err: repository.ErrMessageNotFound, Status: http.StatusNotFound, Code: codeMessageNotFound, Detail: "message not found"},
+	{Err: repository.ErrPinNotFound, Status: http.StatusNotFound, Code: codePinNotFound, Detail: "message is not pinned"},
+	{Err: repository.ErrTooManyPins, Status: http.StatusConflict, Code: codeLimitExceeded, Detail: "the room has too many pinned messages"},
+	{Err: repository.ErrRoomArchived, Status: http.StatusConflict, Code: codeRoomArchived, Detail: "room is archived"},
+}
+
+func getPins(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		roomID := getRoomIDFromContext(ctx)
+		if roomID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		c := controller.NewGetPinsController(dic.Pin.Query)
+		pins, err := c.GetPins(ctx, controller.GetPinsInput{RoomID: *roomID})
+		if err != nil {
+			writeError(w, r, err, pinProblems...)
+			return
+		}
+
+		res, err := json.Marshal(pins)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
+			return
+		}
+
+		if _, err := w.Write(res); err != nil {
+			slog.ErrorContext(ctx, "failed to write response", slog.Any("err", err))
+		}
+	})
+}
+
+func pinMessage(dic *di.Container) http.HandlerFunc {
+	return changePin(dic, (*controller.PinMessageController).PinMessage)
+}
+
+func unpinMessage(dic *di.Container) http.HandlerFunc {
+	return changePin(dic, (*controller.PinMessageController).UnpinMessage)
+}
+
+// changePin はピン留め・解除で共通のリクエスト処理とエラーの変換を行う
+//
+// タイムラインへの記録はリポジトリがピン留めと同じトランザクションで行う
+func changePin(
+	dic *di.Container,
+	action func(c *controller.PinMessageController, ctx context.Context, inp controller.PinMessageInput) (controller.PinMessageOutput, error),
+) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		roomID := getRoomIDFromContext(ctx)
+		accountID := getAccountIDFromContext(ctx)
+		if roomID == nil || accountID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		messageID := chi.URLParam(r, "messageID")
+		tracing.SetAttributes(ctx, tracing.MessageIDKey.String(messageID))
+		c := controller.NewPinMessageController(dic.Pin.Repo)
+		if _, err := action(c, ctx, controller.PinMessageInput{
+			RoomID:    *roomID,
+			MessageID: messageID,
+			AccountID: *accountID,
+		}); err != nil {
+			writeError(w, r, err, pinProblems...)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,188 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+)
+
+// errorCode はクライアントがエラーを判別するためのコード。公開したコードは変更しない
+type errorCode string
+
+const (
+	codeBadRequest       errorCode = "bad_request"
+	codeInvalidJSON      errorCode = "invalid_json"
+	codeValidationFailed errorCode = "validation_failed"
+	codeUnauthorized     errorCode = "unauthorized"
+	codeNotFound         errorCode = "not_found"
+	codeMethodNotAllowed errorCode = "method_not_allowed"
+	codePayloadTooLarge  errorCode = "payload_too_large"
+	codeTooManyRequests  errorCode = "too_many_requests"
+	codeInternalError    errorCode = "internal_error"
+
+	codeInvalidCredentials        errorCode = "invalid_credentials"
+	codeUserNameAlreadyRegistered errorCode = "username_already_registered"
+	codeNotRoomOwner              errorCode = "not_room_owner"
+	codeRoomNotFound              errorCode = "room_not_found"
+	codeRoomArchived              errorCode = "room_archived"
+	codeRoomRestoreExpired        errorCode = "room_restore_expired"
+	codeMessageNotFound           errorCode = "message_not_found"
+	codePinNotFound               errorCode = "pin_not_found"
+	codeAttachmentNotFound        errorCode = "attachment_not_found"
+	codeWebhookNotFound           errorCode = "webhook_not_found"
+	codeSlashCommandNotFound      errorCode = "slash_command_not_found"
+	codeScheduledMessageNotFound  errorCode = "scheduled_message_not_found"
+	codeScheduledMessageSending   errorCode = "scheduled_message_sending"
+	codeNameTaken                 errorCode = "name_taken"
+	codeUnknownSlashCommand       errorCode = "unknown_slash_command"
+	codeSlashCommandFailed        errorCode = "slash_command_failed"
+	codeLimitExceeded             errorCode = "limit_exceeded"
+
+	codeUnsupportedMediaType errorCode = "unsupported_media_type"
+
+	codeRequired                 errorCode = "required"
+	codeEmptyAttachment          errorCode = "empty_attachment"
+	codeInvalidFileName          errorCode = "invalid_file_name"
+	codeInvalidUserName          errorCode = "invalid_username"
+	codeInvalidPassword          errorCode = "invalid_password"
+	codeInvalidRoomName          errorCode = "invalid_room_name"
+	codeInvalidRoomTopic         errorCode = "invalid_room_topic"
+	codeInvalidMessageContent    errorCode = "invalid_message_content"
+	codeInvalidMessageFormat     errorCode = "invalid_message_format"
+	codeInvalidRetentionPeriod   errorCode = "invalid_retention_period"
+	codeInvalidRateLimit         errorCode = "invalid_rate_limit"
+	codeAttachmentNotAvailable   errorCode = "attachment_not_available"
+	codeTooManyAttachments       errorCode = "too_many_attachments"
+	codeCommandWithAttachments   errorCode = "command_with_attachments"
+	codeInvalidQuery             errorCode = "invalid_query"
+	codeNoChanges                errorCode = "no_changes"
+	codeInvalidNotificationLevel errorCode = "invalid_notification_level"
+	codeInvalidMutedUntil        errorCode = "invalid_muted_until"
+	codeInvalidImport            errorCode = "invalid_import"
+	codeInvalidSendAt            errorCode = "invalid_send_at"
+	codeInvalidSlashCommandName  errorCode = "invalid_slash_command_name"
+	codeInvalidDescription       errorCode = "invalid_description"
+	codeInvalidURL               errorCode = "invalid_url"
+	codeInvalidSecret            errorCode = "invalid_secret"
+	codeInvalidEventType         errorCode = "invalid_event_type"
+	codeInvalidID                errorCode = "invalid_id"
+	codeInvalidPagination        errorCode = "invalid_pagination"
+	codeInvalidBoolean           errorCode = "invalid_boolean"
+	codeInvalidLimit             errorCode = "invalid_limit"
+)
+
+// problemDetails は RFC 7807 の problem+json。code, requestId, errors は拡張メンバー
+type problemDetails struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      errorCode    `json:"code"`
+	RequestID string       `json:"requestId,omitempty"`
+	Errors    []fieldError `json:"errors,omitempty"`
+}
+
+// fieldError は入力の検証に失敗したフィールド。Field はリクエストの JSON またはクエリパラメータの名前
+type fieldError struct {
+	Field  string    `json:"field"`
+	Code   errorCode `json:"code"`
+	Detail string    `json:"detail"`
+}
+
+// problemSpec はエラーをレスポンスに対応付ける
+//
+// Field を指定した場合は入力の検証エラーとして 400 の validation_failed を返し、Code と Detail を errors に入れる
+type problemSpec struct {
+	Err    error
+	Status int
+	Code   errorCode
+	Detail string
+	Field  string
+}
+
+// domainProblems はどのスライスでも同じ意味を持つドメインのエラー
+var domainProblems = []problemSpec{
+	{Err: domain.ErrInvalidUserName, Code: codeInvalidUserName, Field: "username", Detail: "username must be 1 to 32 alphanumeric characters"},
+	{Err: domain.ErrInvalidPassword, Code: codeInvalidPassword, Field: "password", Detail: "password does not meet the requirements"},
+	{Err: domain.ErrInvalidRoomName, Code: codeInvalidRoomName, Field: "name", Detail: "room name must be 1 to 127 characters"},
+	{Err: domain.ErrInvalidRoomTopic, Code: codeInvalidRoomTopic, Field: "topic", Detail: "room topic must be at most 250 characters"},
+	{Err: domain.ErrInvalidMessageContent, Code: codeInvalidMessageContent, Field: "content", Detail: "content must be 1 to 1000 bytes"},
+	{Err: domain.ErrInvalidMessageFormat, Code: codeInvalidMessageFormat, Field: "format", Detail: `format must be "plain" or "markdown"`},
+	{Err: domain.ErrInvalidRetentionPeriod, Code: codeInvalidRetentionPeriod, Field: "retentionDays", Detail: "retention must be 1 to 3650 days"},
+}
+
+// writeError は err を specs, domainProblems の順に照合し、problem+json で書き出す
+//
+// どれにも当てはまらないエラーは内部のエラーとして記録し、詳細をクライアントに返さない
+func writeError(w http.ResponseWriter, r *http.Request, err error, specs ...problemSpec) {
+	for _, list := range [][]problemSpec{specs, domainProblems} {
+		for _, s := range list {
+			if !errors.Is(err, s.Err) {
+				continue
+			}
+			if s.Field != "" {
+				writeValidationProblem(w, r, fieldError{Field: s.Field, Code: s.Code, Detail: s.Detail})
+				return
+			}
+			writeProblem(w, r, s.Status, s.Code, s.Detail)
+			return
+		}
+	}
+
+	slog.ErrorContext(r.Context(), "failed to handle request",
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.Any("err", err),
+	)
+	writeProblem(w, r, http.StatusInternalServerError, codeInternalError, "")
+}
+
+// writeValidationProblem は入力の検証エラーを書き出す
+func writeValidationProblem(w http.ResponseWriter, r *http.Request, fields ...fieldError) {
+	writeProblemDetails(w, r, problemDetails{
+		Status: http.StatusBadRequest,
+		Detail: "request validation failed",
+		Code:   codeValidationFailed,
+		Errors: fields,
+	})
+}
+
+// writeProblem は detail が空の場合、ステータスコードの説明だけを返す
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, code errorCode, detail string) {
+	writeProblemDetails(w, r, problemDetails{
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	})
+}
+
+func writeProblemDetails(w http.ResponseWriter, r *http.Request, p problemDetails) {
+	p.Type = "about:blank"
+	p.Title = http.StatusText(p.Status)
+	p.Instance = r.URL.Path
+	p.RequestID = middleware.GetReqID(r.Context())
+
+	body, err := json.Marshal(p)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to marshal problem", slog.Any("err", err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	if _, err := w.Write(body); err != nil {
+		slog.ErrorContext(r.Context(), "failed to write response", slog.Any("err", err))
+	}
+}
+
+// writeInvalidJSON はリクエストの本文を JSON として読めなかったことを返す
+func writeInvalidJSON(w http.ResponseWriter, r *http.Request) {
+	writeProblem(w, r, http.StatusBadRequest, codeInvalidJSON, "request body is not valid JSON")
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/quietsato/toy-small-chat/api/internal/di"
+	"github.com/quietsato/toy-small-chat/api/internal/server/middlewares/ratelimit"
+)
+
+// rateLimit は name のバケットで limit を超えたリクエストを 429 で拒否する
+//
+// v1 とバージョンのない別名は同じ name を使い、どちらのパスからのリクエストも合わせて数える
+func rateLimit(dic *di.Container, name string, limit ratelimit.Limit, key ratelimit.KeyFunc) func(http.Handler) http.Handler {
+	return ratelimit.Handler(dic.RateLimit.Store, ratelimit.Policy{Name: name, Limit: limit, Key: key}, func(w http.ResponseWriter, r *http.Request) {
+		writeProblem(w, r, http.StatusTooManyRequests, codeTooManyRequests, "rate limit exceeded, retry after the number of seconds in Retry-After")
+	})
+}
+
+// byAccount はログインしたアカウントの ID をキーにする。accountCtx の後に使う
+func byAccount(r *http.Request) (string, bool) {
+	accountID := getAccountIDFromContext(r.Context())
+	if accountID == nil {
+		return "", false
+	}
+	return *accountID, true
+}
@@ -0,0 +1,94 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/controller"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase/queryprocessor"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/di"
+)
+
+func getRoomRetention(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		roomID := getRoomIDFromContext(ctx)
+		if roomID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		c := controller.NewGetRoomRetentionController(dic.Retention.Query, dic.Retention.DefaultDays)
+		retention, err := c.GetRoomRetention(ctx, controller.GetRoomRetentionInput{RoomID: *roomID})
+		if err != nil {
+			writeError(w, r, err, retentionProblems...)
+			return
+		}
+
+		res, err := json.Marshal(retention)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
+			return
+		}
+
+		if _, err := w.Write(res); err != nil {
+			slog.ErrorContext(ctx, "failed to write response", slog.Any("err", err))
+		}
+	})
+}
+
+func setRoomRetention(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		roomID := getRoomIDFromContext(ctx)
+		accountID := getAccountIDFromContext(ctx)
+		if roomID == nil || accountID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		defer r.Body.Close()
+		bytes, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "failed to read request body")
+			return
+		}
+
+		inp := controller.SetRoomRetentionInput{}
+		if err := json.Unmarshal(bytes, &inp); err != nil {
+			writeInvalidJSON(w, r)
+			return
+		}
+		inp.RoomID = *roomID
+		inp.AccountID = *accountID
+
+		c := controller.NewSetRoomRetentionController(dic.Retention.Repo, dic.Retention.DefaultDays)
+		retention, err := c.SetRoomRetention(ctx, inp)
+		if err != nil {
+			writeError(w, r, err, retentionProblems...)
+			return
+		}
+
+		res, err := json.Marshal(retention)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
+			return
+		}
+
+		if _, err := w.Write(res); err != nil {
+			slog.ErrorContext(ctx, "failed to write response", slog.Any("err", err))
+		}
+	})
+}
+
+var retentionProblems = []problemSpec{
+	{Err: repository.ErrNotRoomOwner, Status: http.StatusForbidden, Code: codeNotRoomOwner, Detail: "only the room owner can change the retention"},
+	{Err: repository.ErrRoomNotFound, Status: http.StatusNotFound, Code: codeRoomNotFound, Detail: "room not found"},
+	{Err: queryprocessor.ErrRoomNotFound, Status: http.StatusNotFound, Code: codeRoomNotFound, Detail: "room not found"},
+}
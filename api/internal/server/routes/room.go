@@ -3,13 +3,21 @@ package routes
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/quietsato/toy-small-chat/api/internal/applications/room/controller"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/queryprocessor"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/repository"
+	webhookcontroller "github.com/quietsato/toy-small-chat/api/internal/applications/webhook/controller"
 	"github.com/quietsato/toy-small-chat/api/internal/di"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument/tracing"
 )
 
 type ctxKeyRoomID struct{}
@@ -27,6 +35,7 @@ func roomCtx(next http.Handler) http.Handler {
 		roomID := chi.URLParam(r, "roomID")
 		slog.InfoContext(ctx, "RoomCtx", slog.String("roomID", roomID))
 		ctx = context.WithValue(ctx, ctxKeyRoomID{}, roomID)
+		tracing.SetAttributes(ctx, tracing.RoomIDKey.String(roomID))
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
@@ -35,23 +44,44 @@ func getRooms(dic *di.Container) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
+		accountID := getAccountIDFromContext(ctx)
+		if accountID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		q := r.URL.Query()
+		inp := controller.GetRoomsInput{
+			AccountID: *accountID,
+			Query:     q.Get("q"),
+			Sort:      q.Get("sort"),
+			Filter:    q.Get("filter"),
+			Cursor:    q.Get("cursor"),
+		}
+		if v := q.Get("limit"); v != "" {
+			var err error
+			if inp.Limit, err = strconv.Atoi(v); err != nil {
+				writeValidationProblem(w, r, fieldError{Field: "limit", Code: codeInvalidLimit, Detail: "limit must be an integer"})
+				return
+			}
+		}
+
 		c := controller.NewGetRoomsController(dic.Room.Query)
-		rooms, err := c.GetRooms(ctx, controller.GetRoomsInput{})
+		rooms, err := c.GetRooms(ctx, inp)
 		if err != nil {
-			slog.Error("failed to get rooms", slog.Any("err", err))
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			writeError(w, r, err, roomProblems...)
 			return
 		}
 
 		res, err := json.Marshal(rooms)
 		if err != nil {
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
 			return
 		}
 
 		_, err = w.Write(res)
 		if err != nil {
-			slog.Error("failed to write", slog.Any("err", err))
+			slog.ErrorContext(r.Context(), "failed to write", slog.Any("err", err))
 		}
 	})
 }
@@ -62,41 +92,296 @@ func createRoom(dic *di.Container) http.HandlerFunc {
 
 		accountID := getAccountIDFromContext(ctx)
 		if accountID == nil {
-			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
 			return
 		}
 
 		defer r.Body.Close()
 		bytes, err := io.ReadAll(r.Body)
 		if err != nil {
-			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "failed to read request body")
 			return
 		}
 
 		inp := controller.CreateRoomInput{}
 		inp.CreatedBy = *accountID
 		if err := json.Unmarshal(bytes, &inp); err != nil {
-			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			writeInvalidJSON(w, r)
 			return
 		}
 
 		c := controller.NewCreateRoomController(dic.Room.Repo)
 		rooms, err := c.CreateRoom(ctx, inp)
 		if err != nil {
-			slog.Error("failed to create room", slog.Any("err", err))
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			writeError(w, r, err, roomProblems...)
 			return
 		}
 
 		res, err := json.Marshal(rooms)
 		if err != nil {
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
 			return
 		}
 
 		_, err = w.Write(res)
 		if err != nil {
-			slog.Error("failed to write", slog.Any("err", err))
+			slog.ErrorContext(r.Context(), "failed to write", slog.Any("err", err))
 		}
 	})
 }
+
+func updateRoom(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		roomID := getRoomIDFromContext(ctx)
+		accountID := getAccountIDFromContext(ctx)
+		if roomID == nil || accountID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		defer r.Body.Close()
+		bytes, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "failed to read request body")
+			return
+		}
+
+		inp := controller.UpdateRoomInput{}
+		if err := json.Unmarshal(bytes, &inp); err != nil {
+			writeInvalidJSON(w, r)
+			return
+		}
+		inp.RoomID = *roomID
+		inp.AccountID = *accountID
+
+		room, err := applyRoomUpdate(ctx, dic, inp)
+		if err != nil {
+			writeError(w, r, err, roomProblems...)
+			return
+		}
+
+		res, err := json.Marshal(room)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
+			return
+		}
+
+		if _, err := w.Write(res); err != nil {
+			slog.ErrorContext(ctx, "failed to write response", slog.Any("err", err))
+		}
+	})
+}
+
+// applyRoomUpdate はルームを更新し、変更があった場合は送信 Webhook に通知する
+//
+// PATCH /rooms/{roomID} と /topic コマンドで共通の処理
+func applyRoomUpdate(ctx context.Context, dic *di.Container, inp controller.UpdateRoomInput) (controller.UpdateRoomOutput, error) {
+	c := controller.NewUpdateRoomController(dic.Room.Repo)
+	room, err := c.UpdateRoom(ctx, inp)
+	if err != nil {
+		return controller.UpdateRoomOutput{}, err
+	}
+
+	if len(room.Events) > 0 {
+		enqueueWebhookEvent(ctx, dic, inp.RoomID, domain.WebhookEventRoomUpdated, webhookcontroller.RoomUpdatedData{
+			ID:    room.ID,
+			Name:  room.Name,
+			Topic: room.Topic,
+		})
+	}
+	return room, nil
+}
+
+func archiveRoom(dic *di.Container) http.HandlerFunc {
+	return changeRoomArchived(dic, (*controller.ArchiveRoomController).ArchiveRoom, domain.WebhookEventRoomArchived)
+}
+
+func unarchiveRoom(dic *di.Container) http.HandlerFunc {
+	return changeRoomArchived(dic, (*controller.ArchiveRoomController).UnarchiveRoom, domain.WebhookEventRoomUnarchived)
+}
+
+// changeRoomArchived はアーカイブ・解除で共通のリクエスト処理を行う
+//
+// アーカイブの状態が変わった場合は webhookEvent を配信する。タイムラインへの記録はリポジトリが同じトランザクションで行う
+func changeRoomArchived(
+	dic *di.Container,
+	action func(c *controller.ArchiveRoomController, ctx context.Context, inp controller.ArchiveRoomInput) (controller.ArchiveRoomOutput, error),
+	webhookEvent domain.WebhookEventType,
+) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		roomID := getRoomIDFromContext(ctx)
+		accountID := getAccountIDFromContext(ctx)
+		if roomID == nil || accountID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		c := controller.NewArchiveRoomController(dic.Room.Repo)
+		out, err := action(c, ctx, controller.ArchiveRoomInput{
+			RoomID:    *roomID,
+			AccountID: *accountID,
+		})
+		if err != nil {
+			writeError(w, r, err, roomProblems...)
+			return
+		}
+
+		if out.Changed {
+			enqueueWebhookEvent(ctx, dic, *roomID, webhookEvent, webhookcontroller.RoomData{ID: *roomID})
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func deleteRoom(dic *di.Container) http.HandlerFunc {
+	return changeRoomDeleted(dic, (*controller.DeleteRoomController).DeleteRoom, domain.WebhookEventRoomDeleted, false)
+}
+
+func restoreRoom(dic *di.Container) http.HandlerFunc {
+	return changeRoomDeleted(dic, (*controller.DeleteRoomController).RestoreRoom, "", false)
+}
+
+// adminDeleteRoom は作成者に代わって管理者がルームを削除する
+func adminDeleteRoom(dic *di.Container) http.HandlerFunc {
+	return changeRoomDeleted(dic, (*controller.DeleteRoomController).DeleteRoom, domain.WebhookEventRoomDeleted, true)
+}
+
+// adminRestoreRoom は作成者に代わって管理者が削除したルームを復元する
+func adminRestoreRoom(dic *di.Container) http.HandlerFunc {
+	return changeRoomDeleted(dic, (*controller.DeleteRoomController).RestoreRoom, "", true)
+}
+
+// changeRoomDeleted は削除・復元で共通のリクエスト処理を行う
+//
+// webhookEvent が空でない場合は操作の後に配信する。byAdmin が true の場合は管理用 API として、操作者のアカウントを読まない
+func changeRoomDeleted(
+	dic *di.Container,
+	action func(c *controller.DeleteRoomController, ctx context.Context, inp controller.DeleteRoomInput) (controller.DeleteRoomOutput, error),
+	webhookEvent domain.WebhookEventType,
+	byAdmin bool,
+) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		roomID := getRoomIDFromContext(ctx)
+		if roomID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+		inp := controller.DeleteRoomInput{RoomID: *roomID, ByAdmin: byAdmin}
+		if !byAdmin {
+			accountID := getAccountIDFromContext(ctx)
+			if accountID == nil {
+				writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+				return
+			}
+			inp.AccountID = *accountID
+		}
+
+		c := controller.NewDeleteRoomController(dic.Room.Repo, dic.Room.DeletionGracePeriod)
+		if _, err := action(c, ctx, inp); err != nil {
+			writeError(w, r, err, roomProblems...)
+			return
+		}
+
+		if webhookEvent != "" {
+			enqueueWebhookEvent(ctx, dic, *roomID, webhookEvent, webhookcontroller.RoomData{ID: *roomID})
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func getNotificationSettings(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		roomID := getRoomIDFromContext(ctx)
+		accountID := getAccountIDFromContext(ctx)
+		if roomID == nil || accountID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		c := controller.NewGetNotificationSettingsController(dic.Room.Query)
+		settings, err := c.GetNotificationSettings(ctx, controller.GetNotificationSettingsInput{
+			RoomID:    *roomID,
+			AccountID: *accountID,
+		})
+		if err != nil {
+			writeError(w, r, err, roomProblems...)
+			return
+		}
+
+		res, err := json.Marshal(settings)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
+			return
+		}
+
+		if _, err := w.Write(res); err != nil {
+			slog.ErrorContext(ctx, "failed to write response", slog.Any("err", err))
+		}
+	})
+}
+
+func updateNotificationSettings(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		roomID := getRoomIDFromContext(ctx)
+		accountID := getAccountIDFromContext(ctx)
+		if roomID == nil || accountID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		defer r.Body.Close()
+		bytes, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "failed to read request body")
+			return
+		}
+
+		inp := controller.UpdateNotificationSettingsInput{}
+		if err := json.Unmarshal(bytes, &inp); err != nil {
+			writeInvalidJSON(w, r)
+			return
+		}
+		inp.RoomID = *roomID
+		inp.AccountID = *accountID
+
+		c := controller.NewUpdateNotificationSettingsController(dic.Room.Repo)
+		settings, err := c.UpdateNotificationSettings(ctx, inp)
+		if err != nil {
+			writeError(w, r, err, roomProblems...)
+			return
+		}
+
+		res, err := json.Marshal(settings)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
+			return
+		}
+
+		if _, err := w.Write(res); err != nil {
+			slog.ErrorContext(ctx, "failed to write response", slog.Any("err", err))
+		}
+	})
+}
+
+var roomProblems = []problemSpec{
+	{Err: controller.ErrInvalidPagination, Status: http.StatusBadRequest, Code: codeInvalidPagination, Detail: "limit or cursor is invalid"},
+	{Err: controller.ErrInvalidRoomsQuery, Status: http.StatusBadRequest, Code: codeInvalidQuery, Detail: "q, sort or filter is invalid"},
+	{Err: usecase.ErrNoRoomChanges, Status: http.StatusBadRequest, Code: codeNoChanges, Detail: "at least one of name or topic is required"},
+	{Err: domain.ErrInvalidNotificationLevel, Code: codeInvalidNotificationLevel, Field: "level", Detail: "level is not a known notification level"},
+	{Err: usecase.ErrInvalidMutedUntil, Code: codeInvalidMutedUntil, Field: "mutedUntil", Detail: "mutedUntil must be a future RFC 3339 time"},
+	{Err: repository.ErrNotRoomOwner, Status: http.StatusForbidden, Code: codeNotRoomOwner, Detail: "only the room owner can perform this operation"},
+	{Err: repository.ErrRoomNotFound, Status: http.StatusNotFound, Code: codeRoomNotFound, Detail: "room not found"},
+	{Err: queryprocessor.ErrRoomNotFound, Status: http.StatusNotFound, Code: codeRoomNotFound, Detail: "room not found"},
+	{Err: repository.ErrRoomArchived, Status: http.StatusConflict, Code: codeRoomArchived, Detail: "room is archived"},
+	{Err: repository.ErrRestorePeriodExpired, Status: http.StatusGone, Code: codeRoomRestoreExpired, Detail: "the restore period has expired"},
+}
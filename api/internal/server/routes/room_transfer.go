@@ -0,0 +1,76 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/roomtransfer/controller"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/roomtransfer/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/roomtransfer/usecase/queryprocessor"
+	"github.com/quietsato/toy-small-chat/api/internal/di"
+)
+
+func exportRooms(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		ww := &writtenTracker{ResponseWriter: w}
+
+		c := controller.NewExportRoomsController(dic.RoomTransfer.Query)
+		if err := c.ExportRooms(ctx, controller.ExportRoomsInput{
+			RoomID: r.URL.Query().Get("roomId"),
+		}, ww); err != nil {
+			if ww.written {
+				// ステータスコードは送信済みのため、途中で打ち切ったことは記録だけ残す
+				slog.ErrorContext(ctx, "failed to stream room export", slog.Any("err", err))
+				return
+			}
+			w.Header().Del("Content-Type")
+			writeError(w, r, err, roomTransferProblems...)
+		}
+	})
+}
+
+func importRooms(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		defer r.Body.Close()
+
+		c := controller.NewImportRoomsController(dic.RoomTransfer.Repo)
+		res, err := c.ImportRooms(ctx, r.Body)
+		if err != nil {
+			writeError(w, r, err, roomTransferProblems...)
+			return
+		}
+
+		body, err := json.Marshal(res)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		if _, err := w.Write(body); err != nil {
+			slog.ErrorContext(ctx, "failed to write response", slog.Any("err", err))
+		}
+	})
+}
+
+// writtenTracker はレスポンスを書き始めたかどうかを記録する
+type writtenTracker struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (w *writtenTracker) Write(b []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(b)
+}
+
+var roomTransferProblems = []problemSpec{
+	{Err: usecase.ErrInvalidImport, Status: http.StatusBadRequest, Code: codeInvalidImport, Detail: "import is not a valid room export"},
+	{Err: queryprocessor.ErrRoomNotFound, Status: http.StatusNotFound, Code: codeRoomNotFound, Detail: "room not found"},
+}
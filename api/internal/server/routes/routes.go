@@ -1,36 +1,147 @@
 package routes
 
 import (
+	"net/http"
+	"time"
+
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/jwtauth/v5"
 	"github.com/quietsato/toy-small-chat/api/internal/di"
+	"github.com/quietsato/toy-small-chat/api/internal/server/middlewares/ratelimit"
 )
 
+// unversionedDeprecation はバージョンのないパスで公開している v1 の別名の廃止予定
+var unversionedDeprecation = deprecation{
+	Since:  time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+	Sunset: time.Date(2027, 4, 19, 0, 0, 0, 0, time.UTC),
+}
+
+// Setup は API のルートを登録する
+//
+// バージョンごとのルーターは /v1 のようにパスの先頭で分け、同じ di.Container を共有する。
+// v2 を追加する場合も setupV2 を作って r.Route("/v2", ...) で並べる
 func Setup(r *chi.Mux, dic *di.Container) {
+	// レスポンスは JSON を既定とし、それ以外を返すハンドラは自身で上書きする
+	r.Use(middleware.SetHeader("Content-Type", "application/json"))
+
+	// サブルーターは登録時にこれらを引き継ぐため、ルートより先に設定する
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		writeProblem(w, r, http.StatusNotFound, codeNotFound, "")
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		writeProblem(w, r, http.StatusMethodNotAllowed, codeMethodNotAllowed, "")
+	})
+
+	// 死活監視は API のバージョンに依存しないため、バージョンのないパスにだけ公開する
+	r.Get("/healthz", getHealthz())
+	r.Get("/readyz", getReadyz(dic))
+	r.Route("/debug", func(r chi.Router) {
+		r.Use(adminAuth(dic.Auth.AdminToken))
+		r.Get("/status", getDebugStatus(dic))
+	})
+
+	r.Route("/v1", func(r chi.Router) {
+		setupV1(r, dic)
+	})
+
+	// 移行期間のあいだ、バージョンのないパスも v1 の別名として公開する
+	r.Group(func(r chi.Router) {
+		r.Use(deprecated(unversionedDeprecation, func(r *http.Request) string {
+			return "/v1" + r.URL.RequestURI()
+		}))
+		setupV1(r, dic)
+	})
+}
+
+// setupV1 は v1 のルートを登録する
+//
+// v1 のリクエストとレスポンスは互換性を保ったまま変更する。
+// フィールドを廃止する場合は deprecation で廃止予定のヘッダを付け、削除は次のバージョンで行う
+func setupV1(r chi.Router, dic *di.Container) {
 	tokenAuth := dic.Auth.Middleware.GetTokenAuthForMiddleware()
 
 	// Public Routes
 	r.Group(func(r chi.Router) {
-		r.Post("/login", login(dic))
+		r.Get("/openapi.json", getOpenAPI())
+		r.With(rateLimit(dic, "login", dic.RateLimit.Login, ratelimit.ByIP)).Post("/login", login(dic))
 		r.Route("/accounts", func(r chi.Router) {
-			r.Post("/", createAccount(dic))
+			r.With(rateLimit(dic, "signup", dic.RateLimit.Signup, ratelimit.ByIP)).Post("/", createAccount(dic))
+		})
+		// 受信 Webhook は URL に含まれるトークンで認証する
+		r.Post("/hooks/{webhookID}/{token}", postIncomingWebhook(dic))
+	})
+	// Admin Routes
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(adminAuth(dic.Auth.AdminToken))
+		r.Get("/export", exportRooms(dic))
+		r.Post("/import", importRooms(dic))
+		r.Route("/rooms/{roomID}", func(r chi.Router) {
+			r.Use(roomCtx)
+			r.Delete("/", adminDeleteRoom(dic))
+			r.Post("/restore", adminRestoreRoom(dic))
 		})
 	})
 	// Protected Routes
 	r.Group(func(r chi.Router) {
 		r.Use(jwtauth.Verifier(tokenAuth))
-		r.Use(jwtauth.Authenticator(tokenAuth))
+		r.Use(authenticate)
 		r.Use(accountCtx)
 		// Room
 		r.Route("/rooms", func(r chi.Router) {
 			r.Get("/", getRooms(dic))
 			r.Post("/", createRoom(dic))
+			r.Route("/{roomID}", func(r chi.Router) {
+				r.Use(roomCtx)
+				r.Patch("/", updateRoom(dic))
+				r.Delete("/", deleteRoom(dic))
+				r.Post("/archive", archiveRoom(dic))
+				r.Post("/unarchive", unarchiveRoom(dic))
+				r.Post("/restore", restoreRoom(dic))
+				r.Get("/settings", getNotificationSettings(dic))
+				r.Put("/settings", updateNotificationSettings(dic))
+				r.Get("/webhooks", getWebhooks(dic))
+				r.Post("/webhooks", createWebhook(dic))
+				r.Delete("/webhooks/{webhookID}", deleteWebhook(dic))
+				r.Get("/webhooks/{webhookID}/deliveries", getWebhookDeliveries(dic))
+				r.Get("/incoming-webhooks", getIncomingWebhooks(dic))
+				r.Post("/incoming-webhooks", createIncomingWebhook(dic))
+				r.Post("/incoming-webhooks/{webhookID}/rotate", rotateIncomingWebhookToken(dic))
+				r.Post("/incoming-webhooks/{webhookID}/revoke", revokeIncomingWebhook(dic))
+				r.Get("/commands", getSlashCommands(dic))
+				r.Post("/commands", createSlashCommand(dic))
+				r.Delete("/commands/{commandID}", deleteSlashCommand(dic))
+				r.Get("/scheduled-messages", getScheduledMessages(dic))
+				r.Post("/scheduled-messages", scheduleMessage(dic))
+				r.Delete("/scheduled-messages/{scheduledMessageID}", cancelScheduledMessage(dic))
+				r.Get("/retention", getRoomRetention(dic))
+				r.Put("/retention", setRoomRetention(dic))
+			})
 		})
 		// Message
 		r.Route("/rooms/{roomID}/messages", func(r chi.Router) {
 			r.Use(roomCtx)
 			r.Get("/", getMessages(dic))
-			r.Post("/", createMessage(dic))
+			r.With(rateLimit(dic, "messages", dic.RateLimit.Messages, byAccount)).Post("/", createMessage(dic))
+		})
+		// Pin
+		r.Route("/rooms/{roomID}/pins", func(r chi.Router) {
+			r.Use(roomCtx)
+			r.Get("/", getPins(dic))
+			r.Put("/{messageID}", pinMessage(dic))
+			r.Delete("/{messageID}", unpinMessage(dic))
+		})
+		// Attachment
+		r.Route("/rooms/{roomID}/attachments", func(r chi.Router) {
+			r.Use(roomCtx)
+			r.Post("/", uploadAttachment(dic))
+			r.Get("/{attachmentID}", getAttachment(dic, false))
+			r.Get("/{attachmentID}/thumbnail", getAttachment(dic, true))
+		})
+		// Mention
+		r.Route("/me/mentions", func(r chi.Router) {
+			r.Get("/", getMentions(dic))
+			r.Post("/read", markMentionsAsRead(dic))
 		})
 	})
 }
@@ -2,21 +2,48 @@ package routes_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/jwtauth/v5"
 	"github.com/quietsato/toy-small-chat/api/internal/applications/account/infrastructure/serviceimpl"
 	"github.com/quietsato/toy-small-chat/api/internal/applications/room/infrastructure/queryprocessorimpl"
 	"github.com/quietsato/toy-small-chat/api/internal/di"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/server/middlewares/ratelimit"
 	"github.com/quietsato/toy-small-chat/api/internal/server/routes"
 	"github.com/stretchr/testify/require"
 )
 
 func TestPublicRoutes(t *testing.T) {
 	t.Parallel()
+
+	t.Run("受信 Webhook のトークンが不正な場合は UnauthorizedError", func(t *testing.T) {
+		t.Parallel()
+
+		auth := serviceimpl.NewAuthService([]byte("dummy"), time.Hour)
+
+		r := chi.NewRouter()
+		routes.Setup(r, &di.Container{
+			Auth: di.AuthDeps{
+				Service:    auth,
+				Middleware: auth,
+			},
+		})
+
+		body := bytes.NewBufferString(`{"content":"build passed"}`)
+		req := httptest.NewRequest(http.MethodPost, "/hooks/8481027d-d6f6-402f-ae6d-98571e8f6496/invalid", body)
+		rr := httptest.NewRecorder()
+
+		r.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusUnauthorized, rr.Result().StatusCode)
+	})
 }
 
 func TestProtectedRoutes(t *testing.T) {
@@ -26,7 +53,7 @@ func TestProtectedRoutes(t *testing.T) {
 		t.Parallel()
 
 		secretKey := []byte("dummy")
-		auth := serviceimpl.NewAuthService(secretKey)
+		auth := serviceimpl.NewAuthService(secretKey, time.Hour)
 
 		r := chi.NewRouter()
 		routes.Setup(r, &di.Container{
@@ -49,7 +76,7 @@ func TestProtectedRoutes(t *testing.T) {
 		t.Parallel()
 
 		secretKey := []byte("dummy")
-		auth := serviceimpl.NewAuthService(secretKey)
+		auth := serviceimpl.NewAuthService(secretKey, time.Hour)
 
 		r := chi.NewRouter()
 		routes.Setup(r, &di.Container{
@@ -76,3 +103,371 @@ func TestProtectedRoutes(t *testing.T) {
 		require.Equal(t, http.StatusUnauthorized, rr.Result().StatusCode)
 	})
 }
+
+func TestAdminRoutes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		adminToken string
+		header     string
+		expected   int
+	}{
+		{"管理用トークンが未設定の場合は NotFound", "", "Bearer anything", http.StatusNotFound},
+		{"トークンがない場合は UnauthorizedError", "admin-token", "", http.StatusUnauthorized},
+		{"トークンが異なる場合は UnauthorizedError", "admin-token", "Bearer wrong-token", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			auth := serviceimpl.NewAuthService([]byte("dummy"), time.Hour)
+
+			r := chi.NewRouter()
+			routes.Setup(r, &di.Container{
+				Auth: di.AuthDeps{
+					Service:    auth,
+					Middleware: auth,
+					AdminToken: tt.adminToken,
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/export", nil)
+			if tt.header != "" {
+				req.Header.Add("Authorization", tt.header)
+			}
+			rr := httptest.NewRecorder()
+
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expected, rr.Result().StatusCode)
+		})
+	}
+}
+
+func TestErrorResponses(t *testing.T) {
+	t.Parallel()
+
+	type fieldError struct {
+		Field string `json:"field"`
+		Code  string `json:"code"`
+	}
+	type problem struct {
+		Type      string       `json:"type"`
+		Title     string       `json:"title"`
+		Status    int          `json:"status"`
+		Instance  string       `json:"instance"`
+		Code      string       `json:"code"`
+		RequestID string       `json:"requestId"`
+		Errors    []fieldError `json:"errors"`
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		status   int
+		code     string
+		errField string
+	}{
+		{"JWT がない場合は unauthorized", http.MethodGet, "/rooms", "", http.StatusUnauthorized, "unauthorized", ""},
+		{"JSON として不正な場合は invalid_json", http.MethodPost, "/accounts", "{", http.StatusBadRequest, "invalid_json", ""},
+		{"不正なユーザー名の場合はフィールドのエラーを返す", http.MethodPost, "/accounts", `{"username":"al ice","password":"password"}`, http.StatusBadRequest, "validation_failed", "username"},
+		{"存在しないパスの場合は not_found", http.MethodGet, "/unknown", "", http.StatusNotFound, "not_found", ""},
+		{"許可されていないメソッドの場合は method_not_allowed", http.MethodGet, "/login", "", http.StatusMethodNotAllowed, "method_not_allowed", ""},
+		{"v1 で存在しないパスの場合は not_found", http.MethodGet, "/v1/unknown", "", http.StatusNotFound, "not_found", ""},
+		{"v1 で許可されていないメソッドの場合は method_not_allowed", http.MethodGet, "/v1/login", "", http.StatusMethodNotAllowed, "method_not_allowed", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			auth := serviceimpl.NewAuthService([]byte("dummy"), time.Hour)
+
+			r := chi.NewRouter()
+			r.Use(middleware.RequestID)
+			routes.Setup(r, &di.Container{
+				Auth: di.AuthDeps{
+					Service:    auth,
+					Middleware: auth,
+				},
+			})
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			rr := httptest.NewRecorder()
+
+			r.ServeHTTP(rr, req)
+
+			res := rr.Result()
+			require.Equal(t, tt.status, res.StatusCode)
+			require.Equal(t, "application/problem+json", res.Header.Get("Content-Type"))
+
+			var p problem
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&p))
+			require.Equal(t, "about:blank", p.Type)
+			require.Equal(t, http.StatusText(tt.status), p.Title)
+			require.Equal(t, tt.status, p.Status)
+			require.Equal(t, tt.path, p.Instance)
+			require.Equal(t, tt.code, p.Code)
+			require.NotEmpty(t, p.RequestID)
+			if tt.errField != "" {
+				require.Len(t, p.Errors, 1)
+				require.Equal(t, tt.errField, p.Errors[0].Field)
+			}
+		})
+	}
+}
+
+func TestVersionedRoutes(t *testing.T) {
+	t.Parallel()
+
+	dic := newStubContainer(domain.GenerateIncomingWebhookToken())
+	token := "Bearer " + dic.Auth.Service.GenerateToken(stubAccountID)
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		body        string
+		status      int
+		deprecation string
+		sunset      string
+		link        string
+	}{
+		{"v1 のルートには廃止予定のヘッダを付けない", http.MethodGet, "/v1/rooms", "", http.StatusOK, "", "", ""},
+		{"バージョンのないパスには廃止予定のヘッダと v1 へのリンクを付ける", http.MethodGet, "/rooms?limit=1", "", http.StatusOK, "@1792368000", "Mon, 19 Apr 2027 00:00:00 GMT", `</v1/rooms?limit=1>; rel="successor-version"`},
+		{"存在しないパスには廃止予定のヘッダを付けない", http.MethodGet, "/unknown", "", http.StatusNotFound, "", "", ""},
+		{"廃止予定のフィールドを使った場合は Deprecation を付ける", http.MethodPost, "/v1/rooms/" + stubRoomID + "/messages", `{"roomID":"` + stubRoomID + `","content":"hello"}`, http.StatusOK, "@1792368000", "", ""},
+		{"廃止予定のフィールドを使わない場合は Deprecation を付けない", http.MethodPost, "/v1/rooms/" + stubRoomID + "/messages", `{"content":"hello"}`, http.StatusOK, "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewRouter()
+			routes.Setup(r, dic)
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Authorization", token)
+			rr := httptest.NewRecorder()
+
+			r.ServeHTTP(rr, req)
+
+			res := rr.Result()
+			require.Equal(t, tt.status, res.StatusCode)
+			require.Equal(t, tt.deprecation, res.Header.Get("Deprecation"))
+			require.Equal(t, tt.sunset, res.Header.Get("Sunset"))
+			require.Equal(t, tt.link, res.Header.Get("Link"))
+		})
+	}
+}
+
+func TestRateLimitedRoutes(t *testing.T) {
+	t.Parallel()
+
+	send := func(r http.Handler, method, path, remoteAddr, authorization, body string) *http.Response {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.RemoteAddr = remoteAddr
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr.Result()
+	}
+
+	t.Run("アカウント作成は IP アドレスごとに制限し、v1 とバージョンのないパスで上限を共有する", func(t *testing.T) {
+		t.Parallel()
+
+		dic := newStubContainer(domain.GenerateIncomingWebhookToken())
+		dic.RateLimit = di.RateLimitDeps{
+			Store:  ratelimit.NewMemoryStore(),
+			Signup: ratelimit.Limit{Requests: 1, Per: time.Hour},
+		}
+		r := chi.NewRouter()
+		routes.Setup(r, dic)
+		body := `{"username":"alice","password":"password1"}`
+
+		res := send(r, http.MethodPost, "/v1/accounts", "192.0.2.1:1234", "", body)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, "1", res.Header.Get("RateLimit-Limit"))
+		require.Equal(t, "0", res.Header.Get("RateLimit-Remaining"))
+		require.Equal(t, "3600", res.Header.Get("RateLimit-Reset"))
+		require.Equal(t, "1;w=3600", res.Header.Get("RateLimit-Policy"))
+
+		res = send(r, http.MethodPost, "/accounts", "192.0.2.1:5678", "", body)
+		require.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+		require.Equal(t, "application/problem+json", res.Header.Get("Content-Type"))
+		require.Equal(t, "3600", res.Header.Get("Retry-After"))
+		var p struct {
+			Code string `json:"code"`
+		}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&p))
+		require.Equal(t, "too_many_requests", p.Code)
+
+		res = send(r, http.MethodPost, "/v1/accounts", "192.0.2.2:1234", "", body)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		// ログインは別の上限で数える
+		res = send(r, http.MethodPost, "/v1/login", "192.0.2.1:1234", "", body)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Empty(t, res.Header.Get("RateLimit-Limit"))
+	})
+
+	t.Run("メッセージの投稿はアカウントごとに制限し、一覧の取得は制限しない", func(t *testing.T) {
+		t.Parallel()
+
+		dic := newStubContainer(domain.GenerateIncomingWebhookToken())
+		dic.RateLimit = di.RateLimitDeps{
+			Store:    ratelimit.NewMemoryStore(),
+			Messages: ratelimit.Limit{Requests: 1, Per: time.Minute},
+		}
+		r := chi.NewRouter()
+		routes.Setup(r, dic)
+		token := "Bearer " + dic.Auth.Service.GenerateToken(stubAccountID)
+		path := "/v1/rooms/" + stubRoomID + "/messages"
+
+		res := send(r, http.MethodPost, path, "192.0.2.1:1234", token, `{"content":"hello"}`)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		// IP アドレスが変わっても同じアカウントとして数える
+		res = send(r, http.MethodPost, path, "192.0.2.2:1234", token, `{"content":"hello"}`)
+		require.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+		require.Equal(t, "60", res.Header.Get("Retry-After"))
+
+		res = send(r, http.MethodGet, path, "192.0.2.1:1234", token, "")
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+}
+
+func TestMessageRoutes(t *testing.T) {
+	t.Parallel()
+
+	dic := newStubContainer(domain.GenerateIncomingWebhookToken())
+	token := "Bearer " + dic.Auth.Service.GenerateToken(stubAccountID)
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+	}{
+		{"不正なルーム ID のメッセージ一覧は room_not_found", http.MethodGet, ""},
+		{"不正なルーム ID へのメッセージの投稿は room_not_found", http.MethodPost, `{"content":"hello"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewRouter()
+			routes.Setup(r, dic)
+
+			req := httptest.NewRequest(tt.method, "/v1/rooms/not-a-uuid/messages", bytes.NewBufferString(tt.body))
+			req.Header.Set("Authorization", token)
+			rr := httptest.NewRecorder()
+
+			r.ServeHTTP(rr, req)
+
+			res := rr.Result()
+			require.Equal(t, http.StatusNotFound, res.StatusCode)
+			require.Equal(t, "application/problem+json", res.Header.Get("Content-Type"))
+			var p struct {
+				Code string `json:"code"`
+			}
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&p))
+			require.Equal(t, "room_not_found", p.Code)
+		})
+	}
+}
+
+func TestDeletedRoomRoutes(t *testing.T) {
+	t.Parallel()
+
+	dic := newStubContainer(domain.GenerateIncomingWebhookToken())
+	token := "Bearer " + dic.Auth.Service.GenerateToken(stubAccountID)
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"削除したルームのメッセージ一覧は room_not_found", "/v1/rooms/" + stubDeletedRoomID + "/messages"},
+		{"削除したルームのピン一覧は room_not_found", "/v1/rooms/" + stubDeletedRoomID + "/pins"},
+		{"削除したルームの添付ファイルは room_not_found", "/v1/rooms/" + stubDeletedRoomID + "/attachments/" + stubDeletedAttachID},
+		{"削除したルームのサムネイルは room_not_found", "/v1/rooms/" + stubDeletedRoomID + "/attachments/" + stubDeletedAttachID + "/thumbnail"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := chi.NewRouter()
+			routes.Setup(r, dic)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Authorization", token)
+			rr := httptest.NewRecorder()
+
+			r.ServeHTTP(rr, req)
+
+			res := rr.Result()
+			require.Equal(t, http.StatusNotFound, res.StatusCode)
+			require.Equal(t, "application/problem+json", res.Header.Get("Content-Type"))
+			var p struct {
+				Code string `json:"code"`
+			}
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&p))
+			require.Equal(t, "room_not_found", p.Code)
+		})
+	}
+}
+
+func TestIncomingWebhookRoutes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("失効した受信 Webhook はトークンを再発行しても投稿を受け付けない", func(t *testing.T) {
+		t.Parallel()
+
+		hookToken := domain.GenerateIncomingWebhookToken()
+		dic := newStubContainer(hookToken)
+		dic.IncomingWebhook.Repo = &revocableIncomingWebhookRepository{
+			stubIncomingWebhookRepository: stubIncomingWebhookRepository{token: hookToken},
+		}
+		r := chi.NewRouter()
+		routes.Setup(r, dic)
+		token := "Bearer " + dic.Auth.Service.GenerateToken(stubAccountID)
+		webhook := "/v1/rooms/" + stubRoomID + "/incoming-webhooks/" + stubWebhookID
+
+		send := func(path, authorization, body string) *http.Response {
+			req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+			if authorization != "" {
+				req.Header.Set("Authorization", authorization)
+			}
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+			return rr.Result()
+		}
+		code := func(res *http.Response) string {
+			var p struct {
+				Code string `json:"code"`
+			}
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&p))
+			return p.Code
+		}
+		hook := "/v1/hooks/" + stubWebhookID + "/" + hookToken.String()
+
+		require.Equal(t, http.StatusOK, send(hook, "", `{"content":"build passed"}`).StatusCode)
+		require.Equal(t, http.StatusNoContent, send(webhook+"/revoke", token, "").StatusCode)
+
+		res := send(webhook+"/rotate", token, "")
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+		require.Equal(t, "webhook_not_found", code(res))
+
+		res = send(hook, "", `{"content":"build passed"}`)
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+		require.Equal(t, "webhook_not_found", code(res))
+	})
+}
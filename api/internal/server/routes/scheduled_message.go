@@ -0,0 +1,141 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/controller"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase/queryprocessor"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/di"
+)
+
+func getScheduledMessages(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		roomID := getRoomIDFromContext(ctx)
+		accountID := getAccountIDFromContext(ctx)
+		if roomID == nil || accountID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		inp := controller.GetScheduledMessagesInput{
+			RoomID:   *roomID,
+			AuthorID: *accountID,
+		}
+		if v := r.URL.Query().Get("limit"); v != "" {
+			var err error
+			if inp.Limit, err = strconv.Atoi(v); err != nil {
+				writeValidationProblem(w, r, fieldError{Field: "limit", Code: codeInvalidLimit, Detail: "limit must be an integer"})
+				return
+			}
+		}
+
+		c := controller.NewGetScheduledMessagesController(dic.ScheduledMessage.Query)
+		messages, err := c.GetScheduledMessages(ctx, inp)
+		if err != nil {
+			writeError(w, r, err, scheduledMessageProblems...)
+			return
+		}
+
+		res, err := json.Marshal(messages)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
+			return
+		}
+
+		if _, err := w.Write(res); err != nil {
+			slog.ErrorContext(ctx, "failed to write response", slog.Any("err", err))
+		}
+	})
+}
+
+func scheduleMessage(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		roomID := getRoomIDFromContext(ctx)
+		accountID := getAccountIDFromContext(ctx)
+		if roomID == nil || accountID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		defer r.Body.Close()
+		bytes, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "failed to read request body")
+			return
+		}
+
+		inp := controller.ScheduleMessageInput{}
+		if err := json.Unmarshal(bytes, &inp); err != nil {
+			writeInvalidJSON(w, r)
+			return
+		}
+		inp.RoomID = *roomID
+		inp.AuthorID = *accountID
+
+		c := controller.NewScheduleMessageController(dic.ScheduledMessage.Repo)
+		msg, err := c.ScheduleMessage(ctx, inp)
+		if err != nil {
+			writeError(w, r, err, scheduledMessageProblems...)
+			return
+		}
+
+		res, err := json.Marshal(msg)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		if _, err := w.Write(res); err != nil {
+			slog.ErrorContext(ctx, "failed to write response", slog.Any("err", err))
+		}
+	})
+}
+
+func cancelScheduledMessage(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		roomID := getRoomIDFromContext(ctx)
+		accountID := getAccountIDFromContext(ctx)
+		if roomID == nil || accountID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		c := controller.NewCancelScheduledMessageController(dic.ScheduledMessage.Repo)
+		if err := c.CancelScheduledMessage(ctx, controller.CancelScheduledMessageInput{
+			RoomID:             *roomID,
+			ScheduledMessageID: chi.URLParam(r, "scheduledMessageID"),
+			AuthorID:           *accountID,
+		}); err != nil {
+			writeError(w, r, err, scheduledMessageProblems...)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+var scheduledMessageProblems = []problemSpec{
+	{Err: usecase.ErrInvalidSendAt, Code: codeInvalidSendAt, Field: "sendAt", Detail: "sendAt must be in the future and within the scheduling window"},
+	{Err: controller.ErrInvalidScheduledMessagesLimit, Code: codeInvalidLimit, Field: "limit", Detail: "limit is out of range"},
+	{Err: repository.ErrRoomNotFound, Status: http.StatusNotFound, Code: codeRoomNotFound, Detail: "room not found"},
+	{Err: queryprocessor.ErrRoomNotFound, Status: http.StatusNotFound, Code: codeRoomNotFound, Detail: "room not found"},
+	{Err: repository.ErrScheduledMessageNotFound, Status: http.StatusNotFound, Code: codeScheduledMessageNotFound, Detail: "scheduled message not found"},
+	{Err: repository.ErrScheduledMessageSending, Status: http.StatusConflict, Code: codeScheduledMessageSending, Detail: "scheduled message is being sent"},
+	{Err: repository.ErrRoomArchived, Status: http.StatusConflict, Code: codeRoomArchived, Detail: "room is archived"},
+	{Err: repository.ErrTooManyScheduledMessages, Status: http.StatusConflict, Code: codeLimitExceeded, Detail: "too many pending scheduled messages"},
+}
@@ -0,0 +1,333 @@
+package routes_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// openAPIDoc は OpenAPI ドキュメントのうち、テストで使う部分だけを読み込んだもの
+type openAPIDoc struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas    map[string]json.RawMessage `json:"schemas"`
+		Responses  map[string]json.RawMessage `json:"responses"`
+		Parameters map[string]json.RawMessage `json:"parameters"`
+		Headers    map[string]json.RawMessage `json:"headers"`
+	} `json:"components"`
+}
+
+type openAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	RequestBody *openAPIBody               `json:"requestBody"`
+	Responses   map[string]json.RawMessage `json:"responses"`
+}
+
+type openAPIBody struct {
+	Ref     string                      `json:"$ref"`
+	Content map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIMediaType struct {
+	Schema json.RawMessage `json:"schema"`
+}
+
+// httpMethods は paths の各パスのうち、operation を表すキー
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+func (d *openAPIDoc) operation(method, path string) (openAPIOperation, bool) {
+	raw, ok := d.Paths[path][strings.ToLower(method)]
+	if !ok {
+		return openAPIOperation{}, false
+	}
+	var op openAPIOperation
+	if err := json.Unmarshal(raw, &op); err != nil {
+		return openAPIOperation{}, false
+	}
+	return op, true
+}
+
+// response は $ref を解決したステータスコードごとのレスポンス
+func (d *openAPIDoc) response(op openAPIOperation, status int) (openAPIBody, error) {
+	raw, ok := op.Responses[fmt.Sprint(status)]
+	if !ok {
+		return openAPIBody{}, fmt.Errorf("status %d is not documented", status)
+	}
+	var res openAPIBody
+	if err := json.Unmarshal(raw, &res); err != nil {
+		return openAPIBody{}, err
+	}
+	if name, ok := strings.CutPrefix(res.Ref, "#/components/responses/"); ok {
+		raw, ok := d.Components.Responses[name]
+		if !ok {
+			return openAPIBody{}, fmt.Errorf("unresolved $ref %q", res.Ref)
+		}
+		res = openAPIBody{}
+		if err := json.Unmarshal(raw, &res); err != nil {
+			return openAPIBody{}, err
+		}
+	}
+	return res, nil
+}
+
+// schemaValidator は JSON Schema (2020-12) のうち、openapi.json で使うキーワードだけを検証する
+//
+// 知らないキーワードは検証できないためエラーにする
+type schemaValidator struct {
+	doc *openAPIDoc
+}
+
+// annotationKeywords は値を制約しないキーワード
+var annotationKeywords = []string{"description", "contentMediaType", "deprecated"}
+
+func (v schemaValidator) validate(schema json.RawMessage, value any) error {
+	return v.validateAt("$", schema, value)
+}
+
+func (v schemaValidator) validateAt(at string, raw json.RawMessage, value any) error {
+	var s map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return fmt.Errorf("%s: invalid schema: %w", at, err)
+	}
+
+	if ref, ok := s["$ref"]; ok {
+		var name string
+		if err := json.Unmarshal(ref, &name); err != nil {
+			return fmt.Errorf("%s: invalid $ref: %w", at, err)
+		}
+		resolved, ok := v.doc.Components.Schemas[strings.TrimPrefix(name, "#/components/schemas/")]
+		if !ok || !strings.HasPrefix(name, "#/components/schemas/") {
+			return fmt.Errorf("%s: unresolved $ref %q", at, name)
+		}
+		return v.validateAt(at, resolved, value)
+	}
+
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	// type を先に検証し、以降のキーワードでは値の型を前提にできるようにする
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] == "type" || (keys[j] != "type" && keys[i] < keys[j])
+	})
+
+	for _, k := range keys {
+		if slices.Contains(annotationKeywords, k) {
+			continue
+		}
+		if err := v.validateKeyword(at, k, s, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v schemaValidator) validateKeyword(at, keyword string, s map[string]json.RawMessage, value any) error {
+	raw := s[keyword]
+	switch keyword {
+	case "type":
+		var types []string
+		if err := json.Unmarshal(raw, &types); err != nil {
+			var t string
+			if err := json.Unmarshal(raw, &t); err != nil {
+				return fmt.Errorf("%s: invalid type: %w", at, err)
+			}
+			types = []string{t}
+		}
+		if !slices.ContainsFunc(types, func(t string) bool { return hasType(t, value) }) {
+			return fmt.Errorf("%s: expected %v, got %T", at, types, value)
+		}
+
+	case "properties":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		var props map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &props); err != nil {
+			return fmt.Errorf("%s: invalid properties: %w", at, err)
+		}
+		for name, schema := range props {
+			if pv, ok := obj[name]; ok {
+				if err := v.validateAt(at+"."+name, schema, pv); err != nil {
+					return err
+				}
+			}
+		}
+
+	case "required":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		var required []string
+		if err := json.Unmarshal(raw, &required); err != nil {
+			return fmt.Errorf("%s: invalid required: %w", at, err)
+		}
+		for _, name := range required {
+			if _, ok := obj[name]; !ok {
+				return fmt.Errorf("%s: missing required property %q", at, name)
+			}
+		}
+
+	case "additionalProperties":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		var allowed bool
+		if err := json.Unmarshal(raw, &allowed); err != nil {
+			return fmt.Errorf("%s: only boolean additionalProperties is supported", at)
+		}
+		if allowed {
+			return nil
+		}
+		var props map[string]json.RawMessage
+		if p, ok := s["properties"]; ok {
+			if err := json.Unmarshal(p, &props); err != nil {
+				return fmt.Errorf("%s: invalid properties: %w", at, err)
+			}
+		}
+		for name := range obj {
+			if _, ok := props[name]; !ok {
+				return fmt.Errorf("%s: unexpected property %q", at, name)
+			}
+		}
+
+	case "items":
+		arr, ok := value.([]any)
+		if !ok {
+			return nil
+		}
+		for i, item := range arr {
+			if err := v.validateAt(fmt.Sprintf("%s[%d]", at, i), raw, item); err != nil {
+				return err
+			}
+		}
+
+	case "minItems":
+		arr, ok := value.([]any)
+		if !ok {
+			return nil
+		}
+		var n int
+		if err := json.Unmarshal(raw, &n); err != nil {
+			return fmt.Errorf("%s: invalid minItems: %w", at, err)
+		}
+		if len(arr) < n {
+			return fmt.Errorf("%s: expected at least %d items, got %d", at, n, len(arr))
+		}
+
+	case "enum":
+		var values []any
+		if err := json.Unmarshal(raw, &values); err != nil {
+			return fmt.Errorf("%s: invalid enum: %w", at, err)
+		}
+		if !slices.Contains(values, value) {
+			return fmt.Errorf("%s: %v is not one of %v", at, value, values)
+		}
+
+	case "format":
+		str, ok := value.(string)
+		if !ok {
+			return nil
+		}
+		var format string
+		if err := json.Unmarshal(raw, &format); err != nil {
+			return fmt.Errorf("%s: invalid format: %w", at, err)
+		}
+		switch format {
+		case "uuid":
+			if _, err := uuid.Parse(str); err != nil {
+				return fmt.Errorf("%s: %q is not a uuid", at, str)
+			}
+		case "date-time":
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				return fmt.Errorf("%s: %q is not a date-time", at, str)
+			}
+		default:
+			return fmt.Errorf("%s: unsupported format %q", at, format)
+		}
+
+	case "pattern":
+		str, ok := value.(string)
+		if !ok {
+			return nil
+		}
+		var pattern string
+		if err := json.Unmarshal(raw, &pattern); err != nil {
+			return fmt.Errorf("%s: invalid pattern: %w", at, err)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("%s: invalid pattern: %w", at, err)
+		}
+		if !re.MatchString(str) {
+			return fmt.Errorf("%s: %q does not match %s", at, str, pattern)
+		}
+
+	case "minLength", "maxLength":
+		str, ok := value.(string)
+		if !ok {
+			return nil
+		}
+		var n int
+		if err := json.Unmarshal(raw, &n); err != nil {
+			return fmt.Errorf("%s: invalid %s: %w", at, keyword, err)
+		}
+		length := len([]rune(str))
+		if (keyword == "minLength" && length < n) || (keyword == "maxLength" && length > n) {
+			return fmt.Errorf("%s: length %d violates %s %d", at, length, keyword, n)
+		}
+
+	case "minimum", "maximum":
+		num, ok := value.(float64)
+		if !ok {
+			return nil
+		}
+		var n float64
+		if err := json.Unmarshal(raw, &n); err != nil {
+			return fmt.Errorf("%s: invalid %s: %w", at, keyword, err)
+		}
+		if (keyword == "minimum" && num < n) || (keyword == "maximum" && num > n) {
+			return fmt.Errorf("%s: %v violates %s %v", at, num, keyword, n)
+		}
+
+	default:
+		return fmt.Errorf("%s: unsupported keyword %q", at, keyword)
+	}
+	return nil
+}
+
+// hasType は encoding/json で any に読み込んだ値が JSON Schema の型に当てはまるかを返す
+func hasType(t string, value any) bool {
+	switch t {
+	case "null":
+		return value == nil
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == float64(int64(n))
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	}
+	return false
+}
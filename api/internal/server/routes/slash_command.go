@@ -0,0 +1,175 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/controller"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/queryprocessor"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/service"
+	roomcontroller "github.com/quietsato/toy-small-chat/api/internal/applications/room/controller"
+	roomusecase "github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase"
+	roomrepository "github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/di"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+)
+
+// newSlashCommandRegistry は組み込みの /topic コマンドからルームのスライスを呼び出せるようにする
+func newSlashCommandRegistry(dic *di.Container) *usecase.SlashCommandRegistry {
+	return usecase.NewSlashCommandRegistry(dic.Message.Commands, dic.Message.CommandCaller, &roomTopicUpdater{dic})
+}
+
+// roomTopicUpdater は PATCH /rooms/{roomID} と同じ処理でトピックを変更する
+type roomTopicUpdater struct {
+	dic *di.Container
+}
+
+// UpdateTopic implements service.RoomTopicUpdater.
+func (u *roomTopicUpdater) UpdateTopic(ctx context.Context, inp service.UpdateRoomTopicInput) error {
+	_, err := applyRoomUpdate(ctx, u.dic, roomcontroller.UpdateRoomInput{
+		RoomID:    inp.RoomID,
+		AccountID: inp.AccountID,
+		Topic:     &inp.Topic,
+	})
+	switch {
+	case err == nil, errors.Is(err, roomusecase.ErrNoRoomChanges):
+		return nil
+	case errors.Is(err, roomrepository.ErrNotRoomOwner):
+		return service.ErrRoomTopicForbidden
+	case errors.Is(err, domain.ErrInvalidRoomTopic):
+		return service.ErrInvalidRoomTopic
+	case errors.Is(err, roomrepository.ErrRoomNotFound):
+		return repository.ErrRoomNotFound
+	case errors.Is(err, roomrepository.ErrRoomArchived):
+		return repository.ErrRoomArchived
+	default:
+		return err
+	}
+}
+
+var _ service.RoomTopicUpdater = new(roomTopicUpdater)
+
+func getSlashCommands(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		roomID := getRoomIDFromContext(ctx)
+		if roomID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		c := controller.NewGetSlashCommandsController(dic.Message.CommandQuery)
+		commands, err := c.GetSlashCommands(ctx, controller.GetSlashCommandsInput{RoomID: *roomID})
+		if err != nil {
+			writeError(w, r, err, slashCommandProblems...)
+			return
+		}
+
+		res, err := json.Marshal(commands)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
+			return
+		}
+
+		if _, err := w.Write(res); err != nil {
+			slog.ErrorContext(ctx, "failed to write response", slog.Any("err", err))
+		}
+	})
+}
+
+func createSlashCommand(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		roomID := getRoomIDFromContext(ctx)
+		accountID := getAccountIDFromContext(ctx)
+		if roomID == nil || accountID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		defer r.Body.Close()
+		bytes, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "failed to read request body")
+			return
+		}
+
+		inp := controller.CreateSlashCommandInput{}
+		if err := json.Unmarshal(bytes, &inp); err != nil {
+			writeInvalidJSON(w, r)
+			return
+		}
+		inp.RoomID = *roomID
+		inp.AccountID = *accountID
+
+		c := controller.NewCreateSlashCommandController(dic.Message.Commands, dic.Webhook.NetworkPolicy)
+		command, err := c.CreateSlashCommand(ctx, inp)
+		if err != nil {
+			writeError(w, r, err, slashCommandProblems...)
+			return
+		}
+
+		res, err := json.Marshal(command)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		if _, err := w.Write(res); err != nil {
+			slog.ErrorContext(ctx, "failed to write response", slog.Any("err", err))
+		}
+	})
+}
+
+func deleteSlashCommand(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		roomID := getRoomIDFromContext(ctx)
+		accountID := getAccountIDFromContext(ctx)
+		if roomID == nil || accountID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		c := controller.NewDeleteSlashCommandController(dic.Message.Commands)
+		if err := c.DeleteSlashCommand(ctx, controller.DeleteSlashCommandInput{
+			RoomID:    *roomID,
+			CommandID: chi.URLParam(r, "commandID"),
+			AccountID: *accountID,
+		}); err != nil {
+			writeError(w, r, err, slashCommandProblems...)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+var slashCommandProblems = []problemSpec{
+	{Err: domain.ErrInvalidSlashCommandName, Code: codeInvalidSlashCommandName, Field: "name", Detail: "name is not a valid command name"},
+	{Err: domain.ErrInvalidSlashCommandDescription, Code: codeInvalidDescription, Field: "description", Detail: "description is too long"},
+	{Err: domain.ErrInvalidWebhookURL, Code: codeInvalidURL, Field: "url", Detail: "url must be an http or https URL"},
+	{Err: domain.ErrWebhookURLNotAllowed, Code: codeInvalidURL, Field: "url", Detail: "url must not point to a private network"},
+	{Err: domain.ErrInvalidWebhookSecret, Code: codeInvalidSecret, Field: "secret", Detail: "secret does not meet the requirements"},
+	{Err: domain.ErrInvalidUserName, Code: codeInvalidUserName, Field: "botName", Detail: "botName must be 1 to 32 alphanumeric characters"},
+	{Err: repository.ErrNotRoomOwner, Status: http.StatusForbidden, Code: codeNotRoomOwner, Detail: "only the room owner can manage slash commands"},
+	{Err: repository.ErrRoomNotFound, Status: http.StatusNotFound, Code: codeRoomNotFound, Detail: "room not found"},
+	{Err: queryprocessor.ErrRoomNotFound, Status: http.StatusNotFound, Code: codeRoomNotFound, Detail: "room not found"},
+	{Err: repository.ErrSlashCommandNotFound, Status: http.StatusNotFound, Code: codeSlashCommandNotFound, Detail: "slash command not found"},
+	{Err: usecase.ErrSlashCommandNameReserved, Status: http.StatusConflict, Code: codeNameTaken, Detail: "name is reserved for a built-in command"},
+	{Err: repository.ErrSlashCommandExists, Status: http.StatusConflict, Code: codeNameTaken, Detail: "a command with this name already exists"},
+	{Err: repository.ErrBotNameTaken, Status: http.StatusConflict, Code: codeNameTaken, Detail: "botName is already taken"},
+	{Err: repository.ErrTooManySlashCommands, Status: http.StatusConflict, Code: codeLimitExceeded, Detail: "the room has too many slash commands"},
+}
@@ -0,0 +1,517 @@
+package routes_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	accountquery "github.com/quietsato/toy-small-chat/api/internal/applications/account/usecase/queryprocessor"
+	accountrepo "github.com/quietsato/toy-small-chat/api/internal/applications/account/usecase/repository"
+	attachmentquery "github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase/queryprocessor"
+	attachmentrepo "github.com/quietsato/toy-small-chat/api/internal/applications/attachment/usecase/repository"
+	incomingwebhookquery "github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/usecase/queryprocessor"
+	incomingwebhookrepo "github.com/quietsato/toy-small-chat/api/internal/applications/incomingwebhook/usecase/repository"
+	mentionquery "github.com/quietsato/toy-small-chat/api/internal/applications/mention/usecase/queryprocessor"
+	mentionrepo "github.com/quietsato/toy-small-chat/api/internal/applications/mention/usecase/repository"
+	messagequery "github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/queryprocessor"
+	messagerepo "github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/repository"
+	messageservice "github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/service"
+	pinquery "github.com/quietsato/toy-small-chat/api/internal/applications/pin/usecase/queryprocessor"
+	pinrepo "github.com/quietsato/toy-small-chat/api/internal/applications/pin/usecase/repository"
+	retentionquery "github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase/queryprocessor"
+	retentionrepo "github.com/quietsato/toy-small-chat/api/internal/applications/retention/usecase/repository"
+	roomquery "github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/queryprocessor"
+	roomrepo "github.com/quietsato/toy-small-chat/api/internal/applications/room/usecase/repository"
+	roomtransferquery "github.com/quietsato/toy-small-chat/api/internal/applications/roomtransfer/usecase/queryprocessor"
+	roomtransferrepo "github.com/quietsato/toy-small-chat/api/internal/applications/roomtransfer/usecase/repository"
+	scheduledmessagequery "github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase/queryprocessor"
+	scheduledmessagerepo "github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase/repository"
+	webhookquery "github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/queryprocessor"
+	webhookrepo "github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+)
+
+// Stub implementations
+//
+// 各スタブはデータベースの代わりに、省略可能な項目もすべて埋めた固定の値を返す。
+// レスポンスに現れうるフィールドをできるだけ多く出力させ、OpenAPI ドキュメントとの食い違いを検出するため
+
+const (
+	stubAccountID = "0b6c8a36-4a0c-4f55-9a53-2f0f3e1c5a01"
+	stubRoomID    = "6f1d2c3b-8e4a-4b5c-9d6e-7f8a9b0c1d02"
+	// stubDeletedRoomID は削除され、復元を待っているルーム
+	stubDeletedRoomID = "5e0c1b2a-7d3f-4a4b-8c5d-6e7f8a9b0c10"
+	stubMessageID     = "a3c1e5f7-2b4d-4e6f-8a0b-1c2d3e4f5a03"
+	stubAttachID      = "c5e7a9b1-4d6f-4a8b-9c0d-2e3f4a5b6c04"
+	// stubDeletedAttachID は stubDeletedRoomID のルームの添付ファイル
+	stubDeletedAttachID = "6f1d2c3b-5e7a-4b9c-8d0e-3f4a5b6c7d11"
+	stubWebhookID       = "e7a9c1d3-6f8b-4c0d-8e1f-3a4b5c6d7e05"
+	stubCommandID       = "19b3d5f7-8a0c-4e2f-9a3b-4c5d6e7f8a06"
+	stubScheduledID     = "2ac4e6a8-9b1d-4f3a-8b4c-5d6e7f8a9b07"
+	stubMentionID       = "3bd5f7b9-0c2e-4a4b-9c5d-6e7f8a9b0c08"
+	stubBotID           = "4ce6a8ca-1d3f-4b5c-8d6e-7f8a9b0c1d09"
+	stubCreatedAt       = "2024-06-01T09:00:00Z"
+	stubUpdatedAt       = "2024-06-02T10:30:00Z"
+	stubUserName        = "alice"
+	stubPassword        = "password1"
+)
+
+func stubPNG() []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
+
+type stubAccountRepository struct{}
+
+func (stubAccountRepository) CreateAccount(ctx context.Context, inp accountrepo.CreateAccountInput) (accountrepo.CreateAccountOutput, error) {
+	return accountrepo.CreateAccountOutput{AccountID: stubAccountID}, nil
+}
+
+type stubAccountQueryProcessor struct {
+	passwordHash []byte
+}
+
+func newStubAccountQueryProcessor() stubAccountQueryProcessor {
+	raw, err := domain.NewRawPassword([]byte(stubPassword))
+	if err != nil {
+		panic(err)
+	}
+	hash, err := domain.NewHashedPassword(raw)
+	if err != nil {
+		panic(err)
+	}
+	return stubAccountQueryProcessor{passwordHash: hash.Bytes()}
+}
+
+func (q stubAccountQueryProcessor) GetLoginCredential(ctx context.Context, inp accountquery.GetLoginCredentialInput) (accountquery.GetLoginCredentialOutput, error) {
+	return accountquery.GetLoginCredentialOutput{
+		AccountID:    uuid.MustParse(stubAccountID),
+		PasswordHash: q.passwordHash,
+	}, nil
+}
+
+type stubMessageRepository struct{}
+
+func (stubMessageRepository) CreateMessage(ctx context.Context, inp messagerepo.CreateMessageInput) (messagerepo.CreateMessageOutput, error) {
+	return messagerepo.CreateMessageOutput{ID: stubMessageID}, nil
+}
+
+func (stubMessageRepository) Get() error {
+	return nil
+}
+
+type stubMessageQueryProcessor struct{}
+
+func (stubMessageQueryProcessor) GetMessages(roomID string) ([]messagequery.Message, error) {
+	if roomID == stubDeletedRoomID {
+		return nil, messagequery.ErrRoomNotFound
+	}
+	return []messagequery.Message{
+		{
+			ID:          stubMessageID,
+			Kind:        "user",
+			Author:      stubUserName,
+			Content:     "@bob **hello**",
+			Format:      "markdown",
+			CreatedAt:   stubCreatedAt,
+			Pinned:      true,
+			Mentions:    []messagequery.MentionSpan{{Kind: "user", UserName: "bob", Start: 0, End: 4}},
+			Attachments: []messagequery.Attachment{{ID: stubAttachID, FileName: "dot.png", ContentType: "image/png", Size: 68, HasThumbnail: true}},
+		},
+		{
+			ID:          stubScheduledID,
+			Kind:        "system",
+			Author:      "deploybot",
+			AuthorIsBot: true,
+			Content:     "renamed the room",
+			Format:      "plain",
+			CreatedAt:   stubUpdatedAt,
+			Mentions:    []messagequery.MentionSpan{},
+			Attachments: []messagequery.Attachment{},
+			Event:       &messagequery.SystemEvent{Type: "room_renamed", MessageID: stubMessageID, RoomName: "general", PreviousRoomName: "random", Topic: "news"},
+		},
+	}, nil
+}
+
+type stubSlashCommandRepository struct{}
+
+func (stubSlashCommandRepository) CreateSlashCommand(ctx context.Context, inp messagerepo.CreateSlashCommandInput) (messagerepo.CreateSlashCommandOutput, error) {
+	return messagerepo.CreateSlashCommandOutput{ID: stubCommandID}, nil
+}
+
+func (stubSlashCommandRepository) DeleteSlashCommand(ctx context.Context, inp messagerepo.DeleteSlashCommandInput) error {
+	return nil
+}
+
+func (stubSlashCommandRepository) FindSlashCommand(ctx context.Context, inp messagerepo.FindSlashCommandInput) (messagerepo.FindSlashCommandOutput, error) {
+	return messagerepo.FindSlashCommandOutput{
+		ID:           stubCommandID,
+		CallbackURL:  "https://example.com/commands",
+		Secret:       "command-secret",
+		BotAccountID: stubBotID,
+	}, nil
+}
+
+func (stubSlashCommandRepository) GetAccountName(ctx context.Context, accountID string) (string, error) {
+	return stubUserName, nil
+}
+
+type stubSlashCommandQueryProcessor struct{}
+
+func (stubSlashCommandQueryProcessor) GetSlashCommands(ctx context.Context, roomID string) ([]messagequery.SlashCommandDTO, error) {
+	return []messagequery.SlashCommandDTO{
+		{ID: stubCommandID, Name: "deploy", Description: "deploy the app", BotName: "deploybot", CreatedBy: stubAccountID, CreatedAt: stubCreatedAt},
+	}, nil
+}
+
+type stubSlashCommandCaller struct{}
+
+func (stubSlashCommandCaller) Call(ctx context.Context, inp messageservice.CallSlashCommandInput) (messageservice.CallSlashCommandOutput, error) {
+	return messageservice.CallSlashCommandOutput{Text: "deploying", Format: "plain", ResponseType: "ephemeral"}, nil
+}
+
+type stubMentionRepository struct{}
+
+func (stubMentionRepository) MarkMentionsAsRead(ctx context.Context, inp mentionrepo.MarkMentionsAsReadInput) (mentionrepo.MarkMentionsAsReadOutput, error) {
+	return mentionrepo.MarkMentionsAsReadOutput{Updated: 1}, nil
+}
+
+type stubMentionQueryProcessor struct{}
+
+func (stubMentionQueryProcessor) GetMentions(ctx context.Context, inp mentionquery.GetMentionsInput) (mentionquery.GetMentionsOutput, error) {
+	mentions := make([]mentionquery.MentionDTO, 0, inp.Limit)
+	// コントローラーは 1 件多く要求するため、要求どおりに返すと次のページがある状態になる
+	for range inp.Limit {
+		mentions = append(mentions, mentionquery.MentionDTO{
+			ID:        stubMentionID,
+			MessageID: stubMessageID,
+			RoomID:    stubRoomID,
+			RoomName:  "general",
+			Author:    stubUserName,
+			Content:   "@bob hello",
+			CreatedAt: stubCreatedAt,
+		})
+	}
+	return mentionquery.GetMentionsOutput{Mentions: mentions}, nil
+}
+
+type stubPinRepository struct{}
+
+func (stubPinRepository) PinMessage(ctx context.Context, inp pinrepo.PinMessageInput) (pinrepo.PinMessageOutput, error) {
+	return pinrepo.PinMessageOutput{Pinned: true}, nil
+}
+
+func (stubPinRepository) UnpinMessage(ctx context.Context, inp pinrepo.UnpinMessageInput) error {
+	return nil
+}
+
+type stubPinQueryProcessor struct{}
+
+func (stubPinQueryProcessor) GetPins(ctx context.Context, inp pinquery.GetPinsInput) (pinquery.GetPinsOutput, error) {
+	if inp.RoomID.String() == stubDeletedRoomID {
+		return pinquery.GetPinsOutput{}, pinquery.ErrRoomNotFound
+	}
+	return pinquery.GetPinsOutput{Pins: []pinquery.PinDTO{
+		{MessageID: stubMessageID, Author: stubUserName, Content: "hello", Format: "plain", CreatedAt: stubCreatedAt, PinnedBy: stubUserName, PinnedAt: stubUpdatedAt},
+	}}, nil
+}
+
+type stubRoomRepository struct{}
+
+func (stubRoomRepository) CreateRoom(ctx context.Context, inp roomrepo.CreateRoomInput) (roomrepo.CreateRoomOutput, error) {
+	return roomrepo.CreateRoomOutput{ID: stubRoomID}, nil
+}
+
+func (stubRoomRepository) UpdateRoom(ctx context.Context, inp roomrepo.UpdateRoomInput) (roomrepo.UpdateRoomOutput, error) {
+	return roomrepo.UpdateRoomOutput{PreviousName: "random", Name: "general", PreviousTopic: "", Topic: "news"}, nil
+}
+
+func (stubRoomRepository) SetRoomArchived(ctx context.Context, inp roomrepo.SetRoomArchivedInput) (roomrepo.SetRoomArchivedOutput, error) {
+	return roomrepo.SetRoomArchivedOutput{Changed: true}, nil
+}
+
+func (stubRoomRepository) DeleteRoom(ctx context.Context, inp roomrepo.DeleteRoomInput) error {
+	return nil
+}
+
+func (stubRoomRepository) RestoreRoom(ctx context.Context, inp roomrepo.RestoreRoomInput) error {
+	return nil
+}
+
+func (stubRoomRepository) UpdateNotificationSettings(ctx context.Context, inp roomrepo.UpdateNotificationSettingsInput) error {
+	return nil
+}
+
+type stubRoomQueryProcessor struct{}
+
+func (stubRoomQueryProcessor) GetRooms(ctx context.Context, inp roomquery.GetRoomsInput) (roomquery.GetRoomsOutput, error) {
+	rooms := make([]roomquery.RoomDTO, 0, inp.Limit)
+	// コントローラーは 1 件多く要求するため、要求どおりに返すと次のページがある状態になる
+	for range inp.Limit {
+		rooms = append(rooms, roomquery.RoomDTO{
+			ID:             stubRoomID,
+			Name:           "general",
+			Topic:          "news",
+			CreatedBy:      stubAccountID,
+			CreatedAt:      stubCreatedAt,
+			UpdatedAt:      stubUpdatedAt,
+			ArchivedAt:     stubUpdatedAt,
+			LastActivityAt: stubUpdatedAt,
+			MemberCount:    2,
+			LastMessage:    &roomquery.LastMessageDTO{Author: stubUserName, Snippet: "hello"},
+			Notification:   roomquery.NotificationSettingsDTO{Level: "mentions", MutedUntil: "2999-01-01T00:00:00Z"},
+			Cursor:         roomquery.RoomCursor{Time: time.Date(2024, 6, 2, 10, 30, 0, 0, time.UTC), ID: uuid.MustParse(stubRoomID)},
+		})
+	}
+	return roomquery.GetRoomsOutput{Rooms: rooms}, nil
+}
+
+func (stubRoomQueryProcessor) GetNotificationSettings(ctx context.Context, inp roomquery.GetNotificationSettingsInput) (roomquery.GetNotificationSettingsOutput, error) {
+	return roomquery.GetNotificationSettingsOutput{
+		Settings: roomquery.NotificationSettingsDTO{Level: "all", MutedUntil: "2999-01-01T00:00:00Z"},
+	}, nil
+}
+
+type stubAttachmentRepository struct{}
+
+func (stubAttachmentRepository) CreateAttachment(ctx context.Context, inp attachmentrepo.CreateAttachmentInput) (attachmentrepo.CreateAttachmentOutput, error) {
+	return attachmentrepo.CreateAttachmentOutput{}, nil
+}
+
+type stubAttachmentQueryProcessor struct{}
+
+func (stubAttachmentQueryProcessor) GetAttachment(ctx context.Context, inp attachmentquery.GetAttachmentInput) (attachmentquery.GetAttachmentOutput, error) {
+	if inp.AttachmentID == stubDeletedAttachID {
+		return attachmentquery.GetAttachmentOutput{Attachment: attachmentquery.AttachmentDTO{
+			ID:          stubDeletedAttachID,
+			RoomID:      stubDeletedRoomID,
+			FileName:    "dot.png",
+			ContentType: "image/png",
+			Size:        int64(len(stubPNG())),
+			StorageKey:  "attachments/dot.png",
+			RoomDeleted: true,
+		}}, nil
+	}
+	return attachmentquery.GetAttachmentOutput{Attachment: attachmentquery.AttachmentDTO{
+		ID:           stubAttachID,
+		RoomID:       stubRoomID,
+		FileName:     "dot.png",
+		ContentType:  "image/png",
+		Size:         int64(len(stubPNG())),
+		StorageKey:   "attachments/dot.png",
+		ThumbnailKey: "attachments/dot.thumbnail.png",
+	}}, nil
+}
+
+// stubBlobStorage はどのキーに対しても同じ PNG 画像を返す
+type stubBlobStorage struct{}
+
+func (stubBlobStorage) Put(ctx context.Context, key string, r io.Reader) error {
+	_, err := io.Copy(io.Discard, r)
+	return err
+}
+
+func (stubBlobStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(stubPNG())), nil
+}
+
+func (stubBlobStorage) Delete(ctx context.Context, key string) error {
+	return nil
+}
+
+type stubWebhookRepository struct{}
+
+func (stubWebhookRepository) CreateWebhook(ctx context.Context, inp webhookrepo.CreateWebhookInput) (webhookrepo.CreateWebhookOutput, error) {
+	return webhookrepo.CreateWebhookOutput{ID: uuid.MustParse(stubWebhookID)}, nil
+}
+
+func (stubWebhookRepository) DeleteWebhook(ctx context.Context, inp webhookrepo.DeleteWebhookInput) error {
+	return nil
+}
+
+func (stubWebhookRepository) EnqueueDeliveries(ctx context.Context, inp webhookrepo.EnqueueDeliveriesInput) (webhookrepo.EnqueueDeliveriesOutput, error) {
+	return webhookrepo.EnqueueDeliveriesOutput{Count: 1}, nil
+}
+
+func (stubWebhookRepository) ClaimDeliveries(ctx context.Context, inp webhookrepo.ClaimDeliveriesInput) (webhookrepo.ClaimDeliveriesOutput, error) {
+	return webhookrepo.ClaimDeliveriesOutput{}, nil
+}
+
+func (stubWebhookRepository) CompleteDelivery(ctx context.Context, inp webhookrepo.CompleteDeliveryInput) error {
+	return nil
+}
+
+func (stubWebhookRepository) FailDelivery(ctx context.Context, inp webhookrepo.FailDeliveryInput) error {
+	return nil
+}
+
+type stubWebhookQueryProcessor struct{}
+
+func (stubWebhookQueryProcessor) GetWebhooks(ctx context.Context, inp webhookquery.GetWebhooksInput) (webhookquery.GetWebhooksOutput, error) {
+	return webhookquery.GetWebhooksOutput{Webhooks: []webhookquery.WebhookDTO{
+		{ID: stubWebhookID, URL: "https://example.com/hook", EventTypes: []string{"message.created", "room.updated"}, CreatedBy: stubAccountID, CreatedAt: stubCreatedAt},
+	}}, nil
+}
+
+func (stubWebhookQueryProcessor) GetDeliveries(ctx context.Context, inp webhookquery.GetDeliveriesInput) (webhookquery.GetDeliveriesOutput, error) {
+	return webhookquery.GetDeliveriesOutput{Deliveries: []webhookquery.DeliveryDTO{
+		{ID: stubMessageID, EventType: "message.created", Status: "pending", Attempts: 2, LastStatusCode: 503, LastError: "service unavailable", CreatedAt: stubCreatedAt, NextAttemptAt: stubUpdatedAt},
+		{ID: stubMentionID, EventType: "room.archived", Status: "succeeded", Attempts: 1, LastStatusCode: 200, CreatedAt: stubCreatedAt, DeliveredAt: stubUpdatedAt},
+	}}, nil
+}
+
+// stubIncomingWebhookRepository は token に一致するトークンだけを受け付ける
+type stubIncomingWebhookRepository struct {
+	token domain.IncomingWebhookToken
+}
+
+func (stubIncomingWebhookRepository) CreateIncomingWebhook(ctx context.Context, inp incomingwebhookrepo.CreateIncomingWebhookInput) (incomingwebhookrepo.CreateIncomingWebhookOutput, error) {
+	return incomingwebhookrepo.CreateIncomingWebhookOutput{ID: uuid.MustParse(stubWebhookID)}, nil
+}
+
+func (stubIncomingWebhookRepository) RotateToken(ctx context.Context, inp incomingwebhookrepo.RotateTokenInput) error {
+	return nil
+}
+
+func (stubIncomingWebhookRepository) RevokeIncomingWebhook(ctx context.Context, inp incomingwebhookrepo.RevokeIncomingWebhookInput) error {
+	return nil
+}
+
+func (m stubIncomingWebhookRepository) GetCredential(ctx context.Context, inp incomingwebhookrepo.GetCredentialInput) (incomingwebhookrepo.GetCredentialOutput, error) {
+	return incomingwebhookrepo.GetCredentialOutput{
+		RoomID:       uuid.MustParse(stubRoomID),
+		BotAccountID: uuid.MustParse(stubBotID),
+		TokenHash:    m.token.Hash(),
+	}, nil
+}
+
+func (stubIncomingWebhookRepository) ConsumeRateLimit(ctx context.Context, inp incomingwebhookrepo.ConsumeRateLimitInput) error {
+	return nil
+}
+
+// revocableIncomingWebhookRepository は失効の状態を持ち、リポジトリの契約どおり失効済みの受信 Webhook を見つからないものとして扱う
+type revocableIncomingWebhookRepository struct {
+	stubIncomingWebhookRepository
+
+	mu      sync.Mutex
+	revoked bool
+}
+
+func (m *revocableIncomingWebhookRepository) RotateToken(ctx context.Context, inp incomingwebhookrepo.RotateTokenInput) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.revoked {
+		return incomingwebhookrepo.ErrIncomingWebhookNotFound
+	}
+	return nil
+}
+
+func (m *revocableIncomingWebhookRepository) RevokeIncomingWebhook(ctx context.Context, inp incomingwebhookrepo.RevokeIncomingWebhookInput) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.revoked = true
+	return nil
+}
+
+func (m *revocableIncomingWebhookRepository) GetCredential(ctx context.Context, inp incomingwebhookrepo.GetCredentialInput) (incomingwebhookrepo.GetCredentialOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.revoked {
+		return incomingwebhookrepo.GetCredentialOutput{}, incomingwebhookrepo.ErrIncomingWebhookNotFound
+	}
+	return m.stubIncomingWebhookRepository.GetCredential(ctx, inp)
+}
+
+type stubIncomingWebhookQueryProcessor struct{}
+
+func (stubIncomingWebhookQueryProcessor) GetIncomingWebhooks(ctx context.Context, inp incomingwebhookquery.GetIncomingWebhooksInput) (incomingwebhookquery.GetIncomingWebhooksOutput, error) {
+	return incomingwebhookquery.GetIncomingWebhooksOutput{Webhooks: []incomingwebhookquery.IncomingWebhookDTO{
+		{ID: stubWebhookID, Name: "ci", RateLimit: 60, CreatedBy: stubAccountID, CreatedAt: stubCreatedAt, TokenRotatedAt: stubUpdatedAt, RevokedAt: stubUpdatedAt},
+	}}, nil
+}
+
+type stubScheduledMessageRepository struct{}
+
+func (stubScheduledMessageRepository) CreateScheduledMessage(ctx context.Context, inp scheduledmessagerepo.CreateScheduledMessageInput) (scheduledmessagerepo.CreateScheduledMessageOutput, error) {
+	return scheduledmessagerepo.CreateScheduledMessageOutput{ID: uuid.MustParse(stubScheduledID)}, nil
+}
+
+func (stubScheduledMessageRepository) CancelScheduledMessage(ctx context.Context, inp scheduledmessagerepo.CancelScheduledMessageInput) error {
+	return nil
+}
+
+func (stubScheduledMessageRepository) ClaimScheduledMessages(ctx context.Context, inp scheduledmessagerepo.ClaimScheduledMessagesInput) (scheduledmessagerepo.ClaimScheduledMessagesOutput, error) {
+	return scheduledmessagerepo.ClaimScheduledMessagesOutput{}, nil
+}
+
+func (stubScheduledMessageRepository) FailScheduledMessage(ctx context.Context, inp scheduledmessagerepo.FailScheduledMessageInput) error {
+	return nil
+}
+
+type stubScheduledMessageQueryProcessor struct{}
+
+func (stubScheduledMessageQueryProcessor) GetScheduledMessages(ctx context.Context, inp scheduledmessagequery.GetScheduledMessagesInput) (scheduledmessagequery.GetScheduledMessagesOutput, error) {
+	return scheduledmessagequery.GetScheduledMessagesOutput{Messages: []scheduledmessagequery.ScheduledMessageDTO{
+		{ID: stubScheduledID, Content: "good morning", Format: "plain", SendAt: stubUpdatedAt, Status: "sent", MessageID: stubMessageID, CreatedAt: stubCreatedAt, SentAt: stubUpdatedAt},
+		{ID: stubMentionID, Content: "**reminder**", Format: "markdown", SendAt: stubUpdatedAt, Status: "failed", Error: "room archived", CreatedAt: stubCreatedAt},
+	}}, nil
+}
+
+type stubRetentionRepository struct{}
+
+func (stubRetentionRepository) SetRoomRetention(ctx context.Context, inp retentionrepo.SetRoomRetentionInput) error {
+	return nil
+}
+
+func (stubRetentionRepository) PurgeExpiredMessages(ctx context.Context, inp retentionrepo.PurgeExpiredMessagesInput) (retentionrepo.PurgeExpiredMessagesOutput, error) {
+	return retentionrepo.PurgeExpiredMessagesOutput{}, nil
+}
+
+func (stubRetentionRepository) PurgeDeletedRooms(ctx context.Context, inp retentionrepo.PurgeDeletedRoomsInput) (retentionrepo.PurgeDeletedRoomsOutput, error) {
+	return retentionrepo.PurgeDeletedRoomsOutput{}, nil
+}
+
+type stubRetentionQueryProcessor struct{}
+
+func (stubRetentionQueryProcessor) GetRoomRetention(ctx context.Context, roomID uuid.UUID) (retentionquery.GetRoomRetentionOutput, error) {
+	days := 30
+	return retentionquery.GetRoomRetentionOutput{Days: &days}, nil
+}
+
+type stubRoomImportRepository struct{}
+
+func (stubRoomImportRepository) ResolveAccounts(ctx context.Context, inp roomtransferrepo.ResolveAccountsInput) (roomtransferrepo.ResolveAccountsOutput, error) {
+	ids := make(map[uuid.UUID]uuid.UUID, len(inp.Accounts))
+	for _, a := range inp.Accounts {
+		ids[a.ID] = a.ID
+	}
+	return roomtransferrepo.ResolveAccountsOutput{IDs: ids, Placeholders: 1}, nil
+}
+
+func (stubRoomImportRepository) ImportRoom(ctx context.Context, inp roomtransferrepo.ImportRoomInput) (roomtransferrepo.ImportRoomOutput, error) {
+	return roomtransferrepo.ImportRoomOutput{ID: uuid.MustParse(stubRoomID)}, nil
+}
+
+type stubRoomExportQueryProcessor struct{}
+
+func (stubRoomExportQueryProcessor) ExportRooms(ctx context.Context, inp roomtransferquery.ExportRoomsInput, w roomtransferquery.RoomExportWriter) error {
+	createdAt := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	if err := w.WriteRoom(roomtransferquery.RoomDTO{ID: uuid.MustParse(stubRoomID), Name: "general", OwnerID: uuid.MustParse(stubAccountID), CreatedAt: createdAt}); err != nil {
+		return err
+	}
+	if err := w.WriteMember(roomtransferquery.MemberDTO{RoomID: uuid.MustParse(stubRoomID), ID: uuid.MustParse(stubAccountID), UserName: stubUserName, Kind: "user"}); err != nil {
+		return err
+	}
+	return w.WriteMessage(roomtransferquery.MessageDTO{RoomID: uuid.MustParse(stubRoomID), ID: uuid.MustParse(stubMessageID), AuthorID: uuid.MustParse(stubAccountID), Kind: "user", Content: "hello", Format: "plain", CreatedAt: createdAt, UpdatedAt: createdAt})
+}
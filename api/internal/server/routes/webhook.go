@@ -0,0 +1,196 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/controller"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/queryprocessor"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/di"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+)
+
+func getWebhooks(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		roomID := getRoomIDFromContext(ctx)
+		accountID := getAccountIDFromContext(ctx)
+		if roomID == nil || accountID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		c := controller.NewGetWebhooksController(dic.Webhook.Query)
+		webhooks, err := c.GetWebhooks(ctx, controller.GetWebhooksInput{
+			RoomID:    *roomID,
+			AccountID: *accountID,
+		})
+		if err != nil {
+			writeError(w, r, err, webhookProblems...)
+			return
+		}
+
+		res, err := json.Marshal(webhooks)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
+			return
+		}
+
+		if _, err := w.Write(res); err != nil {
+			slog.ErrorContext(ctx, "failed to write response", slog.Any("err", err))
+		}
+	})
+}
+
+func createWebhook(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		roomID := getRoomIDFromContext(ctx)
+		accountID := getAccountIDFromContext(ctx)
+		if roomID == nil || accountID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		defer r.Body.Close()
+		bytes, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "failed to read request body")
+			return
+		}
+
+		inp := controller.CreateWebhookInput{}
+		if err := json.Unmarshal(bytes, &inp); err != nil {
+			writeInvalidJSON(w, r)
+			return
+		}
+		inp.RoomID = *roomID
+		inp.AccountID = *accountID
+
+		c := controller.NewCreateWebhookController(dic.Webhook.Repo, dic.Webhook.NetworkPolicy)
+		webhook, err := c.CreateWebhook(ctx, inp)
+		if err != nil {
+			writeError(w, r, err, webhookProblems...)
+			return
+		}
+
+		res, err := json.Marshal(webhook)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		if _, err := w.Write(res); err != nil {
+			slog.ErrorContext(ctx, "failed to write response", slog.Any("err", err))
+		}
+	})
+}
+
+func deleteWebhook(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		roomID := getRoomIDFromContext(ctx)
+		accountID := getAccountIDFromContext(ctx)
+		if roomID == nil || accountID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		c := controller.NewDeleteWebhookController(dic.Webhook.Repo)
+		if err := c.DeleteWebhook(ctx, controller.DeleteWebhookInput{
+			RoomID:    *roomID,
+			WebhookID: chi.URLParam(r, "webhookID"),
+			AccountID: *accountID,
+		}); err != nil {
+			writeError(w, r, err, webhookProblems...)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func getWebhookDeliveries(dic *di.Container) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		roomID := getRoomIDFromContext(ctx)
+		accountID := getAccountIDFromContext(ctx)
+		if roomID == nil || accountID == nil {
+			writeProblem(w, r, http.StatusBadRequest, codeBadRequest, "")
+			return
+		}
+
+		inp := controller.GetWebhookDeliveriesInput{
+			RoomID:    *roomID,
+			WebhookID: chi.URLParam(r, "webhookID"),
+			AccountID: *accountID,
+		}
+		if v := r.URL.Query().Get("limit"); v != "" {
+			var err error
+			if inp.Limit, err = strconv.Atoi(v); err != nil {
+				writeValidationProblem(w, r, fieldError{Field: "limit", Code: codeInvalidLimit, Detail: "limit must be an integer"})
+				return
+			}
+		}
+
+		c := controller.NewGetWebhookDeliveriesController(dic.Webhook.Query)
+		deliveries, err := c.GetWebhookDeliveries(ctx, inp)
+		if err != nil {
+			writeError(w, r, err, webhookProblems...)
+			return
+		}
+
+		res, err := json.Marshal(deliveries)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("failed to marshal response: %w", err))
+			return
+		}
+
+		if _, err := w.Write(res); err != nil {
+			slog.ErrorContext(ctx, "failed to write response", slog.Any("err", err))
+		}
+	})
+}
+
+// enqueueWebhookEvent はルームのイベントを送信 Webhook の配信キューに登録する
+//
+// 操作自体は完了しているため、登録に失敗してもリクエストは失敗させない
+func enqueueWebhookEvent(ctx context.Context, dic *di.Container, roomID string, eventType domain.WebhookEventType, data any) {
+	c := controller.NewEnqueueWebhookEventController(dic.Webhook.Repo)
+	if err := c.EnqueueWebhookEvent(ctx, controller.EnqueueWebhookEventInput{
+		RoomID:    roomID,
+		EventType: eventType,
+		Data:      data,
+	}); err != nil {
+		slog.WarnContext(ctx, "failed to enqueue webhook event", slog.String("type", eventType.String()), slog.Any("err", err))
+	}
+}
+
+var webhookProblems = []problemSpec{
+	{Err: domain.ErrInvalidWebhookURL, Code: codeInvalidURL, Field: "url", Detail: "url must be an http or https URL"},
+	{Err: domain.ErrWebhookURLNotAllowed, Code: codeInvalidURL, Field: "url", Detail: "url must not point to a private network"},
+	{Err: domain.ErrInvalidWebhookSecret, Code: codeInvalidSecret, Field: "secret", Detail: "secret does not meet the requirements"},
+	{Err: domain.ErrInvalidWebhookEventType, Code: codeInvalidEventType, Field: "events", Detail: "events contains an unknown event type"},
+	{Err: usecase.ErrNoWebhookEvents, Code: codeRequired, Field: "events", Detail: "at least one event is required"},
+	{Err: controller.ErrInvalidDeliveriesLimit, Code: codeInvalidLimit, Field: "limit", Detail: "limit is out of range"},
+	{Err: repository.ErrNotRoomOwner, Status: http.StatusForbidden, Code: codeNotRoomOwner, Detail: "only the room owner can manage webhooks"},
+	{Err: queryprocessor.ErrNotRoomOwner, Status: http.StatusForbidden, Code: codeNotRoomOwner, Detail: "only the room owner can manage webhooks"},
+	{Err: repository.ErrRoomNotFound, Status: http.StatusNotFound, Code: codeRoomNotFound, Detail: "room not found"},
+	{Err: queryprocessor.ErrRoomNotFound, Status: http.StatusNotFound, Code: codeRoomNotFound, Detail: "room not found"},
+	{Err: repository.ErrWebhookNotFound, Status: http.StatusNotFound, Code: codeWebhookNotFound, Detail: "webhook not found"},
+	{Err: queryprocessor.ErrWebhookNotFound, Status: http.StatusNotFound, Code: codeWebhookNotFound, Detail: "webhook not found"},
+	{Err: repository.ErrTooManyWebhooks, Status: http.StatusConflict, Code: codeLimitExceeded, Detail: "the room has too many webhooks"},
+}
@@ -2,38 +2,53 @@ package server
 
 import (
 	"log/slog"
-	"net/http"
-	"time"
+	"net/netip"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
+	"github.com/quietsato/toy-small-chat/api/internal/config"
 	"github.com/quietsato/toy-small-chat/api/internal/di"
+	instrumenthttp "github.com/quietsato/toy-small-chat/api/internal/instrument/http"
+	"github.com/quietsato/toy-small-chat/api/internal/server/middlewares/realip"
+	"github.com/quietsato/toy-small-chat/api/internal/server/middlewares/securityheaders"
 	"github.com/quietsato/toy-small-chat/api/internal/server/routes"
 
 	slogchi "github.com/samber/slog-chi"
 )
 
-func New(dic *di.Container) *chi.Mux {
+func New(dic *di.Container, cfg config.Server, corsCfg config.CORS, headersCfg config.SecurityHeaders) *chi.Mux {
 	r := chi.NewRouter()
 
 	// CORS
 	r.Use(cors.Handler(cors.Options{
-		AllowOriginFunc:    func(r *http.Request, origin string) bool { return true }, // Not for production, allow all origins
-		AllowedMethods:     []string{"GET", "POST", "OPTIONS"},
-		AllowedHeaders:     []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:     []string{},
-		AllowCredentials:   false,
-		MaxAge:             86400, // 24h
+		AllowedOrigins:     corsCfg.AllowedOrigins,
+		AllowedMethods:     corsCfg.AllowedMethods,
+		AllowedHeaders:     corsCfg.AllowedHeaders,
+		ExposedHeaders:     []string{"Deprecation", "Sunset", "Link", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After"},
+		AllowCredentials:   corsCfg.AllowCredentials,
+		MaxAge:             int(corsCfg.MaxAge.Seconds()),
 		OptionsPassthrough: false,
 		Debug:              false,
 	}))
 
+	// プリセットの名前は config で検証している
+	headers, _ := securityheaders.Preset(headersCfg.Preset, headersCfg.FrameAncestors)
+	r.Use(securityheaders.Handler(headers))
+
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
-	r.Use(slogchi.New(slog.Default()))
+	// 信頼するプロキシの CIDR は config で検証している
+	proxies := make([]netip.Prefix, 0, len(cfg.TrustedProxies))
+	for _, proxy := range cfg.TrustedProxies {
+		prefix, _ := netip.ParsePrefix(proxy)
+		proxies = append(proxies, prefix)
+	}
+	r.Use(realip.Handler(proxies))
+	// 死活監視は数秒ごとに呼ばれるため、アクセスログに残さない
+	r.Use(slogchi.NewWithFilters(slog.Default(), slogchi.IgnorePath("/healthz", "/readyz")))
+	r.Use(instrumenthttp.RouteTagger)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(60 * time.Second))
+	r.Use(middleware.Timeout(cfg.RequestTimeout))
 
 	routes.Setup(r, dic)
 
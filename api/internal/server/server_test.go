@@ -0,0 +1,85 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/account/infrastructure/serviceimpl"
+	"github.com/quietsato/toy-small-chat/api/internal/config"
+	"github.com/quietsato/toy-small-chat/api/internal/di"
+	"github.com/quietsato/toy-small-chat/api/internal/server"
+	"github.com/stretchr/testify/require"
+)
+
+func newServer(corsCfg config.CORS) http.Handler {
+	auth := serviceimpl.NewAuthService([]byte("dummy"), time.Hour)
+	return server.New(
+		&di.Container{Auth: di.AuthDeps{Service: auth, Middleware: auth}},
+		config.Server{RequestTimeout: time.Minute},
+		corsCfg,
+		config.SecurityHeaders{Preset: "production"},
+	)
+}
+
+func preflight(handler http.Handler, origin, method string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodOptions, "/v1/rooms/00000000-0000-0000-0000-000000000000", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", method)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	corsCfg := config.CORS{
+		AllowedOrigins:   []string{"https://chat.example.com", "https://*.preview.example.com"},
+		AllowedMethods:   []string{"GET", "POST", "PATCH", "DELETE"},
+		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		AllowCredentials: true,
+		MaxAge:           time.Hour,
+	}
+
+	t.Run("許可したオリジンとサブドメインから PATCH と DELETE を呼び出せる", func(t *testing.T) {
+		t.Parallel()
+
+		handler := newServer(corsCfg)
+
+		for _, origin := range []string{"https://chat.example.com", "https://pr-1.preview.example.com"} {
+			for _, method := range []string{http.MethodPatch, http.MethodDelete} {
+				rr := preflight(handler, origin, method)
+
+				require.Equal(t, origin, rr.Header().Get("Access-Control-Allow-Origin"), origin+" "+method)
+				require.Contains(t, rr.Header().Get("Access-Control-Allow-Methods"), method)
+				require.Equal(t, "true", rr.Header().Get("Access-Control-Allow-Credentials"))
+				require.Equal(t, "3600", rr.Header().Get("Access-Control-Max-Age"))
+			}
+		}
+	})
+
+	t.Run("許可していないオリジンとメソッドは許可しない", func(t *testing.T) {
+		t.Parallel()
+
+		handler := newServer(corsCfg)
+
+		require.Empty(t, preflight(handler, "https://evil.example.net", http.MethodPatch).Header().Get("Access-Control-Allow-Origin"))
+		require.Empty(t, preflight(handler, "https://chat.example.com", http.MethodPut).Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("すべてのレスポンスにセキュリティヘッダーを付ける", func(t *testing.T) {
+		t.Parallel()
+
+		handler := newServer(corsCfg)
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, "nosniff", rr.Header().Get("X-Content-Type-Options"))
+		require.Equal(t, "default-src 'none'; frame-ancestors 'none'", rr.Header().Get("Content-Security-Policy"))
+		require.Equal(t, "max-age=63072000; includeSubDomains", rr.Header().Get("Strict-Transport-Security"))
+	})
+}
@@ -0,0 +1,126 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/retention/controller"
+	"github.com/quietsato/toy-small-chat/api/internal/di"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// deletedRoomBatchSize は 1 つのトランザクションで削除するルームの数
+//
+// ルームはメッセージなどをすべて含めて削除するため、メッセージよりも小さい単位で削除する
+const deletedRoomBatchSize = 10
+
+// RetentionPurger は保存期間を過ぎたメッセージと送信 Webhook の配信履歴、猶予期間を過ぎた削除済みのルームを定期的に削除する
+type RetentionPurger struct {
+	dic         *di.Container
+	messages    metric.Int64Counter
+	attachments metric.Int64Counter
+	rooms       metric.Int64Counter
+}
+
+func NewRetentionPurger(dic *di.Container) *RetentionPurger {
+	meter := otel.Meter("github.com/quietsato/toy-small-chat/api/internal/worker")
+	// MeterProvider が設定されていなくても no-op の計器を返すため、error は無視してよい
+	messages, _ := meter.Int64Counter("chat.retention.purged_messages",
+		metric.WithDescription("Number of messages deleted because their retention period expired"),
+		metric.WithUnit("{message}"),
+	)
+	attachments, _ := meter.Int64Counter("chat.retention.purged_attachments",
+		metric.WithDescription("Number of attachments deleted with expired messages"),
+		metric.WithUnit("{attachment}"),
+	)
+	rooms, _ := meter.Int64Counter("chat.retention.purged_rooms",
+		metric.WithDescription("Number of deleted rooms purged after the deletion grace period"),
+		metric.WithUnit("{room}"),
+	)
+	return &RetentionPurger{dic, messages, attachments, rooms}
+}
+
+// Run は ctx がキャンセルされるまで削除を繰り返す
+//
+// キャンセルされても削除中のバッチは中断せず、コミットしてから戻る
+func (p *RetentionPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.dic.Retention.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		p.drain(ctx)
+		p.drainDeletedRooms(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain は保存期間を過ぎたメッセージと配信履歴がなくなるか ctx がキャンセルされるまでバッチを削除する
+func (p *RetentionPurger) drain(ctx context.Context) {
+	c := controller.NewPurgeExpiredMessagesController(p.dic.Retention.Repo, p.dic.Retention.Blobs)
+	for ctx.Err() == nil {
+		res, err := c.PurgeExpiredMessages(context.WithoutCancel(ctx), controller.PurgeExpiredMessagesInput{
+			DefaultDays: p.dic.Retention.DefaultDays,
+			BatchSize:   p.dic.Retention.BatchSize,
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to purge expired messages", slog.Any("err", err))
+			return
+		}
+
+		p.messages.Add(ctx, int64(res.Messages))
+		p.attachments.Add(ctx, int64(res.Attachments))
+		for _, r := range res.Rooms {
+			slog.InfoContext(ctx, "purged expired messages",
+				slog.String("roomId", r.RoomID),
+				slog.Int("messages", r.Messages),
+			)
+		}
+		if res.Attachments > 0 {
+			slog.InfoContext(ctx, "purged attachments of expired messages", slog.Int("attachments", res.Attachments))
+		}
+		if res.WebhookDeliveries > 0 {
+			slog.InfoContext(ctx, "purged expired webhook deliveries", slog.Int("deliveries", res.WebhookDeliveries))
+		}
+
+		if res.Messages < p.dic.Retention.BatchSize && res.WebhookDeliveries < p.dic.Retention.BatchSize {
+			return
+		}
+	}
+}
+
+// drainDeletedRooms は猶予期間を過ぎた削除済みのルームがなくなるか ctx がキャンセルされるまでバッチを削除する
+func (p *RetentionPurger) drainDeletedRooms(ctx context.Context) {
+	c := controller.NewPurgeDeletedRoomsController(p.dic.Retention.Repo, p.dic.Retention.Blobs)
+	for ctx.Err() == nil {
+		res, err := c.PurgeDeletedRooms(context.WithoutCancel(ctx), controller.PurgeDeletedRoomsInput{
+			GracePeriod: p.dic.Room.DeletionGracePeriod,
+			BatchSize:   deletedRoomBatchSize,
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to purge deleted rooms", slog.Any("err", err))
+			return
+		}
+
+		p.rooms.Add(ctx, int64(len(res.RoomIDs)))
+		p.messages.Add(ctx, int64(res.Messages))
+		p.attachments.Add(ctx, int64(res.Attachments))
+		if len(res.RoomIDs) > 0 {
+			slog.InfoContext(ctx, "purged deleted rooms",
+				slog.Any("roomIds", res.RoomIDs),
+				slog.Int("messages", res.Messages),
+				slog.Int("attachments", res.Attachments),
+			)
+		}
+
+		if len(res.RoomIDs) < deletedRoomBatchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	messagecontroller "github.com/quietsato/toy-small-chat/api/internal/applications/message/controller"
+	messagerepository "github.com/quietsato/toy-small-chat/api/internal/applications/message/usecase/repository"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/controller"
+	"github.com/quietsato/toy-small-chat/api/internal/applications/scheduledmessage/usecase/service"
+	webhookcontroller "github.com/quietsato/toy-small-chat/api/internal/applications/webhook/controller"
+	"github.com/quietsato/toy-small-chat/api/internal/di"
+	"github.com/quietsato/toy-small-chat/api/internal/domain"
+	"github.com/quietsato/toy-small-chat/api/internal/instrument"
+)
+
+// scheduledMessageLease は取り出したメッセージを他のワーカーに渡さない期間
+//
+// 投稿は 1 件ずつ順に行うため、バッチ全体を投稿し終えるまでの時間より長くする
+const scheduledMessageLease = 2 * time.Minute
+
+// ScheduledMessageSender は送信時刻を過ぎた予約メッセージを定期的に確認し、ルームに投稿する
+type ScheduledMessageSender struct {
+	dic *di.Container
+}
+
+func NewScheduledMessageSender(dic *di.Container) *ScheduledMessageSender {
+	return &ScheduledMessageSender{dic}
+}
+
+// Run は ctx がキャンセルされるまで投稿を繰り返す
+//
+// キャンセルされても投稿中のバッチは中断せず、結果を記録してから戻る
+func (s *ScheduledMessageSender) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.dic.ScheduledMessage.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.drain(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain は取り出せるメッセージがなくなるか ctx がキャンセルされるまでバッチを投稿する
+func (s *ScheduledMessageSender) drain(ctx context.Context) {
+	c := controller.NewSendScheduledMessagesController(s.dic.ScheduledMessage.Repo, &messagePoster{s.dic})
+	for ctx.Err() == nil {
+		// 投稿中のバッチは停止時にも投稿し切るため、キャンセルを伝えない
+		res, err := c.SendScheduledMessages(context.WithoutCancel(ctx), controller.SendScheduledMessagesInput{
+			BatchSize: s.dic.ScheduledMessage.BatchSize,
+			Lease:     scheduledMessageLease,
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to send scheduled messages", slog.Any("err", err))
+			return
+		}
+		if res.Claimed > 0 {
+			slog.InfoContext(ctx, "sent scheduled messages",
+				slog.Int("sent", res.Sent),
+				slog.Int("failed", res.Failed),
+				slog.Int("lost", res.Lost),
+			)
+		}
+		if res.Claimed < s.dic.ScheduledMessage.BatchSize {
+			return
+		}
+	}
+}
+
+// messagePoster は POST /rooms/{roomID}/messages と同じくメッセージを作成して送信 Webhook に通知する
+//
+// 実行者が応答を受け取れないため、スラッシュコマンドは実行しない
+type messagePoster struct {
+	dic *di.Container
+}
+
+// PostMessage implements service.MessagePoster.
+func (p *messagePoster) PostMessage(ctx context.Context, inp service.PostMessageInput) (service.PostMessageOutput, error) {
+	c := messagecontroller.NewCreateMessageController(p.dic.Message.Repo, nil)
+	msg, err := c.CreateMessage(ctx, messagecontroller.CreateMessageInput{
+		RoomID:                   inp.RoomID.String(),
+		AuthorID:                 inp.AuthorID.String(),
+		Content:                  inp.Content,
+		Format:                   inp.Format,
+		ScheduledMessageID:       inp.ScheduledMessageID.String(),
+		ScheduledMessageAttempts: inp.Attempts,
+	})
+	if errors.Is(err, messagerepository.ErrScheduledMessageNotClaimed) {
+		return service.PostMessageOutput{}, fmt.Errorf("%w: %w", service.ErrClaimLost, err)
+	}
+	if errors.Is(err, messagerepository.ErrRoomNotFound) ||
+		errors.Is(err, messagerepository.ErrRoomArchived) ||
+		errors.Is(err, domain.ErrInvalidMessageContent) ||
+		errors.Is(err, domain.ErrInvalidMessageFormat) {
+		return service.PostMessageOutput{}, fmt.Errorf("%w: %w", service.ErrMessageRejected, err)
+	}
+	if err != nil {
+		return service.PostMessageOutput{}, err
+	}
+	instrument.RecordMessageCreated(ctx, instrument.MessageSourceScheduled)
+
+	messageID, err := uuid.Parse(msg.ID)
+	if err != nil {
+		return service.PostMessageOutput{}, fmt.Errorf("failed to parse message id: %w", err)
+	}
+
+	ec := webhookcontroller.NewEnqueueWebhookEventController(p.dic.Webhook.Repo)
+	if err := ec.EnqueueWebhookEvent(ctx, webhookcontroller.EnqueueWebhookEventInput{
+		RoomID:    inp.RoomID.String(),
+		EventType: domain.WebhookEventMessageCreated,
+		Data: webhookcontroller.MessageCreatedData{
+			ID:            msg.ID,
+			AuthorID:      msg.AuthorID,
+			Content:       msg.Content,
+			Format:        msg.Format,
+			AttachmentIDs: []string{},
+		},
+	}); err != nil {
+		// 投稿自体は完了しているため、通知の登録に失敗しても再送しない
+		slog.WarnContext(ctx, "failed to enqueue webhook event", slog.String("type", domain.WebhookEventMessageCreated.String()), slog.Any("err", err))
+	}
+
+	return service.PostMessageOutput{MessageID: messageID}, nil
+}
+
+var _ service.MessagePoster = new(messagePoster)
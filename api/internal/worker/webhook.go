@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/quietsato/toy-small-chat/api/internal/applications/webhook/controller"
+	"github.com/quietsato/toy-small-chat/api/internal/di"
+)
+
+// leaseMargin は送信のタイムアウトに上乗せする、結果を記録するまでの猶予
+const leaseMargin = 30 * time.Second
+
+// WebhookDispatcher は配信キューを定期的に確認し、送信時刻を過ぎた送信 Webhook を配信する
+type WebhookDispatcher struct {
+	dic *di.Container
+}
+
+func NewWebhookDispatcher(dic *di.Container) *WebhookDispatcher {
+	return &WebhookDispatcher{dic}
+}
+
+// Run は ctx がキャンセルされるまで配信を繰り返す
+//
+// キャンセルされても送信中のバッチは中断せず、結果を記録してから戻る。
+// 戻った時点で取り出し済みの配信はすべて結果が記録されている
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.dic.Webhook.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.drain(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain は取り出せる配信がなくなるか ctx がキャンセルされるまでバッチを送信する
+func (d *WebhookDispatcher) drain(ctx context.Context) {
+	c := controller.NewDeliverWebhooksController(d.dic.Webhook.Repo, d.dic.Webhook.Sender, d.dic.Webhook.RetryPolicy)
+	for ctx.Err() == nil {
+		// 送信中のバッチは停止時にも送り切るため、キャンセルを伝えない
+		res, err := c.DeliverWebhooks(context.WithoutCancel(ctx), controller.DeliverWebhooksInput{
+			BatchSize: d.dic.Webhook.BatchSize,
+			Lease:     d.dic.Webhook.Timeout + leaseMargin,
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to deliver webhooks", slog.Any("err", err))
+			return
+		}
+		if res.Claimed > 0 {
+			slog.InfoContext(ctx, "delivered webhooks",
+				slog.Int("succeeded", res.Succeeded),
+				slog.Int("failed", res.Failed),
+			)
+		}
+		if res.Claimed < d.dic.Webhook.BatchSize {
+			return
+		}
+	}
+}
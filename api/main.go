@@ -3,50 +3,146 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
+	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/quietsato/toy-small-chat/api/internal/config"
+	"github.com/quietsato/toy-small-chat/api/internal/db"
+	"github.com/quietsato/toy-small-chat/api/internal/db/migrate"
 	"github.com/quietsato/toy-small-chat/api/internal/di"
+	"github.com/quietsato/toy-small-chat/api/internal/health"
 	"github.com/quietsato/toy-small-chat/api/internal/instrument"
 	instrumentdb "github.com/quietsato/toy-small-chat/api/internal/instrument/db"
 	instrumenthttp "github.com/quietsato/toy-small-chat/api/internal/instrument/http"
 	"github.com/quietsato/toy-small-chat/api/internal/server"
+	"github.com/quietsato/toy-small-chat/api/internal/worker"
 )
 
+const usage = `usage: api [flags] [command]
+
+commands:
+  (none)                start the HTTP server and background workers
+  migrate up            apply all pending migrations
+  migrate down [N]      revert the last N applied migrations (default 1)
+  migrate status        list migrations and whether they are applied
+  migrate baseline N    mark migrations up to version N as applied without running them
+  seed                  insert sample data for local development
+  healthcheck           exit 0 if the local server reports ready on /readyz
+  config print          print the effective config as YAML with secrets redacted
+
+` + config.FlagUsage
+
 func main() {
+	src, args, err := config.ParseArgs(os.Args[1:], nil)
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if len(args) == 0 {
+		os.Exit(serve(src))
+	}
+	switch args[0] {
+	case "migrate":
+		os.Exit(runMigrate(src, args[1:]))
+	case "seed":
+		os.Exit(runSeed(src))
+	case "healthcheck":
+		os.Exit(runHealthcheck(src))
+	case "config":
+		os.Exit(runConfig(src, args[1:]))
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+// loadConfig は設定を読み込む。問題がある場合はすべて標準エラーに書き出す
+func loadConfig(src config.Sources) (config.Config, bool) {
+	cfg, err := config.Load(src)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return cfg, false
+	}
+	return cfg, true
+}
+
+// serve は HTTP サーバーとバックグラウンドの処理を実行し、終了コードを返す
+func serve(src config.Sources) int {
+	cfg, ok := loadConfig(src)
+	if !ok {
+		return 1
+	}
+
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
 	defer stop()
 
-	cfg := config.Load()
+	state := health.NewState(time.Now())
 
 	// Initialize OpenTelemetry tracer
-	shutdownInstr := instrument.Init(ctx, slog.LevelInfo, cfg.OtlpEndpoint)
+	shutdownInstr, metricsHandler := instrument.Init(ctx, cfg.Log.Level, cfg.OtlpEndpoint, cfg.Metrics.PrometheusAddr != "")
 
 	// Initialize database connection pool with tracing
 	pool, err := instrumentdb.NewPool(ctx, cfg.Database.URL())
 	if err != nil {
 		slog.Error("failed to connect to database", slog.Any("err", err))
-		return
+		return 1
 	}
 	defer pool.Close()
 	if err := pool.Ping(ctx); err != nil {
 		slog.Error("failed to ping database", slog.Any("err", err))
-		return
+		return 1
 	}
 	slog.Info("successfully connected to database")
 
+	migrations, err := migrate.Load(db.Migrations())
+	if err != nil {
+		slog.Error("failed to load migrations", slog.Any("err", err))
+		return 1
+	}
+	if cfg.Database.AutoMigrate {
+		if err := migrateUp(ctx, pool, migrations); err != nil {
+			slog.Error("failed to migrate database", slog.Any("err", err))
+			return 1
+		}
+	}
+
+	dic := di.New(pool, cfg, migrations, state)
+
+	// Start outgoing webhook dispatcher, scheduled message sender and retention purger
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	workerDone := make(chan struct{})
+	go func() {
+		defer close(workerDone)
+		var wg sync.WaitGroup
+		wg.Go(func() { worker.NewWebhookDispatcher(dic).Run(workerCtx) })
+		wg.Go(func() { worker.NewScheduledMessageSender(dic).Run(workerCtx) })
+		wg.Go(func() { worker.NewRetentionPurger(dic).Run(workerCtx) })
+		wg.Wait()
+	}()
+
 	// Create router and wrap with HTTP tracing
-	router := server.New(di.New(pool, cfg.JWTSecretKey))
+	router := server.New(dic, cfg.Server, cfg.CORS, cfg.SecurityHeaders)
 	handler := instrumenthttp.NewHandler(router, "toy-small-chat")
 
 	srv := http.Server{
-		Addr:    ":8080",
-		Handler: handler,
+		Addr:              cfg.Server.Addr,
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
 	}
 
 	done := make(chan error, 1)
@@ -54,21 +150,103 @@ func main() {
 		done <- srv.ListenAndServe()
 	}()
 
+	// Prometheus のメトリクスは API と別のポートで公開し、外部に公開する API のポートからは見えないようにする
+	var metricsSrv *http.Server
+	if metricsHandler != nil {
+		mux := http.NewServeMux()
+		mux.Handle("GET /metrics", metricsHandler)
+		metricsSrv = &http.Server{
+			Addr:    cfg.Metrics.PrometheusAddr,
+			Handler: mux,
+		}
+		go func() {
+			if err := metricsSrv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				slog.Error("failed to serve metrics", slog.Any("err", err))
+			}
+		}()
+	}
+
 	select {
 	case err := <-done:
+		stopWorker()
+		<-workerDone
 		if !errors.Is(err, http.ErrServerClosed) {
 			slog.Error("failed to serve", slog.Any("err", err))
+			return 1
 		}
 	case <-ctx.Done():
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		// /readyz を 503 にして、ロードバランサーが振り分け先から外すのを待ってから受け付けを止める
+		state.StartDraining()
+		slog.Info("draining before shutdown", slog.Duration("delay", cfg.Health.DrainDelay))
+		time.Sleep(cfg.Health.DrainDelay)
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 		defer cancel()
 
 		if err := srv.Shutdown(ctx); err != nil {
 			slog.ErrorContext(ctx, "failed to shutdown", slog.Any("err", err))
 		}
+		// 新しい配信や予約メッセージは取り出さず、処理中のものの結果を記録し終えるまで待つ
+		stopWorker()
+		select {
+		case <-workerDone:
+		case <-ctx.Done():
+			slog.ErrorContext(ctx, "failed to drain background workers", slog.Any("err", ctx.Err()))
+		}
+		if metricsSrv != nil {
+			if err := metricsSrv.Shutdown(ctx); err != nil {
+				slog.ErrorContext(ctx, "failed to shutdown metrics server", slog.Any("err", err))
+			}
+		}
 		shutdownInstr(ctx)
 		pool.Close()
 
 		slog.InfoContext(ctx, "server stopped gracefully")
 	}
+	return 0
+}
+
+// runHealthcheck はこのコンテナのサーバーの /readyz を呼び出し、終了コードを返す
+//
+// 実行イメージには curl などがないため、コンテナのヘルスチェックにはこのサブコマンドを使う
+func runHealthcheck(src config.Sources) int {
+	cfg, ok := loadConfig(src)
+	if !ok {
+		return 1
+	}
+	// 待ち受けるアドレスのホストを省略した場合や全インターフェースの場合は、ループバックで呼び出す
+	host, port, err := net.SplitHostPort(cfg.Server.Addr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "127.0.0.1"
+	}
+
+	client := http.Client{Timeout: 5 * time.Second}
+	res, err := client.Get("http://" + net.JoinHostPort(host, port) + "/readyz")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		fmt.Fprintln(os.Stderr, res.Status)
+		return 1
+	}
+	return 0
+}
+
+// runConfig は config サブコマンドを実行し、終了コードを返す
+func runConfig(src config.Sources, args []string) int {
+	if len(args) != 1 || args[0] != "print" {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+	if err := config.Print(os.Stdout, src); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
 }
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/quietsato/toy-small-chat/api/internal/config"
+	"github.com/quietsato/toy-small-chat/api/internal/db"
+	"github.com/quietsato/toy-small-chat/api/internal/db/migrate"
+	instrumentdb "github.com/quietsato/toy-small-chat/api/internal/instrument/db"
+)
+
+// runMigrate は migrate サブコマンドを実行し、終了コードを返す
+func runMigrate(src config.Sources, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+	steps := 1
+	var version int64
+	switch {
+	case args[0] == "down" && len(args) == 2:
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			fmt.Fprintf(os.Stderr, "invalid number of migrations to revert: %q\n", args[1])
+			return 2
+		}
+		steps = n
+	case args[0] == "baseline" && len(args) == 2:
+		v, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || v < 1 {
+			fmt.Fprintf(os.Stderr, "invalid migration version: %q\n", args[1])
+			return 2
+		}
+		version = v
+	case len(args) != 1 || !slices.Contains([]string{"up", "down", "status"}, args[0]):
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+
+	return withPool(src, func(ctx context.Context, pool *pgxpool.Pool) error {
+		migrations, err := migrate.Load(db.Migrations())
+		if err != nil {
+			return err
+		}
+		m := migrate.New(pool, migrations)
+
+		switch args[0] {
+		case "up":
+			return migrateUp(ctx, pool, migrations)
+		case "down":
+			done, err := m.Down(ctx, steps)
+			for _, mig := range done {
+				slog.InfoContext(ctx, "reverted migration", slog.Int64("version", mig.Version), slog.String("name", mig.Name))
+			}
+			return err
+		case "baseline":
+			done, err := m.Baseline(ctx, version)
+			for _, mig := range done {
+				slog.InfoContext(ctx, "marked migration as applied", slog.Int64("version", mig.Version), slog.String("name", mig.Name))
+			}
+			return err
+		default: // status
+			statuses, err := m.Status(ctx)
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+			for _, s := range statuses {
+				applied := "pending"
+				if !s.AppliedAt.IsZero() {
+					applied = s.AppliedAt.Format(time.RFC3339)
+				}
+				if s.Missing {
+					applied += " (missing from this binary)"
+				}
+				fmt.Fprintf(w, "%03d\t%s\t%s\n", s.Version, s.Name, applied)
+			}
+			return w.Flush()
+		}
+	})
+}
+
+// runSeed は seed サブコマンドを実行し、終了コードを返す
+func runSeed(src config.Sources) int {
+	return withPool(src, func(ctx context.Context, pool *pgxpool.Pool) error {
+		if err := migrate.Seed(ctx, pool, db.Seeds()); err != nil {
+			return err
+		}
+		slog.InfoContext(ctx, "seeded database")
+		return nil
+	})
+}
+
+// migrateUp は未適用のマイグレーションを適用する
+func migrateUp(ctx context.Context, pool *pgxpool.Pool, migrations []migrate.Migration) error {
+	done, err := migrate.New(pool, migrations).Up(ctx)
+	for _, mig := range done {
+		slog.InfoContext(ctx, "applied migration", slog.Int64("version", mig.Version), slog.String("name", mig.Name))
+	}
+	if err == nil && len(done) == 0 {
+		slog.InfoContext(ctx, "database schema is up to date")
+	}
+	return err
+}
+
+// withPool は設定のデータベースに接続して fn を実行し、終了コードを返す
+func withPool(src config.Sources, fn func(ctx context.Context, pool *pgxpool.Pool) error) int {
+	cfg, ok := loadConfig(src)
+	if !ok {
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	pool, err := instrumentdb.NewPool(ctx, cfg.Database.URL())
+	if err != nil {
+		slog.Error("failed to connect to database", slog.Any("err", err))
+		return 1
+	}
+	defer pool.Close()
+
+	if err := fn(ctx, pool); err != nil {
+		slog.Error("failed to run command", slog.Any("err", err))
+		return 1
+	}
+	return 0
+}
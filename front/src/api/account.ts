@@ -9,7 +9,7 @@ interface LoginOutput {
 }
 
 export async function login(input: LoginInput): Promise<LoginOutput> {
-  const res = await fetch(`http://localhost:18081/login`, {
+  const res = await fetch(`http://localhost:18081/v1/login`, {
     method: "POST",
     headers: {
       "Content-Type": "application/json",
@@ -38,7 +38,7 @@ interface CreateAccountOutput {
 export async function createAccount(
   input: CreateAccountInput
 ): Promise<CreateAccountOutput> {
-  const res = await fetch(`http://localhost:18081/accounts`, {
+  const res = await fetch(`http://localhost:18081/v1/accounts`, {
     method: "POST",
     headers: {
       "Content-Type": "application/json",
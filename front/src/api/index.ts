@@ -1,6 +1,6 @@
 import { Mutex } from "async-mutex";
 
-const API_BASE_URL = "http://localhost:18081";
+const API_BASE_URL = "http://localhost:18081/v1";
 
 interface FetchOptions extends RequestInit {
   headers?: Record<string, string>;